@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/importer"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// importReport is the --json output of `bv import`.
+type importReport struct {
+	Source   string                   `json:"source"`
+	Input    string                   `json:"input"`
+	Output   string                   `json:"output"`
+	DryRun   bool                     `json:"dry_run"`
+	Issues   int                      `json:"issues"`
+	Stats    importer.WriteStats      `json:"stats"`
+	Unmapped []importer.UnmappedField `json:"unmapped"`
+	Warnings []string                 `json:"warnings,omitempty"`
+}
+
+// runImport is the entry point for `bv import`.
+func runImport(args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	from := fs.String("from", "", "Export format: github, jira or linear")
+	output := fs.String("output", "", "JSONL file to write (default: the project's beads JSONL, or .beads/issues.jsonl)")
+	prefix := fs.String("prefix", "", "ID prefix for new issues (default: the Jira/Linear key, gh-N for GitHub)")
+	dryRun := fs.Bool("dry-run", false, "Report what would change without writing")
+	asJSON := fs.Bool("json", false, "Print the report as JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: bv import --from github|jira|linear [options] <file|->")
+		fmt.Fprintln(os.Stderr, "\nConvert an issue-tracker export into beads issues.")
+		fmt.Fprintf(os.Stderr, "  github: gh issue list --state all --limit 10000 --json %s\n", importer.GitHubFields)
+		fmt.Fprintln(os.Stderr, "  jira:   Filters → Export → Export Excel CSV (all fields)")
+		fmt.Fprintln(os.Stderr, "  linear: Settings → Import/Export → Export CSV")
+		fmt.Fprintln(os.Stderr, "\nRe-importing updates issues in place; IDs are matched by external_ref.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	src, err := importer.ParseSource(*from)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	input := fs.Arg(0)
+	var in io.Reader = os.Stdin
+	if input != "-" {
+		f, err := os.Open(input)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		in = f
+	}
+
+	outPath := *output
+	if outPath == "" {
+		if outPath, err = defaultImportPath(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	var existing []model.Issue
+	if _, statErr := os.Stat(outPath); statErr == nil {
+		if existing, err = loader.LoadIssuesFromFile(outPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", outPath, err)
+			return 1
+		}
+	}
+
+	res, err := importer.Import(src, in, importer.Options{Prefix: *prefix, Existing: existing})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	report := importReport{
+		Source:   string(src),
+		Input:    input,
+		Output:   outPath,
+		DryRun:   *dryRun,
+		Issues:   len(res.Issues),
+		Unmapped: res.Unmapped,
+		Warnings: res.Warnings,
+	}
+	if *dryRun {
+		current, readErr := os.ReadFile(outPath)
+		if readErr != nil && !errors.Is(readErr, os.ErrNotExist) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", readErr)
+			return 1
+		}
+		_, report.Stats, err = importer.MergeJSONL(current, res.Issues)
+	} else {
+		report.Stats, err = importer.WriteJSONL(outPath, res.Issues)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", outPath, err)
+		return 1
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+	printImportReport(os.Stdout, report)
+	return 0
+}
+
+// defaultImportPath is the project's existing beads JSONL, or
+// .beads/issues.jsonl when there is none yet.
+func defaultImportPath() (string, error) {
+	beadsDir, err := loader.GetBeadsDir("")
+	if err != nil {
+		return "", err
+	}
+	if path, err := loader.FindJSONLPath(beadsDir); err == nil {
+		return path, nil
+	}
+	return filepath.Join(beadsDir, "issues.jsonl"), nil
+}
+
+func printImportReport(w io.Writer, r importReport) {
+	verb := "Imported"
+	if r.DryRun {
+		verb = "Would import"
+	}
+	fmt.Fprintf(w, "%s %d %s issues into %s: %d created, %d updated, %d unchanged\n",
+		verb, r.Issues, r.Source, r.Output, r.Stats.Created, r.Stats.Updated, r.Stats.Unchanged)
+
+	if len(r.Unmapped) > 0 {
+		fmt.Fprintln(w, "\nNot imported (no beads equivalent or unrecognized value):")
+		width := 0
+		for _, f := range r.Unmapped {
+			width = max(width, len(f.Field))
+		}
+		for _, f := range r.Unmapped {
+			line := fmt.Sprintf("  %-*s  %4d", width, f.Field, f.Count)
+			if f.Example != "" {
+				line += fmt.Sprintf("  e.g. %q", f.Example)
+			}
+			fmt.Fprintln(w, line)
+		}
+	}
+	if len(r.Warnings) > 0 {
+		fmt.Fprintln(w, "\nWarnings:")
+		fmt.Fprintln(w, "  "+strings.Join(r.Warnings, "\n  "))
+	}
+}
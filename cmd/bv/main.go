@@ -31,13 +31,17 @@ import (
 	"github.com/Dicklesworthstone/beads_viewer/pkg/agents"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/baseline"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/cass"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/correlation"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/drift"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/export"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/flow"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/hooks"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/importer"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/metrics"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/query"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/recipe"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/search"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/triage"
@@ -46,11 +50,28 @@ import (
 	"github.com/Dicklesworthstone/beads_viewer/pkg/version"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/watcher"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/workspace"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/writeback"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// subcommands are dispatched on the first argument before flag parsing.
+// Each receives the remaining arguments and returns the process exit code.
+var subcommands = map[string]func(args []string) int{
+	"serve":            runServe,
+	"mcp":              runMCP,
+	"metrics-exporter": runMetricsExporter,
+	"import":           runImport,
+	"merge-driver":     runMergeDriver,
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		if run, ok := subcommands[os.Args[1]]; ok {
+			os.Exit(run(os.Args[2:]))
+		}
+	}
+
 	cpuProfile := flag.String("cpu-profile", "", "Write CPU profile to file")
 	help := flag.Bool("help", false, "Show help")
 	versionFlag := flag.Bool("version", false, "Show version")
@@ -60,6 +81,9 @@ func main() {
 	rollbackFlag := flag.Bool("rollback", false, "Rollback to the previous version (from backup)")
 	yesFlag := flag.Bool("yes", false, "Skip confirmation prompts (use with --update)")
 	exportFile := flag.String("export-md", "", "Export issues to a Markdown file (e.g., report.md)")
+	exportGantt := flag.String("export-gantt", "", "Export the execution plan as a Mermaid gantt chart (.md, or .mmd for the bare chart)")
+	ganttGroup := flag.String("gantt-group", "track", "Gantt sections for --export-gantt: track or epic")
+	reportFile := flag.String("export", "", "Export a report using the recipe's export settings (format from export.format or the file extension; - for stdout)")
 	robotHelp := flag.Bool("robot-help", false, "Show AI agent help")
 	robotDocs := flag.String("robot-docs", "", "Machine-readable JSON docs for AI agents. Topics: guide, commands, examples, env, exit-codes, all")
 	outputFormat := flag.String("format", "", "Structured output format for --robot-* commands: json or toon (env: BV_OUTPUT_FORMAT, TOON_DEFAULT_FORMAT)")
@@ -79,19 +103,23 @@ func main() {
 	attentionLimit := flag.Int("attention-limit", 5, "Limit number of labels in --robot-label-attention output")
 	robotAlerts := flag.Bool("robot-alerts", false, "Output alerts (drift + proactive) as JSON for AI agents")
 	robotMetrics := flag.Bool("robot-metrics", false, "Output performance metrics (timing, cache, memory) as JSON")
+	robotWatch := flag.Bool("robot-watch", false, "Keep running and emit one NDJSON event per data change (diff, top pick, newly actionable, new alerts)")
+	sinceHash := flag.String("since-hash", "", "With --robot-watch: resume from a data_hash seen earlier instead of starting with a full snapshot")
+	watchHeartbeat := flag.Duration("watch-heartbeat", defaultWatchHeartbeat, "With --robot-watch: interval between heartbeat lines (0 disables)")
 	// JSON Schema for robot outputs (bd-2kxo)
 	robotSchema := flag.Bool("robot-schema", false, "Output JSON Schema definitions for all robot commands")
 	schemaCommand := flag.String("schema-command", "", "Output schema for specific command only (e.g., robot-triage)")
 	// Smart suggestions (bv-180)
-	robotSuggest := flag.Bool("robot-suggest", false, "Output smart suggestions (duplicates, dependencies, labels, cycles) as JSON")
-	suggestType := flag.String("suggest-type", "", "Filter suggestions by type: duplicate, dependency, label, cycle")
+	robotSuggest := flag.Bool("robot-suggest", false, "Output smart suggestions (duplicates, dependencies, labels, cycles, priority inversions, redundant deps) as JSON")
+	suggestType := flag.String("suggest-type", "", "Filter suggestions by type: duplicate, dependency, label, cycle, inversion, redundant")
 	suggestConfidence := flag.Float64("suggest-confidence", 0.0, "Minimum confidence for suggestions (0.0-1.0)")
 	suggestBead := flag.String("suggest-bead", "", "Filter suggestions for specific bead ID")
 	// Graph export (bv-136)
 	robotGraph := flag.Bool("robot-graph", false, "Output dependency graph as JSON/DOT/Mermaid for AI agents")
-	graphFormat := flag.String("graph-format", "json", "Graph output format: json, dot, mermaid")
+	graphFormat := flag.String("graph-format", "json", "Graph output format: json, dot, mermaid, gantt")
 	graphRoot := flag.String("graph-root", "", "Subgraph from specific root issue ID")
 	graphDepth := flag.Int("graph-depth", 0, "Max depth for subgraph (0 = unlimited)")
+	graphReduced := flag.Bool("graph-reduced", false, "Drop blocking edges implied by longer chains (transitive reduction) in --robot-graph/--export-graph")
 	// Graph snapshot export (bv-94)
 	exportGraph := flag.String("export-graph", "", "Export graph: .html for interactive, .png/.svg for static (auto-names if empty)")
 	graphPreset := flag.String("graph-preset", "compact", "Graph layout preset: compact (default) or roomy")
@@ -110,9 +138,11 @@ func main() {
 	semanticQuery := flag.String("search", "", "Semantic search query (vector-based; builds/updates index on first run)")
 	robotSearch := flag.Bool("robot-search", false, "Output semantic search results as JSON for AI agents (use with --search)")
 	searchLimit := flag.Int("search-limit", 10, "Max results for --search/--robot-search")
-	searchMode := flag.String("search-mode", "", "Search ranking mode: text or hybrid (default: BV_SEARCH_MODE or text)")
+	searchMode := flag.String("search-mode", "", "Search ranking mode: text, hybrid or fusion (default: BV_SEARCH_MODE or text)")
 	searchPreset := flag.String("search-preset", "", "Hybrid preset name (default: BV_SEARCH_PRESET or default)")
 	searchWeights := flag.String("search-weights", "", "Hybrid weights JSON (overrides preset; keys: text,pagerank,status,impact,priority,recency)")
+	searchKinds := flag.String("search-kinds", "", "Documents to search: issue,comment,commit,session or all (default: BV_SEARCH_KINDS or issue)")
+	searchGroup := flag.Bool("search-group", false, "Group --search matches by bead (one result per bead with all its matches)")
 	diffSince := flag.String("diff-since", "", "Show changes since historical point (commit SHA, branch, tag, or date)")
 	asOf := flag.String("as-of", "", "View state at point in time (commit SHA, branch, tag, or date)")
 	forceFullAnalysis := flag.Bool("force-full-analysis", false, "Compute all metrics regardless of graph size (may be slow for large graphs)")
@@ -174,10 +204,31 @@ func main() {
 	forecastLabel := flag.String("forecast-label", "", "Filter forecast by label")
 	forecastSprint := flag.String("forecast-sprint", "", "Filter forecast by sprint ID")
 	forecastAgents := flag.Int("forecast-agents", 1, "Number of parallel agents for capacity calculation")
+	forecastPercentiles := flag.String("percentiles", "", "Monte Carlo forecast percentiles for --robot-forecast (e.g. 50,85,95)")
+	forecastRuns := flag.Int("forecast-runs", analysis.DefaultMonteCarloRuns, "Simulations for --robot-forecast --percentiles")
 	// Capacity simulation flags (bv-160)
 	robotCapacity := flag.Bool("robot-capacity", false, "Output capacity simulation and completion projection as JSON")
 	capacityAgents := flag.Int("agents", 1, "Number of parallel agents for capacity simulation")
 	capacityLabel := flag.String("capacity-label", "", "Filter capacity simulation by label")
+	// Resource-constrained schedule flags
+	robotSchedule := flag.Bool("robot-schedule", false, "Output per-person/agent schedule of open work as JSON")
+	rosterPath := flag.String("roster", "", "Roster YAML for --robot-schedule and the schedule view (default: .bv/roster.yaml)")
+	// Epic rollup flags
+	robotEpics := flag.Bool("robot-epics", false, "Output epic/parent progress rollups as JSON")
+	// Flow metrics flags
+	robotFlow := flag.Bool("robot-flow", false, "Output lead/cycle time, throughput, WIP aging and cumulative flow as JSON")
+	flowDays := flag.Int("flow-days", flow.DefaultDays, "Window in days for --robot-flow")
+	// What-if scenario flags
+	robotWhatIf := flag.String("robot-whatif", "", "Output before/after plan metrics for hypothetical edits in a scenario JSON file")
+	// Community detection flags
+	robotCommunities := flag.Bool("robot-communities", false, "Output dependency-graph communities with proposed epics and labels as JSON")
+	communityMinSize := flag.Int("community-min-size", 3, "Smallest community reported by --robot-communities")
+	communityResolution := flag.Float64("community-resolution", 1.0, "Modularity resolution for --robot-communities (>1 = smaller communities)")
+	communityClosed := flag.Bool("community-closed", false, "Include closed issues in --robot-communities")
+	// Dominator tree flags
+	robotDominators := flag.String("robot-dominators", "", "Output the mandatory chokepoints on the way to an issue (or 'all' goals) as JSON")
+	// Query language flags
+	robotQuery := flag.String("robot-query", "", "Output the issues matching a structured query (e.g. 'status:open priority<=1 label:api') as JSON")
 	// Burndown flags (bv-159)
 	robotBurndown := flag.String("robot-burndown", "", "Output burndown data for sprint ID, or 'current' for active sprint")
 	// Action script emission flags (bv-89)
@@ -219,6 +270,11 @@ func main() {
 	// Override pflag's default usage so -h/--help prints our custom header.
 	flag.Usage = func() {
 		fmt.Println("Usage: bv [options]")
+		fmt.Println("       bv serve [options]")
+		fmt.Println("       bv mcp [options]")
+		fmt.Println("       bv metrics-exporter [options]")
+		fmt.Println("       bv import --from github|jira|linear <file>")
+		fmt.Println("       bv merge-driver %O %A %B [%P]")
 		fmt.Println("\nA TUI viewer for beads issue tracker.")
 		flag.PrintDefaults()
 	}
@@ -255,6 +311,8 @@ func main() {
 	_ = forecastLabel
 	_ = forecastSprint
 	_ = forecastAgents
+	_ = forecastPercentiles
+	_ = forecastRuns
 	_ = robotCapacity
 	_ = capacityAgents
 	_ = capacityLabel
@@ -279,6 +337,7 @@ func main() {
 		*robotLabelFlow ||
 		*robotLabelAttention ||
 		*robotAlerts ||
+		*robotWatch ||
 		*robotMetrics ||
 		*robotSchema ||
 		*robotSuggest ||
@@ -301,6 +360,13 @@ func main() {
 		*robotByLabel != "" ||
 		*robotByAssignee != "" ||
 		*robotCapacity ||
+		*robotSchedule ||
+		*robotEpics ||
+		*robotFlow ||
+		*robotWhatIf != "" ||
+		*robotCommunities ||
+		*robotDominators != "" ||
+		*robotQuery != "" ||
 		*robotDocs != "" ||
 		*triageRig != "" ||
 		// When stdout is non-TTY, --diff-since auto-enables JSON output. Mark this
@@ -392,9 +458,16 @@ func main() {
 		fmt.Println("      Builds/updates a local on-disk vector index on first run.")
 		fmt.Println("      Use --robot-search to emit JSON for automation.")
 		fmt.Println("      Optional hybrid re-ranking:")
-		fmt.Println("      - --search-mode=text|hybrid (default: BV_SEARCH_MODE or text)")
+		fmt.Println("      - --search-mode=text|hybrid|fusion (default: BV_SEARCH_MODE or text)")
+		fmt.Println("        fusion = hybrid whose text score fuses BM25 and vector ranks (RRF)")
 		fmt.Println("      - --search-preset=default|bug-hunting|sprint-planning|impact-first|text-only")
 		fmt.Println("      - --search-weights='{\"text\":0.4,\"pagerank\":0.2,\"status\":0.15,\"impact\":0.1,\"priority\":0.1,\"recency\":0.05}'")
+		fmt.Println("      Unified search: --search-kinds=issue,comment,commit,session|all also")
+		fmt.Println("      searches comments, correlated commit messages and cass sessions;")
+		fmt.Println("      every match links to its bead. --search-group nests matches per bead.")
+		fmt.Println("      Embedder: BV_SEMANTIC_EMBEDDER=hash (default) | ollama | openai, with")
+		fmt.Println("      BV_SEMANTIC_URL, BV_SEMANTIC_MODEL and BV_SEMANTIC_API_KEY for the server.")
+		fmt.Println("      Indexes of 5000+ issues use an HNSW graph; tune with BV_SEMANTIC_HNSW_*.")
 		fmt.Println("")
 		fmt.Println("  --emit-script [--script-limit=N]")
 		fmt.Println("      Emits a shell script for top-N recommendations (default: 5).")
@@ -404,6 +477,50 @@ func main() {
 		fmt.Println("      Example: bv --emit-script > work.sh && bash work.sh")
 		fmt.Println("      Example: bv --emit-script --script-limit=3")
 		fmt.Println("")
+		fmt.Println("  bv serve [--addr=127.0.0.1:7777] [--socket=PATH] [--format=json|toon]")
+		fmt.Println("      Long-running daemon: keeps issues, graph metrics and git history warm")
+		fmt.Println("      and answers robot queries over HTTP. Reloads when .beads changes.")
+		fmt.Println("      Endpoints: /triage, /next, /insights, /plan, /graph, /search?q=,")
+		fmt.Println("                 /history/{id}, /blocker-chain/{id}, /label-health, /health,")
+		fmt.Println("                 /metrics (OpenMetrics for Prometheus)")
+		fmt.Println("      Payloads match the --robot-* flags; add ?format=toon for TOON.")
+		fmt.Println("      Options: --no-watch, --no-history, --history-limit=N, --quiet")
+		fmt.Println("      Example: bv serve --socket /tmp/bv.sock &")
+		fmt.Println("               curl --unix-socket /tmp/bv.sock http://bv/next")
+		fmt.Println("")
+		fmt.Println("  bv mcp [--no-watch] [--no-history] [--history-limit=N] [--verbose]")
+		fmt.Println("      Model Context Protocol server on stdio for agent hosts.")
+		fmt.Println("      Tools: triage, next, plan, blocker-chain, impact, related, forecast,")
+		fmt.Println("             search, suggest (input schemas: bv --robot-schema | jq .inputs)")
+		fmt.Println("      Resources: bead://<id> (issue record plus blockers and graph scores)")
+		fmt.Println("      Client config: {\"mcpServers\": {\"bv\": {\"command\": \"bv\", \"args\": [\"mcp\"]}}}")
+		fmt.Println("")
+		fmt.Println("  bv metrics-exporter [--addr=127.0.0.1:9477] [--socket=PATH] [--history]")
+		fmt.Println("      Serves only /metrics and /health: the same OpenMetrics exposition as")
+		fmt.Println("      bv serve, without the robot endpoints. Reloads when .beads changes.")
+		fmt.Println("      Gauges: bv_issues_{open,blocked,actionable,closed}, bv_graph_cycles,")
+		fmt.Println("              bv_graph_density, bv_velocity_*, bv_label_health{label},")
+		fmt.Println("              bv_drift_alerts{severity}")
+		fmt.Println("      Internals: bv_operation_duration_seconds histogram, bv_cache_{hits,misses}_total")
+		fmt.Println("      Example: scrape_configs: [{job_name: bv, static_configs: [{targets: ['127.0.0.1:9477']}]}]")
+		fmt.Println("")
+		fmt.Println("  bv import --from github|jira|linear [--prefix=P] [--dry-run] [--json] <file|->")
+		fmt.Println("      Converts a tracker export into the project's beads JSONL (or --output).")
+		fmt.Println("      github: gh issue list --state all --json " + importer.GitHubFields)
+		fmt.Println("      jira: \"Export Excel CSV (all fields)\"; linear: workspace CSV export")
+		fmt.Println("      Maps status, priority, type, labels, assignee, comments, due dates and")
+		fmt.Println("      blocked-by / parent / related links; lists every field it had to drop.")
+		fmt.Println("      Re-runs are idempotent: issues are matched by external_ref (e.g. jira:PROJ-12).")
+		fmt.Println("")
+		fmt.Println("  bv merge-driver [--conflicts=PATH] %O %A %B [%P]")
+		fmt.Println("      Git merge driver for beads JSONL: merges per issue and per field instead of")
+		fmt.Println("      per line, so branches touching different issues never conflict.")
+		fmt.Println("      Labels, dependencies and comments merge as sets; fields changed on both sides")
+		fmt.Println("      go to the later updated_at and are logged to <file>.merge-conflicts.jsonl.")
+		fmt.Println("      Setup:")
+		fmt.Println("        git config merge.beads.driver 'bv merge-driver %O %A %B %P'")
+		fmt.Println("        echo '.beads/*.jsonl merge=beads' >> .gitattributes")
+		fmt.Println("")
 		fmt.Println("  --robot-history")
 		fmt.Println("      Outputs bead-to-commit correlations as JSON.")
 		fmt.Println("      Tracks which code changes relate to which beads via git history analysis.")
@@ -520,10 +637,19 @@ func main() {
 		fmt.Println("      Options:")
 		fmt.Println("        --forecast-label=X    Filter by label")
 		fmt.Println("        --forecast-sprint=Y   Filter by sprint")
-		fmt.Println("        --forecast-agents=N   Parallel agents (default: 1)")
+		fmt.Println("        --forecast-agents=N   Parallel agents (default: 1; --agents also works)")
+		fmt.Println("        --percentiles=50,85,95  Monte Carlo mode: date ranges instead of one ETA")
+		fmt.Println("        --forecast-runs=N     Simulations in Monte Carlo mode (default: 5000)")
+		fmt.Println("      Monte Carlo mode samples cycle times of closed issues with the same labels")
+		fmt.Println("      and type (claim-to-close from git history when available, else created-to-")
+		fmt.Println("      closed lead time; see sample_basis), schedules the open backlog on N agents")
+		fmt.Println("      respecting blockers, and reports percentile dates per issue, epic, label and")
+		fmt.Println("      sprint plus a histogram of completion dates. In-progress work is credited")
+		fmt.Println("      with the time already spent on it (elapsed_days).")
 		fmt.Println("      Example: bv --robot-forecast bv-123")
 		fmt.Println("      Example: bv --robot-forecast all --forecast-label=backend")
 		fmt.Println("      Example: bv --robot-forecast all --forecast-agents=2")
+		fmt.Println("      Example: bv --robot-forecast all --percentiles 50,85,95 --agents 3")
 		fmt.Println("")
 		fmt.Println("  --robot-capacity [--agents=N] [--capacity-label=X]")
 		fmt.Println("      Outputs capacity simulation and completion projection as JSON.")
@@ -540,6 +666,91 @@ func main() {
 		fmt.Println("      Example: bv --robot-capacity --agents=3")
 		fmt.Println("      Example: bv --robot-capacity --capacity-label=backend")
 		fmt.Println("")
+		fmt.Println("  --robot-schedule [--roster=PATH] [--agents=N]")
+		fmt.Println("      Assigns open work to named people and agents and outputs per-worker")
+		fmt.Println("      timelines plus the projected finish as JSON. Blockers finish first,")
+		fmt.Println("      assigned issues stay with their assignee, durations come from")
+		fmt.Println("      estimated_minutes (median otherwise) and each worker's hours per day.")
+		fmt.Println("      The roster (.bv/roster.yaml) lists workers with hours_per_day and")
+		fmt.Println("      optional label skills; without one, assignees plus N agents are used.")
+		fmt.Println("      Key fields:")
+		fmt.Println("        - workers[]: tasks, busy_hours, utilization, finish per worker")
+		fmt.Println("        - tasks[]: issue_id, worker, start/end (and start_day/end_day)")
+		fmt.Println("        - unscheduled[]: work no worker has the skills for")
+		fmt.Println("      Example: bv --robot-schedule")
+		fmt.Println("      Example: bv --robot-schedule --agents=2")
+		fmt.Println("      Example: bv --robot-schedule --roster team.yaml")
+		fmt.Println("")
+		fmt.Println("  --robot-epics")
+		fmt.Println("      Rolls progress up the parent-child hierarchy and outputs, for every")
+		fmt.Println("      epic or parent at any depth, percent complete, remaining estimated")
+		fmt.Println("      minutes, and blocked/actionable counts of the work beneath it.")
+		fmt.Println("      Key fields:")
+		fmt.Println("        - epics[]: id, depth, descendants, closed, percent_complete,")
+		fmt.Println("          remaining_minutes, blocked, actionable, flags")
+		fmt.Println("        - zombies[]: open parents whose children are all closed")
+		fmt.Println("        - orphan_progress[]: deferred parents with closed work beneath")
+		fmt.Println("      Example: bv --robot-epics | jq '.epics[] | select(.depth == 0)'")
+		fmt.Println("")
+		fmt.Println("  --robot-flow [--flow-days=N] [--history-limit=N]")
+		fmt.Println("      Outputs Kanban flow metrics for the last N days (default: 90) as JSON.")
+		fmt.Println("      Status changes come from git history of the beads file; issues")
+		fmt.Println("      without history fall back to their created/updated/closed times.")
+		fmt.Println("      Key fields:")
+		fmt.Println("        - lead_time / cycle_time: p50/p85/p95 days for issues closed")
+		fmt.Println("        - throughput.weeks[]: issues closed per week")
+		fmt.Println("        - wip_aging[]: in-progress issues by age, flagged over_p85")
+		fmt.Println("        - cfd: daily dates plus one count series per status")
+		fmt.Println("      Example: bv --robot-flow | jq '.cycle_time'")
+		fmt.Println("      Example: bv --robot-flow --flow-days=30 | jq '.wip_aging[] | select(.over_p85)'")
+		fmt.Println("")
+		fmt.Println("  --robot-whatif <scenarios.json> [--roster=PATH] [--agents=N]")
+		fmt.Println("      Applies hypothetical edits to a copy of the issues and compares each")
+		fmt.Println("      scenario with the current state. Operations: close, reopen, add_dep,")
+		fmt.Println("      remove_dep, set_priority, add_agent. A scenario may \"extend\" another")
+		fmt.Println("      to build on its edits. The forecast uses the --robot-schedule roster.")
+		fmt.Println("      Key fields:")
+		fmt.Println("        - baseline: open, actionable, blocked, critical_path, top_picks,")
+		fmt.Println("          cycles, forecast_days, forecast_date")
+		fmt.Println("        - scenarios[]: name, ops, after (same fields), delta, warnings")
+		fmt.Println("      Example: bv --robot-whatif plans.json | jq '.scenarios[] | {name, delta}'")
+		fmt.Println("")
+		fmt.Println("  --robot-communities [--community-min-size=N] [--community-resolution=R] [--community-closed]")
+		fmt.Println("      Clusters open issues by their blocks/related links (Louvain modularity)")
+		fmt.Println("      and proposes an epic and label for each cluster. Parent-child links are")
+		fmt.Println("      ignored, so clusters that cut across existing epics show up.")
+		fmt.Println("      Key fields:")
+		fmt.Println("        - modularity: partition quality (above ~0.3 means clear structure)")
+		fmt.Println("        - communities[]: members, cohesion, keywords, suggested_title,")
+		fmt.Println("          suggested_label, missing_label, parent, without_parent, commands")
+		fmt.Println("      Example: bv --robot-communities | jq '.communities[] | {suggested_title, size, cohesion}'")
+		fmt.Println("")
+		fmt.Println("  --robot-dominators <id|all>")
+		fmt.Println("      Dominator tree of open work: the issues every route to a goal must pass")
+		fmt.Println("      through, however the rest is scheduled. Work flows from blockers to the")
+		fmt.Println("      issues they block and from children to their epic, starting from a")
+		fmt.Println("      virtual root of all actionable work. Goals are open epics and open")
+		fmt.Println("      issues nothing open waits on; 'all' reports every goal.")
+		fmt.Println("      Key fields:")
+		fmt.Println("        - dominators[]: goal, reachable, chokepoints (nearest first), upstream")
+		fmt.Println("        - must_pass[]: issues ranked by goals_dominated")
+		fmt.Println("        - unreachable: open issues only reachable through a cycle")
+		fmt.Println("      Example: bv --robot-dominators bv-42 | jq '.dominators[0].chokepoints[].id'")
+		fmt.Println("")
+		fmt.Println("  --robot-query \"<query>\"")
+		fmt.Println("      Issues matching a structured query, the same language as the TUI '/'")
+		fmt.Println("      prompt and the recipe 'query:' field. Terms are field:value (commas")
+		fmt.Println("      mean any of), field<op>value with < <= > >= = !=, bare words or")
+		fmt.Println("      \"quoted phrases\" matching title/description/ID, '-' or NOT to negate,")
+		fmt.Println("      OR and parentheses. blockers:(...), blocks:(...), parent:(...) and")
+		fmt.Println("      children:(...) match related issues. Ages: updated<14d, created>3m.")
+		fmt.Println("      Fields: id status is priority type label assignee repo title desc text")
+		fmt.Println("        parent children blocks blockers comments estimate created updated")
+		fmt.Println("        closed due pagerank betweenness eigenvector hub authority critical")
+		fmt.Println("        slack core indegree outdegree")
+		fmt.Println("      Key fields: parsed (canonical form), fields, count, issues[]")
+		fmt.Println("      Example: bv --robot-query 'is:actionable priority<=1 -label:wontfix' | jq '.issues[].id'")
+		fmt.Println("")
 		fmt.Println("  --emit-script [--script-limit=N] [--script-format=bash|fish|zsh]")
 		fmt.Println("      Emits a shell script for top-N priority recommendations.")
 		fmt.Println("      Useful for agent workflows and automation.")
@@ -559,6 +770,24 @@ func main() {
 		fmt.Println("      Generates a readable status report with Mermaid.js visualizations.")
 		fmt.Println("      Runs pre-export and post-export hooks if configured in .bv/hooks.yaml")
 		fmt.Println("")
+		fmt.Println("  --export-gantt <file> [--gantt-group=track|epic]")
+		fmt.Println("      Writes open work as a Mermaid gantt chart: one section per execution")
+		fmt.Println("      track (or epic), bars sized from estimated_minutes or the ETA model,")
+		fmt.Println("      `after` clauses from blockers, due-date milestones and the critical path")
+		fmt.Println("      marked crit. .md files wrap the chart for READMEs; .mmd gets it bare.")
+		fmt.Println("      Example: bv --export-gantt plan.md")
+		fmt.Println("      Example: bv --export-gantt plan.mmd --gantt-group=epic --label=api")
+		fmt.Println("")
+		fmt.Println("  --export <file|->  (usually with --recipe)")
+		fmt.Println("      Writes the recipe's filtered, sorted issues as a report.")
+		fmt.Println("      Honors the recipe's export block:")
+		fmt.Println("        format: markdown | json | csv | mermaid (default: from the file extension)")
+		fmt.Println("        include_graph: true   embed a Mermaid graph of the filtered issues")
+		fmt.Println("        template: PATH        render a Go text/template instead (gets .Issues,")
+		fmt.Println("                              .Stats, .Triage, .Columns; funcs column, mermaid, date)")
+		fmt.Println("      CSV, markdown and json use the recipe's view.columns.")
+		fmt.Println("      Example: bv --recipe weekly --export weekly.md")
+		fmt.Println("")
 		fmt.Println("  --no-hooks")
 		fmt.Println("      Skip running hooks during export. Useful for CI or quick exports.")
 		fmt.Println("")
@@ -631,16 +860,28 @@ func main() {
 		fmt.Println("      Filters: --severity=<info|warning|critical>, --alert-type=<type>, --alert-label=<label>")
 		fmt.Println("      Fields: type, severity, message, issue_id, label, detected_at, details[].")
 		fmt.Println("")
-		fmt.Println("  --robot-graph [--graph-format=json|dot|mermaid] [--graph-root=ID] [--graph-depth=N]")
+		fmt.Println("  --robot-watch [--since-hash=HASH] [--watch-heartbeat=30s]")
+		fmt.Println("      Keeps running and writes one NDJSON line per data change.")
+		fmt.Println("      Event types: snapshot (first line), delta, heartbeat, error.")
+		fmt.Println("      Delta fields: prev_hash, added[], removed[], modified[], top_pick (when it")
+		fmt.Println("      changed), newly_actionable[], new_alerts[]. Every line carries data_hash and seq.")
+		fmt.Println("      Reconnect with --since-hash=<last data_hash> to get one delta covering")
+		fmt.Println("      everything missed (the last 32 states are journaled in .bv/watch/).")
+		fmt.Println("      Example: bv --robot-watch | jq -c 'select(.type==\"delta\") | .newly_actionable'")
+		fmt.Println("")
+		fmt.Println("  --robot-graph [--graph-format=json|dot|mermaid|gantt] [--graph-root=ID] [--graph-depth=N] [--graph-reduced]")
 		fmt.Println("      Outputs dependency graph in specified format (default: JSON adjacency).")
 		fmt.Println("      Formats:")
 		fmt.Println("        - json: Adjacency list with nodes[], edges[], metadata")
 		fmt.Println("        - dot: Graphviz DOT format (render with: dot -Tpng file.dot -o graph.png)")
 		fmt.Println("        - mermaid: Mermaid diagram format (paste into GitHub/markdown)")
+		fmt.Println("        - gantt: Mermaid gantt chart of open work (see --export-gantt)")
 		fmt.Println("      Options:")
 		fmt.Println("        --label LABEL: Filter to issues with specific label")
 		fmt.Println("        --graph-root ID: Extract subgraph starting from root issue")
 		fmt.Println("        --graph-depth N: Limit subgraph depth (0 = unlimited)")
+		fmt.Println("        --graph-reduced: Drop blocking edges implied by longer chains (transitive")
+		fmt.Println("          reduction); also applies to --export-graph")
 		fmt.Println("      Fields: format, graph (string for dot/mermaid), nodes, edges, filters_applied, explanation")
 		fmt.Println("      Example: bv --robot-graph --graph-format=dot --label=api > api-deps.dot")
 		fmt.Println("")
@@ -671,7 +912,8 @@ func main() {
 		fmt.Println("  --robot-insights")
 		fmt.Println("      Graph metrics JSON for agents.")
 		fmt.Println("      Top lists: Bottlenecks (betweenness), Keystones (critical path), Influencers (eigenvector),")
-		fmt.Println("                 Cores (k-core), Articulation points (cut vertices), Slack (parallelism headroom).")
+		fmt.Println("                 Cores (k-core), Articulation points (cut vertices), Slack (parallelism headroom),")
+		fmt.Println("                 MustPass (goals dominated, see --robot-dominators).")
 		fmt.Println("      Full maps (capped by BV_INSIGHTS_MAP_LIMIT): pagerank, betweenness, eigenvector, hubs/authorities, core_number, slack.")
 		fmt.Println("      status captures per-metric state: computed|approx|timeout|skipped with elapsed_ms and reasons.")
 		fmt.Println("      Shared fields: data_hash, analysis_config.")
@@ -1216,6 +1458,9 @@ func main() {
 					"command":        *schemaCommand,
 					"schema":         schema,
 				}
+				if input, ok := schemas.Inputs[*schemaCommand]; ok {
+					singleOutput["input_schema"] = input
+				}
 				encoder := newRobotEncoder(os.Stdout)
 				if err := encoder.Encode(singleOutput); err != nil {
 					fmt.Fprintf(os.Stderr, "Error encoding schema: %v\n", err)
@@ -1311,6 +1556,15 @@ func main() {
 	projectDir, _ := os.Getwd()
 	baselinePath := baseline.DefaultPath(projectDir)
 
+	// Handle --robot-watch (long-running; loads and reloads on its own)
+	if *robotWatch {
+		if *asOf != "" || *workspaceConfig != "" || *useDolt {
+			fmt.Fprintln(os.Stderr, "Error: --robot-watch follows the local .beads data and cannot be combined with --as-of, --workspace or --dolt")
+			os.Exit(2)
+		}
+		os.Exit(runRobotWatch(*sinceHash, *watchHeartbeat))
+	}
+
 	// Handle --baseline-info
 	if *baselineInfo {
 		if !baseline.Exists(baselinePath) {
@@ -1351,6 +1605,7 @@ func main() {
 	}
 	var issues []model.Issue
 	var beadsPath string
+	var editStore writeback.Store // TUI write-back target; nil keeps the TUI read-only
 	var workspaceInfo *workspace.LoadSummary
 	var asOfResolved string // Resolved commit SHA when using --as-of (for robot output metadata)
 
@@ -1429,6 +1684,15 @@ func main() {
 			}
 		}
 		beadsPath = ""
+
+		// TUI edits go back to the database each issue was loaded from
+		var databases []string
+		for _, result := range results {
+			if result.Error == nil {
+				databases = append(databases, result.RepoName)
+			}
+		}
+		editStore = writeback.NewDoltStore(*doltCfg, databases)
 	} else {
 		// Load from single repo (original behavior)
 		var err error
@@ -1441,6 +1705,9 @@ func main() {
 		// Get beads file path for live reload (respects BEADS_DIR env var)
 		beadsDir, _ := loader.GetBeadsDir("")
 		beadsPath, _ = loader.FindJSONLPath(beadsDir)
+		if store, err := writeback.OpenStore(beadsDir); err == nil {
+			editStore = store
+		}
 
 		// Automatically ensure .bv/ is in .gitignore to prevent polluting git
 		// with search indexes, baselines, and other bv-specific files.
@@ -1511,13 +1778,7 @@ func main() {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
-		searchCfg, err = applySearchConfigOverrides(searchCfg, *searchMode, *searchPreset, *searchWeights)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
-		}
-
-		embedder, err := search.NewEmbedderFromConfig(embedCfg)
+		searchCfg, err = applySearchConfigOverrides(searchCfg, *searchMode, *searchPreset, *searchWeights, *searchKinds)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
@@ -1528,147 +1789,42 @@ func main() {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
-		indexPath := search.DefaultIndexPath(projectDir, embedCfg)
-		idx, loaded, err := search.LoadOrNewVectorIndex(indexPath, embedder.Dim())
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
-		}
-
-		docs := search.DocumentsFromIssues(issuesForSearch)
-		if !*robotSearch && !loaded {
-			fmt.Fprintf(os.Stderr, "Building semantic index (%d issues)...\n", len(docs))
-		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), embedCfg.SyncTimeout())
 		defer cancel()
 
-		syncStats, err := search.SyncVectorIndex(ctx, idx, embedder, docs, 64)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error building semantic index: %v\n", err)
-			os.Exit(1)
-		}
-		if !loaded || syncStats.Changed() {
-			if err := idx.Save(indexPath); err != nil {
-				fmt.Fprintf(os.Stderr, "Error saving semantic index: %v\n", err)
-				os.Exit(1)
-			}
-		}
-
-		qvecs, err := embedder.Embed(ctx, []string{*semanticQuery})
-		if err != nil || len(qvecs) != 1 {
-			if err == nil {
-				err = fmt.Errorf("embedder returned %d vectors for query", len(qvecs))
-			}
-			fmt.Fprintf(os.Stderr, "Error embedding query: %v\n", err)
-			os.Exit(1)
-		}
-
-		limit := *searchLimit
-		if limit <= 0 {
-			limit = 10
+		req := semanticSearchRequest{
+			Query:      *semanticQuery,
+			Limit:      *searchLimit,
+			Config:     searchCfg,
+			Embedding:  embedCfg,
+			ProjectDir: projectDir,
+			Group:      *searchGroup,
+			History: func() (*correlation.HistoryReport, error) {
+				if err := correlation.ValidateRepository(projectDir); err != nil {
+					return nil, err
+				}
+				beadInfos := make([]correlation.BeadInfo, len(issuesForSearch))
+				for i, issue := range issuesForSearch {
+					beadInfos[i] = correlation.BeadInfo{ID: issue.ID, Title: issue.Title, Status: string(issue.Status)}
+				}
+				correlator := correlation.NewCorrelator(projectDir, beadsPath)
+				return correlator.GenerateReport(beadInfos, correlation.CorrelatorOptions{Limit: *historyLimit})
+			},
+			Sessions: func(ctx context.Context, query string) []cass.SearchResult {
+				return cass.NewSearcher(cass.NewDetector()).SearchInWorkspace(ctx, query, projectDir).Results
+			},
 		}
-		fetchLimit := limit
-		if searchCfg.Mode == search.SearchModeHybrid {
-			fetchLimit = search.HybridCandidateLimit(limit, len(issuesForSearch), *semanticQuery)
+		if !*robotSearch {
+			req.Progress = os.Stderr
 		}
-		results, err := idx.SearchTopK(qvecs[0], fetchLimit)
+		out, indexSize, err := runSemanticSearch(ctx, issuesForSearch, dataHash, req)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error searching index: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
-		results = search.ApplyShortQueryLexicalBoost(results, *semanticQuery, docs)
-		if isLikelyIssueID(*semanticQuery) {
-			results = promoteExactSearchResult(*semanticQuery, results)
-		}
-
-		titleByID := make(map[string]string, len(issuesForSearch))
-		for _, iss := range issuesForSearch {
-			titleByID[iss.ID] = iss.Title
-		}
-
-		var hybridResults []search.HybridScore
-		var resolvedPreset search.PresetName
-		var resolvedWeights *search.Weights
-		if searchCfg.Mode == search.SearchModeHybrid {
-			weights, presetName, err := resolveSearchWeights(searchCfg)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-				os.Exit(1)
-			}
-			weights = weights.Normalize()
-			weights = search.AdjustWeightsForQuery(weights, *semanticQuery)
-			resolvedPreset = presetName
-			resolvedWeights = &weights
-
-			cache := search.NewMetricsCache(search.NewAnalyzerMetricsLoader(issuesForSearch))
-			if err := cache.Refresh(); err != nil {
-				fmt.Fprintf(os.Stderr, "Error computing hybrid metrics: %v\n", err)
-				os.Exit(1)
-			}
-
-			scorer := search.NewHybridScorer(weights, cache)
-			hybridResults, err = buildHybridScores(results, scorer)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error scoring hybrid results: %v\n", err)
-				os.Exit(1)
-			}
-			if isLikelyIssueID(*semanticQuery) {
-				hybridResults = promoteExactHybridResult(*semanticQuery, hybridResults)
-			}
-			if len(hybridResults) > limit {
-				hybridResults = hybridResults[:limit]
-			}
-		}
 
 		if *robotSearch {
-			out := robotSearchOutput{
-				GeneratedAt: time.Now().UTC().Format(time.RFC3339),
-				DataHash:    dataHash,
-				Query:       *semanticQuery,
-				Provider:    embedCfg.Provider,
-				Model:       embedCfg.Model,
-				Dim:         embedder.Dim(),
-				IndexPath:   indexPath,
-				Index:       syncStats,
-				Loaded:      loaded,
-				Limit:       limit,
-				Mode:        searchCfg.Mode,
-			}
-			if searchCfg.Mode == search.SearchModeHybrid {
-				out.Preset = resolvedPreset
-				out.Weights = resolvedWeights
-			}
-			out.Results = make([]robotSearchResult, 0, max(len(results), len(hybridResults)))
-			if searchCfg.Mode == search.SearchModeHybrid {
-				for _, r := range hybridResults {
-					out.Results = append(out.Results, robotSearchResult{
-						IssueID:         r.IssueID,
-						Score:           r.FinalScore,
-						TextScore:       r.TextScore,
-						Title:           titleByID[r.IssueID],
-						ComponentScores: r.ComponentScores,
-					})
-				}
-				out.UsageHints = []string{
-					"jq '.results[] | {id: .issue_id, score: .score, text: .text_score}' - Extract scores",
-					"jq '.results[] | {id: .issue_id, components: .component_scores}' - Hybrid breakdown",
-					"jq '.index' - Index update stats (added/updated/removed/embedded)",
-				}
-			} else {
-				for _, r := range results {
-					out.Results = append(out.Results, robotSearchResult{
-						IssueID: r.IssueID,
-						Score:   r.Score,
-						Title:   titleByID[r.IssueID],
-					})
-				}
-				out.UsageHints = []string{
-					"jq '.results[] | {id: .issue_id, score: .score, title: .title}' - Extract results",
-					"jq '.index' - Index update stats (added/updated/removed/embedded)",
-				}
-			}
-
 			if err := writeRobotSearchOutput(os.Stdout, out); err != nil {
 				fmt.Fprintf(os.Stderr, "Error encoding robot-search: %v\n", err)
 				os.Exit(1)
@@ -1677,16 +1833,24 @@ func main() {
 		}
 
 		// Human-readable output
-		if !loaded || syncStats.Changed() {
-			fmt.Fprintf(os.Stderr, "Index: +%d ~%d -%d (%d total) → %s\n", syncStats.Added, syncStats.Updated, syncStats.Removed, idx.Size(), indexPath)
-		}
-		if searchCfg.Mode == search.SearchModeHybrid {
-			for _, r := range hybridResults {
-				fmt.Printf("%.4f\t%s\t%s\n", r.FinalScore, r.IssueID, titleByID[r.IssueID])
-			}
-		} else {
-			for _, r := range results {
-				fmt.Printf("%.4f\t%s\t%s\n", r.Score, r.IssueID, titleByID[r.IssueID])
+		if !out.Loaded || out.Index.Changed() {
+			fmt.Fprintf(os.Stderr, "Index: +%d ~%d -%d (%d total) → %s\n", out.Index.Added, out.Index.Updated, out.Index.Removed, indexSize, out.IndexPath)
+		}
+		for _, w := range out.Warnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+		}
+		for _, r := range out.Results {
+			fmt.Printf("%.4f\t%s\t%s\n", r.Score, r.IssueID, r.Title)
+			matches := r.Matches
+			if r.Match != nil && r.Match.Kind != search.KindIssue {
+				matches = []search.SearchHit{*r.Match}
+			}
+			for _, m := range matches {
+				line := m.Title
+				if m.Snippet != "" {
+					line += ": " + m.Snippet
+				}
+				fmt.Printf("\t  %-7s %s\n", m.Kind, line)
 			}
 		}
 		os.Exit(0)
@@ -2152,6 +2316,8 @@ func main() {
 			format = export.GraphFormatDOT
 		case "mermaid":
 			format = export.GraphFormatMermaid
+		case "gantt":
+			format = export.GraphFormatGantt
 		default:
 			format = export.GraphFormatJSON
 		}
@@ -2162,6 +2328,7 @@ func main() {
 			Root:     *graphRoot,
 			Depth:    *graphDepth,
 			DataHash: dataHash,
+			Reduced:  *graphReduced,
 		}
 
 		result, err := export.ExportGraph(issues, &stats, config)
@@ -2226,6 +2393,7 @@ func main() {
 				DataHash:    dataHash,
 				Path:        *exportGraph,
 				ProjectName: projectName,
+				Reduced:     *graphReduced,
 			}
 			// Auto-generate filename if just "html" or "interactive"
 			if *exportGraph == "html" || *exportGraph == "interactive" {
@@ -2241,6 +2409,9 @@ func main() {
 		}
 
 		// Static PNG/SVG export (use .html for better interactive graphs)
+		if *graphReduced {
+			exportIssues = analysis.ComputeTransitiveReduction(exportIssues).Apply(exportIssues)
+		}
 		opts := export.GraphSnapshotOptions{
 			Path:     *exportGraph,
 			Title:    *graphTitle,
@@ -2270,62 +2441,11 @@ func main() {
 
 		analyzer := analysis.NewAnalyzer(issues)
 		stats := analyzer.Analyze()
-
-		openCount, closedCount, blockedCount := 0, 0, 0
-		for _, issue := range issues {
-			switch issue.Status {
-			case model.StatusClosed:
-				closedCount++
-			case model.StatusBlocked:
-				blockedCount++
-			case model.StatusOpen, model.StatusInProgress:
-				openCount++
-			default:
-				// Ignore tombstones and any unknown statuses for summary counts.
-			}
-		}
-		actionableCount := len(analyzer.GetActionableIssues())
-		cycles := stats.Cycles()
-		curStats := baseline.GraphStats{
-			NodeCount:       stats.NodeCount,
-			EdgeCount:       stats.EdgeCount,
-			Density:         stats.Density,
-			OpenCount:       openCount,
-			ClosedCount:     closedCount,
-			BlockedCount:    blockedCount,
-			CycleCount:      len(cycles),
-			ActionableCount: actionableCount,
-		}
-
-		// Default behavior (no baseline): drift comparisons are suppressed by using
-		// baseline=current for stats, while still allowing cycle/staleness/cascade alerts.
-		bl := &baseline.Baseline{Stats: curStats}
-		cur := &baseline.Baseline{Stats: curStats, Cycles: cycles}
-
-		// If a baseline exists, compare against it for real drift deltas.
-		if baseline.Exists(baselinePath) {
-			loaded, err := baseline.Load(baselinePath)
-			if err != nil {
-				if !envRobot {
-					fmt.Fprintf(os.Stderr, "Warning: Error loading baseline: %v\n", err)
-				}
-			} else {
-				bl = loaded
-				topMetrics := baseline.TopMetrics{
-					PageRank:     buildMetricItems(stats.PageRank(), 10),
-					Betweenness:  buildMetricItems(stats.Betweenness(), 10),
-					CriticalPath: buildMetricItems(stats.CriticalPathScore(), 10),
-					Hubs:         buildMetricItems(stats.Hubs(), 10),
-					Authorities:  buildMetricItems(stats.Authorities(), 10),
-				}
-				cur = &baseline.Baseline{Stats: curStats, TopMetrics: topMetrics, Cycles: cycles}
-			}
+		driftResult, err := computeDriftAlerts(issues, analyzer, &stats, driftConfig, baselinePath)
+		if err != nil && !envRobot {
+			fmt.Fprintf(os.Stderr, "Warning: Error loading baseline: %v\n", err)
 		}
 
-		calc := drift.NewCalculator(bl, cur, driftConfig)
-		calc.SetIssues(issues)
-		driftResult := calc.Calculate()
-
 		// Apply optional filters
 		filtered := driftResult.Alerts[:0]
 		for _, a := range driftResult.Alerts {
@@ -2392,24 +2512,9 @@ func main() {
 
 	// Handle --robot-suggest (bv-180)
 	if *robotSuggest {
-		config := analysis.DefaultSuggestAllConfig()
-		config.MinConfidence = *suggestConfidence
-		config.FilterBead = *suggestBead
-
-		// Parse filter type
-		switch *suggestType {
-		case "duplicate", "duplicates":
-			config.FilterType = analysis.SuggestionPotentialDuplicate
-		case "dependency", "dependencies":
-			config.FilterType = analysis.SuggestionMissingDependency
-		case "label", "labels":
-			config.FilterType = analysis.SuggestionLabelSuggestion
-		case "cycle", "cycles":
-			config.FilterType = analysis.SuggestionCycleWarning
-		case "":
-			// All types
-		default:
-			fmt.Fprintf(os.Stderr, "Invalid suggest-type: %s (use: duplicate, dependency, label, cycle)\n", *suggestType)
+		config, err := suggestAllConfig(*suggestType, *suggestConfidence, *suggestBead)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
 
@@ -2608,151 +2713,12 @@ func main() {
 			analyzer.SetConfig(&cfg)
 		}
 		stats := analyzer.Analyze()
-		// Generate top 50 lists for summary, but full stats are included in the struct
-		insights := stats.GenerateInsights(50)
-
-		// Add project-level velocity snapshot (using dedicated helper for efficiency)
-		if v := analysis.ComputeProjectVelocity(issues, time.Now(), 8); v != nil {
-			snap := &analysis.VelocitySnapshot{
-				Closed7:   v.ClosedLast7Days,
-				Closed30:  v.ClosedLast30Days,
-				AvgDays:   v.AvgDaysToClose,
-				Estimated: v.Estimated,
-			}
-			if len(v.Weekly) > 0 {
-				snap.Weekly = make([]int, len(v.Weekly))
-				for i := range v.Weekly {
-					snap.Weekly[i] = v.Weekly[i].Closed
-				}
-			}
-			insights.Velocity = snap
-		}
-
-		// Optional cap for metric maps to avoid overload
-		limitMaps := func(m map[string]float64, limit int) map[string]float64 {
-			if limit <= 0 || limit >= len(m) {
-				return m
-			}
-			type kv struct {
-				k string
-				v float64
-			}
-			var items []kv
-			for k, v := range m {
-				items = append(items, kv{k, v})
-			}
-			sort.Slice(items, func(i, j int) bool {
-				if items[i].v == items[j].v {
-					return items[i].k < items[j].k
-				}
-				return items[i].v > items[j].v
-			})
-			trim := make(map[string]float64, limit)
-			for i := 0; i < limit; i++ {
-				trim[items[i].k] = items[i].v
-			}
-			return trim
-		}
-
-		limitMapInt := func(m map[string]int, limit int) map[string]int {
-			if limit <= 0 || len(m) <= limit {
-				return m
-			}
-			trim := make(map[string]int, limit)
-			count := 0
-			for k, v := range m {
-				trim[k] = v
-				count++
-				if count >= limit {
-					break
-				}
-			}
-			return trim
-		}
-
-		limitSlice := func(s []string, limit int) []string {
-			if limit <= 0 || len(s) <= limit {
-				return s
-			}
-			return s[:limit]
-		}
-
-		// Default cap to keep payload small; allow override via env
-		mapLimit := 200
-		if v := os.Getenv("BV_INSIGHTS_MAP_LIMIT"); v != "" {
-			if n, err := strconv.Atoi(v); err == nil && n > 0 {
-				mapLimit = n
-			}
-		}
-
-		fullStats := struct {
-			PageRank          map[string]float64 `json:"pagerank"`
-			Betweenness       map[string]float64 `json:"betweenness"`
-			Eigenvector       map[string]float64 `json:"eigenvector"`
-			Hubs              map[string]float64 `json:"hubs"`
-			Authorities       map[string]float64 `json:"authorities"`
-			CriticalPathScore map[string]float64 `json:"critical_path_score"`
-			CoreNumber        map[string]int     `json:"core_number"`
-			Slack             map[string]float64 `json:"slack"`
-			Articulation      []string           `json:"articulation_points"`
-		}{
-			PageRank:          limitMaps(stats.PageRank(), mapLimit),
-			Betweenness:       limitMaps(stats.Betweenness(), mapLimit),
-			Eigenvector:       limitMaps(stats.Eigenvector(), mapLimit),
-			Hubs:              limitMaps(stats.Hubs(), mapLimit),
-			Authorities:       limitMaps(stats.Authorities(), mapLimit),
-			CriticalPathScore: limitMaps(stats.CriticalPathScore(), mapLimit),
-			CoreNumber:        limitMapInt(stats.CoreNumber(), mapLimit),
-			Slack:             limitMaps(stats.Slack(), mapLimit),
-			Articulation:      limitSlice(stats.ArticulationPoints(), mapLimit),
-		}
-
-		// Get top what-if deltas for issues with highest downstream impact (bv-83)
-		topWhatIfs := analyzer.TopWhatIfDeltas(10)
-
-		// Generate advanced insights with canonical structure (bv-181)
-		advancedInsights := analyzer.GenerateAdvancedInsights(analysis.DefaultAdvancedInsightsConfig())
-
-		output := struct {
-			GeneratedAt    string                  `json:"generated_at"`
-			DataHash       string                  `json:"data_hash"`
-			AsOf           string                  `json:"as_of,omitempty"`        // Historical snapshot ref
-			AsOfCommit     string                  `json:"as_of_commit,omitempty"` // Resolved commit SHA
-			AnalysisConfig analysis.AnalysisConfig `json:"analysis_config"`
-			Status         analysis.MetricStatus   `json:"status"`
-			LabelScope     string                  `json:"label_scope,omitempty"`   // bv-122: Label filter applied
-			LabelContext   *analysis.LabelHealth   `json:"label_context,omitempty"` // bv-122: Health context for scoped label
-			analysis.Insights
-			FullStats        interface{}                `json:"full_stats"`
-			TopWhatIfs       []analysis.WhatIfEntry     `json:"top_what_ifs,omitempty"`      // Issues with highest downstream impact (bv-83)
-			AdvancedInsights *analysis.AdvancedInsights `json:"advanced_insights,omitempty"` // bv-181: Canonical advanced features
-			UsageHints       []string                   `json:"usage_hints"`                 // bv-84: Agent-friendly hints
-		}{
-			GeneratedAt:      time.Now().UTC().Format(time.RFC3339),
-			DataHash:         dataHash,
-			AsOf:             *asOf,
-			AsOfCommit:       asOfResolved,
-			AnalysisConfig:   stats.Config,
-			Status:           stats.Status(),
-			LabelScope:       *labelScope,
-			LabelContext:     labelScopeContext,
-			Insights:         insights,
-			FullStats:        fullStats,
-			TopWhatIfs:       topWhatIfs,
-			AdvancedInsights: advancedInsights,
-			UsageHints: []string{
-				"jq '.Bottlenecks[:5] | map(.ID)' - Top 5 bottleneck IDs",
-				"jq '.CriticalPath[:3]' - Top 3 critical path items",
-				"jq '.top_what_ifs[] | select(.delta.direct_unblocks > 2)' - High-impact items",
-				"jq '.full_stats.pagerank | to_entries | sort_by(-.value)[:5]' - Top PageRank",
-				"jq '.full_stats.core_number | to_entries | sort_by(-.value)[:5]' - Strongly embedded nodes (k-core)",
-				"jq '.full_stats.articulation_points' - Structural cut points",
-				"jq '.Slack[:5]' - Nodes with slack (good parallel work candidates)",
-				"jq '.Cycles | length' - Count of detected cycles",
-				"jq '.advanced_insights.cycle_break' - Cycle break suggestions (bv-181)",
-				"BV_INSIGHTS_MAP_LIMIT=50 bv --robot-insights - Reduce map sizes",
-			},
-		}
+		output := buildRobotInsightsOutput(analyzer, &stats, issues, dataHash, robotScope{
+			AsOf:         *asOf,
+			AsOfCommit:   asOfResolved,
+			Label:        *labelScope,
+			LabelContext: labelScopeContext,
+		})
 
 		encoder := newRobotEncoder(os.Stdout)
 		if err := encoder.Encode(output); err != nil {
@@ -2763,65 +2729,19 @@ func main() {
 	}
 
 	if *robotPlan {
-		analyzer := analysis.NewAnalyzer(issues)
 		// For --robot-plan we primarily need Phase 1 metrics (degree/topo/density).
 		// However, we still emit a stable status contract for agents. If the user
 		// explicitly asks for full analysis, honor it; otherwise, skip expensive
 		// centrality metrics and record the skip reasons deterministically.
-		cfg := analysis.ConfigForSize(len(issues), countEdges(issues))
-		if *forceFullAnalysis {
-			cfg = analysis.FullAnalysisConfig()
-		} else {
-			const skipReason = "not computed for --robot-plan"
-			cfg.ComputePageRank = false
-			cfg.PageRankSkipReason = skipReason
-			cfg.ComputeBetweenness = false
-			cfg.BetweennessMode = analysis.BetweennessSkip
-			cfg.BetweennessSkipReason = skipReason
-			cfg.ComputeHITS = false
-			cfg.HITSSkipReason = skipReason
-			cfg.ComputeEigenvector = false
-			cfg.ComputeCriticalPath = false
-			cfg.ComputeCycles = false
-			cfg.CyclesSkipReason = skipReason
-		}
-
-		plan := analyzer.GetExecutionPlan()
-
-		stats := analyzer.AnalyzeAsyncWithConfig(context.Background(), cfg)
-		stats.WaitForPhase2()
-		status := stats.Status()
+		plan, cfg, status := computeRobotPlan(issues, *forceFullAnalysis)
 
 		// Wrap with metadata
-		output := struct {
-			GeneratedAt    string                  `json:"generated_at"`
-			DataHash       string                  `json:"data_hash"`
-			AsOf           string                  `json:"as_of,omitempty"`        // Historical snapshot ref
-			AsOfCommit     string                  `json:"as_of_commit,omitempty"` // Resolved commit SHA
-			AnalysisConfig analysis.AnalysisConfig `json:"analysis_config"`
-			Status         analysis.MetricStatus   `json:"status"`
-			LabelScope     string                  `json:"label_scope,omitempty"`   // bv-122: Label filter applied
-			LabelContext   *analysis.LabelHealth   `json:"label_context,omitempty"` // bv-122: Health context for scoped label
-			Plan           analysis.ExecutionPlan  `json:"plan"`
-			UsageHints     []string                `json:"usage_hints"` // bv-84: Agent-friendly hints
-		}{
-			GeneratedAt:    time.Now().UTC().Format(time.RFC3339),
-			DataHash:       dataHash,
-			AsOf:           *asOf,
-			AsOfCommit:     asOfResolved,
-			AnalysisConfig: cfg,
-			Status:         status,
-			LabelScope:     *labelScope,
-			LabelContext:   labelScopeContext,
-			Plan:           plan,
-			UsageHints: []string{
-				"jq '.plan.tracks | length' - Number of parallel execution tracks",
-				"jq '.plan.tracks[0].items | map(.id)' - First track item IDs",
-				"jq '.plan.tracks[].items[] | select(.unblocks | length > 0)' - Items that unblock others",
-				"jq '.plan.summary' - High-level execution summary",
-				"jq '[.plan.tracks[].items[]] | length' - Total items across all tracks",
-			},
-		}
+		output := buildRobotPlanOutput(plan, cfg, status, dataHash, robotScope{
+			AsOf:         *asOf,
+			AsOfCommit:   asOfResolved,
+			Label:        *labelScope,
+			LabelContext: labelScopeContext,
+		})
 
 		encoder := newRobotEncoder(os.Stdout)
 		if err := encoder.Encode(output); err != nil {
@@ -3037,52 +2957,7 @@ func main() {
 
 		if *robotNext {
 			// Minimal output: just the top pick
-			envelope := NewRobotEnvelope(dataHash)
-			if len(triage.QuickRef.TopPicks) == 0 {
-				output := struct {
-					RobotEnvelope
-					AsOf       string `json:"as_of,omitempty"`
-					AsOfCommit string `json:"as_of_commit,omitempty"`
-					Message    string `json:"message"`
-				}{
-					RobotEnvelope: envelope,
-					AsOf:          *asOf,
-					AsOfCommit:    asOfResolved,
-					Message:       "No actionable items available",
-				}
-				encoder := newRobotEncoder(os.Stdout)
-				if err := encoder.Encode(output); err != nil {
-					fmt.Fprintf(os.Stderr, "Error encoding robot-next: %v\n", err)
-					os.Exit(1)
-				}
-				os.Exit(0)
-			}
-
-			top := triage.QuickRef.TopPicks[0]
-			output := struct {
-				RobotEnvelope
-				AsOf       string   `json:"as_of,omitempty"`
-				AsOfCommit string   `json:"as_of_commit,omitempty"`
-				ID         string   `json:"id"`
-				Title      string   `json:"title"`
-				Score      float64  `json:"score"`
-				Reasons    []string `json:"reasons"`
-				Unblocks   int      `json:"unblocks"`
-				ClaimCmd   string   `json:"claim_command"`
-				ShowCmd    string   `json:"show_command"`
-			}{
-				RobotEnvelope: envelope,
-				AsOf:          *asOf,
-				AsOfCommit:    asOfResolved,
-				ID:            top.ID,
-				Title:         top.Title,
-				Score:         top.Score,
-				Reasons:       top.Reasons,
-				Unblocks:      top.Unblocks,
-				ClaimCmd:      fmt.Sprintf("br update %s --status=in_progress", top.ID),
-				ShowCmd:       fmt.Sprintf("br show %s", top.ID),
-			}
-
+			output := buildRobotNextOutput(NewRobotEnvelope(dataHash), triage, robotScope{AsOf: *asOf, AsOfCommit: asOfResolved})
 			encoder := newRobotEncoder(os.Stdout)
 			if err := encoder.Encode(output); err != nil {
 				fmt.Fprintf(os.Stderr, "Error encoding robot-next: %v\n", err)
@@ -3092,36 +2967,7 @@ func main() {
 		}
 
 		// Full triage output with usage hints
-		output := struct {
-			GeneratedAt string                 `json:"generated_at"`
-			DataHash    string                 `json:"data_hash"`
-			AsOf        string                 `json:"as_of,omitempty"`        // Historical snapshot ref (e.g., HEAD~30)
-			AsOfCommit  string                 `json:"as_of_commit,omitempty"` // Resolved commit SHA
-			Triage      analysis.TriageResult  `json:"triage"`
-			Feedback    *analysis.FeedbackJSON `json:"feedback,omitempty"` // bv-90: Feedback loop state
-			UsageHints  []string               `json:"usage_hints"`        // bv-84: Agent-friendly hints
-		}{
-			GeneratedAt: time.Now().UTC().Format(time.RFC3339),
-			DataHash:    dataHash,
-			AsOf:        *asOf,
-			AsOfCommit:  asOfResolved,
-			Triage:      triage,
-			Feedback:    feedbackInfo,
-			UsageHints: []string{
-				"jq '.triage.quick_ref.top_picks[:3]' - Top 3 picks for immediate work",
-				"jq '.triage.recommendations[3:10] | map({id,title,score})' - Next candidates after top picks",
-				"jq '.triage.blockers_to_clear | map(.id)' - High-impact blockers to clear",
-				"jq '.triage.recommendations[] | select(.type == \"bug\")' - Bug-focused recommendations",
-				"jq '.triage.quick_ref.top_picks[] | select(.unblocks > 2)' - High-impact picks",
-				"jq '.triage.quick_wins' - Low-effort, high-impact items",
-				"--robot-next - Get only the single top recommendation",
-				"--robot-triage-by-track - Group by execution track for multi-agent coordination",
-				"--robot-triage-by-label - Group by label for area-focused agents",
-				"jq '.triage.recommendations_by_track[].top_pick' - Top pick per track",
-				"jq '.triage.recommendations_by_label[].claim_command' - Claim commands per label",
-				"jq '.feedback.weight_adjustments' - View feedback-adjusted weights (bv-90)",
-			},
-		}
+		output := buildRobotTriageOutput(triage, feedbackInfo, dataHash, robotScope{AsOf: *asOf, AsOfCommit: asOfResolved})
 		encoder := newRobotEncoder(os.Stdout)
 		if err := encoder.Encode(output); err != nil {
 			fmt.Fprintf(os.Stderr, "Error encoding robot-triage: %v\n", err)
@@ -3188,6 +3034,7 @@ func main() {
 		analyzer := analysis.NewAnalyzer(issues)
 		stats := analyzer.Analyze()
 		insights := stats.GenerateInsights(50)
+		insights.MustPass = analysis.ComputeDominators(issues).MustPassItems(50)
 		insightsJSON, err := json.MarshalIndent(insights, "", "  ")
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error marshaling insights: %v\n", err)
@@ -3905,33 +3752,7 @@ func main() {
 			os.Exit(1)
 		}
 
-		fileLookup := correlation.NewFileLookup(report)
-		files := strings.Split(*robotImpact, ",")
-		for i := range files {
-			files[i] = strings.TrimSpace(files[i])
-		}
-
-		impactResult := fileLookup.ImpactAnalysis(files)
-
-		type ImpactOutput struct {
-			RobotEnvelope
-			Files         []string                   `json:"files"`
-			RiskLevel     string                     `json:"risk_level"`
-			RiskScore     float64                    `json:"risk_score"`
-			Summary       string                     `json:"summary"`
-			Warnings      []string                   `json:"warnings"`
-			AffectedBeads []correlation.AffectedBead `json:"affected_beads"`
-		}
-
-		output := ImpactOutput{
-			RobotEnvelope: NewRobotEnvelope(report.DataHash),
-			Files:         impactResult.Files,
-			RiskLevel:     impactResult.RiskLevel,
-			RiskScore:     impactResult.RiskScore,
-			Summary:       impactResult.Summary,
-			Warnings:      impactResult.Warnings,
-			AffectedBeads: impactResult.AffectedBeads,
-		}
+		output := buildRobotImpactOutput(report, strings.Split(*robotImpact, ","))
 
 		encoder := newRobotEncoder(os.Stdout)
 		if err := encoder.Encode(output); err != nil {
@@ -4064,44 +3885,17 @@ func main() {
 			os.Exit(1)
 		}
 
-		// Build dependency graph from issues
-		depGraph := make(map[string][]string)
-		for _, issue := range issues {
-			for _, dep := range issue.Dependencies {
-				depGraph[issue.ID] = append(depGraph[issue.ID], dep.DependsOnID)
-			}
-		}
-
-		// Configure options
-		opts := correlation.RelatedWorkOptions{
-			MinRelevance:      *relatedMinRelevance,
-			MaxResults:        *relatedMaxResults,
-			ConcurrencyWindow: 7 * 24 * time.Hour,
-			IncludeClosed:     *relatedIncludeClosed,
-			DependencyGraph:   depGraph,
-		}
-
-		result := report.FindRelatedWork(*robotRelatedWork, opts)
-		if result == nil {
-			fmt.Fprintf(os.Stderr, "Bead not found in history: %s\n", *robotRelatedWork)
+		output, err := buildRobotRelatedOutput(report, issues, relatedWorkRequest{
+			BeadID:        *robotRelatedWork,
+			MinRelevance:  *relatedMinRelevance,
+			MaxResults:    *relatedMaxResults,
+			IncludeClosed: *relatedIncludeClosed,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
 
-		// Add envelope fields to output
-		type RelatedWorkOutput struct {
-			*correlation.RelatedWorkResult
-			DataHash     string `json:"data_hash"`
-			OutputFormat string `json:"output_format,omitempty"`
-			Version      string `json:"version,omitempty"`
-		}
-
-		output := RelatedWorkOutput{
-			RelatedWorkResult: result,
-			DataHash:          report.DataHash,
-			OutputFormat:      robotOutputFormat,
-			Version:           version.Version,
-		}
-
 		encoder := newRobotEncoder(os.Stdout)
 		if err := encoder.Encode(output); err != nil {
 			fmt.Fprintf(os.Stderr, "Error encoding related work: %v\n", err)
@@ -4132,15 +3926,10 @@ func main() {
 			os.Exit(1)
 		}
 
-		type BlockerChainOutput struct {
-			RobotEnvelope
-			Result *analysis.BlockerChainResult `json:"result"`
-		}
-
 		// Compute data hash for consistency
 		dataHash := analysis.ComputeDataHash(issues)
 
-		output := BlockerChainOutput{
+		output := robotBlockerChainOutput{
 			RobotEnvelope: NewRobotEnvelope(dataHash),
 			Result:        result,
 		}
@@ -4464,145 +4253,193 @@ func main() {
 		analyzer := analysis.NewAnalyzer(issues)
 		graphStats := analyzer.Analyze()
 
-		// Filter issues by label and sprint if specified
-		targetIssues := make([]model.Issue, 0, len(issues))
-		var sprintBeadIDs map[string]bool
-		if *forecastSprint != "" {
-			sprints, err := loader.LoadSprints(cwd)
-			if err == nil {
-				for _, s := range sprints {
-					if s.ID == *forecastSprint {
-						sprintBeadIDs = make(map[string]bool)
-						for _, bid := range s.BeadIDs {
-							sprintBeadIDs[bid] = true
-						}
-						break
-					}
-				}
+		agents := *forecastAgents
+		if !flag.CommandLine.Changed("forecast-agents") && flag.CommandLine.Changed("agents") {
+			agents = *capacityAgents
+		}
+
+		if *forecastPercentiles != "" {
+			percentiles, err := parsePercentiles(*forecastPercentiles)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			sprints, _ := loader.LoadSprints(cwd)
+			var history *correlation.HistoryReport
+			if beadsDir, err := loader.GetBeadsDir(""); err == nil {
+				history = generateHistoryReport(cwd, beadsDir, issues, 500)
+			}
+			output, err := buildRobotMonteCarloForecastOutput(issues, &graphStats, sprints, forecastRequest{
+				Target:      *robotForecast,
+				Label:       *forecastLabel,
+				Sprint:      *forecastSprint,
+				Agents:      agents,
+				Percentiles: percentiles,
+				Runs:        *forecastRuns,
+				History:     history,
+			}, time.Now())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
 			}
-			if sprintBeadIDs == nil {
-				fmt.Fprintf(os.Stderr, "Sprint not found: %s\n", *forecastSprint)
+			encoder := newRobotEncoder(os.Stdout)
+			if err := encoder.Encode(output); err != nil {
+				fmt.Fprintf(os.Stderr, "Error encoding forecast: %v\n", err)
 				os.Exit(1)
 			}
+			os.Exit(0)
 		}
 
-		for _, iss := range issues {
-			// Filter by label
-			if *forecastLabel != "" {
-				hasLabel := false
-				for _, l := range iss.Labels {
-					if l == *forecastLabel {
-						hasLabel = true
-						break
-					}
-				}
-				if !hasLabel {
-					continue
-				}
-			}
-			// Filter by sprint
-			if sprintBeadIDs != nil && !sprintBeadIDs[iss.ID] {
-				continue
-			}
-			targetIssues = append(targetIssues, iss)
+		var sprints []model.Sprint
+		if *forecastSprint != "" {
+			sprints, _ = loader.LoadSprints(cwd)
 		}
 
-		now := time.Now()
-		agents := *forecastAgents
-		if agents <= 0 {
-			agents = 1
+		output, err := buildRobotForecastOutput(issues, &graphStats, sprints, forecastRequest{
+			Target: *robotForecast,
+			Label:  *forecastLabel,
+			Sprint: *forecastSprint,
+			Agents: agents,
+		}, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
 
-		type ForecastSummary struct {
-			TotalMinutes  int       `json:"total_minutes"`
-			TotalDays     float64   `json:"total_days"`
-			AvgConfidence float64   `json:"avg_confidence"`
-			EarliestETA   time.Time `json:"earliest_eta"`
-			LatestETA     time.Time `json:"latest_eta"`
+		encoder := newRobotEncoder(os.Stdout)
+		if err := encoder.Encode(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding forecast: %v\n", err)
+			os.Exit(1)
 		}
-		type ForecastOutput struct {
-			RobotEnvelope
-			Agents        int                    `json:"agents"`
-			Filters       map[string]string      `json:"filters,omitempty"`
-			ForecastCount int                    `json:"forecast_count"`
-			Forecasts     []analysis.ETAEstimate `json:"forecasts"`
-			Summary       *ForecastSummary       `json:"summary,omitempty"`
+		os.Exit(0)
+	}
+
+	// Handle --robot-schedule flag
+	if *robotSchedule {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+		agents := 0
+		if flag.CommandLine.Changed("agents") {
+			agents = *capacityAgents
+		}
+		roster, source, err := scheduleRoster(cwd, *rosterPath, issues, agents)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
+		encoder := newRobotEncoder(os.Stdout)
+		if err := encoder.Encode(buildRobotScheduleOutput(issues, roster, source, time.Now())); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding schedule: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
 
-		var forecasts []analysis.ETAEstimate
-		var outputErr error
+	// Handle --robot-epics flag
+	if *robotEpics {
+		encoder := newRobotEncoder(os.Stdout)
+		if err := encoder.Encode(buildRobotEpicsOutput(issues)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding epic rollups: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
 
-		if *robotForecast == "all" {
-			// Forecast all open issues
-			for _, iss := range targetIssues {
-				if iss.Status == model.StatusClosed {
-					continue
-				}
-				eta, err := analysis.EstimateETAForIssue(issues, &graphStats, iss.ID, agents, now)
-				if err != nil {
-					continue
-				}
-				forecasts = append(forecasts, eta)
-			}
-		} else {
-			// Single issue forecast
-			eta, err := analysis.EstimateETAForIssue(issues, &graphStats, *robotForecast, agents, now)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-				os.Exit(1)
-			}
-			forecasts = append(forecasts, eta)
-		}
-
-		// Build summary if multiple forecasts
-		var summary *ForecastSummary
-		if len(forecasts) > 1 {
-			totalMin := 0
-			totalConf := 0.0
-			earliest := forecasts[0].ETADate
-			latest := forecasts[0].ETADate
-			for _, f := range forecasts {
-				totalMin += f.EstimatedMinutes
-				totalConf += f.Confidence
-				if f.ETADate.Before(earliest) {
-					earliest = f.ETADate
-				}
-				if f.ETADate.After(latest) {
-					latest = f.ETADate
-				}
-			}
-			summary = &ForecastSummary{
-				TotalMinutes:  totalMin,
-				TotalDays:     float64(totalMin) / (60.0 * 8.0), // 8hr workday
-				AvgConfidence: totalConf / float64(len(forecasts)),
-				EarliestETA:   earliest,
-				LatestETA:     latest,
-			}
+	// Handle --robot-flow flag
+	if *robotFlow {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+		var events []correlation.BeadEvent
+		if beadsDir, err := loader.GetBeadsDir(""); err == nil {
+			events = flowEvents(cwd, beadsDir, *historyLimit)
+		}
+		encoder := newRobotEncoder(os.Stdout)
+		if err := encoder.Encode(buildRobotFlowOutput(issues, events, *flowDays, time.Now())); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding flow metrics: %v\n", err)
+			os.Exit(1)
 		}
+		os.Exit(0)
+	}
 
-		// Build output
-		filters := make(map[string]string)
-		if *forecastLabel != "" {
-			filters["label"] = *forecastLabel
+	// Handle --robot-communities flag
+	if *robotCommunities {
+		opts := analysis.DefaultCommunityOptions()
+		opts.MinSize = *communityMinSize
+		opts.Resolution = *communityResolution
+		opts.IncludeClosed = *communityClosed
+		encoder := newRobotEncoder(os.Stdout)
+		if err := encoder.Encode(buildRobotCommunitiesOutput(issues, opts)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding communities: %v\n", err)
+			os.Exit(1)
 		}
-		if *forecastSprint != "" {
-			filters["sprint"] = *forecastSprint
+		os.Exit(0)
+	}
+
+	// Handle --robot-dominators flag
+	if *robotDominators != "" {
+		output, err := buildRobotDominatorsOutput(issues, *robotDominators)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		encoder := newRobotEncoder(os.Stdout)
+		if err := encoder.Encode(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding dominators: %v\n", err)
+			os.Exit(1)
 		}
+		os.Exit(0)
+	}
 
-		output := ForecastOutput{
-			RobotEnvelope: NewRobotEnvelope(analysis.ComputeDataHash(issues)),
-			Agents:        agents,
-			ForecastCount: len(forecasts),
-			Forecasts:     forecasts,
-			Summary:       summary,
+	// Handle --robot-query flag
+	if *robotQuery != "" {
+		output, err := buildRobotQueryOutput(issues, *robotQuery, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
-		if len(filters) > 0 {
-			output.Filters = filters
+		encoder := newRobotEncoder(os.Stdout)
+		if err := encoder.Encode(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding query results: %v\n", err)
+			os.Exit(1)
 		}
+		os.Exit(0)
+	}
 
+	// Handle --robot-whatif flag
+	if *robotWhatIf != "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+		scenarios, err := analysis.LoadScenarioFile(*robotWhatIf)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		agents := 0
+		if flag.CommandLine.Changed("agents") {
+			agents = *capacityAgents
+		}
+		roster, _, err := scheduleRoster(cwd, *rosterPath, issues, agents)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		output, err := buildRobotWhatIfOutput(issues, scenarios, roster, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
 		encoder := newRobotEncoder(os.Stdout)
-		if outputErr = encoder.Encode(output); outputErr != nil {
-			fmt.Fprintf(os.Stderr, "Error encoding forecast: %v\n", outputErr)
+		if err := encoder.Encode(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding what-if report: %v\n", err)
 			os.Exit(1)
 		}
 		os.Exit(0)
@@ -4910,51 +4747,54 @@ func main() {
 
 	if *exportFile != "" {
 		fmt.Printf("Exporting to %s...\n", *exportFile)
+		err := exportWithHooks(*exportFile, "markdown", len(issues), *noHooks, func() error {
+			return export.SaveMarkdownToFile(issues, *exportFile)
+		})
+		if err != nil {
+			fmt.Printf("Error exporting: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("Done!")
+		os.Exit(0)
+	}
 
-		// Load and run pre-export hooks
-		cwd, _ := os.Getwd()
-		var executor *hooks.Executor
-		if !*noHooks {
-			hookLoader := hooks.NewLoader(hooks.WithProjectDir(cwd))
-			if err := hookLoader.Load(); err != nil {
-				fmt.Printf("Warning: failed to load hooks: %v\n", err)
-			} else if hookLoader.HasHooks() {
-				ctx := hooks.ExportContext{
-					ExportPath:   *exportFile,
-					ExportFormat: "markdown",
-					IssueCount:   len(issues),
-					Timestamp:    time.Now(),
-				}
-				executor = hooks.NewExecutor(hookLoader.Config(), ctx)
-
-				// Run pre-export hooks
-				if err := executor.RunPreExport(); err != nil {
-					fmt.Printf("Error: pre-export hook failed: %v\n", err)
-					os.Exit(1)
+	if *exportGantt != "" {
+		group := strings.ToLower(*ganttGroup)
+		if group != export.GanttGroupTrack && group != export.GanttGroupEpic {
+			fmt.Fprintf(os.Stderr, "Error: --gantt-group must be track or epic (got %q)\n", *ganttGroup)
+			os.Exit(1)
+		}
+		ganttIssues := issues
+		if *labelScope != "" {
+			ganttIssues = nil
+			for _, iss := range issues {
+				for _, lbl := range iss.Labels {
+					if strings.EqualFold(lbl, *labelScope) {
+						ganttIssues = append(ganttIssues, iss)
+						break
+					}
 				}
 			}
 		}
-
-		// Perform the export
-		if err := export.SaveMarkdownToFile(issues, *exportFile); err != nil {
+		stats := analysis.NewAnalyzer(ganttIssues).Analyze()
+		fmt.Printf("Exporting gantt chart to %s...\n", *exportGantt)
+		err := exportWithHooks(*exportGantt, "gantt", len(ganttIssues), *noHooks, func() error {
+			return export.SaveGanttToFile(ganttIssues, &stats, export.GanttConfig{GroupBy: group}, *exportGantt)
+		})
+		if err != nil {
 			fmt.Printf("Error exporting: %v\n", err)
 			os.Exit(1)
 		}
+		fmt.Println("Done!")
+		os.Exit(0)
+	}
 
-		// Run post-export hooks
-		if executor != nil {
-			if err := executor.RunPostExport(); err != nil {
-				fmt.Printf("Warning: post-export hook failed: %v\n", err)
-				// Don't exit, just warn
-			}
-
-			// Print hook summary if any hooks ran
-			if len(executor.Results()) > 0 {
-				fmt.Println(executor.Summary())
-			}
+	// Handle --export (recipe reports)
+	if *reportFile != "" {
+		if err := runRecipeExport(issues, activeRecipe, *reportFile, *noHooks); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
-
-		fmt.Println("Done!")
 		os.Exit(0)
 	}
 
@@ -4993,6 +4833,16 @@ func main() {
 	// Initial Model with live reload support
 	m := ui.NewModel(issues, activeRecipe, beadsPath)
 	defer m.Stop() // Clean up file watcher
+	m.SetEditStore(editStore)
+	m.SetRosterPath(*rosterPath)
+	if editStore != nil {
+		// Board moves are checked against .bv/transitions.yaml
+		if rules, err := writeback.LoadRules(projectDir); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v (using default transition rules)\n", err)
+		} else {
+			m.SetTransitionRules(rules)
+		}
+	}
 
 	// Enable workspace mode if loading from workspace config
 	if workspaceInfo != nil {
@@ -5308,6 +5158,14 @@ func applyRecipeFilters(issues []model.Issue, r *recipe.Recipe) []model.Issue {
 	f := r.Filters
 	now := time.Now()
 
+	// Query language filter (invalid queries are rejected when recipes load)
+	q, _ := r.ParsedQuery()
+	var qctx *query.Context
+	if q != nil {
+		qctx = query.NewContext(issues, nil)
+		qctx.Now = now
+	}
+
 	// Build a set of open blocker IDs for actionable filtering
 	openBlockers := make(map[string]bool)
 	for _, issue := range issues {
@@ -5460,6 +5318,10 @@ func applyRecipeFilters(issues []model.Issue, r *recipe.Recipe) []model.Issue {
 			}
 		}
 
+		if q != nil && !q.Match(&issue, qctx) {
+			continue
+		}
+
 		result = append(result, issue)
 	}
 
@@ -7580,7 +7442,13 @@ func newJSONRobotEncoder(w io.Writer) *json.Encoder {
 // Default output is JSON. Use `--format toon` (or BV_OUTPUT_FORMAT/TOON_DEFAULT_FORMAT)
 // to emit TOON for agent-friendly token savings.
 func newRobotEncoder(w io.Writer) robotEncoder {
-	if robotOutputFormat == "toon" {
+	return newRobotEncoderForFormat(w, robotOutputFormat)
+}
+
+// newRobotEncoderForFormat is like newRobotEncoder but takes an explicit
+// format, for callers (e.g. bv serve) that negotiate it per request.
+func newRobotEncoderForFormat(w io.Writer, format string) robotEncoder {
+	if format == "toon" {
 		return &toonRobotEncoder{w: w}
 	}
 	return newJSONRobotEncoder(w)
@@ -7709,10 +7577,16 @@ func generateRobotDocs(topic string) map[string]interface{} {
 			Params:      []string{"--severity info|warning|critical", "--alert-type <type>", "--alert-label <label>"},
 			NeedsIssues: true,
 		},
+		"robot-watch": {
+			Flag: "--robot-watch", Description: "Long-running NDJSON stream of deltas (added/removed/modified, top pick, newly actionable, new alerts) with heartbeats.",
+			KeyFields:   []string{"type", "seq", "data_hash", "prev_hash", "added", "removed", "modified", "top_pick", "newly_actionable", "new_alerts"},
+			Params:      []string{"--since-hash <data_hash>", "--watch-heartbeat <duration>"},
+			NeedsIssues: true,
+		},
 		"robot-suggest": {
-			Flag: "--robot-suggest", Description: "Smart suggestions: potential duplicates, missing dependencies, label assignments, cycle warnings.",
+			Flag: "--robot-suggest", Description: "Smart suggestions: potential duplicates, missing dependencies, label assignments, cycle warnings, priority inversions, redundant dependencies.",
 			KeyFields:   []string{"suggestions", "type", "confidence"},
-			Params:      []string{"--suggest-type duplicate|dependency|label|cycle", "--suggest-confidence 0.0-1.0", "--suggest-bead <id>"},
+			Params:      []string{"--suggest-type duplicate|dependency|label|cycle|inversion|redundant", "--suggest-confidence 0.0-1.0", "--suggest-bead <id>"},
 			NeedsIssues: true,
 		},
 		"robot-schema": {
@@ -7737,8 +7611,8 @@ func generateRobotDocs(topic string) map[string]interface{} {
 			NeedsIssues: true,
 		},
 		"robot-search": {
-			Flag: "--robot-search", Description: "Semantic vector search over issue titles and descriptions.",
-			Params:      []string{"--search <query>", "--search-limit <n>", "--search-mode text|hybrid"},
+			Flag: "--robot-search", Description: "Semantic vector search over issues, optionally also comments, commits and cass sessions.",
+			Params:      []string{"--search <query>", "--search-limit <n>", "--search-mode text|hybrid|fusion", "--search-kinds <kinds>", "--search-group"},
 			NeedsIssues: true,
 		},
 		"robot-label-health": {
@@ -7756,7 +7630,7 @@ func generateRobotDocs(topic string) map[string]interface{} {
 		},
 		"robot-graph": {
 			Flag: "--robot-graph", Description: "Dependency graph export in JSON, DOT, or Mermaid format.",
-			Params:      []string{"--graph-format json|dot|mermaid", "--graph-root <id>", "--graph-depth <n>"},
+			Params:      []string{"--graph-format json|dot|mermaid|gantt", "--graph-root <id>", "--graph-depth <n>", "--graph-reduced"},
 			NeedsIssues: true,
 		},
 		"robot-metrics": {
@@ -7811,7 +7685,7 @@ func generateRobotDocs(topic string) map[string]interface{} {
 		},
 		"robot-forecast": {
 			Flag: "--robot-forecast <id|all>", Description: "ETA predictions for bead completion.",
-			Params:      []string{"--forecast-label <label>", "--forecast-sprint <id>", "--forecast-agents <n>"},
+			Params:      []string{"--forecast-label <label>", "--forecast-sprint <id>", "--forecast-agents <n>", "--percentiles <p,p,...>", "--forecast-runs <n>"},
 			NeedsIssues: true,
 		},
 		"robot-capacity": {
@@ -7819,6 +7693,38 @@ func generateRobotDocs(topic string) map[string]interface{} {
 			Params:      []string{"--agents <n>", "--capacity-label <label>"},
 			NeedsIssues: true,
 		},
+		"robot-schedule": {
+			Flag: "--robot-schedule", Description: "Per-person/agent schedule of open work honoring blockers, assignees and capacity.",
+			Params:      []string{"--roster <path>", "--agents <n>"},
+			NeedsIssues: true,
+		},
+		"robot-flow": {
+			Flag: "--robot-flow", Description: "Kanban flow metrics: lead/cycle time percentiles, weekly throughput, WIP aging and cumulative flow series.",
+			Params:      []string{"--flow-days <n>", "--history-limit <n>"},
+			NeedsIssues: true,
+		},
+		"robot-whatif": {
+			Flag: "--robot-whatif <scenarios.json>", Description: "Before/after actionable count, critical path, top picks, cycles and forecast finish for batches of hypothetical edits.",
+			Params:      []string{"--roster <path>", "--agents <n>"},
+			NeedsIssues: true,
+		},
+		"robot-communities": {
+			Flag: "--robot-communities", Description: "Louvain communities of the blocks/related graph with cohesion, proposed epic titles and labels, and members outside the dominant epic.",
+			Params:      []string{"--community-min-size <n>", "--community-resolution <r>", "--community-closed"},
+			NeedsIssues: true,
+		},
+		"robot-dominators": {
+			Flag: "--robot-dominators <id|all>", Description: "Dominator-tree chokepoints every route to a goal must pass through, plus a must-pass ranking by goals dominated.",
+			NeedsIssues: true,
+		},
+		"robot-query": {
+			Flag: "--robot-query <query>", Description: "Issues matching a structured query (status:open,in_progress priority<=1 -label:wontfix blockers:(assignee:bob) pagerank>0.02 updated<14d \"login\"); same language as the TUI '/' prompt and recipe query fields.",
+			NeedsIssues: true,
+		},
+		"robot-epics": {
+			Flag: "--robot-epics", Description: "Progress rollups for every epic/parent: percent complete, remaining minutes, blocked/actionable counts, zombie and orphan-progress flags.",
+			NeedsIssues: true,
+		},
 		"robot-burndown": {
 			Flag: "--robot-burndown <sprint|current>", Description: "Sprint burndown data.",
 			NeedsIssues: true,
@@ -7850,8 +7756,9 @@ func generateRobotDocs(topic string) map[string]interface{} {
 		"TOON_INDENT":         "TOON indentation level (0-16)",
 		"BV_PRETTY_JSON":      "Set to 1 for indented JSON output",
 		"BV_ROBOT":            "Set to 1 to force robot mode (clean stdout)",
-		"BV_SEARCH_MODE":      "Search mode: text or hybrid",
+		"BV_SEARCH_MODE":      "Search mode: text, hybrid or fusion",
 		"BV_SEARCH_PRESET":    "Hybrid search preset name",
+		"BV_SEARCH_KINDS":     "Documents --search covers: issue,comment,commit,session or all",
 	}
 
 	exitCodes := map[string]string{
@@ -7891,6 +7798,7 @@ type RobotSchemas struct {
 	GeneratedAt   string                            `json:"generated_at"`
 	Envelope      map[string]interface{}            `json:"envelope"`
 	Commands      map[string]map[string]interface{} `json:"commands"`
+	Inputs        map[string]map[string]interface{} `json:"inputs,omitempty"` // Argument schemas (used by bv mcp tools)
 }
 
 // generateRobotSchemas creates JSON Schema definitions for robot command outputs
@@ -8051,6 +7959,7 @@ func generateRobotSchemas() RobotSchemas {
 				"Orphans":           map[string]interface{}{"type": "array"},
 				"Cores":             map[string]interface{}{"type": "object"},
 				"Articulation":      map[string]interface{}{"type": "array"},
+				"MustPass":          map[string]interface{}{"type": "array"},
 				"Slack":             map[string]interface{}{"type": "object"},
 				"Velocity":          map[string]interface{}{"type": "object"},
 				"status":            map[string]interface{}{"type": "object"},
@@ -8074,15 +7983,16 @@ func generateRobotSchemas() RobotSchemas {
 		"robot-graph": {
 			"$schema":     "https://json-schema.org/draft/2020-12/schema",
 			"title":       "Robot Graph Output",
-			"description": "Dependency graph in JSON/DOT/Mermaid format",
+			"description": "Dependency graph in JSON/DOT/Mermaid format, or a Mermaid gantt of open work",
 			"type":        "object",
 			"properties": map[string]interface{}{
-				"generated_at": map[string]interface{}{"type": "string", "format": "date-time"},
-				"data_hash":    map[string]interface{}{"type": "string"},
-				"format":       map[string]interface{}{"type": "string", "enum": []string{"json", "dot", "mermaid"}},
-				"nodes":        map[string]interface{}{"type": "array"},
-				"edges":        map[string]interface{}{"type": "array"},
-				"stats":        map[string]interface{}{"type": "object"},
+				"generated_at":            map[string]interface{}{"type": "string", "format": "date-time"},
+				"data_hash":               map[string]interface{}{"type": "string"},
+				"format":                  map[string]interface{}{"type": "string", "enum": []string{"json", "dot", "mermaid", "gantt"}},
+				"nodes":                   map[string]interface{}{"type": "array"},
+				"edges":                   map[string]interface{}{"type": "array"},
+				"stats":                   map[string]interface{}{"type": "object"},
+				"redundant_edges_removed": map[string]interface{}{"type": "integer", "description": "Blocking edges dropped by --graph-reduced"},
 			},
 		},
 		"robot-diff": {
@@ -8113,10 +8023,34 @@ func generateRobotSchemas() RobotSchemas {
 				"summary":      map[string]interface{}{"type": "object"},
 			},
 		},
+		"robot-watch": {
+			"$schema":     "https://json-schema.org/draft/2020-12/schema",
+			"title":       "Robot Watch Event",
+			"description": "One NDJSON line of --robot-watch output",
+			"type":        "object",
+			"properties": map[string]interface{}{
+				"generated_at":     map[string]interface{}{"type": "string", "format": "date-time"},
+				"data_hash":        map[string]interface{}{"type": "string"},
+				"type":             map[string]interface{}{"type": "string", "enum": []string{"snapshot", "delta", "heartbeat", "error"}},
+				"seq":              map[string]interface{}{"type": "integer"},
+				"prev_hash":        map[string]interface{}{"type": "string"},
+				"issue_count":      map[string]interface{}{"type": "integer"},
+				"resumed":          map[string]interface{}{"type": "boolean"},
+				"resync_reason":    map[string]interface{}{"type": "string"},
+				"added":            map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+				"removed":          map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+				"modified":         map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+				"top_pick":         map[string]interface{}{"type": "object"},
+				"prev_top_pick":    map[string]interface{}{"type": "string"},
+				"newly_actionable": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+				"new_alerts":       map[string]interface{}{"type": "array"},
+				"error":            map[string]interface{}{"type": "string"},
+			},
+		},
 		"robot-suggest": {
 			"$schema":     "https://json-schema.org/draft/2020-12/schema",
 			"title":       "Robot Suggest Output",
-			"description": "Smart suggestions for duplicates, dependencies, labels, cycle breaks",
+			"description": "Smart suggestions for duplicates, dependencies, labels, cycle breaks, priority inversions, redundant dependencies",
 			"type":        "object",
 			"properties": map[string]interface{}{
 				"generated_at": map[string]interface{}{"type": "string", "format": "date-time"},
@@ -8139,16 +8073,187 @@ func generateRobotSchemas() RobotSchemas {
 				"at_risk":       map[string]interface{}{"type": "array"},
 			},
 		},
+		"robot-schedule": {
+			"$schema":     "https://json-schema.org/draft/2020-12/schema",
+			"title":       "Robot Schedule Output",
+			"description": "Open work assigned to roster workers with per-worker timelines",
+			"type":        "object",
+			"properties": map[string]interface{}{
+				"generated_at": map[string]interface{}{"type": "string", "format": "date-time"},
+				"data_hash":    map[string]interface{}{"type": "string"},
+				"roster":       map[string]interface{}{"type": "string", "description": "Roster file used, or \"derived\""},
+				"start":        map[string]interface{}{"type": "string", "format": "date-time"},
+				"finish":       map[string]interface{}{"type": "string", "format": "date-time"},
+				"days":         map[string]interface{}{"type": "number"},
+				"workers":      map[string]interface{}{"type": "array", "description": "Per-worker tasks, busy_hours, utilization and finish"},
+				"tasks":        map[string]interface{}{"type": "array", "description": "Scheduled issues by start"},
+				"unscheduled":  map[string]interface{}{"type": "array"},
+				"warnings":     map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			},
+		},
+		"robot-flow": {
+			"$schema":     "https://json-schema.org/draft/2020-12/schema",
+			"title":       "Robot Flow Output",
+			"description": "Kanban flow metrics from status transitions in git history",
+			"type":        "object",
+			"properties": map[string]interface{}{
+				"generated_at": map[string]interface{}{"type": "string", "format": "date-time"},
+				"data_hash":    map[string]interface{}{"type": "string"},
+				"source":       map[string]interface{}{"type": "string", "enum": []string{"git", "timestamps"}},
+				"start":        map[string]interface{}{"type": "string", "format": "date-time"},
+				"end":          map[string]interface{}{"type": "string", "format": "date-time"},
+				"days":         map[string]interface{}{"type": "integer"},
+				"lead_time":    map[string]interface{}{"type": "object", "description": "Created to closed: count, mean/p50/p85/p95/min/max days"},
+				"cycle_time":   map[string]interface{}{"type": "object", "description": "First in_progress to closed: count, mean/p50/p85/p95/min/max days"},
+				"throughput":   map[string]interface{}{"type": "object", "description": "weeks[] of week_start and closed, total, mean_per_week"},
+				"wip_aging":    map[string]interface{}{"type": "array", "description": "In-progress issues, oldest first, with age_days, percentile and over_p85"},
+				"cfd":          map[string]interface{}{"type": "object", "description": "dates[] plus series[] of status and daily counts"},
+			},
+		},
+		"robot-whatif": {
+			"$schema":     "https://json-schema.org/draft/2020-12/schema",
+			"title":       "Robot What-If Output",
+			"description": "Plan metrics for the current state and for each hypothetical scenario",
+			"type":        "object",
+			"properties": map[string]interface{}{
+				"generated_at": map[string]interface{}{"type": "string", "format": "date-time"},
+				"data_hash":    map[string]interface{}{"type": "string"},
+				"baseline":     map[string]interface{}{"type": "object", "description": "open, actionable, blocked, critical_path_length, critical_path, top_picks, cycles, workers, forecast_days, forecast_date"},
+				"scenarios":    map[string]interface{}{"type": "array", "description": "name, extends, ops (inherited first), after (baseline fields), delta and warnings"},
+			},
+		},
+		"robot-communities": {
+			"$schema":     "https://json-schema.org/draft/2020-12/schema",
+			"title":       "Robot Communities Output",
+			"description": "Modularity clusters of open issues with proposed epics and labels",
+			"type":        "object",
+			"properties": map[string]interface{}{
+				"generated_at": map[string]interface{}{"type": "string", "format": "date-time"},
+				"data_hash":    map[string]interface{}{"type": "string"},
+				"nodes":        map[string]interface{}{"type": "integer"},
+				"edges":        map[string]interface{}{"type": "integer"},
+				"modularity":   map[string]interface{}{"type": "number"},
+				"communities":  map[string]interface{}{"type": "array", "description": "id, size, members, internal_edges, boundary_edges, cohesion, keywords, suggested_title, suggested_label, label_coverage, missing_label, parent, without_parent, commands"},
+				"unclustered":  map[string]interface{}{"type": "integer"},
+			},
+		},
+		"robot-dominators": {
+			"$schema":     "https://json-schema.org/draft/2020-12/schema",
+			"title":       "Robot Dominators Output",
+			"description": "Mandatory chokepoints on the way to goals in the open work graph",
+			"type":        "object",
+			"properties": map[string]interface{}{
+				"generated_at": map[string]interface{}{"type": "string", "format": "date-time"},
+				"data_hash":    map[string]interface{}{"type": "string"},
+				"nodes":        map[string]interface{}{"type": "integer"},
+				"edges":        map[string]interface{}{"type": "integer"},
+				"goals":        map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+				"unreachable":  map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+				"must_pass":    map[string]interface{}{"type": "array", "description": "id, title, actionable, goals_dominated, goals"},
+				"dominators":   map[string]interface{}{"type": "array", "description": "goal, title, is_goal, reachable, chokepoints (id, title, status, actionable, depth), upstream"},
+			},
+		},
+		"robot-query": {
+			"$schema":     "https://json-schema.org/draft/2020-12/schema",
+			"title":       "Robot Query Output",
+			"description": "Issues matching a structured query",
+			"type":        "object",
+			"properties": map[string]interface{}{
+				"generated_at": map[string]interface{}{"type": "string", "format": "date-time"},
+				"data_hash":    map[string]interface{}{"type": "string"},
+				"query":        map[string]interface{}{"type": "string"},
+				"parsed":       map[string]interface{}{"type": "string", "description": "Canonical form with explicit grouping"},
+				"fields":       map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+				"count":        map[string]interface{}{"type": "integer"},
+				"issues":       map[string]interface{}{"type": "array", "description": "id, title, status, priority, type, assignee, labels"},
+			},
+		},
+		"robot-epics": {
+			"$schema":     "https://json-schema.org/draft/2020-12/schema",
+			"title":       "Robot Epics Output",
+			"description": "Progress aggregated up the parent-child hierarchy",
+			"type":        "object",
+			"properties": map[string]interface{}{
+				"generated_at":    map[string]interface{}{"type": "string", "format": "date-time"},
+				"data_hash":       map[string]interface{}{"type": "string"},
+				"epics":           map[string]interface{}{"type": "array", "description": "Parents in tree order with descendant counts, percent_complete, remaining_minutes, blocked, actionable and flags"},
+				"zombies":         map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+				"orphan_progress": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+				"summary":         map[string]interface{}{"type": "object"},
+			},
+		},
 		"robot-forecast": {
 			"$schema":     "https://json-schema.org/draft/2020-12/schema",
 			"title":       "Robot Forecast Output",
-			"description": "ETA predictions with dependency-aware scheduling",
+			"description": "ETA predictions with dependency-aware scheduling; with --percentiles, Monte Carlo date ranges",
 			"type":        "object",
 			"properties": map[string]interface{}{
 				"generated_at": map[string]interface{}{"type": "string", "format": "date-time"},
 				"data_hash":    map[string]interface{}{"type": "string"},
 				"forecasts":    map[string]interface{}{"type": "array"},
 				"methodology":  map[string]interface{}{"type": "object"},
+				"method":       map[string]interface{}{"type": "string", "enum": []string{"monte_carlo"}},
+				"completion":   map[string]interface{}{"type": "object", "description": "Percentile dates for finishing every targeted issue"},
+				"sample_basis": map[string]interface{}{"type": "string", "enum": []string{"cycle_time", "lead_time", "mixed"}, "description": "Whether historical durations are claim-to-close cycle times or created-to-closed lead times"},
+				"histogram":    map[string]interface{}{"type": "array", "description": "Completion outcomes: start/end days and run counts"},
+				"issues":       map[string]interface{}{"type": "array", "description": "Per-issue percentile dates"},
+				"epics":        map[string]interface{}{"type": "array"},
+				"labels":       map[string]interface{}{"type": "array"},
+				"sprints":      map[string]interface{}{"type": "array"},
+			},
+		},
+		"robot-blocker-chain": {
+			"$schema":     "https://json-schema.org/draft/2020-12/schema",
+			"title":       "Robot Blocker Chain Output",
+			"description": "Full blocker chain from an issue down to its root blockers",
+			"type":        "object",
+			"properties": map[string]interface{}{
+				"generated_at": map[string]interface{}{"type": "string", "format": "date-time"},
+				"data_hash":    map[string]interface{}{"type": "string"},
+				"result":       map[string]interface{}{"type": "object"},
+			},
+		},
+		"robot-impact": {
+			"$schema":     "https://json-schema.org/draft/2020-12/schema",
+			"title":       "Robot Impact Output",
+			"description": "Beads affected by modifying a set of files, with a risk level",
+			"type":        "object",
+			"properties": map[string]interface{}{
+				"generated_at":   map[string]interface{}{"type": "string", "format": "date-time"},
+				"data_hash":      map[string]interface{}{"type": "string"},
+				"files":          map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+				"risk_level":     map[string]interface{}{"type": "string", "enum": []string{"low", "medium", "high", "critical"}},
+				"risk_score":     map[string]interface{}{"type": "number"},
+				"affected_beads": map[string]interface{}{"type": "array"},
+			},
+		},
+		"robot-related": {
+			"$schema":     "https://json-schema.org/draft/2020-12/schema",
+			"title":       "Robot Related Work Output",
+			"description": "Beads related to a target bead by file, commit, dependency and time overlap",
+			"type":        "object",
+			"properties": map[string]interface{}{
+				"data_hash":          map[string]interface{}{"type": "string"},
+				"target_bead_id":     map[string]interface{}{"type": "string"},
+				"file_overlap":       map[string]interface{}{"type": "array"},
+				"commit_overlap":     map[string]interface{}{"type": "array"},
+				"dependency_cluster": map[string]interface{}{"type": "array"},
+				"concurrent":         map[string]interface{}{"type": "array"},
+				"total_related":      map[string]interface{}{"type": "integer"},
+			},
+		},
+		"robot-search": {
+			"$schema":     "https://json-schema.org/draft/2020-12/schema",
+			"title":       "Robot Search Output",
+			"description": "Semantic (optionally hybrid-ranked) search over issue text",
+			"type":        "object",
+			"properties": map[string]interface{}{
+				"generated_at": map[string]interface{}{"type": "string", "format": "date-time"},
+				"data_hash":    map[string]interface{}{"type": "string"},
+				"query":        map[string]interface{}{"type": "string"},
+				"mode":         map[string]interface{}{"type": "string", "enum": []string{"text", "hybrid", "fusion"}},
+				"kinds":        map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string", "enum": []string{"issue", "comment", "commit", "session"}}},
+				"results":      map[string]interface{}{"type": "array"},
 			},
 		},
 	}
@@ -8158,5 +8263,77 @@ func generateRobotSchemas() RobotSchemas {
 		GeneratedAt:   now,
 		Envelope:      envelope,
 		Commands:      commands,
+		Inputs:        robotInputSchemas(),
+	}
+}
+
+// robotInputSchemas describes the arguments each robot command accepts, keyed
+// like RobotSchemas.Commands. Property names mirror the CLI flags without the
+// command prefix (e.g. --related-min-relevance becomes min_relevance).
+func robotInputSchemas() map[string]map[string]interface{} {
+	object := func(props map[string]interface{}, required ...string) map[string]interface{} {
+		schema := map[string]interface{}{
+			"type":                 "object",
+			"properties":           props,
+			"additionalProperties": false,
+		}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	}
+	id := func(desc string) map[string]interface{} {
+		return map[string]interface{}{"type": "string", "description": desc}
+	}
+
+	return map[string]map[string]interface{}{
+		"robot-triage": object(map[string]interface{}{
+			"group": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"track", "label"},
+				"description": "Also group recommendations by execution track or by label",
+			},
+		}),
+		"robot-next": object(map[string]interface{}{}),
+		"robot-plan": object(map[string]interface{}{}),
+		"robot-blocker-chain": object(map[string]interface{}{
+			"id": id("Issue ID whose blockers to trace"),
+		}, "id"),
+		"robot-impact": object(map[string]interface{}{
+			"files": map[string]interface{}{
+				"type":        "array",
+				"items":       map[string]interface{}{"type": "string"},
+				"minItems":    1,
+				"description": "Repository-relative file paths about to be modified",
+			},
+		}, "files"),
+		"robot-related": object(map[string]interface{}{
+			"id":             id("Bead ID to find related work for"),
+			"min_relevance":  map[string]interface{}{"type": "integer", "minimum": 0, "maximum": 100, "default": 20, "description": "Minimum relevance score"},
+			"max_results":    map[string]interface{}{"type": "integer", "minimum": 0, "default": 10, "description": "Max results per category (0 = unlimited)"},
+			"include_closed": map[string]interface{}{"type": "boolean", "default": false, "description": "Include closed beads"},
+		}, "id"),
+		"robot-forecast": object(map[string]interface{}{
+			"id":     id("Issue ID to forecast, or \"all\" for every open issue"),
+			"label":  map[string]interface{}{"type": "string", "description": "Only forecast issues with this label"},
+			"sprint": map[string]interface{}{"type": "string", "description": "Only forecast issues in this sprint"},
+			"agents": map[string]interface{}{"type": "integer", "minimum": 1, "default": 1, "description": "Parallel agents working the backlog"},
+			"percentiles": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "integer", "minimum": 1, "maximum": 100},
+				"description": "Monte Carlo mode: report these completion percentiles (e.g. [50, 85, 95])"},
+			"runs": map[string]interface{}{"type": "integer", "minimum": 1, "default": analysis.DefaultMonteCarloRuns, "description": "Simulations in Monte Carlo mode"},
+		}, "id"),
+		"robot-search": object(map[string]interface{}{
+			"query":   map[string]interface{}{"type": "string", "minLength": 1, "description": "Free-text search query"},
+			"limit":   map[string]interface{}{"type": "integer", "minimum": 1, "default": 10, "description": "Max results"},
+			"mode":    map[string]interface{}{"type": "string", "enum": []string{"text", "hybrid", "fusion"}, "description": "Ranking mode (default: BV_SEARCH_MODE or text)"},
+			"preset":  map[string]interface{}{"type": "string", "enum": []string{"default", "bug-hunting", "sprint-planning", "impact-first", "text-only"}, "description": "Hybrid ranking preset"},
+			"weights": map[string]interface{}{"type": "string", "description": "Hybrid weights JSON (overrides preset)"},
+			"kinds":   map[string]interface{}{"type": "string", "description": "Comma-separated document kinds: issue, comment, commit, session or all"},
+		}, "query"),
+		"robot-suggest": object(map[string]interface{}{
+			"type":           map[string]interface{}{"type": "string", "enum": []string{"duplicate", "dependency", "label", "cycle", "inversion", "redundant"}, "description": "Only return this suggestion type"},
+			"min_confidence": map[string]interface{}{"type": "number", "minimum": 0, "maximum": 1, "default": 0, "description": "Minimum suggestion confidence"},
+			"bead":           map[string]interface{}{"type": "string", "description": "Only return suggestions involving this bead"},
+		}),
 	}
 }
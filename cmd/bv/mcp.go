@@ -0,0 +1,729 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/Dicklesworthstone/beads_viewer/internal/datasource"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/search"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/version"
+)
+
+// `bv mcp` speaks the Model Context Protocol over stdio (newline-delimited
+// JSON-RPC 2.0) so agent hosts can call robot commands as native tools
+// instead of shelling out and parsing stdout. It shares the warm snapshot
+// machinery with `bv serve`: issues, graph metrics and history are loaded
+// once and reloaded when .beads changes.
+
+// mcpProtocolVersion is the newest protocol revision this server implements.
+const mcpProtocolVersion = "2025-06-18"
+
+// mcpSupportedVersions lists every revision we can answer, newest first.
+var mcpSupportedVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// mcpResourcePageSize bounds resources/list pages for large projects.
+const mcpResourcePageSize = 200
+
+// beadURIScheme prefixes bead resource URIs (bead://bv-123).
+const beadURIScheme = "bead://"
+
+// JSON-RPC error codes.
+const (
+	mcpErrParse            = -32700
+	mcpErrInvalidRequest   = -32600
+	mcpErrMethodNotFound   = -32601
+	mcpErrInvalidParams    = -32602
+	mcpErrInternal         = -32603
+	mcpErrResourceNotFound = -32002
+)
+
+type mcpRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type mcpResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *mcpError       `json:"error,omitempty"`
+}
+
+type mcpNotification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+type mcpError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *mcpError) Error() string { return e.Message }
+
+// mcpTool binds a robot command to an MCP tool. The input schema is taken
+// from generateRobotSchemas().Inputs[Command].
+type mcpTool struct {
+	Name        string
+	Command     string
+	Description string
+	Call        func(s *mcpServer, snap *serveSnapshot, args json.RawMessage) (any, error)
+}
+
+// mcpToolDescriptor is the tools/list wire shape.
+type mcpToolDescriptor struct {
+	Name        string         `json:"name"`
+	Title       string         `json:"title,omitempty"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+}
+
+type mcpContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type mcpToolResult struct {
+	Content []mcpContent `json:"content"`
+	IsError bool         `json:"isError,omitempty"`
+}
+
+type mcpResource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType"`
+}
+
+type mcpResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// mcpServer dispatches JSON-RPC requests against a serveServer snapshot.
+type mcpServer struct {
+	srv   *serveServer
+	tools []mcpTool
+	index map[string]*mcpTool
+
+	outMu sync.Mutex
+	out   io.Writer
+
+	// initialized is set once the client has completed the handshake;
+	// list_changed notifications are suppressed until then.
+	mu          sync.Mutex
+	initialized bool
+}
+
+// runMCP is the entry point for `bv mcp`.
+func runMCP(args []string) int {
+	fs := flag.NewFlagSet("mcp", flag.ContinueOnError)
+	opts := serveOptions{Format: "json", Quiet: true}
+	fs.IntVar(&opts.HistoryLimit, "history-limit", 500, "Max commits to analyze for correlation history (0 = unlimited)")
+	fs.BoolVar(&opts.NoWatch, "no-watch", false, "Do not reload when .beads changes")
+	fs.BoolVar(&opts.NoHistory, "no-history", false, "Skip git history correlation (disables the impact and related tools)")
+	verbose := fs.Bool("verbose", false, "Log reloads and watcher errors to stderr")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: bv mcp [options]")
+		fmt.Fprintln(os.Stderr, "\nRun a Model Context Protocol server on stdio exposing robot commands as tools")
+		fmt.Fprintln(os.Stderr, "and beads as bead://<id> resources.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	opts.Quiet = !*verbose
+	// stdout carries the protocol; keep loaders from printing to it.
+	_ = os.Setenv("BV_ROBOT", "1")
+
+	projectDir, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting working directory: %v\n", err)
+		return 1
+	}
+	beadsDir, err := loader.GetBeadsDir("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting beads directory: %v\n", err)
+		return 1
+	}
+
+	srv := newServeServer(opts, projectDir, beadsDir, func() ([]model.Issue, error) {
+		return datasource.LoadIssues("")
+	})
+	srv.logPrefix = "bv mcp"
+	if err := srv.reload(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading beads: %v\n", err)
+		fmt.Fprintln(os.Stderr, "Make sure you are in a project initialized with 'bd init'.")
+		return 1
+	}
+
+	m := newMCPServer(srv, os.Stdout)
+	if !opts.NoWatch {
+		srv.onReload = func(*serveSnapshot) { m.notify("notifications/resources/list_changed", nil) }
+		stop := srv.watch()
+		defer stop()
+	}
+
+	if err := m.serve(os.Stdin); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func newMCPServer(srv *serveServer, out io.Writer) *mcpServer {
+	m := &mcpServer{
+		srv:   srv,
+		tools: mcpTools(),
+		out:   out,
+	}
+	m.index = make(map[string]*mcpTool, len(m.tools))
+	for i := range m.tools {
+		m.index[m.tools[i].Name] = &m.tools[i]
+	}
+	return m
+}
+
+// serve reads one JSON-RPC message per line until EOF. Requests are handled
+// sequentially; responses are written in order.
+func (m *mcpServer) serve(in io.Reader) error {
+	r := bufio.NewReader(in)
+	for {
+		line, err := r.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			if resp := m.handleMessage(line); resp != nil {
+				m.write(resp)
+			}
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func (m *mcpServer) write(v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "bv mcp: encoding response: %v\n", err)
+		return
+	}
+	m.outMu.Lock()
+	defer m.outMu.Unlock()
+	_, _ = m.out.Write(append(data, '\n'))
+}
+
+func (m *mcpServer) notify(method string, params any) {
+	m.mu.Lock()
+	ready := m.initialized
+	m.mu.Unlock()
+	if !ready {
+		return
+	}
+	m.write(mcpNotification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+// handleMessage decodes and dispatches one message. It returns nil for
+// notifications, which never get a response.
+func (m *mcpServer) handleMessage(line []byte) *mcpResponse {
+	var req mcpRequest
+	if err := json.Unmarshal(line, &req); err != nil {
+		return &mcpResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &mcpError{Code: mcpErrParse, Message: fmt.Sprintf("parse error: %v", err)}}
+	}
+	isNotification := len(req.ID) == 0
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		if isNotification {
+			return nil
+		}
+		return &mcpResponse{JSONRPC: "2.0", ID: req.ID, Error: &mcpError{Code: mcpErrInvalidRequest, Message: "invalid request"}}
+	}
+
+	result, err := m.dispatch(req)
+	if isNotification {
+		return nil
+	}
+	resp := &mcpResponse{JSONRPC: "2.0", ID: req.ID}
+	if err != nil {
+		var rpcErr *mcpError
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &mcpError{Code: mcpErrInternal, Message: err.Error()}
+		}
+		resp.Error = rpcErr
+		return resp
+	}
+	resp.Result = result
+	return resp
+}
+
+func (m *mcpServer) dispatch(req mcpRequest) (any, error) {
+	switch req.Method {
+	case "initialize":
+		return m.initialize(req.Params)
+	case "notifications/initialized":
+		m.mu.Lock()
+		m.initialized = true
+		m.mu.Unlock()
+		return nil, nil
+	case "ping":
+		return struct{}{}, nil
+	case "tools/list":
+		return m.listTools(), nil
+	case "tools/call":
+		return m.callTool(req.Params)
+	case "resources/list":
+		return m.listResources(req.Params)
+	case "resources/templates/list":
+		return map[string]any{
+			"resourceTemplates": []map[string]any{{
+				"uriTemplate": beadURIScheme + "{id}",
+				"name":        "bead",
+				"title":       "Bead",
+				"description": "A single issue with its dependencies, comments and graph metrics",
+				"mimeType":    "application/json",
+			}},
+		}, nil
+	case "resources/read":
+		return m.readResource(req.Params)
+	}
+	if strings.HasPrefix(req.Method, "notifications/") {
+		return nil, nil
+	}
+	return nil, &mcpError{Code: mcpErrMethodNotFound, Message: fmt.Sprintf("method not found: %s", req.Method)}
+}
+
+func (m *mcpServer) initialize(params json.RawMessage) (any, error) {
+	var p struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &mcpError{Code: mcpErrInvalidParams, Message: fmt.Sprintf("invalid initialize params: %v", err)}
+		}
+	}
+	// Echo the client's version when we support it, else offer our newest.
+	negotiated := mcpProtocolVersion
+	for _, v := range mcpSupportedVersions {
+		if v == p.ProtocolVersion {
+			negotiated = v
+			break
+		}
+	}
+	return map[string]any{
+		"protocolVersion": negotiated,
+		"capabilities": map[string]any{
+			"tools":     map[string]any{"listChanged": false},
+			"resources": map[string]any{"listChanged": !m.srv.opts.NoWatch},
+		},
+		"serverInfo": map[string]any{
+			"name":    "bv",
+			"title":   "beads_viewer",
+			"version": version.Version,
+		},
+		"instructions": "Graph-aware triage for beads issue trackers. Start with the triage or next tool; " +
+			"read bead://<id> resources for full issue records. Payloads match bv --robot-* output.",
+	}, nil
+}
+
+func (m *mcpServer) listTools() any {
+	schemas := generateRobotSchemas()
+	tools := make([]mcpToolDescriptor, 0, len(m.tools))
+	for _, t := range m.tools {
+		input := schemas.Inputs[t.Command]
+		if input == nil {
+			input = map[string]any{"type": "object"}
+		}
+		desc := t.Description
+		if out, ok := schemas.Commands[t.Command]; ok {
+			if d, ok := out["description"].(string); ok && d != "" {
+				desc = d + ". " + desc
+			}
+		}
+		tools = append(tools, mcpToolDescriptor{
+			Name:        t.Name,
+			Title:       "bv --" + t.Command,
+			Description: desc,
+			InputSchema: input,
+		})
+	}
+	return map[string]any{"tools": tools}
+}
+
+func (m *mcpServer) callTool(params json.RawMessage) (any, error) {
+	var p struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &mcpError{Code: mcpErrInvalidParams, Message: fmt.Sprintf("invalid tools/call params: %v", err)}
+	}
+	tool, ok := m.index[p.Name]
+	if !ok {
+		return nil, &mcpError{Code: mcpErrInvalidParams, Message: fmt.Sprintf("unknown tool: %s", p.Name)}
+	}
+
+	// Tool failures are reported in-band so the model can see and react to them.
+	payload, err := tool.Call(m, m.srv.snapshot(), p.Arguments)
+	if err != nil {
+		return mcpToolResult{Content: []mcpContent{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(payload); err != nil {
+		return nil, fmt.Errorf("encoding %s result: %w", p.Name, err)
+	}
+	return mcpToolResult{Content: []mcpContent{{Type: "text", Text: strings.TrimRight(buf.String(), "\n")}}}, nil
+}
+
+func (m *mcpServer) listResources(params json.RawMessage) (any, error) {
+	var p struct {
+		Cursor string `json:"cursor"`
+	}
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &mcpError{Code: mcpErrInvalidParams, Message: fmt.Sprintf("invalid resources/list params: %v", err)}
+		}
+	}
+	start := 0
+	if p.Cursor != "" {
+		n, err := strconv.Atoi(p.Cursor)
+		if err != nil || n < 0 {
+			return nil, &mcpError{Code: mcpErrInvalidParams, Message: fmt.Sprintf("invalid cursor %q", p.Cursor)}
+		}
+		start = n
+	}
+
+	snap := m.srv.snapshot()
+	ids := make([]string, 0, len(snap.IssueMap))
+	for id := range snap.IssueMap {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	if start > len(ids) {
+		start = len(ids)
+	}
+	end := start + mcpResourcePageSize
+	if end > len(ids) {
+		end = len(ids)
+	}
+
+	resources := make([]mcpResource, 0, end-start)
+	for _, id := range ids[start:end] {
+		issue := snap.IssueMap[id]
+		resources = append(resources, mcpResource{
+			URI:         beadURIScheme + id,
+			Name:        id,
+			Title:       issue.Title,
+			Description: fmt.Sprintf("%s · P%d · %s", issue.Status, issue.Priority, issue.IssueType),
+			MimeType:    "application/json",
+		})
+	}
+	result := map[string]any{"resources": resources}
+	if end < len(ids) {
+		result["nextCursor"] = strconv.Itoa(end)
+	}
+	return result, nil
+}
+
+// mcpBead is the bead:// resource payload: the raw issue plus the graph
+// context an agent usually needs next.
+type mcpBead struct {
+	RobotEnvelope
+	Issue        model.Issue `json:"issue"`
+	OpenBlockers []string    `json:"open_blockers"`
+	PageRank     float64     `json:"pagerank"`
+	Betweenness  float64     `json:"betweenness"`
+	CriticalPath float64     `json:"critical_path_score"`
+}
+
+func (m *mcpServer) readResource(params json.RawMessage) (any, error) {
+	var p struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &mcpError{Code: mcpErrInvalidParams, Message: fmt.Sprintf("invalid resources/read params: %v", err)}
+	}
+	id, ok := strings.CutPrefix(p.URI, beadURIScheme)
+	if !ok || id == "" {
+		return nil, &mcpError{Code: mcpErrResourceNotFound, Message: fmt.Sprintf("resource not found: %s", p.URI)}
+	}
+	snap := m.srv.snapshot()
+	issue, ok := snap.IssueMap[id]
+	if !ok {
+		return nil, &mcpError{Code: mcpErrResourceNotFound, Message: fmt.Sprintf("resource not found: %s", p.URI)}
+	}
+
+	snap.analyzerMu.Lock()
+	blockers := snap.Analyzer.GetOpenBlockers(id)
+	snap.analyzerMu.Unlock()
+	if blockers == nil {
+		blockers = []string{}
+	}
+	bead := mcpBead{
+		RobotEnvelope: NewRobotEnvelope(snap.DataHash),
+		Issue:         issue,
+		OpenBlockers:  blockers,
+		PageRank:      snap.Stats.GetPageRankScore(id),
+		Betweenness:   snap.Stats.GetBetweennessScore(id),
+		CriticalPath:  snap.Stats.GetCriticalPathScore(id),
+	}
+	data, err := json.MarshalIndent(bead, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{
+		"contents": []mcpResourceContents{{URI: p.URI, MimeType: "application/json", Text: string(data)}},
+	}, nil
+}
+
+// decodeToolArgs strictly decodes tool arguments; unknown keys are rejected
+// to match the additionalProperties:false input schemas.
+func decodeToolArgs(raw json.RawMessage, v any) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+var errNoHistory = errors.New("history unavailable (not a git repository, or started with --no-history)")
+
+// mcpTools registers the robot commands exposed as MCP tools.
+func mcpTools() []mcpTool {
+	return []mcpTool{
+		{
+			Name:        "triage",
+			Command:     "robot-triage",
+			Description: "Same payload as bv --robot-triage.",
+			Call: func(_ *mcpServer, snap *serveSnapshot, raw json.RawMessage) (any, error) {
+				var args struct {
+					Group string `json:"group"`
+				}
+				if err := decodeToolArgs(raw, &args); err != nil {
+					return nil, err
+				}
+				triage, err := snap.triage(args.Group)
+				if err != nil {
+					return nil, err
+				}
+				return buildRobotTriageOutput(triage, snap.Feedback, snap.DataHash, robotScope{}), nil
+			},
+		},
+		{
+			Name:        "next",
+			Command:     "robot-next",
+			Description: "The single top pick with claim and show commands; same payload as bv --robot-next.",
+			Call: func(_ *mcpServer, snap *serveSnapshot, raw json.RawMessage) (any, error) {
+				if err := decodeToolArgs(raw, &struct{}{}); err != nil {
+					return nil, err
+				}
+				return buildRobotNextOutput(NewRobotEnvelope(snap.DataHash), snap.Triage, robotScope{}), nil
+			},
+		},
+		{
+			Name:        "plan",
+			Command:     "robot-plan",
+			Description: "Same payload as bv --robot-plan.",
+			Call: func(_ *mcpServer, snap *serveSnapshot, raw json.RawMessage) (any, error) {
+				if err := decodeToolArgs(raw, &struct{}{}); err != nil {
+					return nil, err
+				}
+				return snap.plan(), nil
+			},
+		},
+		{
+			Name:        "blocker-chain",
+			Command:     "robot-blocker-chain",
+			Description: "Same payload as bv --robot-blocker-chain <id>.",
+			Call: func(_ *mcpServer, snap *serveSnapshot, raw json.RawMessage) (any, error) {
+				var args struct {
+					ID string `json:"id"`
+				}
+				if err := decodeToolArgs(raw, &args); err != nil {
+					return nil, err
+				}
+				if args.ID == "" {
+					return nil, errors.New("missing required argument: id")
+				}
+				result := snap.blockerChain(args.ID)
+				if result == nil {
+					return nil, fmt.Errorf("issue not found: %s", args.ID)
+				}
+				return robotBlockerChainOutput{RobotEnvelope: NewRobotEnvelope(snap.DataHash), Result: result}, nil
+			},
+		},
+		{
+			Name:        "impact",
+			Command:     "robot-impact",
+			Description: "Same payload as bv --robot-impact <files>. Requires git history.",
+			Call: func(_ *mcpServer, snap *serveSnapshot, raw json.RawMessage) (any, error) {
+				var args struct {
+					Files []string `json:"files"`
+				}
+				if err := decodeToolArgs(raw, &args); err != nil {
+					return nil, err
+				}
+				if len(args.Files) == 0 {
+					return nil, errors.New("missing required argument: files")
+				}
+				if snap.History == nil {
+					return nil, errNoHistory
+				}
+				return buildRobotImpactOutput(snap.History, args.Files), nil
+			},
+		},
+		{
+			Name:        "related",
+			Command:     "robot-related",
+			Description: "Same payload as bv --robot-related <id>. Requires git history.",
+			Call: func(_ *mcpServer, snap *serveSnapshot, raw json.RawMessage) (any, error) {
+				args := struct {
+					ID            string `json:"id"`
+					MinRelevance  *int   `json:"min_relevance"`
+					MaxResults    *int   `json:"max_results"`
+					IncludeClosed bool   `json:"include_closed"`
+				}{}
+				if err := decodeToolArgs(raw, &args); err != nil {
+					return nil, err
+				}
+				if args.ID == "" {
+					return nil, errors.New("missing required argument: id")
+				}
+				if snap.History == nil {
+					return nil, errNoHistory
+				}
+				req := relatedWorkRequest{BeadID: args.ID, MinRelevance: 20, MaxResults: 10, IncludeClosed: args.IncludeClosed}
+				if args.MinRelevance != nil {
+					req.MinRelevance = *args.MinRelevance
+				}
+				if args.MaxResults != nil {
+					req.MaxResults = *args.MaxResults
+				}
+				return buildRobotRelatedOutput(snap.History, snap.Issues, req)
+			},
+		},
+		{
+			Name:        "forecast",
+			Command:     "robot-forecast",
+			Description: "Same payload as bv --robot-forecast <id|all>.",
+			Call: func(s *mcpServer, snap *serveSnapshot, raw json.RawMessage) (any, error) {
+				var args struct {
+					ID          string `json:"id"`
+					Label       string `json:"label"`
+					Sprint      string `json:"sprint"`
+					Agents      int    `json:"agents"`
+					Percentiles []int  `json:"percentiles"`
+					Runs        int    `json:"runs"`
+				}
+				if err := decodeToolArgs(raw, &args); err != nil {
+					return nil, err
+				}
+				if args.ID == "" {
+					return nil, errors.New("missing required argument: id")
+				}
+				req := forecastRequest{
+					Target: args.ID,
+					Label:  args.Label,
+					Sprint: args.Sprint,
+					Agents: args.Agents,
+				}
+				if len(args.Percentiles) > 0 {
+					sprints, _ := loader.LoadSprints(s.srv.projectDir)
+					req.Percentiles, req.Runs, req.History = args.Percentiles, args.Runs, snap.History
+					return buildRobotMonteCarloForecastOutput(snap.Issues, snap.Stats, sprints, req, time.Now())
+				}
+				var sprints []model.Sprint
+				if args.Sprint != "" {
+					sprints, _ = loader.LoadSprints(s.srv.projectDir)
+				}
+				return buildRobotForecastOutput(snap.Issues, snap.Stats, sprints, req, time.Now())
+			},
+		},
+		{
+			Name:        "search",
+			Command:     "robot-search",
+			Description: "Same payload as bv --robot-search --search <query>.",
+			Call: func(s *mcpServer, snap *serveSnapshot, raw json.RawMessage) (any, error) {
+				var args struct {
+					Query   string `json:"query"`
+					Limit   int    `json:"limit"`
+					Mode    string `json:"mode"`
+					Preset  string `json:"preset"`
+					Weights string `json:"weights"`
+					Kinds   string `json:"kinds"`
+				}
+				if err := decodeToolArgs(raw, &args); err != nil {
+					return nil, err
+				}
+				query := strings.TrimSpace(args.Query)
+				if query == "" {
+					return nil, errors.New("missing required argument: query")
+				}
+				limit := args.Limit
+				if limit <= 0 {
+					limit = 10
+				}
+				cfg, err := search.SearchConfigFromEnv()
+				if err == nil {
+					cfg, err = applySearchConfigOverrides(cfg, args.Mode, args.Preset, args.Weights, args.Kinds)
+				}
+				if err != nil {
+					return nil, err
+				}
+				ctx, cancel := context.WithTimeout(context.Background(), search.EmbeddingConfigFromEnv().SyncTimeout())
+				defer cancel()
+				return s.srv.search(ctx, snap, query, limit, cfg)
+			},
+		},
+		{
+			Name:        "suggest",
+			Command:     "robot-suggest",
+			Description: "Same payload as bv --robot-suggest.",
+			Call: func(_ *mcpServer, snap *serveSnapshot, raw json.RawMessage) (any, error) {
+				var args struct {
+					Type          string  `json:"type"`
+					MinConfidence float64 `json:"min_confidence"`
+					Bead          string  `json:"bead"`
+				}
+				if err := decodeToolArgs(raw, &args); err != nil {
+					return nil, err
+				}
+				config, err := suggestAllConfig(args.Type, args.MinConfidence, args.Bead)
+				if err != nil {
+					return nil, err
+				}
+				return analysis.GenerateRobotSuggestOutput(snap.Issues, config, snap.DataHash), nil
+			},
+		},
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// runMCPSession feeds newline-delimited requests to a fresh server and
+// returns the responses keyed by request id.
+func runMCPSession(t *testing.T, issues []model.Issue, requests ...string) map[float64]map[string]any {
+	t.Helper()
+	srv := newServeServer(serveOptions{NoHistory: true, NoWatch: true, Quiet: true}, t.TempDir(), "", func() ([]model.Issue, error) {
+		return issues, nil
+	})
+	if err := srv.reload(); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	var out bytes.Buffer
+	if err := newMCPServer(srv, &out).serve(strings.NewReader(strings.Join(requests, "\n") + "\n")); err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+
+	responses := make(map[float64]map[string]any)
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var msg map[string]any
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			t.Fatalf("invalid response line %q: %v", line, err)
+		}
+		id, ok := msg["id"].(float64)
+		if !ok {
+			t.Fatalf("response without numeric id: %s", line)
+		}
+		responses[id] = msg
+	}
+	return responses
+}
+
+// toolPayload decodes the JSON text content of a tools/call result.
+func toolPayload(t *testing.T, resp map[string]any) map[string]any {
+	t.Helper()
+	result, _ := resp["result"].(map[string]any)
+	if result == nil {
+		t.Fatalf("missing result: %v", resp)
+	}
+	if result["isError"] == true {
+		t.Fatalf("tool returned error: %v", result["content"])
+	}
+	content := result["content"].([]any)[0].(map[string]any)
+	var payload map[string]any
+	if err := json.Unmarshal([]byte(content["text"].(string)), &payload); err != nil {
+		t.Fatalf("tool text is not JSON: %v", err)
+	}
+	return payload
+}
+
+func TestMCPToolsUseRobotSchemas(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "M-1", Title: "Root", Status: model.StatusOpen, Priority: 1, IssueType: model.TypeTask},
+		{ID: "M-2", Title: "Child", Status: model.StatusOpen, Priority: 2, IssueType: model.TypeTask,
+			Dependencies: []*model.Dependency{{IssueID: "M-2", DependsOnID: "M-1", Type: model.DepBlocks}}},
+	}
+	resps := runMCPSession(t, issues,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"next","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"blocker-chain","arguments":{"id":"M-2"}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"blocker-chain","arguments":{"bogus":true}}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"tools/call","params":{"name":"impact","arguments":{"files":["main.go"]}}}`,
+		`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"nope"}}`,
+		`{"jsonrpc":"2.0","id":8,"method":"nope/nope"}`,
+	)
+
+	if init := resps[1]["result"].(map[string]any); init["protocolVersion"] != "2024-11-05" {
+		t.Fatalf("protocolVersion = %v, want echoed 2024-11-05", init["protocolVersion"])
+	}
+
+	inputs := generateRobotSchemas().Inputs
+	tools := resps[2]["result"].(map[string]any)["tools"].([]any)
+	want := []string{"triage", "next", "plan", "blocker-chain", "impact", "related", "forecast", "search", "suggest"}
+	if len(tools) != len(want) {
+		t.Fatalf("got %d tools, want %d", len(tools), len(want))
+	}
+	for i, raw := range tools {
+		tool := raw.(map[string]any)
+		if tool["name"] != want[i] {
+			t.Fatalf("tool[%d] = %v, want %s", i, tool["name"], want[i])
+		}
+		if _, ok := inputs["robot-"+want[i]]; !ok {
+			t.Fatalf("no input schema for robot-%s", want[i])
+		}
+		if tool["inputSchema"].(map[string]any)["type"] != "object" {
+			t.Fatalf("tool %s inputSchema is not an object schema", want[i])
+		}
+	}
+
+	if next := toolPayload(t, resps[3]); next["id"] != "M-1" {
+		t.Fatalf("next id = %v, want M-1", next["id"])
+	}
+	if chain := toolPayload(t, resps[4]); chain["result"] == nil {
+		t.Fatalf("blocker-chain missing result")
+	}
+	for _, id := range []float64{5, 6} {
+		if result := resps[id]["result"].(map[string]any); result["isError"] != true {
+			t.Fatalf("request %v: expected isError result, got %v", id, result)
+		}
+	}
+	if code := resps[7]["error"].(map[string]any)["code"].(float64); code != mcpErrInvalidParams {
+		t.Fatalf("unknown tool code = %v, want %d", code, mcpErrInvalidParams)
+	}
+	if code := resps[8]["error"].(map[string]any)["code"].(float64); code != mcpErrMethodNotFound {
+		t.Fatalf("unknown method code = %v, want %d", code, mcpErrMethodNotFound)
+	}
+}
+
+func TestMCPBeadResources(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "bv-1", Title: "First", Status: model.StatusOpen, Priority: 1, IssueType: model.TypeBug},
+		{ID: "bv-2", Title: "Second", Status: model.StatusClosed, Priority: 3, IssueType: model.TypeTask},
+	}
+	resps := runMCPSession(t, issues,
+		`{"jsonrpc":"2.0","id":1,"method":"resources/list"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"resources/read","params":{"uri":"bead://bv-2"}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"resources/read","params":{"uri":"bead://missing"}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"resources/templates/list"}`,
+	)
+
+	list := resps[1]["result"].(map[string]any)["resources"].([]any)
+	if len(list) != 2 || list[0].(map[string]any)["uri"] != "bead://bv-1" {
+		t.Fatalf("unexpected resources/list: %v", list)
+	}
+
+	contents := resps[2]["result"].(map[string]any)["contents"].([]any)[0].(map[string]any)
+	var bead struct {
+		Issue model.Issue `json:"issue"`
+	}
+	if err := json.Unmarshal([]byte(contents["text"].(string)), &bead); err != nil {
+		t.Fatalf("decode bead: %v", err)
+	}
+	if bead.Issue.ID != "bv-2" || bead.Issue.Status != model.StatusClosed {
+		t.Fatalf("read wrong bead: %+v", bead.Issue)
+	}
+
+	if code := resps[3]["error"].(map[string]any)["code"].(float64); code != mcpErrResourceNotFound {
+		t.Fatalf("missing bead code = %v, want %d", code, mcpErrResourceNotFound)
+	}
+	tmpl := resps[4]["result"].(map[string]any)["resourceTemplates"].([]any)[0].(map[string]any)
+	if tmpl["uriTemplate"] != "bead://{id}" {
+		t.Fatalf("uriTemplate = %v", tmpl["uriTemplate"])
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/mergedriver"
+)
+
+// mergeConflictRecord is one line of the conflicts sidecar.
+type mergeConflictRecord struct {
+	MergedAt time.Time `json:"merged_at"`
+	File     string    `json:"file,omitempty"`
+	mergedriver.Conflict
+}
+
+// runMergeDriver is the entry point for `bv merge-driver`, invoked by git
+// as `bv merge-driver %O %A %B %P`. The merged result replaces %A.
+func runMergeDriver(args []string) int {
+	fs := flag.NewFlagSet("merge-driver", flag.ContinueOnError)
+	conflictsPath := fs.String("conflicts", "", "Where to append unresolved field conflicts (default: <file>.merge-conflicts.jsonl beside the merged file)")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: bv merge-driver [--conflicts=PATH] <base> <ours> <theirs> [path]")
+		fmt.Fprintln(os.Stderr, "\nThree-way merge of beads JSONL, per issue and per field. Register it with:")
+		fmt.Fprintln(os.Stderr, "  git config merge.beads.driver 'bv merge-driver %O %A %B %P'")
+		fmt.Fprintln(os.Stderr, "  echo '.beads/*.jsonl merge=beads' >> .gitattributes")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() < 3 || fs.NArg() > 4 {
+		fs.Usage()
+		return 2
+	}
+	basePath, oursPath, theirsPath := fs.Arg(0), fs.Arg(1), fs.Arg(2)
+	target := fs.Arg(3) // %P: the path in the work tree, when git passes it
+
+	var sides [3][]byte
+	for i, path := range []string{basePath, oursPath, theirsPath} {
+		data, err := os.ReadFile(path)
+		if err != nil && !(i == 0 && errors.Is(err, os.ErrNotExist)) {
+			fmt.Fprintf(os.Stderr, "bv merge-driver: %v\n", err)
+			return 1
+		}
+		sides[i] = data
+	}
+
+	// Any error leaves %A untouched and exits non-zero, so git falls back
+	// to reporting a regular conflict.
+	res, err := mergedriver.Merge(sides[0], sides[1], sides[2])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "bv merge-driver: %s: %v\n", displayMergePath(target), err)
+		return 1
+	}
+	if err := os.WriteFile(oursPath, res.Data, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "bv merge-driver: %v\n", err)
+		return 1
+	}
+
+	if len(res.Conflicts) > 0 {
+		sidecar := *conflictsPath
+		if sidecar == "" {
+			sidecar = defaultConflictsPath(target)
+		}
+		if err := appendMergeConflicts(sidecar, target, res.Conflicts); err != nil {
+			fmt.Fprintf(os.Stderr, "bv merge-driver: writing %s: %v\n", sidecar, err)
+			return 1
+		}
+		fmt.Fprintf(os.Stderr, "bv merge-driver: %s: %d field conflict(s) resolved by updated_at; review %s\n",
+			displayMergePath(target), len(res.Conflicts), sidecar)
+	}
+	return 0
+}
+
+func displayMergePath(target string) string {
+	if target == "" {
+		return "beads JSONL"
+	}
+	return target
+}
+
+// defaultConflictsPath puts the sidecar next to the merged file. The
+// ".merge" in its name keeps the loader from mistaking it for issue data.
+func defaultConflictsPath(target string) string {
+	if target != "" {
+		return strings.TrimSuffix(target, ".jsonl") + ".merge-conflicts.jsonl"
+	}
+	beadsDir, err := loader.GetBeadsDir("")
+	if err != nil {
+		beadsDir = ".beads"
+	}
+	return filepath.Join(beadsDir, "issues.merge-conflicts.jsonl")
+}
+
+// appendMergeConflicts appends one JSON line per conflict, so successive
+// merges accumulate until someone reviews and deletes the file.
+func appendMergeConflicts(path, target string, conflicts []mergedriver.Conflict) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	now := time.Now().UTC()
+	for _, c := range conflicts {
+		if err := enc.Encode(mergeConflictRecord{MergedAt: now, File: target, Conflict: c}); err != nil {
+			return err
+		}
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
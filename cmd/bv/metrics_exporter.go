@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/Dicklesworthstone/beads_viewer/internal/datasource"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/baseline"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/drift"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/metrics"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// GET /metrics publishes project health (the triage ProjectHealth block,
+// per-label health and drift alert counts) together with bv's own timing
+// and cache registries in the OpenMetrics text format, so Prometheus can
+// scrape `bv serve` or the lighter `bv metrics-exporter`.
+
+const defaultMetricsExporterAddr = "127.0.0.1:9477"
+
+// runMetricsExporter is the entry point for `bv metrics-exporter`: a serve
+// daemon that only answers /metrics and /health.
+func runMetricsExporter(args []string) int {
+	fs := flag.NewFlagSet("metrics-exporter", flag.ContinueOnError)
+	opts := serveOptions{Format: "json", NoHistory: true}
+	fs.StringVar(&opts.Addr, "addr", defaultMetricsExporterAddr, "TCP listen address")
+	fs.StringVar(&opts.Socket, "socket", "", "Listen on a Unix domain socket instead of TCP")
+	fs.BoolVar(&opts.NoWatch, "no-watch", false, "Do not reload when .beads changes")
+	history := fs.Bool("history", false, "Correlate git history so bv_issues_stale is published (slower reloads)")
+	fs.IntVar(&opts.HistoryLimit, "history-limit", 500, "Max commits to analyze with --history (0 = unlimited)")
+	fs.BoolVar(&opts.Quiet, "quiet", false, "Suppress reload log lines on stderr")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: bv metrics-exporter [options]")
+		fmt.Fprintln(os.Stderr, "\nServe project health and bv timings on /metrics for Prometheus.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	opts.NoHistory = !*history
+	_ = os.Setenv("BV_ROBOT", "1")
+
+	projectDir, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting working directory: %v\n", err)
+		return 1
+	}
+	beadsDir, err := loader.GetBeadsDir("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting beads directory: %v\n", err)
+		return 1
+	}
+
+	srv := newServeServer(opts, projectDir, beadsDir, func() ([]model.Issue, error) {
+		return datasource.LoadIssues("")
+	})
+	srv.logPrefix = "bv metrics-exporter"
+	if err := srv.reload(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading beads: %v\n", err)
+		fmt.Fprintln(os.Stderr, "Make sure you are in a project initialized with 'bd init'.")
+		return 1
+	}
+
+	if !opts.NoWatch {
+		stop := srv.watch()
+		defer stop()
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /metrics", srv.handleMetrics)
+	mux.HandleFunc("GET /health", srv.handleHealth)
+	return srv.listenAndServe(mux)
+}
+
+func (s *serveServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	snap, reloads, lastErr := s.snap, s.reloads, s.lastErr
+	s.mu.RUnlock()
+
+	// Render into a buffer so a failure can still become a proper 500.
+	var buf bytes.Buffer
+	om := metrics.NewOpenMetricsWriter(&buf)
+	if err := s.writeProjectMetrics(om, snap); err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+	om.Family("bv_reloads", "counter", "", "Successful reloads since the daemon started.")
+	om.Sample("bv_reloads_total", float64(reloads))
+	stale := 0.0
+	if lastErr != nil {
+		stale = 1
+	}
+	om.Gauge("bv_reload_failing", "1 when the most recent reload failed and stale data is served.", stale)
+	om.WriteRegistries()
+	if err := om.Close(); err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", metrics.OpenMetricsContentType)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// writeProjectMetrics writes the gauges derived from one snapshot.
+func (s *serveServer) writeProjectMetrics(om *metrics.OpenMetricsWriter, snap *serveSnapshot) error {
+	health := snap.Triage.ProjectHealth
+
+	om.Gauge("bv_snapshot_timestamp_seconds", "Unix time the published data was loaded.", float64(snap.LoadedAt.UnixNano())/1e9)
+	om.Gauge("bv_issues", "Issues in the project, all statuses.", float64(health.Counts.Total))
+	om.Gauge("bv_issues_open", "Issues that are not closed.", float64(health.Counts.Open))
+	om.Gauge("bv_issues_closed", "Closed issues.", float64(health.Counts.Closed))
+	om.Gauge("bv_issues_blocked", "Open issues that are not actionable.", float64(health.Counts.Blocked))
+	om.Gauge("bv_issues_actionable", "Open issues with no open blockers.", float64(health.Counts.Actionable))
+
+	om.Gauge("bv_graph_nodes", "Issues in the dependency graph.", float64(health.Graph.NodeCount))
+	om.Gauge("bv_graph_edges", "Blocking dependencies in the graph.", float64(health.Graph.EdgeCount))
+	om.Gauge("bv_graph_density", "Dependency graph density (edges / possible edges).", health.Graph.Density)
+	om.Gauge("bv_graph_cycles", "Dependency cycles detected.", float64(health.Graph.CycleCount))
+
+	if v := health.Velocity; v != nil {
+		om.Gauge("bv_velocity_closed_7d", "Issues closed in the last 7 days.", float64(v.ClosedLast7Days))
+		om.Gauge("bv_velocity_closed_30d", "Issues closed in the last 30 days.", float64(v.ClosedLast30Days))
+		om.Gauge("bv_velocity_avg_days_to_close", "Mean days from creation to close.", v.AvgDaysToClose)
+	}
+	if st := health.Staleness; st != nil {
+		om.Gauge("bv_issues_stale", fmt.Sprintf("Open issues with no activity for %d days.", st.ThresholdDays), float64(st.StaleCount))
+	}
+
+	labels := analysis.ComputeAllLabelHealth(snap.Issues, analysis.DefaultLabelHealthConfig(), time.Now().UTC(), snap.Stats)
+	om.Family("bv_label_health", "gauge", "", "Composite label health score (0-100).")
+	for _, lh := range labels.Labels {
+		om.Sample("bv_label_health", float64(lh.Health), metrics.Label{Name: "label", Value: lh.Label})
+	}
+	om.Family("bv_label_issues_open", "gauge", "", "Open issues carrying the label.")
+	for _, lh := range labels.Labels {
+		om.Sample("bv_label_issues_open", float64(lh.OpenCount), metrics.Label{Name: "label", Value: lh.Label})
+	}
+	om.Family("bv_label_issues_blocked", "gauge", "", "Issues with status blocked carrying the label.")
+	for _, lh := range labels.Labels {
+		om.Sample("bv_label_issues_blocked", float64(lh.Blocked), metrics.Label{Name: "label", Value: lh.Label})
+	}
+
+	cfg, err := drift.LoadConfig(s.projectDir)
+	if err != nil {
+		return fmt.Errorf("loading drift config: %w", err)
+	}
+	snap.analyzerMu.Lock()
+	result, _ := computeDriftAlerts(snap.Issues, snap.Analyzer, snap.Stats, cfg, baseline.DefaultPath(s.projectDir))
+	snap.analyzerMu.Unlock()
+	om.Family("bv_drift_alerts", "gauge", "", "Drift and proactive alerts, as reported by --robot-alerts.")
+	for _, sev := range []struct {
+		severity drift.Severity
+		count    int
+	}{
+		{drift.SeverityCritical, result.CriticalCount},
+		{drift.SeverityWarning, result.WarningCount},
+		{drift.SeverityInfo, result.InfoCount},
+	} {
+		om.Sample("bv_drift_alerts", float64(sev.count), metrics.Label{Name: "severity", Value: string(sev.severity)})
+	}
+	return nil
+}
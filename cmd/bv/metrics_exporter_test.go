@@ -0,0 +1,67 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/metrics"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestServeMetricsExposition(t *testing.T) {
+	var mu sync.Mutex
+	issues := []model.Issue{
+		{ID: "M-1", Title: "Root", Status: model.StatusOpen, Priority: 1, IssueType: model.TypeTask, Labels: []string{"api"}},
+		{ID: "M-2", Title: "Child", Status: model.StatusOpen, Priority: 2, IssueType: model.TypeTask, Labels: []string{"api"},
+			Dependencies: []*model.Dependency{{IssueID: "M-2", DependsOnID: "M-1", Type: model.DepBlocks}}},
+		{ID: "M-3", Title: "Done", Status: model.StatusClosed, Priority: 2, IssueType: model.TypeTask, Labels: []string{"ui"}},
+	}
+	ts := newTestServeServer(t, &issues, &mu)
+
+	resp, err := http.Get(ts.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != metrics.OpenMetricsContentType {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(body)
+
+	for _, want := range []string{
+		"bv_issues 3\n",
+		"bv_issues_open 2\n",
+		"bv_issues_closed 1\n",
+		"bv_issues_blocked 1\n",
+		"bv_issues_actionable 1\n",
+		"bv_graph_cycles 0\n",
+		"# TYPE bv_graph_density gauge\n",
+		"bv_velocity_closed_30d ",
+		`bv_label_health{label="api"} `,
+		`bv_label_issues_open{label="api"} 2` + "\n",
+		`bv_label_issues_blocked{label="api"} 0` + "\n",
+		`bv_drift_alerts{severity="critical"} `,
+		`bv_drift_alerts{severity="info"} `,
+		"bv_reloads_total 1\n",
+		"bv_reload_failing 0\n",
+		`bv_operation_duration_seconds_count{operation="triage_analysis"} `,
+		`bv_cache_hits_total{cache="graph_cache"} `,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("/metrics missing %q", want)
+		}
+	}
+	if !strings.HasSuffix(out, "# EOF\n") {
+		t.Errorf("/metrics must end with # EOF")
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/export"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/hooks"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/recipe"
+)
+
+// exportWithHooks runs write between the pre- and post-export hooks from
+// .bv/hooks.yaml, printing hook results and warnings. It returns the error
+// that stopped the export, if any.
+func exportWithHooks(path, format string, issueCount int, noHooks bool, write func() error) error {
+	cwd, _ := os.Getwd()
+	var executor *hooks.Executor
+	if !noHooks {
+		hookLoader := hooks.NewLoader(hooks.WithProjectDir(cwd))
+		if err := hookLoader.Load(); err != nil {
+			fmt.Printf("Warning: failed to load hooks: %v\n", err)
+		} else if hookLoader.HasHooks() {
+			ctx := hooks.ExportContext{
+				ExportPath:   path,
+				ExportFormat: format,
+				IssueCount:   issueCount,
+				Timestamp:    time.Now(),
+			}
+			executor = hooks.NewExecutor(hookLoader.Config(), ctx)
+
+			// Run pre-export hooks
+			if err := executor.RunPreExport(); err != nil {
+				return fmt.Errorf("pre-export hook failed: %w", err)
+			}
+		}
+	}
+
+	// Perform the export
+	if err := write(); err != nil {
+		return err
+	}
+
+	// Run post-export hooks
+	if executor != nil {
+		if err := executor.RunPostExport(); err != nil {
+			fmt.Printf("Warning: post-export hook failed: %v\n", err)
+			// Don't exit, just warn
+		}
+
+		// Print hook summary if any hooks ran
+		if len(executor.Results()) > 0 {
+			fmt.Println(executor.Summary())
+		}
+	}
+	return nil
+}
+
+// runRecipeExport handles --export: the recipe's filtered issues rendered
+// per its export block (format, include_graph, template). Without a recipe
+// every issue is exported with the default columns. path "-" is stdout.
+func runRecipeExport(issues []model.Issue, r *recipe.Recipe, path string, noHooks bool) error {
+	if r == nil {
+		r = &recipe.Recipe{Name: "all", Description: "All issues"}
+	}
+
+	format := r.Export.Format
+	if format == "md" {
+		format = export.ReportMarkdown
+	}
+	if format == "" {
+		format = export.ReportFormatForPath(path)
+	}
+	if r.Export.Template == "" && !export.ValidReportFormat(format) {
+		return fmt.Errorf("recipe %s: unknown export.format %q (expected markdown, json, csv or mermaid)", r.Name, r.Export.Format)
+	}
+	templatePath := ""
+	if r.Export.Template != "" {
+		templatePath = expandRecipePath(r.Export.Template)
+		format = "template"
+	}
+
+	// Scores come from the whole project so a filtered report keeps their
+	// global meaning; only the rendered set is narrowed.
+	analyzer := analysis.NewAnalyzer(issues)
+	stats := analyzer.Analyze()
+	triage := analysis.ComputeTriageFromAnalyzer(analyzer, &stats, issues, analysis.TriageOptions{WaitForPhase2: true}, time.Now())
+
+	selected := applyRecipeSort(applyRecipeFilters(issues, r), r)
+	if r.View.MaxItems > 0 && len(selected) > r.View.MaxItems {
+		selected = selected[:r.View.MaxItems]
+	}
+
+	data := export.ReportData{
+		Title:        "Recipe: " + r.Name,
+		Description:  r.Description,
+		GeneratedAt:  time.Now().UTC(),
+		DataHash:     analysis.ComputeDataHash(issues),
+		Issues:       selected,
+		Columns:      r.View.Columns,
+		Stats:        &stats,
+		Triage:       &triage,
+		OpenBlockers: make(map[string][]string),
+		IncludeGraph: r.Export.IncludeGraph,
+	}
+	for _, issue := range selected {
+		if blockers := analyzer.GetOpenBlockers(issue.ID); len(blockers) > 0 {
+			data.OpenBlockers[issue.ID] = blockers
+		}
+	}
+	if templatePath != "" || slices.Contains(data.Columns, "triage") {
+		data.TriageScores = make(map[string]float64)
+		for _, score := range analysis.ComputeTriageScores(issues) {
+			data.TriageScores[score.IssueID] = score.TriageScore
+		}
+	}
+
+	render := func() ([]byte, error) {
+		var buf bytes.Buffer
+		var err error
+		if templatePath != "" {
+			err = export.RenderTemplateReport(&buf, templatePath, data)
+		} else {
+			err = export.RenderReport(&buf, format, data)
+		}
+		return buf.Bytes(), err
+	}
+
+	if path == "-" {
+		out, err := render()
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(out)
+		return err
+	}
+
+	fmt.Printf("Exporting %d issues (%s) to %s...\n", len(selected), format, path)
+	err := exportWithHooks(path, format, len(selected), noHooks, func() error {
+		out, err := render()
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, out, 0644)
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Println("Done!")
+	return nil
+}
+
+// expandRecipePath resolves "~/" in template paths; relative paths are
+// taken from the working directory (the project root).
+func expandRecipePath(p string) string {
+	if rest, ok := strings.CutPrefix(p, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return p
+}
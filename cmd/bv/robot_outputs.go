@@ -0,0 +1,989 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/baseline"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/correlation"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/drift"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/flow"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/query"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/version"
+)
+
+// Robot payload builders shared by the one-shot --robot-* flags and the
+// long-running `bv serve` and `bv mcp` servers. Keeping the payload shapes in one place
+// guarantees both paths emit byte-identical field sets.
+
+// robotScope carries the optional time-travel and label-scope metadata that
+// decorates several robot payloads.
+type robotScope struct {
+	AsOf         string
+	AsOfCommit   string
+	Label        string
+	LabelContext *analysis.LabelHealth
+}
+
+// robotTriageOutput is the payload for --robot-triage (and its by-track/by-label variants).
+type robotTriageOutput struct {
+	GeneratedAt  string                 `json:"generated_at"`
+	DataHash     string                 `json:"data_hash"`
+	OutputFormat string                 `json:"output_format,omitempty"`
+	AsOf         string                 `json:"as_of,omitempty"`        // Historical snapshot ref (e.g., HEAD~30)
+	AsOfCommit   string                 `json:"as_of_commit,omitempty"` // Resolved commit SHA
+	Triage       analysis.TriageResult  `json:"triage"`
+	Feedback     *analysis.FeedbackJSON `json:"feedback,omitempty"` // bv-90: Feedback loop state
+	UsageHints   []string               `json:"usage_hints"`        // bv-84: Agent-friendly hints
+}
+
+func buildRobotTriageOutput(triage analysis.TriageResult, feedback *analysis.FeedbackJSON, dataHash string, scope robotScope) robotTriageOutput {
+	return robotTriageOutput{
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		DataHash:    dataHash,
+		AsOf:        scope.AsOf,
+		AsOfCommit:  scope.AsOfCommit,
+		Triage:      triage,
+		Feedback:    feedback,
+		UsageHints: []string{
+			"jq '.triage.quick_ref.top_picks[:3]' - Top 3 picks for immediate work",
+			"jq '.triage.recommendations[3:10] | map({id,title,score})' - Next candidates after top picks",
+			"jq '.triage.blockers_to_clear | map(.id)' - High-impact blockers to clear",
+			"jq '.triage.recommendations[] | select(.type == \"bug\")' - Bug-focused recommendations",
+			"jq '.triage.quick_ref.top_picks[] | select(.unblocks > 2)' - High-impact picks",
+			"jq '.triage.quick_wins' - Low-effort, high-impact items",
+			"--robot-next - Get only the single top recommendation",
+			"--robot-triage-by-track - Group by execution track for multi-agent coordination",
+			"--robot-triage-by-label - Group by label for area-focused agents",
+			"jq '.triage.recommendations_by_track[].top_pick' - Top pick per track",
+			"jq '.triage.recommendations_by_label[].claim_command' - Claim commands per label",
+			"jq '.feedback.weight_adjustments' - View feedback-adjusted weights (bv-90)",
+		},
+	}
+}
+
+// robotNextEmptyOutput is emitted by --robot-next when nothing is actionable.
+type robotNextEmptyOutput struct {
+	RobotEnvelope
+	AsOf       string `json:"as_of,omitempty"`
+	AsOfCommit string `json:"as_of_commit,omitempty"`
+	Message    string `json:"message"`
+}
+
+// robotNextOutput is the minimal single-pick payload for --robot-next.
+type robotNextOutput struct {
+	RobotEnvelope
+	AsOf       string   `json:"as_of,omitempty"`
+	AsOfCommit string   `json:"as_of_commit,omitempty"`
+	ID         string   `json:"id"`
+	Title      string   `json:"title"`
+	Score      float64  `json:"score"`
+	Reasons    []string `json:"reasons"`
+	Unblocks   int      `json:"unblocks"`
+	ClaimCmd   string   `json:"claim_command"`
+	ShowCmd    string   `json:"show_command"`
+}
+
+// buildRobotNextOutput returns either a robotNextOutput or, when there are no
+// top picks, a robotNextEmptyOutput.
+func buildRobotNextOutput(envelope RobotEnvelope, triage analysis.TriageResult, scope robotScope) any {
+	if len(triage.QuickRef.TopPicks) == 0 {
+		return robotNextEmptyOutput{
+			RobotEnvelope: envelope,
+			AsOf:          scope.AsOf,
+			AsOfCommit:    scope.AsOfCommit,
+			Message:       "No actionable items available",
+		}
+	}
+
+	top := triage.QuickRef.TopPicks[0]
+	return robotNextOutput{
+		RobotEnvelope: envelope,
+		AsOf:          scope.AsOf,
+		AsOfCommit:    scope.AsOfCommit,
+		ID:            top.ID,
+		Title:         top.Title,
+		Score:         top.Score,
+		Reasons:       top.Reasons,
+		Unblocks:      top.Unblocks,
+		ClaimCmd:      fmt.Sprintf("br update %s --status=in_progress", top.ID),
+		ShowCmd:       fmt.Sprintf("br show %s", top.ID),
+	}
+}
+
+// robotPlanOutput is the payload for --robot-plan.
+type robotPlanOutput struct {
+	GeneratedAt    string                  `json:"generated_at"`
+	DataHash       string                  `json:"data_hash"`
+	OutputFormat   string                  `json:"output_format,omitempty"`
+	AsOf           string                  `json:"as_of,omitempty"`        // Historical snapshot ref
+	AsOfCommit     string                  `json:"as_of_commit,omitempty"` // Resolved commit SHA
+	AnalysisConfig analysis.AnalysisConfig `json:"analysis_config"`
+	Status         analysis.MetricStatus   `json:"status"`
+	LabelScope     string                  `json:"label_scope,omitempty"`   // bv-122: Label filter applied
+	LabelContext   *analysis.LabelHealth   `json:"label_context,omitempty"` // bv-122: Health context for scoped label
+	Plan           analysis.ExecutionPlan  `json:"plan"`
+	UsageHints     []string                `json:"usage_hints"` // bv-84: Agent-friendly hints
+}
+
+// planAnalysisConfig returns the analysis config used by --robot-plan. Plans
+// only need Phase 1 metrics, so centrality metrics are skipped (with
+// deterministic skip reasons) unless full analysis is forced.
+func planAnalysisConfig(issues []model.Issue, forceFull bool) analysis.AnalysisConfig {
+	cfg := analysis.ConfigForSize(len(issues), countEdges(issues))
+	if forceFull {
+		return analysis.FullAnalysisConfig()
+	}
+	const skipReason = "not computed for --robot-plan"
+	cfg.ComputePageRank = false
+	cfg.PageRankSkipReason = skipReason
+	cfg.ComputeBetweenness = false
+	cfg.BetweennessMode = analysis.BetweennessSkip
+	cfg.BetweennessSkipReason = skipReason
+	cfg.ComputeHITS = false
+	cfg.HITSSkipReason = skipReason
+	cfg.ComputeEigenvector = false
+	cfg.ComputeCriticalPath = false
+	cfg.ComputeCycles = false
+	cfg.CyclesSkipReason = skipReason
+	return cfg
+}
+
+func buildRobotPlanOutput(plan analysis.ExecutionPlan, cfg analysis.AnalysisConfig, status analysis.MetricStatus, dataHash string, scope robotScope) robotPlanOutput {
+	return robotPlanOutput{
+		GeneratedAt:    time.Now().UTC().Format(time.RFC3339),
+		DataHash:       dataHash,
+		AsOf:           scope.AsOf,
+		AsOfCommit:     scope.AsOfCommit,
+		AnalysisConfig: cfg,
+		Status:         status,
+		LabelScope:     scope.Label,
+		LabelContext:   scope.LabelContext,
+		Plan:           plan,
+		UsageHints: []string{
+			"jq '.plan.tracks | length' - Number of parallel execution tracks",
+			"jq '.plan.tracks[0].items | map(.id)' - First track item IDs",
+			"jq '.plan.tracks[].items[] | select(.unblocks | length > 0)' - Items that unblock others",
+			"jq '.plan.summary' - High-level execution summary",
+			"jq '[.plan.tracks[].items[]] | length' - Total items across all tracks",
+		},
+	}
+}
+
+// computeRobotPlan runs the Phase 1 analysis needed for --robot-plan.
+func computeRobotPlan(issues []model.Issue, forceFull bool) (analysis.ExecutionPlan, analysis.AnalysisConfig, analysis.MetricStatus) {
+	analyzer := analysis.NewAnalyzer(issues)
+	cfg := planAnalysisConfig(issues, forceFull)
+	plan := analyzer.GetExecutionPlan()
+
+	stats := analyzer.AnalyzeAsyncWithConfig(context.Background(), cfg)
+	stats.WaitForPhase2()
+	return plan, cfg, stats.Status()
+}
+
+// robotInsightsFullStats holds the (capped) per-node metric maps for --robot-insights.
+type robotInsightsFullStats struct {
+	PageRank          map[string]float64 `json:"pagerank"`
+	Betweenness       map[string]float64 `json:"betweenness"`
+	Eigenvector       map[string]float64 `json:"eigenvector"`
+	Hubs              map[string]float64 `json:"hubs"`
+	Authorities       map[string]float64 `json:"authorities"`
+	CriticalPathScore map[string]float64 `json:"critical_path_score"`
+	CoreNumber        map[string]int     `json:"core_number"`
+	Slack             map[string]float64 `json:"slack"`
+	Articulation      []string           `json:"articulation_points"`
+}
+
+// robotInsightsOutput is the payload for --robot-insights.
+type robotInsightsOutput struct {
+	GeneratedAt    string                  `json:"generated_at"`
+	DataHash       string                  `json:"data_hash"`
+	OutputFormat   string                  `json:"output_format,omitempty"`
+	AsOf           string                  `json:"as_of,omitempty"`        // Historical snapshot ref
+	AsOfCommit     string                  `json:"as_of_commit,omitempty"` // Resolved commit SHA
+	AnalysisConfig analysis.AnalysisConfig `json:"analysis_config"`
+	Status         analysis.MetricStatus   `json:"status"`
+	LabelScope     string                  `json:"label_scope,omitempty"`   // bv-122: Label filter applied
+	LabelContext   *analysis.LabelHealth   `json:"label_context,omitempty"` // bv-122: Health context for scoped label
+	analysis.Insights
+	FullStats        interface{}                `json:"full_stats"`
+	TopWhatIfs       []analysis.WhatIfEntry     `json:"top_what_ifs,omitempty"`      // Issues with highest downstream impact (bv-83)
+	AdvancedInsights *analysis.AdvancedInsights `json:"advanced_insights,omitempty"` // bv-181: Canonical advanced features
+	UsageHints       []string                   `json:"usage_hints"`                 // bv-84: Agent-friendly hints
+}
+
+// buildRobotInsightsOutput assembles --robot-insights from an analyzer whose
+// stats have already been computed.
+func buildRobotInsightsOutput(analyzer *analysis.Analyzer, stats *analysis.GraphStats, issues []model.Issue, dataHash string, scope robotScope) robotInsightsOutput {
+	// Generate top 50 lists for summary, but full stats are included in the struct
+	insights := stats.GenerateInsights(50)
+	insights.MustPass = analysis.ComputeDominators(issues).MustPassItems(50)
+
+	// Add project-level velocity snapshot (using dedicated helper for efficiency)
+	if v := analysis.ComputeProjectVelocity(issues, time.Now(), 8); v != nil {
+		snap := &analysis.VelocitySnapshot{
+			Closed7:   v.ClosedLast7Days,
+			Closed30:  v.ClosedLast30Days,
+			AvgDays:   v.AvgDaysToClose,
+			Estimated: v.Estimated,
+		}
+		if len(v.Weekly) > 0 {
+			snap.Weekly = make([]int, len(v.Weekly))
+			for i := range v.Weekly {
+				snap.Weekly[i] = v.Weekly[i].Closed
+			}
+		}
+		insights.Velocity = snap
+	}
+
+	// Default cap to keep payload small; allow override via env
+	mapLimit := 200
+	if v := os.Getenv("BV_INSIGHTS_MAP_LIMIT"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			mapLimit = n
+		}
+	}
+
+	fullStats := robotInsightsFullStats{
+		PageRank:          limitFloatMap(stats.PageRank(), mapLimit),
+		Betweenness:       limitFloatMap(stats.Betweenness(), mapLimit),
+		Eigenvector:       limitFloatMap(stats.Eigenvector(), mapLimit),
+		Hubs:              limitFloatMap(stats.Hubs(), mapLimit),
+		Authorities:       limitFloatMap(stats.Authorities(), mapLimit),
+		CriticalPathScore: limitFloatMap(stats.CriticalPathScore(), mapLimit),
+		CoreNumber:        limitIntMap(stats.CoreNumber(), mapLimit),
+		Slack:             limitFloatMap(stats.Slack(), mapLimit),
+		Articulation:      limitStrings(stats.ArticulationPoints(), mapLimit),
+	}
+
+	return robotInsightsOutput{
+		GeneratedAt:    time.Now().UTC().Format(time.RFC3339),
+		DataHash:       dataHash,
+		AsOf:           scope.AsOf,
+		AsOfCommit:     scope.AsOfCommit,
+		AnalysisConfig: stats.Config,
+		Status:         stats.Status(),
+		LabelScope:     scope.Label,
+		LabelContext:   scope.LabelContext,
+		Insights:       insights,
+		FullStats:      fullStats,
+		// Get top what-if deltas for issues with highest downstream impact (bv-83)
+		TopWhatIfs: analyzer.TopWhatIfDeltas(10),
+		// Generate advanced insights with canonical structure (bv-181)
+		AdvancedInsights: analyzer.GenerateAdvancedInsights(analysis.DefaultAdvancedInsightsConfig()),
+		UsageHints: []string{
+			"jq '.Bottlenecks[:5] | map(.ID)' - Top 5 bottleneck IDs",
+			"jq '.CriticalPath[:3]' - Top 3 critical path items",
+			"jq '.top_what_ifs[] | select(.delta.direct_unblocks > 2)' - High-impact items",
+			"jq '.full_stats.pagerank | to_entries | sort_by(-.value)[:5]' - Top PageRank",
+			"jq '.full_stats.core_number | to_entries | sort_by(-.value)[:5]' - Strongly embedded nodes (k-core)",
+			"jq '.full_stats.articulation_points' - Structural cut points",
+			"jq '.Slack[:5]' - Nodes with slack (good parallel work candidates)",
+			"jq '.Cycles | length' - Count of detected cycles",
+			"jq '.advanced_insights.cycle_break' - Cycle break suggestions (bv-181)",
+			"BV_INSIGHTS_MAP_LIMIT=50 bv --robot-insights - Reduce map sizes",
+		},
+	}
+}
+
+// robotBlockerChainOutput is the payload for --robot-blocker-chain.
+type robotBlockerChainOutput struct {
+	RobotEnvelope
+	Result *analysis.BlockerChainResult `json:"result"`
+}
+
+// robotImpactOutput is the payload for --robot-impact.
+type robotImpactOutput struct {
+	RobotEnvelope
+	Files         []string                   `json:"files"`
+	RiskLevel     string                     `json:"risk_level"`
+	RiskScore     float64                    `json:"risk_score"`
+	Summary       string                     `json:"summary"`
+	Warnings      []string                   `json:"warnings"`
+	AffectedBeads []correlation.AffectedBead `json:"affected_beads"`
+}
+
+// buildRobotImpactOutput runs a file impact analysis against a history report.
+func buildRobotImpactOutput(report *correlation.HistoryReport, files []string) robotImpactOutput {
+	cleaned := make([]string, 0, len(files))
+	for _, f := range files {
+		if f = strings.TrimSpace(f); f != "" {
+			cleaned = append(cleaned, f)
+		}
+	}
+	impact := correlation.NewFileLookup(report).ImpactAnalysis(cleaned)
+	return robotImpactOutput{
+		RobotEnvelope: NewRobotEnvelope(report.DataHash),
+		Files:         impact.Files,
+		RiskLevel:     impact.RiskLevel,
+		RiskScore:     impact.RiskScore,
+		Summary:       impact.Summary,
+		Warnings:      impact.Warnings,
+		AffectedBeads: impact.AffectedBeads,
+	}
+}
+
+// robotRelatedWorkOutput is the payload for --robot-related.
+type robotRelatedWorkOutput struct {
+	*correlation.RelatedWorkResult
+	DataHash     string `json:"data_hash"`
+	OutputFormat string `json:"output_format,omitempty"`
+	Version      string `json:"version,omitempty"`
+}
+
+// relatedWorkRequest holds the --related-* knobs.
+type relatedWorkRequest struct {
+	BeadID        string
+	MinRelevance  int
+	MaxResults    int
+	IncludeClosed bool
+}
+
+// buildRobotRelatedOutput finds work related to a bead. It returns an error
+// when the bead is not present in the history report.
+func buildRobotRelatedOutput(report *correlation.HistoryReport, issues []model.Issue, req relatedWorkRequest) (robotRelatedWorkOutput, error) {
+	// Build dependency graph from issues
+	depGraph := make(map[string][]string)
+	for _, issue := range issues {
+		for _, dep := range issue.Dependencies {
+			depGraph[issue.ID] = append(depGraph[issue.ID], dep.DependsOnID)
+		}
+	}
+
+	result := report.FindRelatedWork(req.BeadID, correlation.RelatedWorkOptions{
+		MinRelevance:      req.MinRelevance,
+		MaxResults:        req.MaxResults,
+		ConcurrencyWindow: 7 * 24 * time.Hour,
+		IncludeClosed:     req.IncludeClosed,
+		DependencyGraph:   depGraph,
+	})
+	if result == nil {
+		return robotRelatedWorkOutput{}, fmt.Errorf("bead not found in history: %s", req.BeadID)
+	}
+	return robotRelatedWorkOutput{
+		RelatedWorkResult: result,
+		DataHash:          report.DataHash,
+		OutputFormat:      robotOutputFormat,
+		Version:           version.Version,
+	}, nil
+}
+
+// robotForecastSummary aggregates a multi-issue --robot-forecast.
+type robotForecastSummary struct {
+	TotalMinutes  int       `json:"total_minutes"`
+	TotalDays     float64   `json:"total_days"`
+	AvgConfidence float64   `json:"avg_confidence"`
+	EarliestETA   time.Time `json:"earliest_eta"`
+	LatestETA     time.Time `json:"latest_eta"`
+}
+
+// robotForecastOutput is the payload for --robot-forecast.
+type robotForecastOutput struct {
+	RobotEnvelope
+	Agents        int                    `json:"agents"`
+	Filters       map[string]string      `json:"filters,omitempty"`
+	ForecastCount int                    `json:"forecast_count"`
+	Forecasts     []analysis.ETAEstimate `json:"forecasts"`
+	Summary       *robotForecastSummary  `json:"summary,omitempty"`
+}
+
+// forecastRequest holds the --robot-forecast target and --forecast-* filters.
+// Target is an issue ID or "all".
+type forecastRequest struct {
+	Target string
+	Label  string
+	Sprint string
+	Agents int
+
+	// Percentiles switches to Monte Carlo simulation (--percentiles).
+	Percentiles []int
+	Runs        int
+	History     *correlation.HistoryReport // Claim→close cycle times, optional
+}
+
+// buildRobotForecastOutput estimates ETAs for one issue or every open issue
+// matching the label/sprint filters. sprints is only consulted when
+// req.Sprint is set.
+func buildRobotForecastOutput(issues []model.Issue, stats *analysis.GraphStats, sprints []model.Sprint, req forecastRequest, now time.Time) (robotForecastOutput, error) {
+	var sprintBeadIDs map[string]bool
+	if req.Sprint != "" {
+		for _, s := range sprints {
+			if s.ID == req.Sprint {
+				sprintBeadIDs = make(map[string]bool)
+				for _, bid := range s.BeadIDs {
+					sprintBeadIDs[bid] = true
+				}
+				break
+			}
+		}
+		if sprintBeadIDs == nil {
+			return robotForecastOutput{}, fmt.Errorf("sprint not found: %s", req.Sprint)
+		}
+	}
+
+	// Filter issues by label and sprint if specified
+	targetIssues := make([]model.Issue, 0, len(issues))
+	for _, iss := range issues {
+		if req.Label != "" {
+			hasLabel := false
+			for _, l := range iss.Labels {
+				if l == req.Label {
+					hasLabel = true
+					break
+				}
+			}
+			if !hasLabel {
+				continue
+			}
+		}
+		if sprintBeadIDs != nil && !sprintBeadIDs[iss.ID] {
+			continue
+		}
+		targetIssues = append(targetIssues, iss)
+	}
+
+	agents := req.Agents
+	if agents <= 0 {
+		agents = 1
+	}
+
+	var forecasts []analysis.ETAEstimate
+	if req.Target == "all" {
+		// Forecast all open issues
+		for _, iss := range targetIssues {
+			if iss.Status == model.StatusClosed {
+				continue
+			}
+			eta, err := analysis.EstimateETAForIssue(issues, stats, iss.ID, agents, now)
+			if err != nil {
+				continue
+			}
+			forecasts = append(forecasts, eta)
+		}
+	} else {
+		eta, err := analysis.EstimateETAForIssue(issues, stats, req.Target, agents, now)
+		if err != nil {
+			return robotForecastOutput{}, err
+		}
+		forecasts = append(forecasts, eta)
+	}
+
+	// Build summary if multiple forecasts
+	var summary *robotForecastSummary
+	if len(forecasts) > 1 {
+		totalMin := 0
+		totalConf := 0.0
+		earliest := forecasts[0].ETADate
+		latest := forecasts[0].ETADate
+		for _, f := range forecasts {
+			totalMin += f.EstimatedMinutes
+			totalConf += f.Confidence
+			if f.ETADate.Before(earliest) {
+				earliest = f.ETADate
+			}
+			if f.ETADate.After(latest) {
+				latest = f.ETADate
+			}
+		}
+		summary = &robotForecastSummary{
+			TotalMinutes:  totalMin,
+			TotalDays:     float64(totalMin) / (60.0 * 8.0), // 8hr workday
+			AvgConfidence: totalConf / float64(len(forecasts)),
+			EarliestETA:   earliest,
+			LatestETA:     latest,
+		}
+	}
+
+	output := robotForecastOutput{
+		RobotEnvelope: NewRobotEnvelope(analysis.ComputeDataHash(issues)),
+		Agents:        agents,
+		ForecastCount: len(forecasts),
+		Forecasts:     forecasts,
+		Summary:       summary,
+	}
+	filters := make(map[string]string)
+	if req.Label != "" {
+		filters["label"] = req.Label
+	}
+	if req.Sprint != "" {
+		filters["sprint"] = req.Sprint
+	}
+	if len(filters) > 0 {
+		output.Filters = filters
+	}
+	return output, nil
+}
+
+// robotMonteCarloForecastOutput is the payload for --robot-forecast with
+// --percentiles.
+type robotMonteCarloForecastOutput struct {
+	RobotEnvelope
+	Method  string            `json:"method"` // "monte_carlo"
+	Target  string            `json:"target"`
+	Filters map[string]string `json:"filters,omitempty"`
+	*analysis.MonteCarloForecast
+}
+
+// parsePercentiles parses a --percentiles list such as "50,85,95".
+func parsePercentiles(s string) ([]int, error) {
+	var out []int
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(part), "%"))
+		part = strings.TrimPrefix(strings.TrimPrefix(part, "p"), "P")
+		if part == "" {
+			continue
+		}
+		p, err := strconv.Atoi(part)
+		if err != nil || p < 1 || p > 100 {
+			return nil, fmt.Errorf("invalid percentile %q (expected integers 1-100, e.g. 50,85,95)", part)
+		}
+		out = append(out, p)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no percentiles in %q", s)
+	}
+	sort.Ints(out)
+	return out, nil
+}
+
+// buildRobotMonteCarloForecastOutput simulates completion of the open
+// backlog and reports percentile dates for the target issue(s), their
+// epics, labels and sprints. The seed comes from the data hash, so the same
+// data always yields the same forecast.
+func buildRobotMonteCarloForecastOutput(issues []model.Issue, stats *analysis.GraphStats, sprints []model.Sprint, req forecastRequest, now time.Time) (robotMonteCarloForecastOutput, error) {
+	scope := make(map[string]bool)
+	var sprintBeadIDs map[string]bool
+	if req.Sprint != "" {
+		for _, s := range sprints {
+			if s.ID == req.Sprint {
+				sprintBeadIDs = make(map[string]bool)
+				for _, bid := range s.BeadIDs {
+					sprintBeadIDs[bid] = true
+				}
+			}
+		}
+		if sprintBeadIDs == nil {
+			return robotMonteCarloForecastOutput{}, fmt.Errorf("sprint not found: %s", req.Sprint)
+		}
+	}
+	found := false
+	for _, iss := range issues {
+		if req.Target != "all" && iss.ID != req.Target {
+			continue
+		}
+		found = true
+		if req.Label != "" && !slices.Contains(iss.Labels, req.Label) {
+			continue
+		}
+		if sprintBeadIDs != nil && !sprintBeadIDs[iss.ID] {
+			continue
+		}
+		scope[iss.ID] = true
+	}
+	if !found {
+		return robotMonteCarloForecastOutput{}, fmt.Errorf("issue %q not found", req.Target)
+	}
+
+	dataHash := analysis.ComputeDataHash(issues)
+	seed := fnv.New64a()
+	seed.Write([]byte(dataHash))
+	mc, err := analysis.ForecastMonteCarlo(issues, stats, analysis.MonteCarloOptions{
+		Agents:      req.Agents,
+		Runs:        req.Runs,
+		Percentiles: req.Percentiles,
+		Seed:        int64(seed.Sum64() >> 1),
+		CycleTimes:  historyCycleTimes(req.History),
+		StartedAt:   historyStartTimes(req.History),
+		Scope:       scope,
+		Sprints:     sprints,
+	}, now)
+	if err != nil {
+		return robotMonteCarloForecastOutput{}, err
+	}
+
+	output := robotMonteCarloForecastOutput{
+		RobotEnvelope:      NewRobotEnvelope(dataHash),
+		Method:             "monte_carlo",
+		Target:             req.Target,
+		MonteCarloForecast: mc,
+	}
+	filters := make(map[string]string)
+	if req.Label != "" {
+		filters["label"] = req.Label
+	}
+	if req.Sprint != "" {
+		filters["sprint"] = req.Sprint
+	}
+	if len(filters) > 0 {
+		output.Filters = filters
+	}
+	return output, nil
+}
+
+// historyCycleTimes extracts claim→close cycle times from git history.
+func historyCycleTimes(report *correlation.HistoryReport) map[string]time.Duration {
+	if report == nil {
+		return nil
+	}
+	out := make(map[string]time.Duration)
+	for id, h := range report.Histories {
+		if h.CycleTime != nil && h.CycleTime.ClaimToClose != nil && *h.CycleTime.ClaimToClose > 0 {
+			out[id] = *h.CycleTime.ClaimToClose
+		}
+	}
+	return out
+}
+
+// historyStartTimes extracts when each bead was last claimed from git
+// history.
+func historyStartTimes(report *correlation.HistoryReport) map[string]time.Time {
+	if report == nil {
+		return nil
+	}
+	out := make(map[string]time.Time)
+	for id, h := range report.Histories {
+		for _, e := range h.Events {
+			if e.EventType == correlation.EventClaimed && e.Timestamp.After(out[id]) {
+				out[id] = e.Timestamp
+			}
+		}
+	}
+	return out
+}
+
+// robotScheduleOutput is the payload for --robot-schedule.
+type robotScheduleOutput struct {
+	RobotEnvelope
+	Roster string `json:"roster"` // Roster file used, or "derived"
+	analysis.Schedule
+}
+
+// scheduleRoster resolves the roster for --robot-schedule: an explicit
+// path must exist; otherwise .bv/roster.yaml is used when present, and the
+// roster is derived from assignees plus agents when it is not.
+func scheduleRoster(projectDir, path string, issues []model.Issue, agents int) (analysis.Roster, string, error) {
+	explicit := path != ""
+	if !explicit {
+		path = analysis.RosterPath(projectDir)
+	} else if _, err := os.Stat(path); err != nil {
+		return analysis.Roster{}, "", fmt.Errorf("roster: %w", err)
+	}
+	roster, err := analysis.LoadRoster(path)
+	if err != nil {
+		return roster, "", err
+	}
+	if len(roster.Workers) == 0 {
+		if explicit {
+			return roster, "", fmt.Errorf("roster %s lists no workers", path)
+		}
+		return analysis.DefaultRoster(issues, agents), "derived", nil
+	}
+	return roster, path, nil
+}
+
+func buildRobotScheduleOutput(issues []model.Issue, roster analysis.Roster, source string, now time.Time) robotScheduleOutput {
+	return robotScheduleOutput{
+		RobotEnvelope: NewRobotEnvelope(analysis.ComputeDataHash(issues)),
+		Roster:        source,
+		Schedule:      analysis.BuildSchedule(issues, roster, analysis.ScheduleOptions{Start: now}),
+	}
+}
+
+// robotEpicsOutput is the payload for --robot-epics.
+type robotEpicsOutput struct {
+	RobotEnvelope
+	analysis.RollupReport
+}
+
+func buildRobotEpicsOutput(issues []model.Issue) robotEpicsOutput {
+	return robotEpicsOutput{
+		RobotEnvelope: NewRobotEnvelope(analysis.ComputeDataHash(issues)),
+		RollupReport:  analysis.ComputeEpicRollups(issues),
+	}
+}
+
+// robotWhatIfOutput is the payload for --robot-whatif.
+type robotWhatIfOutput struct {
+	RobotEnvelope
+	analysis.ScenarioReport
+}
+
+func buildRobotWhatIfOutput(issues []model.Issue, scenarios analysis.ScenarioFile, roster analysis.Roster, now time.Time) (robotWhatIfOutput, error) {
+	report, err := analysis.SimulateScenarios(issues, scenarios, analysis.ScenarioOptions{Roster: roster, Now: now})
+	if err != nil {
+		return robotWhatIfOutput{}, err
+	}
+	return robotWhatIfOutput{
+		RobotEnvelope:  NewRobotEnvelope(analysis.ComputeDataHash(issues)),
+		ScenarioReport: report,
+	}, nil
+}
+
+// robotCommunitiesOutput is the payload for --robot-communities.
+type robotCommunitiesOutput struct {
+	RobotEnvelope
+	analysis.CommunityReport
+}
+
+func buildRobotCommunitiesOutput(issues []model.Issue, opts analysis.CommunityOptions) robotCommunitiesOutput {
+	return robotCommunitiesOutput{
+		RobotEnvelope:   NewRobotEnvelope(analysis.ComputeDataHash(issues)),
+		CommunityReport: analysis.DetectCommunities(issues, opts),
+	}
+}
+
+// robotDominatorsOutput is the payload for --robot-dominators.
+type robotDominatorsOutput struct {
+	RobotEnvelope
+	*analysis.DominatorAnalysis
+	Dominators []analysis.GoalDominators `json:"dominators"`
+}
+
+// buildRobotDominatorsOutput reports the chokepoints for one open issue, or
+// for every detected goal when target is "all".
+func buildRobotDominatorsOutput(issues []model.Issue, target string) (robotDominatorsOutput, error) {
+	dom := analysis.ComputeDominators(issues)
+	out := robotDominatorsOutput{
+		RobotEnvelope:     NewRobotEnvelope(analysis.ComputeDataHash(issues)),
+		DominatorAnalysis: dom,
+		Dominators:        []analysis.GoalDominators{},
+	}
+	if target == "all" {
+		for _, id := range dom.Goals {
+			g, _ := dom.ForGoal(id)
+			out.Dominators = append(out.Dominators, g)
+		}
+		return out, nil
+	}
+	g, ok := dom.ForGoal(target)
+	if !ok {
+		return robotDominatorsOutput{}, fmt.Errorf("issue %q not found or already closed", target)
+	}
+	out.Dominators = append(out.Dominators, g)
+	return out, nil
+}
+
+// robotQueryIssue is one issue matched by --robot-query.
+type robotQueryIssue struct {
+	ID       string       `json:"id"`
+	Title    string       `json:"title"`
+	Status   model.Status `json:"status"`
+	Priority int          `json:"priority"`
+	Type     string       `json:"type"`
+	Assignee string       `json:"assignee,omitempty"`
+	Labels   []string     `json:"labels,omitempty"`
+}
+
+// robotQueryOutput is the payload for --robot-query.
+type robotQueryOutput struct {
+	RobotEnvelope
+	Query  string            `json:"query"`
+	Parsed string            `json:"parsed"` // Canonical form with explicit grouping
+	Fields []string          `json:"fields"`
+	Count  int               `json:"count"`
+	Issues []robotQueryIssue `json:"issues"`
+}
+
+func buildRobotQueryOutput(issues []model.Issue, src string, now time.Time) (robotQueryOutput, error) {
+	q, err := query.Parse(src)
+	if err != nil {
+		return robotQueryOutput{}, err
+	}
+	ctx := query.NewContext(issues, nil)
+	ctx.Now = now
+	out := robotQueryOutput{
+		RobotEnvelope: NewRobotEnvelope(analysis.ComputeDataHash(issues)),
+		Query:         src,
+		Parsed:        q.String(),
+		Fields:        q.Fields(),
+		Issues:        []robotQueryIssue{},
+	}
+	for _, iss := range q.Filter(issues, ctx) {
+		out.Issues = append(out.Issues, robotQueryIssue{
+			ID:       iss.ID,
+			Title:    iss.Title,
+			Status:   iss.Status,
+			Priority: iss.Priority,
+			Type:     string(iss.IssueType),
+			Assignee: iss.Assignee,
+			Labels:   iss.Labels,
+		})
+	}
+	out.Count = len(out.Issues)
+	return out, nil
+}
+
+// robotFlowOutput is the payload for --robot-flow.
+type robotFlowOutput struct {
+	RobotEnvelope
+	flow.Report
+}
+
+// flowEvents reads bead status transitions from git history, analyzing at
+// most limit commits (0 = unlimited). Errors are swallowed: flow metrics
+// fall back to issue timestamps outside a git checkout.
+func flowEvents(projectDir, beadsDir string, limit int) []correlation.BeadEvent {
+	if correlation.ValidateRepository(projectDir) != nil {
+		return nil
+	}
+	beadsPath, err := loader.FindJSONLPath(beadsDir)
+	if err != nil {
+		return nil
+	}
+	events, err := correlation.NewExtractor(projectDir, beadsPath).Extract(correlation.ExtractOptions{Limit: limit})
+	if err != nil {
+		return nil
+	}
+	return events
+}
+
+func buildRobotFlowOutput(issues []model.Issue, events []correlation.BeadEvent, days int, now time.Time) robotFlowOutput {
+	return robotFlowOutput{
+		RobotEnvelope: NewRobotEnvelope(analysis.ComputeDataHash(issues)),
+		Report:        flow.Compute(issues, events, flow.Options{Now: now, Days: days}),
+	}
+}
+
+// computeDriftAlerts runs drift detection for --robot-alerts and
+// --robot-watch. Without a saved baseline, stats drift is suppressed by
+// comparing the current stats to themselves while cycle, staleness and
+// cascade alerts still fire. A baseline that fails to load is reported in the
+// error alongside the baseline-less result.
+func computeDriftAlerts(issues []model.Issue, analyzer *analysis.Analyzer, stats *analysis.GraphStats, cfg *drift.Config, baselinePath string) (*drift.Result, error) {
+	openCount, closedCount, blockedCount := 0, 0, 0
+	for _, issue := range issues {
+		switch issue.Status {
+		case model.StatusClosed:
+			closedCount++
+		case model.StatusBlocked:
+			blockedCount++
+		case model.StatusOpen, model.StatusInProgress:
+			openCount++
+		default:
+			// Ignore tombstones and any unknown statuses for summary counts.
+		}
+	}
+	cycles := stats.Cycles()
+	curStats := baseline.GraphStats{
+		NodeCount:       stats.NodeCount,
+		EdgeCount:       stats.EdgeCount,
+		Density:         stats.Density,
+		OpenCount:       openCount,
+		ClosedCount:     closedCount,
+		BlockedCount:    blockedCount,
+		CycleCount:      len(cycles),
+		ActionableCount: len(analyzer.GetActionableIssues()),
+	}
+
+	bl := &baseline.Baseline{Stats: curStats}
+	cur := &baseline.Baseline{Stats: curStats, Cycles: cycles}
+
+	// If a baseline exists, compare against it for real drift deltas.
+	var loadErr error
+	if baselinePath != "" && baseline.Exists(baselinePath) {
+		loaded, err := baseline.Load(baselinePath)
+		if err != nil {
+			loadErr = err
+		} else {
+			bl = loaded
+			topMetrics := baseline.TopMetrics{
+				PageRank:     buildMetricItems(stats.PageRank(), 10),
+				Betweenness:  buildMetricItems(stats.Betweenness(), 10),
+				CriticalPath: buildMetricItems(stats.CriticalPathScore(), 10),
+				Hubs:         buildMetricItems(stats.Hubs(), 10),
+				Authorities:  buildMetricItems(stats.Authorities(), 10),
+			}
+			cur = &baseline.Baseline{Stats: curStats, TopMetrics: topMetrics, Cycles: cycles}
+		}
+	}
+
+	calc := drift.NewCalculator(bl, cur, cfg)
+	calc.SetIssues(issues)
+	return calc.Calculate(), loadErr
+}
+
+// suggestAllConfig builds the --robot-suggest config from the --suggest-* flags.
+func suggestAllConfig(suggestType string, minConfidence float64, bead string) (analysis.SuggestAllConfig, error) {
+	config := analysis.DefaultSuggestAllConfig()
+	config.MinConfidence = minConfidence
+	config.FilterBead = bead
+
+	switch suggestType {
+	case "duplicate", "duplicates":
+		config.FilterType = analysis.SuggestionPotentialDuplicate
+	case "dependency", "dependencies":
+		config.FilterType = analysis.SuggestionMissingDependency
+	case "label", "labels":
+		config.FilterType = analysis.SuggestionLabelSuggestion
+	case "cycle", "cycles":
+		config.FilterType = analysis.SuggestionCycleWarning
+	case "inversion", "inversions":
+		config.FilterType = analysis.SuggestionPriorityInversion
+	case "redundant", "redundancy":
+		config.FilterType = analysis.SuggestionRedundantDependency
+	case "":
+		// All types
+	default:
+		return config, fmt.Errorf("invalid suggest-type: %s (use: duplicate, dependency, label, cycle, inversion, redundant)", suggestType)
+	}
+	return config, nil
+}
+
+// limitFloatMap keeps the top-N entries of a metric map (ties broken by key).
+func limitFloatMap(m map[string]float64, limit int) map[string]float64 {
+	if limit <= 0 || limit >= len(m) {
+		return m
+	}
+	type kv struct {
+		k string
+		v float64
+	}
+	items := make([]kv, 0, len(m))
+	for k, v := range m {
+		items = append(items, kv{k, v})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].v == items[j].v {
+			return items[i].k < items[j].k
+		}
+		return items[i].v > items[j].v
+	})
+	trim := make(map[string]float64, limit)
+	for i := 0; i < limit; i++ {
+		trim[items[i].k] = items[i].v
+	}
+	return trim
+}
+
+func limitIntMap(m map[string]int, limit int) map[string]int {
+	if limit <= 0 || len(m) <= limit {
+		return m
+	}
+	trim := make(map[string]int, limit)
+	count := 0
+	for k, v := range m {
+		trim[k] = v
+		count++
+		if count >= limit {
+			break
+		}
+	}
+	return trim
+}
+
+func limitStrings(s []string, limit int) []string {
+	if limit <= 0 || len(s) <= limit {
+		return s
+	}
+	return s[:limit]
+}
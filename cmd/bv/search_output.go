@@ -1,13 +1,18 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"regexp"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/Dicklesworthstone/beads_viewer/pkg/cass"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/correlation"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/search"
 )
 
@@ -17,6 +22,9 @@ type robotSearchResult struct {
 	TextScore       float64            `json:"text_score,omitempty"`
 	Title           string             `json:"title,omitempty"`
 	ComponentScores map[string]float64 `json:"component_scores,omitempty"`
+	Kind            search.DocKind     `json:"kind,omitempty"`
+	Match           *search.SearchHit  `json:"match,omitempty"`
+	Matches         []search.SearchHit `json:"matches,omitempty"`
 }
 
 type robotSearchOutput struct {
@@ -28,28 +36,258 @@ type robotSearchOutput struct {
 	Dim         int                   `json:"dim"`
 	IndexPath   string                `json:"index_path"`
 	Index       search.IndexSyncStats `json:"index"`
-	Loaded      bool                  `json:"loaded"`
-	Limit       int                   `json:"limit"`
-	Mode        search.SearchMode     `json:"mode"`
-	Preset      search.PresetName     `json:"preset,omitempty"`
-	Weights     *search.Weights       `json:"weights,omitempty"`
-	Results     []robotSearchResult   `json:"results"`
-	UsageHints  []string              `json:"usage_hints,omitempty"`
+	// OutputFormat is only set by bv serve, which picks it per request.
+	OutputFormat string `json:"output_format,omitempty"`
+	// Lexical reports the BM25 index sync in fusion mode.
+	Lexical    *search.IndexSyncStats `json:"lexical_index,omitempty"`
+	ANN        bool                   `json:"ann,omitempty"`
+	Loaded     bool                   `json:"loaded"`
+	Limit      int                    `json:"limit"`
+	Mode       search.SearchMode      `json:"mode"`
+	Preset     search.PresetName      `json:"preset,omitempty"`
+	Weights    *search.Weights        `json:"weights,omitempty"`
+	Kinds      []search.DocKind       `json:"kinds,omitempty"`
+	KindIndex  kindIndexStats         `json:"kind_indexes,omitempty"`
+	Grouped    bool                   `json:"grouped,omitempty"`
+	Warnings   []string               `json:"warnings,omitempty"`
+	Results    []robotSearchResult    `json:"results"`
+	UsageHints []string               `json:"usage_hints,omitempty"`
 }
 
+// kindIndexStats reports per-kind index syncs in a unified search.
+type kindIndexStats map[search.DocKind]search.IndexSyncStats
+
 func writeRobotSearchOutput(w io.Writer, out robotSearchOutput) error {
 	enc := json.NewEncoder(w)
 	enc.SetIndent("", "  ")
 	return enc.Encode(out)
 }
 
-func applySearchConfigOverrides(cfg search.SearchConfig, modeFlag, presetFlag, weightsFlag string) (search.SearchConfig, error) {
+// semanticSearchRequest describes a single semantic search run.
+type semanticSearchRequest struct {
+	Query      string
+	Limit      int
+	Config     search.SearchConfig
+	Embedding  search.EmbeddingConfig
+	ProjectDir string
+	// Metrics optionally supplies a pre-warmed metrics cache for hybrid mode.
+	// When nil, metrics are computed from the issues on demand.
+	Metrics search.MetricsCache
+	// Progress, when non-nil, receives a note before a fresh index is built.
+	Progress io.Writer
+	// Group returns one result per bead with all of its matches instead of
+	// one result per matching document.
+	Group bool
+	// History and Sessions supply commit and cass session documents when
+	// Config.Kinds asks for them. Either may be nil.
+	History  func() (*correlation.HistoryReport, error)
+	Sessions func(ctx context.Context, query string) []cass.SearchResult
+}
+
+// runSemanticSearch syncs the on-disk vector index with issues, runs the
+// query and returns the robot payload. The second return value is the index
+// size after sync.
+func runSemanticSearch(ctx context.Context, issues []model.Issue, dataHash string, req semanticSearchRequest) (robotSearchOutput, int, error) {
+	embedder, err := search.NewEmbedderFromConfig(req.Embedding)
+	if err != nil {
+		return robotSearchOutput{}, 0, err
+	}
+
+	indexPath := search.DefaultIndexPath(req.ProjectDir, req.Embedding)
+	idx, loaded, err := search.LoadOrNewVectorIndex(indexPath, embedder.Dim())
+	if err != nil {
+		return robotSearchOutput{}, 0, err
+	}
+
+	annBuilt := idx.SetHNSWConfig(search.HNSWConfigFromEnv())
+
+	docs := search.DocumentsFromIssues(issues)
+	if req.Progress != nil && !loaded {
+		fmt.Fprintf(req.Progress, "Building semantic index (%d issues)...\n", len(docs))
+	}
+
+	syncStats, err := search.SyncVectorIndex(ctx, idx, embedder, docs, 64)
+	if err != nil {
+		return robotSearchOutput{}, 0, fmt.Errorf("building semantic index: %w", err)
+	}
+	if !loaded || syncStats.Changed() || annBuilt {
+		if err := idx.Save(indexPath); err != nil {
+			return robotSearchOutput{}, 0, fmt.Errorf("saving semantic index: %w", err)
+		}
+	}
+
+	qvecs, err := embedder.Embed(ctx, []string{req.Query})
+	if err != nil || len(qvecs) != 1 {
+		if err == nil {
+			err = fmt.Errorf("embedder returned %d vectors for query", len(qvecs))
+		}
+		return robotSearchOutput{}, 0, fmt.Errorf("embedding query: %w", err)
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+	fetchLimit := limit
+	if req.Config.Mode.UsesMetrics() {
+		fetchLimit = search.HybridCandidateLimit(limit, len(issues), req.Query)
+	}
+
+	out := robotSearchOutput{
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		DataHash:    dataHash,
+		Query:       req.Query,
+		Provider:    req.Embedding.Provider,
+		Model:       search.EmbedderModel(embedder),
+		Dim:         embedder.Dim(),
+		IndexPath:   indexPath,
+		Index:       syncStats,
+		ANN:         idx.UsesANN(),
+		Loaded:      loaded,
+		Limit:       limit,
+		Mode:        req.Config.Mode,
+	}
+	if !search.IsIssueOnly(req.Config.Kinds) {
+		out, err = runUnifiedSearch(ctx, issues, req, out, embedder, idx, qvecs[0], fetchLimit)
+		if err != nil {
+			return robotSearchOutput{}, 0, err
+		}
+		return out, idx.Size(), nil
+	}
+
+	results, err := idx.SearchTopK(qvecs[0], fetchLimit)
+	if err != nil {
+		return robotSearchOutput{}, 0, fmt.Errorf("searching index: %w", err)
+	}
+
+	if req.Config.Mode == search.SearchModeFusion {
+		lexical, stats, err := syncLexicalIndex(ctx, issues, req.ProjectDir)
+		if err != nil {
+			return robotSearchOutput{}, 0, err
+		}
+		out.Lexical = &stats
+		results = search.FuseRRF(search.DefaultRRFK, lexical.Search(req.Query, fetchLimit), results)
+		if len(results) > fetchLimit {
+			results = results[:fetchLimit]
+		}
+	} else {
+		results = search.ApplyShortQueryLexicalBoost(results, req.Query, docs)
+	}
+	if isLikelyIssueID(req.Query) {
+		results = promoteExactSearchResult(req.Query, results)
+	}
+
+	titleByID := issueTitles(issues)
+
+	if !req.Config.Mode.UsesMetrics() {
+		if len(results) > limit {
+			results = results[:limit]
+		}
+		out.Results = make([]robotSearchResult, 0, len(results))
+		for _, r := range results {
+			out.Results = append(out.Results, robotSearchResult{
+				IssueID: r.IssueID,
+				Score:   r.Score,
+				Title:   titleByID[r.IssueID],
+			})
+		}
+		out.UsageHints = []string{
+			"jq '.results[] | {id: .issue_id, score: .score, title: .title}' - Extract results",
+			"jq '.index' - Index update stats (added/updated/removed/embedded)",
+		}
+		return out, idx.Size(), nil
+	}
+
+	scorer, err := newSearchScorer(issues, req, &out)
+	if err != nil {
+		return robotSearchOutput{}, 0, err
+	}
+	hybridResults, err := buildHybridScores(results, scorer)
+	if err != nil {
+		return robotSearchOutput{}, 0, fmt.Errorf("scoring hybrid results: %w", err)
+	}
+	if isLikelyIssueID(req.Query) {
+		hybridResults = promoteExactHybridResult(req.Query, hybridResults)
+	}
+	if len(hybridResults) > limit {
+		hybridResults = hybridResults[:limit]
+	}
+
+	out.Results = make([]robotSearchResult, 0, len(hybridResults))
+	for _, r := range hybridResults {
+		out.Results = append(out.Results, robotSearchResult{
+			IssueID:         r.IssueID,
+			Score:           r.FinalScore,
+			TextScore:       r.TextScore,
+			Title:           titleByID[r.IssueID],
+			ComponentScores: r.ComponentScores,
+		})
+	}
+	out.UsageHints = []string{
+		"jq '.results[] | {id: .issue_id, score: .score, text: .text_score}' - Extract scores",
+		"jq '.results[] | {id: .issue_id, components: .component_scores}' - Hybrid breakdown",
+		"jq '.index' - Index update stats (added/updated/removed/embedded)",
+	}
+	return out, idx.Size(), nil
+}
+
+// newSearchScorer resolves the hybrid weights for req, records them in out
+// and returns a scorer backed by req.Metrics or freshly computed metrics.
+func newSearchScorer(issues []model.Issue, req semanticSearchRequest, out *robotSearchOutput) (search.HybridScorer, error) {
+	weights, presetName, err := resolveSearchWeights(req.Config)
+	if err != nil {
+		return nil, err
+	}
+	weights = weights.Normalize()
+	weights = search.AdjustWeightsForQuery(weights, req.Query)
+	out.Preset = presetName
+	out.Weights = &weights
+
+	cache := req.Metrics
+	if cache == nil {
+		cache = search.NewMetricsCache(search.NewAnalyzerMetricsLoader(issues))
+		if err := cache.Refresh(); err != nil {
+			return nil, fmt.Errorf("computing hybrid metrics: %w", err)
+		}
+	}
+	return search.NewHybridScorer(weights, cache), nil
+}
+
+func issueTitles(issues []model.Issue) map[string]string {
+	titleByID := make(map[string]string, len(issues))
+	for _, iss := range issues {
+		titleByID[iss.ID] = iss.Title
+	}
+	return titleByID
+}
+
+// syncLexicalIndex brings the on-disk BM25 index up to date with issues,
+// saving it only when something changed.
+func syncLexicalIndex(ctx context.Context, issues []model.Issue, projectDir string) (*search.LexicalIndex, search.IndexSyncStats, error) {
+	path := search.DefaultLexicalIndexPath(projectDir)
+	idx, loaded, err := search.LoadOrNewLexicalIndex(path)
+	if err != nil {
+		return nil, search.IndexSyncStats{}, err
+	}
+	stats, err := search.SyncLexicalIndex(ctx, idx, search.LexicalDocumentsFromIssues(issues))
+	if err != nil {
+		return nil, stats, fmt.Errorf("building lexical index: %w", err)
+	}
+	if !loaded || stats.Changed() {
+		if err := idx.Save(path); err != nil {
+			return nil, stats, fmt.Errorf("saving lexical index: %w", err)
+		}
+	}
+	return idx, stats, nil
+}
+
+func applySearchConfigOverrides(cfg search.SearchConfig, modeFlag, presetFlag, weightsFlag, kindsFlag string) (search.SearchConfig, error) {
 	if modeFlag != "" {
 		switch search.SearchMode(strings.ToLower(modeFlag)) {
-		case search.SearchModeText, search.SearchModeHybrid:
+		case search.SearchModeText, search.SearchModeHybrid, search.SearchModeFusion:
 			cfg.Mode = search.SearchMode(strings.ToLower(modeFlag))
 		default:
-			return search.SearchConfig{}, fmt.Errorf("invalid --search-mode: %q (expected text|hybrid)", modeFlag)
+			return search.SearchConfig{}, fmt.Errorf("invalid --search-mode: %q (expected text|hybrid|fusion)", modeFlag)
 		}
 	}
 
@@ -70,6 +308,14 @@ func applySearchConfigOverrides(cfg search.SearchConfig, modeFlag, presetFlag, w
 		cfg.HasWeights = true
 	}
 
+	if kindsFlag != "" {
+		kinds, err := search.ParseDocKinds(kindsFlag)
+		if err != nil {
+			return search.SearchConfig{}, fmt.Errorf("invalid --search-kinds: %w", err)
+		}
+		cfg.Kinds = kinds
+	}
+
 	return cfg, nil
 }
 
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/correlation"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/search"
+)
+
+// runUnifiedSearch ranks issues, comments, correlated commits and cass
+// session snippets together. out arrives with the issue index already
+// synced; every hit links back to the bead that owns it.
+func runUnifiedSearch(ctx context.Context, issues []model.Issue, req semanticSearchRequest, out robotSearchOutput, embedder search.Embedder, issueIdx *search.VectorIndex, qvec []float32, fetchLimit int) (robotSearchOutput, error) {
+	out.Kinds = req.Config.Kinds
+	out.Grouped = req.Group
+
+	docs := make(map[string]search.SearchDocument)
+	var extras []search.SearchDocument
+	var rankings [][]search.SearchResult
+	for _, kind := range req.Config.Kinds {
+		var kindDocs []search.SearchDocument
+		var results []search.SearchResult
+		var err error
+		switch kind {
+		case search.KindIssue:
+			kindDocs = search.IssueSearchDocuments(issues)
+			results, err = issueIdx.SearchTopK(qvec, fetchLimit)
+		case search.KindComment:
+			kindDocs = search.CommentDocuments(issues)
+			results, err = searchKindIndex(ctx, req, &out, embedder, kind, kindDocs, qvec, fetchLimit)
+		case search.KindCommit:
+			report, warning := loadSearchHistory(req)
+			if warning != "" {
+				out.Warnings = append(out.Warnings, warning)
+				continue
+			}
+			kindDocs = search.CommitDocuments(report)
+			results, err = searchKindIndex(ctx, req, &out, embedder, kind, kindDocs, qvec, fetchLimit)
+		case search.KindSession:
+			if req.Sessions == nil {
+				out.Warnings = append(out.Warnings, "session search skipped: cass is not available here")
+				continue
+			}
+			known := make(map[string]bool, len(issues))
+			for _, iss := range issues {
+				known[iss.ID] = true
+			}
+			kindDocs = search.SessionDocuments(req.Sessions(ctx, req.Query), known)
+			results, err = search.ScoreDocuments(ctx, embedder, qvec, kindDocs)
+		}
+		if err != nil {
+			return robotSearchOutput{}, fmt.Errorf("searching %s documents: %w", kind, err)
+		}
+		for _, d := range kindDocs {
+			docs[d.Key] = d
+			if d.Kind != search.KindIssue {
+				extras = append(extras, d)
+			}
+		}
+		rankings = append(rankings, results)
+	}
+
+	results := search.MergeRankings(rankings...)
+	if req.Config.Mode == search.SearchModeFusion {
+		lexical, stats, err := syncLexicalIndex(ctx, issues, req.ProjectDir)
+		if err != nil {
+			return robotSearchOutput{}, err
+		}
+		out.Lexical = &stats
+		if err := search.AddLexicalDocuments(lexical, extras); err != nil {
+			return robotSearchOutput{}, fmt.Errorf("indexing documents for fusion: %w", err)
+		}
+		var lexicalResults []search.SearchResult
+		for _, r := range lexical.Search(req.Query, fetchLimit*len(req.Config.Kinds)) {
+			if _, ok := docs[r.IssueID]; ok {
+				lexicalResults = append(lexicalResults, r)
+			}
+		}
+		results = search.FuseRRF(search.DefaultRRFK, lexicalResults, results)
+	} else {
+		texts := make(map[string]string, len(docs))
+		for key, d := range docs {
+			texts[key] = d.Text
+		}
+		results = search.ApplyShortQueryLexicalBoost(results, req.Query, texts)
+	}
+	if isLikelyIssueID(req.Query) {
+		results = promoteExactSearchResult(req.Query, results)
+	}
+
+	// Issues keep zero-score results like the issue-only search does; other
+	// kinds only count when they actually match.
+	hits := make([]search.SearchHit, 0, len(results))
+	for _, r := range results {
+		d, ok := docs[r.IssueID]
+		if !ok || (d.Kind != search.KindIssue && r.Score <= 0) {
+			continue
+		}
+		hits = append(hits, search.NewSearchHit(d, r.Score))
+	}
+
+	var scorer search.HybridScorer
+	if req.Config.Mode.UsesMetrics() {
+		var err error
+		if scorer, err = newSearchScorer(issues, req, &out); err != nil {
+			return robotSearchOutput{}, err
+		}
+	}
+	titleByID := issueTitles(issues)
+	score := func(beadID string, textScore float64) (robotSearchResult, error) {
+		r := robotSearchResult{IssueID: beadID, Score: textScore, Title: titleByID[beadID]}
+		if scorer == nil {
+			return r, nil
+		}
+		scored, err := scorer.Score(beadID, textScore)
+		if err != nil {
+			return r, fmt.Errorf("scoring hybrid results: %w", err)
+		}
+		r.Score = scored.FinalScore
+		r.TextScore = scored.TextScore
+		r.ComponentScores = scored.ComponentScores
+		return r, nil
+	}
+
+	if req.Group {
+		for _, g := range search.GroupHitsByBead(hits) {
+			r, err := score(g.BeadID, g.Score)
+			if err != nil {
+				return robotSearchOutput{}, err
+			}
+			r.Kind = g.Hits[0].Kind
+			r.Matches = g.Hits
+			out.Results = append(out.Results, r)
+		}
+	} else {
+		for i := range hits {
+			r, err := score(hits[i].BeadID, hits[i].Score)
+			if err != nil {
+				return robotSearchOutput{}, err
+			}
+			r.Kind = hits[i].Kind
+			r.Match = &hits[i]
+			out.Results = append(out.Results, r)
+		}
+	}
+	if scorer != nil {
+		sort.SliceStable(out.Results, func(i, j int) bool {
+			return out.Results[i].Score > out.Results[j].Score
+		})
+	}
+	if isLikelyIssueID(req.Query) {
+		out.Results = promoteExactRobotResult(req.Query, out.Results)
+	}
+	if len(out.Results) > out.Limit {
+		out.Results = out.Results[:out.Limit]
+	}
+	if out.Results == nil {
+		out.Results = []robotSearchResult{}
+	}
+
+	out.UsageHints = []string{
+		"jq '.results[] | {id: .issue_id, kind: .kind, score: .score, snippet: .match.snippet}' - Extract matches",
+		"jq '[.results[] | select(.kind == \"commit\")]' - Keep one document kind",
+		"jq '.kind_indexes' - Per-kind index update stats",
+	}
+	if req.Group {
+		out.UsageHints[0] = "jq '.results[] | {id: .issue_id, score: .score, matches: [.matches[] | .kind]}' - Matches per bead"
+	}
+	return out, nil
+}
+
+// searchKindIndex syncs the persisted index for one document kind, records
+// its sync stats in out and returns the documents nearest to qvec.
+func searchKindIndex(ctx context.Context, req semanticSearchRequest, out *robotSearchOutput, embedder search.Embedder, kind search.DocKind, docs []search.SearchDocument, qvec []float32, k int) ([]search.SearchResult, error) {
+	idx, stats, err := search.SyncKindIndex(ctx, req.ProjectDir, req.Embedding, embedder, kind, docs)
+	if err != nil {
+		return nil, err
+	}
+	if out.KindIndex == nil {
+		out.KindIndex = make(kindIndexStats)
+	}
+	out.KindIndex[kind] = stats
+	return idx.SearchTopK(qvec, k)
+}
+
+// loadSearchHistory returns the commit correlation report for commit
+// search, or a warning explaining why commits were skipped.
+func loadSearchHistory(req semanticSearchRequest) (*correlation.HistoryReport, string) {
+	if req.History == nil {
+		return nil, "commit search skipped: commit history is not available here"
+	}
+	report, err := req.History()
+	if err != nil {
+		return nil, "commit search skipped: " + strings.TrimSpace(err.Error())
+	}
+	return report, ""
+}
+
+// promoteExactRobotResult moves the result owned by the queried issue ID to
+// the front, preferring its issue document over other matches.
+func promoteExactRobotResult(query string, results []robotSearchResult) []robotSearchResult {
+	needle := strings.TrimSpace(query)
+	best := -1
+	for i := range results {
+		if !strings.EqualFold(results[i].IssueID, needle) {
+			continue
+		}
+		if best < 0 || (results[i].Kind == search.KindIssue && results[best].Kind != search.KindIssue) {
+			best = i
+		}
+	}
+	if best <= 0 {
+		return results
+	}
+	match := results[best]
+	copy(results[1:best+1], results[0:best])
+	results[0] = match
+	return results
+}
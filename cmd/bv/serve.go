@@ -0,0 +1,820 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/Dicklesworthstone/beads_viewer/internal/datasource"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/correlation"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/export"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/search"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/watcher"
+)
+
+// `bv serve` keeps issues, graph metrics and the correlation history warm in
+// memory and answers robot queries over a local HTTP/JSON API. Payloads are
+// the same as the matching --robot-* flags; append ?format=toon (or send
+// Accept: text/toon) to receive TOON instead of JSON.
+
+const defaultServeAddr = "127.0.0.1:7777"
+
+// serveOptions configures the serve daemon.
+type serveOptions struct {
+	Addr         string
+	Socket       string
+	Format       string
+	HistoryLimit int
+	NoWatch      bool
+	NoHistory    bool
+	Quiet        bool
+}
+
+// serveSnapshot is an immutable view of the project at one load. Handlers
+// read it under the server's read lock; reloads swap in a new snapshot.
+type serveSnapshot struct {
+	Issues       []model.Issue
+	IssueMap     map[string]model.Issue
+	DataHash     string
+	Analyzer     *analysis.Analyzer
+	Stats        *analysis.GraphStats
+	Triage       analysis.TriageResult
+	History      *correlation.HistoryReport
+	Feedback     *analysis.FeedbackJSON
+	LoadedAt     time.Time
+	LoadDuration time.Duration
+
+	// analyzerMu serializes on-demand analyzer queries (blocker chains,
+	// what-ifs) that are not documented as safe for concurrent use.
+	analyzerMu sync.Mutex
+
+	insightsOnce sync.Once
+	insights     robotInsightsOutput
+
+	metricsOnce sync.Once
+	metrics     search.MetricsCache
+	metricsErr  error
+}
+
+// serveServer holds the warm state and the HTTP handlers.
+type serveServer struct {
+	opts       serveOptions
+	projectDir string
+	beadsDir   string
+	load       func() ([]model.Issue, error)
+
+	mu      sync.RWMutex
+	snap    *serveSnapshot
+	reloads int
+	lastErr error
+
+	// searchMu serializes semantic searches, which share the on-disk index.
+	searchMu sync.Mutex
+
+	// logPrefix tags watcher log lines ("bv serve", "bv mcp").
+	logPrefix string
+	// onReload, if set, is called after the watcher swaps in a new snapshot.
+	onReload func(*serveSnapshot)
+	// onReloadError, if set, is called when a watcher-triggered reload fails.
+	onReloadError func(error)
+}
+
+// runServe is the entry point for `bv serve`.
+func runServe(args []string) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	opts := serveOptions{}
+	fs.StringVar(&opts.Addr, "addr", defaultServeAddr, "TCP listen address")
+	fs.StringVar(&opts.Socket, "socket", "", "Listen on a Unix domain socket instead of TCP")
+	fs.StringVar(&opts.Format, "format", "", "Default response format: json or toon (env: BV_OUTPUT_FORMAT, TOON_DEFAULT_FORMAT)")
+	fs.IntVar(&opts.HistoryLimit, "history-limit", 500, "Max commits to analyze for correlation history (0 = unlimited)")
+	fs.BoolVar(&opts.NoWatch, "no-watch", false, "Do not reload when .beads changes")
+	fs.BoolVar(&opts.NoHistory, "no-history", false, "Skip git history correlation (disables /history and staleness)")
+	fs.BoolVar(&opts.Quiet, "quiet", false, "Suppress reload log lines on stderr")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: bv serve [options]")
+		fmt.Fprintln(os.Stderr, "\nServe robot commands over a local HTTP/JSON API with warm analysis state.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	opts.Format = resolveRobotOutputFormat(opts.Format)
+	if opts.Format != "json" && opts.Format != "toon" {
+		fmt.Fprintf(os.Stderr, "Invalid --format %q (expected json|toon)\n", opts.Format)
+		return 2
+	}
+	// Keep loaders quiet: stdout/stderr noise is not useful for a daemon.
+	_ = os.Setenv("BV_ROBOT", "1")
+
+	projectDir, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting working directory: %v\n", err)
+		return 1
+	}
+	beadsDir, err := loader.GetBeadsDir("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting beads directory: %v\n", err)
+		return 1
+	}
+
+	srv := newServeServer(opts, projectDir, beadsDir, func() ([]model.Issue, error) {
+		return datasource.LoadIssues("")
+	})
+	if err := srv.reload(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading beads: %v\n", err)
+		fmt.Fprintln(os.Stderr, "Make sure you are in a project initialized with 'bd init'.")
+		return 1
+	}
+
+	if !opts.NoWatch {
+		stop := srv.watch()
+		defer stop()
+	}
+	return srv.listenAndServe(srv.handler())
+}
+
+// listenAndServe serves handler on the configured address or socket until
+// SIGINT/SIGTERM and returns the process exit code.
+func (s *serveServer) listenAndServe(handler http.Handler) int {
+	listener, where, err := serveListen(s.opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	httpServer := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
+	snap := s.snapshot()
+	fmt.Fprintf(os.Stderr, "%s: %d issues loaded in %s; listening on %s\n", s.logPrefix, len(snap.Issues), formatDuration(snap.LoadDuration), where)
+
+	if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if s.opts.Socket != "" {
+		_ = os.Remove(s.opts.Socket)
+	}
+	return 0
+}
+
+// serveListen opens the TCP or Unix-socket listener described by opts.
+func serveListen(opts serveOptions) (net.Listener, string, error) {
+	if opts.Socket == "" {
+		l, err := net.Listen("tcp", opts.Addr)
+		if err != nil {
+			return nil, "", fmt.Errorf("listen on %s: %w", opts.Addr, err)
+		}
+		return l, "http://" + l.Addr().String(), nil
+	}
+
+	// A stale socket from a crashed daemon would make Listen fail; only
+	// remove it when nothing answers on it.
+	if _, err := os.Stat(opts.Socket); err == nil {
+		if conn, dialErr := net.DialTimeout("unix", opts.Socket, 200*time.Millisecond); dialErr == nil {
+			conn.Close()
+			return nil, "", fmt.Errorf("socket %s is already in use", opts.Socket)
+		}
+		_ = os.Remove(opts.Socket)
+	}
+	l, err := net.Listen("unix", opts.Socket)
+	if err != nil {
+		return nil, "", fmt.Errorf("listen on %s: %w", opts.Socket, err)
+	}
+	// Sockets inherit the umask; restrict to the owner on shared boxes.
+	_ = os.Chmod(opts.Socket, 0o600)
+	return l, "unix:" + opts.Socket, nil
+}
+
+func newServeServer(opts serveOptions, projectDir, beadsDir string, load func() ([]model.Issue, error)) *serveServer {
+	if opts.Format == "" {
+		opts.Format = "json"
+	}
+	return &serveServer{
+		opts:       opts,
+		projectDir: projectDir,
+		beadsDir:   beadsDir,
+		load:       load,
+		logPrefix:  "bv serve",
+	}
+}
+
+// snapshot returns the current snapshot (never nil after a successful reload).
+func (s *serveServer) snapshot() *serveSnapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.snap
+}
+
+// reload loads issues and recomputes the warm analysis state. On failure the
+// previous snapshot is kept so clients keep getting answers.
+func (s *serveServer) reload() error {
+	start := time.Now()
+	issues, err := s.load()
+	if err != nil {
+		s.mu.Lock()
+		s.lastErr = err
+		s.mu.Unlock()
+		return err
+	}
+
+	snap := s.buildSnapshot(issues)
+	snap.LoadedAt = time.Now().UTC()
+	snap.LoadDuration = time.Since(start)
+
+	s.mu.Lock()
+	s.snap = snap
+	s.reloads++
+	s.lastErr = nil
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *serveServer) buildSnapshot(issues []model.Issue) *serveSnapshot {
+	snap := &serveSnapshot{
+		Issues:   issues,
+		IssueMap: make(map[string]model.Issue, len(issues)),
+		DataHash: analysis.ComputeDataHash(issues),
+	}
+	for _, iss := range issues {
+		snap.IssueMap[iss.ID] = iss
+	}
+
+	snap.Analyzer = analysis.NewAnalyzer(issues)
+	snap.Stats = snap.Analyzer.AnalyzeAsync(context.Background())
+	snap.Stats.WaitForPhase2()
+
+	if !s.opts.NoHistory {
+		snap.History = s.loadHistory(issues)
+	}
+
+	snap.Triage = analysis.ComputeTriageFromAnalyzer(snap.Analyzer, snap.Stats, issues, analysis.TriageOptions{
+		WaitForPhase2: true,
+		History:       snap.History,
+	}, time.Now())
+
+	if s.beadsDir != "" {
+		if feedbackData, err := analysis.LoadFeedback(s.beadsDir); err == nil && len(feedbackData.Events) > 0 {
+			info := feedbackData.ToJSON()
+			snap.Feedback = &info
+		}
+	}
+	return snap
+}
+
+// loadHistory generates the bead/commit correlation report.
+func (s *serveServer) loadHistory(issues []model.Issue) *correlation.HistoryReport {
+	return generateHistoryReport(s.projectDir, s.beadsDir, issues, s.opts.HistoryLimit)
+}
+
+// generateHistoryReport correlates beads with git history, analyzing at
+// most limit commits (0 = unlimited). Errors are swallowed: history is
+// optional and the project may not be a git checkout.
+func generateHistoryReport(projectDir, beadsDir string, issues []model.Issue, limit int) *correlation.HistoryReport {
+	if projectDir == "" || beadsDir == "" {
+		return nil
+	}
+	if correlation.ValidateRepository(projectDir) != nil {
+		return nil
+	}
+	beadsPath, err := loader.FindJSONLPath(beadsDir)
+	if err != nil {
+		return nil
+	}
+	beadInfos := make([]correlation.BeadInfo, len(issues))
+	for i, issue := range issues {
+		beadInfos[i] = correlation.BeadInfo{
+			ID:     issue.ID,
+			Title:  issue.Title,
+			Status: string(issue.Status),
+		}
+	}
+	correlator := correlation.NewCorrelator(projectDir, beadsPath)
+	report, err := correlator.GenerateReport(beadInfos, correlation.CorrelatorOptions{Limit: limit})
+	if err != nil {
+		return nil
+	}
+	return report
+}
+
+// watch starts file watchers on the beads sources and reloads on change.
+// The returned func stops all watchers.
+func (s *serveServer) watch() func() {
+	var paths []string
+	if jsonlPath, err := loader.FindJSONLPath(s.beadsDir); err == nil {
+		paths = append(paths, jsonlPath)
+	}
+	if dbPath := filepath.Join(s.beadsDir, "beads.db"); fileExists(dbPath) {
+		paths = append(paths, dbPath)
+	}
+
+	reloadCh := make(chan struct{}, 1)
+	var watchers []*watcher.Watcher
+	for _, path := range paths {
+		w, err := watcher.NewWatcher(path,
+			watcher.WithDebounceDuration(500*time.Millisecond),
+			watcher.WithOnChange(func() {
+				select {
+				case reloadCh <- struct{}{}:
+				default:
+				}
+			}),
+			watcher.WithOnError(func(err error) {
+				if !s.opts.Quiet {
+					fmt.Fprintf(os.Stderr, "%s: watcher error: %v\n", s.logPrefix, err)
+				}
+			}),
+		)
+		if err != nil {
+			continue
+		}
+		if err := w.Start(); err != nil {
+			continue
+		}
+		watchers = append(watchers, w)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-reloadCh:
+				if err := s.reload(); err != nil {
+					if !s.opts.Quiet {
+						fmt.Fprintf(os.Stderr, "%s: reload failed (keeping previous data): %v\n", s.logPrefix, err)
+					}
+					if s.onReloadError != nil {
+						s.onReloadError(err)
+					}
+					continue
+				}
+				snap := s.snapshot()
+				if !s.opts.Quiet {
+					fmt.Fprintf(os.Stderr, "%s: reloaded %d issues in %s (hash %s)\n", s.logPrefix, len(snap.Issues), formatDuration(snap.LoadDuration), snap.DataHash)
+				}
+				if s.onReload != nil {
+					s.onReload(snap)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		for _, w := range watchers {
+			w.Stop()
+		}
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// handler builds the HTTP routes.
+func (s *serveServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", s.handleHealth)
+	mux.HandleFunc("POST /reload", s.handleReload)
+	mux.HandleFunc("GET /triage", s.handleTriage)
+	mux.HandleFunc("GET /next", s.handleNext)
+	mux.HandleFunc("GET /insights", s.handleInsights)
+	mux.HandleFunc("GET /plan", s.handlePlan)
+	mux.HandleFunc("GET /graph", s.handleGraph)
+	mux.HandleFunc("GET /search", s.handleSearch)
+	mux.HandleFunc("GET /history", s.handleHistory)
+	mux.HandleFunc("GET /history/{id}", s.handleHistory)
+	mux.HandleFunc("GET /blocker-chain/{id}", s.handleBlockerChain)
+	mux.HandleFunc("GET /label-health", s.handleLabelHealth)
+	mux.HandleFunc("GET /metrics", s.handleMetrics)
+	mux.HandleFunc("GET /issues/{id}", s.handleIssue)
+	mux.HandleFunc("GET /{$}", s.handleIndex)
+	return mux
+}
+
+// serveEndpoints documents the routes for GET /.
+var serveEndpoints = []string{
+	"GET /health - daemon status, data hash, reload count",
+	"POST /reload - force a reload from disk",
+	"GET /triage[?group=track|label] - same as --robot-triage",
+	"GET /next - same as --robot-next",
+	"GET /insights - same as --robot-insights",
+	"GET /plan - same as --robot-plan",
+	"GET /graph[?format=json|dot|mermaid&label=&root=&depth=&reduced=] - same as --robot-graph",
+	"GET /search?q=QUERY[&limit=&mode=text|hybrid|fusion&preset=&kinds=] - same as --robot-search",
+	"GET /history[/{id}] - same as --robot-history / --bead-history",
+	"GET /blocker-chain/{id} - same as --robot-blocker-chain",
+	"GET /label-health - same as --robot-label-health",
+	"GET /metrics - OpenMetrics/Prometheus exposition of project health and bv timings",
+	"GET /issues/{id} - raw issue record",
+}
+
+// responseFormat picks json or toon from the query string, Accept header,
+// or the daemon default. The graph endpoint reuses ?format for its own
+// purpose, so ?output= is accepted as an alias everywhere.
+func (s *serveServer) responseFormat(r *http.Request) string {
+	for _, key := range []string{"output", "format"} {
+		switch strings.ToLower(r.URL.Query().Get(key)) {
+		case "json":
+			return "json"
+		case "toon":
+			return "toon"
+		}
+	}
+	if strings.Contains(r.Header.Get("Accept"), "toon") {
+		return "toon"
+	}
+	return s.opts.Format
+}
+
+func (s *serveServer) envelope(r *http.Request, dataHash string) RobotEnvelope {
+	env := NewRobotEnvelope(dataHash)
+	env.OutputFormat = s.responseFormat(r)
+	return env
+}
+
+func (s *serveServer) writePayload(w http.ResponseWriter, r *http.Request, status int, v any) {
+	format := s.responseFormat(r)
+	if format == "toon" {
+		w.Header().Set("Content-Type", "text/toon; charset=utf-8")
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(status)
+	if err := newRobotEncoderForFormat(w, format).Encode(v); err != nil && !s.opts.Quiet {
+		fmt.Fprintf(os.Stderr, "bv serve: encoding %s: %v\n", r.URL.Path, err)
+	}
+}
+
+type serveError struct {
+	Error string `json:"error"`
+}
+
+func (s *serveServer) writeError(w http.ResponseWriter, r *http.Request, status int, msg string) {
+	s.writePayload(w, r, status, serveError{Error: msg})
+}
+
+func (s *serveServer) handleIndex(w http.ResponseWriter, r *http.Request) {
+	snap := s.snapshot()
+	s.writePayload(w, r, http.StatusOK, struct {
+		RobotEnvelope
+		Endpoints []string `json:"endpoints"`
+	}{
+		RobotEnvelope: s.envelope(r, snap.DataHash),
+		Endpoints:     serveEndpoints,
+	})
+}
+
+type serveHealth struct {
+	RobotEnvelope
+	Status       string `json:"status"`
+	IssueCount   int    `json:"issue_count"`
+	LoadedAt     string `json:"loaded_at"`
+	LoadMs       int64  `json:"load_ms"`
+	Reloads      int    `json:"reloads"`
+	Phase2Ready  bool   `json:"phase2_ready"`
+	HasHistory   bool   `json:"has_history"`
+	LastError    string `json:"last_error,omitempty"`
+	WatchEnabled bool   `json:"watch_enabled"`
+}
+
+func (s *serveServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	snap, reloads, lastErr := s.snap, s.reloads, s.lastErr
+	s.mu.RUnlock()
+
+	out := serveHealth{
+		RobotEnvelope: s.envelope(r, snap.DataHash),
+		Status:        "ok",
+		IssueCount:    len(snap.Issues),
+		LoadedAt:      snap.LoadedAt.Format(time.RFC3339),
+		LoadMs:        snap.LoadDuration.Milliseconds(),
+		Reloads:       reloads,
+		Phase2Ready:   snap.Stats.IsPhase2Ready(),
+		HasHistory:    snap.History != nil,
+		WatchEnabled:  !s.opts.NoWatch,
+	}
+	if lastErr != nil {
+		out.Status = "stale"
+		out.LastError = lastErr.Error()
+	}
+	s.writePayload(w, r, http.StatusOK, out)
+}
+
+func (s *serveServer) handleReload(w http.ResponseWriter, r *http.Request) {
+	if err := s.reload(); err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, fmt.Sprintf("reload failed: %v", err))
+		return
+	}
+	s.handleHealth(w, r)
+}
+
+func (s *serveServer) handleTriage(w http.ResponseWriter, r *http.Request) {
+	snap := s.snapshot()
+	triage, err := snap.triage(r.URL.Query().Get("group"))
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	out := buildRobotTriageOutput(triage, snap.Feedback, snap.DataHash, robotScope{})
+	out.OutputFormat = s.responseFormat(r)
+	s.writePayload(w, r, http.StatusOK, out)
+}
+
+// triage returns the cached triage, or recomputes it grouped by "track" or
+// "label" when group is set.
+func (snap *serveSnapshot) triage(group string) (analysis.TriageResult, error) {
+	switch group {
+	case "":
+		return snap.Triage, nil
+	case "track", "label":
+		snap.analyzerMu.Lock()
+		defer snap.analyzerMu.Unlock()
+		return analysis.ComputeTriageFromAnalyzer(snap.Analyzer, snap.Stats, snap.Issues, analysis.TriageOptions{
+			GroupByTrack:  group == "track",
+			GroupByLabel:  group == "label",
+			WaitForPhase2: true,
+			History:       snap.History,
+		}, time.Now()), nil
+	default:
+		return analysis.TriageResult{}, fmt.Errorf("invalid group %q (expected track|label)", group)
+	}
+}
+
+func (s *serveServer) handleNext(w http.ResponseWriter, r *http.Request) {
+	snap := s.snapshot()
+	s.writePayload(w, r, http.StatusOK, buildRobotNextOutput(s.envelope(r, snap.DataHash), snap.Triage, robotScope{}))
+}
+
+func (s *serveServer) handleInsights(w http.ResponseWriter, r *http.Request) {
+	snap := s.snapshot()
+	snap.insightsOnce.Do(func() {
+		snap.analyzerMu.Lock()
+		defer snap.analyzerMu.Unlock()
+		snap.insights = buildRobotInsightsOutput(snap.Analyzer, snap.Stats, snap.Issues, snap.DataHash, robotScope{})
+	})
+	out := snap.insights
+	out.GeneratedAt = time.Now().UTC().Format(time.RFC3339)
+	out.OutputFormat = s.responseFormat(r)
+	s.writePayload(w, r, http.StatusOK, out)
+}
+
+func (s *serveServer) handlePlan(w http.ResponseWriter, r *http.Request) {
+	out := s.snapshot().plan()
+	out.OutputFormat = s.responseFormat(r)
+	s.writePayload(w, r, http.StatusOK, out)
+}
+
+func (snap *serveSnapshot) plan() robotPlanOutput {
+	snap.analyzerMu.Lock()
+	plan := snap.Analyzer.GetExecutionPlan()
+	snap.analyzerMu.Unlock()
+	return buildRobotPlanOutput(plan, snap.Stats.Config, snap.Stats.Status(), snap.DataHash, robotScope{})
+}
+
+func (s *serveServer) handleGraph(w http.ResponseWriter, r *http.Request) {
+	snap := s.snapshot()
+	q := r.URL.Query()
+
+	var format export.GraphExportFormat
+	switch strings.ToLower(q.Get("format")) {
+	case "dot":
+		format = export.GraphFormatDOT
+	case "mermaid":
+		format = export.GraphFormatMermaid
+	default:
+		format = export.GraphFormatJSON
+	}
+	depth := 0
+	if v := q.Get("depth"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			s.writeError(w, r, http.StatusBadRequest, fmt.Sprintf("invalid depth %q", v))
+			return
+		}
+		depth = n
+	}
+	reduced := false
+	if v := q.Get("reduced"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			s.writeError(w, r, http.StatusBadRequest, fmt.Sprintf("invalid reduced %q", v))
+			return
+		}
+		reduced = b
+	}
+
+	result, err := export.ExportGraph(snap.Issues, snap.Stats, export.GraphExportConfig{
+		Format:   format,
+		Label:    q.Get("label"),
+		Root:     q.Get("root"),
+		Depth:    depth,
+		DataHash: snap.DataHash,
+		Reduced:  reduced,
+	})
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.writePayload(w, r, http.StatusOK, result)
+}
+
+func (s *serveServer) handleSearch(w http.ResponseWriter, r *http.Request) {
+	snap := s.snapshot()
+	q := r.URL.Query()
+	query := strings.TrimSpace(q.Get("q"))
+	if query == "" {
+		s.writeError(w, r, http.StatusBadRequest, "missing required query parameter q")
+		return
+	}
+	limit := 10
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			s.writeError(w, r, http.StatusBadRequest, fmt.Sprintf("invalid limit %q", v))
+			return
+		}
+		limit = n
+	}
+
+	searchCfg, err := search.SearchConfigFromEnv()
+	if err == nil {
+		searchCfg, err = applySearchConfigOverrides(searchCfg, q.Get("mode"), q.Get("preset"), q.Get("weights"), q.Get("kinds"))
+	}
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), search.EmbeddingConfigFromEnv().SyncTimeout())
+	defer cancel()
+
+	out, err := s.search(ctx, snap, query, limit, searchCfg)
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+	out.OutputFormat = s.responseFormat(r)
+	s.writePayload(w, r, http.StatusOK, out)
+}
+
+// search runs a semantic search against the snapshot. Searches are
+// serialized because they share the on-disk vector index.
+func (s *serveServer) search(ctx context.Context, snap *serveSnapshot, query string, limit int, cfg search.SearchConfig) (robotSearchOutput, error) {
+	req := semanticSearchRequest{
+		Query:      query,
+		Limit:      limit,
+		Config:     cfg,
+		Embedding:  search.EmbeddingConfigFromEnv(),
+		ProjectDir: s.projectDir,
+		History: func() (*correlation.HistoryReport, error) {
+			if snap.History == nil {
+				return nil, errors.New("history unavailable (not a git repository, or started with --no-history)")
+			}
+			return snap.History, nil
+		},
+	}
+	if cfg.Mode.UsesMetrics() {
+		cache, err := snap.metricsCache()
+		if err != nil {
+			return robotSearchOutput{}, err
+		}
+		req.Metrics = cache
+	}
+
+	s.searchMu.Lock()
+	defer s.searchMu.Unlock()
+	out, _, err := runSemanticSearch(ctx, snap.Issues, snap.DataHash, req)
+	return out, err
+}
+
+// metricsCache lazily builds the hybrid-search metrics cache for the snapshot.
+func (snap *serveSnapshot) metricsCache() (search.MetricsCache, error) {
+	snap.metricsOnce.Do(func() {
+		cache := search.NewMetricsCache(search.NewAnalyzerMetricsLoader(snap.Issues))
+		if err := cache.Refresh(); err != nil {
+			snap.metricsErr = fmt.Errorf("computing hybrid metrics: %w", err)
+			return
+		}
+		snap.metrics = cache
+	})
+	return snap.metrics, snap.metricsErr
+}
+
+func (s *serveServer) handleHistory(w http.ResponseWriter, r *http.Request) {
+	snap := s.snapshot()
+	if snap.History == nil {
+		s.writeError(w, r, http.StatusServiceUnavailable, "history unavailable (not a git repository, or started with --no-history)")
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		s.writePayload(w, r, http.StatusOK, snap.History)
+		return
+	}
+
+	history, ok := snap.History.Histories[id]
+	if !ok {
+		if _, known := snap.IssueMap[id]; !known {
+			s.writeError(w, r, http.StatusNotFound, fmt.Sprintf("issue not found: %s", id))
+			return
+		}
+	}
+	s.writePayload(w, r, http.StatusOK, struct {
+		RobotEnvelope
+		BeadID   string                  `json:"bead_id"`
+		GitRange string                  `json:"git_range"`
+		History  correlation.BeadHistory `json:"history"`
+	}{
+		RobotEnvelope: s.envelope(r, snap.DataHash),
+		BeadID:        id,
+		GitRange:      snap.History.GitRange,
+		History:       history,
+	})
+}
+
+func (s *serveServer) handleBlockerChain(w http.ResponseWriter, r *http.Request) {
+	snap := s.snapshot()
+	id := r.PathValue("id")
+	result := snap.blockerChain(id)
+	if result == nil {
+		s.writeError(w, r, http.StatusNotFound, fmt.Sprintf("issue not found: %s", id))
+		return
+	}
+	s.writePayload(w, r, http.StatusOK, robotBlockerChainOutput{
+		RobotEnvelope: s.envelope(r, snap.DataHash),
+		Result:        result,
+	})
+}
+
+func (snap *serveSnapshot) blockerChain(id string) *analysis.BlockerChainResult {
+	snap.analyzerMu.Lock()
+	defer snap.analyzerMu.Unlock()
+	return snap.Analyzer.GetBlockerChain(id)
+}
+
+func (s *serveServer) handleLabelHealth(w http.ResponseWriter, r *http.Request) {
+	snap := s.snapshot()
+	cfg := analysis.DefaultLabelHealthConfig()
+	results := analysis.ComputeAllLabelHealth(snap.Issues, cfg, time.Now().UTC(), snap.Stats)
+	s.writePayload(w, r, http.StatusOK, struct {
+		RobotEnvelope
+		AnalysisConfig analysis.LabelHealthConfig   `json:"analysis_config"`
+		Results        analysis.LabelAnalysisResult `json:"results"`
+	}{
+		RobotEnvelope:  s.envelope(r, snap.DataHash),
+		AnalysisConfig: cfg,
+		Results:        results,
+	})
+}
+
+func (s *serveServer) handleIssue(w http.ResponseWriter, r *http.Request) {
+	snap := s.snapshot()
+	id := r.PathValue("id")
+	issue, ok := snap.IssueMap[id]
+	if !ok {
+		s.writeError(w, r, http.StatusNotFound, fmt.Sprintf("issue not found: %s", id))
+		return
+	}
+	s.writePayload(w, r, http.StatusOK, struct {
+		RobotEnvelope
+		Issue model.Issue `json:"issue"`
+	}{
+		RobotEnvelope: s.envelope(r, snap.DataHash),
+		Issue:         issue,
+	})
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func newTestServeServer(t *testing.T, issues *[]model.Issue, mu *sync.Mutex) *httptest.Server {
+	t.Helper()
+	srv := newServeServer(serveOptions{NoHistory: true, NoWatch: true, Quiet: true}, t.TempDir(), "", func() ([]model.Issue, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]model.Issue(nil), (*issues)...), nil
+	})
+	if err := srv.reload(); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	ts := httptest.NewServer(srv.handler())
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func getJSON(t *testing.T, url string, wantStatus int) map[string]any {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != wantStatus {
+		t.Fatalf("GET %s: status %d, want %d", url, resp.StatusCode, wantStatus)
+	}
+	var payload map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		t.Fatalf("GET %s: decode: %v", url, err)
+	}
+	return payload
+}
+
+func TestServeEndpointsMatchRobotPayloads(t *testing.T) {
+	var mu sync.Mutex
+	issues := []model.Issue{
+		{ID: "S-1", Title: "Root", Status: model.StatusOpen, Priority: 1, IssueType: model.TypeTask},
+		{ID: "S-2", Title: "Child", Status: model.StatusOpen, Priority: 2, IssueType: model.TypeTask,
+			Dependencies: []*model.Dependency{{IssueID: "S-2", DependsOnID: "S-1", Type: model.DepBlocks}}},
+	}
+	ts := newTestServeServer(t, &issues, &mu)
+
+	next := getJSON(t, ts.URL+"/next", http.StatusOK)
+	if next["id"] != "S-1" {
+		t.Fatalf("/next id = %v, want S-1", next["id"])
+	}
+	for _, key := range []string{"generated_at", "data_hash", "claim_command"} {
+		if _, ok := next[key]; !ok {
+			t.Fatalf("/next missing %s", key)
+		}
+	}
+
+	triage := getJSON(t, ts.URL+"/triage", http.StatusOK)
+	if _, ok := triage["triage"].(map[string]any); !ok {
+		t.Fatalf("/triage missing triage object: %v", triage)
+	}
+	if triage["output_format"] != "json" {
+		t.Fatalf("/triage output_format = %v, want json", triage["output_format"])
+	}
+
+	byTrack := getJSON(t, ts.URL+"/triage?group=track", http.StatusOK)
+	if tri, _ := byTrack["triage"].(map[string]any); tri["recommendations_by_track"] == nil {
+		t.Fatalf("/triage?group=track missing recommendations_by_track")
+	}
+	getJSON(t, ts.URL+"/triage?group=bogus", http.StatusBadRequest)
+
+	plan := getJSON(t, ts.URL+"/plan", http.StatusOK)
+	if _, ok := plan["plan"]; !ok {
+		t.Fatalf("/plan missing plan")
+	}
+	if plan["output_format"] != "json" {
+		t.Fatalf("/plan output_format = %v, want json", plan["output_format"])
+	}
+
+	insights := getJSON(t, ts.URL+"/insights", http.StatusOK)
+	if _, ok := insights["full_stats"]; !ok {
+		t.Fatalf("/insights missing full_stats")
+	}
+	if insights["output_format"] != "json" {
+		t.Fatalf("/insights output_format = %v, want json", insights["output_format"])
+	}
+	resp, err := http.Get(ts.URL + "/insights?output=toon")
+	if err != nil {
+		t.Fatalf("GET /insights?output=toon: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	// Without the tru encoder installed TOON falls back to JSON.
+	if text := string(body); !strings.Contains(text, "output_format: toon") && !strings.Contains(text, `"output_format":"toon"`) {
+		t.Fatalf("/insights?output=toon lacks its format marker:\n%.300s", body)
+	}
+
+	chain := getJSON(t, ts.URL+"/blocker-chain/S-2", http.StatusOK)
+	if _, ok := chain["result"]; !ok {
+		t.Fatalf("/blocker-chain missing result")
+	}
+	getJSON(t, ts.URL+"/issues/NOPE", http.StatusNotFound)
+	getJSON(t, ts.URL+"/history/S-1", http.StatusServiceUnavailable)
+}
+
+func TestServeReloadSwapsSnapshot(t *testing.T) {
+	var mu sync.Mutex
+	issues := []model.Issue{
+		{ID: "R-1", Title: "First", Status: model.StatusOpen, Priority: 2, IssueType: model.TypeTask},
+	}
+	ts := newTestServeServer(t, &issues, &mu)
+
+	before := getJSON(t, ts.URL+"/health", http.StatusOK)
+
+	mu.Lock()
+	issues = append(issues, model.Issue{ID: "R-2", Title: "Urgent", Status: model.StatusOpen, Priority: 0, IssueType: model.TypeBug})
+	mu.Unlock()
+
+	resp, err := http.Post(ts.URL+"/reload", "application/json", nil)
+	if err != nil {
+		t.Fatalf("POST /reload: %v", err)
+	}
+	resp.Body.Close()
+
+	after := getJSON(t, ts.URL+"/health", http.StatusOK)
+	if after["issue_count"].(float64) != 2 {
+		t.Fatalf("issue_count after reload = %v, want 2", after["issue_count"])
+	}
+	if before["data_hash"] == after["data_hash"] {
+		t.Fatalf("data_hash unchanged after reload")
+	}
+	if next := getJSON(t, ts.URL+"/next", http.StatusOK); next["id"] != "R-2" {
+		t.Fatalf("/next after reload = %v, want R-2", next["id"])
+	}
+}
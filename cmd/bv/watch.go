@@ -0,0 +1,439 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/internal/datasource"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/baseline"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/drift"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// --robot-watch keeps running and writes one NDJSON event per data change, so
+// orchestrators can follow a project instead of polling --robot-triage. Every
+// emitted state is journaled under .bv/watch/ by data hash; --since-hash
+// replays the transitions since a hash the consumer already saw.
+
+// Watch event types.
+const (
+	watchEventSnapshot  = "snapshot"  // full current state (first event, or resume target unknown)
+	watchEventDelta     = "delta"     // changes since prev_hash
+	watchEventHeartbeat = "heartbeat" // nothing changed; the stream is alive
+	watchEventError     = "error"     // a reload failed; the previous state still holds
+)
+
+const (
+	defaultWatchHeartbeat = 30 * time.Second
+	watchJournalKeep      = 32 // journaled states kept for --since-hash
+)
+
+// watchEvent is one NDJSON line of --robot-watch output.
+type watchEvent struct {
+	RobotEnvelope
+	Type       string `json:"type"`
+	Seq        int    `json:"seq"`
+	PrevHash   string `json:"prev_hash,omitempty"`
+	IssueCount int    `json:"issue_count"`
+
+	// Resumed marks the first delta after reconnecting with --since-hash.
+	Resumed bool `json:"resumed,omitempty"`
+	// ResyncReason explains why a resume fell back to a full snapshot.
+	ResyncReason string `json:"resync_reason,omitempty"`
+
+	Added    []string `json:"added,omitempty"`
+	Removed  []string `json:"removed,omitempty"`
+	Modified []string `json:"modified,omitempty"`
+
+	// TopPick is set on snapshots and whenever the top recommendation changes.
+	TopPick     *analysis.TopPick `json:"top_pick,omitempty"`
+	PrevTopPick string            `json:"prev_top_pick,omitempty"`
+
+	// On snapshots these list everything current; on deltas only what is new.
+	NewlyActionable []string      `json:"newly_actionable,omitempty"`
+	NewAlerts       []drift.Alert `json:"new_alerts,omitempty"`
+
+	Error string `json:"error,omitempty"`
+}
+
+// watchState is the part of a snapshot needed to compute the next delta.
+// It is what the journal stores per data hash.
+type watchState struct {
+	DataHash     string                               `json:"data_hash"`
+	Fingerprints map[string]analysis.IssueFingerprint `json:"fingerprints"`
+	TopPick      string                               `json:"top_pick,omitempty"`
+	Actionable   []string                             `json:"actionable,omitempty"`
+	AlertKeys    []string                             `json:"alert_keys,omitempty"`
+
+	alerts []drift.Alert // current alerts; not journaled
+}
+
+// robotWatcher turns successive snapshots into watch events.
+type robotWatcher struct {
+	enc        *json.Encoder
+	journalDir string // "" disables the journal
+	projectDir string // for drift config and baseline
+	seq        int
+	last       *watchState
+}
+
+func newRobotWatcher(w io.Writer, projectDir, journalDir string) *robotWatcher {
+	return &robotWatcher{
+		enc:        json.NewEncoder(w),
+		journalDir: journalDir,
+		projectDir: projectDir,
+	}
+}
+
+// watchJournalDir returns where watch states are journaled for a project.
+func watchJournalDir(projectDir string) string {
+	return filepath.Join(projectDir, ".bv", "watch")
+}
+
+// runRobotWatch is the entry point for --robot-watch.
+func runRobotWatch(sinceHash string, heartbeat time.Duration) int {
+	projectDir, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting working directory: %v\n", err)
+		return 1
+	}
+	beadsDir, err := loader.GetBeadsDir("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting beads directory: %v\n", err)
+		return 1
+	}
+
+	srv := newServeServer(serveOptions{NoHistory: true, Quiet: true}, projectDir, beadsDir, func() ([]model.Issue, error) {
+		return datasource.LoadIssues("")
+	})
+	srv.logPrefix = "bv --robot-watch"
+	if err := srv.reload(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading beads: %v\n", err)
+		fmt.Fprintln(os.Stderr, "Make sure you are in a project initialized with 'bd init'.")
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	snapshots := make(chan *serveSnapshot, 1)
+	reloadErrs := make(chan error, 1)
+	srv.onReload = func(snap *serveSnapshot) {
+		select {
+		case snapshots <- snap:
+		case <-ctx.Done():
+		}
+	}
+	srv.onReloadError = func(err error) {
+		select {
+		case reloadErrs <- err:
+		default: // One pending error report is enough
+		}
+	}
+	stopWatch := srv.watch()
+	defer stopWatch()
+
+	rw := newRobotWatcher(os.Stdout, projectDir, watchJournalDir(projectDir))
+	if err := rw.start(srv.snapshot(), sinceHash); err != nil {
+		return watchOutputError(err)
+	}
+
+	var ticks <-chan time.Time
+	if heartbeat > 0 {
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+	for {
+		var err error
+		select {
+		case <-ctx.Done():
+			return 0
+		case snap := <-snapshots:
+			err = rw.change(snap)
+		case reloadErr := <-reloadErrs:
+			err = rw.reloadFailed(reloadErr)
+		case <-ticks:
+			err = rw.heartbeat()
+		}
+		if err != nil {
+			return watchOutputError(err)
+		}
+	}
+}
+
+// watchOutputError reports a failed write. A consumer closing the pipe is a
+// normal way to stop watching.
+func watchOutputError(err error) int {
+	if errors.Is(err, syscall.EPIPE) {
+		return 0
+	}
+	fmt.Fprintf(os.Stderr, "Error writing watch event: %v\n", err)
+	return 1
+}
+
+// start emits the first event: a delta from sinceHash when its state is
+// journaled, otherwise a full snapshot.
+func (rw *robotWatcher) start(snap *serveSnapshot, sinceHash string) error {
+	cur := rw.stateOf(snap)
+	if sinceHash != "" {
+		if prev, err := rw.loadState(sinceHash); err == nil {
+			rw.last = prev
+			ev := rw.delta(snap, cur)
+			ev.Resumed = true
+			return rw.emit(ev, cur)
+		}
+	}
+
+	ev := rw.newEvent(watchEventSnapshot, snap)
+	if sinceHash != "" {
+		ev.ResyncReason = fmt.Sprintf("state %s is not in the watch journal", sinceHash)
+	}
+	ev.TopPick = topPickOf(snap)
+	ev.NewlyActionable = cur.Actionable
+	ev.NewAlerts = cur.alerts
+	return rw.emit(ev, cur)
+}
+
+// change emits a delta if snap differs from the last emitted state.
+func (rw *robotWatcher) change(snap *serveSnapshot) error {
+	if rw.last != nil && snap.DataHash == rw.last.DataHash {
+		return nil
+	}
+	cur := rw.stateOf(snap)
+	return rw.emit(rw.delta(snap, cur), cur)
+}
+
+// heartbeat emits a liveness line carrying the current hash.
+func (rw *robotWatcher) heartbeat() error {
+	ev := watchEvent{Type: watchEventHeartbeat}
+	if rw.last != nil {
+		ev.RobotEnvelope = NewRobotEnvelope(rw.last.DataHash)
+		ev.IssueCount = len(rw.last.Fingerprints)
+	}
+	return rw.write(ev)
+}
+
+// reloadFailed reports a reload error; the last state stays current.
+func (rw *robotWatcher) reloadFailed(err error) error {
+	ev := watchEvent{Type: watchEventError, Error: err.Error()}
+	if rw.last != nil {
+		ev.RobotEnvelope = NewRobotEnvelope(rw.last.DataHash)
+		ev.IssueCount = len(rw.last.Fingerprints)
+	}
+	return rw.write(ev)
+}
+
+// delta builds the event for the transition from rw.last to cur.
+func (rw *robotWatcher) delta(snap *serveSnapshot, cur *watchState) watchEvent {
+	ev := rw.newEvent(watchEventDelta, snap)
+	ev.PrevHash = rw.last.DataHash
+
+	diff := analysis.ComputeFingerprintDiff(rw.last.Fingerprints, cur.Fingerprints)
+	ev.Added, ev.Removed, ev.Modified = diff.Added, diff.Removed, diff.Modified
+
+	if cur.TopPick != rw.last.TopPick {
+		ev.TopPick = topPickOf(snap)
+		ev.PrevTopPick = rw.last.TopPick
+	}
+	ev.NewlyActionable = stringsNotIn(cur.Actionable, rw.last.Actionable)
+
+	seen := make(map[string]bool, len(rw.last.AlertKeys))
+	for _, key := range rw.last.AlertKeys {
+		seen[key] = true
+	}
+	for _, alert := range cur.alerts {
+		if !seen[watchAlertKey(alert)] {
+			ev.NewAlerts = append(ev.NewAlerts, alert)
+		}
+	}
+	return ev
+}
+
+func (rw *robotWatcher) newEvent(kind string, snap *serveSnapshot) watchEvent {
+	return watchEvent{
+		RobotEnvelope: NewRobotEnvelope(snap.DataHash),
+		Type:          kind,
+		IssueCount:    len(snap.Issues),
+	}
+}
+
+// emit writes ev and makes cur the state later deltas are computed against.
+func (rw *robotWatcher) emit(ev watchEvent, cur *watchState) error {
+	if err := rw.write(ev); err != nil {
+		return err
+	}
+	rw.last = cur
+	rw.saveState(cur)
+	return nil
+}
+
+func (rw *robotWatcher) write(ev watchEvent) error {
+	rw.seq++
+	ev.Seq = rw.seq
+	return rw.enc.Encode(ev)
+}
+
+// stateOf extracts the delta-relevant state of a snapshot.
+func (rw *robotWatcher) stateOf(snap *serveSnapshot) *watchState {
+	st := &watchState{
+		DataHash:     snap.DataHash,
+		Fingerprints: analysis.ComputeIssueFingerprints(snap.Issues),
+	}
+	if pick := topPickOf(snap); pick != nil {
+		st.TopPick = pick.ID
+	}
+	snap.analyzerMu.Lock()
+	for _, issue := range snap.Analyzer.GetActionableIssues() {
+		st.Actionable = append(st.Actionable, issue.ID)
+	}
+	snap.analyzerMu.Unlock()
+	sort.Strings(st.Actionable)
+	st.alerts = rw.alerts(snap)
+	for _, alert := range st.alerts {
+		st.AlertKeys = append(st.AlertKeys, watchAlertKey(alert))
+	}
+	sort.Strings(st.AlertKeys)
+	return st
+}
+
+// alerts computes the drift and proactive alerts for snap, as --robot-alerts does.
+func (rw *robotWatcher) alerts(snap *serveSnapshot) []drift.Alert {
+	cfg, err := drift.LoadConfig(rw.projectDir)
+	if err != nil {
+		cfg = drift.DefaultConfig()
+	}
+	snap.analyzerMu.Lock()
+	defer snap.analyzerMu.Unlock()
+	result, _ := computeDriftAlerts(snap.Issues, snap.Analyzer, snap.Stats, cfg, baseline.DefaultPath(rw.projectDir))
+	return result.Alerts
+}
+
+// watchAlertKey identifies an alert across reloads. Messages carry counts
+// and ages that change over time, so they are left out.
+func watchAlertKey(a drift.Alert) string {
+	return strings.Join([]string{string(a.Type), a.IssueID, a.Label}, "|")
+}
+
+func topPickOf(snap *serveSnapshot) *analysis.TopPick {
+	if picks := snap.Triage.QuickRef.TopPicks; len(picks) > 0 {
+		pick := picks[0]
+		return &pick
+	}
+	return nil
+}
+
+// stringsNotIn returns the elements of a (sorted) that are not in b.
+func stringsNotIn(a, b []string) []string {
+	have := make(map[string]bool, len(b))
+	for _, s := range b {
+		have[s] = true
+	}
+	var out []string
+	for _, s := range a {
+		if !have[s] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// loadState reads a journaled state by data hash.
+func (rw *robotWatcher) loadState(hash string) (*watchState, error) {
+	if rw.journalDir == "" || !isWatchHash(hash) {
+		return nil, os.ErrNotExist
+	}
+	data, err := os.ReadFile(filepath.Join(rw.journalDir, hash+".json"))
+	if err != nil {
+		return nil, err
+	}
+	var st watchState
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+// saveState journals st and prunes the oldest states. Journal failures only
+// cost resumability, so they are ignored.
+func (rw *robotWatcher) saveState(st *watchState) {
+	if rw.journalDir == "" || !isWatchHash(st.DataHash) {
+		return
+	}
+	if err := os.MkdirAll(rw.journalDir, 0o755); err != nil {
+		return
+	}
+	data, err := json.Marshal(st)
+	if err != nil {
+		return
+	}
+	path := filepath.Join(rw.journalDir, st.DataHash+".json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return
+	}
+	// Touch on re-save so a state seen again counts as recent
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	pruneWatchJournal(rw.journalDir, watchJournalKeep)
+}
+
+// pruneWatchJournal keeps the newest keep states in dir.
+func pruneWatchJournal(dir string, keep int) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	type journalFile struct {
+		path    string
+		modTime time.Time
+	}
+	var files []journalFile
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, journalFile{path: filepath.Join(dir, entry.Name()), modTime: info.ModTime()})
+	}
+	if len(files) <= keep {
+		return
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.After(files[j].modTime) })
+	for _, f := range files[keep:] {
+		_ = os.Remove(f.path)
+	}
+}
+
+// isWatchHash reports whether s looks like a data hash, so --since-hash
+// cannot name arbitrary files.
+func isWatchHash(s string) bool {
+	if s == "" || len(s) > 64 {
+		return false
+	}
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func decodeWatchEvents(t *testing.T, buf *bytes.Buffer) []watchEvent {
+	t.Helper()
+	var events []watchEvent
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var ev watchEvent
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			t.Fatalf("invalid NDJSON line %q: %v", line, err)
+		}
+		events = append(events, ev)
+	}
+	buf.Reset()
+	return events
+}
+
+func TestRobotWatchEmitsDeltasAndResumes(t *testing.T) {
+	projectDir := t.TempDir()
+	journal := watchJournalDir(projectDir)
+	srv := newServeServer(serveOptions{NoHistory: true, Quiet: true}, projectDir, "", nil)
+
+	v1 := []model.Issue{
+		{ID: "W-1", Title: "Blocker", Status: model.StatusOpen, Priority: 1, IssueType: model.TypeTask},
+		{ID: "W-2", Title: "Blocked", Status: model.StatusOpen, Priority: 2, IssueType: model.TypeTask,
+			Dependencies: []*model.Dependency{{IssueID: "W-2", DependsOnID: "W-1", Type: model.DepBlocks}}},
+	}
+	v2 := []model.Issue{
+		{ID: "W-1", Title: "Blocker", Status: model.StatusClosed, Priority: 1, IssueType: model.TypeTask},
+		v1[1],
+		{ID: "W-3", Title: "New", Status: model.StatusOpen, Priority: 3, IssueType: model.TypeTask},
+	}
+	snap1, snap2 := srv.buildSnapshot(v1), srv.buildSnapshot(v2)
+
+	var buf bytes.Buffer
+	rw := newRobotWatcher(&buf, projectDir, journal)
+	if err := rw.start(snap1, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := rw.change(snap1); err != nil { // same hash: no event
+		t.Fatal(err)
+	}
+	if err := rw.change(snap2); err != nil {
+		t.Fatal(err)
+	}
+	if err := rw.heartbeat(); err != nil {
+		t.Fatal(err)
+	}
+
+	events := decodeWatchEvents(t, &buf)
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want snapshot, delta, heartbeat", len(events))
+	}
+	snapshot, delta, beat := events[0], events[1], events[2]
+	if snapshot.Type != watchEventSnapshot || snapshot.TopPick == nil || snapshot.TopPick.ID != "W-1" || snapshot.DataHash != snap1.DataHash {
+		t.Fatalf("unexpected snapshot: %+v", snapshot)
+	}
+	if delta.Type != watchEventDelta || delta.PrevHash != snap1.DataHash || delta.DataHash != snap2.DataHash {
+		t.Fatalf("delta hashes wrong: %+v", delta)
+	}
+	if strings.Join(delta.Added, ",") != "W-3" || strings.Join(delta.Modified, ",") != "W-1" || len(delta.Removed) != 0 {
+		t.Fatalf("delta diff wrong: added=%v modified=%v removed=%v", delta.Added, delta.Modified, delta.Removed)
+	}
+	if strings.Join(delta.NewlyActionable, ",") != "W-2,W-3" {
+		t.Fatalf("newly actionable = %v, want [W-2 W-3]", delta.NewlyActionable)
+	}
+	if delta.TopPick == nil || delta.PrevTopPick != "W-1" {
+		t.Fatalf("top pick change not reported: %+v / %q", delta.TopPick, delta.PrevTopPick)
+	}
+	if beat.Type != watchEventHeartbeat || beat.DataHash != snap2.DataHash || beat.Seq != 3 {
+		t.Fatalf("unexpected heartbeat: %+v", beat)
+	}
+
+	// A consumer that saw snap1 reconnects and gets one delta covering snap2
+	resumed := newRobotWatcher(&buf, projectDir, journal)
+	if err := resumed.start(snap2, snap1.DataHash); err != nil {
+		t.Fatal(err)
+	}
+	events = decodeWatchEvents(t, &buf)
+	if ev := events[0]; ev.Type != watchEventDelta || !ev.Resumed || ev.PrevHash != snap1.DataHash || strings.Join(ev.Added, ",") != "W-3" {
+		t.Fatalf("resume did not replay the missed delta: %+v", ev)
+	}
+
+	// Unknown hashes fall back to a full snapshot
+	unknown := newRobotWatcher(&buf, projectDir, journal)
+	if err := unknown.start(snap2, "0123456789abcdef"); err != nil {
+		t.Fatal(err)
+	}
+	events = decodeWatchEvents(t, &buf)
+	if ev := events[0]; ev.Type != watchEventSnapshot || ev.ResyncReason == "" {
+		t.Fatalf("expected a resync snapshot, got %+v", ev)
+	}
+}
+
+func TestPruneWatchJournalKeepsNewest(t *testing.T) {
+	projectDir := t.TempDir()
+	rw := newRobotWatcher(&bytes.Buffer{}, projectDir, watchJournalDir(projectDir))
+	base := time.Now().Add(-time.Hour)
+	for i, hash := range []string{"aa", "bb", "cc"} {
+		rw.saveState(&watchState{DataHash: hash})
+		stamp := base.Add(time.Duration(i) * time.Minute)
+		if err := os.Chtimes(filepath.Join(rw.journalDir, hash+".json"), stamp, stamp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pruneWatchJournal(rw.journalDir, 2)
+	if _, err := rw.loadState("aa"); err == nil {
+		t.Fatalf("oldest state should have been pruned")
+	}
+	if _, err := rw.loadState("cc"); err != nil {
+		t.Fatalf("newest state missing: %v", err)
+	}
+	if _, err := rw.loadState("../etc"); err == nil {
+		t.Fatalf("non-hash names must be rejected")
+	}
+}
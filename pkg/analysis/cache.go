@@ -303,9 +303,9 @@ func ComputeDataHash(issues []model.Issue) string {
 // IssueFingerprint represents a per-issue hash split across content and dependencies.
 // It supports fast diffing between snapshots without a full rebuild.
 type IssueFingerprint struct {
-	ID             string
-	ContentHash    string
-	DependencyHash string
+	ID             string `json:"id"`
+	ContentHash    string `json:"content_hash"`
+	DependencyHash string `json:"dependency_hash"`
 }
 
 // IssueDiff captures a per-issue diff between two snapshots.
@@ -329,17 +329,22 @@ func ComputeIssueFingerprint(issue model.Issue) IssueFingerprint {
 
 // ComputeIssueDiff compares old and new issue slices and returns an IssueDiff.
 func ComputeIssueDiff(oldIssues, newIssues []model.Issue) IssueDiff {
-	oldFP := make(map[string]IssueFingerprint, len(oldIssues))
-	for i := range oldIssues {
-		fp := ComputeIssueFingerprint(oldIssues[i])
-		oldFP[fp.ID] = fp
-	}
-	newFP := make(map[string]IssueFingerprint, len(newIssues))
-	for i := range newIssues {
-		fp := ComputeIssueFingerprint(newIssues[i])
-		newFP[fp.ID] = fp
+	return ComputeFingerprintDiff(ComputeIssueFingerprints(oldIssues), ComputeIssueFingerprints(newIssues))
+}
+
+// ComputeIssueFingerprints fingerprints every issue, keyed by ID.
+func ComputeIssueFingerprints(issues []model.Issue) map[string]IssueFingerprint {
+	fps := make(map[string]IssueFingerprint, len(issues))
+	for i := range issues {
+		fp := ComputeIssueFingerprint(issues[i])
+		fps[fp.ID] = fp
 	}
+	return fps
+}
 
+// ComputeFingerprintDiff is ComputeIssueDiff over previously computed
+// fingerprints, for callers that keep fingerprints instead of full snapshots.
+func ComputeFingerprintDiff(oldFP, newFP map[string]IssueFingerprint) IssueDiff {
 	var diff IssueDiff
 	for id, newIssue := range newFP {
 		oldIssue, exists := oldFP[id]
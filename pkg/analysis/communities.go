@@ -0,0 +1,416 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// CommunityOptions configures community detection.
+type CommunityOptions struct {
+	MinSize       int     // Smallest community to report (default 3)
+	Resolution    float64 // Modularity resolution; >1 favours smaller communities (default 1)
+	IncludeClosed bool    // Cluster closed issues too (default: open work only)
+}
+
+// DefaultCommunityOptions returns sensible defaults
+func DefaultCommunityOptions() CommunityOptions {
+	return CommunityOptions{
+		MinSize:    3,
+		Resolution: 1.0,
+	}
+}
+
+// Community is a densely connected group of issues in the dependency graph,
+// with a proposed epic and label that would make the grouping explicit.
+type Community struct {
+	ID            int      `json:"id"`
+	Size          int      `json:"size"`
+	Members       []string `json:"members"`
+	InternalEdges int      `json:"internal_edges"`
+	BoundaryEdges int      `json:"boundary_edges"`
+	Cohesion      float64  `json:"cohesion"` // Share of members' edges that stay inside the community
+
+	Keywords       []string `json:"keywords,omitempty"` // Distinctive title words, most dominant first
+	SuggestedTitle string   `json:"suggested_title"`    // Proposed epic title
+	SuggestedLabel string   `json:"suggested_label,omitempty"`
+	LabelCoverage  float64  `json:"label_coverage"`          // Share of members already carrying SuggestedLabel
+	MissingLabel   []string `json:"missing_label,omitempty"` // Members without SuggestedLabel
+
+	Parent        string   `json:"parent,omitempty"`         // Most common parent epic among members
+	WithoutParent []string `json:"without_parent,omitempty"` // Members not under Parent
+	Commands      []string `json:"commands,omitempty"`       // br commands that would formalise the grouping
+}
+
+// CommunityReport is the result of modularity clustering over the issue graph.
+type CommunityReport struct {
+	Nodes       int         `json:"nodes"`
+	Edges       int         `json:"edges"`
+	Modularity  float64     `json:"modularity"`
+	Communities []Community `json:"communities"`
+	Unclustered int         `json:"unclustered"` // Issues in no community of at least MinSize
+}
+
+// CommunityOf maps each clustered issue ID to its index in Communities.
+func (r CommunityReport) CommunityOf() map[string]int {
+	of := make(map[string]int)
+	for i, c := range r.Communities {
+		for _, id := range c.Members {
+			of[id] = i
+		}
+	}
+	return of
+}
+
+// DetectCommunities partitions the undirected graph of blocking, related
+// and discovered-from links with the Louvain method. Parent-child links are
+// left out so existing epics do not decide the outcome; instead each
+// community reports the members that sit outside its dominant epic.
+func DetectCommunities(issues []model.Issue, opts CommunityOptions) CommunityReport {
+	if opts.MinSize <= 0 {
+		opts.MinSize = DefaultCommunityOptions().MinSize
+	}
+	if opts.Resolution <= 0 {
+		opts.Resolution = 1.0
+	}
+
+	// Nodes in ID order keep the clustering deterministic.
+	byID := make(map[string]*model.Issue, len(issues))
+	parentOf := make(map[string]string)
+	var ids []string
+	for i := range issues {
+		iss := &issues[i]
+		if _, dup := byID[iss.ID]; dup {
+			continue
+		}
+		byID[iss.ID] = iss
+		for _, dep := range iss.Dependencies {
+			if dep != nil && dep.Type == model.DepParentChild && dep.DependsOnID != iss.ID && parentOf[iss.ID] == "" {
+				parentOf[iss.ID] = dep.DependsOnID
+			}
+		}
+		if opts.IncludeClosed || !isClosedLikeStatus(iss.Status) {
+			ids = append(ids, iss.ID)
+		}
+	}
+	sort.Strings(ids)
+	index := make(map[string]int, len(ids))
+	for i, id := range ids {
+		index[id] = i
+	}
+
+	adj := make([]map[int]float64, len(ids))
+	for i := range adj {
+		adj[i] = make(map[int]float64)
+	}
+	edges := 0
+	for i, id := range ids {
+		for _, dep := range byID[id].Dependencies {
+			if dep == nil || dep.Type == model.DepParentChild {
+				continue
+			}
+			j, ok := index[dep.DependsOnID]
+			if !ok || j == i || adj[i][j] > 0 {
+				continue
+			}
+			adj[i][j], adj[j][i] = 1, 1
+			edges++
+		}
+	}
+
+	membership, modularity := louvain(adj, opts.Resolution)
+
+	groups := make(map[int][]int)
+	for node, c := range membership {
+		groups[c] = append(groups[c], node)
+	}
+	report := CommunityReport{Nodes: len(ids), Edges: edges, Modularity: modularity, Communities: []Community{}}
+
+	// Keyword document frequency across all clustered issues, for weighting
+	// the words that set a community apart.
+	titleWords := make([][]string, len(ids))
+	docFreq := make(map[string]int)
+	for i, id := range ids {
+		titleWords[i] = extractKeywords(byID[id].Title, "")
+		for _, w := range titleWords[i] {
+			docFreq[w]++
+		}
+	}
+
+	for _, nodes := range groups {
+		if len(nodes) < opts.MinSize {
+			report.Unclustered += len(nodes)
+			continue
+		}
+		in := make(map[int]bool, len(nodes))
+		for _, n := range nodes {
+			in[n] = true
+		}
+		c := Community{Size: len(nodes)}
+		for _, n := range nodes {
+			c.Members = append(c.Members, ids[n])
+			for m := range adj[n] {
+				if !in[m] {
+					c.BoundaryEdges++
+				} else if m > n {
+					c.InternalEdges++
+				}
+			}
+		}
+		sort.Strings(c.Members)
+		if total := c.InternalEdges + c.BoundaryEdges; total > 0 {
+			c.Cohesion = float64(c.InternalEdges) / float64(total)
+		}
+
+		c.Keywords = communityKeywords(nodes, titleWords, docFreq, len(ids))
+		describeCommunity(&c, nodes, ids, byID, parentOf, adj)
+		report.Communities = append(report.Communities, c)
+	}
+
+	sort.Slice(report.Communities, func(i, j int) bool {
+		a, b := report.Communities[i], report.Communities[j]
+		if a.Size != b.Size {
+			return a.Size > b.Size
+		}
+		if a.Cohesion != b.Cohesion {
+			return a.Cohesion > b.Cohesion
+		}
+		return a.Members[0] < b.Members[0]
+	})
+	for i := range report.Communities {
+		report.Communities[i].ID = i + 1
+	}
+	return report
+}
+
+// communityKeywords ranks title words by how many members use them,
+// discounted by how common they are across the whole graph.
+func communityKeywords(nodes []int, titleWords [][]string, docFreq map[string]int, total int) []string {
+	freq := make(map[string]int)
+	for _, n := range nodes {
+		for _, w := range titleWords[n] {
+			freq[w]++
+		}
+	}
+	type scored struct {
+		word  string
+		score float64
+	}
+	var words []scored
+	for w, f := range freq {
+		if f < 2 {
+			continue
+		}
+		words = append(words, scored{w, float64(f) * math.Log(1+float64(total)/float64(docFreq[w]))})
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if words[i].score != words[j].score {
+			return words[i].score > words[j].score
+		}
+		return words[i].word < words[j].word
+	})
+	var keywords []string
+	for i := 0; i < len(words) && i < 3; i++ {
+		keywords = append(keywords, words[i].word)
+	}
+	return keywords
+}
+
+// describeCommunity fills in the proposed title, label and parent, and the
+// commands that would apply them.
+func describeCommunity(c *Community, nodes []int, ids []string, byID map[string]*model.Issue, parentOf map[string]string, adj []map[int]float64) {
+	switch {
+	case len(c.Keywords) > 0:
+		title := []rune(strings.Join(c.Keywords, ", "))
+		c.SuggestedTitle = strings.ToUpper(string(title[:1])) + string(title[1:])
+	default:
+		// No shared vocabulary: name it after its best-connected member.
+		hub := nodes[0]
+		for _, n := range nodes {
+			if len(adj[n]) > len(adj[hub]) || (len(adj[n]) == len(adj[hub]) && ids[n] < ids[hub]) {
+				hub = n
+			}
+		}
+		c.SuggestedTitle = byID[ids[hub]].Title
+	}
+
+	// A label already carried by at least half the members wins; otherwise
+	// the top keyword is proposed as a new one.
+	labelCount := make(map[string]int)
+	for _, id := range c.Members {
+		for _, l := range byID[id].Labels {
+			labelCount[l]++
+		}
+	}
+	best := ""
+	for l, n := range labelCount {
+		if n > labelCount[best] || (n == labelCount[best] && l < best) {
+			best = l
+		}
+	}
+	switch {
+	case best != "" && 2*labelCount[best] >= c.Size:
+		c.SuggestedLabel = best
+	case len(c.Keywords) > 0:
+		c.SuggestedLabel = c.Keywords[0]
+	}
+	if c.SuggestedLabel != "" {
+		c.LabelCoverage = float64(labelCount[c.SuggestedLabel]) / float64(c.Size)
+		for _, id := range c.Members {
+			if !hasLabel(byID[id].Labels, c.SuggestedLabel) {
+				c.MissingLabel = append(c.MissingLabel, id)
+			}
+		}
+	}
+
+	// The dominant parent must hold at least two members to count.
+	parentCount := make(map[string]int)
+	for _, id := range c.Members {
+		if p := parentOf[id]; p != "" {
+			parentCount[p]++
+		}
+	}
+	for p, n := range parentCount {
+		if n >= 2 && (n > parentCount[c.Parent] || (n == parentCount[c.Parent] && p < c.Parent)) {
+			c.Parent = p
+		}
+	}
+	for _, id := range c.Members {
+		if id != c.Parent && (c.Parent == "" || parentOf[id] != c.Parent) {
+			c.WithoutParent = append(c.WithoutParent, id)
+		}
+	}
+
+	if c.Parent == "" {
+		c.Commands = append(c.Commands, fmt.Sprintf("br create --title=%q --type=epic", c.SuggestedTitle))
+	} else {
+		for _, id := range c.WithoutParent {
+			c.Commands = append(c.Commands, fmt.Sprintf("br dep add %s parent-child:%s", id, c.Parent))
+		}
+	}
+	for _, id := range c.MissingLabel {
+		c.Commands = append(c.Commands, fmt.Sprintf("br update %s --add-label=%s", id, c.SuggestedLabel))
+	}
+}
+
+// louvain clusters an undirected weighted graph (adj symmetric, no self
+// loops) and returns each node's community and the partition's modularity.
+// Nodes are visited in index order, so results are deterministic.
+func louvain(adj []map[int]float64, resolution float64) ([]int, float64) {
+	membership := make([]int, len(adj))
+	for i := range membership {
+		membership[i] = i
+	}
+	total := 0.0
+	for _, nbrs := range adj {
+		for _, w := range nbrs {
+			total += w
+		}
+	}
+	if total == 0 {
+		return membership, 0
+	}
+
+	level := adj
+	for {
+		comm, moved := louvainLocalMoves(level, total, resolution)
+		if !moved {
+			break
+		}
+		// Renumber communities densely and fold each into a single node.
+		renumber := make(map[int]int)
+		for _, c := range comm {
+			if _, ok := renumber[c]; !ok {
+				renumber[c] = len(renumber)
+			}
+		}
+		next := make([]map[int]float64, len(renumber))
+		for i := range next {
+			next[i] = make(map[int]float64)
+		}
+		for i, nbrs := range level {
+			ci := renumber[comm[i]]
+			for j, w := range nbrs {
+				next[ci][renumber[comm[j]]] += w
+			}
+		}
+		for i, c := range membership {
+			membership[i] = renumber[comm[c]]
+		}
+		level = next
+	}
+
+	// Q = sum over communities of in_c/2m - resolution*(tot_c/2m)^2
+	in := make(map[int]float64)
+	tot := make(map[int]float64)
+	for i, nbrs := range adj {
+		for j, w := range nbrs {
+			tot[membership[i]] += w
+			if membership[i] == membership[j] {
+				in[membership[i]] += w
+			}
+		}
+	}
+	q := 0.0
+	for c, t := range tot {
+		q += in[c]/total - resolution*(t/total)*(t/total)
+	}
+	return membership, q
+}
+
+// louvainLocalMoves repeatedly moves each node to the neighbouring
+// community with the largest modularity gain until no move helps.
+// Self loops (folded communities) count towards a node's degree.
+func louvainLocalMoves(adj []map[int]float64, total, resolution float64) ([]int, bool) {
+	n := len(adj)
+	comm := make([]int, n)
+	degree := make([]float64, n)
+	tot := make([]float64, n)
+	for i, nbrs := range adj {
+		comm[i] = i
+		for _, w := range nbrs {
+			degree[i] += w
+		}
+		tot[i] = degree[i]
+	}
+
+	moved := false
+	for pass := 0; pass < 100; pass++ {
+		changed := false
+		for i := 0; i < n; i++ {
+			links := make(map[int]float64)
+			for j, w := range adj[i] {
+				if j != i {
+					links[comm[j]] += w
+				}
+			}
+			own := comm[i]
+			tot[own] -= degree[i]
+
+			best, bestGain := own, links[own]-resolution*tot[own]*degree[i]/total
+			candidates := make([]int, 0, len(links))
+			for c := range links {
+				candidates = append(candidates, c)
+			}
+			sort.Ints(candidates)
+			for _, c := range candidates {
+				if gain := links[c] - resolution*tot[c]*degree[i]/total; gain > bestGain+1e-12 {
+					best, bestGain = c, gain
+				}
+			}
+
+			tot[best] += degree[i]
+			if best != own {
+				comm[i] = best
+				changed, moved = true, true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	return comm, moved
+}
@@ -0,0 +1,60 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestDetectCommunities(t *testing.T) {
+	related := func(id, other string) *model.Dependency {
+		return &model.Dependency{IssueID: id, DependsOnID: other, Type: model.DepRelated}
+	}
+	issues := []model.Issue{
+		// Two triangles joined by a single edge (a3 - b1).
+		{ID: "E", Title: "Auth epic", Status: model.StatusOpen, IssueType: model.TypeEpic},
+		{ID: "a1", Title: "Login token refresh", Status: model.StatusOpen, Labels: []string{"auth"}, Dependencies: childOf("a1", "E")},
+		{ID: "a2", Title: "Token expiry handling", Status: model.StatusOpen, Labels: []string{"auth"}, Dependencies: childOf("a2", "E", blockedBy("a2", "a1")...)},
+		{ID: "a3", Title: "Revoke token endpoint", Status: model.StatusOpen, Dependencies: append(blockedBy("a3", "a1", "a2"), related("a3", "b1"))},
+		{ID: "b1", Title: "Chart export", Status: model.StatusOpen},
+		{ID: "b2", Title: "Chart colors", Status: model.StatusOpen, Dependencies: blockedBy("b2", "b1")},
+		{ID: "b3", Title: "Chart legend", Status: model.StatusOpen, Dependencies: []*model.Dependency{related("b3", "b1"), related("b3", "b2")}},
+		{ID: "done", Title: "Old chart work", Status: model.StatusClosed, Dependencies: blockedBy("done", "b1")},
+	}
+
+	report := DetectCommunities(issues, DefaultCommunityOptions())
+	if report.Nodes != 7 || report.Edges != 7 || len(report.Communities) != 2 || report.Unclustered != 1 {
+		t.Fatalf("report = %+v", report)
+	}
+	if report.Modularity < 0.3 {
+		t.Errorf("modularity = %v, want a clear split", report.Modularity)
+	}
+
+	auth, chart := report.Communities[0], report.Communities[1]
+	if strings.Join(auth.Members, ",") != "a1,a2,a3" || strings.Join(chart.Members, ",") != "b1,b2,b3" {
+		t.Fatalf("members = %v / %v", auth.Members, chart.Members)
+	}
+	if auth.InternalEdges != 3 || auth.BoundaryEdges != 1 || auth.Cohesion != 0.75 {
+		t.Errorf("auth edges %d/%d, cohesion %v", auth.InternalEdges, auth.BoundaryEdges, auth.Cohesion)
+	}
+	if auth.Keywords[0] != "token" || auth.SuggestedLabel != "auth" || strings.Join(auth.MissingLabel, ",") != "a3" {
+		t.Errorf("auth keywords %v, label %q missing %v", auth.Keywords, auth.SuggestedLabel, auth.MissingLabel)
+	}
+	if auth.Parent != "E" || strings.Join(auth.WithoutParent, ",") != "a3" {
+		t.Errorf("auth parent %q, without %v", auth.Parent, auth.WithoutParent)
+	}
+	if auth.Commands[0] != "br dep add a3 parent-child:E" {
+		t.Errorf("auth commands = %v", auth.Commands)
+	}
+
+	if chart.SuggestedTitle != "Chart" || chart.Parent != "" || len(chart.WithoutParent) != 3 {
+		t.Errorf("chart = %+v", chart)
+	}
+	if chart.Commands[0] != `br create --title="Chart" --type=epic` {
+		t.Errorf("chart commands = %v", chart.Commands)
+	}
+	if of := report.CommunityOf(); of["b2"] != 1 || of["a1"] != 0 {
+		t.Errorf("CommunityOf = %v", of)
+	}
+}
@@ -134,11 +134,17 @@ func formatCyclePath(cycle []string) string {
 
 // WouldCreateCycle checks if adding a dependency from->to would create a cycle
 func WouldCreateCycle(issues []model.Issue, fromID, toID string) (bool, []string) {
+	return wouldCreateCycle(issues, fromID, toID, model.DependencyType.IsBlocking)
+}
+
+// wouldCreateCycle is WouldCreateCycle over the dependencies whose type
+// satisfies include.
+func wouldCreateCycle(issues []model.Issue, fromID, toID string, include func(model.DependencyType) bool) (bool, []string) {
 	// Build adjacency map
 	adj := make(map[string][]string)
 	for _, issue := range issues {
 		for _, dep := range issue.Dependencies {
-			if dep == nil || !dep.Type.IsBlocking() {
+			if dep == nil || !include(dep.Type) {
 				continue
 			}
 			adj[issue.ID] = append(adj[issue.ID], dep.DependsOnID)
@@ -201,6 +207,24 @@ func CheckDependencyAddition(issues []model.Issue, fromID, toID string) (bool, [
 	return true, nil, ""
 }
 
+// CheckTypedDependencyAddition is CheckDependencyAddition for a dependency of
+// the given type. Blocking edges are checked against the blocking graph and
+// parent-child edges against the hierarchy; related and discovered-from links
+// never form cycles.
+func CheckTypedDependencyAddition(issues []model.Issue, fromID, toID string, depType model.DependencyType) (bool, []string, string) {
+	switch {
+	case depType.IsBlocking():
+		return CheckDependencyAddition(issues, fromID, toID)
+	case depType == model.DepParentChild:
+		isParentChild := func(t model.DependencyType) bool { return t == model.DepParentChild }
+		if wouldCycle, path := wouldCreateCycle(issues, fromID, toID, isParentChild); wouldCycle {
+			warning := fmt.Sprintf("Making %s a child of %s would create a hierarchy cycle: %s", fromID, toID, formatCyclePath(path))
+			return false, path, warning
+		}
+	}
+	return true, nil, ""
+}
+
 // CycleWarningDetector provides stateful cycle warning detection
 type CycleWarningDetector struct {
 	config CycleWarningConfig
@@ -468,3 +468,29 @@ func BenchmarkCheckDependencyAddition(b *testing.B) {
 		CheckDependencyAddition(issues, "n1", "n2")
 	}
 }
+
+func TestCheckTypedDependencyAddition(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "epic", Status: model.StatusOpen},
+		{ID: "story", Status: model.StatusOpen, Dependencies: []*model.Dependency{
+			{IssueID: "story", DependsOnID: "epic", Type: model.DepParentChild},
+		}},
+		{ID: "task", Status: model.StatusOpen, Dependencies: []*model.Dependency{
+			{IssueID: "task", DependsOnID: "story", Type: model.DepBlocks},
+		}},
+	}
+
+	// Parent-child edges only cycle through the hierarchy
+	if ok, path, _ := CheckTypedDependencyAddition(issues, "epic", "story", model.DepParentChild); ok || len(path) != 3 {
+		t.Errorf("epic as child of its own child should be refused, got ok=%v path=%v", ok, path)
+	}
+	if ok, _, _ := CheckTypedDependencyAddition(issues, "epic", "story", model.DepBlocks); !ok {
+		t.Error("blocks edge epic -> story has no blocking cycle")
+	}
+	if ok, path, warning := CheckTypedDependencyAddition(issues, "story", "task", model.DepBlocks); ok || len(path) == 0 || warning == "" {
+		t.Errorf("story -> task would close a blocking cycle, got ok=%v path=%v", ok, path)
+	}
+	if ok, _, _ := CheckTypedDependencyAddition(issues, "story", "task", model.DepRelated); !ok {
+		t.Error("related links never form cycles")
+	}
+}
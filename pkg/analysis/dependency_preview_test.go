@@ -0,0 +1,44 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestPreviewDependencyChange(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "a", Status: model.StatusOpen},
+		{ID: "b", Status: model.StatusOpen, Dependencies: []*model.Dependency{
+			{IssueID: "b", DependsOnID: "a", Type: model.DepBlocks},
+		}},
+		{ID: "c", Status: model.StatusOpen},
+	}
+
+	add := PreviewDependencyChange(issues, model.Dependency{IssueID: "c", DependsOnID: "b", Type: model.DepBlocks}, false)
+	if !add.Blocking || len(add.Nodes) != 2 || add.Nodes[0].ID != "c" || add.Nodes[1].ID != "b" {
+		t.Fatalf("unexpected impact shape: %+v", add)
+	}
+	if add.LongestPathAfter <= add.LongestPathBefore {
+		t.Errorf("c -> b should lengthen the longest chain: %v -> %v", add.LongestPathBefore, add.LongestPathAfter)
+	}
+	if b := add.Nodes[1]; b.PageRankAfter <= b.PageRankBefore {
+		t.Errorf("b gains a dependent, PageRank should rise: %v -> %v", b.PageRankBefore, b.PageRankAfter)
+	}
+	if len(issues[2].Dependencies) != 0 {
+		t.Fatal("PreviewDependencyChange modified its input")
+	}
+
+	remove := PreviewDependencyChange(issues, model.Dependency{IssueID: "b", DependsOnID: "a", Type: model.DepBlocks}, true)
+	if remove.LongestPathAfter >= remove.LongestPathBefore {
+		t.Errorf("removing b -> a should shorten the longest chain: %v -> %v", remove.LongestPathBefore, remove.LongestPathAfter)
+	}
+	if len(issues[1].Dependencies) != 1 {
+		t.Fatal("PreviewDependencyChange modified its input on removal")
+	}
+
+	related := PreviewDependencyChange(issues, model.Dependency{IssueID: "c", DependsOnID: "a", Type: model.DepRelated}, false)
+	if related.Blocking || related.LongestPathAfter != related.LongestPathBefore {
+		t.Errorf("related links should not move metrics: %+v", related)
+	}
+}
@@ -0,0 +1,287 @@
+package analysis
+
+import (
+	"sort"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// Chokepoint is an issue that every route of work towards a goal passes
+// through.
+type Chokepoint struct {
+	ID         string       `json:"id"`
+	Title      string       `json:"title"`
+	Status     model.Status `json:"status"`
+	Actionable bool         `json:"actionable"` // Nothing open feeds into it
+	Depth      int          `json:"depth"`      // Steps up the dominator tree from the goal (1 = immediate dominator)
+}
+
+// GoalDominators lists the mandatory chokepoints on the way to one goal.
+type GoalDominators struct {
+	Goal        string       `json:"goal"`
+	Title       string       `json:"title"`
+	IsGoal      bool         `json:"is_goal"`     // An open epic or a final deliverable
+	Reachable   bool         `json:"reachable"`   // False when only a dependency cycle leads to it
+	Chokepoints []Chokepoint `json:"chokepoints"` // Nearest first
+	Upstream    int          `json:"upstream"`    // Open issues with a path to the goal
+}
+
+// MustPassIssue is an issue ranked by how many goals it dominates.
+type MustPassIssue struct {
+	ID             string   `json:"id"`
+	Title          string   `json:"title"`
+	Actionable     bool     `json:"actionable"`
+	GoalsDominated int      `json:"goals_dominated"`
+	Goals          []string `json:"goals"`
+}
+
+// DominatorAnalysis is the dominator tree of the open work graph, rooted at
+// a virtual node that feeds every issue nothing open feeds into. Work flows
+// from a blocker to the issues it blocks and from a child to its parent
+// epic. Issue X dominates goal G when every such route from the root to G
+// passes through X: unlike an undirected cut point, X cannot be worked
+// around however the remaining work is scheduled.
+type DominatorAnalysis struct {
+	Nodes       int             `json:"nodes"`
+	Edges       int             `json:"edges"`
+	Goals       []string        `json:"goals"`       // Open epics and open issues nothing open waits on
+	Unreachable []string        `json:"unreachable"` // Open issues only reachable through a cycle
+	MustPass    []MustPassIssue `json:"must_pass"`
+
+	issues []*model.Issue // Node i+1; node 0 is the virtual root
+	index  map[string]int
+	preds  [][]int
+	idom   []int // -1 when unreachable
+	goal   []bool
+}
+
+// ComputeDominators builds the dominator tree of the open issues using the
+// iterative algorithm of Cooper, Harvey and Kennedy and ranks issues by how
+// many goals they dominate.
+func ComputeDominators(issues []model.Issue) *DominatorAnalysis {
+	d := &DominatorAnalysis{index: make(map[string]int)}
+	for i := range issues {
+		iss := &issues[i]
+		if isClosedLikeStatus(iss.Status) {
+			continue
+		}
+		if _, dup := d.index[iss.ID]; dup {
+			continue
+		}
+		d.index[iss.ID] = 0
+		d.issues = append(d.issues, iss)
+	}
+	sort.Slice(d.issues, func(i, j int) bool { return d.issues[i].ID < d.issues[j].ID })
+	for i, iss := range d.issues {
+		d.index[iss.ID] = i + 1
+	}
+
+	n := len(d.issues) + 1
+	d.Nodes = n - 1
+	d.preds = make([][]int, n)
+	succs := make([][]int, n)
+	seen := make(map[[2]int]bool)
+	addEdge := func(from, to int) {
+		if from == to || seen[[2]int{from, to}] {
+			return
+		}
+		seen[[2]int{from, to}] = true
+		d.preds[to] = append(d.preds[to], from)
+		succs[from] = append(succs[from], to)
+	}
+	for i, iss := range d.issues {
+		for _, dep := range iss.Dependencies {
+			if dep == nil {
+				continue
+			}
+			j, ok := d.index[dep.DependsOnID]
+			if !ok {
+				continue
+			}
+			switch {
+			case dep.Type.IsBlocking():
+				addEdge(j, i+1)
+			case dep.Type == model.DepParentChild:
+				addEdge(i+1, j)
+			}
+		}
+	}
+	d.Edges = len(seen)
+	for v := 1; v < n; v++ {
+		if len(d.preds[v]) == 0 {
+			addEdge(0, v)
+		}
+	}
+	for v := range succs {
+		sort.Ints(succs[v])
+	}
+
+	// Postorder numbering from the root, iteratively to survive long chains.
+	post := make([]int, n)
+	for v := range post {
+		post[v] = -1
+	}
+	var order []int
+	visited := make([]bool, n)
+	type frame struct{ v, next int }
+	stack := []frame{{0, 0}}
+	visited[0] = true
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		if top.next < len(succs[top.v]) {
+			w := succs[top.v][top.next]
+			top.next++
+			if !visited[w] {
+				visited[w] = true
+				stack = append(stack, frame{w, 0})
+			}
+			continue
+		}
+		post[top.v] = len(order)
+		order = append(order, top.v)
+		stack = stack[:len(stack)-1]
+	}
+
+	d.idom = make([]int, n)
+	for v := range d.idom {
+		d.idom[v] = -1
+	}
+	d.idom[0] = 0
+	intersect := func(a, b int) int {
+		for a != b {
+			for post[a] < post[b] {
+				a = d.idom[a]
+			}
+			for post[b] < post[a] {
+				b = d.idom[b]
+			}
+		}
+		return a
+	}
+	for changed := true; changed; {
+		changed = false
+		for k := len(order) - 2; k >= 0; k-- { // Reverse postorder, root excluded
+			v := order[k]
+			next := -1
+			for _, p := range d.preds[v] {
+				if d.idom[p] == -1 {
+					continue
+				}
+				if next == -1 {
+					next = p
+				} else {
+					next = intersect(p, next)
+				}
+			}
+			if d.idom[v] != next {
+				d.idom[v] = next
+				changed = true
+			}
+		}
+	}
+
+	d.goal = make([]bool, n)
+	d.Goals = []string{}
+	d.Unreachable = []string{}
+	dominated := make(map[int][]string)
+	for v := 1; v < n; v++ {
+		iss := d.issues[v-1]
+		if d.idom[v] == -1 {
+			d.Unreachable = append(d.Unreachable, iss.ID)
+		}
+		if iss.IssueType != model.TypeEpic && (len(succs[v]) > 0 || d.actionable(v)) {
+			continue
+		}
+		d.goal[v] = true
+		d.Goals = append(d.Goals, iss.ID)
+		if d.idom[v] == -1 {
+			continue
+		}
+		for x := d.idom[v]; x != 0; x = d.idom[x] {
+			dominated[x] = append(dominated[x], iss.ID)
+		}
+	}
+
+	d.MustPass = make([]MustPassIssue, 0, len(dominated))
+	for x, goals := range dominated {
+		d.MustPass = append(d.MustPass, MustPassIssue{
+			ID:             d.issues[x-1].ID,
+			Title:          d.issues[x-1].Title,
+			Actionable:     d.actionable(x),
+			GoalsDominated: len(goals),
+			Goals:          goals,
+		})
+	}
+	sort.Slice(d.MustPass, func(i, j int) bool {
+		a, b := d.MustPass[i], d.MustPass[j]
+		if a.GoalsDominated != b.GoalsDominated {
+			return a.GoalsDominated > b.GoalsDominated
+		}
+		return a.ID < b.ID
+	})
+	return d
+}
+
+// actionable reports whether node v is fed only by the virtual root.
+func (d *DominatorAnalysis) actionable(v int) bool {
+	return len(d.preds[v]) == 1 && d.preds[v][0] == 0
+}
+
+// ForGoal returns the chokepoints on the way to any open issue, not only
+// to the detected goals. ok is false for closed or unknown issues.
+func (d *DominatorAnalysis) ForGoal(id string) (GoalDominators, bool) {
+	v, ok := d.index[id]
+	if !ok {
+		return GoalDominators{}, false
+	}
+	iss := d.issues[v-1]
+	g := GoalDominators{
+		Goal:        id,
+		Title:       iss.Title,
+		IsGoal:      d.goal[v],
+		Reachable:   d.idom[v] != -1,
+		Chokepoints: []Chokepoint{},
+	}
+	if g.Reachable {
+		depth := 1
+		for x := d.idom[v]; x != 0; x = d.idom[x] {
+			c := d.issues[x-1]
+			g.Chokepoints = append(g.Chokepoints, Chokepoint{
+				ID:         c.ID,
+				Title:      c.Title,
+				Status:     c.Status,
+				Actionable: d.actionable(x),
+				Depth:      depth,
+			})
+			depth++
+		}
+	}
+
+	seen := map[int]bool{v: true}
+	queue := []int{v}
+	for len(queue) > 0 {
+		x := queue[0]
+		queue = queue[1:]
+		for _, p := range d.preds[x] {
+			if p != 0 && !seen[p] {
+				seen[p] = true
+				queue = append(queue, p)
+			}
+		}
+	}
+	g.Upstream = len(seen) - 1
+	return g, true
+}
+
+// MustPassItems returns the top limit must-pass issues as insight items
+// valued by the number of goals each dominates.
+func (d *DominatorAnalysis) MustPassItems(limit int) []InsightItem {
+	items := make([]InsightItem, 0, len(d.MustPass))
+	for i, mp := range d.MustPass {
+		if limit > 0 && i >= limit {
+			break
+		}
+		items = append(items, InsightItem{ID: mp.ID, Value: float64(mp.GoalsDominated)})
+	}
+	return items
+}
@@ -0,0 +1,58 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestComputeDominators(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "a", Title: "A", Status: model.StatusOpen, Dependencies: blockedBy("a", "k")},
+		{ID: "k", Title: "Done", Status: model.StatusClosed},
+		{ID: "b", Title: "B", Status: model.StatusOpen, Dependencies: blockedBy("b", "a")},
+		{ID: "d", Title: "D", Status: model.StatusOpen},
+		{ID: "c", Title: "C", Status: model.StatusInProgress, Dependencies: blockedBy("c", "b", "d")},
+		{ID: "g", Title: "Goal", Status: model.StatusOpen, Dependencies: blockedBy("g", "c")},
+		{ID: "epic", Title: "Epic", Status: model.StatusOpen, IssueType: model.TypeEpic},
+		{ID: "x", Title: "X", Status: model.StatusOpen, Dependencies: append(blockedBy("x", "c"), childOf("x", "epic")...)},
+		{ID: "y", Title: "Y", Status: model.StatusOpen, Dependencies: append(blockedBy("y", "c"), childOf("y", "epic")...)},
+		{ID: "p", Title: "P", Status: model.StatusOpen, Dependencies: blockedBy("p", "q")},
+		{ID: "q", Title: "Q", Status: model.StatusOpen, Dependencies: blockedBy("q", "p")},
+		{ID: "r", Title: "R", Status: model.StatusOpen, Dependencies: blockedBy("r", "q")},
+	}
+
+	d := ComputeDominators(issues)
+	if strings.Join(d.Goals, ",") != "epic,g,r" {
+		t.Errorf("goals = %v, want [epic g r]", d.Goals)
+	}
+	if strings.Join(d.Unreachable, ",") != "p,q,r" {
+		t.Errorf("unreachable = %v, want [p q r]", d.Unreachable)
+	}
+	// c is on every route to both goals; b is not, since d also feeds c.
+	if len(d.MustPass) != 1 || d.MustPass[0].ID != "c" || strings.Join(d.MustPass[0].Goals, ",") != "epic,g" {
+		t.Fatalf("must pass = %+v, want c dominating [epic g]", d.MustPass)
+	}
+	if items := d.MustPassItems(5); len(items) != 1 || items[0].Value != 2 {
+		t.Errorf("must pass items = %+v", items)
+	}
+
+	epic, ok := d.ForGoal("epic")
+	if !ok || !epic.IsGoal || !epic.Reachable || epic.Upstream != 6 {
+		t.Errorf("epic = %+v", epic)
+	}
+	if len(epic.Chokepoints) != 1 || epic.Chokepoints[0].ID != "c" || epic.Chokepoints[0].Actionable {
+		t.Errorf("epic chokepoints = %+v", epic.Chokepoints)
+	}
+	b, _ := d.ForGoal("b")
+	if b.IsGoal || len(b.Chokepoints) != 1 || b.Chokepoints[0].ID != "a" || !b.Chokepoints[0].Actionable {
+		t.Errorf("b = %+v", b)
+	}
+	if r, _ := d.ForGoal("r"); r.Reachable || len(r.Chokepoints) != 0 {
+		t.Errorf("r = %+v", r)
+	}
+	if _, ok := d.ForGoal("k"); ok {
+		t.Error("closed issue should not be a goal")
+	}
+}
@@ -874,6 +874,13 @@ func NewGraphStatsForTest(
 	return stats
 }
 
+// NewPendingGraphStatsForTest creates a GraphStats with only the phase 1
+// degrees whose Phase 2 never completes, for testing callers that must not
+// wait on it.
+func NewPendingGraphStatsForTest(outDegree, inDegree map[string]int) *GraphStats {
+	return &GraphStats{OutDegree: outDegree, InDegree: inDegree, phase2Done: make(chan struct{})}
+}
+
 const (
 	incrementalGraphStatsCacheTTL        = 5 * time.Minute
 	incrementalGraphStatsCacheMaxEntries = 8
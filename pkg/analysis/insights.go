@@ -20,6 +20,7 @@ type Insights struct {
 	Authorities    []InsightItem // Strong prerequisite providers
 	Cores          []InsightItem // Highest k-core numbers (structural cohesion)
 	Articulation   []string      // Cut vertices whose removal disconnects graph
+	MustPass       []InsightItem // Issues dominating the most goals (see ComputeDominators)
 	Slack          []InsightItem // Highest slack (parallelizable / flexible nodes)
 	Orphans        []string      // No dependencies (and not blocked?) - Leaf nodes
 	Cycles         [][]string
@@ -28,6 +29,10 @@ type Insights struct {
 
 	// Full stats for calculation explanations
 	Stats *GraphStats
+
+	// Dominators backs MustPass when the caller computed it; the TUI does
+	// so off the UI thread along with the Phase 2 insights.
+	Dominators *DominatorAnalysis `json:"-"`
 }
 
 // VelocitySnapshot is a lightweight view of project throughput for insights.
@@ -0,0 +1,707 @@
+package analysis
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// Monte Carlo completion forecasting.
+//
+// EstimateETAForIssue gives one date per issue. ForecastMonteCarlo instead
+// samples each open issue's duration from the cycle times of similar closed
+// issues (same labels and type), schedules the open work on N agents while
+// respecting blocking dependencies, and repeats that many times. Percentiles
+// of the simulated completion times give date ranges ("85% of runs finish by
+// ...") for each issue and for epics, labels and sprints.
+//
+// Cycle times are claim→close spans from git history. Closed issues without
+// one contribute their created→closed lead time instead, which runs longer;
+// SampleBasis reports which kind the forecast was built from. Work already
+// in progress only finishes like the history that outlasted the time spent
+// on it so far.
+
+// Monte Carlo defaults.
+const (
+	DefaultMonteCarloRuns    = 5000
+	DefaultHistogramBins     = 20
+	minCycleTimeSamples      = 3
+	minSimulatedDurationDays = 1.0 / (24 * 60) // One minute
+
+	// forecastReservoirSize bounds the finish times kept per issue and
+	// group; longer simulations keep a uniform sample of their runs.
+	forecastReservoirSize = 1000
+)
+
+// Sample bases reported in MonteCarloForecast.SampleBasis.
+const (
+	SampleBasisCycleTime = "cycle_time" // Claim→close from git history
+	SampleBasisLeadTime  = "lead_time"  // Created→closed
+	SampleBasisMixed     = "mixed"
+)
+
+// DefaultForecastPercentiles are reported when none are requested.
+var DefaultForecastPercentiles = []int{50, 85, 95}
+
+// MonteCarloOptions configures ForecastMonteCarlo.
+type MonteCarloOptions struct {
+	Agents      int   // Parallel workers (default 1)
+	Runs        int   // Simulations (default DefaultMonteCarloRuns)
+	Percentiles []int // 1..100 (default DefaultForecastPercentiles)
+	Seed        int64 // Same seed and data give the same forecast
+
+	// CycleTimes overrides the created→closed span of closed issues, e.g.
+	// with claim→close times from git history correlation.
+	CycleTimes map[string]time.Duration
+
+	// StartedAt is when in_progress issues were claimed. Issues missing
+	// from it count from their last update.
+	StartedAt map[string]time.Time
+
+	// Scope limits which issues are reported (nil = every open issue). All
+	// open issues are still simulated since they compete for agents.
+	Scope map[string]bool
+
+	// Sprints are reported when they contain a scoped issue.
+	Sprints []model.Sprint
+
+	HistogramBins int // Default DefaultHistogramBins
+}
+
+// PercentileDate is one percentile of a simulated completion distribution.
+type PercentileDate struct {
+	Percentile int       `json:"percentile"`
+	Days       float64   `json:"days"`
+	Date       time.Time `json:"date"`
+}
+
+// HistogramBin counts runs whose completion fell in [StartDays, EndDays).
+type HistogramBin struct {
+	StartDays float64   `json:"start_days"`
+	EndDays   float64   `json:"end_days"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Count     int       `json:"count"`
+}
+
+// MonteCarloIssueForecast is the completion distribution of one issue.
+// ElapsedDays is how long in_progress work has already been underway.
+type MonteCarloIssueForecast struct {
+	IssueID     string           `json:"issue_id"`
+	Title       string           `json:"title"`
+	Status      model.Status     `json:"status"`
+	SampleFrom  string           `json:"sample_from"` // Cycle-time pool: label+type, label, type, global, estimate, or children for epics
+	Samples     int              `json:"samples"`     // Historical cycle times in that pool
+	ElapsedDays float64          `json:"elapsed_days,omitempty"`
+	Percentiles []PercentileDate `json:"percentiles"`
+}
+
+// MonteCarloGroupForecast is the completion distribution of a set of
+// issues: the time at which the last of them is done.
+type MonteCarloGroupForecast struct {
+	Name        string           `json:"name"`
+	Title       string           `json:"title,omitempty"`
+	OpenIssues  int              `json:"open_issues"`
+	Percentiles []PercentileDate `json:"percentiles"`
+}
+
+// MonteCarloForecast is the result of ForecastMonteCarlo.
+type MonteCarloForecast struct {
+	Runs              int                       `json:"runs"`
+	Agents            int                       `json:"agents"`
+	Seed              int64                     `json:"seed"`
+	Percentiles       []int                     `json:"percentile_levels"`
+	HistoricalSamples int                       `json:"historical_samples"`
+	SampleBasis       string                    `json:"sample_basis,omitempty"` // cycle_time, lead_time or mixed
+	SimulatedIssues   int                       `json:"simulated_issues"`
+	Completion        MonteCarloGroupForecast   `json:"completion"` // All scoped issues
+	Histogram         []HistogramBin            `json:"histogram"`  // Of Completion
+	Issues            []MonteCarloIssueForecast `json:"issues"`
+	Epics             []MonteCarloGroupForecast `json:"epics,omitempty"`
+	Labels            []MonteCarloGroupForecast `json:"labels,omitempty"`
+	Sprints           []MonteCarloGroupForecast `json:"sprints,omitempty"`
+}
+
+// cycleTimeSampler draws the remaining duration (days) of one open issue.
+type cycleTimeSampler struct {
+	source  string
+	samples []float64 // Historical cycle times, days, ascending
+	base    float64   // Estimate-based duration when samples is empty
+	elapsed float64   // Days already spent on in_progress work
+	from    int       // First sample longer than elapsed
+}
+
+func (s cycleTimeSampler) draw(rng *rand.Rand) float64 {
+	if len(s.samples) > 0 {
+		// Started work finishes like the history that outlasted it; past
+		// all of it, the issue is due now.
+		if s.from == len(s.samples) {
+			return minSimulatedDurationDays
+		}
+		return s.samples[s.from+rng.Intn(len(s.samples)-s.from)] - s.elapsed
+	}
+	// No history: lognormal spread around the effort estimate.
+	return s.base*math.Exp(rng.NormFloat64()*0.5) - s.elapsed
+}
+
+// reservoir keeps a uniform sample of at most cap(values) of the values
+// added to it.
+type reservoir struct {
+	values []float64
+	seen   int
+}
+
+func newReservoir(size int) reservoir {
+	return reservoir{values: make([]float64, 0, size)}
+}
+
+func (r *reservoir) add(v float64, rng *rand.Rand) {
+	r.seen++
+	if len(r.values) < cap(r.values) {
+		r.values = append(r.values, v)
+		return
+	}
+	if j := rng.Intn(r.seen); j < len(r.values) {
+		r.values[j] = v
+	}
+}
+
+// forecastGroup tracks when the last of a set of open issues finishes.
+type forecastGroup struct {
+	MonteCarloGroupForecast
+	members []int
+	done    reservoir
+}
+
+// ForecastMonteCarlo simulates completion of every open issue.
+func ForecastMonteCarlo(issues []model.Issue, stats *GraphStats, opts MonteCarloOptions, now time.Time) (*MonteCarloForecast, error) {
+	if opts.Agents <= 0 {
+		opts.Agents = 1
+	}
+	if opts.Runs <= 0 {
+		opts.Runs = DefaultMonteCarloRuns
+	}
+	if len(opts.Percentiles) == 0 {
+		opts.Percentiles = DefaultForecastPercentiles
+	}
+	for _, p := range opts.Percentiles {
+		if p < 1 || p > 100 {
+			return nil, fmt.Errorf("percentile %d out of range 1-100", p)
+		}
+	}
+	if opts.HistogramBins <= 0 {
+		opts.HistogramBins = DefaultHistogramBins
+	}
+
+	sim := newForecastSim(issues, stats, opts, now)
+	if opts.Scope != nil {
+		for id := range opts.Scope {
+			if _, ok := sim.index[id]; !ok {
+				if _, exists := sim.all[id]; !exists {
+					return nil, fmt.Errorf("issue %q not found", id)
+				}
+			}
+		}
+	}
+
+	inScope := func(id string) bool { return opts.Scope == nil || opts.Scope[id] }
+	var scoped []int
+	for i, iss := range sim.issues {
+		if inScope(iss.ID) {
+			scoped = append(scoped, i)
+		}
+	}
+	completion := &forecastGroup{MonteCarloGroupForecast: MonteCarloGroupForecast{Name: "all", OpenIssues: len(scoped)}, members: scoped}
+
+	// Epics: every epic that is scoped or has a scoped descendant.
+	var epics []*forecastGroup
+	for i, iss := range sim.issues {
+		if iss.IssueType != model.TypeEpic {
+			continue
+		}
+		members := append([]int{i}, sim.descendants(i)...)
+		relevant := false
+		for _, m := range members {
+			relevant = relevant || inScope(sim.issues[m].ID)
+		}
+		if relevant {
+			epics = append(epics, &forecastGroup{
+				MonteCarloGroupForecast: MonteCarloGroupForecast{Name: iss.ID, Title: iss.Title, OpenIssues: len(members)},
+				members:                 members,
+			})
+		}
+	}
+
+	// Labels over scoped issues.
+	byLabel := make(map[string][]int)
+	for _, i := range scoped {
+		for _, l := range sim.issues[i].Labels {
+			byLabel[l] = append(byLabel[l], i)
+		}
+	}
+	labelNames := make([]string, 0, len(byLabel))
+	for l := range byLabel {
+		labelNames = append(labelNames, l)
+	}
+	sort.Strings(labelNames)
+	labels := make([]*forecastGroup, len(labelNames))
+	for k, l := range labelNames {
+		labels[k] = &forecastGroup{MonteCarloGroupForecast: MonteCarloGroupForecast{Name: l, OpenIssues: len(byLabel[l])}, members: byLabel[l]}
+	}
+
+	// Sprints holding a scoped issue, over all their open issues.
+	var sprints []*forecastGroup
+	for _, s := range opts.Sprints {
+		var members []int
+		relevant := false
+		for _, id := range s.BeadIDs {
+			if i, ok := sim.index[id]; ok {
+				members = append(members, i)
+				relevant = relevant || inScope(id)
+			}
+		}
+		if relevant {
+			sprints = append(sprints, &forecastGroup{
+				MonteCarloGroupForecast: MonteCarloGroupForecast{Name: s.ID, Title: s.Name, OpenIssues: len(members)},
+				members:                 members,
+			})
+		}
+	}
+
+	// Finish times are collected as each run ends instead of keeping every
+	// run of every issue; only the overall completion keeps all runs, for
+	// the histogram.
+	size := min(opts.Runs, forecastReservoirSize)
+	issueDone := make([]reservoir, len(scoped))
+	for k := range issueDone {
+		issueDone[k] = newReservoir(size)
+	}
+	groups := append(append(append([]*forecastGroup{completion}, epics...), labels...), sprints...)
+	completion.done = newReservoir(opts.Runs)
+	for _, g := range groups[1:] {
+		g.done = newReservoir(size)
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	keep := rand.New(rand.NewSource(opts.Seed + 1))
+	for r := 0; r < opts.Runs; r++ {
+		done := sim.run(rng)
+		for k, i := range scoped {
+			issueDone[k].add(done[i], keep)
+		}
+		for _, g := range groups {
+			last := 0.0
+			for _, i := range g.members {
+				last = max(last, done[i])
+			}
+			g.done.add(last, keep)
+		}
+	}
+
+	out := &MonteCarloForecast{
+		Runs:              opts.Runs,
+		Agents:            opts.Agents,
+		Seed:              opts.Seed,
+		Percentiles:       opts.Percentiles,
+		HistoricalSamples: sim.historical,
+		SampleBasis:       sim.basis,
+		SimulatedIssues:   len(sim.issues),
+	}
+	for k, i := range scoped {
+		iss := sim.issues[i]
+		out.Issues = append(out.Issues, MonteCarloIssueForecast{
+			IssueID:     iss.ID,
+			Title:       iss.Title,
+			Status:      iss.Status,
+			SampleFrom:  sim.samplers[i].source,
+			Samples:     len(sim.samplers[i].samples),
+			ElapsedDays: math.Round(sim.samplers[i].elapsed*100) / 100,
+			Percentiles: percentileDates(issueDone[k].values, opts.Percentiles, now),
+		})
+	}
+	report := func(gs []*forecastGroup) []MonteCarloGroupForecast {
+		var forecasts []MonteCarloGroupForecast
+		for _, g := range gs {
+			g.Percentiles = percentileDates(g.done.values, opts.Percentiles, now)
+			forecasts = append(forecasts, g.MonteCarloGroupForecast)
+		}
+		return forecasts
+	}
+	completion.Percentiles = percentileDates(completion.done.values, opts.Percentiles, now)
+	out.Completion = completion.MonteCarloGroupForecast
+	out.Histogram = histogram(completion.done.values, opts.HistogramBins, now)
+	out.Epics = report(epics)
+	out.Labels = report(labels)
+	out.Sprints = report(sprints)
+	return out, nil
+}
+
+// forecastSim is the open-issue graph prepared for repeated scheduling.
+type forecastSim struct {
+	agents     int
+	issues     []model.Issue // Open issues, sorted by ID
+	all        map[string]bool
+	index      map[string]int
+	samplers   []cycleTimeSampler
+	rank       []int   // Pick order when several issues are ready
+	blockers   []int   // Open blockers per issue
+	dependents [][]int // Issues waiting on each issue
+	children   [][]int // Open parent-child descendants (one level)
+	historical int
+	basis      string
+
+	// Per-run scratch
+	indeg []int
+	ready readyHeap
+	busy  finishHeap
+	doneT []float64
+}
+
+func newForecastSim(issues []model.Issue, stats *GraphStats, opts MonteCarloOptions, now time.Time) *forecastSim {
+	s := &forecastSim{agents: opts.Agents, all: make(map[string]bool, len(issues)), index: make(map[string]int)}
+	for _, iss := range issues {
+		s.all[iss.ID] = true
+		if isClosedLikeStatus(iss.Status) {
+			continue
+		}
+		s.issues = append(s.issues, iss)
+	}
+	sort.Slice(s.issues, func(a, b int) bool { return s.issues[a].ID < s.issues[b].ID })
+	for i, iss := range s.issues {
+		s.index[iss.ID] = i
+	}
+
+	n := len(s.issues)
+	s.blockers = make([]int, n)
+	s.dependents = make([][]int, n)
+	s.children = make([][]int, n)
+	addEdge := func(from, to int) { // from waits for to
+		s.blockers[from]++
+		s.dependents[to] = append(s.dependents[to], from)
+	}
+	for i, iss := range s.issues {
+		for _, dep := range iss.Dependencies {
+			if dep == nil {
+				continue
+			}
+			j, open := s.index[dep.DependsOnID]
+			if !open || j == i {
+				continue
+			}
+			switch {
+			case dep.Type.IsBlocking():
+				addEdge(i, j)
+			case dep.Type == model.DepParentChild:
+				// An epic is done when its children are; it carries no work of its own.
+				s.children[j] = append(s.children[j], i)
+				if s.issues[j].IssueType == model.TypeEpic {
+					addEdge(j, i)
+				}
+			}
+		}
+	}
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		x, y := s.issues[order[a]], s.issues[order[b]]
+		if (x.Status == model.StatusInProgress) != (y.Status == model.StatusInProgress) {
+			return x.Status == model.StatusInProgress
+		}
+		return x.Priority < y.Priority
+	})
+	s.rank = make([]int, n)
+	for r, i := range order {
+		s.rank[i] = r
+	}
+
+	s.samplers, s.historical, s.basis = buildCycleTimeSamplers(issues, stats, s.issues, s.children, opts.CycleTimes)
+	for i, iss := range s.issues {
+		if iss.Status != model.StatusInProgress {
+			continue
+		}
+		started, ok := opts.StartedAt[iss.ID]
+		if !ok {
+			started = iss.UpdatedAt
+		}
+		if started.IsZero() || !now.After(started) {
+			continue
+		}
+		sp := &s.samplers[i]
+		sp.elapsed = now.Sub(started).Hours() / 24
+		sp.from = sort.Search(len(sp.samples), func(k int) bool { return sp.samples[k] > sp.elapsed })
+	}
+	s.indeg = make([]int, n)
+	s.doneT = make([]float64, n)
+	return s
+}
+
+// buildCycleTimeSamplers picks, for each open issue, the most specific pool
+// of historical cycle times with enough samples. It also returns how many
+// closed issues were sampled and whether they gave cycle or lead times.
+func buildCycleTimeSamplers(all []model.Issue, stats *GraphStats, open []model.Issue, children [][]int, overrides map[string]time.Duration) ([]cycleTimeSampler, int, string) {
+	byLabelType := make(map[string][]float64)
+	byLabel := make(map[string][]float64)
+	byType := make(map[model.IssueType][]float64)
+	var global []float64
+	leadTimes := 0
+	for _, iss := range all {
+		if iss.Status != model.StatusClosed {
+			continue
+		}
+		d, ok := overrides[iss.ID]
+		if !ok {
+			if iss.ClosedAt == nil || iss.CreatedAt.IsZero() {
+				continue
+			}
+			d = iss.ClosedAt.Sub(iss.CreatedAt)
+		}
+		if d <= 0 {
+			continue
+		}
+		if !ok {
+			leadTimes++
+		}
+		days := max(d.Hours()/24, minSimulatedDurationDays)
+		global = append(global, days)
+		byType[iss.IssueType] = append(byType[iss.IssueType], days)
+		for _, l := range iss.Labels {
+			byLabel[l] = append(byLabel[l], days)
+			byLabelType[l+"\x00"+string(iss.IssueType)] = append(byLabelType[l+"\x00"+string(iss.IssueType)], days)
+		}
+	}
+	basis := ""
+	switch {
+	case len(global) == 0:
+	case leadTimes == 0:
+		basis = SampleBasisCycleTime
+	case leadTimes == len(global):
+		basis = SampleBasisLeadTime
+	default:
+		basis = SampleBasisMixed
+	}
+
+	// Pools are kept sorted so in_progress issues can sample the tail that
+	// outlasts their elapsed time.
+	sort.Float64s(global)
+	for _, pool := range byType {
+		sort.Float64s(pool)
+	}
+	pooled := func(keys []string, pools map[string][]float64) []float64 {
+		var out []float64
+		for _, k := range keys {
+			out = append(out, pools[k]...)
+		}
+		sort.Float64s(out)
+		return out
+	}
+	medianMinutes := computeMedianEstimatedMinutes(all)
+	samplers := make([]cycleTimeSampler, len(open))
+	for i, iss := range open {
+		if iss.IssueType == model.TypeEpic && len(children[i]) > 0 {
+			samplers[i] = cycleTimeSampler{source: "children"}
+			continue
+		}
+		labelKeys := make([]string, len(iss.Labels))
+		labelTypeKeys := make([]string, len(iss.Labels))
+		for k, l := range iss.Labels {
+			labelKeys[k] = l
+			labelTypeKeys[k] = l + "\x00" + string(iss.IssueType)
+		}
+		switch {
+		case len(pooled(labelTypeKeys, byLabelType)) >= minCycleTimeSamples:
+			samplers[i] = cycleTimeSampler{source: "label+type", samples: pooled(labelTypeKeys, byLabelType)}
+		case len(pooled(labelKeys, byLabel)) >= minCycleTimeSamples:
+			samplers[i] = cycleTimeSampler{source: "label", samples: pooled(labelKeys, byLabel)}
+		case len(byType[iss.IssueType]) >= minCycleTimeSamples:
+			samplers[i] = cycleTimeSampler{source: "type", samples: byType[iss.IssueType]}
+		case len(global) >= minCycleTimeSamples:
+			samplers[i] = cycleTimeSampler{source: "global", samples: global}
+		default:
+			minutes, _ := estimateComplexityMinutes(iss, stats, medianMinutes)
+			samplers[i] = cycleTimeSampler{source: "estimate", base: float64(minutes) / (8 * 60)} // 8h workday
+		}
+	}
+	return samplers, len(global), basis
+}
+
+// descendants lists the open parent-child descendants of issue i.
+func (s *forecastSim) descendants(i int) []int {
+	var out []int
+	seen := map[int]bool{i: true}
+	stack := append([]int(nil), s.children[i]...)
+	for len(stack) > 0 {
+		c := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if seen[c] {
+			continue
+		}
+		seen[c] = true
+		out = append(out, c)
+		stack = append(stack, s.children[c]...)
+	}
+	sort.Ints(out)
+	return out
+}
+
+// run schedules every open issue once and returns each issue's finish
+// time in days. The returned slice is reused by the next run.
+func (s *forecastSim) run(rng *rand.Rand) []float64 {
+	n := len(s.issues)
+	durations := make([]float64, n)
+	for i := range durations {
+		if s.samplers[i].source != "children" {
+			durations[i] = max(s.samplers[i].draw(rng), minSimulatedDurationDays)
+		}
+	}
+
+	copy(s.indeg, s.blockers)
+	s.ready = s.ready[:0]
+	s.busy = s.busy[:0]
+	for i := 0; i < n; i++ {
+		if s.indeg[i] == 0 {
+			heap.Push(&s.ready, readyItem{i, s.rank[i]})
+		}
+	}
+
+	finished := 0
+	finish := func(i int, t float64) {
+		s.doneT[i] = t
+		finished++
+		for _, d := range s.dependents[i] {
+			s.indeg[d]--
+			if s.indeg[d] == 0 {
+				heap.Push(&s.ready, readyItem{d, s.rank[d]})
+			}
+		}
+	}
+
+	free := s.agents
+	now := 0.0
+	for finished < n {
+		for s.ready.Len() > 0 {
+			next := s.ready[0].issue
+			if durations[next] == 0 {
+				heap.Pop(&s.ready)
+				finish(next, now)
+				continue
+			}
+			if free == 0 {
+				break
+			}
+			heap.Pop(&s.ready)
+			free--
+			heap.Push(&s.busy, busyItem{next, now + durations[next]})
+		}
+		if s.busy.Len() == 0 {
+			if finished < n {
+				// Only a dependency cycle can stall: start its first member.
+				for i := 0; i < n; i++ {
+					if s.indeg[i] > 0 {
+						s.indeg[i] = 0
+						heap.Push(&s.ready, readyItem{i, s.rank[i]})
+						break
+					}
+				}
+			}
+			continue
+		}
+		b := heap.Pop(&s.busy).(busyItem)
+		now = b.at
+		free++
+		finish(b.issue, now)
+	}
+
+	return s.doneT
+}
+
+type readyItem struct{ issue, rank int }
+
+type readyHeap []readyItem
+
+func (h readyHeap) Len() int           { return len(h) }
+func (h readyHeap) Less(i, j int) bool { return h[i].rank < h[j].rank }
+func (h readyHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *readyHeap) Push(x any)        { *h = append(*h, x.(readyItem)) }
+func (h *readyHeap) Pop() any {
+	old := *h
+	it := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return it
+}
+
+type busyItem struct {
+	issue int
+	at    float64
+}
+
+type finishHeap []busyItem
+
+func (h finishHeap) Len() int { return len(h) }
+func (h finishHeap) Less(i, j int) bool {
+	if h[i].at != h[j].at {
+		return h[i].at < h[j].at
+	}
+	return h[i].issue < h[j].issue
+}
+func (h finishHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *finishHeap) Push(x any)   { *h = append(*h, x.(busyItem)) }
+func (h *finishHeap) Pop() any {
+	old := *h
+	it := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return it
+}
+
+// percentileDates returns nearest-rank percentiles of values (days).
+func percentileDates(values []float64, percentiles []int, now time.Time) []PercentileDate {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	out := make([]PercentileDate, len(percentiles))
+	for k, p := range percentiles {
+		days := 0.0
+		if len(sorted) > 0 {
+			idx := int(math.Ceil(float64(p)/100*float64(len(sorted)))) - 1
+			days = sorted[max(0, min(idx, len(sorted)-1))]
+		}
+		out[k] = PercentileDate{Percentile: p, Days: math.Round(days*100) / 100, Date: now.Add(durationDays(days))}
+	}
+	return out
+}
+
+// histogram buckets values (days) into equal-width bins.
+func histogram(values []float64, bins int, now time.Time) []HistogramBin {
+	if len(values) == 0 {
+		return nil
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+	if hi == lo {
+		return []HistogramBin{{StartDays: lo, EndDays: hi, Start: now.Add(durationDays(lo)), End: now.Add(durationDays(hi)), Count: len(values)}}
+	}
+	width := (hi - lo) / float64(bins)
+	out := make([]HistogramBin, bins)
+	for b := range out {
+		start, end := lo+width*float64(b), lo+width*float64(b+1)
+		out[b] = HistogramBin{
+			StartDays: math.Round(start*100) / 100,
+			EndDays:   math.Round(end*100) / 100,
+			Start:     now.Add(durationDays(start)),
+			End:       now.Add(durationDays(end)),
+		}
+	}
+	for _, v := range values {
+		b := min(int((v-lo)/width), bins-1)
+		out[b].Count++
+	}
+	return out
+}
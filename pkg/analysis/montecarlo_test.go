@@ -0,0 +1,255 @@
+package analysis
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// closedWithCycle is a closed issue that took days from creation to close.
+func closedWithCycle(id string, typ model.IssueType, labels []string, days float64, now time.Time) model.Issue {
+	closed := now.Add(-24 * time.Hour)
+	return model.Issue{
+		ID: id, Title: id, Status: model.StatusClosed, IssueType: typ, Labels: labels,
+		CreatedAt: closed.Add(-time.Duration(days * float64(24*time.Hour))),
+		ClosedAt:  &closed,
+	}
+}
+
+func blockedBy(id string, blockers ...string) []*model.Dependency {
+	deps := make([]*model.Dependency, len(blockers))
+	for i, b := range blockers {
+		deps[i] = &model.Dependency{IssueID: id, DependsOnID: b, Type: model.DepBlocks}
+	}
+	return deps
+}
+
+func percentileDays(t *testing.T, ps []PercentileDate, p int) float64 {
+	t.Helper()
+	for _, pd := range ps {
+		if pd.Percentile == p {
+			return pd.Days
+		}
+	}
+	t.Fatalf("percentile %d missing from %+v", p, ps)
+	return 0
+}
+
+func TestForecastMonteCarlo_DeterministicSchedule(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	// Every historical task took exactly 2 days, so the schedule is fixed.
+	issues := []model.Issue{
+		closedWithCycle("h1", model.TypeTask, nil, 2, now),
+		closedWithCycle("h2", model.TypeTask, nil, 2, now),
+		closedWithCycle("h3", model.TypeTask, nil, 2, now),
+		{ID: "E", Title: "Epic", Status: model.StatusOpen, IssueType: model.TypeEpic},
+		{ID: "A", Title: "A", Status: model.StatusOpen, IssueType: model.TypeTask, Priority: 0,
+			Dependencies: []*model.Dependency{{IssueID: "A", DependsOnID: "E", Type: model.DepParentChild}}},
+		{ID: "B", Title: "B", Status: model.StatusOpen, IssueType: model.TypeTask, Priority: 0,
+			Dependencies: append(blockedBy("B", "A"), &model.Dependency{IssueID: "B", DependsOnID: "E", Type: model.DepParentChild})},
+		{ID: "C", Title: "C", Status: model.StatusOpen, IssueType: model.TypeTask, Priority: 2, Labels: []string{"docs"}},
+	}
+
+	res, err := ForecastMonteCarlo(issues, nil, MonteCarloOptions{Agents: 1, Runs: 50, Seed: 1}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// One agent: A (P0) 0-2, B 2-4, then C 4-6. The epic carries no work.
+	want := map[string]float64{"A": 2, "B": 4, "C": 6, "E": 4}
+	for _, f := range res.Issues {
+		if got := percentileDays(t, f.Percentiles, 95); got != want[f.IssueID] {
+			t.Errorf("%s P95 = %.2f days, want %.0f", f.IssueID, got, want[f.IssueID])
+		}
+	}
+	if len(res.Epics) != 1 || percentileDays(t, res.Epics[0].Percentiles, 50) != 4 || res.Epics[0].OpenIssues != 3 {
+		t.Errorf("epics = %+v", res.Epics)
+	}
+	if got := percentileDays(t, res.Completion.Percentiles, 50); got != 6 {
+		t.Errorf("completion P50 = %.2f, want 6", got)
+	}
+
+	// Two agents: C runs alongside A.
+	res, err = ForecastMonteCarlo(issues, nil, MonteCarloOptions{Agents: 2, Runs: 50, Seed: 1}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := percentileDays(t, res.Completion.Percentiles, 50); got != 4 {
+		t.Errorf("2 agents: completion P50 = %.2f, want 4", got)
+	}
+	if len(res.Labels) != 1 || res.Labels[0].Name != "docs" || percentileDays(t, res.Labels[0].Percentiles, 50) != 2 {
+		t.Errorf("labels = %+v", res.Labels)
+	}
+}
+
+func TestForecastMonteCarlo_SamplesMatchingPool(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	var issues []model.Issue
+	for i, days := range []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10} {
+		issues = append(issues, closedWithCycle(string(rune('a'+i)), model.TypeBug, []string{"api"}, days, now))
+	}
+	for i := 0; i < 3; i++ {
+		issues = append(issues, closedWithCycle(string(rune('x'+i)), model.TypeFeature, []string{"ui"}, 30, now))
+	}
+	issues = append(issues,
+		model.Issue{ID: "bug", Title: "Bug", Status: model.StatusOpen, IssueType: model.TypeBug, Labels: []string{"api"}},
+		model.Issue{ID: "feat", Title: "Feature", Status: model.StatusOpen, IssueType: model.TypeFeature, Labels: []string{"ui"}},
+		model.Issue{ID: "chore", Title: "Chore", Status: model.StatusOpen, IssueType: model.TypeChore},
+	)
+	sprints := []model.Sprint{{ID: "s1", Name: "Sprint 1", BeadIDs: []string{"bug", "feat"}}}
+
+	opts := MonteCarloOptions{Agents: 3, Runs: 2000, Seed: 7, Scope: map[string]bool{"bug": true, "chore": true}, Sprints: sprints}
+	res, err := ForecastMonteCarlo(issues, nil, opts, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byID := make(map[string]MonteCarloIssueForecast)
+	for _, f := range res.Issues {
+		byID[f.IssueID] = f
+	}
+	if len(res.Issues) != 2 || byID["feat"].IssueID != "" {
+		t.Fatalf("scope not applied: %+v", res.Issues)
+	}
+	bug := byID["bug"]
+	if bug.SampleFrom != "label+type" || bug.Samples != 10 {
+		t.Errorf("bug sampled from %s (%d)", bug.SampleFrom, bug.Samples)
+	}
+	if byID["chore"].SampleFrom != "global" {
+		t.Errorf("chore sampled from %s", byID["chore"].SampleFrom)
+	}
+	p50, p85, p95 := percentileDays(t, bug.Percentiles, 50), percentileDays(t, bug.Percentiles, 85), percentileDays(t, bug.Percentiles, 95)
+	if !(p50 <= p85 && p85 <= p95) || p50 < 4 || p50 > 7 || p95 < 9 {
+		t.Errorf("bug percentiles = %.1f / %.1f / %.1f", p50, p85, p95)
+	}
+
+	// The sprint holds a scoped issue, so it is reported over all its open issues.
+	if len(res.Sprints) != 1 || res.Sprints[0].OpenIssues != 2 || percentileDays(t, res.Sprints[0].Percentiles, 50) != 30 {
+		t.Errorf("sprints = %+v", res.Sprints)
+	}
+
+	total := 0
+	for _, b := range res.Histogram {
+		total += b.Count
+	}
+	if total != opts.Runs || len(res.Histogram) != DefaultHistogramBins {
+		t.Errorf("histogram covers %d runs in %d bins", total, len(res.Histogram))
+	}
+
+	again, err := ForecastMonteCarlo(issues, nil, opts, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(res, again) {
+		t.Error("same seed produced a different forecast")
+	}
+}
+
+func TestForecastMonteCarlo_NoHistoryAndCycles(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	issues := []model.Issue{
+		{ID: "A", Title: "A", Status: model.StatusOpen, IssueType: model.TypeTask, Dependencies: blockedBy("A", "B")},
+		{ID: "B", Title: "B", Status: model.StatusOpen, IssueType: model.TypeTask, Dependencies: blockedBy("B", "A")},
+	}
+	res, err := ForecastMonteCarlo(issues, nil, MonteCarloOptions{Runs: 100, Percentiles: []int{50}}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Issues) != 2 || res.Issues[0].SampleFrom != "estimate" || percentileDays(t, res.Completion.Percentiles, 50) <= 0 {
+		t.Fatalf("forecast = %+v", res)
+	}
+
+	if _, err := ForecastMonteCarlo(issues, nil, MonteCarloOptions{Percentiles: []int{0}}, now); err == nil {
+		t.Error("expected an error for percentile 0")
+	}
+	if _, err := ForecastMonteCarlo(issues, nil, MonteCarloOptions{Scope: map[string]bool{"nope": true}}, now); err == nil {
+		t.Error("expected an error for an unknown issue")
+	}
+}
+
+func TestForecastMonteCarlo_InProgressElapsedAndBasis(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	issues := []model.Issue{
+		closedWithCycle("h1", model.TypeTask, nil, 2, now),
+		closedWithCycle("h2", model.TypeTask, nil, 5, now),
+		closedWithCycle("h3", model.TypeTask, nil, 10, now),
+		{ID: "W", Title: "Started", Status: model.StatusInProgress, IssueType: model.TypeTask},
+		{ID: "L", Title: "Late", Status: model.StatusInProgress, IssueType: model.TypeTask},
+	}
+	opts := MonteCarloOptions{
+		Agents:     2,
+		Runs:       200,
+		Seed:       3,
+		CycleTimes: map[string]time.Duration{"h1": 48 * time.Hour},
+		StartedAt:  map[string]time.Time{"W": now.Add(-4 * 24 * time.Hour), "L": now.Add(-20 * 24 * time.Hour)},
+	}
+	res, err := ForecastMonteCarlo(issues, nil, opts, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.SampleBasis != SampleBasisMixed {
+		t.Errorf("sample basis = %q, want mixed", res.SampleBasis)
+	}
+	byID := make(map[string]MonteCarloIssueForecast)
+	for _, f := range res.Issues {
+		byID[f.IssueID] = f
+	}
+	// Four days in, only the 5- and 10-day histories are still possible.
+	w := byID["W"]
+	if w.ElapsedDays != 4 {
+		t.Errorf("W elapsed = %.2f days, want 4", w.ElapsedDays)
+	}
+	if lo, hi := percentileDays(t, w.Percentiles, 50), percentileDays(t, w.Percentiles, 95); lo < 1 || hi > 6 {
+		t.Errorf("W percentiles = %.2f..%.2f, want within 1..6 days", lo, hi)
+	}
+	// Past every historical cycle time: due now.
+	if got := percentileDays(t, byID["L"].Percentiles, 95); got != 0 {
+		t.Errorf("L P95 = %.2f days, want 0", got)
+	}
+
+	delete(opts.CycleTimes, "h1")
+	if res, _ := ForecastMonteCarlo(issues, nil, opts, now); res.SampleBasis != SampleBasisLeadTime {
+		t.Errorf("sample basis = %q, want lead_time", res.SampleBasis)
+	}
+}
+
+func TestForecastMonteCarlo_BoundsKeptRuns(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	var issues []model.Issue
+	for i, days := range []float64{1, 2, 3, 4, 5, 6} {
+		issues = append(issues, closedWithCycle(string(rune('a'+i)), model.TypeTask, nil, days, now))
+	}
+	issues = append(issues, model.Issue{ID: "T", Title: "T", Status: model.StatusOpen, IssueType: model.TypeTask, Labels: []string{"x"}})
+
+	opts := MonteCarloOptions{Runs: 3 * forecastReservoirSize, Seed: 5}
+	res, err := ForecastMonteCarlo(issues, nil, opts, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Percentiles of the kept runs still land on the historical values.
+	p50, p95 := percentileDays(t, res.Issues[0].Percentiles, 50), percentileDays(t, res.Issues[0].Percentiles, 95)
+	if p50 < 3 || p50 > 4 || p95 != 6 {
+		t.Errorf("T percentiles = %.2f / %.2f", p50, p95)
+	}
+	total := 0
+	for _, b := range res.Histogram {
+		total += b.Count
+	}
+	if total != opts.Runs {
+		t.Errorf("histogram covers %d runs, want all %d", total, opts.Runs)
+	}
+	again, _ := ForecastMonteCarlo(issues, nil, opts, now)
+	if !reflect.DeepEqual(res, again) {
+		t.Error("same seed produced a different forecast")
+	}
+
+	r := newReservoir(10)
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		r.add(float64(i), rng)
+	}
+	if len(r.values) != 10 || r.seen != 100 {
+		t.Errorf("reservoir kept %d of %d values, want 10", len(r.values), r.seen)
+	}
+}
@@ -0,0 +1,180 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// PriorityInversion is an open blocker with a lower priority (a higher P
+// number) than the most important work it transitively blocks. As with
+// priority inheritance in schedulers, the fix is for the blocker to take on
+// that work's priority until it is done.
+type PriorityInversion struct {
+	BlockerID         string   `json:"blocker_id"`
+	BlockerTitle      string   `json:"blocker_title"`
+	BlockerPriority   int      `json:"blocker_priority"`
+	BlockerActionable bool     `json:"blocker_actionable"` // No open blockers of its own
+	BlockedID         string   `json:"blocked_id"`         // Highest-priority issue it holds up
+	BlockedTitle      string   `json:"blocked_title"`
+	BlockedPriority   int      `json:"blocked_priority"`
+	InheritedPriority int      `json:"inherited_priority"` // Proposed priority for the blocker
+	Chain             []string `json:"chain"`              // BlockedID, its blocker, ..., BlockerID
+	BlockedCount      int      `json:"blocked_count"`      // Open issues it transitively blocks
+}
+
+// Gap is how many priority levels the blocker would be raised.
+func (p PriorityInversion) Gap() int {
+	return p.BlockerPriority - p.InheritedPriority
+}
+
+// DetectPriorityInversions walks every open issue's blocker chains and
+// reports each open blocker whose priority is lower than the highest
+// priority it holds up. Each blocker appears once, against its most
+// important blocked issue (shortest chain on ties). Deferred issues do not
+// pass their priority on. Results are ordered by inherited priority, then
+// largest gap.
+func DetectPriorityInversions(issues []model.Issue) []PriorityInversion {
+	ctx := NewTriageContext(NewAnalyzer(issues))
+	byID := make(map[string]*model.Issue, len(issues))
+	var targets []*model.Issue
+	for i := range issues {
+		iss := &issues[i]
+		byID[iss.ID] = iss
+		if !isClosedLikeStatus(iss.Status) && iss.Status != model.StatusDeferred {
+			targets = append(targets, iss)
+		}
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].Priority != targets[j].Priority {
+			return targets[i].Priority < targets[j].Priority
+		}
+		return targets[i].ID < targets[j].ID
+	})
+
+	best := make(map[string]PriorityInversion)
+	blockedBy := make(map[string]map[string]bool) // blocker -> issues it holds up
+	for _, target := range targets {
+		// Breadth-first over open blockers so each chain is a shortest one.
+		next := map[string]string{target.ID: ""}
+		queue := []string{target.ID}
+		for len(queue) > 0 {
+			id := queue[0]
+			queue = queue[1:]
+			for _, b := range ctx.OpenBlockers(id) {
+				if _, seen := next[b]; seen {
+					continue
+				}
+				next[b] = id
+				queue = append(queue, b)
+				if blockedBy[b] == nil {
+					blockedBy[b] = make(map[string]bool)
+				}
+				blockedBy[b][target.ID] = true
+
+				blocker := byID[b]
+				if blocker == nil || blocker.Priority <= target.Priority {
+					continue
+				}
+				var chain []string
+				for at := b; at != ""; at = next[at] {
+					chain = append(chain, at)
+				}
+				for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+					chain[i], chain[j] = chain[j], chain[i]
+				}
+				// Targets come most important first, so the first
+				// inversion found for a blocker wins unless a later one
+				// of equal priority has a shorter chain.
+				if prev, ok := best[b]; ok && (prev.InheritedPriority < target.Priority || len(prev.Chain) <= len(chain)) {
+					continue
+				}
+				best[b] = PriorityInversion{
+					BlockerID:         b,
+					BlockerTitle:      blocker.Title,
+					BlockerPriority:   blocker.Priority,
+					BlockerActionable: ctx.BlockerDepth(b) == 0,
+					BlockedID:         target.ID,
+					BlockedTitle:      target.Title,
+					BlockedPriority:   target.Priority,
+					InheritedPriority: target.Priority,
+					Chain:             chain,
+				}
+			}
+		}
+	}
+
+	inversions := make([]PriorityInversion, 0, len(best))
+	for id, inv := range best {
+		inv.BlockedCount = len(blockedBy[id])
+		inversions = append(inversions, inv)
+	}
+	sort.Slice(inversions, func(i, j int) bool {
+		a, b := inversions[i], inversions[j]
+		if a.InheritedPriority != b.InheritedPriority {
+			return a.InheritedPriority < b.InheritedPriority
+		}
+		if a.Gap() != b.Gap() {
+			return a.Gap() > b.Gap()
+		}
+		return a.BlockerID < b.BlockerID
+	})
+	return inversions
+}
+
+// PriorityInversionConfig configures priority inversion suggestions
+type PriorityInversionConfig struct {
+	// MaxSuggestions is the maximum number of inversions to report
+	// Default: 20
+	MaxSuggestions int
+}
+
+// DefaultPriorityInversionConfig returns sensible defaults
+func DefaultPriorityInversionConfig() PriorityInversionConfig {
+	return PriorityInversionConfig{
+		MaxSuggestions: 20,
+	}
+}
+
+// DetectPriorityInversionSuggestions proposes raising each inverted blocker
+// to the priority it inherits. Confidence grows with the priority gap and
+// is highest for direct blockers.
+func DetectPriorityInversionSuggestions(issues []model.Issue, config PriorityInversionConfig) []Suggestion {
+	var suggestions []Suggestion
+	for i, inv := range DetectPriorityInversions(issues) {
+		if config.MaxSuggestions > 0 && i >= config.MaxSuggestions {
+			break
+		}
+		hops := len(inv.Chain) - 1
+		confidence := 0.5 + 0.15*float64(inv.Gap()) - 0.05*float64(hops-1)
+		confidence = min(max(confidence, 0.4), 1.0)
+
+		summary := fmt.Sprintf("P%d %s blocks P%d %s", inv.BlockerPriority, inv.BlockerID, inv.BlockedPriority, inv.BlockedID)
+		reason := fmt.Sprintf("Blocker chain: %s. Raising %s to P%d lets it inherit the priority of the work it holds up",
+			formatBlockerChain(inv.Chain), inv.BlockerID, inv.InheritedPriority)
+		if inv.BlockedCount > 1 {
+			reason += fmt.Sprintf(" (%d open issues wait on it)", inv.BlockedCount)
+		}
+
+		suggestions = append(suggestions, NewSuggestion(
+			SuggestionPriorityInversion,
+			inv.BlockerID,
+			summary,
+			reason,
+			confidence,
+		).WithRelatedBead(inv.BlockedID).
+			WithAction(fmt.Sprintf("br update %s --priority=%d", inv.BlockerID, inv.InheritedPriority)).
+			WithMetadata("current_priority", inv.BlockerPriority).
+			WithMetadata("inherited_priority", inv.InheritedPriority).
+			WithMetadata("chain", inv.Chain).
+			WithMetadata("blocked_count", inv.BlockedCount))
+	}
+	return suggestions
+}
+
+// formatBlockerChain renders a chain as "blocked ← blocker ← ...".
+func formatBlockerChain(chain []string) string {
+	return strings.Join(chain, " ← ")
+}
@@ -0,0 +1,69 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestDetectPriorityInversions(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "ship", Title: "Ship", Status: model.StatusOpen, Priority: 0, Dependencies: blockedBy("ship", "api", "old")},
+		{ID: "api", Title: "API", Status: model.StatusInProgress, Priority: 1, Dependencies: blockedBy("api", "chore")},
+		{ID: "chore", Title: "Chore", Status: model.StatusOpen, Priority: 3},
+		{ID: "old", Title: "Done", Status: model.StatusClosed, Priority: 4},
+		{ID: "docs", Title: "Docs", Status: model.StatusOpen, Priority: 2, Dependencies: blockedBy("docs", "chore", "lint")},
+		{ID: "lint", Title: "Lint", Status: model.StatusOpen, Priority: 2},
+		{ID: "later", Title: "Someday", Status: model.StatusDeferred, Priority: 0, Dependencies: blockedBy("later", "nit")},
+		{ID: "nit", Title: "Nit", Status: model.StatusOpen, Priority: 4},
+		{ID: "x", Title: "x", Status: model.StatusOpen, Priority: 1, Dependencies: blockedBy("x", "y")},
+		{ID: "y", Title: "y", Status: model.StatusOpen, Priority: 2, Dependencies: blockedBy("y", "x")},
+	}
+
+	inversions := DetectPriorityInversions(issues)
+	var got []string
+	for _, inv := range inversions {
+		got = append(got, inv.BlockerID)
+	}
+	// chore and api inherit P0 (chore's gap is larger), y inherits P1 through
+	// the cycle. Closed blockers, equal priorities and deferred work do not count.
+	if strings.Join(got, ",") != "chore,api,y" {
+		t.Fatalf("inversions = %v, want [chore api y]", got)
+	}
+
+	chore := inversions[0]
+	if chore.BlockedID != "ship" || chore.InheritedPriority != 0 || chore.Gap() != 3 {
+		t.Errorf("chore = %+v", chore)
+	}
+	if strings.Join(chore.Chain, ",") != "ship,api,chore" || chore.BlockedCount != 3 || !chore.BlockerActionable {
+		t.Errorf("chore chain %v, blocks %d, actionable %v", chore.Chain, chore.BlockedCount, chore.BlockerActionable)
+	}
+	if api := inversions[1]; api.BlockerActionable || len(api.Chain) != 2 {
+		t.Errorf("api = %+v", api)
+	}
+}
+
+func TestDetectPriorityInversionSuggestions(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "ship", Title: "Ship", Status: model.StatusOpen, Priority: 0, Dependencies: blockedBy("ship", "chore")},
+		{ID: "chore", Title: "Chore", Status: model.StatusOpen, Priority: 3},
+	}
+	sugs := DetectPriorityInversionSuggestions(issues, DefaultPriorityInversionConfig())
+	if len(sugs) != 1 {
+		t.Fatalf("suggestions = %+v", sugs)
+	}
+	s := sugs[0]
+	if s.Type != SuggestionPriorityInversion || s.TargetBead != "chore" || s.RelatedBead != "ship" {
+		t.Errorf("suggestion = %+v", s)
+	}
+	if s.ActionCommand != "br update chore --priority=0" || s.Confidence != 0.95 {
+		t.Errorf("action %q, confidence %v", s.ActionCommand, s.Confidence)
+	}
+
+	config := DefaultSuggestAllConfig()
+	config.FilterType = SuggestionPriorityInversion
+	if set := GenerateAllSuggestions(issues, config, ""); set.Stats.ByType[SuggestionPriorityInversion] != 1 || set.Stats.Total != 1 {
+		t.Errorf("GenerateAllSuggestions stats = %+v", set.Stats)
+	}
+}
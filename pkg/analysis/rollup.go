@@ -0,0 +1,288 @@
+package analysis
+
+import (
+	"sort"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// Epic rollups.
+//
+// Parent-child dependencies form a hierarchy (epic → feature → task) that
+// the tree view draws but nothing else aggregates. ComputeEpicRollups walks
+// it bottom-up and reports, for every issue that has children, how much of
+// the work beneath it is done, what remains, and what is blocked or ready.
+// It also flags two hygiene problems:
+//
+//   - zombie: the parent is still open although every child is closed
+//   - orphan_progress: work was closed under a parent that is deferred
+
+// Rollup flags.
+const (
+	RollupFlagZombie         = "zombie"
+	RollupFlagOrphanProgress = "orphan_progress"
+)
+
+// EpicRollup aggregates the descendants of one parent issue.
+type EpicRollup struct {
+	ID        string          `json:"id"`
+	Title     string          `json:"title"`
+	Status    model.Status    `json:"status"`
+	IssueType model.IssueType `json:"issue_type"`
+	Depth     int             `json:"depth"`               // 0 for top-level parents
+	ParentID  string          `json:"parent_id,omitempty"` // First existing parent, if nested
+
+	Children         int     `json:"children"`          // Direct children
+	Descendants      int     `json:"descendants"`       // All issues beneath, at any depth
+	Closed           int     `json:"closed"`            // Closed descendants
+	PercentComplete  float64 `json:"percent_complete"`  // Closed / Descendants × 100
+	RemainingMinutes int     `json:"remaining_minutes"` // Estimated minutes of open descendants
+	UnestimatedOpen  int     `json:"unestimated_open"`  // Open descendants counted at the median estimate
+	InProgress       int     `json:"in_progress"`
+	Blocked          int     `json:"blocked"`    // Open descendants waiting on an open blocker
+	Actionable       int     `json:"actionable"` // Open descendants that can start now
+
+	Flags []string `json:"flags,omitempty"`
+}
+
+// HasFlag reports whether the rollup carries the given flag.
+func (r EpicRollup) HasFlag(flag string) bool {
+	for _, f := range r.Flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// RollupSummary totals a rollup report.
+type RollupSummary struct {
+	Parents          int     `json:"parents"`
+	OpenParents      int     `json:"open_parents"`
+	PercentComplete  float64 `json:"percent_complete"` // Across all issues under a top-level parent
+	RemainingMinutes int     `json:"remaining_minutes"`
+	Zombies          int     `json:"zombies"`
+	OrphanProgress   int     `json:"orphan_progress"`
+}
+
+// RollupReport is the result of ComputeEpicRollups. Epics are in tree
+// order: each parent is followed by its nested parents.
+type RollupReport struct {
+	Epics          []EpicRollup  `json:"epics"`
+	Zombies        []string      `json:"zombies"`
+	OrphanProgress []string      `json:"orphan_progress"`
+	Summary        RollupSummary `json:"summary"`
+}
+
+// ByID indexes the report's rollups by issue ID.
+func (r RollupReport) ByID() map[string]EpicRollup {
+	m := make(map[string]EpicRollup, len(r.Epics))
+	for _, e := range r.Epics {
+		m[e.ID] = e
+	}
+	return m
+}
+
+// ComputeEpicRollups aggregates progress up the parent-child hierarchy.
+// An issue with several parents counts toward each of them. Tombstoned
+// issues are ignored, and cycles in the hierarchy are cut.
+func ComputeEpicRollups(issues []model.Issue) RollupReport {
+	report := RollupReport{Epics: []EpicRollup{}, Zombies: []string{}, OrphanProgress: []string{}}
+
+	byID := make(map[string]*model.Issue, len(issues))
+	for i := range issues {
+		if issues[i].Status == model.StatusTombstone {
+			continue
+		}
+		byID[issues[i].ID] = &issues[i]
+	}
+
+	children := make(map[string][]string)
+	parentOf := make(map[string]string)
+	for i := range issues {
+		issue := &issues[i]
+		if byID[issue.ID] != issue {
+			continue
+		}
+		for _, dep := range issue.Dependencies {
+			if dep == nil || dep.Type != model.DepParentChild || dep.DependsOnID == issue.ID {
+				continue
+			}
+			if _, ok := byID[dep.DependsOnID]; !ok {
+				continue
+			}
+			children[dep.DependsOnID] = append(children[dep.DependsOnID], issue.ID)
+			if _, ok := parentOf[issue.ID]; !ok {
+				parentOf[issue.ID] = dep.DependsOnID
+			}
+		}
+	}
+	if len(children) == 0 {
+		return report
+	}
+	for id := range children {
+		sortRollupIDs(children[id], byID)
+	}
+
+	median := computeMedianEstimatedMinutes(issues)
+	blocked := func(issue *model.Issue) bool {
+		if issue.Status == model.StatusBlocked {
+			return true
+		}
+		for _, dep := range issue.Dependencies {
+			if dep == nil || !dep.Type.IsBlocking() {
+				continue
+			}
+			if blocker, ok := byID[dep.DependsOnID]; ok && !isClosedLikeStatus(blocker.Status) {
+				return true
+			}
+		}
+		return false
+	}
+
+	rollup := func(id string, depth int) EpicRollup {
+		issue := byID[id]
+		r := EpicRollup{
+			ID:        id,
+			Title:     issue.Title,
+			Status:    issue.Status,
+			IssueType: issue.IssueType,
+			Depth:     depth,
+			ParentID:  parentOf[id],
+			Children:  len(children[id]),
+		}
+
+		// Collect each descendant once, even through diamonds or cycles.
+		seen := map[string]bool{id: true}
+		stack := append([]string(nil), children[id]...)
+		for len(stack) > 0 {
+			cid := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if seen[cid] {
+				continue
+			}
+			seen[cid] = true
+			stack = append(stack, children[cid]...)
+
+			child := byID[cid]
+			r.Descendants++
+			if isClosedLikeStatus(child.Status) {
+				r.Closed++
+				continue
+			}
+			if len(children[cid]) == 0 {
+				// Nested parents contribute through their own children.
+				if child.EstimatedMinutes != nil && *child.EstimatedMinutes > 0 {
+					r.RemainingMinutes += *child.EstimatedMinutes
+				} else {
+					r.RemainingMinutes += median
+					r.UnestimatedOpen++
+				}
+			}
+			switch {
+			case blocked(child):
+				r.Blocked++
+			case child.Status == model.StatusDeferred:
+			default:
+				r.Actionable++
+			}
+			if child.Status == model.StatusInProgress {
+				r.InProgress++
+			}
+		}
+		if r.Descendants > 0 {
+			r.PercentComplete = roundPercent(float64(r.Closed) / float64(r.Descendants) * 100)
+		}
+
+		allChildrenClosed := true
+		for _, cid := range children[id] {
+			if !isClosedLikeStatus(byID[cid].Status) {
+				allChildrenClosed = false
+				break
+			}
+		}
+		if !isClosedLikeStatus(issue.Status) && issue.Status != model.StatusDeferred && allChildrenClosed {
+			r.Flags = append(r.Flags, RollupFlagZombie)
+		}
+		if issue.Status == model.StatusDeferred && r.Closed > 0 {
+			r.Flags = append(r.Flags, RollupFlagOrphanProgress)
+		}
+		return r
+	}
+
+	// Walk from top-level parents so the report reads like the tree.
+	var roots []string
+	for id := range children {
+		if _, nested := parentOf[id]; !nested {
+			roots = append(roots, id)
+		}
+	}
+	sortRollupIDs(roots, byID)
+
+	emitted := make(map[string]bool)
+	var walk func(id string, depth int)
+	walk = func(id string, depth int) {
+		if emitted[id] || len(children[id]) == 0 {
+			return
+		}
+		emitted[id] = true
+		report.Epics = append(report.Epics, rollup(id, depth))
+		for _, cid := range children[id] {
+			walk(cid, depth+1)
+		}
+	}
+	for _, id := range roots {
+		walk(id, 0)
+	}
+	// Parents reachable only through a cycle have no root above them.
+	var rest []string
+	for id := range children {
+		if !emitted[id] {
+			rest = append(rest, id)
+		}
+	}
+	sortRollupIDs(rest, byID)
+	for _, id := range rest {
+		walk(id, 0)
+	}
+
+	var closed, total int
+	for _, e := range report.Epics {
+		report.Summary.Parents++
+		if !isClosedLikeStatus(e.Status) {
+			report.Summary.OpenParents++
+		}
+		if e.HasFlag(RollupFlagZombie) {
+			report.Zombies = append(report.Zombies, e.ID)
+		}
+		if e.HasFlag(RollupFlagOrphanProgress) {
+			report.OrphanProgress = append(report.OrphanProgress, e.ID)
+		}
+		if e.Depth == 0 {
+			closed += e.Closed
+			total += e.Descendants
+			report.Summary.RemainingMinutes += e.RemainingMinutes
+		}
+	}
+	if total > 0 {
+		report.Summary.PercentComplete = roundPercent(float64(closed) / float64(total) * 100)
+	}
+	report.Summary.Zombies = len(report.Zombies)
+	report.Summary.OrphanProgress = len(report.OrphanProgress)
+	return report
+}
+
+// sortRollupIDs orders issues by priority, then ID.
+func sortRollupIDs(ids []string, byID map[string]*model.Issue) {
+	sort.SliceStable(ids, func(i, j int) bool {
+		a, b := byID[ids[i]], byID[ids[j]]
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		return a.ID < b.ID
+	})
+}
+
+func roundPercent(p float64) float64 {
+	return float64(int(p*10+0.5)) / 10
+}
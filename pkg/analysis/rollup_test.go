@@ -0,0 +1,91 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func childOf(id, parent string, extra ...*model.Dependency) []*model.Dependency {
+	return append([]*model.Dependency{{IssueID: id, DependsOnID: parent, Type: model.DepParentChild}}, extra...)
+}
+
+func TestComputeEpicRollups(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "E", Title: "Epic", Status: model.StatusOpen, IssueType: model.TypeEpic},
+		{ID: "F", Title: "Feature", Status: model.StatusOpen, Dependencies: childOf("F", "E")},
+		{ID: "a", Title: "a", Status: model.StatusClosed, EstimatedMinutes: minutes(60), Dependencies: childOf("a", "F")},
+		{ID: "b", Title: "b", Status: model.StatusInProgress, EstimatedMinutes: minutes(120), Dependencies: childOf("b", "F")},
+		{ID: "c", Title: "c", Status: model.StatusOpen, Dependencies: childOf("c", "F", blockedBy("c", "b")...)},
+		{ID: "d", Title: "d", Status: model.StatusClosed, Dependencies: childOf("d", "E")},
+		{ID: "Z", Title: "Zombie", Status: model.StatusOpen, IssueType: model.TypeEpic},
+		{ID: "z1", Title: "z1", Status: model.StatusClosed, Dependencies: childOf("z1", "Z")},
+		{ID: "D", Title: "Deferred", Status: model.StatusDeferred, IssueType: model.TypeEpic},
+		{ID: "d1", Title: "d1", Status: model.StatusClosed, Dependencies: childOf("d1", "D")},
+		{ID: "d2", Title: "d2", Status: model.StatusOpen, Dependencies: childOf("d2", "D")},
+		{ID: "lone", Title: "No children", Status: model.StatusOpen},
+	}
+
+	report := ComputeEpicRollups(issues)
+	var order []string
+	for _, e := range report.Epics {
+		order = append(order, e.ID)
+	}
+	if len(order) != 4 || order[0] != "D" || order[1] != "E" || order[2] != "F" || order[3] != "Z" {
+		t.Fatalf("epics in order %v, want [D E F Z]", order)
+	}
+
+	byID := report.ByID()
+	e := byID["E"]
+	// E: F, a, b, c, d → 2 of 5 closed; b (120) + c (median 90) remain.
+	if e.Children != 2 || e.Descendants != 5 || e.Closed != 2 || e.PercentComplete != 40 {
+		t.Errorf("E = %+v", e)
+	}
+	if e.RemainingMinutes != 210 || e.UnestimatedOpen != 1 {
+		t.Errorf("E remaining = %d (%d unestimated), want 210 (1)", e.RemainingMinutes, e.UnestimatedOpen)
+	}
+	if e.Blocked != 1 || e.Actionable != 2 || e.InProgress != 1 || len(e.Flags) != 0 {
+		t.Errorf("E counts = %+v", e)
+	}
+	if f := byID["F"]; f.Depth != 1 || f.ParentID != "E" || f.PercentComplete != 33.3 {
+		t.Errorf("F = %+v", f)
+	}
+
+	if !byID["Z"].HasFlag(RollupFlagZombie) || byID["Z"].PercentComplete != 100 {
+		t.Errorf("Z = %+v", byID["Z"])
+	}
+	if d := byID["D"]; !d.HasFlag(RollupFlagOrphanProgress) || d.HasFlag(RollupFlagZombie) || d.Actionable != 1 {
+		t.Errorf("D = %+v", d)
+	}
+	if len(report.Zombies) != 1 || len(report.OrphanProgress) != 1 {
+		t.Errorf("zombies %v, orphan progress %v", report.Zombies, report.OrphanProgress)
+	}
+	if s := report.Summary; s.Parents != 4 || s.OpenParents != 4 || s.Zombies != 1 || s.PercentComplete != 50 {
+		t.Errorf("summary = %+v", s)
+	}
+}
+
+func TestComputeEpicRollups_CyclesAndDiamonds(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "A", Title: "A", Status: model.StatusOpen, Dependencies: childOf("A", "B")},
+		{ID: "B", Title: "B", Status: model.StatusOpen, Dependencies: childOf("B", "A")},
+		{ID: "P", Title: "P", Status: model.StatusOpen},
+		{ID: "Q", Title: "Q", Status: model.StatusOpen, Dependencies: childOf("Q", "P")},
+		{ID: "R", Title: "R", Status: model.StatusOpen, Dependencies: childOf("R", "P")},
+		{ID: "x", Title: "x", Status: model.StatusClosed, Dependencies: append(childOf("x", "Q"), childOf("x", "R")...)},
+	}
+	report := ComputeEpicRollups(issues)
+	byID := report.ByID()
+	if len(report.Epics) != 5 {
+		t.Fatalf("epics = %+v", report.Epics)
+	}
+	if a := byID["A"]; a.Descendants != 1 {
+		t.Errorf("A counts itself through the cycle: %+v", a)
+	}
+	if p := byID["P"]; p.Descendants != 3 || p.Closed != 1 {
+		t.Errorf("P counts x twice: %+v", p)
+	}
+	if !byID["Q"].HasFlag(RollupFlagZombie) || !byID["R"].HasFlag(RollupFlagZombie) || byID["P"].HasFlag(RollupFlagZombie) {
+		t.Errorf("zombies = %v", report.Zombies)
+	}
+}
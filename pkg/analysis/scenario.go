@@ -0,0 +1,552 @@
+package analysis
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// Batch what-if simulation.
+//
+// computeWhatIfDelta answers "what if this one issue were closed". A
+// scenario is a list of hypothetical edits (close or reopen issues, add or
+// remove dependencies, change priorities, add agents) applied to a copy of
+// the issues. SimulateScenarios reports plan-level metrics for the current
+// state and for every scenario, so several plans can be compared side by
+// side. Scenarios compose: one may extend another and add edits on top.
+
+// Scenario operation kinds.
+const (
+	ScenarioOpClose       = "close"
+	ScenarioOpReopen      = "reopen"
+	ScenarioOpAddDep      = "add_dep"
+	ScenarioOpRemoveDep   = "remove_dep"
+	ScenarioOpSetPriority = "set_priority"
+	ScenarioOpAddAgent    = "add_agent"
+)
+
+// ScenarioOp is one hypothetical edit.
+type ScenarioOp struct {
+	Op        string               `json:"op"`
+	ID        string               `json:"id,omitempty"`         // Issue edited (every op but add_agent)
+	DependsOn string               `json:"depends_on,omitempty"` // add_dep/remove_dep: the other end of the edge
+	Type      model.DependencyType `json:"type,omitempty"`       // add_dep: default blocks; remove_dep: any type when empty
+	Priority  *int                 `json:"priority,omitempty"`   // set_priority
+	Count     int                  `json:"count,omitempty"`      // add_agent: default 1
+}
+
+// Scenario is a named plan. Extends names another scenario in the same file
+// whose operations are applied first.
+type Scenario struct {
+	Name    string       `json:"name"`
+	Extends string       `json:"extends,omitempty"`
+	Ops     []ScenarioOp `json:"ops"`
+}
+
+// ScenarioFile is the --robot-whatif input:
+//
+//	{"scenarios": [
+//	  {"name": "ship-auth", "ops": [
+//	    {"op": "close", "id": "bv-12"},
+//	    {"op": "remove_dep", "id": "bv-20", "depends_on": "bv-14"}]},
+//	  {"name": "ship-auth+agents", "extends": "ship-auth", "ops": [
+//	    {"op": "add_agent", "count": 2}]}
+//	]}
+//
+// A single scenario object, or a bare list of operations, is also accepted.
+type ScenarioFile struct {
+	Scenarios []Scenario `json:"scenarios"`
+}
+
+// LoadScenarioFile reads and validates a scenario file.
+func LoadScenarioFile(path string) (ScenarioFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ScenarioFile{}, fmt.Errorf("reading scenarios: %w", err)
+	}
+	file, err := ParseScenarioFile(data)
+	if err != nil {
+		return file, fmt.Errorf("%s: %w", path, err)
+	}
+	return file, nil
+}
+
+// ParseScenarioFile decodes and validates scenario JSON. Unnamed scenarios
+// are called scenario-1, scenario-2, ...
+func ParseScenarioFile(data []byte) (ScenarioFile, error) {
+	var file ScenarioFile
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case len(trimmed) == 0:
+		return file, fmt.Errorf("no scenarios")
+	case trimmed[0] == '[':
+		var ops []ScenarioOp
+		if err := json.Unmarshal(trimmed, &ops); err != nil {
+			return file, fmt.Errorf("parsing scenarios: %w", err)
+		}
+		file.Scenarios = []Scenario{{Ops: ops}}
+	default:
+		var raw struct {
+			Scenarios []Scenario `json:"scenarios"`
+			Scenario
+		}
+		if err := json.Unmarshal(trimmed, &raw); err != nil {
+			return file, fmt.Errorf("parsing scenarios: %w", err)
+		}
+		file.Scenarios = raw.Scenarios
+		if len(file.Scenarios) == 0 && len(raw.Ops) > 0 {
+			file.Scenarios = []Scenario{raw.Scenario}
+		}
+	}
+	if len(file.Scenarios) == 0 {
+		return file, fmt.Errorf("no scenarios")
+	}
+
+	names := make(map[string]bool, len(file.Scenarios))
+	for i := range file.Scenarios {
+		sc := &file.Scenarios[i]
+		if sc.Name == "" {
+			sc.Name = fmt.Sprintf("scenario-%d", i+1)
+		}
+		if names[sc.Name] {
+			return file, fmt.Errorf("duplicate scenario %q", sc.Name)
+		}
+		names[sc.Name] = true
+		for j, op := range sc.Ops {
+			if err := op.validate(); err != nil {
+				return file, fmt.Errorf("scenario %q op %d: %w", sc.Name, j+1, err)
+			}
+		}
+	}
+	for _, sc := range file.Scenarios {
+		if _, err := file.resolve(sc.Name); err != nil {
+			return file, err
+		}
+	}
+	return file, nil
+}
+
+// validate checks the fields an operation needs, independent of the issues.
+func (op ScenarioOp) validate() error {
+	switch op.Op {
+	case ScenarioOpClose, ScenarioOpReopen:
+	case ScenarioOpAddDep, ScenarioOpRemoveDep:
+		if op.DependsOn == "" {
+			return fmt.Errorf("%s needs depends_on", op.Op)
+		}
+		if op.Type != "" && !op.Type.IsValid() {
+			return fmt.Errorf("unknown dependency type %q", op.Type)
+		}
+	case ScenarioOpSetPriority:
+		if op.Priority == nil {
+			return fmt.Errorf("set_priority needs priority")
+		}
+	case ScenarioOpAddAgent:
+		if op.Count < 0 {
+			return fmt.Errorf("add_agent count must be positive")
+		}
+		return nil
+	case "":
+		return fmt.Errorf("missing op")
+	default:
+		return fmt.Errorf("unknown op %q", op.Op)
+	}
+	if op.ID == "" {
+		return fmt.Errorf("%s needs id", op.Op)
+	}
+	return nil
+}
+
+// resolve returns a scenario's operations with everything it extends first.
+func (f ScenarioFile) resolve(name string) ([]ScenarioOp, error) {
+	byName := make(map[string]Scenario, len(f.Scenarios))
+	for _, sc := range f.Scenarios {
+		byName[sc.Name] = sc
+	}
+	var chain []Scenario
+	seen := make(map[string]bool)
+	for next := name; next != ""; {
+		sc, ok := byName[next]
+		if !ok && len(chain) == 0 {
+			return nil, fmt.Errorf("unknown scenario %q", next)
+		}
+		if !ok {
+			return nil, fmt.Errorf("scenario %q extends unknown scenario %q", chain[len(chain)-1].Name, next)
+		}
+		if seen[next] {
+			return nil, fmt.Errorf("scenario %q extends itself", name)
+		}
+		seen[next] = true
+		chain = append(chain, sc)
+		next = sc.Extends
+	}
+	var ops []ScenarioOp
+	for i := len(chain) - 1; i >= 0; i-- {
+		ops = append(ops, chain[i].Ops...)
+	}
+	return ops, nil
+}
+
+// ScenarioOptions configures SimulateScenarios.
+type ScenarioOptions struct {
+	Roster Roster    // Workers for the completion forecast; add_agent extends it
+	Now    time.Time // Forecast start and close time of simulated closes
+}
+
+// ScenarioMetrics is the plan-level state of one issue set.
+type ScenarioMetrics struct {
+	Open               int        `json:"open"`
+	Actionable         int        `json:"actionable"`
+	Blocked            int        `json:"blocked"`
+	CriticalPathLength int        `json:"critical_path_length"` // Issues on the longest chain of open blockers
+	CriticalPath       []string   `json:"critical_path"`        // That chain, first blocker first
+	TopPicks           []string   `json:"top_picks"`
+	Cycles             [][]string `json:"cycles"`
+	Workers            int        `json:"workers"`
+	ForecastDays       float64    `json:"forecast_days"` // Days until the schedule finishes
+	ForecastDate       time.Time  `json:"forecast_date"`
+	Unscheduled        int        `json:"unscheduled,omitempty"` // Open work no worker could take
+
+	actionable []string
+}
+
+// ScenarioDelta is a scenario's change from the current state. Counts are
+// after minus before.
+type ScenarioDelta struct {
+	Open               int        `json:"open"`
+	Actionable         int        `json:"actionable"`
+	Blocked            int        `json:"blocked"`
+	CriticalPathLength int        `json:"critical_path_length"`
+	Cycles             int        `json:"cycles"`
+	ForecastDays       float64    `json:"forecast_days"` // Negative: finishes sooner
+	NewlyActionable    []string   `json:"newly_actionable,omitempty"`
+	NoLongerActionable []string   `json:"no_longer_actionable,omitempty"`
+	TopPicksAdded      []string   `json:"top_picks_added,omitempty"`
+	TopPicksRemoved    []string   `json:"top_picks_removed,omitempty"`
+	CyclesAdded        [][]string `json:"cycles_added,omitempty"`
+	CyclesRemoved      [][]string `json:"cycles_removed,omitempty"`
+}
+
+// ScenarioResult is one simulated scenario.
+type ScenarioResult struct {
+	Name     string          `json:"name"`
+	Extends  string          `json:"extends,omitempty"`
+	Ops      []ScenarioOp    `json:"ops"` // Including inherited operations
+	After    ScenarioMetrics `json:"after"`
+	Delta    ScenarioDelta   `json:"delta"`
+	Warnings []string        `json:"warnings,omitempty"` // Operations that changed nothing
+}
+
+// ScenarioReport compares every scenario against the current state.
+type ScenarioReport struct {
+	Baseline  ScenarioMetrics  `json:"baseline"`
+	Scenarios []ScenarioResult `json:"scenarios"`
+}
+
+// SimulateScenarios applies each scenario to a copy of issues and measures
+// the result. issues is not modified. An operation naming an unknown issue
+// is an error.
+func SimulateScenarios(issues []model.Issue, file ScenarioFile, opts ScenarioOptions) (ScenarioReport, error) {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	report := ScenarioReport{Baseline: scenarioMetrics(issues, opts.Roster, opts.Now)}
+	for _, sc := range file.Scenarios {
+		ops, err := file.resolve(sc.Name)
+		if err != nil {
+			return report, err
+		}
+		changed, roster, warnings, err := ApplyScenario(issues, opts.Roster, ops, opts.Now)
+		if err != nil {
+			return report, fmt.Errorf("scenario %q: %w", sc.Name, err)
+		}
+		after := scenarioMetrics(changed, roster, opts.Now)
+		report.Scenarios = append(report.Scenarios, ScenarioResult{
+			Name:     sc.Name,
+			Extends:  sc.Extends,
+			Ops:      ops,
+			After:    after,
+			Delta:    diffScenarioMetrics(report.Baseline, after),
+			Warnings: warnings,
+		})
+	}
+	return report, nil
+}
+
+// ApplyScenario returns a copy of issues and roster with ops applied, plus
+// a warning for each operation that changed nothing.
+func ApplyScenario(issues []model.Issue, roster Roster, ops []ScenarioOp, now time.Time) ([]model.Issue, Roster, []string, error) {
+	changed := make([]model.Issue, len(issues))
+	index := make(map[string]int, len(issues))
+	for i, iss := range issues {
+		changed[i] = iss.Clone()
+		index[iss.ID] = i
+	}
+	roster.Workers = append([]Worker(nil), roster.Workers...)
+	workerNames := make(map[string]bool, len(roster.Workers))
+	for _, w := range roster.Workers {
+		workerNames[w.Name] = true
+	}
+
+	var warnings []string
+	for n, op := range ops {
+		if op.Op == ScenarioOpAddAgent {
+			count := max(op.Count, 1)
+			for k := 1; count > 0; k++ {
+				name := fmt.Sprintf("whatif-agent-%d", k)
+				if !workerNames[name] {
+					workerNames[name] = true
+					roster.Workers = append(roster.Workers, Worker{Name: name, Kind: "agent"})
+					count--
+				}
+			}
+			continue
+		}
+
+		i, ok := index[op.ID]
+		if !ok {
+			return nil, roster, nil, fmt.Errorf("op %d (%s): unknown issue %q", n+1, op.Op, op.ID)
+		}
+		iss := &changed[i]
+		switch op.Op {
+		case ScenarioOpClose:
+			if isClosedLikeStatus(iss.Status) {
+				warnings = append(warnings, fmt.Sprintf("%s is already %s", iss.ID, iss.Status))
+				continue
+			}
+			iss.Status = model.StatusClosed
+			closedAt := now
+			iss.ClosedAt = &closedAt
+		case ScenarioOpReopen:
+			if !isClosedLikeStatus(iss.Status) {
+				warnings = append(warnings, fmt.Sprintf("%s is already %s", iss.ID, iss.Status))
+				continue
+			}
+			iss.Status = model.StatusOpen
+			iss.ClosedAt = nil
+		case ScenarioOpAddDep:
+			if _, ok := index[op.DependsOn]; !ok {
+				return nil, roster, nil, fmt.Errorf("op %d (%s): unknown issue %q", n+1, op.Op, op.DependsOn)
+			}
+			if op.DependsOn == iss.ID {
+				return nil, roster, nil, fmt.Errorf("op %d (%s): %s cannot depend on itself", n+1, op.Op, iss.ID)
+			}
+			depType := op.Type
+			if depType == "" {
+				depType = model.DepBlocks
+			}
+			exists := false
+			for _, d := range iss.Dependencies {
+				if d != nil && d.DependsOnID == op.DependsOn && (d.Type == depType || d.Type.IsBlocking() && depType.IsBlocking()) {
+					exists = true
+				}
+			}
+			if exists {
+				warnings = append(warnings, fmt.Sprintf("%s already depends on %s", iss.ID, op.DependsOn))
+				continue
+			}
+			iss.Dependencies = append(iss.Dependencies, &model.Dependency{
+				IssueID:     iss.ID,
+				DependsOnID: op.DependsOn,
+				Type:        depType,
+				CreatedAt:   now,
+			})
+		case ScenarioOpRemoveDep:
+			var kept []*model.Dependency
+			for _, d := range iss.Dependencies {
+				if d != nil && d.DependsOnID == op.DependsOn && (op.Type == "" || d.Type == op.Type) {
+					continue
+				}
+				kept = append(kept, d)
+			}
+			if len(kept) == len(iss.Dependencies) {
+				warnings = append(warnings, fmt.Sprintf("%s does not depend on %s", iss.ID, op.DependsOn))
+				continue
+			}
+			iss.Dependencies = kept
+		case ScenarioOpSetPriority:
+			if iss.Priority == *op.Priority {
+				warnings = append(warnings, fmt.Sprintf("%s is already P%d", iss.ID, iss.Priority))
+				continue
+			}
+			iss.Priority = *op.Priority
+		}
+	}
+	return changed, roster, warnings, nil
+}
+
+// scenarioMetrics measures an issue set: triage counts and top picks,
+// cycles, the longest open blocker chain and a scheduled finish.
+func scenarioMetrics(issues []model.Issue, roster Roster, now time.Time) ScenarioMetrics {
+	analyzer := NewAnalyzer(issues)
+	cfg := TriageConfig()
+	cfg.ComputeCycles = true
+	cfg.CyclesTimeout = 500 * time.Millisecond
+	cfg.MaxCyclesToStore = 100
+	stats := analyzer.AnalyzeAsyncWithConfig(context.Background(), cfg)
+	stats.WaitForPhase2()
+	triage := ComputeTriageFromAnalyzer(analyzer, stats, issues, TriageOptions{}, now)
+
+	m := ScenarioMetrics{
+		Open:       triage.QuickRef.OpenCount,
+		Actionable: triage.QuickRef.ActionableCount,
+		Blocked:    triage.QuickRef.BlockedCount,
+		TopPicks:   []string{},
+		Cycles:     stats.Cycles(),
+		Workers:    len(roster.Workers),
+	}
+	for _, pick := range triage.QuickRef.TopPicks {
+		m.TopPicks = append(m.TopPicks, pick.ID)
+	}
+	if m.Cycles == nil {
+		m.Cycles = [][]string{}
+	}
+	for _, iss := range analyzer.GetActionableIssues() {
+		m.actionable = append(m.actionable, iss.ID)
+	}
+	sort.Strings(m.actionable)
+	m.CriticalPath = longestOpenChain(issues)
+	m.CriticalPathLength = len(m.CriticalPath)
+
+	sched := BuildSchedule(issues, roster, ScheduleOptions{Start: now})
+	m.ForecastDays = sched.Days
+	m.ForecastDate = sched.Finish
+	m.Unscheduled = len(sched.Unscheduled)
+	return m
+}
+
+// longestOpenChain returns the longest chain of open issues linked by
+// blocking dependencies, first blocker first. Cycles are cut where they
+// close.
+func longestOpenChain(issues []model.Issue) []string {
+	blockers := make(map[string][]string)
+	var ids []string
+	for _, iss := range issues {
+		if !isClosedLikeStatus(iss.Status) {
+			blockers[iss.ID] = nil
+			ids = append(ids, iss.ID)
+		}
+	}
+	for _, iss := range issues {
+		if _, open := blockers[iss.ID]; !open {
+			continue
+		}
+		for _, d := range iss.Dependencies {
+			if d == nil || !d.Type.IsBlocking() {
+				continue
+			}
+			if _, open := blockers[d.DependsOnID]; open && d.DependsOnID != iss.ID {
+				blockers[iss.ID] = append(blockers[iss.ID], d.DependsOnID)
+			}
+		}
+		sort.Strings(blockers[iss.ID])
+	}
+	sort.Strings(ids)
+
+	depth := make(map[string]int, len(ids))
+	prev := make(map[string]string, len(ids))
+	onStack := make(map[string]bool)
+	var visit func(id string) int
+	visit = func(id string) int {
+		if d, ok := depth[id]; ok {
+			return d
+		}
+		if onStack[id] {
+			return 0
+		}
+		onStack[id] = true
+		best := 0
+		for _, b := range blockers[id] {
+			if d := visit(b); d > best {
+				best = d
+				prev[id] = b
+			}
+		}
+		onStack[id] = false
+		depth[id] = best + 1
+		return best + 1
+	}
+
+	end := ""
+	for _, id := range ids {
+		if visit(id) > depth[end] {
+			end = id
+		}
+	}
+	var chain []string
+	for id := end; id != ""; id = prev[id] {
+		chain = append(chain, id)
+	}
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	if chain == nil {
+		chain = []string{}
+	}
+	return chain
+}
+
+func diffScenarioMetrics(before, after ScenarioMetrics) ScenarioDelta {
+	d := ScenarioDelta{
+		Open:               after.Open - before.Open,
+		Actionable:         after.Actionable - before.Actionable,
+		Blocked:            after.Blocked - before.Blocked,
+		CriticalPathLength: after.CriticalPathLength - before.CriticalPathLength,
+		Cycles:             len(after.Cycles) - len(before.Cycles),
+		ForecastDays:       roundDays(after.ForecastDays - before.ForecastDays),
+	}
+	d.NewlyActionable, d.NoLongerActionable = stringSetDiff(before.actionable, after.actionable)
+	d.TopPicksAdded, d.TopPicksRemoved = stringSetDiff(before.TopPicks, after.TopPicks)
+
+	key := func(cycle []string) string {
+		sorted := append([]string(nil), cycle...)
+		sort.Strings(sorted)
+		return strings.Join(sorted, "\x00")
+	}
+	beforeCycles := make(map[string]bool, len(before.Cycles))
+	for _, c := range before.Cycles {
+		beforeCycles[key(c)] = true
+	}
+	afterCycles := make(map[string]bool, len(after.Cycles))
+	for _, c := range after.Cycles {
+		afterCycles[key(c)] = true
+		if !beforeCycles[key(c)] {
+			d.CyclesAdded = append(d.CyclesAdded, c)
+		}
+	}
+	for _, c := range before.Cycles {
+		if !afterCycles[key(c)] {
+			d.CyclesRemoved = append(d.CyclesRemoved, c)
+		}
+	}
+	return d
+}
+
+// stringSetDiff returns the members of after missing from before, and of
+// before missing from after, each in input order.
+func stringSetDiff(before, after []string) (added, removed []string) {
+	inBefore := make(map[string]bool, len(before))
+	for _, s := range before {
+		inBefore[s] = true
+	}
+	inAfter := make(map[string]bool, len(after))
+	for _, s := range after {
+		inAfter[s] = true
+		if !inBefore[s] {
+			added = append(added, s)
+		}
+	}
+	for _, s := range before {
+		if !inAfter[s] {
+			removed = append(removed, s)
+		}
+	}
+	return added, removed
+}
@@ -0,0 +1,104 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestParseScenarioFile(t *testing.T) {
+	file, err := ParseScenarioFile([]byte(`[{"op": "close", "id": "a"}, {"op": "add_agent"}]`))
+	if err != nil || len(file.Scenarios) != 1 || file.Scenarios[0].Name != "scenario-1" || len(file.Scenarios[0].Ops) != 2 {
+		t.Fatalf("bare op list: %+v, %v", file, err)
+	}
+	file, err = ParseScenarioFile([]byte(`{"name": "solo", "ops": [{"op": "reopen", "id": "a"}]}`))
+	if err != nil || len(file.Scenarios) != 1 || file.Scenarios[0].Name != "solo" {
+		t.Fatalf("single scenario: %+v, %v", file, err)
+	}
+
+	for input, want := range map[string]string{
+		`{"scenarios": []}`:                                                             "no scenarios",
+		`[{"op": "explode", "id": "a"}]`:                                                "unknown op",
+		`[{"op": "add_dep", "id": "a"}]`:                                                "needs depends_on",
+		`[{"op": "set_priority", "id": "a"}]`:                                           "needs priority",
+		`[{"op": "close"}]`:                                                             "needs id",
+		`{"scenarios": [{"name": "x", "extends": "nope", "ops": []}]}`:                  "extends unknown scenario",
+		`{"scenarios": [{"name": "x", "ops": []}, {"name": "x", "ops": []}]}`:           "duplicate",
+		`{"scenarios": [{"name": "x", "extends": "y"}, {"name": "y", "extends": "x"}]}`: "extends itself",
+	} {
+		if _, err := ParseScenarioFile([]byte(input)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: err = %v, want %q", input, err, want)
+		}
+	}
+}
+
+func TestSimulateScenarios(t *testing.T) {
+	day := minutes(360) // One day for a default agent
+	issues := []model.Issue{
+		{ID: "a", Title: "a", Status: model.StatusOpen, Priority: 2, EstimatedMinutes: day},
+		{ID: "b", Title: "b", Status: model.StatusOpen, Priority: 2, EstimatedMinutes: day, Dependencies: blockedBy("b", "a")},
+		{ID: "c", Title: "c", Status: model.StatusOpen, Priority: 2, EstimatedMinutes: day, Dependencies: blockedBy("c", "b")},
+		{ID: "d", Title: "d", Status: model.StatusOpen, Priority: 2, EstimatedMinutes: day},
+		{ID: "x", Title: "x", Status: model.StatusOpen, Priority: 2, EstimatedMinutes: day, Dependencies: blockedBy("x", "y")},
+		{ID: "y", Title: "y", Status: model.StatusOpen, Priority: 2, EstimatedMinutes: day, Dependencies: blockedBy("y", "x")},
+	}
+	file, err := ParseScenarioFile([]byte(`{"scenarios": [
+		{"name": "close-a", "ops": [{"op": "close", "id": "a"}, {"op": "reopen", "id": "d"}]},
+		{"name": "close-a+agent", "extends": "close-a", "ops": [{"op": "add_agent"}]},
+		{"name": "break-cycle", "ops": [{"op": "remove_dep", "id": "y", "depends_on": "x"}, {"op": "set_priority", "id": "c", "priority": 0}]}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	opts := ScenarioOptions{Roster: DefaultRoster(issues, 1), Now: now}
+	report, err := SimulateScenarios(issues, file, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := report.Baseline
+	if base.Open != 6 || base.Actionable != 2 || base.Blocked != 4 || len(base.Cycles) != 1 {
+		t.Errorf("baseline = %+v", base)
+	}
+	if base.CriticalPathLength != 3 || strings.Join(base.CriticalPath, ",") != "a,b,c" {
+		t.Errorf("baseline critical path = %v", base.CriticalPath)
+	}
+	if base.Workers != 1 || base.ForecastDays <= 0 {
+		t.Errorf("baseline forecast = %v days with %d workers", base.ForecastDays, base.Workers)
+	}
+	if issues[0].Status != model.StatusOpen || len(issues[5].Dependencies) != 1 {
+		t.Error("SimulateScenarios modified its input")
+	}
+
+	closeA := report.Scenarios[0]
+	if d := closeA.Delta; d.Open != -1 || d.Actionable != 0 || d.CriticalPathLength != -1 || d.ForecastDays >= 0 {
+		t.Errorf("close-a delta = %+v", d)
+	}
+	if d := closeA.Delta; len(d.NewlyActionable) != 1 || d.NewlyActionable[0] != "b" || len(d.NoLongerActionable) != 1 || d.NoLongerActionable[0] != "a" {
+		t.Errorf("close-a actionable changes = %v / %v", d.NewlyActionable, d.NoLongerActionable)
+	}
+	if len(closeA.Warnings) != 1 || !strings.Contains(closeA.Warnings[0], "d is already open") {
+		t.Errorf("close-a warnings = %v", closeA.Warnings)
+	}
+
+	withAgent := report.Scenarios[1]
+	if len(withAgent.Ops) != 3 || withAgent.Extends != "close-a" || withAgent.After.Workers != 2 {
+		t.Errorf("close-a+agent = %+v", withAgent)
+	}
+	if withAgent.After.ForecastDays >= closeA.After.ForecastDays {
+		t.Errorf("an extra agent should finish sooner: %v vs %v days", withAgent.After.ForecastDays, closeA.After.ForecastDays)
+	}
+
+	breakCycle := report.Scenarios[2]
+	if d := breakCycle.Delta; d.Cycles != -1 || len(d.CyclesRemoved) != 1 || len(d.CyclesAdded) != 0 || d.Actionable != 1 {
+		t.Errorf("break-cycle delta = %+v", d)
+	}
+
+	bad, _ := ParseScenarioFile([]byte(`[{"op": "close", "id": "missing"}]`))
+	if _, err := SimulateScenarios(issues, bad, opts); err == nil || !strings.Contains(err.Error(), `unknown issue "missing"`) {
+		t.Errorf("unknown issue: err = %v", err)
+	}
+}
@@ -0,0 +1,485 @@
+package analysis
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/graph/topo"
+	"gopkg.in/yaml.v3"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// Resource-constrained scheduling.
+//
+// GetExecutionPlan groups actionable work into tracks without saying who
+// does it. BuildSchedule assigns every open issue to a named worker from a
+// roster and places it on that worker's timeline: blockers finish first,
+// issues already assigned stay with their assignee, label skills limit who
+// may take unassigned work, and each worker only has so many hours a day.
+// Durations come from EstimatedMinutes (or the project's median estimate).
+
+// DefaultHoursPerDay is the capacity of a worker whose roster entry does
+// not set one.
+const DefaultHoursPerDay = 6.0
+
+// RosterFilename is the roster's name under .bv/.
+const RosterFilename = "roster.yaml"
+
+// Worker is one person or agent that can take work.
+type Worker struct {
+	Name        string   `yaml:"name" json:"name"`
+	Kind        string   `yaml:"kind,omitempty" json:"kind,omitempty"` // "person" or "agent"
+	HoursPerDay float64  `yaml:"hours_per_day,omitempty" json:"hours_per_day"`
+	Skills      []string `yaml:"skills,omitempty" json:"skills,omitempty"` // Labels this worker takes; empty = anything
+}
+
+// Roster lists the workers available to a schedule.
+//
+//	# .bv/roster.yaml
+//	default_hours_per_day: 6
+//	workers:
+//	  - name: alice
+//	    hours_per_day: 4
+//	    skills: [frontend, design]
+//	  - name: agent-1
+//	    kind: agent
+//	    hours_per_day: 20
+type Roster struct {
+	DefaultHoursPerDay float64  `yaml:"default_hours_per_day,omitempty" json:"default_hours_per_day,omitempty"`
+	Workers            []Worker `yaml:"workers" json:"workers"`
+}
+
+// RosterPath returns the default roster path for a project.
+func RosterPath(projectDir string) string {
+	return filepath.Join(projectDir, ".bv", RosterFilename)
+}
+
+// LoadRoster reads a roster file. A missing file yields an empty roster and
+// no error, so callers can fall back to DefaultRoster.
+func LoadRoster(path string) (Roster, error) {
+	var r Roster
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return r, nil
+		}
+		return r, fmt.Errorf("reading roster: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &r); err != nil {
+		return r, fmt.Errorf("parsing roster %s: %w", path, err)
+	}
+	seen := make(map[string]bool, len(r.Workers))
+	for i, w := range r.Workers {
+		if strings.TrimSpace(w.Name) == "" {
+			return r, fmt.Errorf("roster %s: worker %d has no name", path, i+1)
+		}
+		if seen[w.Name] {
+			return r, fmt.Errorf("roster %s: duplicate worker %q", path, w.Name)
+		}
+		seen[w.Name] = true
+		if w.HoursPerDay < 0 || w.HoursPerDay > 24 {
+			return r, fmt.Errorf("roster %s: %s has hours_per_day %.1f (expected 0-24)", path, w.Name, w.HoursPerDay)
+		}
+	}
+	return r, nil
+}
+
+// DefaultRoster is used without a roster file: every assignee of open work
+// plus agents unnamed agents (agent-1, agent-2, ...). With no assignees and
+// no agents requested, a single agent is assumed.
+func DefaultRoster(issues []model.Issue, agents int) Roster {
+	var r Roster
+	seen := make(map[string]bool)
+	for _, iss := range issues {
+		if iss.Assignee == "" || seen[iss.Assignee] || isClosedLikeStatus(iss.Status) {
+			continue
+		}
+		seen[iss.Assignee] = true
+		r.Workers = append(r.Workers, Worker{Name: iss.Assignee, Kind: "person"})
+	}
+	sort.Slice(r.Workers, func(i, j int) bool { return r.Workers[i].Name < r.Workers[j].Name })
+	if agents <= 0 && len(r.Workers) == 0 {
+		agents = 1
+	}
+	for i := 1; i <= agents; i++ {
+		r.Workers = append(r.Workers, Worker{Name: fmt.Sprintf("agent-%d", i), Kind: "agent"})
+	}
+	return r
+}
+
+// ScheduleOptions configures BuildSchedule.
+type ScheduleOptions struct {
+	Start time.Time // Timeline origin (usually now)
+}
+
+// ScheduledTask is one issue placed on a worker's timeline.
+type ScheduledTask struct {
+	IssueID   string       `json:"issue_id"`
+	Title     string       `json:"title"`
+	Status    model.Status `json:"status"`
+	Priority  int          `json:"priority"`
+	Worker    string       `json:"worker"`
+	Pinned    bool         `json:"pinned"` // Already assigned to this worker
+	Minutes   int          `json:"minutes"`
+	Estimated bool         `json:"estimated"` // Minutes came from estimated_minutes (else the median)
+	StartDay  float64      `json:"start_day"` // Days from the schedule start
+	EndDay    float64      `json:"end_day"`
+	Start     time.Time    `json:"start"`
+	End       time.Time    `json:"end"`
+	BlockedBy []string     `json:"blocked_by,omitempty"` // Open blockers that finish first
+}
+
+// WorkerTimeline is one worker's share of the schedule.
+type WorkerTimeline struct {
+	Worker
+	Tasks       []ScheduledTask `json:"tasks"`
+	BusyHours   float64         `json:"busy_hours"`
+	Utilization float64         `json:"utilization"` // Busy share of the worker's capacity up to the project finish
+	Finish      time.Time       `json:"finish"`
+}
+
+// UnscheduledTask is open work no worker could take.
+type UnscheduledTask struct {
+	IssueID string `json:"issue_id"`
+	Title   string `json:"title"`
+	Reason  string `json:"reason"`
+}
+
+// Schedule is the output of BuildSchedule.
+type Schedule struct {
+	Start       time.Time         `json:"start"`
+	Finish      time.Time         `json:"finish"`
+	Days        float64           `json:"days"`
+	Workers     []WorkerTimeline  `json:"workers"`
+	Tasks       []ScheduledTask   `json:"tasks"` // Every scheduled task, by start
+	Unscheduled []UnscheduledTask `json:"unscheduled,omitempty"`
+	Warnings    []string          `json:"warnings,omitempty"`
+}
+
+// schedTask is an open issue awaiting placement.
+type schedTask struct {
+	issue     model.Issue
+	minutes   int
+	estimated bool
+	blockers  []int
+	workers   []int // Eligible worker indexes
+	pinned    bool
+	rank      int
+	placed    bool
+	end       float64
+	failed    string // Why it cannot be scheduled
+}
+
+// BuildSchedule places every open issue on a worker's timeline with a
+// greedy list schedule: among tasks whose blockers are placed, the one that
+// can start earliest goes next (ties by status, priority, then ID), on the
+// eligible worker that finishes it first.
+func BuildSchedule(issues []model.Issue, roster Roster, opts ScheduleOptions) Schedule {
+	defaultHours := roster.DefaultHoursPerDay
+	if defaultHours <= 0 {
+		defaultHours = DefaultHoursPerDay
+	}
+	workers := append([]Worker(nil), roster.Workers...)
+	index := make(map[string]int, len(workers))
+	for i := range workers {
+		if workers[i].HoursPerDay <= 0 {
+			workers[i].HoursPerDay = defaultHours
+		}
+		index[workers[i].Name] = i
+	}
+
+	sched := Schedule{Start: opts.Start, Finish: opts.Start}
+
+	// Open work; epics with open children are containers, not work.
+	hasOpenChild := make(map[string]bool)
+	for _, iss := range issues {
+		if isClosedLikeStatus(iss.Status) {
+			continue
+		}
+		for _, dep := range iss.Dependencies {
+			if dep != nil && dep.Type == model.DepParentChild {
+				hasOpenChild[dep.DependsOnID] = true
+			}
+		}
+	}
+	var open []model.Issue
+	for _, iss := range issues {
+		if isClosedLikeStatus(iss.Status) || (iss.IssueType == model.TypeEpic && hasOpenChild[iss.ID]) {
+			continue
+		}
+		open = append(open, iss)
+	}
+	sort.Slice(open, func(i, j int) bool { return open[i].ID < open[j].ID })
+
+	// Assignees missing from the roster still own their work, but take
+	// nothing else.
+	rostered := len(workers)
+	var added []string
+	for _, iss := range open {
+		if iss.Assignee == "" {
+			continue
+		}
+		if _, ok := index[iss.Assignee]; !ok {
+			index[iss.Assignee] = len(workers)
+			workers = append(workers, Worker{Name: iss.Assignee, Kind: "person", HoursPerDay: defaultHours})
+			added = append(added, iss.Assignee)
+		}
+	}
+	if len(added) > 0 {
+		sched.Warnings = append(sched.Warnings, fmt.Sprintf("assignees not in the roster keep only their own issues, at %.0fh/day: %s", defaultHours, strings.Join(added, ", ")))
+	}
+
+	median := computeMedianEstimatedMinutes(issues)
+	taskIndex := make(map[string]int, len(open))
+	tasks := make([]*schedTask, len(open))
+	for i, iss := range open {
+		taskIndex[iss.ID] = i
+		t := &schedTask{issue: iss, minutes: median}
+		if iss.EstimatedMinutes != nil && *iss.EstimatedMinutes > 0 {
+			t.minutes, t.estimated = *iss.EstimatedMinutes, true
+		}
+		if iss.Assignee != "" {
+			t.workers, t.pinned = []int{index[iss.Assignee]}, true
+		} else {
+			t.workers = eligibleWorkers(workers[:rostered], iss.Labels)
+			if len(t.workers) == 0 {
+				t.failed = "no worker has the skills for labels " + strings.Join(iss.Labels, ", ")
+			}
+		}
+		tasks[i] = t
+	}
+	for i, iss := range open {
+		for _, dep := range iss.Dependencies {
+			if dep == nil || !dep.Type.IsBlocking() {
+				continue
+			}
+			if j, ok := taskIndex[dep.DependsOnID]; ok && j != i {
+				tasks[i].blockers = append(tasks[i].blockers, j)
+			}
+		}
+	}
+
+	order := make([]int, len(tasks))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		x, y := tasks[order[a]].issue, tasks[order[b]].issue
+		if (x.Status == model.StatusInProgress) != (y.Status == model.StatusInProgress) {
+			return x.Status == model.StatusInProgress
+		}
+		return x.Priority < y.Priority
+	})
+	for r, i := range order {
+		tasks[i].rank = r
+	}
+
+	free := make([]float64, len(workers)) // Day each worker is next free
+	timelines := make([]WorkerTimeline, len(workers))
+	for i, w := range workers {
+		timelines[i].Worker = w
+	}
+
+	remaining := len(tasks)
+	for _, t := range tasks {
+		if t.failed != "" {
+			t.placed = true
+			remaining--
+		}
+	}
+	for remaining > 0 {
+		best, bestWorker := -1, -1
+		var bestStart, bestEnd float64
+		stalled := true
+		for i, t := range tasks {
+			if t.placed {
+				continue
+			}
+			ready, readyAt, failedBlocker := true, 0.0, ""
+			for _, b := range t.blockers {
+				switch {
+				case tasks[b].failed != "":
+					failedBlocker = tasks[b].issue.ID
+				case !tasks[b].placed:
+					ready = false
+				default:
+					readyAt = max(readyAt, tasks[b].end)
+				}
+			}
+			if failedBlocker != "" {
+				t.failed = "blocked by unschedulable " + failedBlocker
+				t.placed = true
+				remaining--
+				stalled = false
+				continue
+			}
+			if !ready {
+				continue
+			}
+			stalled = false
+			for _, w := range t.workers {
+				start := max(free[w], readyAt)
+				end := start + float64(t.minutes)/60/workers[w].HoursPerDay
+				better := best == -1 || start < bestStart
+				if !better && start == bestStart {
+					better = t.rank < tasks[best].rank || (i == best && end < bestEnd)
+				}
+				if better {
+					best, bestWorker, bestStart, bestEnd = i, w, start, end
+				}
+			}
+		}
+		if stalled {
+			sched.Warnings = append(sched.Warnings, breakScheduleCycle(tasks))
+			continue
+		}
+		if best == -1 {
+			continue // Only failures were settled this pass
+		}
+
+		t := tasks[best]
+		t.placed, t.end = true, bestEnd
+		free[bestWorker] = bestEnd
+		remaining--
+
+		st := ScheduledTask{
+			IssueID:   t.issue.ID,
+			Title:     t.issue.Title,
+			Status:    t.issue.Status,
+			Priority:  t.issue.Priority,
+			Worker:    workers[bestWorker].Name,
+			Pinned:    t.pinned,
+			Minutes:   t.minutes,
+			Estimated: t.estimated,
+			StartDay:  roundDays(bestStart),
+			EndDay:    roundDays(bestEnd),
+			Start:     opts.Start.Add(durationDays(bestStart)),
+			End:       opts.Start.Add(durationDays(bestEnd)),
+		}
+		for _, b := range t.blockers {
+			st.BlockedBy = append(st.BlockedBy, tasks[b].issue.ID)
+		}
+		sort.Strings(st.BlockedBy)
+		timelines[bestWorker].Tasks = append(timelines[bestWorker].Tasks, st)
+		timelines[bestWorker].BusyHours += float64(t.minutes) / 60
+		sched.Tasks = append(sched.Tasks, st)
+		if st.End.After(sched.Finish) {
+			sched.Finish = st.End
+			sched.Days = roundDays(bestEnd)
+		}
+	}
+
+	for _, t := range tasks {
+		if t.failed != "" {
+			sched.Unscheduled = append(sched.Unscheduled, UnscheduledTask{IssueID: t.issue.ID, Title: t.issue.Title, Reason: t.failed})
+		}
+	}
+	for i := range timelines {
+		tl := &timelines[i]
+		tl.Finish = opts.Start
+		if n := len(tl.Tasks); n > 0 {
+			tl.Finish = tl.Tasks[n-1].End
+		}
+		if sched.Days > 0 {
+			tl.Utilization = roundDays(tl.BusyHours / (tl.HoursPerDay * sched.Days))
+		}
+	}
+	sched.Workers = timelines
+	sort.SliceStable(sched.Tasks, func(i, j int) bool { return sched.Tasks[i].StartDay < sched.Tasks[j].StartDay })
+	return sched
+}
+
+// breakScheduleCycle unblocks a stalled schedule. Only a dependency cycle
+// leaves nothing ready, so it finds a cycle among the unplaced tasks whose
+// blockers all lie inside it, drops its first member's edges into the
+// cycle and returns the warning naming the cycle.
+func breakScheduleCycle(tasks []*schedTask) string {
+	g := simple.NewDirectedGraph()
+	for i, t := range tasks {
+		if !t.placed {
+			g.AddNode(simple.Node(i))
+		}
+	}
+	for i, t := range tasks {
+		for _, b := range t.blockers {
+			if !t.placed && !tasks[b].placed {
+				g.SetEdge(g.NewEdge(simple.Node(i), simple.Node(b)))
+			}
+		}
+	}
+
+	var cycle []int
+	for _, scc := range topo.TarjanSCC(g) {
+		if len(scc) < 2 {
+			continue
+		}
+		members := make(map[int]bool, len(scc))
+		for _, n := range scc {
+			members[int(n.ID())] = true
+		}
+		closed := true
+		for m := range members {
+			for _, b := range tasks[m].blockers {
+				if !tasks[b].placed && !members[b] {
+					closed = false
+				}
+			}
+		}
+		if !closed {
+			continue // Waits on another cycle downstream
+		}
+		c := make([]int, 0, len(members))
+		for m := range members {
+			c = append(c, m)
+		}
+		sort.Ints(c)
+		if cycle == nil || c[0] < cycle[0] {
+			cycle = c
+		}
+	}
+
+	first := tasks[cycle[0]]
+	ids := make([]string, len(cycle))
+	inCycle := make(map[int]bool, len(cycle))
+	for i, m := range cycle {
+		ids[i] = tasks[m].issue.ID
+		inCycle[m] = true
+	}
+	var kept []int
+	for _, b := range first.blockers {
+		if !inCycle[b] {
+			kept = append(kept, b)
+		}
+	}
+	first.blockers = kept
+	return fmt.Sprintf("dependency cycle through %s; scheduled %s ignoring its blockers in the cycle", strings.Join(ids, ", "), first.issue.ID)
+}
+
+// eligibleWorkers returns the workers whose skills cover one of labels,
+// or who list no skills.
+func eligibleWorkers(workers []Worker, labels []string) []int {
+	var out []int
+	for i, w := range workers {
+		if len(w.Skills) == 0 {
+			out = append(out, i)
+			continue
+		}
+		for _, s := range w.Skills {
+			if hasLabel(labels, s) {
+				out = append(out, i)
+				break
+			}
+		}
+	}
+	return out
+}
+
+func roundDays(d float64) float64 {
+	return float64(int64(d*100+0.5)) / 100
+}
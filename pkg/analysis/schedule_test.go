@@ -0,0 +1,162 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func minutes(m int) *int { return &m }
+
+func taskByID(t *testing.T, s Schedule, id string) ScheduledTask {
+	t.Helper()
+	for _, st := range s.Tasks {
+		if st.IssueID == id {
+			return st
+		}
+	}
+	t.Fatalf("%s not scheduled: %+v", id, s.Tasks)
+	return ScheduledTask{}
+}
+
+func TestBuildSchedule_RespectsDependenciesAndCapacity(t *testing.T) {
+	start := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	issues := []model.Issue{
+		{ID: "A", Title: "A", Status: model.StatusOpen, Priority: 0, EstimatedMinutes: minutes(480)},
+		{ID: "B", Title: "B", Status: model.StatusOpen, Priority: 0, EstimatedMinutes: minutes(240), Dependencies: blockedBy("B", "A")},
+		{ID: "C", Title: "C", Status: model.StatusOpen, Priority: 2, EstimatedMinutes: minutes(240)},
+		{ID: "D", Title: "D", Status: model.StatusOpen, Priority: 1, EstimatedMinutes: minutes(120), Assignee: "bo"},
+		{ID: "X", Title: "Done", Status: model.StatusClosed, EstimatedMinutes: minutes(60)},
+	}
+	roster := Roster{Workers: []Worker{
+		{Name: "ana", HoursPerDay: 8},
+		{Name: "bo", HoursPerDay: 4},
+	}}
+
+	s := BuildSchedule(issues, roster, ScheduleOptions{Start: start})
+	if len(s.Tasks) != 4 || len(s.Unscheduled) != 0 || len(s.Warnings) != 0 {
+		t.Fatalf("schedule = %+v", s)
+	}
+	// ana: A 0-1. bo: D (pinned) 0-0.5, then C 0.5-1.5. B waits for A: ana 1-1.5.
+	want := map[string][3]any{
+		"A": {"ana", 0.0, 1.0},
+		"D": {"bo", 0.0, 0.5},
+		"C": {"bo", 0.5, 1.5},
+		"B": {"ana", 1.0, 1.5},
+	}
+	for id, w := range want {
+		st := taskByID(t, s, id)
+		if st.Worker != w[0] || st.StartDay != w[1] || st.EndDay != w[2] {
+			t.Errorf("%s on %s %.2f-%.2f, want %v", id, st.Worker, st.StartDay, st.EndDay, w)
+		}
+	}
+	if b := taskByID(t, s, "B"); len(b.BlockedBy) != 1 || b.BlockedBy[0] != "A" {
+		t.Errorf("B blocked by %v", b.BlockedBy)
+	}
+	if !taskByID(t, s, "D").Pinned || taskByID(t, s, "C").Pinned {
+		t.Error("only D is pinned to its assignee")
+	}
+	if s.Days != 1.5 || !s.Finish.Equal(start.Add(36*time.Hour)) {
+		t.Errorf("finish = %v (%.2f days)", s.Finish, s.Days)
+	}
+	if s.Workers[0].BusyHours != 12 || s.Workers[1].Utilization != 1 {
+		t.Errorf("workers = %+v", s.Workers)
+	}
+}
+
+func TestBuildSchedule_SkillsAndUnschedulable(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "ui", Title: "UI", Status: model.StatusOpen, Labels: []string{"frontend"}, EstimatedMinutes: minutes(360)},
+		{ID: "db", Title: "DB", Status: model.StatusOpen, Labels: []string{"database"}, EstimatedMinutes: minutes(360)},
+		{ID: "after", Title: "After DB", Status: model.StatusOpen, Dependencies: blockedBy("after", "db")},
+		{ID: "own", Title: "Owned", Status: model.StatusInProgress, Assignee: "cy", EstimatedMinutes: minutes(60)},
+	}
+	roster := Roster{Workers: []Worker{{Name: "fe", Skills: []string{"frontend"}}}}
+
+	s := BuildSchedule(issues, roster, ScheduleOptions{})
+	if st := taskByID(t, s, "ui"); st.Worker != "fe" {
+		t.Errorf("ui on %s", st.Worker)
+	}
+	if st := taskByID(t, s, "own"); st.Worker != "cy" || st.Estimated != true {
+		t.Errorf("own = %+v", st)
+	}
+	if len(s.Unscheduled) != 2 || s.Unscheduled[0].IssueID != "after" || s.Unscheduled[1].IssueID != "db" {
+		t.Fatalf("unscheduled = %+v", s.Unscheduled)
+	}
+	if len(s.Warnings) != 1 {
+		t.Errorf("expected a warning for the unknown assignee, got %v", s.Warnings)
+	}
+}
+
+func TestBuildSchedule_EpicsAndCycles(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "E", Title: "Epic", Status: model.StatusOpen, IssueType: model.TypeEpic},
+		{ID: "A", Title: "A", Status: model.StatusOpen, Dependencies: append(blockedBy("A", "B"),
+			&model.Dependency{IssueID: "A", DependsOnID: "E", Type: model.DepParentChild})},
+		{ID: "B", Title: "B", Status: model.StatusOpen, Dependencies: blockedBy("B", "A")},
+	}
+	s := BuildSchedule(issues, DefaultRoster(issues, 0), ScheduleOptions{})
+	if len(s.Tasks) != 2 || len(s.Warnings) != 1 {
+		t.Fatalf("schedule = %+v", s)
+	}
+	if s.Workers[0].Name != "agent-1" || s.Workers[0].HoursPerDay != DefaultHoursPerDay {
+		t.Errorf("default roster = %+v", s.Workers)
+	}
+	// Without estimates each task takes the default hour.
+	if taskByID(t, s, "B").StartDay != taskByID(t, s, "A").EndDay {
+		t.Error("cycle member B should follow A")
+	}
+
+	// A task downstream of a cycle keeps its blocker and is not blamed.
+	issues = []model.Issue{
+		{ID: "A", Title: "A", Status: model.StatusOpen, Dependencies: blockedBy("A", "C")},
+		{ID: "B", Title: "B", Status: model.StatusOpen, Dependencies: blockedBy("B", "C")},
+		{ID: "C", Title: "C", Status: model.StatusOpen, Dependencies: blockedBy("C", "B")},
+	}
+	s = BuildSchedule(issues, DefaultRoster(issues, 0), ScheduleOptions{})
+	if len(s.Tasks) != 3 || len(s.Warnings) != 1 {
+		t.Fatalf("schedule = %+v", s)
+	}
+	if w := s.Warnings[0]; !strings.Contains(w, "through B, C;") {
+		t.Errorf("warning should name only the cycle members: %q", w)
+	}
+	a, c := taskByID(t, s, "A"), taskByID(t, s, "C")
+	if a.StartDay < c.EndDay || len(a.BlockedBy) != 1 || a.BlockedBy[0] != "C" {
+		t.Errorf("A should follow its blocker C: A=%+v C=%+v", a, c)
+	}
+}
+
+func TestLoadRoster(t *testing.T) {
+	dir := t.TempDir()
+	r, err := LoadRoster(RosterPath(dir))
+	if err != nil || len(r.Workers) != 0 {
+		t.Fatalf("missing roster: %+v, %v", r, err)
+	}
+
+	path := RosterPath(dir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	yaml := "default_hours_per_day: 5\nworkers:\n  - name: ana\n    skills: [api]\n  - name: agent-1\n    kind: agent\n    hours_per_day: 20\n"
+	if err := os.WriteFile(path, []byte(yaml), 0644); err != nil {
+		t.Fatal(err)
+	}
+	r, err = LoadRoster(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.DefaultHoursPerDay != 5 || len(r.Workers) != 2 || r.Workers[0].Skills[0] != "api" || r.Workers[1].HoursPerDay != 20 {
+		t.Fatalf("roster = %+v", r)
+	}
+
+	if err := os.WriteFile(path, []byte("workers:\n  - name: ana\n  - name: ana\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadRoster(path); err == nil {
+		t.Error("expected an error for a duplicate worker")
+	}
+}
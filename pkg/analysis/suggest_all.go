@@ -21,6 +21,12 @@ type SuggestAllConfig struct {
 	// Cycles warning config
 	Cycles CycleWarningConfig
 
+	// Priority inversion config
+	PriorityInversions PriorityInversionConfig
+
+	// Redundant dependency config
+	RedundantDependencies RedundantDependencyConfig
+
 	// EnableDuplicates enables duplicate detection
 	EnableDuplicates bool
 
@@ -33,6 +39,12 @@ type SuggestAllConfig struct {
 	// EnableCycles enables cycle warnings
 	EnableCycles bool
 
+	// EnablePriorityInversions enables priority inversion suggestions
+	EnablePriorityInversions bool
+
+	// EnableRedundantDependencies enables redundant dependency suggestions
+	EnableRedundantDependencies bool
+
 	// MinConfidence filters suggestions below this threshold
 	MinConfidence float64
 
@@ -49,16 +61,20 @@ type SuggestAllConfig struct {
 // DefaultSuggestAllConfig returns sensible defaults with all features enabled
 func DefaultSuggestAllConfig() SuggestAllConfig {
 	return SuggestAllConfig{
-		Duplicates:         DefaultDuplicateConfig(),
-		Dependencies:       DefaultDependencySuggestionConfig(),
-		Labels:             DefaultLabelSuggestionConfig(),
-		Cycles:             DefaultCycleWarningConfig(),
-		EnableDuplicates:   true,
-		EnableDependencies: true,
-		EnableLabels:       true,
-		EnableCycles:       true,
-		MinConfidence:      0.0,
-		MaxSuggestions:     50,
+		Duplicates:                  DefaultDuplicateConfig(),
+		Dependencies:                DefaultDependencySuggestionConfig(),
+		Labels:                      DefaultLabelSuggestionConfig(),
+		Cycles:                      DefaultCycleWarningConfig(),
+		PriorityInversions:          DefaultPriorityInversionConfig(),
+		RedundantDependencies:       DefaultRedundantDependencyConfig(),
+		EnableDuplicates:            true,
+		EnableDependencies:          true,
+		EnableLabels:                true,
+		EnableCycles:                true,
+		EnablePriorityInversions:    true,
+		EnableRedundantDependencies: true,
+		MinConfidence:               0.0,
+		MaxSuggestions:              50,
 	}
 }
 
@@ -87,6 +103,16 @@ func GenerateAllSuggestions(issues []model.Issue, config SuggestAllConfig, dataH
 		allSuggestions = append(allSuggestions, cycles...)
 	}
 
+	if config.EnablePriorityInversions && (config.FilterType == "" || config.FilterType == SuggestionPriorityInversion) {
+		inversions := DetectPriorityInversionSuggestions(issues, config.PriorityInversions)
+		allSuggestions = append(allSuggestions, inversions...)
+	}
+
+	if config.EnableRedundantDependencies && (config.FilterType == "" || config.FilterType == SuggestionRedundantDependency) {
+		redundant := DetectRedundantDependencies(issues, config.RedundantDependencies)
+		allSuggestions = append(allSuggestions, redundant...)
+	}
+
 	// Apply filters
 	filtered := make([]Suggestion, 0, len(allSuggestions))
 	for _, sug := range allSuggestions {
@@ -157,6 +183,8 @@ func GenerateRobotSuggestOutput(issues []model.Issue, config SuggestAllConfig, d
 			"jq '.suggestions.stats.by_type' - Count by suggestion type",
 			"jq '.suggestions.suggestions[].action_command' - All action commands",
 			"--suggest-type=dependency - Filter to dependency suggestions",
+			"--suggest-type=inversion - Low-priority blockers holding up high-priority work",
+			"--suggest-type=redundant - Blocking edges already implied by longer chains",
 			"--suggest-confidence=0.7 - Minimum confidence threshold",
 			"--suggest-bead=<id> - Suggestions for specific bead",
 		},
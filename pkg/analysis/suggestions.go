@@ -22,6 +22,12 @@ const (
 
 	// SuggestionCycleWarning warns about potential dependency cycles
 	SuggestionCycleWarning SuggestionType = "cycle_warning"
+
+	// SuggestionPriorityInversion suggests raising a blocker to the priority of the work it holds up
+	SuggestionPriorityInversion SuggestionType = "priority_inversion"
+
+	// SuggestionRedundantDependency suggests removing a blocking edge implied by a longer chain
+	SuggestionRedundantDependency SuggestionType = "redundant_dependency"
 )
 
 // Suggestion represents a smart recommendation for project hygiene
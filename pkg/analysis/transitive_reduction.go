@@ -0,0 +1,194 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/graph/topo"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// RedundantEdge is a blocking dependency already implied by a longer chain
+// of blockers: From depends on To directly and through Path.
+type RedundantEdge struct {
+	From string   `json:"from"` // The dependent issue
+	To   string   `json:"to"`   // The blocker it lists directly
+	Path []string `json:"path"` // From, intermediate blockers..., To
+}
+
+// TransitiveReduction lists the blocking edges that can be removed without
+// changing which issues block which.
+type TransitiveReduction struct {
+	Edges     int             `json:"edges"` // Blocking edges between known issues
+	Redundant []RedundantEdge `json:"redundant"`
+
+	redundant map[[2]string]bool
+}
+
+// ComputeTransitiveReduction finds every blocking edge From→To for which a
+// longer chain From→...→To exists. Intermediate issues must be open: a
+// closed blocker no longer blocks, so a chain through it implies nothing.
+// Edges inside a dependency cycle have no unique reduction and are left
+// to the cycle warnings.
+func ComputeTransitiveReduction(issues []model.Issue) TransitiveReduction {
+	index := make(map[string]int64, len(issues))
+	open := make(map[string]bool, len(issues))
+	for i, iss := range issues {
+		if _, dup := index[iss.ID]; dup {
+			continue
+		}
+		index[iss.ID] = int64(i)
+		open[iss.ID] = !isClosedLikeStatus(iss.Status)
+	}
+
+	g := simple.NewDirectedGraph()
+	for _, id := range index {
+		g.AddNode(simple.Node(id))
+	}
+	succ := make(map[string][]string)
+	direct := make(map[[2]string]bool)
+	for _, iss := range issues {
+		for _, dep := range iss.Dependencies {
+			if dep == nil || !dep.Type.IsBlocking() || dep.DependsOnID == iss.ID {
+				continue
+			}
+			to, ok := index[dep.DependsOnID]
+			key := [2]string{iss.ID, dep.DependsOnID}
+			if !ok || direct[key] {
+				continue
+			}
+			direct[key] = true
+			succ[iss.ID] = append(succ[iss.ID], dep.DependsOnID)
+			g.SetEdge(g.NewEdge(simple.Node(index[iss.ID]), simple.Node(to)))
+		}
+	}
+
+	component := make(map[int64]int, len(index))
+	for c, scc := range topo.TarjanSCC(g) {
+		for _, n := range scc {
+			component[n.ID()] = c
+		}
+	}
+
+	r := TransitiveReduction{Edges: len(direct), Redundant: []RedundantEdge{}, redundant: make(map[[2]string]bool)}
+	sources := make([]string, 0, len(succ))
+	for id := range succ {
+		sort.Strings(succ[id])
+		sources = append(sources, id)
+	}
+	sort.Strings(sources)
+
+	for _, u := range sources {
+		// Breadth-first from u's open blockers; longer[v] is the last hop of
+		// the shortest chain of two or more edges reaching direct blocker v.
+		parent := map[string]string{u: ""}
+		var queue []string
+		for _, w := range succ[u] {
+			parent[w] = u
+			if open[w] {
+				queue = append(queue, w)
+			}
+		}
+		longer := make(map[string]string)
+		for len(queue) > 0 {
+			x := queue[0]
+			queue = queue[1:]
+			for _, y := range succ[x] {
+				if direct[[2]string{u, y}] && longer[y] == "" {
+					longer[y] = x
+				}
+				if _, seen := parent[y]; !seen {
+					parent[y] = x
+					if open[y] {
+						queue = append(queue, y)
+					}
+				}
+			}
+		}
+
+		for _, v := range succ[u] {
+			last, ok := longer[v]
+			if !ok || component[index[u]] == component[index[v]] {
+				continue
+			}
+			path := []string{v}
+			for at := last; at != ""; at = parent[at] {
+				path = append(path, at)
+			}
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+			}
+			r.Redundant = append(r.Redundant, RedundantEdge{From: u, To: v, Path: path})
+			r.redundant[[2]string{u, v}] = true
+		}
+	}
+	return r
+}
+
+// IsRedundant reports whether from's blocking dependency on to is implied
+// by a longer chain.
+func (r TransitiveReduction) IsRedundant(from, to string) bool {
+	return r.redundant[[2]string{from, to}]
+}
+
+// Apply returns copies of issues without their redundant blocking
+// dependencies. issues is not modified.
+func (r TransitiveReduction) Apply(issues []model.Issue) []model.Issue {
+	reduced := make([]model.Issue, len(issues))
+	for i, iss := range issues {
+		reduced[i] = iss
+		var deps []*model.Dependency
+		dropped := false
+		for _, dep := range iss.Dependencies {
+			if dep != nil && dep.Type.IsBlocking() && r.IsRedundant(iss.ID, dep.DependsOnID) {
+				dropped = true
+				continue
+			}
+			deps = append(deps, dep)
+		}
+		if dropped {
+			reduced[i].Dependencies = deps
+		}
+	}
+	return reduced
+}
+
+// RedundantDependencyConfig configures redundant dependency suggestions
+type RedundantDependencyConfig struct {
+	// MaxSuggestions is the maximum number of redundant edges to report
+	// Default: 20
+	MaxSuggestions int
+}
+
+// DefaultRedundantDependencyConfig returns sensible defaults
+func DefaultRedundantDependencyConfig() RedundantDependencyConfig {
+	return RedundantDependencyConfig{
+		MaxSuggestions: 20,
+	}
+}
+
+// DetectRedundantDependencies suggests removing blocking edges implied by
+// longer chains. Short implying chains are the most obviously redundant.
+func DetectRedundantDependencies(issues []model.Issue, config RedundantDependencyConfig) []Suggestion {
+	var suggestions []Suggestion
+	for i, edge := range ComputeTransitiveReduction(issues).Redundant {
+		if config.MaxSuggestions > 0 && i >= config.MaxSuggestions {
+			break
+		}
+		intermediates := len(edge.Path) - 2
+		confidence := max(0.9-0.05*float64(intermediates-1), 0.6)
+
+		suggestions = append(suggestions, NewSuggestion(
+			SuggestionRedundantDependency,
+			edge.From,
+			fmt.Sprintf("%s → %s is implied by a longer chain", edge.From, edge.To),
+			fmt.Sprintf("Already blocked through %s", formatCyclePath(edge.Path)),
+			confidence,
+		).WithRelatedBead(edge.To).
+			WithAction(fmt.Sprintf("br dep remove %s %s", edge.From, edge.To)).
+			WithMetadata("path", edge.Path))
+	}
+	return suggestions
+}
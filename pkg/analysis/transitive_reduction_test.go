@@ -0,0 +1,70 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestComputeTransitiveReduction(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "a", Status: model.StatusOpen, Dependencies: blockedBy("a", "b", "c", "d")},
+		{ID: "b", Status: model.StatusOpen, Dependencies: blockedBy("b", "c")},
+		{ID: "c", Status: model.StatusOpen, Dependencies: blockedBy("c", "d")},
+		{ID: "d", Status: model.StatusOpen},
+		// A closed intermediate no longer blocks, so q's edge to s stands.
+		{ID: "q", Status: model.StatusOpen, Dependencies: blockedBy("q", "r", "s")},
+		{ID: "r", Status: model.StatusClosed, Dependencies: blockedBy("r", "s")},
+		{ID: "s", Status: model.StatusOpen},
+		// Edges inside a cycle are left to the cycle warnings.
+		{ID: "x", Status: model.StatusOpen, Dependencies: blockedBy("x", "y", "z")},
+		{ID: "y", Status: model.StatusOpen, Dependencies: blockedBy("y", "z")},
+		{ID: "z", Status: model.StatusOpen, Dependencies: blockedBy("z", "x")},
+	}
+
+	r := ComputeTransitiveReduction(issues)
+	var got []string
+	for _, e := range r.Redundant {
+		got = append(got, e.From+">"+e.To+":"+strings.Join(e.Path, ","))
+	}
+	if want := "a>c:a,b,c|a>d:a,c,d"; strings.Join(got, "|") != want {
+		t.Fatalf("redundant = %v, want %s", got, want)
+	}
+	if r.Edges != 12 || !r.IsRedundant("a", "d") || r.IsRedundant("q", "s") || r.IsRedundant("x", "z") {
+		t.Errorf("edges %d, lookups a>d %v q>s %v x>z %v", r.Edges, r.IsRedundant("a", "d"), r.IsRedundant("q", "s"), r.IsRedundant("x", "z"))
+	}
+
+	reduced := r.Apply(issues)
+	if len(reduced[0].Dependencies) != 1 || reduced[0].Dependencies[0].DependsOnID != "b" {
+		t.Errorf("reduced a deps = %+v", reduced[0].Dependencies)
+	}
+	if len(issues[0].Dependencies) != 3 {
+		t.Error("Apply modified its input")
+	}
+}
+
+func TestDetectRedundantDependencies(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "a", Status: model.StatusOpen, Dependencies: blockedBy("a", "b", "c")},
+		{ID: "b", Status: model.StatusOpen, Dependencies: blockedBy("b", "c")},
+		{ID: "c", Status: model.StatusOpen},
+	}
+	sugs := DetectRedundantDependencies(issues, DefaultRedundantDependencyConfig())
+	if len(sugs) != 1 {
+		t.Fatalf("suggestions = %+v", sugs)
+	}
+	s := sugs[0]
+	if s.Type != SuggestionRedundantDependency || s.TargetBead != "a" || s.RelatedBead != "c" {
+		t.Errorf("suggestion = %+v", s)
+	}
+	if s.ActionCommand != "br dep remove a c" || s.Confidence != 0.9 {
+		t.Errorf("action %q, confidence %v", s.ActionCommand, s.Confidence)
+	}
+
+	config := DefaultSuggestAllConfig()
+	config.FilterType = SuggestionRedundantDependency
+	if set := GenerateAllSuggestions(issues, config, ""); set.Stats.ByType[SuggestionRedundantDependency] != 1 || set.Stats.Total != 1 {
+		t.Errorf("GenerateAllSuggestions stats = %+v", set.Stats)
+	}
+}
@@ -358,6 +358,7 @@ func (e *Extractor) parseDiff(diffData []byte, info commitInfo, filterBeadID str
 			CommitMsg:   info.Message,
 			Author:      info.Author,
 			AuthorEmail: info.AuthorEmail,
+			Status:      newSnap.Status,
 		}
 
 		if !hadOld && hasNew {
@@ -40,6 +40,7 @@ func (e EventType) IsValid() bool {
 type BeadEvent struct {
 	BeadID      string    `json:"bead_id"`
 	EventType   EventType `json:"event_type"`
+	Status      string    `json:"status,omitempty"` // Bead status after this commit
 	Timestamp   time.Time `json:"timestamp"`
 	CommitSHA   string    `json:"commit_sha"`
 	CommitMsg   string    `json:"commit_message"`
@@ -38,6 +38,7 @@ const (
 	AlertHighImpactUnblock  AlertType = "high_impact_unblock"
 	AlertAbandonedClaim     AlertType = "abandoned_claim"
 	AlertPotentialDuplicate AlertType = "potential_duplicate"
+	AlertPriorityInversion  AlertType = "priority_inversion"
 )
 
 // Alert represents a single drift detection alert
@@ -128,6 +129,9 @@ func (c *Calculator) Calculate() *Result {
 	// Check blocking cascades (uses current issues if provided)
 	c.checkBlockingCascade(result)
 
+	// Check priority inversions (uses current issues if provided)
+	c.checkPriorityInversions(result)
+
 	// Compute summary
 	for _, alert := range result.Alerts {
 		switch alert.Severity {
@@ -512,6 +516,35 @@ func (c *Calculator) checkBlockingCascade(result *Result) {
 	}
 }
 
+// checkPriorityInversions raises alerts for open blockers with a lower
+// priority than the work they transitively hold up. Inversions blocking
+// P0/P1 work are warnings; the rest are info.
+func (c *Calculator) checkPriorityInversions(result *Result) {
+	if c.config.IsAlertDisabled(string(AlertPriorityInversion)) {
+		return
+	}
+	if len(c.issues) == 0 {
+		return
+	}
+
+	now := time.Now().UTC()
+	for _, inv := range analysis.DetectPriorityInversions(c.issues) {
+		severity := SeverityInfo
+		if inv.InheritedPriority <= 1 {
+			severity = SeverityWarning
+		}
+		result.Alerts = append(result.Alerts, Alert{
+			Type:     AlertPriorityInversion,
+			Severity: severity,
+			Message: fmt.Sprintf("P%d %s blocks P%d %s; propose inheriting P%d",
+				inv.BlockerPriority, inv.BlockerID, inv.BlockedPriority, inv.BlockedID, inv.InheritedPriority),
+			IssueID:    inv.BlockerID,
+			DetectedAt: now,
+			Details:    inv.Chain,
+		})
+	}
+}
+
 // cycleKey creates a normalized key for a cycle for comparison.
 // It rotates the cycle so the lexicographically smallest element is first,
 // preserving the order (direction) of elements.
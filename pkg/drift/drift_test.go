@@ -300,6 +300,49 @@ func TestCalculatorBlockingCascadeWithPriorities(t *testing.T) {
 	}
 }
 
+func TestCalculatorPriorityInversion(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "ship", Title: "Ship release", Status: model.StatusOpen, Priority: 0, Dependencies: []*model.Dependency{{DependsOnID: "api", Type: model.DepBlocks}}},
+		{ID: "api", Title: "API", Status: model.StatusOpen, Priority: 1, Dependencies: []*model.Dependency{{DependsOnID: "chore", Type: model.DepBlocks}}},
+		{ID: "chore", Title: "Forgotten chore", Status: model.StatusOpen, Priority: 3},
+		{ID: "docs", Title: "Docs", Status: model.StatusOpen, Priority: 2, Dependencies: []*model.Dependency{{DependsOnID: "typo", Type: model.DepBlocks}}},
+		{ID: "typo", Title: "Typo", Status: model.StatusOpen, Priority: 4},
+	}
+	bl := &baseline.Baseline{Stats: baseline.GraphStats{}}
+	current := &baseline.Baseline{Stats: baseline.GraphStats{}}
+
+	calc := NewCalculator(bl, current, DefaultConfig())
+	calc.SetIssues(issues)
+	result := calc.Calculate()
+
+	byIssue := make(map[string]Alert)
+	for _, a := range result.Alerts {
+		if a.Type == AlertPriorityInversion {
+			byIssue[a.IssueID] = a
+		}
+	}
+	if len(byIssue) != 3 {
+		t.Fatalf("expected 3 inversion alerts, got %+v", byIssue)
+	}
+	chore := byIssue["chore"]
+	if chore.Severity != SeverityWarning || strings.Join(chore.Details, ",") != "ship,api,chore" || !strings.Contains(chore.Message, "inheriting P0") {
+		t.Errorf("chore alert = %+v", chore)
+	}
+	if byIssue["typo"].Severity != SeverityInfo {
+		t.Errorf("typo alert = %+v", byIssue["typo"])
+	}
+
+	cfg := DefaultConfig()
+	cfg.DisabledAlerts = []string{string(AlertPriorityInversion)}
+	calc = NewCalculator(bl, current, cfg)
+	calc.SetIssues(issues)
+	for _, a := range calc.Calculate().Alerts {
+		if a.Type == AlertPriorityInversion {
+			t.Fatalf("disabled alert type still raised: %+v", a)
+		}
+	}
+}
+
 func TestResultSummary(t *testing.T) {
 	result := &Result{
 		HasDrift: true,
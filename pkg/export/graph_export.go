@@ -18,15 +18,17 @@ const (
 	GraphFormatJSON    GraphExportFormat = "json"
 	GraphFormatDOT     GraphExportFormat = "dot"
 	GraphFormatMermaid GraphExportFormat = "mermaid"
+	GraphFormatGantt   GraphExportFormat = "gantt"
 )
 
 // GraphExportConfig configures graph export behavior.
 type GraphExportConfig struct {
-	Format   GraphExportFormat // Output format (json, dot, mermaid)
+	Format   GraphExportFormat // Output format (json, dot, mermaid, gantt)
 	Label    string            // Filter to specific label
 	Root     string            // Subgraph from specific root
 	Depth    int               // Max depth for subgraph (0 = unlimited)
 	DataHash string            // Hash of input data for provenance
+	Reduced  bool              // Drop blocking edges implied by longer chains (transitive reduction)
 }
 
 // GraphExportResult contains the exported graph and metadata.
@@ -39,6 +41,7 @@ type GraphExportResult struct {
 	Explanation    GraphExplanation  `json:"explanation"`
 	DataHash       string            `json:"data_hash,omitempty"`
 	Adjacency      *AdjacencyGraph   `json:"adjacency,omitempty"`
+	RedundantEdges int               `json:"redundant_edges_removed,omitempty"`
 }
 
 // GraphExplanation provides context for AI agents.
@@ -88,6 +91,14 @@ func ExportGraph(issues []model.Issue, stats *analysis.GraphStats, config GraphE
 		}, nil
 	}
 
+	// Drop redundant blocking edges if requested
+	redundantEdges := 0
+	if config.Reduced {
+		reduction := analysis.ComputeTransitiveReduction(filteredIssues)
+		redundantEdges = len(reduction.Redundant)
+		filteredIssues = reduction.Apply(filteredIssues)
+	}
+
 	// Build issue ID set for edge filtering
 	issueIDs := make(map[string]bool, len(filteredIssues))
 	for _, i := range filteredIssues {
@@ -115,6 +126,9 @@ func ExportGraph(issues []model.Issue, stats *analysis.GraphStats, config GraphE
 	if config.Depth > 0 {
 		filtersApplied["depth"] = fmt.Sprintf("%d", config.Depth)
 	}
+	if config.Reduced {
+		filtersApplied["reduced"] = "true"
+	}
 
 	result := &GraphExportResult{
 		Format:         string(config.Format),
@@ -122,6 +136,7 @@ func ExportGraph(issues []model.Issue, stats *analysis.GraphStats, config GraphE
 		Edges:          edgeCount,
 		FiltersApplied: filtersApplied,
 		DataHash:       config.DataHash,
+		RedundantEdges: redundantEdges,
 	}
 
 	switch config.Format {
@@ -143,6 +158,14 @@ func ExportGraph(issues []model.Issue, stats *analysis.GraphStats, config GraphE
 			WhenToUse:   "When you need an embeddable diagram for documentation or GitHub issues",
 		}
 
+	case GraphFormatGantt:
+		result.Graph = GenerateMermaidGantt(filteredIssues, stats, GanttConfig{})
+		result.Explanation = GraphExplanation{
+			What:        "Open work as a Mermaid gantt chart: sections per execution track, bars from estimates, after clauses from blockers, critical path marked crit",
+			HowToRender: "Paste into a ```mermaid block on GitHub, or use mermaid.live",
+			WhenToUse:   "When you need a timeline of the plan for a README or status update",
+		}
+
 	case GraphFormatJSON:
 		fallthrough
 	default:
@@ -413,3 +413,36 @@ func TestExportGraph_DeterministicOutput(t *testing.T) {
 		t.Error("DOT output should be deterministic across calls")
 	}
 }
+
+func TestExportGraph_Reduced(t *testing.T) {
+	blocks := func(from, to string) *model.Dependency {
+		return &model.Dependency{IssueID: from, DependsOnID: to, Type: model.DepBlocks}
+	}
+	issues := []model.Issue{
+		{ID: "bv-1", Title: "Base", Status: model.StatusOpen},
+		{ID: "bv-2", Title: "Middle", Status: model.StatusOpen, Dependencies: []*model.Dependency{blocks("bv-2", "bv-1")}},
+		{ID: "bv-3", Title: "Top", Status: model.StatusOpen, Dependencies: []*model.Dependency{blocks("bv-3", "bv-2"), blocks("bv-3", "bv-1")}},
+	}
+
+	analyzer := analysis.NewAnalyzer(issues)
+	stats := analyzer.Analyze()
+
+	result, err := ExportGraph(issues, &stats, GraphExportConfig{Format: GraphFormatJSON, Reduced: true})
+	if err != nil {
+		t.Fatalf("ExportGraph failed: %v", err)
+	}
+	if result.Edges != 2 || len(result.Adjacency.Edges) != 2 || result.RedundantEdges != 1 {
+		t.Errorf("Expected 2 edges after removing 1 redundant, got %d (%d adjacency, %d removed)", result.Edges, len(result.Adjacency.Edges), result.RedundantEdges)
+	}
+	for _, e := range result.Adjacency.Edges {
+		if e.From == "bv-3" && e.To == "bv-1" {
+			t.Error("Redundant edge bv-3 -> bv-1 should be dropped")
+		}
+	}
+	if result.FiltersApplied["reduced"] != "true" {
+		t.Errorf("Expected reduced filter, got %v", result.FiltersApplied)
+	}
+	if len(issues[2].Dependencies) != 2 {
+		t.Error("ExportGraph modified its input")
+	}
+}
@@ -32,6 +32,7 @@ type InteractiveGraphOptions struct {
 	DataHash    string
 	Path        string // Output path - if empty, auto-generates based on project
 	ProjectName string // Project name for auto-naming
+	Reduced     bool   // Render only the transitive reduction of blocking edges
 }
 
 // graphNode represents a node in the interactive graph with full bead data
@@ -82,6 +83,10 @@ type graphNode struct {
 	IsArticulation  bool    `json:"is_articulation"`
 	PageRankRank    int     `json:"pagerank_rank"`
 	BetweennessRank int     `json:"betweenness_rank"`
+
+	// Dependency-graph community (0 = none)
+	Community      int    `json:"community,omitempty"`
+	CommunityTitle string `json:"community_title,omitempty"`
 }
 
 // graphLink represents an edge in the interactive graph
@@ -119,6 +124,11 @@ func GenerateInteractiveGraphHTML(opts InteractiveGraphOptions) (string, error)
 		return "", fmt.Errorf("no issues to export")
 	}
 
+	// Drop blocking edges already implied by longer chains
+	if opts.Reduced {
+		opts.Issues = analysis.ComputeTransitiveReduction(opts.Issues).Apply(opts.Issues)
+	}
+
 	// Build graph data with all metrics
 	nodes := make([]graphNode, 0, len(opts.Issues))
 	links := make([]graphLink, 0)
@@ -158,6 +168,10 @@ func GenerateInteractiveGraphHTML(opts InteractiveGraphOptions) (string, error)
 		articulationSet[id] = true
 	}
 
+	// Cluster every node, closed ones included, for community coloring
+	communities := analysis.DetectCommunities(opts.Issues, analysis.CommunityOptions{IncludeClosed: true})
+	communityOf := communities.CommunityOf()
+
 	// Build reverse dependency map (who blocks who)
 	blocksMap := make(map[string][]string)
 	for _, iss := range opts.Issues {
@@ -256,6 +270,10 @@ func GenerateInteractiveGraphHTML(opts InteractiveGraphOptions) (string, error)
 			PageRankRank:    pageRankRank[iss.ID],
 			BetweennessRank: betweennessRank[iss.ID],
 		}
+		if c, ok := communityOf[iss.ID]; ok {
+			node.Community = communities.Communities[c].ID
+			node.CommunityTitle = communities.Communities[c].SuggestedTitle
+		}
 		nodes = append(nodes, node)
 
 		// Build links from dependencies
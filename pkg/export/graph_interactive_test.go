@@ -1,6 +1,7 @@
 package export
 
 import (
+	"os"
 	"strings"
 	"testing"
 
@@ -77,3 +78,31 @@ func TestGenerateInteractiveGraphHTML_Basic(t *testing.T) {
 		t.Error("Expected non-empty path")
 	}
 }
+
+func TestGenerateInteractiveGraphHTML_Communities(t *testing.T) {
+	blocks := func(from, to string) []*model.Dependency {
+		return []*model.Dependency{{IssueID: from, DependsOnID: to, Type: model.DepBlocks}}
+	}
+	issues := []model.Issue{
+		{ID: "bv-1", Title: "Cache layer", Status: model.StatusOpen},
+		{ID: "bv-2", Title: "Cache eviction", Status: model.StatusOpen, Dependencies: blocks("bv-2", "bv-1")},
+		{ID: "bv-3", Title: "Cache metrics", Status: model.StatusClosed, Dependencies: blocks("bv-3", "bv-2")},
+		{ID: "bv-4", Title: "Loner", Status: model.StatusOpen},
+	}
+
+	path, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{Issues: issues, Path: t.TempDir() + "/graph.html"})
+	if err != nil {
+		t.Fatalf("GenerateInteractiveGraphHTML failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+	if strings.Count(html, `"community":1,"community_title":"Cache"`) != 3 {
+		t.Error("Expected all three cache issues (closed included) in community 1")
+	}
+	if !strings.Contains(html, `id="btn-community"`) {
+		t.Error("Expected community coloring toggle")
+	}
+}
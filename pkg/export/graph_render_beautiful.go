@@ -654,6 +654,7 @@ func generateUltimateHTML(title, dataHash, graphDataJSON string, nodeCount, edge
             </div>
             <div class="toolbar-group">
                 <button id="btn-heatmap" title="Toggle heatmap coloring - shows node importance by color intensity (H)">🔥</button>
+                <button id="btn-community" title="Toggle community coloring - colors nodes by dependency-graph cluster (C)">🧩</button>
                 <button id="btn-triage" title="Show/hide triage recommendations panel with prioritized work items (G)">📋</button>
                 <button id="btn-top" title="Show/hide top nodes panel with highest PageRank nodes (T)">⭐</button>
                 <button id="btn-recent" title="Show/hide recently viewed nodes (Y)">🕐</button>
@@ -853,7 +854,8 @@ func generateUltimateHTML(title, dataHash, graphDataJSON string, nodeCount, edge
                 <div class="keyboard-hints">
                     <kbd>F</kbd> Fit · <kbd>R</kbd> Reset · <kbd>Space</kbd> Fullscreen<br>
                     <kbd>Esc</kbd> Clear · <kbd>1-4</kbd> View modes<br>
-                    <kbd>H</kbd> Heatmap · <kbd>T</kbd> Top · <kbd>G</kbd> Triage
+                    <kbd>H</kbd> Heatmap · <kbd>C</kbd> Communities<br>
+                    <kbd>T</kbd> Top · <kbd>G</kbd> Triage
                 </div>
             </div>
         </div>
@@ -892,6 +894,7 @@ func generateUltimateHTML(title, dataHash, graphDataJSON string, nodeCount, edge
                     <div class="help-item"><span class="help-key">D</span> Dock/detach detail panel</div>
                     <div class="help-item"><span class="help-key">L</span> Toggle light/dark mode</div>
                     <div class="help-item"><span class="help-key">H</span> Toggle heatmap coloring</div>
+                    <div class="help-item"><span class="help-key">C</span> Toggle community coloring</div>
                     <div class="help-item"><span class="help-key">T</span> Show top nodes panel</div>
                     <div class="help-item"><span class="help-key">G</span> Show triage panel</div>
                     <div class="help-item"><span class="help-key">Y</span> Show recently viewed</div>
@@ -953,7 +956,7 @@ const maxBW = Math.max(...DATA.nodes.map(n => n.betweenness || 0), 0.001);
 const maxCP = Math.max(...DATA.nodes.map(n => n.critical_path || 0), 1);
 const maxInDeg = Math.max(...DATA.nodes.map(n => n.in_degree || 0), 1);
 
-let sizeMetric = 'pagerank', heatmapMode = false, hoveredNode = null, highlightedNodes = new Set();
+let sizeMetric = 'pagerank', heatmapMode = false, communityMode = false, hoveredNode = null, highlightedNodes = new Set();
 
 function getNodeSize(n) {
     const base = 5, scale = 16;
@@ -979,6 +982,18 @@ function getHeatmapColor(n) {
     return 'hsl(' + hue + ', 80%%, 50%%)';
 }
 
+// Communities get well-separated hues (golden angle); unclustered nodes stay grey
+function getCommunityColor(n) {
+    if (!n.community) return '#555577';
+    return 'hsl(' + ((n.community * 137.508) %% 360) + ', 65%%, 55%%)';
+}
+
+function baseNodeColor(n) {
+    if (heatmapMode) return getHeatmapColor(n);
+    if (communityMode) return getCommunityColor(n);
+    return STATUS_COLORS[n.status] || '#555577';
+}
+
 // Get connected subgraph (for golden glow highlight)
 function getConnectedNodes(nodeId, depth = 2) {
     const connected = new Set([nodeId]);
@@ -1004,8 +1019,7 @@ const Graph = ForceGraph()(container)
     .nodeLabel(null)
     .nodeColor(n => {
         if (highlightedNodes.size > 0 && !highlightedNodes.has(n.id)) return (STATUS_COLORS[n.status] || '#555577') + '20';
-        if (heatmapMode) return getHeatmapColor(n);
-        return STATUS_COLORS[n.status] || '#555577';
+        return baseNodeColor(n);
     })
     .nodeVal(n => getNodeSize(n))
     .linkColor(l => {
@@ -1042,7 +1056,7 @@ const Graph = ForceGraph()(container)
         const x = node.x, y = node.y;
         if (x === undefined || y === undefined || !isFinite(x) || !isFinite(y)) return;
         const size = getNodeSize(node);
-        const baseColor = heatmapMode ? getHeatmapColor(node) : STATUS_COLORS[node.status] || '#555577';
+        const baseColor = baseNodeColor(node);
         const isHighlighted = highlightedNodes.size === 0 || highlightedNodes.has(node.id);
         const isHovered = hoveredNode && hoveredNode.id === node.id;
         const alpha = isHighlighted ? 1 : 0.15;
@@ -1167,6 +1181,7 @@ function populatePanelContent(prefix, node) {
     addBadge('badge-' + node.status, node.status.replace('_', ' '));
     addBadge('', 'P' + node.priority);
     if (node.is_articulation) addBadge('badge-articulation', 'Cut Vertex');
+    if (node.community) addBadge('', '🧩 ' + node.community_title);
     if (node.slack === 0) addBadge('badge-critical', 'Critical Path');
     (node.labels || []).forEach(l => addBadge('badge-type', l));
 
@@ -1563,7 +1578,7 @@ document.getElementById('btn-reset').onclick = () => {
     document.getElementById('search-input').value = '';
     document.getElementById('view-mode').value = 'force';
     document.getElementById('size-by').value = 'pagerank';
-    statusFilter = ''; typeFilter = ''; sizeMetric = 'pagerank'; heatmapMode = false;
+    statusFilter = ''; typeFilter = ''; sizeMetric = 'pagerank'; heatmapMode = false; communityMode = false;
     highlightedNodes = new Set();
     Graph.dagMode(null); Graph.nodeVisibility(() => true); Graph.nodeVal(n => getNodeSize(n));
     Graph.nodeColor(n => STATUS_COLORS[n.status] || '#555577');
@@ -1573,6 +1588,7 @@ document.getElementById('btn-reset').onclick = () => {
     document.getElementById('top-nodes-panel').classList.remove('visible');
     document.getElementById('triage-panel').style.display = 'none';
     document.getElementById('btn-heatmap').classList.remove('active');
+    document.getElementById('btn-community').classList.remove('active');
     document.getElementById('btn-triage').classList.remove('active');
     document.getElementById('btn-top').classList.remove('active');
 };
@@ -1580,9 +1596,21 @@ document.getElementById('btn-reset').onclick = () => {
 // Heatmap toggle - legend always visible, toggle controls coloring mode
 document.getElementById('btn-heatmap').onclick = () => {
     heatmapMode = !heatmapMode;
+    communityMode = false;
     document.getElementById('btn-heatmap').classList.toggle('active', heatmapMode);
+    document.getElementById('btn-community').classList.remove('active');
     document.getElementById('heatmap-legend').classList.toggle('heatmap-active', heatmapMode);
-    Graph.nodeColor(n => heatmapMode ? getHeatmapColor(n) : STATUS_COLORS[n.status] || '#555577');
+    Graph.nodeColor(n => baseNodeColor(n));
+};
+
+// Community toggle - color nodes by the cluster they belong to
+document.getElementById('btn-community').onclick = () => {
+    communityMode = !communityMode;
+    heatmapMode = false;
+    document.getElementById('btn-community').classList.toggle('active', communityMode);
+    document.getElementById('btn-heatmap').classList.remove('active');
+    document.getElementById('heatmap-legend').classList.remove('heatmap-active');
+    Graph.nodeColor(n => baseNodeColor(n));
 };
 
 // Triage panel
@@ -1831,6 +1859,7 @@ document.onkeydown = e => {
             break;
         case ' ': e.preventDefault(); document.getElementById('btn-fullscreen').click(); break;
         case 'h': document.getElementById('btn-heatmap').click(); break;
+        case 'c': document.getElementById('btn-community').click(); break;
         case 't': document.getElementById('btn-top').click(); break;
         case 'g': document.getElementById('btn-triage').click(); break;
         case 'd': togglePanelMode(); break;
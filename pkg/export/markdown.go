@@ -10,6 +10,7 @@ import (
 	"time"
 	"unicode"
 
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
 )
 
@@ -107,6 +108,9 @@ func GenerateMarkdown(issues []model.Issue, title string) (string, error) {
 	// Quick Actions Section
 	sb.WriteString(generateQuickActions(issues))
 
+	// Epic Progress Section
+	sb.WriteString(generateEpicProgress(issues))
+
 	// Precompute stable, unique slugs for TOC anchors and headings.
 	slugCounts := make(map[string]int, len(issues))
 	issueSlugs := make([]string, len(issues))
@@ -401,6 +405,54 @@ func generateQuickActions(issues []model.Issue) string {
 	return sb.String()
 }
 
+// generateEpicProgress creates a table of progress rolled up the
+// parent-child hierarchy, or nothing when no issue has children
+func generateEpicProgress(issues []model.Issue) string {
+	report := analysis.ComputeEpicRollups(issues)
+	if len(report.Epics) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## Epic Progress\n\n")
+	sb.WriteString("| Epic | Progress | Done | Remaining | Blocked | Actionable | Flags |\n")
+	sb.WriteString("|------|----------|------|-----------|---------|------------|-------|\n")
+	for _, e := range report.Epics {
+		name := fmt.Sprintf("%s**%s** %s", strings.Repeat("↳ ", e.Depth), e.ID, e.Title)
+		remaining := "-"
+		if e.RemainingMinutes > 0 {
+			remaining = fmt.Sprintf("%.1fh", float64(e.RemainingMinutes)/60)
+		}
+		var flags []string
+		for _, f := range e.Flags {
+			switch f {
+			case analysis.RollupFlagZombie:
+				flags = append(flags, "🧟 zombie")
+			case analysis.RollupFlagOrphanProgress:
+				flags = append(flags, "⚠️ orphan progress")
+			}
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s %.0f%% | %d/%d | %s | %d | %d | %s |\n",
+			strings.ReplaceAll(name, "|", "\\|"), progressBar(e.PercentComplete, 10), e.PercentComplete,
+			e.Closed, e.Descendants, remaining, e.Blocked, e.Actionable, strings.Join(flags, ", ")))
+	}
+	sb.WriteString("\n")
+	if len(report.Zombies) > 0 {
+		sb.WriteString(fmt.Sprintf("*Zombie epics (all children closed, parent still open): %s*\n\n", strings.Join(report.Zombies, ", ")))
+	}
+	if len(report.OrphanProgress) > 0 {
+		sb.WriteString(fmt.Sprintf("*Closed work under deferred parents: %s*\n\n", strings.Join(report.OrphanProgress, ", ")))
+	}
+	return sb.String()
+}
+
+// progressBar renders percent (0-100) as a bar of width cells
+func progressBar(percent float64, width int) string {
+	filled := int(percent/100*float64(width) + 0.5)
+	filled = min(max(filled, 0), width)
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
+
 // generateIssueCommands creates command snippets for a single issue
 func generateIssueCommands(issue model.Issue) string {
 	var sb strings.Builder
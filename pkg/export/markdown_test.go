@@ -1607,3 +1607,34 @@ func TestGenerateMarkdown_TombstoneIssueNoCommands(t *testing.T) {
 		t.Error("Tombstone issue should not have command snippets")
 	}
 }
+
+func TestGenerateMarkdown_EpicProgress(t *testing.T) {
+	now := time.Now()
+	child := func(id, parent string, status model.Status) model.Issue {
+		return model.Issue{ID: id, Title: "Task " + id, Status: status, CreatedAt: now, UpdatedAt: now,
+			Dependencies: []*model.Dependency{{IssueID: id, DependsOnID: parent, Type: model.DepParentChild}}}
+	}
+	issues := []model.Issue{
+		{ID: "EPIC-1", Title: "Launch | v2", Status: model.StatusOpen, IssueType: model.TypeEpic, CreatedAt: now, UpdatedAt: now},
+		child("T-1", "EPIC-1", model.StatusClosed),
+		child("T-2", "EPIC-1", model.StatusOpen),
+		{ID: "EPIC-2", Title: "Done already", Status: model.StatusOpen, IssueType: model.TypeEpic, CreatedAt: now, UpdatedAt: now},
+		child("T-3", "EPIC-2", model.StatusClosed),
+	}
+
+	md, err := GenerateMarkdown(issues, "Epic Test")
+	if err != nil {
+		t.Fatalf("GenerateMarkdown returned error: %v", err)
+	}
+	section := md[strings.Index(md, "## Epic Progress"):strings.Index(md, "## Table of Contents")]
+	if !strings.Contains(section, "| **EPIC-1** Launch \\| v2 | █████░░░░░ 50% | 1/2 |") {
+		t.Errorf("EPIC-1 row missing or malformed:\n%s", section)
+	}
+	if !strings.Contains(section, "🧟 zombie") || !strings.Contains(section, "Zombie epics (all children closed, parent still open): EPIC-2") {
+		t.Errorf("EPIC-2 should be flagged as a zombie:\n%s", section)
+	}
+
+	if md, _ := GenerateMarkdown(issues[:1], "No Children"); strings.Contains(md, "## Epic Progress") {
+		t.Error("Epic Progress should be omitted without parent-child links")
+	}
+}
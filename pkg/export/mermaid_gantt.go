@@ -0,0 +1,383 @@
+package export
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// Gantt section groupings.
+const (
+	GanttGroupTrack = "track" // One section per execution-plan track
+	GanttGroupEpic  = "epic"  // One section per parent epic
+)
+
+// GanttConfig configures Mermaid Gantt generation.
+type GanttConfig struct {
+	Title       string
+	GroupBy     string    // GanttGroupTrack (default) or GanttGroupEpic
+	Start       time.Time // Date unblocked work starts (default: now)
+	HoursPerDay float64   // Converts estimated_minutes to days (default: analysis.DefaultHoursPerDay)
+}
+
+func (c GanttConfig) withDefaults() GanttConfig {
+	if c.Start.IsZero() {
+		c.Start = time.Now()
+	}
+	if c.HoursPerDay <= 0 {
+		c.HoursPerDay = analysis.DefaultHoursPerDay
+	}
+	if c.Title == "" {
+		c.Title = "Project plan"
+	}
+	return c
+}
+
+// ganttTask is one open issue on the chart.
+type ganttTask struct {
+	issue    model.Issue
+	id       string  // Mermaid task ID
+	days     float64 // Bar length
+	blockers []int   // Open blockers drawn as `after` clauses
+	start    float64 // Earliest start, days from config.Start
+	crit     bool
+}
+
+// GenerateMermaidGantt renders the open issues as a Mermaid gantt chart.
+// Bars are sized from estimated_minutes at HoursPerDay, or from the ETA
+// model when an issue has no estimate. Open blockers become `after`
+// clauses, due dates become milestones, and the longest chain of blocked
+// work (the critical path) is marked crit.
+func GenerateMermaidGantt(issues []model.Issue, stats *analysis.GraphStats, config GanttConfig) string {
+	config = config.withDefaults()
+	tasks, sections, order := buildGanttTasks(issues, stats, config)
+
+	var sb strings.Builder
+	sb.WriteString("gantt\n")
+	sb.WriteString("    title " + ganttText(config.Title) + "\n")
+	sb.WriteString("    dateFormat YYYY-MM-DD\n")
+	sb.WriteString("    axisFormat %b %d\n")
+	startDate := config.Start.Format("2006-01-02")
+
+	if len(tasks) == 0 {
+		sb.WriteString("    section No open work\n")
+		sb.WriteString("    Nothing to schedule :done, " + startDate + ", 1d\n")
+		return sb.String()
+	}
+
+	for _, sec := range sections {
+		sb.WriteString("    section " + ganttText(sec.name) + "\n")
+		for _, i := range sec.tasks {
+			t := tasks[i]
+			var tags []string
+			if t.crit {
+				tags = append(tags, "crit")
+			}
+			if t.issue.Status == model.StatusInProgress {
+				tags = append(tags, "active")
+			}
+			tags = append(tags, t.id)
+			if len(t.blockers) > 0 {
+				after := make([]string, len(t.blockers))
+				for j, b := range t.blockers {
+					after[j] = tasks[b].id
+				}
+				tags = append(tags, "after "+strings.Join(after, " "))
+			} else {
+				tags = append(tags, startDate)
+			}
+			tags = append(tags, ganttDuration(t.days))
+			name := ganttText(t.issue.ID + " " + t.issue.Title)
+			sb.WriteString(fmt.Sprintf("    %s :%s\n", name, strings.Join(tags, ", ")))
+		}
+	}
+
+	// Due dates, marked crit when the projected finish misses them
+	var due []int
+	for _, i := range order {
+		if tasks[i].issue.DueDate != nil {
+			due = append(due, i)
+		}
+	}
+	if len(due) > 0 {
+		sort.SliceStable(due, func(a, b int) bool { return tasks[due[a]].issue.DueDate.Before(*tasks[due[b]].issue.DueDate) })
+		sb.WriteString("    section Due dates\n")
+		for _, i := range due {
+			t := tasks[i]
+			tags := []string{"milestone"}
+			finish := config.Start.Add(time.Duration((t.start + t.days) * float64(24*time.Hour)))
+			if finish.After(*t.issue.DueDate) {
+				tags = append(tags, "crit")
+			}
+			tags = append(tags, t.id+"_due", t.issue.DueDate.Format("2006-01-02"), "0d")
+			sb.WriteString(fmt.Sprintf("    %s due :%s\n", ganttText(t.issue.ID), strings.Join(tags, ", ")))
+		}
+	}
+	return sb.String()
+}
+
+type ganttSection struct {
+	name  string
+	tasks []int
+}
+
+// buildGanttTasks sizes and orders the open issues, marks the critical
+// path and groups tasks into sections. order is a topological order with
+// dependency cycles broken.
+func buildGanttTasks(issues []model.Issue, stats *analysis.GraphStats, config GanttConfig) ([]*ganttTask, []ganttSection, []int) {
+	byID := make(map[string]model.Issue, len(issues))
+	parentEpic := make(map[string]string)
+	hasOpenChild := make(map[string]bool)
+	for _, iss := range issues {
+		byID[iss.ID] = iss
+	}
+	for _, iss := range issues {
+		for _, dep := range iss.Dependencies {
+			if dep == nil || dep.Type != model.DepParentChild {
+				continue
+			}
+			if parent, ok := byID[dep.DependsOnID]; ok && parent.IssueType == model.TypeEpic {
+				parentEpic[iss.ID] = parent.ID
+				if !isClosedLikeStatus(iss.Status) {
+					hasOpenChild[parent.ID] = true
+				}
+			}
+		}
+	}
+
+	var open []model.Issue
+	for _, iss := range issues {
+		// Epics with open children are sections, not work
+		if isClosedLikeStatus(iss.Status) || hasOpenChild[iss.ID] {
+			continue
+		}
+		open = append(open, iss)
+	}
+	sort.Slice(open, func(i, j int) bool { return open[i].ID < open[j].ID })
+
+	index := make(map[string]int, len(open))
+	tasks := make([]*ganttTask, len(open))
+	usedIDs := make(map[string]bool, len(open))
+	for i, iss := range open {
+		index[iss.ID] = i
+		tasks[i] = &ganttTask{issue: iss, id: ganttTaskID(iss.ID, usedIDs), days: ganttDays(issues, stats, iss, config)}
+	}
+	for i, iss := range open {
+		for _, dep := range iss.Dependencies {
+			if dep == nil || !dep.Type.IsBlocking() {
+				continue
+			}
+			if j, ok := index[dep.DependsOnID]; ok && j != i && !slices.Contains(tasks[i].blockers, j) {
+				tasks[i].blockers = append(tasks[i].blockers, j)
+			}
+		}
+	}
+
+	// Topological order (Kahn, by ID). Cycle members go last and keep only
+	// their edges to already-ordered tasks, so every `after` resolves.
+	indegree := make([]int, len(tasks))
+	dependents := make([][]int, len(tasks))
+	for i, t := range tasks {
+		indegree[i] = len(t.blockers)
+		for _, b := range t.blockers {
+			dependents[b] = append(dependents[b], i)
+		}
+	}
+	var order []int
+	placed := make([]bool, len(tasks))
+	for len(order) < len(tasks) {
+		progressed := false
+		for i := range tasks {
+			if placed[i] || indegree[i] > 0 {
+				continue
+			}
+			placed[i], progressed = true, true
+			order = append(order, i)
+			for _, d := range dependents[i] {
+				indegree[d]--
+			}
+		}
+		if !progressed {
+			for i, t := range tasks {
+				if placed[i] {
+					continue
+				}
+				var kept []int
+				for _, b := range t.blockers {
+					if placed[b] {
+						kept = append(kept, b)
+					}
+				}
+				t.blockers = kept
+				indegree[i] = 0
+				break
+			}
+		}
+	}
+
+	// Earliest starts, then the critical path back from the latest finish.
+	var last, prevOnPath = -1, make([]int, len(tasks))
+	for _, i := range order {
+		t := tasks[i]
+		prevOnPath[i] = -1
+		for _, b := range t.blockers {
+			if end := tasks[b].start + tasks[b].days; end > t.start || (end == t.start && prevOnPath[i] == -1) {
+				t.start, prevOnPath[i] = end, b
+			}
+		}
+		if last == -1 || t.start+t.days > tasks[last].start+tasks[last].days {
+			last = i
+		}
+	}
+	for i := last; i != -1; i = prevOnPath[i] {
+		tasks[i].crit = true
+	}
+
+	// Sections, tasks in start order within each.
+	position := make([]int, len(tasks))
+	for p, i := range order {
+		position[i] = p
+	}
+	groups := make(map[string][]int)
+	var names []string
+	sectionOf := ganttTrackSections(issues, tasks, order)
+	if config.GroupBy == GanttGroupEpic {
+		sectionOf = func(i int) string {
+			if epic, ok := parentEpic[tasks[i].issue.ID]; ok {
+				return epic + " " + byID[epic].Title
+			}
+			return "No epic"
+		}
+	}
+	for _, i := range order {
+		name := sectionOf(i)
+		if _, ok := groups[name]; !ok {
+			names = append(names, name)
+		}
+		groups[name] = append(groups[name], i)
+	}
+	sort.SliceStable(names, func(a, b int) bool {
+		// Catch-all sections last
+		return !isGanttCatchAll(names[a]) && isGanttCatchAll(names[b])
+	})
+	sections := make([]ganttSection, 0, len(names))
+	for _, name := range names {
+		members := groups[name]
+		sort.SliceStable(members, func(a, b int) bool {
+			x, y := tasks[members[a]], tasks[members[b]]
+			if x.start != y.start {
+				return x.start < y.start
+			}
+			return position[members[a]] < position[members[b]]
+		})
+		sections = append(sections, ganttSection{name: name, tasks: members})
+	}
+	return tasks, sections, order
+}
+
+// ganttTrackSections maps each task to its execution-plan track. Blocked
+// tasks join the track of the actionable work they wait on.
+func ganttTrackSections(issues []model.Issue, tasks []*ganttTask, order []int) func(int) string {
+	plan := analysis.NewAnalyzer(issues).GetExecutionPlan()
+	track := make(map[string]string)
+	for _, tr := range plan.Tracks {
+		name := "Track " + strings.TrimPrefix(tr.TrackID, "track-")
+		for _, item := range tr.Items {
+			track[item.ID] = name
+		}
+	}
+	section := make([]string, len(tasks))
+	for _, i := range order {
+		if name, ok := track[tasks[i].issue.ID]; ok {
+			section[i] = name
+			continue
+		}
+		for _, b := range tasks[i].blockers {
+			if section[b] != "" && section[b] != ganttBlockedSection {
+				section[i] = section[b]
+				break
+			}
+		}
+		if section[i] == "" {
+			section[i] = ganttBlockedSection
+		}
+	}
+	return func(i int) string { return section[i] }
+}
+
+const ganttBlockedSection = "Blocked by cycles"
+
+func isGanttCatchAll(name string) bool {
+	return name == ganttBlockedSection || name == "No epic"
+}
+
+// ganttDays sizes a bar: explicit estimates at hoursPerDay, otherwise the
+// ETA model's estimate for one agent.
+func ganttDays(issues []model.Issue, stats *analysis.GraphStats, iss model.Issue, config GanttConfig) float64 {
+	if iss.EstimatedMinutes != nil && *iss.EstimatedMinutes > 0 {
+		return float64(*iss.EstimatedMinutes) / 60 / config.HoursPerDay
+	}
+	if eta, err := analysis.EstimateETAForIssue(issues, stats, iss.ID, 1, config.Start); err == nil && eta.EstimatedDays > 0 {
+		return eta.EstimatedDays
+	}
+	return float64(analysis.DefaultEstimatedMinutes) / 60 / config.HoursPerDay
+}
+
+// ganttDuration formats days as whole days, or hours when fractional.
+func ganttDuration(days float64) string {
+	hours := max(1, int(math.Round(days*24)))
+	if hours%24 == 0 {
+		return fmt.Sprintf("%dd", hours/24)
+	}
+	return fmt.Sprintf("%dh", hours)
+}
+
+// ganttTaskID derives a unique Mermaid task ID (letters, digits, _).
+func ganttTaskID(orig string, used map[string]bool) string {
+	id := strings.ReplaceAll(sanitizeMermaidID(orig), "-", "_")
+	if id[0] >= '0' && id[0] <= '9' {
+		id = "t" + id
+	}
+	if used[id] {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(orig))
+		id = fmt.Sprintf("%s_%x", id, h.Sum32())
+	}
+	used[id] = true
+	return id
+}
+
+// ganttText strips characters that end a Mermaid gantt name or title.
+func ganttText(s string) string {
+	return strings.NewReplacer(":", " -", ";", ",", "#", "").Replace(sanitizeMermaidText(s))
+}
+
+// SaveGanttToFile writes a Mermaid gantt chart. Files ending in .mmd or
+// .mermaid get the bare chart; anything else gets a Markdown document with
+// the chart in a mermaid code fence, ready to paste into a README.
+func SaveGanttToFile(issues []model.Issue, stats *analysis.GraphStats, config GanttConfig, filename string) error {
+	config = config.withDefaults()
+	chart := GenerateMermaidGantt(issues, stats, config)
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".mmd", ".mermaid":
+		return os.WriteFile(filename, []byte(chart), 0644)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("# " + config.Title + "\n\n")
+	sb.WriteString(fmt.Sprintf("Open work as of %s. Bars use `estimated_minutes` at %gh/day, or the ETA model when unset; ", config.Start.Format("2006-01-02"), config.HoursPerDay))
+	sb.WriteString("blocked work starts after its blockers, red bars are the critical path and red milestones are due dates the plan misses.\n\n")
+	sb.WriteString("```mermaid\n")
+	sb.WriteString(chart)
+	sb.WriteString("```\n")
+	return os.WriteFile(filename, []byte(sb.String()), 0644)
+}
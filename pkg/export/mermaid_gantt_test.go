@@ -0,0 +1,112 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func ganttMinutes(m int) *int { return &m }
+
+func ganttIssues() []model.Issue {
+	due := time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)
+	blocks := func(id, on string) *model.Dependency {
+		return &model.Dependency{IssueID: id, DependsOnID: on, Type: model.DepBlocks}
+	}
+	return []model.Issue{
+		{ID: "epic-1", Title: "Launch", Status: model.StatusOpen, IssueType: model.TypeEpic},
+		{ID: "bv-1", Title: "Design: API", Status: model.StatusInProgress, EstimatedMinutes: ganttMinutes(360),
+			Dependencies: []*model.Dependency{{IssueID: "bv-1", DependsOnID: "epic-1", Type: model.DepParentChild}}},
+		{ID: "bv-2", Title: "Build", Status: model.StatusOpen, EstimatedMinutes: ganttMinutes(720),
+			Dependencies: []*model.Dependency{blocks("bv-2", "bv-1"), {IssueID: "bv-2", DependsOnID: "epic-1", Type: model.DepParentChild}}},
+		{ID: "bv-3", Title: "Ship", Status: model.StatusOpen, EstimatedMinutes: ganttMinutes(180), DueDate: &due,
+			Dependencies: []*model.Dependency{blocks("bv-3", "bv-2")}},
+		{ID: "bv-4", Title: "Docs", Status: model.StatusOpen, EstimatedMinutes: ganttMinutes(60)},
+		{ID: "bv-5", Title: "Done", Status: model.StatusClosed, EstimatedMinutes: ganttMinutes(60)},
+	}
+}
+
+func TestGenerateMermaidGantt_Tracks(t *testing.T) {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	got := GenerateMermaidGantt(ganttIssues(), nil, GanttConfig{Title: "Plan", Start: start})
+
+	want := `gantt
+    title Plan
+    dateFormat YYYY-MM-DD
+    axisFormat %b %d
+    section Track A
+    bv-1 Design - API :crit, active, bv_1, 2025-03-01, 1d
+    bv-2 Build :crit, bv_2, after bv_1, 2d
+    bv-3 Ship :crit, bv_3, after bv_2, 12h
+    section Track B
+    bv-4 Docs :bv_4, 2025-03-01, 4h
+    section Due dates
+    bv-3 due :milestone, crit, bv_3_due, 2025-03-02, 0d
+`
+	if got != want {
+		t.Errorf("gantt mismatch\n got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGenerateMermaidGantt_EpicsAndCycles(t *testing.T) {
+	issues := ganttIssues()
+	issues = append(issues,
+		model.Issue{ID: "x", Title: "X", Status: model.StatusOpen, EstimatedMinutes: ganttMinutes(60),
+			Dependencies: []*model.Dependency{{IssueID: "x", DependsOnID: "y", Type: model.DepBlocks}}},
+		model.Issue{ID: "y", Title: "Y", Status: model.StatusOpen, EstimatedMinutes: ganttMinutes(60),
+			Dependencies: []*model.Dependency{{IssueID: "y", DependsOnID: "x", Type: model.DepBlocks}}},
+	)
+	got := GenerateMermaidGantt(issues, nil, GanttConfig{GroupBy: GanttGroupEpic, Start: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)})
+
+	epic := strings.Index(got, "section epic-1 Launch")
+	noEpic := strings.Index(got, "section No epic")
+	if epic == -1 || noEpic == -1 || epic > noEpic {
+		t.Fatalf("expected the epic section before the catch-all:\n%s", got)
+	}
+	if strings.Contains(got, ":epic_1") {
+		t.Error("an epic with open children should not be a task")
+	}
+	// The cycle is broken: one of x/y starts on its own, the other follows it.
+	if !strings.Contains(got, "X :x, 2025-03-01, 4h") || !strings.Contains(got, "Y :y, after x, 4h") {
+		t.Errorf("cycle not broken as expected:\n%s", got)
+	}
+}
+
+func TestExportGraph_Gantt(t *testing.T) {
+	issues := ganttIssues()
+	stats := analysis.NewAnalyzer(issues).Analyze()
+	result, err := ExportGraph(issues, &stats, GraphExportConfig{Format: GraphFormatGantt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Format != "gantt" || !strings.HasPrefix(result.Graph, "gantt\n") || !strings.Contains(result.Graph, "after bv_1") {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestSaveGanttToFile(t *testing.T) {
+	dir := t.TempDir()
+	issues := ganttIssues()
+
+	md := filepath.Join(dir, "plan.md")
+	if err := SaveGanttToFile(issues, nil, GanttConfig{}, md); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(md)
+	if !strings.HasPrefix(string(data), "# Project plan\n") || !strings.Contains(string(data), "```mermaid\ngantt\n") {
+		t.Errorf("markdown = %s", data)
+	}
+
+	mmd := filepath.Join(dir, "plan.mmd")
+	if err := SaveGanttToFile(issues, nil, GanttConfig{}, mmd); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(mmd); !strings.HasPrefix(string(data), "gantt\n") {
+		t.Errorf("bare chart = %s", data)
+	}
+}
@@ -0,0 +1,320 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// Report formats a recipe's export.format may name.
+const (
+	ReportMarkdown = "markdown"
+	ReportJSON     = "json"
+	ReportCSV      = "csv"
+	ReportMermaid  = "mermaid"
+)
+
+// DefaultReportColumns is used when a recipe does not set view.columns.
+var DefaultReportColumns = []string{"id", "title", "status", "priority", "updated"}
+
+// ReportData is what a recipe report renders, and what custom templates
+// receive as their root value ({{.Issues}}, {{.Stats.GetPageRankScore .ID}},
+// {{.Triage.QuickRef.TopPicks}} ...).
+type ReportData struct {
+	Title       string
+	Description string
+	GeneratedAt time.Time
+	DataHash    string
+
+	// Issues is the recipe's filtered, sorted set.
+	Issues  []model.Issue
+	Columns []string
+
+	// Stats and Triage are computed over the whole project so scores keep
+	// their global meaning inside a filtered report.
+	Stats        *analysis.GraphStats
+	Triage       *analysis.TriageResult
+	TriageScores map[string]float64
+
+	// OpenBlockers maps an issue ID to the open issues blocking it.
+	OpenBlockers map[string][]string
+
+	// IncludeGraph embeds Mermaid for Issues; Mermaid holds it (templates
+	// can always call {{mermaid}}).
+	IncludeGraph bool
+	Mermaid      string
+}
+
+// ReportFormatForPath infers a report format from a file extension,
+// defaulting to markdown.
+func ReportFormatForPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return ReportJSON
+	case ".csv":
+		return ReportCSV
+	case ".mmd", ".mermaid":
+		return ReportMermaid
+	}
+	return ReportMarkdown
+}
+
+// ValidReportFormat reports whether format is a recipe export format.
+func ValidReportFormat(format string) bool {
+	switch format {
+	case ReportMarkdown, ReportJSON, ReportCSV, ReportMermaid:
+		return true
+	}
+	return false
+}
+
+// mermaidFor renders the dependency graph restricted to the report's issues.
+func (d *ReportData) mermaidFor() string {
+	ids := make(map[string]bool, len(d.Issues))
+	for _, i := range d.Issues {
+		ids[i.ID] = true
+	}
+	return GenerateMermaidGraph(d.Issues, ids, MermaidConfig{ShowNoDependenciesNode: true})
+}
+
+// RenderReport writes data in the given built-in format.
+func RenderReport(w io.Writer, format string, data ReportData) error {
+	if len(data.Columns) == 0 {
+		data.Columns = DefaultReportColumns
+	}
+	if data.IncludeGraph || format == ReportMermaid {
+		data.Mermaid = data.mermaidFor()
+	}
+	switch format {
+	case ReportMarkdown, "":
+		return renderMarkdownReport(w, data)
+	case ReportJSON:
+		return renderJSONReport(w, data)
+	case ReportCSV:
+		return renderCSVReport(w, data)
+	case ReportMermaid:
+		_, err := io.WriteString(w, data.Mermaid)
+		return err
+	}
+	return fmt.Errorf("unknown report format %q (expected markdown, json, csv or mermaid)", format)
+}
+
+// RenderTemplateReport executes the text/template file at path with data.
+func RenderTemplateReport(w io.Writer, path string, data ReportData) error {
+	if len(data.Columns) == 0 {
+		data.Columns = DefaultReportColumns
+	}
+	if data.IncludeGraph {
+		data.Mermaid = data.mermaidFor()
+	}
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read template: %w", err)
+	}
+	tmpl, err := template.New(filepath.Base(path)).Funcs(reportFuncs(&data)).Parse(string(src))
+	if err != nil {
+		return fmt.Errorf("parse template: %w", err)
+	}
+	if err := tmpl.Execute(w, data); err != nil {
+		return fmt.Errorf("execute template: %w", err)
+	}
+	return nil
+}
+
+// reportFuncs are the helpers available to custom templates.
+func reportFuncs(d *ReportData) template.FuncMap {
+	return template.FuncMap{
+		"column": func(name string, issue model.Issue) string { return d.cell(name, issue) },
+		"date": func(t time.Time) string {
+			if t.IsZero() {
+				return ""
+			}
+			return t.Format("2006-01-02")
+		},
+		"join":     strings.Join,
+		"mermaid":  d.mermaidFor,
+		"priority": getPriorityLabel,
+		"truncate": truncateString,
+		"upper":    strings.ToUpper,
+		"lower":    strings.ToLower,
+	}
+}
+
+// cell renders one column of one issue as text.
+func (d *ReportData) cell(column string, i model.Issue) string {
+	switch column {
+	case "id":
+		return i.ID
+	case "title":
+		return i.Title
+	case "status":
+		return string(i.Status)
+	case "priority":
+		return getPriorityLabel(i.Priority)
+	case "type":
+		return string(i.IssueType)
+	case "assignee":
+		return i.Assignee
+	case "created":
+		return formatReportTime(i.CreatedAt)
+	case "updated":
+		return formatReportTime(i.UpdatedAt)
+	case "closed":
+		if i.ClosedAt == nil {
+			return ""
+		}
+		return formatReportTime(*i.ClosedAt)
+	case "tags", "labels":
+		return strings.Join(i.Labels, ", ")
+	case "blockers":
+		return strings.Join(d.OpenBlockers[i.ID], ", ")
+	}
+	if v, ok := d.metric(column, i.ID); ok {
+		return strconv.FormatFloat(v, 'f', 4, 64)
+	}
+	return ""
+}
+
+// metric returns a numeric graph or triage score column.
+func (d *ReportData) metric(column, id string) (float64, bool) {
+	switch column {
+	case "triage":
+		return d.TriageScores[id], true
+	}
+	if d.Stats == nil {
+		return 0, false
+	}
+	switch column {
+	case "pagerank":
+		return d.Stats.GetPageRankScore(id), true
+	case "betweenness":
+		return d.Stats.GetBetweennessScore(id), true
+	case "eigenvector":
+		return d.Stats.GetEigenvectorScore(id), true
+	case "hubs":
+		return d.Stats.GetHubScore(id), true
+	case "authorities":
+		return d.Stats.GetAuthorityScore(id), true
+	case "critical_path":
+		return d.Stats.GetCriticalPathScore(id), true
+	}
+	return 0, false
+}
+
+func formatReportTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02 15:04")
+}
+
+func renderMarkdownReport(w io.Writer, d ReportData) error {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# %s\n\n", d.Title))
+	if d.Description != "" {
+		sb.WriteString(d.Description + "\n\n")
+	}
+	sb.WriteString(fmt.Sprintf("*Generated: %s · %d issues*\n\n", d.GeneratedAt.Format(time.RFC1123), len(d.Issues)))
+
+	header := make([]string, len(d.Columns))
+	rule := make([]string, len(d.Columns))
+	for idx, c := range d.Columns {
+		header[idx] = strings.ToUpper(c[:1]) + strings.ReplaceAll(c[1:], "_", " ")
+		rule[idx] = "---"
+	}
+	sb.WriteString("| " + strings.Join(header, " | ") + " |\n")
+	sb.WriteString("|" + strings.Join(rule, "|") + "|\n")
+	for _, i := range d.Issues {
+		cells := make([]string, len(d.Columns))
+		for idx, c := range d.Columns {
+			v := strings.ReplaceAll(d.cell(c, i), "\n", " ")
+			cells[idx] = strings.ReplaceAll(v, "|", "\\|")
+		}
+		sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+
+	if d.IncludeGraph {
+		sb.WriteString("\n## Dependency Graph\n\n```mermaid\n")
+		sb.WriteString(d.Mermaid)
+		sb.WriteString("```\n")
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func renderCSVReport(w io.Writer, d ReportData) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(d.Columns); err != nil {
+		return err
+	}
+	for _, i := range d.Issues {
+		row := make([]string, len(d.Columns))
+		for idx, c := range d.Columns {
+			row[idx] = d.cell(c, i)
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// jsonReport is the json export format. Rows carry the recipe's columns,
+// with numeric columns as numbers.
+type jsonReport struct {
+	Title       string           `json:"title"`
+	Description string           `json:"description,omitempty"`
+	GeneratedAt time.Time        `json:"generated_at"`
+	DataHash    string           `json:"data_hash,omitempty"`
+	Count       int              `json:"count"`
+	Columns     []string         `json:"columns"`
+	Issues      []map[string]any `json:"issues"`
+	Mermaid     string           `json:"mermaid,omitempty"`
+}
+
+func renderJSONReport(w io.Writer, d ReportData) error {
+	out := jsonReport{
+		Title:       d.Title,
+		Description: d.Description,
+		GeneratedAt: d.GeneratedAt,
+		DataHash:    d.DataHash,
+		Count:       len(d.Issues),
+		Columns:     d.Columns,
+		Issues:      make([]map[string]any, 0, len(d.Issues)),
+		Mermaid:     d.Mermaid,
+	}
+	for _, i := range d.Issues {
+		row := make(map[string]any, len(d.Columns))
+		for _, c := range d.Columns {
+			switch c {
+			case "priority":
+				row[c] = i.Priority
+			case "tags", "labels":
+				row[c] = append([]string{}, i.Labels...)
+			case "blockers":
+				row[c] = append([]string{}, d.OpenBlockers[i.ID]...)
+			default:
+				if v, ok := d.metric(c, i.ID); ok {
+					row[c] = v
+				} else {
+					row[c] = d.cell(c, i)
+				}
+			}
+		}
+		out.Issues = append(out.Issues, row)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
@@ -0,0 +1,156 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func reportFixture() ReportData {
+	issues := []model.Issue{
+		{ID: "A", Title: "Schema", Status: model.StatusOpen, Priority: 1, IssueType: model.TypeTask, Labels: []string{"db"}},
+		{ID: "B", Title: "API, v2", Status: model.StatusOpen, Priority: 2, IssueType: model.TypeFeature,
+			Dependencies: []*model.Dependency{{IssueID: "B", DependsOnID: "A", Type: model.DepBlocks}}},
+	}
+	stats := analysis.NewAnalyzer(issues).Analyze()
+	return ReportData{
+		Title:        "Recipe: weekly",
+		GeneratedAt:  time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC),
+		Issues:       issues,
+		Stats:        &stats,
+		TriageScores: map[string]float64{"A": 0.8, "B": 0.25},
+		OpenBlockers: map[string][]string{"B": {"A"}},
+	}
+}
+
+func TestRenderReport_CSVUsesViewColumns(t *testing.T) {
+	data := reportFixture()
+	data.Columns = []string{"id", "title", "blockers", "triage"}
+
+	var buf bytes.Buffer
+	if err := RenderReport(&buf, ReportCSV, data); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"id", "title", "blockers", "triage"},
+		{"A", "Schema", "", "0.8000"},
+		{"B", "API, v2", "A", "0.2500"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d: %v", len(rows), len(want), rows)
+	}
+	for i := range want {
+		if strings.Join(rows[i], "|") != strings.Join(want[i], "|") {
+			t.Errorf("row %d = %v, want %v", i, rows[i], want[i])
+		}
+	}
+}
+
+func TestRenderReport_MarkdownIncludeGraph(t *testing.T) {
+	data := reportFixture()
+	data.IncludeGraph = true
+
+	var buf bytes.Buffer
+	if err := RenderReport(&buf, ReportMarkdown, data); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"# Recipe: weekly", "| Id | Title | Status | Priority | Updated |", "| B | API, v2 | open |", "```mermaid", "B ==> A"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown report missing %q:\n%s", want, out)
+		}
+	}
+
+	// Without include_graph there is no graph section
+	data.IncludeGraph = false
+	buf.Reset()
+	if err := RenderReport(&buf, ReportMarkdown, data); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "mermaid") {
+		t.Errorf("graph rendered without include_graph:\n%s", buf.String())
+	}
+}
+
+func TestRenderReport_JSONTypesColumns(t *testing.T) {
+	data := reportFixture()
+	data.Columns = []string{"id", "priority", "blockers", "pagerank"}
+
+	var buf bytes.Buffer
+	if err := RenderReport(&buf, ReportJSON, data); err != nil {
+		t.Fatal(err)
+	}
+	var out struct {
+		Count  int              `json:"count"`
+		Issues []map[string]any `json:"issues"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Count != 2 || len(out.Issues) != 2 {
+		t.Fatalf("count = %d, issues = %d", out.Count, len(out.Issues))
+	}
+	b := out.Issues[1]
+	if b["priority"] != float64(2) {
+		t.Errorf("priority = %#v, want a number", b["priority"])
+	}
+	if _, ok := b["pagerank"].(float64); !ok {
+		t.Errorf("pagerank = %#v, want a number", b["pagerank"])
+	}
+	if blockers, ok := b["blockers"].([]any); !ok || len(blockers) != 1 {
+		t.Errorf("blockers = %#v", b["blockers"])
+	}
+}
+
+func TestRenderTemplateReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.tmpl")
+	src := `{{.Title}}: {{len .Issues}}
+{{range .Issues}}{{.ID}} {{priority .Priority}} {{column "blockers" .}}
+{{end}}{{if .Stats}}nodes={{.Stats.NodeCount}}{{end}}
+{{mermaid}}`
+	if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := RenderTemplateReport(&buf, path, reportFixture()); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"Recipe: weekly: 2", "A ⚡ High (P1) \n", "B 🔹 Medium (P2) A", "nodes=2", "graph TD"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("template output missing %q:\n%s", want, out)
+		}
+	}
+
+	if err := RenderTemplateReport(&buf, filepath.Join(t.TempDir(), "missing.tmpl"), reportFixture()); err == nil {
+		t.Error("expected an error for a missing template")
+	}
+}
+
+func TestReportFormatForPath(t *testing.T) {
+	for path, want := range map[string]string{
+		"out.md":     ReportMarkdown,
+		"out.CSV":    ReportCSV,
+		"out.json":   ReportJSON,
+		"graph.mmd":  ReportMermaid,
+		"report":     ReportMarkdown,
+		"report.txt": ReportMarkdown,
+	} {
+		if got := ReportFormatForPath(path); got != want {
+			t.Errorf("ReportFormatForPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
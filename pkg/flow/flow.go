@@ -0,0 +1,424 @@
+// Package flow computes Kanban flow metrics from bead status transitions:
+// lead and cycle time distributions, weekly throughput, the age of work in
+// progress, and cumulative flow diagram (CFD) series per status.
+//
+// Status transitions come from git history of the beads file (see
+// correlation.Extractor). Issues the history does not cover fall back to
+// their created_at, updated_at and closed_at timestamps.
+package flow
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/correlation"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// DefaultDays is the default length of the reporting window.
+const DefaultDays = 90
+
+// Data sources reported in Report.Source.
+const (
+	SourceGit        = "git"        // Status changes from git history
+	SourceTimestamps = "timestamps" // Issue timestamps only
+)
+
+// Transition records an issue entering a status.
+type Transition struct {
+	Status model.Status `json:"status"`
+	At     time.Time    `json:"at"`
+}
+
+// Options configures Compute.
+type Options struct {
+	Now  time.Time // End of the window (default: time.Now())
+	Days int       // Window length in days (default: DefaultDays)
+}
+
+// Distribution summarizes durations in days.
+type Distribution struct {
+	Count    int     `json:"count"`
+	MeanDays float64 `json:"mean_days"`
+	P50Days  float64 `json:"p50_days"`
+	P85Days  float64 `json:"p85_days"`
+	P95Days  float64 `json:"p95_days"`
+	MinDays  float64 `json:"min_days"`
+	MaxDays  float64 `json:"max_days"`
+
+	values []float64 // Sorted samples, for ranking WIP ages
+}
+
+// WeekCount is the number of issues closed in the week starting WeekStart.
+type WeekCount struct {
+	WeekStart string `json:"week_start"` // Monday, YYYY-MM-DD
+	Closed    int    `json:"closed"`
+}
+
+// Throughput counts closed issues per week.
+type Throughput struct {
+	Weeks       []WeekCount `json:"weeks"`
+	Total       int         `json:"total"`
+	MeanPerWeek float64     `json:"mean_per_week"`
+}
+
+// AgingItem is an in-progress issue and how long it has been in progress.
+type AgingItem struct {
+	IssueID   string    `json:"issue_id"`
+	Title     string    `json:"title"`
+	Assignee  string    `json:"assignee,omitempty"`
+	Priority  int       `json:"priority"`
+	StartedAt time.Time `json:"started_at"`
+	AgeDays   float64   `json:"age_days"`
+	// Percentile is the share of completed cycle times shorter than this age.
+	Percentile float64 `json:"percentile"`
+	// OverP85 is set once the item is older than 85% of completed work.
+	OverP85 bool `json:"over_p85"`
+}
+
+// CFDSeries is the daily count of issues in one status.
+type CFDSeries struct {
+	Status model.Status `json:"status"`
+	Counts []int        `json:"counts"`
+}
+
+// CFD holds cumulative flow series, one count per day per status. Series
+// are in stacking order, done work first.
+type CFD struct {
+	Dates  []string    `json:"dates"` // YYYY-MM-DD
+	Series []CFDSeries `json:"series"`
+}
+
+// Report is the result of Compute.
+type Report struct {
+	Source     string       `json:"source"`
+	Start      time.Time    `json:"start"`
+	End        time.Time    `json:"end"`
+	Days       int          `json:"days"`
+	LeadTime   Distribution `json:"lead_time"`  // Created → closed, for issues closed in the window
+	CycleTime  Distribution `json:"cycle_time"` // First in_progress → closed, for issues closed in the window
+	Throughput Throughput   `json:"throughput"`
+	WIPAging   []AgingItem  `json:"wip_aging"` // Oldest first
+	CFD        CFD          `json:"cfd"`
+}
+
+// cfdOrder stacks done work at the bottom and fresh work at the top.
+var cfdOrder = []model.Status{
+	model.StatusClosed,
+	model.StatusReview,
+	model.StatusInProgress,
+	model.StatusHooked,
+	model.StatusBlocked,
+	model.StatusDeferred,
+	model.StatusPinned,
+	model.StatusOpen,
+}
+
+// EventsFromHistory flattens a correlation report's per-bead events into
+// one chronological list.
+func EventsFromHistory(report *correlation.HistoryReport) []correlation.BeadEvent {
+	if report == nil {
+		return nil
+	}
+	var events []correlation.BeadEvent
+	for _, h := range report.Histories {
+		events = append(events, h.Events...)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+	return events
+}
+
+// Transitions builds each issue's status timeline from git events,
+// anchored at the issue's creation and ending in its current status. The
+// second result is SourceGit when any event was used.
+func Transitions(issues []model.Issue, events []correlation.BeadEvent) (map[string][]Transition, string) {
+	byIssue := make(map[string][]correlation.BeadEvent)
+	for _, e := range events {
+		byIssue[e.BeadID] = append(byIssue[e.BeadID], e)
+	}
+
+	source := SourceTimestamps
+	timelines := make(map[string][]Transition, len(issues))
+	for i := range issues {
+		issue := &issues[i]
+		if issue.Status == model.StatusTombstone {
+			continue
+		}
+
+		evs := byIssue[issue.ID]
+		sort.SliceStable(evs, func(a, b int) bool { return evs[a].Timestamp.Before(evs[b].Timestamp) })
+		var tl []Transition
+		for _, e := range evs {
+			status := eventStatus(e)
+			if status == "" || (len(tl) > 0 && tl[len(tl)-1].Status == status) {
+				continue
+			}
+			tl = append(tl, Transition{Status: status, At: e.Timestamp})
+		}
+		if len(tl) > 0 {
+			source = SourceGit
+		}
+
+		if !issue.CreatedAt.IsZero() && (len(tl) == 0 || issue.CreatedAt.Before(tl[0].At)) {
+			if len(tl) > 0 && tl[0].Status == model.StatusOpen {
+				tl[0].At = issue.CreatedAt
+			} else {
+				tl = append([]Transition{{Status: model.StatusOpen, At: issue.CreatedAt}}, tl...)
+			}
+		}
+
+		// The current status wins over whatever history saw last.
+		if len(tl) == 0 || tl[len(tl)-1].Status != issue.Status {
+			at := issue.UpdatedAt
+			if issue.Status == model.StatusClosed && issue.ClosedAt != nil {
+				at = *issue.ClosedAt
+			}
+			if len(tl) > 0 && at.Before(tl[len(tl)-1].At) {
+				at = tl[len(tl)-1].At
+			}
+			if at.IsZero() {
+				continue
+			}
+			tl = append(tl, Transition{Status: issue.Status, At: at})
+		} else if issue.Status == model.StatusClosed && issue.ClosedAt != nil {
+			// closed_at is when the issue closed; the commit recording it may
+			// come much later (batched syncs, imported history).
+			if n := len(tl); n == 1 || !issue.ClosedAt.Before(tl[n-2].At) {
+				tl[n-1].At = *issue.ClosedAt
+			}
+		}
+		timelines[issue.ID] = tl
+	}
+	return timelines, source
+}
+
+// eventStatus returns the status a bead entered with an event. Events read
+// from older caches lack the status, so it is inferred from the event type.
+func eventStatus(e correlation.BeadEvent) model.Status {
+	if e.Status != "" {
+		return model.Status(e.Status)
+	}
+	switch e.EventType {
+	case correlation.EventCreated, correlation.EventReopened:
+		return model.StatusOpen
+	case correlation.EventClaimed:
+		return model.StatusInProgress
+	case correlation.EventClosed:
+		return model.StatusClosed
+	}
+	return ""
+}
+
+// Compute derives flow metrics for the window ending at opts.Now.
+func Compute(issues []model.Issue, events []correlation.BeadEvent, opts Options) Report {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	days := opts.Days
+	if days <= 0 {
+		days = DefaultDays
+	}
+	timelines, source := Transitions(issues, events)
+
+	// The window never starts before the oldest issue.
+	start := startOfDay(now.AddDate(0, 0, -(days - 1)))
+	earliest := now
+	for _, tl := range timelines {
+		if len(tl) > 0 && tl[0].At.Before(earliest) {
+			earliest = tl[0].At
+		}
+	}
+	if e := startOfDay(earliest.In(now.Location())); e.After(start) {
+		start = e
+	}
+
+	report := Report{
+		Source:   source,
+		Start:    start,
+		End:      now,
+		Days:     int(startOfDay(now).Sub(start).Hours()/24+0.5) + 1,
+		WIPAging: []AgingItem{},
+	}
+
+	var leads, cycles []float64
+	var closes []time.Time
+	for i := range issues {
+		issue := &issues[i]
+		tl := timelines[issue.ID]
+		if len(tl) == 0 {
+			continue
+		}
+		last := tl[len(tl)-1]
+		if last.Status != model.StatusClosed || last.At.Before(start) || last.At.After(now) {
+			continue
+		}
+		closes = append(closes, last.At)
+		leads = append(leads, durationDays(last.At.Sub(tl[0].At)))
+		for _, t := range tl {
+			if t.Status == model.StatusInProgress {
+				cycles = append(cycles, durationDays(last.At.Sub(t.At)))
+				break
+			}
+		}
+	}
+	report.LeadTime = distribution(leads)
+	report.CycleTime = distribution(cycles)
+	report.Throughput = weeklyThroughput(closes, start, now)
+
+	for i := range issues {
+		issue := &issues[i]
+		tl := timelines[issue.ID]
+		if issue.Status != model.StatusInProgress || len(tl) == 0 {
+			continue
+		}
+		started := tl[len(tl)-1].At
+		age := durationDays(now.Sub(started))
+		item := AgingItem{
+			IssueID:    issue.ID,
+			Title:      issue.Title,
+			Assignee:   issue.Assignee,
+			Priority:   issue.Priority,
+			StartedAt:  started,
+			AgeDays:    age,
+			Percentile: report.CycleTime.rank(age),
+		}
+		item.OverP85 = report.CycleTime.Count > 0 && age > report.CycleTime.P85Days
+		report.WIPAging = append(report.WIPAging, item)
+	}
+	sort.SliceStable(report.WIPAging, func(i, j int) bool {
+		if report.WIPAging[i].AgeDays != report.WIPAging[j].AgeDays {
+			return report.WIPAging[i].AgeDays > report.WIPAging[j].AgeDays
+		}
+		return report.WIPAging[i].IssueID < report.WIPAging[j].IssueID
+	})
+
+	report.CFD = cumulativeFlow(timelines, start, now, report.Days)
+	return report
+}
+
+// cumulativeFlow counts, at the end of each day, how many issues were in
+// each status.
+func cumulativeFlow(timelines map[string][]Transition, start, now time.Time, days int) CFD {
+	cfd := CFD{Dates: make([]string, days)}
+	counts := make(map[model.Status][]int)
+	for d := 0; d < days; d++ {
+		day := start.AddDate(0, 0, d)
+		cfd.Dates[d] = day.Format("2006-01-02")
+		at := day.AddDate(0, 0, 1)
+		if at.After(now) {
+			at = now
+		}
+		for _, tl := range timelines {
+			// Last transition at or before the sample instant
+			k := sort.Search(len(tl), func(i int) bool { return tl[i].At.After(at) })
+			if k == 0 {
+				continue
+			}
+			status := tl[k-1].Status
+			if counts[status] == nil {
+				counts[status] = make([]int, days)
+			}
+			counts[status][d]++
+		}
+	}
+
+	seen := make(map[model.Status]bool)
+	for _, s := range cfdOrder {
+		seen[s] = true
+		if c, ok := counts[s]; ok {
+			cfd.Series = append(cfd.Series, CFDSeries{Status: s, Counts: c})
+		}
+	}
+	var other []model.Status
+	for s := range counts {
+		if !seen[s] {
+			other = append(other, s)
+		}
+	}
+	sort.Slice(other, func(i, j int) bool { return other[i] < other[j] })
+	for _, s := range other {
+		cfd.Series = append(cfd.Series, CFDSeries{Status: s, Counts: counts[s]})
+	}
+	if cfd.Series == nil {
+		cfd.Series = []CFDSeries{}
+	}
+	return cfd
+}
+
+// weeklyThroughput bins close times into Monday-started weeks.
+func weeklyThroughput(closes []time.Time, start, now time.Time) Throughput {
+	first, last := weekStart(start), weekStart(now)
+	var tp Throughput
+	for w := first; !w.After(last); w = w.AddDate(0, 0, 7) {
+		tp.Weeks = append(tp.Weeks, WeekCount{WeekStart: w.Format("2006-01-02")})
+	}
+	for _, c := range closes {
+		idx := int(weekStart(c.In(first.Location())).Sub(first).Hours()/24+0.5) / 7
+		if idx >= 0 && idx < len(tp.Weeks) {
+			tp.Weeks[idx].Closed++
+			tp.Total++
+		}
+	}
+	if len(tp.Weeks) > 0 {
+		tp.MeanPerWeek = round2(float64(tp.Total) / float64(len(tp.Weeks)))
+	}
+	return tp
+}
+
+func distribution(values []float64) Distribution {
+	d := Distribution{Count: len(values)}
+	if len(values) == 0 {
+		return d
+	}
+	sort.Float64s(values)
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	d.values = values
+	d.MeanDays = round2(sum / float64(len(values)))
+	d.P50Days = round2(percentile(values, 50))
+	d.P85Days = round2(percentile(values, 85))
+	d.P95Days = round2(percentile(values, 95))
+	d.MinDays = round2(values[0])
+	d.MaxDays = round2(values[len(values)-1])
+	return d
+}
+
+// rank returns the percentage of samples below v.
+func (d Distribution) rank(v float64) float64 {
+	if len(d.values) == 0 {
+		return 0
+	}
+	below := sort.SearchFloat64s(d.values, v)
+	return round2(float64(below) / float64(len(d.values)) * 100)
+}
+
+// percentile uses the nearest-rank method on sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	idx := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	return sorted[min(max(idx, 0), len(sorted)-1)]
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+func weekStart(t time.Time) time.Time {
+	day := startOfDay(t)
+	offset := (int(day.Weekday()) + 6) % 7 // Monday = 0
+	return day.AddDate(0, 0, -offset)
+}
+
+func durationDays(d time.Duration) float64 {
+	return round2(d.Hours() / 24)
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
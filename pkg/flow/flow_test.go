@@ -0,0 +1,138 @@
+package flow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/correlation"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// 2025-03-03 is a Monday.
+var day0 = time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+
+func at(days, hours int) time.Time {
+	return day0.AddDate(0, 0, days).Add(time.Duration(hours) * time.Hour)
+}
+
+func ev(id string, typ correlation.EventType, status string, t time.Time) correlation.BeadEvent {
+	return correlation.BeadEvent{BeadID: id, EventType: typ, Status: status, Timestamp: t}
+}
+
+func flowIssues() ([]model.Issue, []correlation.BeadEvent) {
+	closed := func(t time.Time) *time.Time { return &t }
+	issues := []model.Issue{
+		{ID: "a", Title: "A", Status: model.StatusClosed, CreatedAt: at(0, 9), ClosedAt: closed(at(4, 9))},
+		{ID: "b", Title: "B", Status: model.StatusClosed, CreatedAt: at(1, 9), ClosedAt: closed(at(9, 9))},
+		{ID: "c", Title: "C", Status: model.StatusInProgress, CreatedAt: at(2, 9), UpdatedAt: at(3, 9)},
+		{ID: "d", Title: "D", Status: model.StatusOpen, CreatedAt: at(5, 9)},
+		{ID: "gone", Title: "Gone", Status: model.StatusTombstone, CreatedAt: at(0, 9)},
+	}
+	events := []correlation.BeadEvent{
+		ev("a", correlation.EventCreated, "open", at(0, 10)),
+		ev("a", correlation.EventClaimed, "in_progress", at(2, 9)),
+		ev("a", correlation.EventClosed, "closed", at(4, 9)),
+		ev("b", correlation.EventModified, "blocked", at(2, 9)),
+		ev("b", correlation.EventClaimed, "", at(6, 9)), // Older cache entry: no status
+		ev("b", correlation.EventClosed, "closed", at(9, 9)),
+		ev("c", correlation.EventClaimed, "in_progress", at(8, 9)),
+	}
+	return issues, events
+}
+
+func TestTransitions(t *testing.T) {
+	issues, events := flowIssues()
+	timelines, source := Transitions(issues, events)
+	if source != SourceGit {
+		t.Errorf("source = %q, want git", source)
+	}
+	if _, ok := timelines["gone"]; ok {
+		t.Error("tombstones have no timeline")
+	}
+
+	b := timelines["b"]
+	want := []model.Status{model.StatusOpen, model.StatusBlocked, model.StatusInProgress, model.StatusClosed}
+	if len(b) != len(want) {
+		t.Fatalf("b timeline = %+v", b)
+	}
+	for i, s := range want {
+		if b[i].Status != s {
+			t.Errorf("b[%d] = %s, want %s", i, b[i].Status, s)
+		}
+	}
+	if !b[0].At.Equal(at(1, 9)) {
+		t.Errorf("b should start at created_at, got %v", b[0].At)
+	}
+	// a's creation commit came an hour after created_at.
+	if a := timelines["a"]; !a[0].At.Equal(at(0, 9)) || len(a) != 3 {
+		t.Errorf("a timeline = %+v", a)
+	}
+
+	// Without history, timestamps are all there is.
+	timelines, source = Transitions(issues, nil)
+	if source != SourceTimestamps {
+		t.Errorf("source = %q, want timestamps", source)
+	}
+	if c := timelines["c"]; len(c) != 2 || c[1].Status != model.StatusInProgress || !c[1].At.Equal(at(3, 9)) {
+		t.Errorf("c timeline = %+v", c)
+	}
+}
+
+func TestCompute(t *testing.T) {
+	issues, events := flowIssues()
+	now := at(10, 21) // Thursday evening of the second week
+	r := Compute(issues, events, Options{Now: now, Days: 30})
+
+	if !r.Start.Equal(day0) || r.Days != 11 {
+		t.Errorf("window %v, %d days; want from first creation, 11 days", r.Start, r.Days)
+	}
+	// Lead: a 4d, b 8d. Cycle: a 2d, b 3d.
+	if l := r.LeadTime; l.Count != 2 || l.P50Days != 4 || l.P85Days != 8 || l.MeanDays != 6 {
+		t.Errorf("lead time = %+v", l)
+	}
+	if c := r.CycleTime; c.Count != 2 || c.MinDays != 2 || c.MaxDays != 3 {
+		t.Errorf("cycle time = %+v", c)
+	}
+
+	tp := r.Throughput
+	if len(tp.Weeks) != 2 || tp.Weeks[0].WeekStart != "2025-03-03" || tp.Weeks[0].Closed != 1 || tp.Weeks[1].Closed != 1 || tp.MeanPerWeek != 1 {
+		t.Errorf("throughput = %+v", tp)
+	}
+
+	if len(r.WIPAging) != 1 {
+		t.Fatalf("wip aging = %+v", r.WIPAging)
+	}
+	if w := r.WIPAging[0]; w.IssueID != "c" || w.AgeDays != 2.5 || w.Percentile != 50 || w.OverP85 {
+		t.Errorf("c aging = %+v", w)
+	}
+
+	series := make(map[model.Status][]int)
+	for _, s := range r.CFD.Series {
+		series[s.Status] = s.Counts
+	}
+	if r.CFD.Series[0].Status != model.StatusClosed || len(r.CFD.Dates) != 11 {
+		t.Fatalf("cfd = %+v", r.CFD)
+	}
+	// End of day 4: a closed, b blocked, c open, d not created yet.
+	if series[model.StatusClosed][4] != 1 || series[model.StatusBlocked][4] != 1 || series[model.StatusOpen][4] != 1 {
+		t.Errorf("day 4: closed %d blocked %d open %d", series[model.StatusClosed][4], series[model.StatusBlocked][4], series[model.StatusOpen][4])
+	}
+	// Last day: a, b closed; c in progress; d open.
+	if series[model.StatusClosed][10] != 2 || series[model.StatusInProgress][10] != 1 || series[model.StatusOpen][10] != 1 {
+		t.Errorf("last day = %v", series)
+	}
+}
+
+func TestCompute_WindowAndEmpty(t *testing.T) {
+	issues, events := flowIssues()
+	r := Compute(issues, events, Options{Now: at(10, 9), Days: 5})
+	// Only b closed inside the last five days.
+	if r.Days != 5 || r.LeadTime.Count != 1 || r.Throughput.Total != 1 {
+		t.Errorf("report = %+v", r)
+	}
+
+	empty := Compute(nil, nil, Options{Now: at(0, 9)})
+	if empty.Days != 1 || empty.LeadTime.Count != 0 || len(empty.WIPAging) != 0 || empty.CFD.Series == nil {
+		t.Errorf("empty report = %+v", empty)
+	}
+}
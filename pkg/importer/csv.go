@@ -0,0 +1,157 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// csvTable is a CSV export. Jira repeats headers for multi-valued fields
+// (one "Labels" column per label), so columns are looked up by name and
+// may match more than once.
+type csvTable struct {
+	header []string
+	norm   []string
+	rows   [][]string
+}
+
+func readCSV(r io.Reader) (*csvTable, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	cr := csv.NewReader(bytes.NewReader(data))
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("empty CSV")
+	}
+	t := &csvTable{header: records[0], rows: records[1:]}
+	for _, h := range t.header {
+		t.norm = append(t.norm, normalizeName(h))
+	}
+	return t, nil
+}
+
+// require fails when none of names is a column, which usually means the
+// wrong --from was given.
+func (t *csvTable) require(names ...string) error {
+	for _, name := range names {
+		n := normalizeName(name)
+		for _, h := range t.norm {
+			if h == n {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("missing %q column; is this the right export?", names[0])
+}
+
+// csvRow reads one row and remembers which columns were consumed, so the
+// rest can be reported as unmapped.
+type csvRow struct {
+	t      *csvTable
+	values []string
+	used   map[int]bool
+}
+
+func (t *csvTable) row(i int) *csvRow {
+	return &csvRow{t: t, values: t.rows[i], used: make(map[int]bool)}
+}
+
+// all returns the non-empty values of every column matching one of names.
+func (r *csvRow) all(names ...string) []string {
+	var out []string
+	for _, name := range names {
+		n := normalizeName(name)
+		for i, h := range r.t.norm {
+			if h != n || i >= len(r.values) {
+				continue
+			}
+			r.used[i] = true
+			if v := strings.TrimSpace(r.values[i]); v != "" {
+				out = append(out, v)
+			}
+		}
+	}
+	return out
+}
+
+// get returns the first non-empty value among names.
+func (r *csvRow) get(names ...string) string {
+	if vals := r.all(names...); len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
+
+// reportUnused records every non-empty column the parser did not read.
+func (r *csvRow) reportUnused(u *unmappedFields) {
+	seen := make(map[string]bool)
+	for i, v := range r.values {
+		if r.used[i] || i >= len(r.t.header) || strings.TrimSpace(v) == "" {
+			continue
+		}
+		name := r.t.header[i]
+		if seen[name] {
+			continue // Count a repeated column once per record
+		}
+		seen[name] = true
+		u.add(name, v)
+	}
+}
+
+// exportTimeLayouts covers ISO timestamps, Jira's "02/Jan/24 3:04 PM" and
+// JavaScript's Date.toString (older Linear exports).
+var exportTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"02/Jan/06 3:04 PM",
+	"2/Jan/06 3:04 PM",
+	"02/Jan/2006 3:04 PM",
+	"02/Jan/06 15:04",
+	"02/Jan/2006 15:04",
+	"02/Jan/06",
+	"2/Jan/06",
+	"Mon Jan 02 2006 15:04:05 GMT-0700",
+	"1/2/2006 15:04",
+	"1/2/2006",
+}
+
+func parseExportTime(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, " ("); i > 0 {
+		s = s[:i] // "... GMT+0000 (Coordinated Universal Time)"
+	}
+	for _, layout := range exportTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// timeField parses a timestamp column, reporting values it cannot read.
+func (r *csvRow) timeField(u *unmappedFields, names ...string) *time.Time {
+	v := r.get(names...)
+	if v == "" {
+		return nil
+	}
+	t, ok := parseExportTime(v)
+	if !ok {
+		u.add(names[0]+" (unparsed date)", v)
+		return nil
+	}
+	return &t
+}
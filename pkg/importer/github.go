@@ -0,0 +1,163 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// GitHubFields is the --json field list the importer understands:
+//
+//	gh issue list --state all --limit 10000 --json <GitHubFields>
+const GitHubFields = "number,title,body,state,labels,assignees,comments,createdAt,updatedAt,closedAt,url"
+
+type githubUser struct {
+	Login string `json:"login"`
+}
+
+type githubIssue struct {
+	Number    int          `json:"number"`
+	Title     string       `json:"title"`
+	Body      string       `json:"body"`
+	State     string       `json:"state"`
+	URL       string       `json:"url"`
+	CreatedAt time.Time    `json:"createdAt"`
+	UpdatedAt time.Time    `json:"updatedAt"`
+	ClosedAt  *time.Time   `json:"closedAt"`
+	Assignees []githubUser `json:"assignees"`
+	Labels    []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Comments []struct {
+		Author    githubUser `json:"author"`
+		Body      string     `json:"body"`
+		CreatedAt time.Time  `json:"createdAt"`
+	} `json:"comments"`
+}
+
+// githubMapped lists the gh JSON fields that land in the beads record.
+var githubMapped = map[string]bool{
+	"number": true, "title": true, "body": true, "state": true,
+	"labels": true, "assignees": true, "comments": true,
+	"createdAt": true, "updatedAt": true, "closedAt": true, "url": true,
+}
+
+// githubRefPattern finds "blocked by #12", "depends on #3, #4" and
+// "blocks https://github.com/o/r/issues/9" in issue and comment bodies.
+var (
+	githubRefPattern  = regexp.MustCompile(`(?i)\b(blocked by|depends on|blocks)\s*:?\s*((?:(?:#\d+|https://github\.com/[\w.-]+/[\w.-]+/issues/\d+)(?:\s*(?:,|and)\s*)?)+)`)
+	githubItemPattern = regexp.MustCompile(`#(\d+)|https://github\.com/([\w.-]+/[\w.-]+)/issues/(\d+)`)
+	githubRepoPattern = regexp.MustCompile(`^https://github\.com/([\w.-]+/[\w.-]+)/issues/\d+`)
+)
+
+func parseGitHub(r io.Reader) (*parsed, error) {
+	var raw []map[string]json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("expected the JSON array from `gh issue list --json %s`: %w", GitHubFields, err)
+	}
+
+	p := &parsed{}
+	for i, fields := range raw {
+		data, _ := json.Marshal(fields)
+		var gi githubIssue
+		if err := json.Unmarshal(data, &gi); err != nil {
+			return nil, fmt.Errorf("issue %d: %w", i+1, err)
+		}
+		if gi.Number == 0 {
+			return nil, fmt.Errorf("issue %d has no number; include \"number\" in --json", i+1)
+		}
+		for name, value := range fields {
+			if !githubMapped[name] && !isEmptyJSON(value) {
+				p.unmapped.add(name, string(value))
+			}
+		}
+
+		repo := ""
+		if m := githubRepoPattern.FindStringSubmatch(gi.URL); m != nil {
+			repo = m[1]
+		}
+		key := repo + "#" + strconv.Itoa(gi.Number)
+
+		issue := model.Issue{
+			Title:       gi.Title,
+			Description: gi.Body,
+			Status:      model.StatusOpen,
+			CreatedAt:   gi.CreatedAt,
+			UpdatedAt:   gi.UpdatedAt,
+		}
+		if strings.EqualFold(gi.State, "closed") {
+			issue.Status = model.StatusClosed
+			issue.ClosedAt = gi.ClosedAt
+			if issue.ClosedAt == nil || issue.ClosedAt.IsZero() {
+				closed := gi.UpdatedAt
+				issue.ClosedAt = &closed
+			}
+		} else if gi.State != "" && !strings.EqualFold(gi.State, "open") {
+			p.unmapped.add("state="+gi.State, key)
+		}
+		if len(gi.Assignees) > 0 {
+			issue.Assignee = gi.Assignees[0].Login
+			if len(gi.Assignees) > 1 {
+				p.unmapped.add("assignees (beyond the first)", gi.Assignees[1].Login)
+			}
+		}
+		for _, l := range gi.Labels {
+			issue.Labels = append(issue.Labels, l.Name)
+		}
+		issue.Priority, _, issue.IssueType, _ = classifyLabels(issue.Labels)
+
+		// Comment IDs count within the issue, so a new comment elsewhere in
+		// the export does not renumber this issue's comments on re-import.
+		texts := []string{gi.Body}
+		for j, c := range gi.Comments {
+			issue.Comments = append(issue.Comments, &model.Comment{
+				ID:        int64(j + 1),
+				Author:    c.Author.Login,
+				Text:      c.Body,
+				CreatedAt: c.CreatedAt,
+			})
+			texts = append(texts, c.Body)
+		}
+		p.links = append(p.links, githubLinks(key, repo, texts)...)
+		p.records = append(p.records, record{key: key, issue: issue})
+	}
+	return p, nil
+}
+
+// githubLinks extracts dependency phrases from issue texts. Bare #N refers
+// to the issue's own repository.
+func githubLinks(key, repo string, texts []string) []link {
+	var links []link
+	for _, text := range texts {
+		for _, m := range githubRefPattern.FindAllStringSubmatch(text, -1) {
+			verb := strings.ToLower(m[1])
+			for _, item := range githubItemPattern.FindAllStringSubmatch(m[2], -1) {
+				target := repo + "#" + item[1]
+				if item[1] == "" {
+					target = item[2] + "#" + item[3]
+				}
+				if verb == "blocks" {
+					links = append(links, link{from: target, to: key, depType: model.DepBlocks})
+				} else {
+					links = append(links, link{from: key, to: target, depType: model.DepBlocks})
+				}
+			}
+		}
+	}
+	return links
+}
+
+func isEmptyJSON(v json.RawMessage) bool {
+	switch string(bytes.TrimSpace(v)) {
+	case "", "null", `""`, "[]", "{}", "false", "0":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,386 @@
+// Package importer converts offline issue-tracker exports into beads issues.
+//
+// Supported sources:
+//   - github: the JSON array printed by `gh issue list --json ...`
+//   - jira:   the CSV from Jira's "Export Excel CSV (all fields)"
+//   - linear: the CSV from Linear's workspace export
+//
+// Every imported issue records where it came from in ExternalRef
+// ("<source>:<key>", e.g. "jira:PROJ-12"). Re-importing looks issues up by
+// that reference first, so IDs stay stable and an unchanged export rewrites
+// nothing.
+package importer
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// Source identifies an export format.
+type Source string
+
+const (
+	SourceGitHub Source = "github"
+	SourceJira   Source = "jira"
+	SourceLinear Source = "linear"
+)
+
+// Sources lists the supported export formats.
+var Sources = []Source{SourceGitHub, SourceJira, SourceLinear}
+
+// ParseSource validates a --from value.
+func ParseSource(s string) (Source, error) {
+	for _, src := range Sources {
+		if strings.EqualFold(s, string(src)) {
+			return src, nil
+		}
+	}
+	names := make([]string, len(Sources))
+	for i, src := range Sources {
+		names[i] = string(src)
+	}
+	return "", fmt.Errorf("unknown import source %q (expected %s)", s, strings.Join(names, ", "))
+}
+
+// Options controls ID assignment.
+type Options struct {
+	// Prefix replaces the source's own key prefix in new IDs: with "web",
+	// Jira PROJ-12 becomes web-12. GitHub issues default to "gh".
+	Prefix string
+
+	// Existing issues are matched by ExternalRef so re-imports keep their
+	// IDs; an unrelated issue already using a generated ID is an error.
+	Existing []model.Issue
+}
+
+// UnmappedField is a source field that has no place in the beads model, or
+// a value that could not be translated.
+type UnmappedField struct {
+	Field   string `json:"field"`
+	Count   int    `json:"count"`             // Records carrying it
+	Example string `json:"example,omitempty"` // First value seen, truncated
+}
+
+// Result is a converted export.
+type Result struct {
+	Source   Source          `json:"source"`
+	Issues   []model.Issue   `json:"-"`
+	Unmapped []UnmappedField `json:"unmapped"`
+	Warnings []string        `json:"warnings,omitempty"`
+}
+
+// Import reads an export and converts it to beads issues, in export order
+// with dependencies resolved to beads IDs.
+func Import(src Source, r io.Reader, opts Options) (*Result, error) {
+	var (
+		p   *parsed
+		err error
+	)
+	switch src {
+	case SourceGitHub:
+		p, err = parseGitHub(r)
+	case SourceJira:
+		p, err = parseJira(r)
+	case SourceLinear:
+		p, err = parseLinear(r)
+	default:
+		return nil, fmt.Errorf("unknown import source %q", src)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse %s export: %w", src, err)
+	}
+
+	res := &Result{Source: src}
+	if err := p.assignIDs(src, opts); err != nil {
+		return nil, err
+	}
+	res.Warnings = p.resolveLinks(src, opts.Existing)
+	for _, rec := range p.records {
+		res.Issues = append(res.Issues, rec.issue)
+	}
+	res.Unmapped = p.unmapped.list()
+	return res, nil
+}
+
+// record is one source issue before IDs are assigned.
+type record struct {
+	key   string // Source key: "PROJ-12", "ENG-4", "owner/repo#7"
+	issue model.Issue
+}
+
+// link is a dependency between two source keys: from depends on to.
+type link struct {
+	from, to string
+	depType  model.DependencyType
+}
+
+// parsed is the output of a source parser.
+type parsed struct {
+	records  []record
+	links    []link
+	unmapped unmappedFields
+}
+
+func externalRef(src Source, key string) string {
+	return string(src) + ":" + key
+}
+
+// newID derives the beads ID for a source key.
+func newID(src Source, key, prefix string) string {
+	number := key
+	if i := strings.LastIndexAny(key, "-#"); i >= 0 {
+		number = key[i+1:]
+	}
+	if src == SourceGitHub && prefix == "" {
+		prefix = "gh"
+	}
+	if prefix == "" {
+		return key
+	}
+	return prefix + "-" + number
+}
+
+// assignIDs gives every record its beads ID and ExternalRef.
+func (p *parsed) assignIDs(src Source, opts Options) error {
+	byRef := make(map[string]string, len(opts.Existing))
+	refByID := make(map[string]string, len(opts.Existing))
+	for _, iss := range opts.Existing {
+		ref := ""
+		if iss.ExternalRef != nil {
+			ref = *iss.ExternalRef
+			byRef[ref] = iss.ID
+		}
+		refByID[iss.ID] = ref
+	}
+
+	taken := make(map[string]string, len(p.records))
+	for i := range p.records {
+		rec := &p.records[i]
+		ref := externalRef(src, rec.key)
+		id, ok := byRef[ref]
+		if !ok {
+			id = newID(src, rec.key, opts.Prefix)
+			if other, exists := refByID[id]; exists && other != ref {
+				return fmt.Errorf("%s would be imported as %s, but an unrelated issue already has that ID; choose another --prefix", rec.key, id)
+			}
+		}
+		if other, dup := taken[id]; dup {
+			return fmt.Errorf("%s and %s both map to ID %s; choose another --prefix", other, rec.key, id)
+		}
+		taken[id] = rec.key
+		rec.issue.ID = id
+		rec.issue.ExternalRef = &ref
+		for _, c := range rec.issue.Comments {
+			c.IssueID = id
+		}
+	}
+	return nil
+}
+
+// resolveLinks turns source links into dependencies. Targets outside the
+// export are looked up among existing issues by ExternalRef; anything
+// still unknown is dropped with a warning.
+func (p *parsed) resolveLinks(src Source, existing []model.Issue) []string {
+	index := make(map[string]int, len(p.records))
+	ids := make(map[string]string, len(p.records))
+	for i, rec := range p.records {
+		index[rec.key] = i
+		ids[rec.key] = rec.issue.ID
+	}
+	for _, iss := range existing {
+		if iss.ExternalRef == nil {
+			continue
+		}
+		if key, ok := strings.CutPrefix(*iss.ExternalRef, string(src)+":"); ok {
+			if _, inExport := ids[key]; !inExport {
+				ids[key] = iss.ID
+			}
+		}
+	}
+
+	var warnings []string
+	for _, l := range p.links {
+		fromIdx, ok := index[l.from]
+		if !ok {
+			// Reverse links ("X blocks Y") may name a Y outside the export.
+			warnings = append(warnings, fmt.Sprintf("%s: linked issue %s is not in the export; dropped", l.to, l.from))
+			continue
+		}
+		to, ok := ids[l.to]
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("%s: %s %s is not in the export; dropped", l.from, describeLink(l.depType), l.to))
+			continue
+		}
+		issue := &p.records[fromIdx].issue
+		if to == issue.ID || hasDependency(issue, to, l.depType) {
+			continue
+		}
+		issue.Dependencies = append(issue.Dependencies, &model.Dependency{
+			IssueID:     issue.ID,
+			DependsOnID: to,
+			Type:        l.depType,
+			CreatedAt:   issue.CreatedAt,
+		})
+	}
+	return warnings
+}
+
+func describeLink(t model.DependencyType) string {
+	switch t {
+	case model.DepParentChild:
+		return "parent"
+	case model.DepRelated:
+		return "related issue"
+	}
+	return "blocker"
+}
+
+func hasDependency(issue *model.Issue, to string, t model.DependencyType) bool {
+	for _, d := range issue.Dependencies {
+		if d.DependsOnID == to && d.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+// unmappedFields collects dropped fields and untranslatable values.
+type unmappedFields map[string]*UnmappedField
+
+func (u *unmappedFields) add(field, example string) {
+	if *u == nil {
+		*u = make(unmappedFields)
+	}
+	f, ok := (*u)[field]
+	if !ok {
+		f = &UnmappedField{Field: field, Example: truncate(example, 60)}
+		(*u)[field] = f
+	}
+	f.Count++
+}
+
+func (u unmappedFields) list() []UnmappedField {
+	out := make([]UnmappedField, 0, len(u))
+	for _, f := range u {
+		out = append(out, *f)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Field < out[j].Field })
+	return out
+}
+
+func truncate(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
+
+// normalizeName lowercases s and folds separators so "In-Progress",
+// "in_progress" and "In Progress" compare equal.
+func normalizeName(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.NewReplacer("_", " ", "-", " ", "'", "").Replace(s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// statusFromName maps a tracker's workflow state onto a beads status.
+func statusFromName(name string) (model.Status, bool) {
+	switch normalizeName(name) {
+	case "open", "todo", "to do", "backlog", "new", "triage", "reopened", "selected for development", "unstarted":
+		return model.StatusOpen, true
+	case "in progress", "started", "doing", "in development", "active":
+		return model.StatusInProgress, true
+	case "in review", "review", "code review", "qa", "testing", "in qa":
+		return model.StatusReview, true
+	case "blocked", "on hold", "waiting":
+		return model.StatusBlocked, true
+	case "done", "closed", "resolved", "complete", "completed", "canceled", "cancelled",
+		"duplicate", "wont do", "wont fix", "merged":
+		return model.StatusClosed, true
+	}
+	return model.StatusOpen, false
+}
+
+// priorityFromName maps a named priority onto beads' 0 (critical) to 4.
+func priorityFromName(name string) (int, bool) {
+	n := normalizeName(name)
+	if len(n) == 2 && n[0] == 'p' && n[1] >= '0' && n[1] <= '4' {
+		return int(n[1] - '0'), true
+	}
+	switch n {
+	case "urgent", "highest", "critical", "blocker":
+		return 0, true
+	case "high", "major":
+		return 1, true
+	case "medium", "normal", "no priority", "none", "":
+		return 2, true
+	case "low", "minor":
+		return 3, true
+	case "lowest", "trivial":
+		return 4, true
+	}
+	return 2, false
+}
+
+// typeFromName maps an issue type (or a type-like label) onto a beads type.
+func typeFromName(name string) (model.IssueType, bool) {
+	switch normalizeName(name) {
+	case "bug", "defect", "incident":
+		return model.TypeBug, true
+	case "feature", "story", "user story", "new feature", "improvement", "enhancement":
+		return model.TypeFeature, true
+	case "epic":
+		return model.TypeEpic, true
+	case "chore", "maintenance":
+		return model.TypeChore, true
+	case "task", "sub task", "subtask":
+		return model.TypeTask, true
+	}
+	return model.TypeTask, false
+}
+
+// labelQualifier strips "type:", "kind/", "priority:"-style scopes from a label.
+func labelQualifier(label string) (scope, value string) {
+	for _, sep := range []string{":", "/"} {
+		if before, after, ok := strings.Cut(label, sep); ok {
+			return normalizeName(before), strings.TrimSpace(after)
+		}
+	}
+	return "", label
+}
+
+// classifyLabels derives priority and type from labels such as "P1",
+// "priority: high", "bug" or "kind/feature". Labels are kept as-is.
+func classifyLabels(labels []string) (priority int, priorityOK bool, issueType model.IssueType, typeOK bool) {
+	priority, issueType = 2, model.TypeTask
+	for _, label := range labels {
+		scope, value := labelQualifier(label)
+		switch scope {
+		case "priority", "prio":
+			if p, ok := priorityFromName(value); ok && !priorityOK {
+				priority, priorityOK = p, true
+			}
+			continue
+		case "type", "kind":
+			if t, ok := typeFromName(value); ok && !typeOK {
+				issueType, typeOK = t, true
+			}
+			continue
+		}
+		if n := normalizeName(label); len(n) == 2 && n[0] == 'p' {
+			if p, ok := priorityFromName(n); ok && !priorityOK {
+				priority, priorityOK = p, true
+			}
+			continue
+		}
+		if t, ok := typeFromName(label); ok && !typeOK && t != model.TypeTask {
+			issueType, typeOK = t, true
+		}
+	}
+	return priority, priorityOK, issueType, typeOK
+}
@@ -0,0 +1,256 @@
+package importer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+const githubExport = `[
+  {"number": 1, "title": "Login fails", "body": "Crashes on submit.\nBlocked by #2", "state": "OPEN",
+   "labels": [{"name": "bug"}, {"name": "priority: high"}], "assignees": [{"login": "ana"}, {"login": "bo"}],
+   "comments": [{"author": {"login": "bo"}, "body": "also depends on #3", "createdAt": "2024-03-02T10:00:00Z"}],
+   "createdAt": "2024-03-01T09:00:00Z", "updatedAt": "2024-03-02T10:00:00Z", "closedAt": null,
+   "url": "https://github.com/acme/web/issues/1", "milestone": {"title": "v1"}},
+  {"number": 2, "title": "Session store", "body": "", "state": "CLOSED", "labels": [{"name": "P0"}],
+   "assignees": [], "comments": [], "createdAt": "2024-02-01T09:00:00Z", "updatedAt": "2024-02-05T09:00:00Z",
+   "closedAt": "2024-02-05T09:00:00Z", "url": "https://github.com/acme/web/issues/2", "stateReason": "NOT_PLANNED"}
+]`
+
+func byID(t *testing.T, issues []model.Issue) map[string]model.Issue {
+	t.Helper()
+	m := make(map[string]model.Issue, len(issues))
+	for _, iss := range issues {
+		if err := iss.Validate(); err != nil {
+			t.Fatalf("%s: %v", iss.ID, err)
+		}
+		m[iss.ID] = iss
+	}
+	return m
+}
+
+func unmappedFieldNames(res *Result) string {
+	var names []string
+	for _, f := range res.Unmapped {
+		names = append(names, f.Field)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestImportGitHub(t *testing.T) {
+	res, err := Import(SourceGitHub, strings.NewReader(githubExport), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues := byID(t, res.Issues)
+	login, store := issues["gh-1"], issues["gh-2"]
+
+	if login.Status != model.StatusOpen || login.Priority != 1 || login.IssueType != model.TypeBug || login.Assignee != "ana" {
+		t.Errorf("gh-1 mapped wrong: status=%s priority=%d type=%s assignee=%s", login.Status, login.Priority, login.IssueType, login.Assignee)
+	}
+	if *login.ExternalRef != "github:acme/web#1" {
+		t.Errorf("ExternalRef = %q", *login.ExternalRef)
+	}
+	if len(login.Comments) != 1 || login.Comments[0].Author != "bo" || login.Comments[0].IssueID != "gh-1" {
+		t.Errorf("comments = %+v", login.Comments)
+	}
+	if len(login.Dependencies) != 1 || login.Dependencies[0].DependsOnID != "gh-2" || login.Dependencies[0].Type != model.DepBlocks {
+		t.Errorf("dependencies = %+v", login.Dependencies)
+	}
+	if store.Status != model.StatusClosed || store.ClosedAt == nil || store.Priority != 0 {
+		t.Errorf("gh-2 mapped wrong: %+v", store)
+	}
+
+	if got := unmappedFieldNames(res); got != "assignees (beyond the first),milestone,stateReason" {
+		t.Errorf("unmapped = %s", got)
+	}
+	if len(res.Warnings) != 1 || !strings.Contains(res.Warnings[0], "acme/web#3") {
+		t.Errorf("warnings = %v", res.Warnings)
+	}
+}
+
+const jiraExport = "\xef\xbb\xbfSummary,Issue key,Issue id,Issue Type,Status,Status Category,Priority,Assignee,Reporter,Created,Updated,Resolved,Labels,Labels,Comment,Inward issue link (Blocks),Outward issue link (Blocks),Parent,Original Estimate,Sprint\n" +
+	"Checkout epic,SHOP-1,1001,Epic,In Progress,In Progress,High,ana,lee,01/Mar/24 9:00 AM,02/Mar/24 9:00 AM,,payments,,,,,,,\n" +
+	"Card form,SHOP-2,1002,Story,Waiting for QA,In Progress,Medium,bo,lee,01/Mar/24 10:00 AM,03/Mar/24 4:30 PM,,payments,frontend,\"02/Mar/24 1:00 PM;bo;Needs the token API\",SHOP-3,,1001,7200,Sprint 4\n" +
+	"Token API,SHOP-3,1003,Task,Done,Done,Highest,ana,lee,28/Feb/24 9:00 AM,01/Mar/24 5:00 PM,01/Mar/24 5:00 PM,,,,,SHOP-2,1001,,Sprint 4\n"
+
+func TestImportJira(t *testing.T) {
+	res, err := Import(SourceJira, strings.NewReader(jiraExport), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues := byID(t, res.Issues)
+	epic, form, api := issues["SHOP-1"], issues["SHOP-2"], issues["SHOP-3"]
+
+	if epic.IssueType != model.TypeEpic || epic.Status != model.StatusInProgress || epic.Priority != 1 {
+		t.Errorf("SHOP-1 mapped wrong: %+v", epic)
+	}
+	// Custom workflow state falls back on its status category
+	if form.Status != model.StatusInProgress || form.IssueType != model.TypeFeature || strings.Join(form.Labels, ",") != "payments,frontend" {
+		t.Errorf("SHOP-2 mapped wrong: status=%s type=%s labels=%v", form.Status, form.IssueType, form.Labels)
+	}
+	if form.EstimatedMinutes == nil || *form.EstimatedMinutes != 120 {
+		t.Errorf("estimate = %v", form.EstimatedMinutes)
+	}
+	if len(form.Comments) != 1 || form.Comments[0].Author != "bo" || form.Comments[0].Text != "Needs the token API" {
+		t.Errorf("comments = %+v", form.Comments)
+	}
+	// Blocks links from both sides collapse to one edge; parent id resolves to the key
+	deps := map[string]model.DependencyType{}
+	for _, d := range form.Dependencies {
+		deps[d.DependsOnID] = d.Type
+	}
+	if len(form.Dependencies) != 2 || deps["SHOP-3"] != model.DepBlocks || deps["SHOP-1"] != model.DepParentChild {
+		t.Errorf("SHOP-2 dependencies = %v", deps)
+	}
+	if api.Status != model.StatusClosed || api.ClosedAt == nil || api.Priority != 0 || len(api.Dependencies) != 1 {
+		t.Errorf("SHOP-3 mapped wrong: %+v", api)
+	}
+
+	if got := unmappedFieldNames(res); got != "Reporter,Sprint" {
+		t.Errorf("unmapped = %s", got)
+	}
+	if len(res.Warnings) != 0 {
+		t.Errorf("warnings = %v", res.Warnings)
+	}
+}
+
+const linearExport = "ID,Team,Title,Description,Status,Estimate,Priority,Project,Assignee,Labels,Created,Updated,Started,Completed,Canceled,Parent issue,Blocked by,Related issues\n" +
+	"ENG-1,Engineering,Rate limiter,,In Progress,3,Urgent,API,ana,\"Feature, backend\",2024-03-01T09:00:00.000Z,2024-03-02T09:00:00.000Z,2024-03-02T09:00:00.000Z,,,,ENG-2,ENG-3\n" +
+	"ENG-2,Engineering,Redis cluster,,Shipped,,No priority,API,,Chore,2024-02-01T09:00:00.000Z,2024-02-10T09:00:00.000Z,,2024-02-10T09:00:00.000Z,,,,\n" +
+	"ENG-3,Engineering,Docs,,Todo,,Low,,,,2024-03-03T09:00:00.000Z,2024-03-03T09:00:00.000Z,,,,ENG-1,,ENG-1\n"
+
+func TestImportLinear(t *testing.T) {
+	res, err := Import(SourceLinear, strings.NewReader(linearExport), Options{Prefix: "api"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues := byID(t, res.Issues)
+	limiter, redis, docs := issues["api-1"], issues["api-2"], issues["api-3"]
+
+	if limiter.Status != model.StatusInProgress || limiter.Priority != 0 || limiter.IssueType != model.TypeFeature {
+		t.Errorf("api-1 mapped wrong: %+v", limiter)
+	}
+	if *limiter.ExternalRef != "linear:ENG-1" {
+		t.Errorf("ExternalRef = %q", *limiter.ExternalRef)
+	}
+	// Unknown state with a Completed stamp is closed
+	if redis.Status != model.StatusClosed || redis.ClosedAt == nil || redis.Priority != 2 || redis.IssueType != model.TypeChore {
+		t.Errorf("api-2 mapped wrong: %+v", redis)
+	}
+	if len(limiter.Dependencies) != 2 {
+		t.Errorf("api-1 dependencies = %+v", limiter.Dependencies)
+	}
+	if len(docs.Dependencies) != 1 || docs.Dependencies[0].Type != model.DepParentChild {
+		t.Errorf("api-3 dependencies = %+v", docs.Dependencies)
+	}
+	if got := unmappedFieldNames(res); got != "Estimate,Project" {
+		t.Errorf("unmapped = %s", got)
+	}
+}
+
+func TestReimportIsIdempotent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".beads", "issues.jsonl")
+
+	// An unrelated issue already in the file is kept verbatim
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	local := `{"id":"bd-7","title":"Local","status":"open","priority":2,"issue_type":"task","custom":"kept"}` + "\n"
+	if err := os.WriteFile(path, []byte(local), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(export string) WriteStats {
+		t.Helper()
+		existing, err := loader.LoadIssuesFromFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := Import(SourceJira, strings.NewReader(export), Options{Existing: existing})
+		if err != nil {
+			t.Fatal(err)
+		}
+		stats, err := WriteJSONL(path, res.Issues)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return stats
+	}
+
+	if stats := run(jiraExport); stats.Created != 3 {
+		t.Fatalf("first import: %+v", stats)
+	}
+	first, _ := os.ReadFile(path)
+	if !bytes.HasPrefix(first, []byte(local)) {
+		t.Fatalf("unrelated line was rewritten:\n%s", first)
+	}
+
+	if stats := run(jiraExport); stats.Unchanged != 3 || stats.Created+stats.Updated != 0 {
+		t.Fatalf("re-import: %+v", stats)
+	}
+	second, _ := os.ReadFile(path)
+	if !bytes.Equal(first, second) {
+		t.Fatalf("re-import changed the file")
+	}
+
+	// A changed title updates in place under the same ID
+	edited := strings.Replace(jiraExport, "Token API", "Token service", 1)
+	if stats := run(edited); stats.Updated != 1 || stats.Unchanged != 2 {
+		t.Fatalf("edited import: %+v", stats)
+	}
+	issues, err := loader.LoadIssuesFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 4 || byID(t, issues)["SHOP-3"].Title != "Token service" {
+		t.Fatalf("after edit: %+v", issues)
+	}
+}
+
+func TestReimportWithNewCommentUpdatesOnlyThatIssue(t *testing.T) {
+	export := strings.Replace(githubExport, `"assignees": [], "comments": []`,
+		`"assignees": [], "comments": [{"author": {"login": "ana"}, "body": "done", "createdAt": "2024-02-05T09:00:00Z"}]`, 1)
+	commented := strings.Replace(export, `"createdAt": "2024-03-02T10:00:00Z"}]`,
+		`"createdAt": "2024-03-02T10:00:00Z"}, {"author": {"login": "ana"}, "body": "on it", "createdAt": "2024-03-03T10:00:00Z"}]`, 1)
+	if commented == export {
+		t.Fatal("fixture edit did not apply")
+	}
+
+	first, err := Import(SourceGitHub, strings.NewReader(export), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, _, err := MergeJSONL(nil, first.Issues)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Import(SourceGitHub, strings.NewReader(commented), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, stats, err := MergeJSONL(content, second.Issues); err != nil || stats.Updated != 1 || stats.Unchanged != 1 {
+		t.Fatalf("re-import after one new comment: %+v, %v", stats, err)
+	}
+}
+
+func TestImportRefusesIDCollision(t *testing.T) {
+	existing := []model.Issue{{ID: "SHOP-2", Title: "Mine", Status: model.StatusOpen, IssueType: model.TypeTask}}
+	if _, err := Import(SourceJira, strings.NewReader(jiraExport), Options{Existing: existing}); err == nil || !strings.Contains(err.Error(), "--prefix") {
+		t.Fatalf("expected a collision error, got %v", err)
+	}
+}
+
+func TestParseSource(t *testing.T) {
+	if src, err := ParseSource("GitHub"); err != nil || src != SourceGitHub {
+		t.Fatalf("ParseSource(GitHub) = %q, %v", src, err)
+	}
+	if _, err := ParseSource("trello"); err == nil {
+		t.Fatal("expected an error for an unknown source")
+	}
+}
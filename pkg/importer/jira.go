@@ -0,0 +1,150 @@
+package importer
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// parseJira reads Jira's "Export Excel CSV (all fields)".
+func parseJira(r io.Reader) (*parsed, error) {
+	t, err := readCSV(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.require("Issue key"); err != nil {
+		return nil, err
+	}
+	if err := t.require("Summary"); err != nil {
+		return nil, err
+	}
+
+	// Newer exports point at parents by numeric issue id, not key.
+	keyByID := make(map[string]string)
+	for i := range t.rows {
+		row := t.row(i)
+		if id, key := row.get("Issue id"), row.get("Issue key"); id != "" && key != "" {
+			keyByID[id] = key
+		}
+	}
+	resolve := func(ref string) string {
+		if key, ok := keyByID[ref]; ok {
+			return key
+		}
+		return ref
+	}
+
+	p := &parsed{}
+	for i := range t.rows {
+		row := t.row(i)
+		key := row.get("Issue key")
+		if key == "" {
+			continue
+		}
+		row.get("Issue id")
+
+		issue := model.Issue{
+			Title:       row.get("Summary"),
+			Description: row.get("Description"),
+			Assignee:    row.get("Assignee"),
+			Labels:      row.all("Labels"),
+		}
+		if created := row.timeField(&p.unmapped, "Created"); created != nil {
+			issue.CreatedAt = *created
+		}
+		if updated := row.timeField(&p.unmapped, "Updated"); updated != nil {
+			issue.UpdatedAt = *updated
+		}
+		issue.DueDate = row.timeField(&p.unmapped, "Due Date", "Due date")
+
+		status := row.get("Status")
+		var ok bool
+		if issue.Status, ok = statusFromName(status); !ok {
+			// Custom workflow states still belong to a status category.
+			if s, catOK := statusFromName(row.get("Status Category")); catOK {
+				issue.Status = s
+			} else if row.get("Resolution") != "" {
+				issue.Status = model.StatusClosed
+			} else {
+				p.unmapped.add("Status="+status, key)
+			}
+		}
+		row.all("Status Category", "Resolution")
+		if issue.Status == model.StatusClosed {
+			issue.ClosedAt = row.timeField(&p.unmapped, "Resolved")
+			if issue.ClosedAt == nil {
+				closed := issue.UpdatedAt
+				issue.ClosedAt = &closed
+			}
+		} else {
+			row.all("Resolved")
+		}
+
+		if prio := row.get("Priority"); prio != "" {
+			if issue.Priority, ok = priorityFromName(prio); !ok {
+				p.unmapped.add("Priority="+prio, key)
+			}
+		} else {
+			issue.Priority = 2
+		}
+		if typ := row.get("Issue Type"); typ != "" {
+			if issue.IssueType, ok = typeFromName(typ); !ok {
+				p.unmapped.add("Issue Type="+typ, key)
+			}
+		} else {
+			issue.IssueType = model.TypeTask
+		}
+
+		if est := row.get("Original Estimate", "Original estimate"); est != "" {
+			if secs, err := strconv.Atoi(est); err == nil && secs > 0 {
+				minutes := secs / 60
+				issue.EstimatedMinutes = &minutes
+			} else {
+				p.unmapped.add("Original Estimate (not seconds)", est)
+			}
+		}
+
+		// Numbered per issue, like GitHub comments.
+		for j, c := range row.all("Comment") {
+			issue.Comments = append(issue.Comments, jiraComment(int64(j+1), c, issue.CreatedAt))
+		}
+
+		for _, target := range row.all("Inward issue link (Blocks)") {
+			p.links = append(p.links, link{from: key, to: resolve(target), depType: model.DepBlocks})
+		}
+		for _, target := range row.all("Outward issue link (Blocks)") {
+			p.links = append(p.links, link{from: resolve(target), to: key, depType: model.DepBlocks})
+		}
+		for _, target := range row.all("Inward issue link (Relates)", "Outward issue link (Relates)") {
+			// Both sides list the link; keep one edge, lower key first.
+			a, b := key, resolve(target)
+			if b < a {
+				a, b = b, a
+			}
+			p.links = append(p.links, link{from: a, to: b, depType: model.DepRelated})
+		}
+		if parent := row.get("Parent", "Parent id", "Custom field (Epic Link)"); parent != "" {
+			p.links = append(p.links, link{from: key, to: resolve(parent), depType: model.DepParentChild})
+		}
+		row.all("Parent summary")
+
+		row.reportUnused(&p.unmapped)
+		p.records = append(p.records, record{key: key, issue: issue})
+	}
+	return p, nil
+}
+
+// jiraComment splits Jira's "date;author;text" comment cells.
+func jiraComment(id int64, cell string, fallback time.Time) *model.Comment {
+	c := &model.Comment{ID: id, Text: cell, CreatedAt: fallback}
+	parts := strings.SplitN(cell, ";", 3)
+	if len(parts) == 3 {
+		if at, ok := parseExportTime(parts[0]); ok {
+			c.CreatedAt, c.Author, c.Text = at, strings.TrimSpace(parts[1]), parts[2]
+		}
+	}
+	return c
+}
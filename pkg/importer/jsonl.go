@@ -0,0 +1,121 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/writeback"
+)
+
+// WriteStats counts what a merge did to the JSONL file.
+type WriteStats struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+}
+
+// MergeJSONL merges issues into the beads JSONL content existing (which
+// may be empty). Lines for other issues, and lines whose issue did not
+// change, are kept byte-for-byte; changed issues are re-encoded in place
+// and new issues are appended in order.
+func MergeJSONL(existing []byte, issues []model.Issue) ([]byte, WriteStats, error) {
+	var stats WriteStats
+	encoded := make(map[string][]byte, len(issues))
+	for _, iss := range issues {
+		line, err := json.Marshal(iss)
+		if err != nil {
+			return nil, stats, fmt.Errorf("encode %s: %w", iss.ID, err)
+		}
+		encoded[iss.ID] = line
+	}
+
+	var out bytes.Buffer
+	out.Grow(len(existing) + len(issues)*512)
+	written := make(map[string]bool, len(issues))
+	r := bufio.NewReader(bytes.NewReader(existing))
+	for {
+		line, readErr := r.ReadBytes('\n')
+		if len(line) > 0 {
+			trimmed := bytes.TrimSpace(line)
+			id := probeID(trimmed)
+			if next, ok := encoded[id]; ok && !written[id] {
+				written[id] = true
+				// Compare through the model so key order and bd-only
+				// fields do not count as changes.
+				var current model.Issue
+				if json.Unmarshal(trimmed, &current) == nil && jsonEqual(current, next) {
+					stats.Unchanged++
+				} else {
+					stats.Updated++
+					line = append(next, '\n')
+				}
+			}
+			out.Write(line)
+			if line[len(line)-1] != '\n' {
+				out.WriteByte('\n')
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return nil, stats, readErr
+		}
+	}
+
+	for _, iss := range issues {
+		if written[iss.ID] {
+			continue
+		}
+		written[iss.ID] = true
+		out.Write(encoded[iss.ID])
+		out.WriteByte('\n')
+		stats.Created++
+	}
+	return out.Bytes(), stats, nil
+}
+
+func jsonEqual(issue model.Issue, encoded []byte) bool {
+	current, err := json.Marshal(issue)
+	return err == nil && bytes.Equal(current, encoded)
+}
+
+func probeID(line []byte) string {
+	var probe struct {
+		ID string `json:"id"`
+	}
+	if json.Unmarshal(line, &probe) != nil {
+		return ""
+	}
+	return probe.ID
+}
+
+// WriteJSONL merges issues into the JSONL file at path, creating it (and
+// its directory) if needed. The file is replaced atomically, and left
+// untouched when nothing changed.
+func WriteJSONL(path string, issues []model.Issue) (WriteStats, error) {
+	existing, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return WriteStats{}, err
+	}
+	content, stats, err := MergeJSONL(existing, issues)
+	if err != nil {
+		return stats, err
+	}
+	if stats.Created == 0 && stats.Updated == 0 && existing != nil {
+		return stats, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return stats, err
+	}
+	return stats, writeback.WriteFileAtomic(path, content)
+}
@@ -0,0 +1,117 @@
+package importer
+
+import (
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// linearKeyPattern matches issue identifiers such as ENG-42 inside
+// Linear's comma-separated relation columns.
+var linearKeyPattern = regexp.MustCompile(`\b[A-Z][A-Z0-9]*-\d+\b`)
+
+// linearNumericPriority maps Linear's numeric priority (0 = none, 1 = urgent).
+var linearNumericPriority = map[string]int{"0": 2, "1": 0, "2": 1, "3": 2, "4": 3}
+
+// parseLinear reads Linear's workspace CSV export.
+func parseLinear(r io.Reader) (*parsed, error) {
+	t, err := readCSV(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.require("ID"); err != nil {
+		return nil, err
+	}
+	if err := t.require("Title"); err != nil {
+		return nil, err
+	}
+
+	p := &parsed{}
+	for i := range t.rows {
+		row := t.row(i)
+		key := row.get("ID")
+		if key == "" {
+			continue
+		}
+
+		issue := model.Issue{
+			Title:       row.get("Title"),
+			Description: row.get("Description"),
+			Assignee:    row.get("Assignee"),
+		}
+		for _, l := range strings.Split(row.get("Labels"), ",") {
+			if l = strings.TrimSpace(l); l != "" {
+				issue.Labels = append(issue.Labels, l)
+			}
+		}
+		if created := row.timeField(&p.unmapped, "Created"); created != nil {
+			issue.CreatedAt = *created
+		}
+		if updated := row.timeField(&p.unmapped, "Updated"); updated != nil {
+			issue.UpdatedAt = *updated
+		}
+		issue.DueDate = row.timeField(&p.unmapped, "Due Date")
+		completed := row.timeField(&p.unmapped, "Completed")
+		canceled := row.timeField(&p.unmapped, "Canceled")
+		started := row.timeField(&p.unmapped, "Started")
+
+		status := row.get("Status")
+		var ok bool
+		if issue.Status, ok = statusFromName(status); !ok {
+			// Team-specific states: fall back on the lifecycle timestamps.
+			switch {
+			case completed != nil || canceled != nil:
+				issue.Status = model.StatusClosed
+			case started != nil:
+				issue.Status = model.StatusInProgress
+			default:
+				p.unmapped.add("Status="+status, key)
+			}
+		}
+		if issue.Status == model.StatusClosed {
+			switch {
+			case completed != nil:
+				issue.ClosedAt = completed
+			case canceled != nil:
+				issue.ClosedAt = canceled
+			default:
+				closed := issue.UpdatedAt
+				issue.ClosedAt = &closed
+			}
+		}
+
+		prio := row.get("Priority")
+		if n, numeric := linearNumericPriority[prio]; numeric {
+			issue.Priority = n
+		} else if issue.Priority, ok = priorityFromName(prio); !ok {
+			p.unmapped.add("Priority="+prio, key)
+		}
+		// Linear has no issue types; teams encode them as labels.
+		_, _, issue.IssueType, _ = classifyLabels(issue.Labels)
+
+		for _, target := range linearKeyPattern.FindAllString(row.get("Blocked by"), -1) {
+			p.links = append(p.links, link{from: key, to: target, depType: model.DepBlocks})
+		}
+		for _, target := range linearKeyPattern.FindAllString(row.get("Blocking"), -1) {
+			p.links = append(p.links, link{from: target, to: key, depType: model.DepBlocks})
+		}
+		for _, target := range linearKeyPattern.FindAllString(strings.Join(row.all("Related issues", "Duplicate of"), ","), -1) {
+			a, b := key, target
+			if b < a {
+				a, b = b, a
+			}
+			p.links = append(p.links, link{from: a, to: b, depType: model.DepRelated})
+		}
+		if parent := linearKeyPattern.FindString(row.get("Parent issue")); parent != "" {
+			p.links = append(p.links, link{from: key, to: parent, depType: model.DepParentChild})
+		}
+		// Team is the ID prefix; Triaged is an intermediate lifecycle stamp.
+		row.all("Team", "Triaged")
+
+		row.reportUnused(&p.unmapped)
+		p.records = append(p.records, record{key: key, issue: issue})
+	}
+	return p, nil
+}
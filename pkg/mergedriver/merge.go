@@ -0,0 +1,490 @@
+// Package mergedriver implements a git merge driver for beads JSONL files.
+//
+// Git only sees lines, so two branches that touch different issues still
+// conflict whenever their edits land near each other. Merge instead matches
+// issues by ID across the base, ours and theirs versions and merges each
+// issue field by field:
+//
+//   - a field changed on one side takes that side's value;
+//   - labels, dependencies and comments are merged as sets (additions from
+//     both sides are kept, removals from either side are honored);
+//   - updated_at takes the later of the two;
+//   - a field changed differently on both sides is a true conflict: the side
+//     with the later updated_at wins (ours on a tie) and the conflict is
+//     reported so nothing is lost silently.
+//
+// Fields bv does not model are carried through untouched. The result is
+// written as canonical JSONL: one issue per line, sorted by ID, with keys in
+// model order.
+package mergedriver
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// Side names used in conflicts.
+const (
+	SideOurs   = "ours"
+	SideTheirs = "theirs"
+)
+
+// Conflict is a field both sides changed to different values.
+type Conflict struct {
+	IssueID string          `json:"issue_id"`
+	Field   string          `json:"field"`
+	Base    json.RawMessage `json:"base,omitempty"`
+	Ours    json.RawMessage `json:"ours,omitempty"`
+	Theirs  json.RawMessage `json:"theirs,omitempty"`
+	Winner  string          `json:"winner"` // SideOurs or SideTheirs
+	Reason  string          `json:"reason"`
+}
+
+// Result is a merged JSONL file.
+type Result struct {
+	Data      []byte
+	Issues    int // Issues in the merged file
+	Merged    int // Issues changed on both sides
+	Conflicts []Conflict
+}
+
+// deletedField is the Conflict.Field for an issue deleted on one side and
+// edited on the other.
+const deletedField = "(deleted)"
+
+// Merge performs a three-way merge of beads JSONL content. base may be
+// empty (both sides added the file). Malformed or invalid lines are an
+// error: the driver refuses rather than dropping an issue.
+func Merge(base, ours, theirs []byte) (*Result, error) {
+	b, err := parseSide("base", base)
+	if err != nil {
+		return nil, err
+	}
+	o, err := parseSide(SideOurs, ours)
+	if err != nil {
+		return nil, err
+	}
+	t, err := parseSide(SideTheirs, theirs)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]bool, len(o)+len(t))
+	for id := range o {
+		ids[id] = true
+	}
+	for id := range t {
+		ids[id] = true
+	}
+	sorted := make([]string, 0, len(ids))
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+	sort.Strings(sorted)
+
+	res := &Result{}
+	var out bytes.Buffer
+	for _, id := range sorted {
+		merged, conflicts, both := mergeIssue(id, b[id], o[id], t[id])
+		res.Conflicts = append(res.Conflicts, conflicts...)
+		if both {
+			res.Merged++
+		}
+		if merged == nil {
+			continue
+		}
+		out.Write(encodeIssue(merged))
+		out.WriteByte('\n')
+		res.Issues++
+	}
+	res.Data = out.Bytes()
+	return res, nil
+}
+
+// entry is one issue as read from one side.
+type entry struct {
+	fields  map[string]json.RawMessage // Compacted values, including fields bv does not model
+	updated time.Time
+}
+
+// parseSide reads one version of the file. Lines are validated through the
+// loader and kept raw so unknown fields survive the merge.
+func parseSide(name string, data []byte) (map[string]*entry, error) {
+	var warnings []string
+	issues, err := loader.ParseIssuesWithOptions(bytes.NewReader(data), loader.ParseOptions{
+		WarningHandler: func(msg string) { warnings = append(warnings, msg) },
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if len(warnings) > 0 {
+		return nil, fmt.Errorf("%s: %s", name, warnings[0])
+	}
+	updated := make(map[string]time.Time, len(issues))
+	for _, iss := range issues {
+		updated[iss.ID] = iss.UpdatedAt
+	}
+
+	entries := make(map[string]*entry, len(issues))
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), loader.DefaultMaxBufferSize)
+	for lineNum := 1; sc.Scan(); lineNum++ {
+		line := bytes.TrimSpace(sc.Bytes())
+		if lineNum == 1 {
+			line = bytes.TrimPrefix(line, []byte("\xef\xbb\xbf"))
+		}
+		if len(line) == 0 {
+			continue
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(line, &fields); err != nil {
+			return nil, fmt.Errorf("%s: line %d: %w", name, lineNum, err)
+		}
+		for k, v := range fields {
+			fields[k] = compact(v)
+		}
+		var id string
+		_ = json.Unmarshal(fields["id"], &id)
+		// A repeated ID keeps its last line, as bd does on import.
+		entries[id] = &entry{fields: fields, updated: updated[id]}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return entries, nil
+}
+
+func compact(v json.RawMessage) json.RawMessage {
+	var buf bytes.Buffer
+	if json.Compact(&buf, v) != nil {
+		return v
+	}
+	return buf.Bytes()
+}
+
+// mergeIssue merges one issue. It returns nil fields when the issue is
+// deleted, and whether both sides changed it.
+func mergeIssue(id string, base, ours, theirs *entry) (map[string]json.RawMessage, []Conflict, bool) {
+	switch {
+	case ours == nil && theirs == nil:
+		return nil, nil, false
+	case ours == nil || theirs == nil:
+		kept, side := theirs, SideTheirs
+		if theirs == nil {
+			kept, side = ours, SideOurs
+		}
+		if base == nil {
+			return kept.fields, nil, false // Added on one side
+		}
+		if sameFields(base.fields, kept.fields) {
+			return nil, nil, false // Deleted on the other side
+		}
+		// Deleted on one side, edited on the other: keep the edit.
+		return kept.fields, []Conflict{{
+			IssueID: id,
+			Field:   deletedField,
+			Winner:  side,
+			Reason:  "deleted on one side and edited on the other; kept the edited issue",
+		}}, false
+	}
+
+	var baseFields map[string]json.RawMessage
+	if base != nil {
+		baseFields = base.fields
+	}
+	oursChanged := !sameFields(baseFields, ours.fields)
+	theirsChanged := !sameFields(baseFields, theirs.fields)
+	switch {
+	case !theirsChanged:
+		return ours.fields, nil, false
+	case !oursChanged:
+		return theirs.fields, nil, false
+	}
+
+	tiebreak, reason := SideOurs, "both sides changed it; ours has the later or equal updated_at"
+	if theirs.updated.After(ours.updated) {
+		tiebreak, reason = SideTheirs, "both sides changed it; theirs has the later updated_at"
+	}
+
+	merged := make(map[string]json.RawMessage, len(ours.fields))
+	var conflicts []Conflict
+	for _, group := range fieldGroups(baseFields, ours.fields, theirs.fields) {
+		key := group[0]
+		var winner string
+		switch {
+		case key == "updated_at":
+			winner = tiebreak
+		case setFields[key] != nil:
+			if v := mergeSet(setFields[key], baseFields[key], ours.fields[key], theirs.fields[key]); v != nil {
+				merged[key] = v
+			}
+			continue
+		default:
+			bv, ov, tv := groupValue(baseFields, group), groupValue(ours.fields, group), groupValue(theirs.fields, group)
+			switch {
+			case ov == tv, tv == bv:
+				winner = SideOurs
+			case ov == bv:
+				winner = SideTheirs
+			default:
+				winner = tiebreak
+				conflicts = append(conflicts, Conflict{
+					IssueID: id,
+					Field:   strings.Join(group, "+"),
+					Base:    baseFields[key],
+					Ours:    ours.fields[key],
+					Theirs:  theirs.fields[key],
+					Winner:  winner,
+					Reason:  reason,
+				})
+			}
+		}
+		from := ours.fields
+		if winner == SideTheirs {
+			from = theirs.fields
+		}
+		for _, k := range group {
+			if v, ok := from[k]; ok {
+				merged[k] = v
+			}
+		}
+	}
+	return merged, conflicts, true
+}
+
+// coupledFields change together: a status taken from one side must bring
+// that side's closed_at along.
+var coupledFields = map[string][]string{
+	"status":    {"status", "closed_at"},
+	"closed_at": {"status", "closed_at"},
+}
+
+// fieldGroups lists every key present on any side, coupled keys grouped.
+func fieldGroups(sides ...map[string]json.RawMessage) [][]string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, side := range sides {
+		for k := range side {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	grouped := make(map[string]bool)
+	var groups [][]string
+	for _, k := range keys {
+		if grouped[k] {
+			continue
+		}
+		group := coupledFields[k]
+		if group == nil {
+			group = []string{k}
+		}
+		for _, g := range group {
+			grouped[g] = true
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// groupValue is a comparable form of a group's values ("" when absent).
+func groupValue(fields map[string]json.RawMessage, group []string) string {
+	parts := make([]string, len(group))
+	for i, k := range group {
+		parts[i] = string(fields[k])
+	}
+	return strings.Join(parts, "\x00")
+}
+
+func sameFields(a, b map[string]json.RawMessage) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || !bytes.Equal(v, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// setField describes an array field merged as a set.
+type setField struct {
+	key  func(elem json.RawMessage) string // Element identity
+	less func(a, b json.RawMessage) bool   // Canonical order
+}
+
+var setFields = map[string]*setField{
+	"labels":       {key: labelKey, less: byKey(labelKey)},
+	"dependencies": {key: dependencyKey, less: byKey(dependencyKey)},
+	"comments":     {key: commentKey, less: commentLess},
+}
+
+func byKey(key func(json.RawMessage) string) func(a, b json.RawMessage) bool {
+	return func(a, b json.RawMessage) bool { return key(a) < key(b) }
+}
+
+func labelKey(elem json.RawMessage) string {
+	var s string
+	if json.Unmarshal(elem, &s) != nil {
+		return string(elem)
+	}
+	return s
+}
+
+func dependencyKey(elem json.RawMessage) string {
+	var d model.Dependency
+	if json.Unmarshal(elem, &d) != nil {
+		return string(elem)
+	}
+	return d.DependsOnID + "\x00" + string(d.Type)
+}
+
+func commentKey(elem json.RawMessage) string {
+	var c model.Comment
+	if json.Unmarshal(elem, &c) != nil {
+		return string(elem)
+	}
+	if c.ID != 0 {
+		return fmt.Sprintf("#%d", c.ID)
+	}
+	return c.Author + "\x00" + c.CreatedAt.UTC().Format(time.RFC3339Nano) + "\x00" + c.Text
+}
+
+func commentLess(a, b json.RawMessage) bool {
+	var ca, cb model.Comment
+	_ = json.Unmarshal(a, &ca)
+	_ = json.Unmarshal(b, &cb)
+	if !ca.CreatedAt.Equal(cb.CreatedAt) {
+		return ca.CreatedAt.Before(cb.CreatedAt)
+	}
+	return commentKey(a) < commentKey(b)
+}
+
+// mergeSet merges array fields: an element survives unless a side that had
+// it in base removed it. An element edited in place takes the edited copy.
+// An empty result is omitted, as the model does.
+func mergeSet(f *setField, base, ours, theirs json.RawMessage) json.RawMessage {
+	b, o, t := setElems(f, base), setElems(f, ours), setElems(f, theirs)
+	keys := make(map[string]bool, len(o)+len(t))
+	for k := range o {
+		keys[k] = true
+	}
+	for k := range t {
+		keys[k] = true
+	}
+
+	var out []json.RawMessage
+	for k := range keys {
+		bv, inBase := b[k]
+		ov, inOurs := o[k]
+		tv, inTheirs := t[k]
+		switch {
+		case inOurs && inTheirs:
+			if bytes.Equal(ov, bv) {
+				out = append(out, tv)
+			} else {
+				out = append(out, ov)
+			}
+		case inBase:
+			// Removed on one side; dropped unless the other side edited it.
+			if inOurs && !bytes.Equal(ov, bv) {
+				out = append(out, ov)
+			} else if inTheirs && !bytes.Equal(tv, bv) {
+				out = append(out, tv)
+			}
+		case inOurs:
+			out = append(out, ov)
+		default:
+			out = append(out, tv)
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	sort.Slice(out, func(i, j int) bool { return f.less(out[i], out[j]) })
+
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, v := range out {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(v)
+	}
+	buf.WriteByte(']')
+	return buf.Bytes()
+}
+
+func setElems(f *setField, v json.RawMessage) map[string]json.RawMessage {
+	var elems []json.RawMessage
+	if len(v) == 0 || json.Unmarshal(v, &elems) != nil {
+		return nil
+	}
+	m := make(map[string]json.RawMessage, len(elems))
+	for _, e := range elems {
+		m[f.key(e)] = compact(e)
+	}
+	return m
+}
+
+// fieldOrder is the position of each model.Issue JSON key, so output keys
+// follow the order bd writes them.
+var fieldOrder = func() map[string]int {
+	t := reflect.TypeOf(model.Issue{})
+	order := make(map[string]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			order[name] = i
+		}
+	}
+	return order
+}()
+
+// encodeIssue writes one canonical JSONL line: model keys in model order,
+// then any other keys alphabetically.
+func encodeIssue(fields map[string]json.RawMessage) []byte {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		oi, iKnown := fieldOrder[keys[i]]
+		oj, jKnown := fieldOrder[keys[j]]
+		switch {
+		case iKnown && jKnown:
+			return oi < oj
+		case iKnown != jKnown:
+			return iKnown
+		}
+		return keys[i] < keys[j]
+	})
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(k)
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(fields[k])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes()
+}
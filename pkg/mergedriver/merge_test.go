@@ -0,0 +1,191 @@
+package mergedriver
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func jsonl(lines ...string) []byte {
+	return []byte(strings.Join(lines, "\n") + "\n")
+}
+
+func parseMerged(t *testing.T, res *Result) map[string]model.Issue {
+	t.Helper()
+	issues, err := loader.ParseIssues(strings.NewReader(string(res.Data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := make(map[string]model.Issue, len(issues))
+	for _, iss := range issues {
+		m[iss.ID] = iss
+	}
+	return m
+}
+
+const (
+	baseA = `{"id":"A","title":"Login","status":"open","priority":2,"issue_type":"task","created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-01T00:00:00Z","labels":["auth"]}`
+	baseB = `{"id":"B","title":"Logout","status":"open","priority":2,"issue_type":"task","created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-01T00:00:00Z"}`
+)
+
+func TestMerge_DifferentIssuesDoNotConflict(t *testing.T) {
+	base := jsonl(baseA, baseB)
+	ours := jsonl(strings.Replace(baseA, `"priority":2`, `"priority":0`, 1), baseB)
+	theirs := jsonl(baseA, strings.Replace(baseB, `"status":"open"`, `"status":"in_progress"`, 1),
+		`{"id":"C","title":"New","status":"open","priority":1,"issue_type":"bug","created_at":"2025-01-02T00:00:00Z","updated_at":"2025-01-02T00:00:00Z"}`)
+
+	res, err := Merge(base, ours, theirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Conflicts) != 0 || res.Merged != 0 {
+		t.Fatalf("conflicts=%v merged=%d", res.Conflicts, res.Merged)
+	}
+	issues := parseMerged(t, res)
+	if issues["A"].Priority != 0 || issues["B"].Status != model.StatusInProgress || issues["C"].Title != "New" {
+		t.Fatalf("merged = %+v", issues)
+	}
+	// Canonical: sorted by ID, keys in model order
+	lines := strings.Split(strings.TrimSpace(string(res.Data)), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], `{"id":"A","title":"Login",`) || !strings.HasPrefix(lines[2], `{"id":"C"`) {
+		t.Fatalf("not canonical:\n%s", res.Data)
+	}
+}
+
+func TestMerge_SameIssueDifferentFields(t *testing.T) {
+	base := jsonl(baseA)
+	ours := jsonl(strings.NewReplacer(`"priority":2`, `"priority":1`, `"updated_at":"2025-01-01T00:00:00Z"`, `"updated_at":"2025-01-03T00:00:00Z"`).Replace(baseA))
+	theirs := jsonl(strings.NewReplacer(`"title":"Login"`, `"title":"Login page"`, `"updated_at":"2025-01-01T00:00:00Z"`, `"updated_at":"2025-01-02T00:00:00Z"`).Replace(baseA))
+
+	res, err := Merge(base, ours, theirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Conflicts) != 0 || res.Merged != 1 {
+		t.Fatalf("conflicts=%v merged=%d", res.Conflicts, res.Merged)
+	}
+	a := parseMerged(t, res)["A"]
+	if a.Priority != 1 || a.Title != "Login page" || a.UpdatedAt.Day() != 3 {
+		t.Fatalf("merged A = %+v", a)
+	}
+}
+
+func TestMerge_TrueConflictUsesUpdatedAt(t *testing.T) {
+	base := jsonl(baseA)
+	ours := jsonl(strings.NewReplacer(`"title":"Login"`, `"title":"Sign in"`, `"updated_at":"2025-01-01T00:00:00Z"`, `"updated_at":"2025-01-02T00:00:00Z"`).Replace(baseA))
+	theirs := jsonl(strings.NewReplacer(`"title":"Login"`, `"title":"Log in"`, `"updated_at":"2025-01-01T00:00:00Z"`, `"updated_at":"2025-01-05T00:00:00Z"`).Replace(baseA))
+
+	res, err := Merge(base, ours, theirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a := parseMerged(t, res)["A"]; a.Title != "Log in" {
+		t.Fatalf("title = %q, want theirs (later updated_at)", a.Title)
+	}
+	if len(res.Conflicts) != 1 {
+		t.Fatalf("conflicts = %+v", res.Conflicts)
+	}
+	c := res.Conflicts[0]
+	if c.IssueID != "A" || c.Field != "title" || c.Winner != SideTheirs || string(c.Ours) != `"Sign in"` || string(c.Base) != `"Login"` {
+		t.Fatalf("conflict = %+v", c)
+	}
+	if strings.Contains(string(res.Data), "<<<<<<<") {
+		t.Fatal("conflict markers in output")
+	}
+}
+
+func TestMerge_SetsUnionAndHonorRemovals(t *testing.T) {
+	base := jsonl(`{"id":"A","title":"T","status":"open","priority":2,"issue_type":"task","labels":["auth","old"],` +
+		`"dependencies":[{"issue_id":"A","depends_on_id":"X","type":"blocks","created_at":"2025-01-01T00:00:00Z","created_by":""}]}`)
+	ours := jsonl(`{"id":"A","title":"T","status":"open","priority":2,"issue_type":"task","labels":["auth","ui"],` +
+		`"dependencies":[{"issue_id":"A","depends_on_id":"X","type":"blocks","created_at":"2025-01-01T00:00:00Z","created_by":""}],` +
+		`"comments":[{"id":1,"issue_id":"A","author":"ana","text":"ours","created_at":"2025-01-02T00:00:00Z"}]}`)
+	theirs := jsonl(`{"id":"A","title":"T","status":"open","priority":2,"issue_type":"task","labels":["old","auth","api"],` +
+		`"dependencies":[{"issue_id":"A","depends_on_id":"Y","type":"blocks","created_at":"2025-01-01T00:00:00Z","created_by":""}],` +
+		`"comments":[{"id":2,"issue_id":"A","author":"bo","text":"theirs","created_at":"2025-01-01T12:00:00Z"}]}`)
+
+	res, err := Merge(base, ours, theirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Conflicts) != 0 {
+		t.Fatalf("conflicts = %+v", res.Conflicts)
+	}
+	a := parseMerged(t, res)["A"]
+	if got := strings.Join(a.Labels, ","); got != "api,auth,ui" {
+		t.Errorf("labels = %s, want api,auth,ui (old removed by ours)", got)
+	}
+	if len(a.Dependencies) != 1 || a.Dependencies[0].DependsOnID != "Y" {
+		t.Errorf("dependencies = %+v, want only Y (X removed by theirs)", a.Dependencies)
+	}
+	if len(a.Comments) != 2 || a.Comments[0].Text != "theirs" || a.Comments[1].Text != "ours" {
+		t.Errorf("comments = %+v, want both in time order", a.Comments)
+	}
+}
+
+func TestMerge_StatusCarriesClosedAt(t *testing.T) {
+	base := jsonl(baseA)
+	ours := jsonl(strings.NewReplacer(`"status":"open"`, `"status":"closed","closed_at":"2025-01-04T00:00:00Z"`, `"updated_at":"2025-01-01T00:00:00Z"`, `"updated_at":"2025-01-04T00:00:00Z"`).Replace(baseA))
+	theirs := jsonl(strings.NewReplacer(`"status":"open"`, `"status":"in_progress"`, `"updated_at":"2025-01-01T00:00:00Z"`, `"updated_at":"2025-01-02T00:00:00Z"`).Replace(baseA))
+
+	res, err := Merge(base, ours, theirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := parseMerged(t, res)["A"]
+	if a.Status != model.StatusClosed || a.ClosedAt == nil {
+		t.Fatalf("merged A status=%s closed_at=%v", a.Status, a.ClosedAt)
+	}
+	if len(res.Conflicts) != 1 || res.Conflicts[0].Field != "status+closed_at" || res.Conflicts[0].Winner != SideOurs {
+		t.Fatalf("conflicts = %+v", res.Conflicts)
+	}
+}
+
+func TestMerge_Deletions(t *testing.T) {
+	base := jsonl(baseA, baseB)
+	// Ours deletes A; theirs leaves it alone but edits B, which ours deleted too
+	ours := jsonl()
+	theirs := jsonl(baseA, strings.Replace(baseB, `"title":"Logout"`, `"title":"Sign out"`, 1))
+
+	res, err := Merge(base, ours, theirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues := parseMerged(t, res)
+	if _, ok := issues["A"]; ok {
+		t.Error("A should stay deleted")
+	}
+	if issues["B"].Title != "Sign out" {
+		t.Errorf("B = %+v, want the edited issue kept", issues["B"])
+	}
+	if len(res.Conflicts) != 1 || res.Conflicts[0].IssueID != "B" || res.Conflicts[0].Field != deletedField {
+		t.Fatalf("conflicts = %+v", res.Conflicts)
+	}
+}
+
+func TestMerge_KeepsUnknownFields(t *testing.T) {
+	base := jsonl(`{"id":"A","title":"T","status":"open","priority":2,"issue_type":"task","zz_custom":{"k":1}}`)
+	ours := jsonl(`{"id":"A","title":"T","status":"open","priority":1,"issue_type":"task","zz_custom":{"k":1}}`)
+	theirs := jsonl(`{"id":"A","title":"T","status":"open","priority":2,"issue_type":"task","zz_custom":{"k":2}}`)
+
+	res, err := Merge(base, ours, theirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(res.Data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if string(fields["zz_custom"]) != `{"k":2}` || string(fields["priority"]) != "1" {
+		t.Fatalf("merged = %s", res.Data)
+	}
+}
+
+func TestMerge_RejectsMalformedInput(t *testing.T) {
+	if _, err := Merge(jsonl(baseA), jsonl(baseA, `{"id":"B",`), jsonl(baseA)); err == nil || !strings.Contains(err.Error(), "ours") {
+		t.Fatalf("expected an error naming ours, got %v", err)
+	}
+}
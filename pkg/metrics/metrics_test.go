@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"bytes"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -359,6 +361,66 @@ func TestEnabled(t *testing.T) {
 	SetEnabled(originalEnabled)
 }
 
+func TestTimingMetric_Buckets(t *testing.T) {
+	m := newTimingMetric("buckets")
+	m.Record(50 * time.Microsecond) // <= 0.0001
+	m.Record(2 * time.Millisecond)  // <= 0.005
+	m.Record(time.Minute)           // +Inf
+
+	got := m.Buckets()
+	if len(got) != len(TimingBuckets)+1 {
+		t.Fatalf("len(Buckets) = %d; want %d", len(got), len(TimingBuckets)+1)
+	}
+	if got[0] != 1 || got[3] != 2 || got[len(got)-2] != 2 || got[len(got)-1] != 3 {
+		t.Errorf("Buckets = %v; want cumulative 1..2..3", got)
+	}
+
+	m.Reset()
+	if got := m.Buckets(); got[len(got)-1] != 0 {
+		t.Errorf("Buckets after reset = %v; want zeros", got)
+	}
+}
+
+func TestOpenMetricsWriter(t *testing.T) {
+	ResetAll()
+	defer ResetAll()
+	TriageAnalysis.Record(3 * time.Millisecond)
+	GraphCache.Hit()
+	GraphCache.Miss()
+	GraphCache.Hit()
+
+	var buf bytes.Buffer
+	w := NewOpenMetricsWriter(&buf)
+	w.Gauge("bv_label_health", "Label health.", 42, Label{Name: "label", Value: `a"b\c`})
+	w.WriteRegistries()
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"# TYPE bv_label_health gauge\n",
+		`bv_label_health{label="a\"b\\c"} 42` + "\n",
+		"# UNIT bv_operation_duration_seconds seconds\n",
+		`bv_operation_duration_seconds_bucket{operation="triage_analysis",le="0.001"} 0` + "\n",
+		`bv_operation_duration_seconds_bucket{operation="triage_analysis",le="0.005"} 1` + "\n",
+		`bv_operation_duration_seconds_bucket{operation="triage_analysis",le="+Inf"} 1` + "\n",
+		`bv_operation_duration_seconds_count{operation="triage_analysis"} 1` + "\n",
+		`bv_operation_duration_seconds_sum{operation="triage_analysis"} 0.003` + "\n",
+		"# TYPE bv_cache_hits counter\n",
+		`bv_cache_hits_total{cache="graph_cache"} 2` + "\n",
+		`bv_cache_misses_total{cache="graph_cache"} 1` + "\n",
+		`bv_cache_hits_total{cache="style_cache"} 0` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q\n%s", want, out)
+		}
+	}
+	if !strings.HasSuffix(out, "# EOF\n") {
+		t.Errorf("output must end with # EOF")
+	}
+}
+
 func BenchmarkTimingMetric_Record(b *testing.B) {
 	m := newTimingMetric("bench")
 	d := 100 * time.Microsecond
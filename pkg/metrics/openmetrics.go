@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// OpenMetricsContentType is the Content-Type for the OpenMetrics text format.
+// Prometheus negotiates it and falls back to treating the body as text.
+const OpenMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// Label is a name/value pair attached to an OpenMetrics sample.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// OpenMetricsWriter writes metric families in the OpenMetrics text format.
+// The first write error is kept and returned by Close; later writes are
+// skipped.
+type OpenMetricsWriter struct {
+	w   io.Writer
+	err error
+}
+
+// NewOpenMetricsWriter returns a writer that emits to w.
+func NewOpenMetricsWriter(w io.Writer) *OpenMetricsWriter {
+	return &OpenMetricsWriter{w: w}
+}
+
+func (o *OpenMetricsWriter) printf(format string, args ...any) {
+	if o.err != nil {
+		return
+	}
+	_, o.err = fmt.Fprintf(o.w, format, args...)
+}
+
+// Family writes the TYPE, UNIT (when non-empty) and HELP lines that start a
+// metric family. typ is gauge, counter or histogram.
+func (o *OpenMetricsWriter) Family(name, typ, unit, help string) {
+	o.printf("# TYPE %s %s\n", name, typ)
+	if unit != "" {
+		o.printf("# UNIT %s %s\n", name, unit)
+	}
+	o.printf("# HELP %s %s\n", name, escapeHelp(help))
+}
+
+// Sample writes one sample line. name includes any suffix (_total, _bucket).
+func (o *OpenMetricsWriter) Sample(name string, value float64, labels ...Label) {
+	o.printf("%s%s %s\n", name, formatLabels(labels), formatValue(value))
+}
+
+// Gauge writes a single-sample gauge family.
+func (o *OpenMetricsWriter) Gauge(name, help string, value float64, labels ...Label) {
+	o.Family(name, "gauge", "", help)
+	o.Sample(name, value, labels...)
+}
+
+// WriteRegistries writes the built-in timing metrics as the
+// bv_operation_duration_seconds histogram and the cache metrics as
+// bv_cache_hits/bv_cache_misses counters. Every registered metric is
+// written, active or not, so series do not appear and vanish.
+func (o *OpenMetricsWriter) WriteRegistries() {
+	const timing = "bv_operation_duration_seconds"
+	o.Family(timing, "histogram", "seconds", "Time spent in instrumented bv operations.")
+	for _, m := range AllTimingMetrics() {
+		op := Label{Name: "operation", Value: m.Name()}
+		buckets := m.Buckets()
+		for i, count := range buckets {
+			le := math.Inf(1)
+			if i < len(TimingBuckets) {
+				le = TimingBuckets[i]
+			}
+			o.Sample(timing+"_bucket", float64(count), op, Label{Name: "le", Value: formatValue(le)})
+		}
+		// Reading count separately could race past the buckets; the +Inf
+		// bucket is the count by definition.
+		o.Sample(timing+"_count", float64(buckets[len(buckets)-1]), op)
+		o.Sample(timing+"_sum", float64(m.TotalNs())/1e9, op)
+	}
+
+	o.Family("bv_cache_hits", "counter", "", "Cache lookups served from cache.")
+	for _, m := range AllCacheMetrics() {
+		o.Sample("bv_cache_hits_total", float64(m.Hits()), Label{Name: "cache", Value: m.Name()})
+	}
+	o.Family("bv_cache_misses", "counter", "", "Cache lookups that had to recompute.")
+	for _, m := range AllCacheMetrics() {
+		o.Sample("bv_cache_misses_total", float64(m.Misses()), Label{Name: "cache", Value: m.Name()})
+	}
+}
+
+// Close writes the terminating # EOF line and returns the first write error.
+func (o *OpenMetricsWriter) Close() error {
+	o.printf("# EOF\n")
+	return o.err
+}
+
+func formatLabels(labels []Label) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	parts := make([]string, len(labels))
+	for i, l := range labels {
+		parts[i] = l.Name + `="` + escapeLabelValue(l.Value) + `"`
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(s string) string {
+	return labelValueEscaper.Replace(s)
+}
+
+var helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
@@ -44,8 +44,17 @@ type TimingMetric struct {
 	totalNs int64
 	maxNs   int64
 	minNs   int64 // 0 means not set
+
+	// buckets[i] counts measurements <= TimingBuckets[i]; the extra last
+	// slot counts everything slower (the +Inf bucket).
+	buckets [len(TimingBuckets) + 1]int64
 }
 
+// TimingBuckets are the histogram upper bounds, in seconds, used when
+// timings are exported as OpenMetrics histograms. They span sub-millisecond
+// cache lookups up to multi-second graph analysis on large projects.
+var TimingBuckets = [...]float64{0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5, 10}
+
 // newTimingMetric creates a new timing metric with the given name.
 func newTimingMetric(name string) *TimingMetric {
 	return &TimingMetric{name: name}
@@ -61,6 +70,7 @@ func (m *TimingMetric) Record(d time.Duration) {
 
 	atomic.AddInt64(&m.count, 1)
 	atomic.AddInt64(&m.totalNs, ns)
+	atomic.AddInt64(&m.buckets[bucketIndex(d.Seconds())], 1)
 
 	// Update max atomically using compare-and-swap
 	for {
@@ -82,6 +92,16 @@ func (m *TimingMetric) Record(d time.Duration) {
 	}
 }
 
+// bucketIndex returns the first bucket whose upper bound holds seconds.
+func bucketIndex(seconds float64) int {
+	for i, le := range TimingBuckets {
+		if seconds <= le {
+			return i
+		}
+	}
+	return len(TimingBuckets)
+}
+
 // Name returns the metric name.
 func (m *TimingMetric) Name() string {
 	return m.name
@@ -119,6 +139,18 @@ func (m *TimingMetric) AvgNs() int64 {
 	return total / count
 }
 
+// Buckets returns the cumulative histogram counts for TimingBuckets
+// followed by the +Inf bucket, as OpenMetrics expects.
+func (m *TimingMetric) Buckets() []int64 {
+	out := make([]int64, len(m.buckets))
+	var cum int64
+	for i := range m.buckets {
+		cum += atomic.LoadInt64(&m.buckets[i])
+		out[i] = cum
+	}
+	return out
+}
+
 // Stats returns all timing statistics at once.
 func (m *TimingMetric) Stats() TimingStats {
 	count := atomic.LoadInt64(&m.count)
@@ -147,6 +179,9 @@ func (m *TimingMetric) Reset() {
 	atomic.StoreInt64(&m.totalNs, 0)
 	atomic.StoreInt64(&m.maxNs, 0)
 	atomic.StoreInt64(&m.minNs, 0)
+	for i := range m.buckets {
+		atomic.StoreInt64(&m.buckets[i], 0)
+	}
 }
 
 // TimingStats holds a snapshot of timing statistics.
@@ -0,0 +1,235 @@
+package query
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+type valueKind int
+
+const (
+	kindString valueKind = iota // Whole values, case-insensitive, '*' suffix wildcard
+	kindText                    // Substrings, case-insensitive
+	kindNumber
+	kindTime
+)
+
+// field describes one queryable attribute. Exactly one accessor matches
+// the kind; related additionally allows field:(subquery).
+type field struct {
+	name    string
+	kind    valueKind
+	help    string
+	values  map[string]bool // Allowed values; nil means any
+	metric  bool            // Needs graph metrics
+	strs    func(*model.Issue, *Context) []string
+	num     func(*model.Issue, *Context) (float64, bool)
+	when    func(*model.Issue) (time.Time, bool)
+	related func(*model.Issue, *Context) []*model.Issue
+}
+
+func one(s string) []string { return []string{s} }
+
+func setOf(values ...string) map[string]bool {
+	m := make(map[string]bool, len(values))
+	for _, v := range values {
+		m[v] = true
+	}
+	return m
+}
+
+func count(issues []*model.Issue) (float64, bool) { return float64(len(issues)), true }
+
+func metric(name, help string, value func(*analysis.GraphStats, string) (float64, bool)) *field {
+	return &field{name: name, kind: kindNumber, help: help, metric: true,
+		num: func(i *model.Issue, c *Context) (float64, bool) { return value(c.Stats(), i.ID) }}
+}
+
+func optionalTime(t *time.Time) (time.Time, bool) {
+	if t == nil || t.IsZero() {
+		return time.Time{}, false
+	}
+	return *t, true
+}
+
+var fields = []*field{
+	{name: "id", kind: kindString, help: "issue ID; 'bv-1*' matches a prefix",
+		strs: func(i *model.Issue, _ *Context) []string { return one(i.ID) }},
+	{name: "status", kind: kindString, help: "open, in_progress, blocked, deferred, pinned, hooked, review, closed, tombstone",
+		values: setOf("open", "in_progress", "blocked", "deferred", "pinned", "hooked", "review", "closed", "tombstone"),
+		strs:   func(i *model.Issue, _ *Context) []string { return one(string(i.Status)) }},
+	{name: "is", kind: kindString, help: "open, closed, actionable (no open blockers), blocked (has open blockers), assigned, overdue",
+		values: setOf("open", "closed", "actionable", "blocked", "assigned", "overdue"),
+		strs:   issueStates},
+	{name: "priority", kind: kindNumber, help: "0 (critical) to 4; 'P1' also works",
+		num: func(i *model.Issue, _ *Context) (float64, bool) { return float64(i.Priority), true }},
+	{name: "type", kind: kindString, help: "bug, feature, task, epic, chore",
+		strs: func(i *model.Issue, _ *Context) []string { return one(string(i.IssueType)) }},
+	{name: "label", kind: kindString, help: "any of the issue's labels",
+		strs: func(i *model.Issue, _ *Context) []string { return i.Labels }},
+	{name: "assignee", kind: kindString, help: "assignee name",
+		strs: func(i *model.Issue, _ *Context) []string { return one(i.Assignee) }},
+	{name: "repo", kind: kindString, help: "source repo, or the ID prefix before '-', ':' or '_'",
+		strs: issueRepos},
+	{name: "title", kind: kindText, help: "substring of the title",
+		strs: func(i *model.Issue, _ *Context) []string { return one(i.Title) }},
+	{name: "desc", kind: kindText, help: "substring of the description",
+		strs: func(i *model.Issue, _ *Context) []string { return one(i.Description) }},
+	{name: "text", kind: kindText, help: "substring of the title, description, design, acceptance criteria or notes",
+		strs: func(i *model.Issue, _ *Context) []string {
+			return []string{i.Title, i.Description, i.Design, i.AcceptanceCriteria, i.Notes}
+		}},
+	{name: "parent", kind: kindString, help: "parent ID, or parent:(subquery)",
+		strs: func(i *model.Issue, c *Context) []string {
+			var ids []string
+			for _, dep := range i.Dependencies {
+				if dep != nil && dep.Type == model.DepParentChild {
+					ids = append(ids, dep.DependsOnID)
+				}
+			}
+			return ids
+		},
+		related: func(i *model.Issue, c *Context) []*model.Issue { return c.parents(i) }},
+	{name: "children", kind: kindNumber, help: "number of children, or children:(subquery)",
+		num:     func(i *model.Issue, c *Context) (float64, bool) { return count(c.children[i.ID]) },
+		related: func(i *model.Issue, c *Context) []*model.Issue { return c.children[i.ID] }},
+	{name: "blocks", kind: kindNumber, help: "number of open issues it blocks, or blocks:(subquery)",
+		num:     func(i *model.Issue, c *Context) (float64, bool) { return count(c.blocks[i.ID]) },
+		related: func(i *model.Issue, c *Context) []*model.Issue { return c.blocks[i.ID] }},
+	{name: "blockers", kind: kindNumber, help: "number of open blockers, or blockers:(subquery)",
+		num:     func(i *model.Issue, c *Context) (float64, bool) { return count(c.openBlockers(i)) },
+		related: func(i *model.Issue, c *Context) []*model.Issue { return c.openBlockers(i) }},
+	{name: "comments", kind: kindNumber, help: "number of comments",
+		num: func(i *model.Issue, _ *Context) (float64, bool) { return float64(len(i.Comments)), true }},
+	{name: "estimate", kind: kindNumber, help: "estimated minutes",
+		num: func(i *model.Issue, _ *Context) (float64, bool) {
+			if i.EstimatedMinutes == nil {
+				return 0, false
+			}
+			return float64(*i.EstimatedMinutes), true
+		}},
+	{name: "created", kind: kindTime, help: "creation time",
+		when: func(i *model.Issue) (time.Time, bool) { return optionalTime(&i.CreatedAt) }},
+	{name: "updated", kind: kindTime, help: "last update time",
+		when: func(i *model.Issue) (time.Time, bool) { return optionalTime(&i.UpdatedAt) }},
+	{name: "closed", kind: kindTime, help: "close time",
+		when: func(i *model.Issue) (time.Time, bool) { return optionalTime(i.ClosedAt) }},
+	{name: "due", kind: kindTime, help: "due date",
+		when: func(i *model.Issue) (time.Time, bool) { return optionalTime(i.DueDate) }},
+	metric("pagerank", "PageRank", (*analysis.GraphStats).PageRankValue),
+	metric("betweenness", "betweenness centrality", (*analysis.GraphStats).BetweennessValue),
+	metric("eigenvector", "eigenvector centrality", (*analysis.GraphStats).EigenvectorValue),
+	metric("hub", "HITS hub score", (*analysis.GraphStats).HubValue),
+	metric("authority", "HITS authority score", (*analysis.GraphStats).AuthorityValue),
+	metric("critical", "critical path depth", (*analysis.GraphStats).CriticalPathValue),
+	metric("slack", "longest-path slack", (*analysis.GraphStats).SlackValue),
+	metric("core", "k-core number", func(s *analysis.GraphStats, id string) (float64, bool) {
+		v, ok := s.CoreNumberValue(id)
+		return float64(v), ok
+	}),
+	metric("indegree", "issues depending on it", func(s *analysis.GraphStats, id string) (float64, bool) {
+		v, ok := s.InDegree[id]
+		return float64(v), ok
+	}),
+	metric("outdegree", "issues it depends on", func(s *analysis.GraphStats, id string) (float64, bool) {
+		v, ok := s.OutDegree[id]
+		return float64(v), ok
+	}),
+}
+
+var aliases = map[string]string{
+	"p":          "priority",
+	"tag":        "label",
+	"labels":     "label",
+	"state":      "status",
+	"kind":       "type",
+	"owner":      "assignee",
+	"blockedby":  "blockers",
+	"blocked_by": "blockers",
+	"pr":         "pagerank",
+}
+
+var fieldsByName = func() map[string]*field {
+	m := make(map[string]*field, len(fields))
+	for _, f := range fields {
+		m[f.name] = f
+	}
+	return m
+}()
+
+func lookupField(name string) (*field, bool) {
+	if canonical, ok := aliases[name]; ok {
+		name = canonical
+	}
+	f, ok := fieldsByName[name]
+	return f, ok
+}
+
+// FieldHelp describes a query field for help output.
+type FieldHelp struct {
+	Name        string   `json:"name"`
+	Kind        string   `json:"kind"` // string, text, number or time
+	Description string   `json:"description"`
+	Subquery    bool     `json:"subquery,omitempty"` // Accepts field:(subquery)
+	Aliases     []string `json:"aliases,omitempty"`
+}
+
+// Reference lists every query field in documentation order.
+func Reference() []FieldHelp {
+	byField := make(map[string][]string)
+	for alias, name := range aliases {
+		byField[name] = append(byField[name], alias)
+	}
+	kinds := map[valueKind]string{kindString: "string", kindText: "text", kindNumber: "number", kindTime: "time"}
+	help := make([]FieldHelp, 0, len(fields))
+	for _, f := range fields {
+		a := byField[f.name]
+		sort.Strings(a)
+		help = append(help, FieldHelp{
+			Name:        f.name,
+			Kind:        kinds[f.kind],
+			Description: f.help,
+			Subquery:    f.related != nil,
+			Aliases:     a,
+		})
+	}
+	return help
+}
+
+// issueStates lists the is: values that hold for an issue.
+func issueStates(i *model.Issue, c *Context) []string {
+	var states []string
+	if isClosed(i.Status) {
+		states = append(states, "closed")
+	} else {
+		states = append(states, "open")
+		if len(c.openBlockers(i)) > 0 {
+			states = append(states, "blocked")
+		} else {
+			states = append(states, "actionable")
+		}
+		if i.DueDate != nil && i.DueDate.Before(c.now()) {
+			states = append(states, "overdue")
+		}
+	}
+	if i.Assignee != "" {
+		states = append(states, "assigned")
+	}
+	return states
+}
+
+// issueRepos returns the source repo and the ID prefix, if any.
+func issueRepos(i *model.Issue, _ *Context) []string {
+	var repos []string
+	if i.SourceRepo != "" {
+		repos = append(repos, i.SourceRepo)
+	}
+	if idx := strings.IndexAny(i.ID, "-:_"); idx > 0 {
+		repos = append(repos, i.ID[:idx])
+	}
+	return repos
+}
@@ -0,0 +1,331 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ParseError reports where and why a query failed to parse.
+type ParseError struct {
+	Pos int // Byte offset into the query
+	Msg string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("query: col %d: %s", e.Pos+1, e.Msg)
+}
+
+type tokenKind int
+
+const (
+	tokWord tokenKind = iota
+	tokString
+	tokLParen
+	tokRParen
+	tokNot // A leading "-" before "(" or a quoted phrase
+	tokEOF
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// lex splits a query into words, quoted strings and parentheses. A word
+// runs until whitespace or a parenthesis; "field:(" therefore lexes as the
+// word "field:" followed by "(".
+func lex(src string) ([]token, error) {
+	var toks []token
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case unicode.IsSpace(rune(c)):
+			i++
+		case c == '(':
+			toks = append(toks, token{tokLParen, "(", i})
+			i++
+		case c == ')':
+			toks = append(toks, token{tokRParen, ")", i})
+			i++
+		case c == '"':
+			start := i
+			var sb strings.Builder
+			i++
+			for i < len(src) && src[i] != '"' {
+				if src[i] == '\\' && i+1 < len(src) {
+					i++
+				}
+				sb.WriteByte(src[i])
+				i++
+			}
+			if i >= len(src) {
+				return nil, &ParseError{start, "unterminated quoted string"}
+			}
+			i++
+			toks = append(toks, token{tokString, sb.String(), start})
+		case c == '-' && i+1 < len(src) && (src[i+1] == '(' || src[i+1] == '"'):
+			toks = append(toks, token{tokNot, "-", i})
+			i++
+		default:
+			start := i
+			for i < len(src) && !unicode.IsSpace(rune(src[i])) && src[i] != '(' && src[i] != ')' && src[i] != '"' {
+				i++
+			}
+			toks = append(toks, token{tokWord, src[start:i], start})
+		}
+	}
+	return append(toks, token{tokEOF, "", len(src)}), nil
+}
+
+// termPattern splits "field<op>value"; the value may be empty when a quoted
+// string or a parenthesized subquery follows.
+var termPattern = regexp.MustCompile(`^([A-Za-z_]+)(:|!=|<=|>=|<|>|=)(.*)$`)
+
+type parser struct {
+	toks []token
+	pos  int
+}
+
+// Parse parses a query. Terms separated by whitespace must all match; OR
+// (upper case) separates alternatives, and AND is accepted for clarity.
+// A leading "-" or NOT negates a term or a parenthesized group. Bare words
+// and quoted phrases match the title, description or ID. An empty query
+// matches every issue.
+func Parse(src string) (*Query, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	var root node = matchAll{}
+	if p.peek().kind != tokEOF {
+		if root, err = p.parseOr(); err != nil {
+			return nil, err
+		}
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, &ParseError{t.pos, fmt.Sprintf("unexpected %q", t.text)}
+	}
+	return &Query{src: src, root: root}, nil
+}
+
+func (p *parser) peek() token { return p.toks[p.pos] }
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	nodes := []node{left}
+	for t := p.peek(); t.kind == tokWord && t.text == "OR"; t = p.peek() {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, right)
+	}
+	if len(nodes) == 1 {
+		return left, nil
+	}
+	return orNode(nodes), nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	var nodes []node
+	for {
+		t := p.peek()
+		if t.kind == tokEOF || t.kind == tokRParen || (t.kind == tokWord && t.text == "OR") {
+			break
+		}
+		if t.kind == tokWord && t.text == "AND" {
+			p.next()
+			continue
+		}
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+	switch len(nodes) {
+	case 0:
+		return nil, &ParseError{p.peek().pos, "expected a term"}
+	case 1:
+		return nodes[0], nil
+	}
+	return andNode(nodes), nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNot:
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{n}, nil
+	case tokLParen:
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, &ParseError{t.pos, "unclosed parenthesis"}
+		}
+		return n, nil
+	case tokString:
+		return textNode(strings.ToLower(t.text)), nil
+	case tokWord:
+		if t.text == "NOT" {
+			n, err := p.parseUnary()
+			if err != nil {
+				return nil, err
+			}
+			return notNode{n}, nil
+		}
+		if strings.HasPrefix(t.text, "-") && len(t.text) > 1 {
+			n, err := p.parseWord(token{tokWord, t.text[1:], t.pos + 1})
+			if err != nil {
+				return nil, err
+			}
+			return notNode{n}, nil
+		}
+		return p.parseWord(t)
+	}
+	return nil, &ParseError{t.pos, fmt.Sprintf("unexpected %q", t.text)}
+}
+
+// parseWord turns a word into a field term, or a text match when it has no
+// operator.
+func (p *parser) parseWord(t token) (node, error) {
+	m := termPattern.FindStringSubmatch(t.text)
+	if m == nil {
+		return textNode(strings.ToLower(t.text)), nil
+	}
+	name, op, raw := strings.ToLower(m[1]), m[2], m[3]
+	f, ok := lookupField(name)
+	if !ok {
+		return nil, &ParseError{t.pos, fmt.Sprintf("unknown field %q", m[1])}
+	}
+	valuePos := t.pos + len(m[1]) + len(op)
+
+	if raw == "" {
+		switch next := p.peek(); {
+		case next.kind == tokLParen && f.related != nil:
+			if op != ":" && op != "=" && op != "!=" {
+				return nil, &ParseError{valuePos, fmt.Sprintf("%s%s( needs ':' or '!='", m[1], op)}
+			}
+			p.next()
+			sub, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if closing := p.next(); closing.kind != tokRParen {
+				return nil, &ParseError{next.pos, "unclosed parenthesis"}
+			}
+			var n node = relatedNode{field: f, sub: sub}
+			if op == "!=" {
+				n = notNode{n}
+			}
+			return n, nil
+		case next.kind == tokString:
+			raw = p.next().text
+		default:
+			return nil, &ParseError{valuePos, fmt.Sprintf("missing value for %s", m[1])}
+		}
+	}
+	return newTerm(f, op, raw, valuePos)
+}
+
+// newTerm validates a field comparison and pre-parses its values.
+func newTerm(f *field, op, raw string, pos int) (node, error) {
+	t := &termNode{field: f, op: op}
+	values := strings.Split(raw, ",")
+	ordered := op == "<" || op == "<=" || op == ">" || op == ">="
+	if ordered && len(values) > 1 {
+		return nil, &ParseError{pos, fmt.Sprintf("%s%s takes a single value", f.name, op)}
+	}
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			return nil, &ParseError{pos, fmt.Sprintf("empty value for %s", f.name)}
+		}
+		switch f.kind {
+		case kindString, kindText:
+			if ordered {
+				return nil, &ParseError{pos, fmt.Sprintf("%s does not support %s", f.name, op)}
+			}
+			v = strings.ToLower(v)
+			if f.values != nil && !f.values[v] {
+				return nil, &ParseError{pos, fmt.Sprintf("unknown %s %q", f.name, v)}
+			}
+			t.strs = append(t.strs, v)
+		case kindNumber:
+			n, err := strconv.ParseFloat(strings.TrimPrefix(strings.ToLower(v), "p"), 64)
+			if err != nil {
+				return nil, &ParseError{pos, fmt.Sprintf("%s expects a number, got %q", f.name, v)}
+			}
+			t.nums = append(t.nums, n)
+		case kindTime:
+			tv, err := parseTimeValue(v)
+			if err != nil {
+				return nil, &ParseError{pos, fmt.Sprintf("%s: %v", f.name, err)}
+			}
+			t.times = append(t.times, tv)
+		}
+		pos += len(v) + 1
+	}
+	return t, nil
+}
+
+// timeValue is either an age ("14d") or an absolute date. Plain dates
+// span the whole day.
+type timeValue struct {
+	raw  string
+	age  time.Duration
+	date time.Time
+	span time.Duration
+}
+
+func (v timeValue) String() string { return v.raw }
+
+var agePattern = regexp.MustCompile(`^(\d+)([hdwmy])$`)
+
+func parseTimeValue(s string) (timeValue, error) {
+	if m := agePattern.FindStringSubmatch(strings.ToLower(s)); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		unit := map[string]time.Duration{
+			"h": time.Hour,
+			"d": 24 * time.Hour,
+			"w": 7 * 24 * time.Hour,
+			"m": 30 * 24 * time.Hour,
+			"y": 365 * 24 * time.Hour,
+		}[m[2]]
+		return timeValue{raw: s, age: time.Duration(n) * unit}, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return timeValue{raw: s, date: t}, nil
+		}
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return timeValue{raw: s, date: t, span: 24 * time.Hour}, nil
+	}
+	return timeValue{}, fmt.Errorf("invalid time %q (expected an age like '14d', '2w', '3m' or a date like 2025-01-31)", s)
+}
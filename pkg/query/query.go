@@ -0,0 +1,444 @@
+// Package query implements the issue query language shared by the TUI
+// search prompt, recipes and --robot-query, for example:
+//
+//	status:open,in_progress priority<=1 type:bug label:api -label:wontfix
+//	blockers:(assignee:bob) pagerank>0.02 updated<14d "login timeout"
+//
+// A query is parsed into an AST once and evaluated against model.Issue,
+// the issue graph and, for metric fields, analysis.GraphStats.
+package query
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// Query is a parsed query.
+type Query struct {
+	src  string
+	root node
+}
+
+// Match reports whether issue satisfies the query.
+func (q *Query) Match(issue *model.Issue, ctx *Context) bool {
+	return q.root.match(issue, ctx)
+}
+
+// Filter returns the issues that satisfy the query, in their original order.
+func (q *Query) Filter(issues []model.Issue, ctx *Context) []model.Issue {
+	var result []model.Issue
+	for i := range issues {
+		if q.Match(&issues[i], ctx) {
+			result = append(result, issues[i])
+		}
+	}
+	return result
+}
+
+// Source returns the query as written.
+func (q *Query) Source() string { return q.src }
+
+// String returns the query in canonical form, with explicit grouping.
+func (q *Query) String() string { return q.root.String() }
+
+// Fields returns the sorted, distinct field names the query uses.
+func (q *Query) Fields() []string {
+	seen := make(map[string]bool)
+	q.root.walk(func(n node) {
+		switch n := n.(type) {
+		case *termNode:
+			seen[n.field.name] = true
+		case relatedNode:
+			seen[n.field.name] = true
+		}
+	})
+	fields := make([]string, 0, len(seen))
+	for name := range seen {
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// HasFields reports whether the query uses any field, as opposed to being
+// plain text.
+func (q *Query) HasFields() bool { return len(q.Fields()) > 0 }
+
+// UsesMetrics reports whether evaluating the query needs graph metrics.
+func (q *Query) UsesMetrics() bool {
+	for _, name := range q.Fields() {
+		if f, _ := lookupField(name); f.metric {
+			return true
+		}
+	}
+	return false
+}
+
+// Context holds what queries are evaluated against besides the issue
+// itself: the other issues (for blocker and parent relations) and graph
+// metrics. It is safe for concurrent use.
+type Context struct {
+	Now time.Time // Reference time for ages; zero means time.Now()
+
+	// NoWait evaluates metric fields against the metrics computed so far
+	// instead of waiting for phase 2; metrics still pending match nothing.
+	// Set it before the first evaluation.
+	NoWait bool
+
+	issues   []model.Issue
+	byID     map[string]*model.Issue
+	blocks   map[string][]*model.Issue // Blocker ID -> open issues it blocks
+	children map[string][]*model.Issue
+
+	stats     *analysis.GraphStats
+	statsOnce sync.Once
+}
+
+// NewContext indexes issues for evaluation. stats may be nil, in which case
+// it is computed on first use by a metric field.
+func NewContext(issues []model.Issue, stats *analysis.GraphStats) *Context {
+	c := &Context{
+		issues:   issues,
+		byID:     make(map[string]*model.Issue, len(issues)),
+		blocks:   make(map[string][]*model.Issue),
+		children: make(map[string][]*model.Issue),
+		stats:    stats,
+	}
+	for i := range issues {
+		c.byID[issues[i].ID] = &issues[i]
+	}
+	for i := range issues {
+		iss := &issues[i]
+		for _, dep := range iss.Dependencies {
+			if dep == nil {
+				continue
+			}
+			switch {
+			case dep.Type.IsBlocking() && !isClosed(iss.Status):
+				c.blocks[dep.DependsOnID] = append(c.blocks[dep.DependsOnID], iss)
+			case dep.Type == model.DepParentChild:
+				c.children[dep.DependsOnID] = append(c.children[dep.DependsOnID], iss)
+			}
+		}
+	}
+	return c
+}
+
+// Stats returns the graph metrics, computing them if none were given and,
+// unless NoWait is set, waiting for phase 2 metrics if they are still being
+// computed.
+func (c *Context) Stats() *analysis.GraphStats {
+	c.statsOnce.Do(func() {
+		if c.stats == nil {
+			c.stats = analysis.NewAnalyzer(c.issues).AnalyzeAsync(context.Background())
+		}
+		if !c.NoWait {
+			c.stats.WaitForPhase2()
+		}
+	})
+	return c.stats
+}
+
+func (c *Context) now() time.Time {
+	if c.Now.IsZero() {
+		return time.Now()
+	}
+	return c.Now
+}
+
+// openBlockers returns the open issues that block issue.
+func (c *Context) openBlockers(issue *model.Issue) []*model.Issue {
+	var blockers []*model.Issue
+	for _, dep := range issue.Dependencies {
+		if dep == nil || !dep.Type.IsBlocking() {
+			continue
+		}
+		if b, ok := c.byID[dep.DependsOnID]; ok && !isClosed(b.Status) {
+			blockers = append(blockers, b)
+		}
+	}
+	return blockers
+}
+
+func (c *Context) parents(issue *model.Issue) []*model.Issue {
+	var parents []*model.Issue
+	for _, dep := range issue.Dependencies {
+		if dep != nil && dep.Type == model.DepParentChild {
+			if p, ok := c.byID[dep.DependsOnID]; ok {
+				parents = append(parents, p)
+			}
+		}
+	}
+	return parents
+}
+
+func isClosed(s model.Status) bool {
+	return s == model.StatusClosed || s == model.StatusTombstone
+}
+
+// node is an element of the query AST.
+type node interface {
+	match(issue *model.Issue, ctx *Context) bool
+	walk(fn func(node))
+	String() string
+}
+
+type matchAll struct{}
+
+func (matchAll) match(*model.Issue, *Context) bool { return true }
+func (n matchAll) walk(fn func(node))              { fn(n) }
+func (matchAll) String() string                    { return "" }
+
+type andNode []node
+
+func (n andNode) match(issue *model.Issue, ctx *Context) bool {
+	for _, c := range n {
+		if !c.match(issue, ctx) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n andNode) walk(fn func(node)) {
+	fn(n)
+	for _, c := range n {
+		c.walk(fn)
+	}
+}
+
+func (n andNode) String() string {
+	parts := make([]string, len(n))
+	for i, c := range n {
+		parts[i] = c.String()
+		if _, ok := c.(orNode); ok {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+type orNode []node
+
+func (n orNode) match(issue *model.Issue, ctx *Context) bool {
+	for _, c := range n {
+		if c.match(issue, ctx) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n orNode) walk(fn func(node)) {
+	fn(n)
+	for _, c := range n {
+		c.walk(fn)
+	}
+}
+
+func (n orNode) String() string {
+	parts := make([]string, len(n))
+	for i, c := range n {
+		parts[i] = c.String()
+	}
+	return strings.Join(parts, " OR ")
+}
+
+type notNode struct{ n node }
+
+func (n notNode) match(issue *model.Issue, ctx *Context) bool { return !n.n.match(issue, ctx) }
+
+func (n notNode) walk(fn func(node)) {
+	fn(n)
+	n.n.walk(fn)
+}
+
+func (n notNode) String() string {
+	switch n.n.(type) {
+	case andNode, orNode:
+		return "-(" + n.n.String() + ")"
+	}
+	return "-" + n.n.String()
+}
+
+// textNode matches a lower-cased substring of the title, description or ID.
+type textNode string
+
+func (n textNode) match(issue *model.Issue, _ *Context) bool {
+	s := string(n)
+	return strings.Contains(strings.ToLower(issue.Title), s) ||
+		strings.Contains(strings.ToLower(issue.Description), s) ||
+		strings.Contains(strings.ToLower(issue.ID), s)
+}
+
+func (n textNode) walk(fn func(node)) { fn(n) }
+func (n textNode) String() string     { return quote(string(n)) }
+
+// relatedNode matches issues with at least one related issue (a blocker,
+// a parent, ...) that matches a subquery.
+type relatedNode struct {
+	field *field
+	sub   node
+}
+
+func (n relatedNode) match(issue *model.Issue, ctx *Context) bool {
+	for _, r := range n.field.related(issue, ctx) {
+		if n.sub.match(r, ctx) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n relatedNode) walk(fn func(node)) {
+	fn(n)
+	n.sub.walk(fn)
+}
+
+func (n relatedNode) String() string { return n.field.name + ":(" + n.sub.String() + ")" }
+
+// termNode compares one field against one or more values; several values
+// after ':' or '=' match if any does, after '!=' if none does.
+type termNode struct {
+	field *field
+	op    string
+	strs  []string
+	nums  []float64
+	times []timeValue
+}
+
+func (n *termNode) match(issue *model.Issue, ctx *Context) bool {
+	hit := false
+	switch n.field.kind {
+	case kindString, kindText:
+		have := n.field.strs(issue, ctx)
+		for _, want := range n.strs {
+			for _, h := range have {
+				if matchString(n.field.kind, strings.ToLower(h), want) {
+					hit = true
+				}
+			}
+		}
+	case kindNumber:
+		v, ok := n.field.num(issue, ctx)
+		if !ok {
+			return false
+		}
+		for _, want := range n.nums {
+			if compareNumber(n.op, v, want) {
+				hit = true
+			}
+		}
+	case kindTime:
+		t, ok := n.field.when(issue)
+		if !ok {
+			return false
+		}
+		for _, want := range n.times {
+			if compareTime(n.op, t, want, ctx.now()) {
+				hit = true
+			}
+		}
+	}
+	if n.op == "!=" {
+		return !hit
+	}
+	return hit
+}
+
+func (n *termNode) walk(fn func(node)) { fn(n) }
+
+func (n *termNode) String() string {
+	var values []string
+	switch n.field.kind {
+	case kindString, kindText:
+		for _, s := range n.strs {
+			values = append(values, quote(s))
+		}
+	case kindNumber:
+		for _, v := range n.nums {
+			values = append(values, strconv.FormatFloat(v, 'g', -1, 64))
+		}
+	case kindTime:
+		for _, t := range n.times {
+			values = append(values, t.String())
+		}
+	}
+	return n.field.name + n.op + strings.Join(values, ",")
+}
+
+// matchString compares a value: text fields match substrings, other fields
+// whole values, with a trailing '*' matching any suffix.
+func matchString(kind valueKind, have, want string) bool {
+	if kind == kindText {
+		return strings.Contains(have, want)
+	}
+	if prefix, ok := strings.CutSuffix(want, "*"); ok {
+		return strings.HasPrefix(have, prefix)
+	}
+	return have == want
+}
+
+func compareNumber(op string, v, want float64) bool {
+	switch op {
+	case "<":
+		return v < want
+	case "<=":
+		return v <= want
+	case ">":
+		return v > want
+	case ">=":
+		return v >= want
+	}
+	return v == want
+}
+
+// compareTime compares an age against an age value, so "updated<14d" means
+// updated less than 14 days ago, and ':' means within. A date value is
+// compared as a point in time, or as a whole day for plain dates.
+func compareTime(op string, t time.Time, want timeValue, now time.Time) bool {
+	if want.date.IsZero() {
+		age := now.Sub(t)
+		switch op {
+		case "<":
+			return age < want.age
+		case "<=":
+			return age <= want.age
+		case ">":
+			return age > want.age
+		case ">=":
+			return age >= want.age
+		}
+		return age <= want.age
+	}
+	start, end := want.date, want.date.Add(want.span)
+	switch op {
+	case "<":
+		return t.Before(start)
+	case "<=":
+		return t.Before(end) || t.Equal(start)
+	case ">":
+		return !t.Before(end) && !t.Equal(start)
+	case ">=":
+		return !t.Before(start)
+	}
+	if want.span == 0 {
+		return t.Equal(start)
+	}
+	return !t.Before(start) && t.Before(end)
+}
+
+// quote wraps values containing spaces or syntax in double quotes.
+func quote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\"(),:<>=") && !strings.HasPrefix(s, "-") {
+		return s
+	}
+	return strconv.Quote(s)
+}
@@ -0,0 +1,165 @@
+package query
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func deps(id string, blockers ...string) []*model.Dependency {
+	var d []*model.Dependency
+	for _, b := range blockers {
+		d = append(d, &model.Dependency{IssueID: id, DependsOnID: b, Type: model.DepBlocks})
+	}
+	return d
+}
+
+func testIssues(now time.Time) []model.Issue {
+	est := 90
+	return []model.Issue{
+		{ID: "bv-1", Title: "Login timeout on slow networks", Status: model.StatusOpen, Priority: 0, IssueType: model.TypeBug,
+			Labels: []string{"api", "auth"}, UpdatedAt: now.AddDate(0, 0, -2), CreatedAt: now.AddDate(0, -2, 0),
+			Dependencies: deps("bv-1", "bv-3")},
+		{ID: "bv-2", Title: "Refactor session store", Status: model.StatusInProgress, Priority: 1, IssueType: model.TypeBug,
+			Labels: []string{"api", "wontfix"}, Assignee: "alice", UpdatedAt: now.AddDate(0, 0, -30), CreatedAt: now.AddDate(0, -1, 0),
+			Dependencies: append(deps("bv-2", "bv-3"), &model.Dependency{IssueID: "bv-2", DependsOnID: "bv-5", Type: model.DepParentChild})},
+		{ID: "bv-3", Title: "Rate limiter", Status: model.StatusOpen, Priority: 2, IssueType: model.TypeTask,
+			Assignee: "bob", UpdatedAt: now.AddDate(0, 0, -1), CreatedAt: now.AddDate(0, 0, -20), EstimatedMinutes: &est},
+		{ID: "bv-4", Title: "Old bug", Status: model.StatusClosed, Priority: 1, IssueType: model.TypeBug,
+			Labels: []string{"api"}, UpdatedAt: now.AddDate(0, -3, 0), CreatedAt: now.AddDate(-1, 0, 0),
+			Description: "Users saw a login timeout"},
+		{ID: "bv-5", Title: "Sessions epic", Status: model.StatusOpen, Priority: 1, IssueType: model.TypeEpic,
+			CreatedAt: time.Date(2025, 1, 31, 15, 0, 0, 0, time.Local)},
+	}
+}
+
+func ids(issues []model.Issue) string {
+	var out []string
+	for _, iss := range issues {
+		out = append(out, iss.ID)
+	}
+	return strings.Join(out, ",")
+}
+
+func TestQueryMatch(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.Local)
+	issues := testIssues(now)
+	ctx := NewContext(issues, nil)
+	ctx.Now = now
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"", "bv-1,bv-2,bv-3,bv-4,bv-5"},
+		{"status:open,in_progress priority<=1 type:bug label:api -label:wontfix", "bv-1"},
+		{"blockers:(assignee:bob)", "bv-1,bv-2"},
+		{"blocks>1", "bv-3"},
+		{"blocks:2 is:actionable", "bv-3"},
+		{"is:blocked", "bv-1,bv-2"},
+		{"updated<14d", "bv-1,bv-3"},
+		{"updated>=30d", "bv-2,bv-4"},
+		{`"login timeout"`, "bv-1,bv-4"},
+		{`title:"login timeout"`, "bv-1"},
+		{"login -is:closed", "bv-1"},
+		{"type:epic OR assignee:alice", "bv-2,bv-5"},
+		{"(p:0 OR p:2) AND NOT label:auth", "bv-3"},
+		{"-(is:closed OR type:epic) p>0", "bv-2,bv-3"},
+		{"parent:bv-5", "bv-2"},
+		{"children:(is:blocked)", "bv-5"},
+		{"id:bv-1*", "bv-1"},
+		{"repo:bv estimate>=60", "bv-3"},
+		{"assignee!=alice,bob is:open", "bv-1,bv-5"},
+		{"created:2025-01-31", "bv-5"},
+		{"created<2025-01-31", "bv-4"},
+		{"label:AP*", "bv-1,bv-2,bv-4"},
+		{"indegree>=2", "bv-3"},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.query)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.query, err)
+			continue
+		}
+		if got := ids(q.Filter(issues, ctx)); got != tt.want {
+			t.Errorf("%q (%s) matched %s, want %s", tt.query, q, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := map[string]string{
+		"colour:red":     `unknown field "colour"`,
+		"priority:high":  "priority expects a number",
+		"status:doing":   `unknown status "doing"`,
+		"label>api":      "label does not support >",
+		"priority<1,2":   "takes a single value",
+		"updated<soon":   "invalid time",
+		`"unterminated`:  "unterminated quoted string",
+		"(status:open":   "unclosed parenthesis",
+		"status:open)":   `unexpected ")"`,
+		"blockers<(p:0)": "needs ':' or '!='",
+		"status:":        "missing value for status",
+		"status:open OR": "expected a term",
+	}
+	for src, want := range tests {
+		_, err := Parse(src)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Parse(%q) error = %v, want %q", src, err, want)
+		}
+	}
+}
+
+func TestQueryString(t *testing.T) {
+	tests := map[string]string{
+		`status:Open,closed p<=1 "login timeout"`: `status:open,closed priority<=1 "login timeout"`,
+		"a OR b c": "a OR b c",
+		"-(type:bug OR tag:api) blockedby:(owner:bob)": "-(type:bug OR label:api) blockers:(assignee:bob)",
+		"x (y OR z)": "x (y OR z)",
+	}
+	for src, want := range tests {
+		q, err := Parse(src)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", src, err)
+		}
+		if q.String() != want {
+			t.Errorf("String(%q) = %q, want %q", src, q.String(), want)
+		}
+		if _, err := Parse(q.String()); err != nil {
+			t.Errorf("canonical form %q does not re-parse: %v", q.String(), err)
+		}
+	}
+
+	q, _ := Parse("login pagerank>0.1 status:open")
+	if !q.HasFields() || !q.UsesMetrics() || strings.Join(q.Fields(), ",") != "pagerank,status" {
+		t.Errorf("fields = %v, metrics = %v", q.Fields(), q.UsesMetrics())
+	}
+	if q, _ := Parse("login timeout"); q.HasFields() {
+		t.Error("plain text should have no fields")
+	}
+}
+
+func TestContextNoWaitSkipsPendingMetrics(t *testing.T) {
+	issues := testIssues(time.Now())
+	stats := analysis.NewPendingGraphStatsForTest(map[string]int{"bv-1": 1}, map[string]int{"bv-3": 2})
+	ctx := NewContext(issues, stats)
+	ctx.NoWait = true
+
+	done := make(chan string)
+	go func() {
+		degree, _ := Parse("indegree>=2")
+		rank, _ := Parse("pagerank>=0")
+		done <- ids(degree.Filter(issues, ctx)) + "|" + ids(rank.Filter(issues, ctx))
+	}()
+	select {
+	case got := <-done:
+		if got != "bv-3|" {
+			t.Errorf("got %q, want phase 1 matches only", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("NoWait context blocked on phase 2")
+	}
+}
@@ -151,6 +151,10 @@ func (l *Loader) loadFromFile(path, source string) error {
 			continue
 		}
 		recipe.Name = name
+		if _, err := recipe.ParsedQuery(); err != nil {
+			l.warnings = append(l.warnings, fmt.Sprintf("%s recipe %q skipped: %v", source, name, err))
+			continue
+		}
 		l.recipes[name] = *recipe
 		l.sources[name] = source
 	}
@@ -284,6 +284,51 @@ func TestLoaderInvalidYAML(t *testing.T) {
 	}
 }
 
+func TestLoaderQuery(t *testing.T) {
+	tmpDir := t.TempDir()
+	userPath := filepath.Join(tmpDir, "recipes.yaml")
+
+	userConfig := `recipes:
+  hot-bugs:
+    description: "Urgent bugs"
+    query: 'type:bug priority<=1 -label:wontfix'
+  broken:
+    description: "Bad query"
+    query: 'priority<=high'
+`
+	if err := os.WriteFile(userPath, []byte(userConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loader := recipe.NewLoader(
+		recipe.WithUserPath(userPath),
+		recipe.WithProjectDir(""),
+	)
+	if err := loader.Load(); err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+
+	r := loader.Get("hot-bugs")
+	if r == nil {
+		t.Fatal("Expected hot-bugs recipe")
+	}
+	q, err := r.ParsedQuery()
+	if err != nil || q == nil {
+		t.Fatalf("ParsedQuery() = %v, %v", q, err)
+	}
+	if got := q.String(); got != "type:bug priority<=1 -label:wontfix" {
+		t.Errorf("Parsed query = %q", got)
+	}
+
+	// Recipes with invalid queries are skipped with a warning
+	if loader.Get("broken") != nil {
+		t.Error("Expected recipe with invalid query to be skipped")
+	}
+	if len(loader.Warnings()) == 0 {
+		t.Error("Expected warning for invalid query")
+	}
+}
+
 func TestLoadDefault(t *testing.T) {
 	loader, err := recipe.LoadDefault()
 	if err != nil {
@@ -5,6 +5,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/query"
 )
 
 // Recipe defines a reusable view configuration for beads
@@ -12,12 +14,21 @@ type Recipe struct {
 	Name        string       `yaml:"name" json:"name"`
 	Description string       `yaml:"description,omitempty" json:"description,omitempty"`
 	Filters     FilterConfig `yaml:"filters,omitempty" json:"filters,omitempty"`
+	Query       string       `yaml:"query,omitempty" json:"query,omitempty"` // Query language filter, applied with Filters
 	Sort        SortConfig   `yaml:"sort,omitempty" json:"sort,omitempty"`
 	View        ViewConfig   `yaml:"view,omitempty" json:"view,omitempty"`
 	Export      ExportConfig `yaml:"export,omitempty" json:"export,omitempty"`
 	Metrics     []string     `yaml:"metrics,omitempty" json:"metrics,omitempty"` // Which metrics to show
 }
 
+// ParsedQuery parses the recipe's query, returning nil if it has none.
+func (r *Recipe) ParsedQuery() (*query.Query, error) {
+	if r == nil || strings.TrimSpace(r.Query) == "" {
+		return nil, nil
+	}
+	return query.Parse(r.Query)
+}
+
 // FilterConfig defines which issues to include
 type FilterConfig struct {
 	Status        []string `yaml:"status,omitempty" json:"status,omitempty"`                 // open, closed, in_progress, blocked
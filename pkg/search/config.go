@@ -13,12 +13,20 @@ import (
 // Supported variables:
 //   - BV_SEMANTIC_EMBEDDER: embedding provider (default: "hash")
 //   - BV_SEMANTIC_MODEL: model identifier (provider-specific, optional)
-//   - BV_SEMANTIC_DIM: embedding dimension (default: DefaultEmbeddingDim)
+//   - BV_SEMANTIC_DIM: embedding dimension (default: the model's native size, else DefaultEmbeddingDim)
+//   - BV_SEMANTIC_URL: base URL of the embedding server (openai/ollama providers)
+//   - BV_SEMANTIC_API_KEY: bearer token for the embedding server; the openai
+//     provider falls back to OPENAI_API_KEY when no URL is set
 func EmbeddingConfigFromEnv() EmbeddingConfig {
 	provider := strings.ToLower(strings.TrimSpace(os.Getenv(EnvSemanticEmbedder)))
 	cfg := EmbeddingConfig{
 		Provider: Provider(provider),
 		Model:    strings.TrimSpace(os.Getenv(EnvSemanticModel)),
+		BaseURL:  strings.TrimSpace(os.Getenv(EnvSemanticURL)),
+		APIKey:   strings.TrimSpace(os.Getenv(EnvSemanticAPIKey)),
+	}
+	if cfg.APIKey == "" && cfg.BaseURL == "" && cfg.Provider == ProviderOpenAI {
+		cfg.APIKey = strings.TrimSpace(os.Getenv("OPENAI_API_KEY"))
 	}
 	if dimStr := os.Getenv(EnvSemanticDim); dimStr != "" {
 		if dim, err := strconv.Atoi(dimStr); err == nil {
@@ -31,6 +39,29 @@ func EmbeddingConfigFromEnv() EmbeddingConfig {
 	return cfg.Normalized()
 }
 
+// HNSWConfigFromEnv reads ANN index tuning from environment variables,
+// falling back to DefaultHNSWConfig for unset or invalid values.
+//
+// Supported variables:
+//   - BV_SEMANTIC_HNSW_M: graph links per node (default 16)
+//   - BV_SEMANTIC_HNSW_EF_CONSTRUCTION: insert candidate list size (default 100)
+//   - BV_SEMANTIC_HNSW_EF_SEARCH: query candidate list size (default 64)
+//   - BV_SEMANTIC_HNSW_MIN_SIZE: index size below which search is exact (default 5000)
+func HNSWConfigFromEnv() HNSWConfig {
+	var cfg HNSWConfig
+	for env, dst := range map[string]*int{
+		EnvHNSWM:              &cfg.M,
+		EnvHNSWEfConstruction: &cfg.EfConstruction,
+		EnvHNSWEfSearch:       &cfg.EfSearch,
+		EnvHNSWMinSize:        &cfg.MinSize,
+	} {
+		if v, err := strconv.Atoi(strings.TrimSpace(os.Getenv(env))); err == nil {
+			*dst = v
+		}
+	}
+	return cfg.Normalized()
+}
+
 // NewEmbedderFromConfig constructs an Embedder for the given configuration.
 func NewEmbedderFromConfig(cfg EmbeddingConfig) (Embedder, error) {
 	cfg = cfg.Normalized()
@@ -39,10 +70,10 @@ func NewEmbedderFromConfig(cfg EmbeddingConfig) (Embedder, error) {
 		return NewHashEmbedder(cfg.Dim), nil
 	case ProviderPythonSentenceTransformers:
 		return nil, fmt.Errorf("semantic embedder %q not implemented (mvp placeholder); set %s=%q for deterministic fallback", cfg.Provider, EnvSemanticEmbedder, ProviderHash)
-	case ProviderOpenAI:
-		return nil, fmt.Errorf("semantic embedder %q not implemented (placeholder); set %s=%q for deterministic fallback", cfg.Provider, EnvSemanticEmbedder, ProviderHash)
+	case ProviderOpenAI, ProviderOllama:
+		return NewHTTPEmbedder(cfg)
 	default:
-		return nil, fmt.Errorf("unknown semantic embedder %q; expected %q", cfg.Provider, ProviderHash)
+		return nil, fmt.Errorf("unknown semantic embedder %q; expected %q, %q or %q", cfg.Provider, ProviderHash, ProviderOpenAI, ProviderOllama)
 	}
 }
 
@@ -52,12 +83,21 @@ type SearchMode string
 const (
 	SearchModeText   SearchMode = "text"
 	SearchModeHybrid SearchMode = "hybrid"
+	// SearchModeFusion ranks like hybrid, but its text relevance fuses BM25
+	// lexical ranks with vector similarity ranks via reciprocal rank fusion.
+	SearchModeFusion SearchMode = "fusion"
 )
 
+// UsesMetrics reports whether the mode blends graph metrics into scores.
+func (m SearchMode) UsesMetrics() bool {
+	return m == SearchModeHybrid || m == SearchModeFusion
+}
+
 const (
 	EnvSearchMode    = "BV_SEARCH_MODE"
 	EnvSearchPreset  = "BV_SEARCH_PRESET"
 	EnvSearchWeights = "BV_SEARCH_WEIGHTS"
+	EnvSearchKinds   = "BV_SEARCH_KINDS"
 )
 
 // SearchConfig captures hybrid search configuration from env or flags.
@@ -66,22 +106,24 @@ type SearchConfig struct {
 	Preset     PresetName
 	Weights    Weights
 	HasWeights bool
+	Kinds      []DocKind
 }
 
 // SearchConfigFromEnv reads hybrid search configuration from environment variables.
-// Defaults: mode=text, preset=default.
+// Defaults: mode=text, preset=default, kinds=issue.
 func SearchConfigFromEnv() (SearchConfig, error) {
 	cfg := SearchConfig{
 		Mode:   SearchModeText,
 		Preset: PresetDefault,
+		Kinds:  []DocKind{KindIssue},
 	}
 
 	if mode := strings.TrimSpace(os.Getenv(EnvSearchMode)); mode != "" {
 		switch SearchMode(strings.ToLower(mode)) {
-		case SearchModeText, SearchModeHybrid:
+		case SearchModeText, SearchModeHybrid, SearchModeFusion:
 			cfg.Mode = SearchMode(strings.ToLower(mode))
 		default:
-			return SearchConfig{}, fmt.Errorf("invalid %s: %q (expected text|hybrid)", EnvSearchMode, mode)
+			return SearchConfig{}, fmt.Errorf("invalid %s: %q (expected text|hybrid|fusion)", EnvSearchMode, mode)
 		}
 	}
 
@@ -102,6 +144,14 @@ func SearchConfigFromEnv() (SearchConfig, error) {
 		cfg.HasWeights = true
 	}
 
+	if raw := strings.TrimSpace(os.Getenv(EnvSearchKinds)); raw != "" {
+		kinds, err := ParseDocKinds(raw)
+		if err != nil {
+			return SearchConfig{}, fmt.Errorf("invalid %s: %w", EnvSearchKinds, err)
+		}
+		cfg.Kinds = kinds
+	}
+
 	return cfg, nil
 }
 
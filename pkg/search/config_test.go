@@ -342,10 +342,33 @@ func TestNewEmbedderFromConfig(t *testing.T) {
 			errContains: "not implemented",
 		},
 		{
-			name:        "openai not implemented",
-			cfg:         EmbeddingConfig{Provider: ProviderOpenAI, Dim: 1536},
+			name:    "openai provider uses HTTP embedder",
+			cfg:     EmbeddingConfig{Provider: ProviderOpenAI},
+			wantErr: false,
+			checkEmbed: func(t *testing.T, e Embedder) {
+				if e.Provider() != ProviderOpenAI {
+					t.Errorf("Provider() = %q, want %q", e.Provider(), ProviderOpenAI)
+				}
+				if e.Dim() != 1536 {
+					t.Errorf("Dim() = %d, want 1536 (text-embedding-3-small)", e.Dim())
+				}
+			},
+		},
+		{
+			name:    "ollama provider uses HTTP embedder",
+			cfg:     EmbeddingConfig{Provider: ProviderOllama, Model: "all-minilm"},
+			wantErr: false,
+			checkEmbed: func(t *testing.T, e Embedder) {
+				if e.Dim() != 384 {
+					t.Errorf("Dim() = %d, want 384 (all-minilm)", e.Dim())
+				}
+			},
+		},
+		{
+			name:        "invalid base URL",
+			cfg:         EmbeddingConfig{Provider: ProviderOllama, BaseURL: "localhost:11434"},
 			wantErr:     true,
-			errContains: "not implemented",
+			errContains: EnvSemanticURL,
 		},
 		{
 			name:        "unknown provider error",
@@ -355,7 +378,7 @@ func TestNewEmbedderFromConfig(t *testing.T) {
 		},
 		{
 			name:        "error message suggests hash fallback",
-			cfg:         EmbeddingConfig{Provider: ProviderOpenAI},
+			cfg:         EmbeddingConfig{Provider: ProviderPythonSentenceTransformers},
 			wantErr:     true,
 			errContains: ProviderHash.String(),
 		},
@@ -1,6 +1,10 @@
 package search
 
-import "context"
+import (
+	"context"
+	"strings"
+	"time"
+)
 
 // Provider identifies an embedding backend.
 type Provider string
@@ -14,8 +18,12 @@ const (
 	// sentence-transformers to generate high-quality embeddings (MVP choice for bv-9gf).
 	ProviderPythonSentenceTransformers Provider = "python-sentence-transformers"
 
-	// ProviderOpenAI uses a hosted embedding API.
+	// ProviderOpenAI uses an OpenAI-compatible /v1/embeddings endpoint: the
+	// hosted API by default, or any self-hosted server via BaseURL.
 	ProviderOpenAI Provider = "openai"
+
+	// ProviderOllama uses an Ollama-style /api/embeddings endpoint.
+	ProviderOllama Provider = "ollama"
 )
 
 const DefaultEmbeddingDim = 384
@@ -24,26 +32,84 @@ const (
 	EnvSemanticEmbedder = "BV_SEMANTIC_EMBEDDER"
 	EnvSemanticModel    = "BV_SEMANTIC_MODEL"
 	EnvSemanticDim      = "BV_SEMANTIC_DIM"
+	EnvSemanticURL      = "BV_SEMANTIC_URL"
+	EnvSemanticAPIKey   = "BV_SEMANTIC_API_KEY"
 )
 
 // EmbeddingConfig captures embedder selection/configuration.
 // Provider implementations may ignore fields they don't use.
 type EmbeddingConfig struct {
-	Provider Provider
-	Model    string
-	Dim      int
+	Provider  Provider
+	Model     string
+	Dim       int
+	BaseURL   string // HTTP providers: server base URL (default per provider)
+	APIKey    string // HTTP providers: sent as a bearer token when set
+	BatchSize int    // HTTP providers: texts per request (default 64)
 }
 
+// defaultModels are used by HTTP providers when no model is configured.
+var defaultModels = map[Provider]string{
+	ProviderOpenAI: "text-embedding-3-small",
+	ProviderOllama: "nomic-embed-text",
+}
+
+// knownModelDims are the native output sizes of common embedding models, so
+// the index dimension need not be configured by hand.
+var knownModelDims = map[string]int{
+	"text-embedding-3-small": 1536,
+	"text-embedding-3-large": 3072,
+	"text-embedding-ada-002": 1536,
+	"nomic-embed-text":       768,
+	"mxbai-embed-large":      1024,
+	"all-minilm":             384,
+	"all-MiniLM-L6-v2":       384,
+	"bge-m3":                 1024,
+	"snowflake-arctic-embed": 1024,
+}
+
+// Normalized fills in defaults: the provider's default model for HTTP
+// providers, and the model's native dimension (or DefaultEmbeddingDim).
 func (c EmbeddingConfig) Normalized() EmbeddingConfig {
+	if c.Model == "" {
+		c.Model = defaultModels[c.Provider]
+	}
+	if c.Dim <= 0 {
+		c.Dim = knownModelDims[strings.TrimSuffix(c.Model, ":latest")]
+	}
 	if c.Dim <= 0 {
 		c.Dim = DefaultEmbeddingDim
 	}
 	return c
 }
 
+// SyncTimeout bounds building or refreshing an index. Hashing is local and
+// fast; an HTTP embedder may have to embed the whole backlog on first use.
+func (c EmbeddingConfig) SyncTimeout() time.Duration {
+	switch c.Provider {
+	case ProviderOpenAI, ProviderOllama:
+		return 10 * time.Minute
+	}
+	return 30 * time.Second
+}
+
 // Embedder produces fixed-size dense vectors for text inputs.
 type Embedder interface {
 	Provider() Provider
 	Dim() int
 	Embed(ctx context.Context, texts []string) ([][]float32, error)
 }
+
+// ModelNamer is implemented by embedders backed by a named model. Vectors
+// from different models are not comparable, so the vector index records the
+// name and re-embeds everything when it changes.
+type ModelNamer interface {
+	Model() string
+}
+
+// EmbedderModel returns the model behind e, or "" if it has none.
+func EmbedderModel(e Embedder) string {
+	if m, ok := e.(ModelNamer); ok {
+		return m.Model()
+	}
+	return ""
+}
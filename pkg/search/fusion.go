@@ -0,0 +1,33 @@
+package search
+
+// DefaultRRFK is the rank constant commonly used for reciprocal rank fusion;
+// larger values flatten the difference between top and lower ranks.
+const DefaultRRFK = 60
+
+// FuseRRF merges ranked result lists with reciprocal rank fusion: each
+// document scores the sum of 1/(k+rank) over the lists it appears in. Scores
+// are divided by the best achievable sum, so a document ranked first in
+// every list scores 1. Only ranks matter; the input scores are ignored,
+// which lets BM25 and cosine similarity be combined without calibration.
+func FuseRRF(k int, rankings ...[]SearchResult) []SearchResult {
+	if k <= 0 {
+		k = DefaultRRFK
+	}
+	if len(rankings) == 0 {
+		return nil
+	}
+	scores := make(map[string]float64)
+	for _, ranking := range rankings {
+		for rank, r := range ranking {
+			scores[r.IssueID] += 1 / float64(k+rank+1)
+		}
+	}
+	best := float64(len(rankings)) / float64(k+1)
+
+	fused := make([]SearchResult, 0, len(scores))
+	for id, score := range scores {
+		fused = append(fused, SearchResult{IssueID: id, Score: score / best})
+	}
+	sortSearchResults(fused)
+	return fused
+}
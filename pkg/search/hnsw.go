@@ -0,0 +1,541 @@
+package search
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+const (
+	hnswMagic   = "HNSW"
+	hnswVersion = uint16(1)
+	hnswSeed    = 42
+
+	// Tombstones are kept until they pass this count and outnumber the
+	// live nodes.
+	hnswTombstoneSlack = 64
+)
+
+const (
+	EnvHNSWM              = "BV_SEMANTIC_HNSW_M"
+	EnvHNSWEfConstruction = "BV_SEMANTIC_HNSW_EF_CONSTRUCTION"
+	EnvHNSWEfSearch       = "BV_SEMANTIC_HNSW_EF_SEARCH"
+	EnvHNSWMinSize        = "BV_SEMANTIC_HNSW_MIN_SIZE"
+)
+
+// HNSWConfig tunes the approximate nearest-neighbor graph.
+type HNSWConfig struct {
+	M              int // Links per node on upper layers; the base layer allows 2*M
+	EfConstruction int // Candidate list size while inserting
+	EfSearch       int // Candidate list size while searching; raised to k when smaller
+	MinSize        int // Below this many vectors, SearchTopK scans exactly
+}
+
+// DefaultHNSWConfig keeps exact search for typical single-project indexes
+// and switches to the graph for large workspace indexes.
+var DefaultHNSWConfig = HNSWConfig{M: 16, EfConstruction: 100, EfSearch: 64, MinSize: 5000}
+
+// Normalized fills unset or invalid values from DefaultHNSWConfig.
+func (c HNSWConfig) Normalized() HNSWConfig {
+	if c.M < 2 {
+		c.M = DefaultHNSWConfig.M
+	}
+	if c.EfConstruction <= 0 {
+		c.EfConstruction = DefaultHNSWConfig.EfConstruction
+	}
+	c.EfConstruction = max(c.EfConstruction, c.M)
+	if c.EfSearch <= 0 {
+		c.EfSearch = DefaultHNSWConfig.EfSearch
+	}
+	if c.MinSize <= 0 {
+		c.MinSize = DefaultHNSWConfig.MinSize
+	}
+	return c
+}
+
+type hnswNode struct {
+	id      string
+	vec     []float32
+	links   [][]uint32 // Neighbor node indexes per layer, base layer first
+	deleted bool
+}
+
+// HNSWIndex is a hierarchical navigable small world graph over vectors,
+// scored by dot product like VectorIndex.SearchTopK. Deletes leave
+// tombstones that still route searches but never appear in results; the
+// graph is rebuilt from the live nodes once tombstones outnumber them.
+//
+// HNSWIndex is not safe for concurrent use; VectorIndex guards it with its
+// own lock.
+type HNSWIndex struct {
+	cfg       HNSWConfig
+	nodes     []hnswNode
+	byID      map[string]uint32 // Live nodes only
+	entry     int               // Entry node, -1 when empty
+	maxLevel  int
+	deleted   int
+	rng       *rand.Rand
+	levelMult float64
+}
+
+func NewHNSWIndex(cfg HNSWConfig) *HNSWIndex {
+	cfg = cfg.Normalized()
+	return &HNSWIndex{
+		cfg:       cfg,
+		byID:      make(map[string]uint32),
+		entry:     -1,
+		rng:       rand.New(rand.NewSource(hnswSeed)),
+		levelMult: 1 / math.Log(float64(cfg.M)),
+	}
+}
+
+// Len returns the number of live vectors.
+func (h *HNSWIndex) Len() int { return len(h.byID) }
+
+// Insert adds vec under id, replacing any previous vector for id. The slice
+// is retained, so callers must not modify it afterwards.
+func (h *HNSWIndex) Insert(id string, vec []float32) {
+	h.Delete(id)
+
+	level := int(-math.Log(1-h.rng.Float64()) * h.levelMult)
+	n := uint32(len(h.nodes))
+	h.nodes = append(h.nodes, hnswNode{id: id, vec: vec, links: make([][]uint32, level+1)})
+	h.byID[id] = n
+
+	if h.entry < 0 {
+		h.entry, h.maxLevel = int(n), level
+		return
+	}
+
+	cur := uint32(h.entry)
+	for l := h.maxLevel; l > level; l-- {
+		cur = h.greedy(vec, cur, l)
+	}
+	entries := []uint32{cur}
+	for l := min(level, h.maxLevel); l >= 0; l-- {
+		found := h.searchLayer(vec, entries, h.cfg.EfConstruction, l)
+		live := found[:0:0]
+		for _, c := range found {
+			if !h.nodes[c.node].deleted {
+				live = append(live, c)
+			}
+		}
+		if len(live) == 0 {
+			live = found // Stay connected through tombstones
+		}
+		neighbors := h.selectNeighbors(live, h.cfg.M)
+		h.nodes[n].links[l] = neighbors
+		for _, nb := range neighbors {
+			h.link(nb, n, l)
+		}
+		entries = entries[:0]
+		for _, c := range found {
+			entries = append(entries, c.node)
+		}
+	}
+	if level > h.maxLevel {
+		h.entry, h.maxLevel = int(n), level
+	}
+}
+
+// Delete removes id from search results. It reports whether id was present.
+func (h *HNSWIndex) Delete(id string) bool {
+	n, ok := h.byID[id]
+	if !ok {
+		return false
+	}
+	delete(h.byID, id)
+	h.nodes[n].deleted = true
+	h.deleted++
+	if h.deleted > hnswTombstoneSlack && h.deleted > len(h.byID) {
+		h.rebuild()
+	}
+	return true
+}
+
+// rebuild drops tombstones by re-inserting the live nodes in their original
+// order.
+func (h *HNSWIndex) rebuild() {
+	old := h.nodes
+	*h = *NewHNSWIndex(h.cfg)
+	for _, node := range old {
+		if !node.deleted {
+			h.Insert(node.id, node.vec)
+		}
+	}
+}
+
+// Search returns up to k live nodes most similar to query, best first.
+// ef is the candidate list size; values below k are raised to k, and it is
+// widened by the tombstone count so tombstones cannot crowd out live nodes.
+func (h *HNSWIndex) Search(query []float32, k, ef int) []SearchResult {
+	if k <= 0 || h.entry < 0 {
+		return nil
+	}
+	ef = max(ef, k) + h.deleted
+	cur := uint32(h.entry)
+	for l := h.maxLevel; l > 0; l-- {
+		cur = h.greedy(query, cur, l)
+	}
+	found := h.searchLayer(query, []uint32{cur}, ef, 0)
+
+	// Rescore with the exact scan's precision so scores do not depend on
+	// which path answered.
+	results := make([]SearchResult, 0, k)
+	for _, c := range found {
+		if node := h.nodes[c.node]; !node.deleted {
+			results = append(results, SearchResult{IssueID: node.id, Score: dotFloat32(query, node.vec)})
+		}
+	}
+	if len(results) < min(k, len(h.byID)) {
+		return h.exact(query, k)
+	}
+	sortSearchResults(results)
+	if len(results) > k {
+		results = results[:k]
+	}
+	return results
+}
+
+// exact scores every live node. Search falls back to it when the graph
+// walk could not reach k live nodes.
+func (h *HNSWIndex) exact(query []float32, k int) []SearchResult {
+	results := make([]SearchResult, 0, len(h.byID))
+	for _, n := range h.byID {
+		node := h.nodes[n]
+		results = append(results, SearchResult{IssueID: node.id, Score: dotFloat32(query, node.vec)})
+	}
+	sortSearchResults(results)
+	if len(results) > k {
+		results = results[:k]
+	}
+	return results
+}
+
+// greedy walks layer l from start towards query and returns the closest
+// node found.
+func (h *HNSWIndex) greedy(query []float32, start uint32, l int) uint32 {
+	cur := start
+	best := dot32(query, h.nodes[cur].vec)
+	for changed := true; changed; {
+		changed = false
+		for _, nb := range h.nodes[cur].links[l] {
+			if sim := dot32(query, h.nodes[nb].vec); sim > best {
+				cur, best, changed = nb, sim, true
+			}
+		}
+	}
+	return cur
+}
+
+type hnswCandidate struct {
+	node uint32
+	sim  float64
+}
+
+// candidateHeap is a max-heap by similarity, or a min-heap when worst is set.
+type candidateHeap struct {
+	items []hnswCandidate
+	worst bool
+}
+
+func (c *candidateHeap) Len() int { return len(c.items) }
+func (c *candidateHeap) Less(i, j int) bool {
+	if c.worst {
+		return c.items[i].sim < c.items[j].sim
+	}
+	return c.items[i].sim > c.items[j].sim
+}
+func (c *candidateHeap) Swap(i, j int) { c.items[i], c.items[j] = c.items[j], c.items[i] }
+func (c *candidateHeap) Push(x any)    { c.items = append(c.items, x.(hnswCandidate)) }
+func (c *candidateHeap) Pop() any {
+	last := c.items[len(c.items)-1]
+	c.items = c.items[:len(c.items)-1]
+	return last
+}
+
+// searchLayer is the beam search of the HNSW paper: it returns up to ef
+// nodes of layer l closest to query, sorted best first.
+func (h *HNSWIndex) searchLayer(query []float32, entries []uint32, ef, l int) []hnswCandidate {
+	visited := make([]uint64, (len(h.nodes)+63)/64)
+	seen := func(n uint32) bool {
+		word, bit := n/64, uint64(1)<<(n%64)
+		if visited[word]&bit != 0 {
+			return true
+		}
+		visited[word] |= bit
+		return false
+	}
+	candidates := &candidateHeap{}
+	found := &candidateHeap{worst: true}
+	for _, e := range entries {
+		if seen(e) {
+			continue
+		}
+		c := hnswCandidate{e, dot32(query, h.nodes[e].vec)}
+		heap.Push(candidates, c)
+		heap.Push(found, c)
+		if found.Len() > ef {
+			heap.Pop(found)
+		}
+	}
+
+	for candidates.Len() > 0 {
+		c := heap.Pop(candidates).(hnswCandidate)
+		if found.Len() >= ef && c.sim < found.items[0].sim {
+			break
+		}
+		for _, nb := range h.nodes[c.node].links[l] {
+			if seen(nb) {
+				continue
+			}
+			sim := dot32(query, h.nodes[nb].vec)
+			if found.Len() < ef || sim > found.items[0].sim {
+				heap.Push(candidates, hnswCandidate{nb, sim})
+				heap.Push(found, hnswCandidate{nb, sim})
+				if found.Len() > ef {
+					heap.Pop(found)
+				}
+			}
+		}
+	}
+
+	out := found.items
+	sort.Slice(out, func(i, j int) bool { return out[i].sim > out[j].sim })
+	return out
+}
+
+// selectNeighbors picks up to m of the candidates (sorted best first) with
+// the HNSW heuristic: a candidate is skipped when it is closer to an already
+// selected neighbor than to the base node, which spreads links across
+// clusters. Skipped candidates fill any remaining slots.
+func (h *HNSWIndex) selectNeighbors(candidates []hnswCandidate, m int) []uint32 {
+	selected := make([]uint32, 0, m)
+	var skipped []uint32
+	for _, c := range candidates {
+		if len(selected) >= m {
+			break
+		}
+		keep := true
+		for _, s := range selected {
+			if dot32(h.nodes[c.node].vec, h.nodes[s].vec) > c.sim {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			selected = append(selected, c.node)
+		} else {
+			skipped = append(skipped, c.node)
+		}
+	}
+	for _, s := range skipped {
+		if len(selected) >= m {
+			break
+		}
+		selected = append(selected, s)
+	}
+	return selected
+}
+
+// link adds a layer-l edge from node to target, pruning node's links with
+// the selection heuristic when it has too many.
+func (h *HNSWIndex) link(node, target uint32, l int) {
+	links := append(h.nodes[node].links[l], target)
+	limit := h.cfg.M
+	if l == 0 {
+		limit = 2 * h.cfg.M
+	}
+	if len(links) > limit {
+		base := h.nodes[node].vec
+		candidates := make([]hnswCandidate, len(links))
+		for i, nb := range links {
+			candidates[i] = hnswCandidate{nb, dot32(base, h.nodes[nb].vec)}
+		}
+		sort.Slice(candidates, func(i, j int) bool { return candidates[i].sim > candidates[j].sim })
+		links = h.selectNeighbors(candidates, limit)
+	}
+	h.nodes[node].links[l] = links
+}
+
+// writeTo serializes the graph. Live vectors are not written; readFrom
+// takes them from the owning VectorIndex. Tombstones keep their vectors
+// because they still route searches.
+//
+// Layout: "HNSW", version u16, M u16, efConstruction u16, node count u32,
+// entry i32, max level u16, then per node: id (u16 length + bytes),
+// deleted u8, [vector f32*dim if deleted], layer count u8, and per layer a
+// u16 link count followed by u32 node indexes.
+func (h *HNSWIndex) writeTo(w *bufio.Writer) error {
+	header := struct {
+		Version, M, EfConstruction uint16
+		Count                      uint32
+		Entry                      int32
+		MaxLevel                   uint16
+	}{hnswVersion, uint16(h.cfg.M), uint16(h.cfg.EfConstruction), uint32(len(h.nodes)), int32(h.entry), uint16(h.maxLevel)}
+	if _, err := w.WriteString(hnswMagic); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, header); err != nil {
+		return err
+	}
+	for _, node := range h.nodes {
+		if err := writeString16(w, node.id); err != nil {
+			return err
+		}
+		var deleted uint8
+		if node.deleted {
+			deleted = 1
+		}
+		if err := w.WriteByte(deleted); err != nil {
+			return err
+		}
+		if node.deleted {
+			if err := binary.Write(w, binary.LittleEndian, node.vec); err != nil {
+				return err
+			}
+		}
+		if err := w.WriteByte(uint8(len(node.links))); err != nil {
+			return err
+		}
+		for _, links := range node.links {
+			if err := binary.Write(w, binary.LittleEndian, uint16(len(links))); err != nil {
+				return err
+			}
+			if err := binary.Write(w, binary.LittleEndian, links); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// readHNSW reads a graph written by writeTo. Live nodes take their vectors
+// from entries; a graph that disagrees with entries is rejected so the
+// caller can rebuild it.
+func readHNSW(r io.Reader, cfg HNSWConfig, dim int, entries map[string]VectorEntry) (*HNSWIndex, error) {
+	var magic [4]byte
+	if _, err := io.ReadFull(r, magic[:]); err != nil {
+		return nil, fmt.Errorf("read graph header: %w", err)
+	}
+	if string(magic[:]) != hnswMagic {
+		return nil, fmt.Errorf("invalid graph magic %q", string(magic[:]))
+	}
+	var header struct {
+		Version, M, EfConstruction uint16
+		Count                      uint32
+		Entry                      int32
+		MaxLevel                   uint16
+	}
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return nil, fmt.Errorf("read graph header: %w", err)
+	}
+	if header.Version != hnswVersion {
+		return nil, fmt.Errorf("unsupported graph version %d", header.Version)
+	}
+	// Delete bounds the tombstones, so a larger count is a damaged header
+	// and must not size an allocation.
+	if int64(header.Count) > 2*int64(len(entries))+hnswTombstoneSlack {
+		return nil, fmt.Errorf("graph has %d nodes for %d vectors", header.Count, len(entries))
+	}
+	cfg.M, cfg.EfConstruction = int(header.M), int(header.EfConstruction)
+	h := NewHNSWIndex(cfg)
+	if int(header.Entry) >= int(header.Count) || header.Entry < -1 {
+		return nil, fmt.Errorf("invalid graph entry %d", header.Entry)
+	}
+	h.entry, h.maxLevel = int(header.Entry), int(header.MaxLevel)
+
+	h.nodes = make([]hnswNode, header.Count)
+	for i := range h.nodes {
+		node := &h.nodes[i]
+		id, err := readString16(r)
+		if err != nil {
+			return nil, fmt.Errorf("read graph node: %w", err)
+		}
+		node.id = id
+		var flags [1]byte
+		if _, err := io.ReadFull(r, flags[:]); err != nil {
+			return nil, fmt.Errorf("read graph node: %w", err)
+		}
+		if flags[0] == 1 {
+			node.deleted = true
+			node.vec = make([]float32, dim)
+			if err := binary.Read(r, binary.LittleEndian, node.vec); err != nil {
+				return nil, fmt.Errorf("read graph vector: %w", err)
+			}
+			h.deleted++
+		} else {
+			entry, ok := entries[id]
+			if _, dup := h.byID[id]; !ok || dup {
+				return nil, fmt.Errorf("graph node %q does not match the index", id)
+			}
+			node.vec = entry.Vector
+			h.byID[id] = uint32(i)
+		}
+		var layers [1]byte
+		if _, err := io.ReadFull(r, layers[:]); err != nil {
+			return nil, fmt.Errorf("read graph node: %w", err)
+		}
+		node.links = make([][]uint32, layers[0])
+		for l := range node.links {
+			var n uint16
+			if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+				return nil, fmt.Errorf("read graph links: %w", err)
+			}
+			node.links[l] = make([]uint32, n)
+			if err := binary.Read(r, binary.LittleEndian, node.links[l]); err != nil {
+				return nil, fmt.Errorf("read graph links: %w", err)
+			}
+		}
+	}
+	if len(h.byID) != len(entries) {
+		return nil, fmt.Errorf("graph has %d live nodes, index has %d", len(h.byID), len(entries))
+	}
+	// Searches descend from the entry node's top layer, so it must be the
+	// node that reaches maxLevel.
+	if h.entry < 0 {
+		if len(h.nodes) > 0 || h.maxLevel != 0 {
+			return nil, fmt.Errorf("invalid graph entry %d", h.entry)
+		}
+	} else if len(h.nodes[h.entry].links) != h.maxLevel+1 {
+		return nil, fmt.Errorf("graph entry %q does not reach max level %d", h.nodes[h.entry].id, h.maxLevel)
+	}
+	for _, node := range h.nodes {
+		if len(node.links) == 0 || len(node.links) > h.maxLevel+1 {
+			return nil, fmt.Errorf("graph node %q has an invalid level", node.id)
+		}
+		for l, links := range node.links {
+			for _, nb := range links {
+				if int(nb) >= len(h.nodes) || len(h.nodes[nb].links) <= l {
+					return nil, fmt.Errorf("graph node %q has an invalid link", node.id)
+				}
+			}
+		}
+	}
+	// Continue the level sequence rather than replaying the first levels.
+	h.rng = rand.New(rand.NewSource(hnswSeed + int64(len(h.nodes))))
+	return h, nil
+}
+
+// dot32 is a float32 dot product for graph traversal, where speed matters
+// more than the last digits.
+func dot32(a, b []float32) float64 {
+	b = b[:len(a)]
+	var s0, s1, s2, s3 float32
+	i := 0
+	for ; i+4 <= len(a); i += 4 {
+		s0 += a[i] * b[i]
+		s1 += a[i+1] * b[i+1]
+		s2 += a[i+2] * b[i+2]
+		s3 += a[i+3] * b[i+3]
+	}
+	for ; i < len(a); i++ {
+		s0 += a[i] * b[i]
+	}
+	return float64(s0 + s1 + s2 + s3)
+}
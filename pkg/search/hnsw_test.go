@@ -0,0 +1,369 @@
+package search
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func randomUnitVectors(rng *rand.Rand, n, dim int) [][]float32 {
+	vecs := make([][]float32, n)
+	for i := range vecs {
+		v := make([]float32, dim)
+		var norm float64
+		for j := range v {
+			v[j] = float32(rng.NormFloat64())
+			norm += float64(v[j]) * float64(v[j])
+		}
+		norm = math.Sqrt(norm)
+		for j := range v {
+			v[j] = float32(float64(v[j]) / norm)
+		}
+		vecs[i] = v
+	}
+	return vecs
+}
+
+func bruteForceTopK(vecs map[string][]float32, query []float32, k int) []string {
+	results := make([]SearchResult, 0, len(vecs))
+	for id, v := range vecs {
+		results = append(results, SearchResult{IssueID: id, Score: dotFloat32(query, v)})
+	}
+	sortSearchResults(results)
+	ids := make([]string, 0, k)
+	for _, r := range results[:min(k, len(results))] {
+		ids = append(ids, r.IssueID)
+	}
+	return ids
+}
+
+func vectorID(i int) string { return fmt.Sprintf("bv-%d", i) }
+
+func TestHNSWRecallAgainstBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	const n, dim, k = 3000, 32, 10
+	vecs := randomUnitVectors(rng, n, dim)
+
+	idx := NewVectorIndex(dim)
+	idx.SetHNSWConfig(HNSWConfig{MinSize: 1})
+	exact := make(map[string][]float32, n)
+	for i, v := range vecs {
+		id := vectorID(i)
+		exact[id] = v
+		if err := idx.Upsert(id, ComputeContentHash(id), v); err != nil {
+			t.Fatalf("Upsert: %v", err)
+		}
+	}
+	if !idx.UsesANN() {
+		t.Fatal("expected the HNSW graph to be used")
+	}
+
+	recall := func() float64 {
+		hits, total := 0, 0
+		for _, q := range randomUnitVectors(rng, 100, dim) {
+			want := bruteForceTopK(exact, q, k)
+			got, err := idx.SearchTopK(q, k)
+			if err != nil {
+				t.Fatalf("SearchTopK: %v", err)
+			}
+			inGot := make(map[string]bool, len(got))
+			for _, r := range got {
+				inGot[r.IssueID] = true
+			}
+			for _, id := range want {
+				if inGot[id] {
+					hits++
+				}
+			}
+			total += len(want)
+		}
+		return float64(hits) / float64(total)
+	}
+
+	if r := recall(); r < 0.95 {
+		t.Fatalf("recall@%d = %.3f, want >= 0.95", k, r)
+	}
+
+	// Incremental updates: remove a third, move another third.
+	for i, v := range randomUnitVectors(rng, n, dim) {
+		id := vectorID(i)
+		switch i % 3 {
+		case 0:
+			idx.Remove(id)
+			delete(exact, id)
+		case 1:
+			if err := idx.Upsert(id, ComputeContentHash(id+"2"), v); err != nil {
+				t.Fatalf("Upsert: %v", err)
+			}
+			exact[id] = v
+		}
+	}
+	if idx.Size() != len(exact) {
+		t.Fatalf("size = %d, want %d", idx.Size(), len(exact))
+	}
+	if r := recall(); r < 0.95 {
+		t.Fatalf("recall@%d after updates = %.3f, want >= 0.95", k, r)
+	}
+}
+
+func TestHNSWDeleteNeverReturnsRemoved(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	h := NewHNSWIndex(HNSWConfig{M: 4})
+	vecs := randomUnitVectors(rng, 300, 8)
+	for i, v := range vecs {
+		h.Insert(vectorID(i), v)
+	}
+	for i := 0; i < 250; i++ {
+		h.Delete(vectorID(i))
+	}
+	if h.Len() != 50 {
+		t.Fatalf("Len = %d, want 50", h.Len())
+	}
+	if h.deleted > 64 && h.deleted > h.Len() {
+		t.Fatalf("tombstones not compacted: %d", h.deleted)
+	}
+	for _, q := range vecs[:20] {
+		for _, r := range h.Search(q, 10, 32) {
+			var i int
+			for i = range vecs {
+				if vectorID(i) == r.IssueID {
+					break
+				}
+			}
+			if i < 250 {
+				t.Fatalf("deleted node %s returned", r.IssueID)
+			}
+		}
+	}
+	if got := h.Search(vecs[260], 1, 16); len(got) != 1 || got[0].IssueID != vectorID(260) {
+		t.Fatalf("expected exact self match, got %v", got)
+	}
+}
+
+func TestHNSWSearchFillsKPastTombstones(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	h := NewHNSWIndex(HNSWConfig{M: 4})
+	vecs := randomUnitVectors(rng, 200, 8)
+	all := make(map[string][]float32, len(vecs))
+	for i, v := range vecs {
+		h.Insert(vectorID(i), v)
+		all[vectorID(i)] = v
+	}
+	// Tombstone the query's nearest neighbors, staying under the rebuild
+	// threshold, so they would fill a candidate list of size ef.
+	q := vecs[0]
+	removed := make(map[string]bool)
+	for _, id := range bruteForceTopK(all, q, 60) {
+		h.Delete(id)
+		removed[id] = true
+	}
+	if h.deleted != 60 {
+		t.Fatalf("tombstones = %d, want 60 (no rebuild)", h.deleted)
+	}
+	got := h.Search(q, 10, 10)
+	if len(got) != 10 {
+		t.Fatalf("got %d results, want 10", len(got))
+	}
+	for _, r := range got {
+		if removed[r.IssueID] {
+			t.Fatalf("deleted node %s returned", r.IssueID)
+		}
+	}
+}
+
+func TestReadHNSWRejectsInconsistentHeader(t *testing.T) {
+	rng := rand.New(rand.NewSource(6))
+	h := NewHNSWIndex(HNSWConfig{M: 4})
+	entries := make(map[string]VectorEntry)
+	for i, v := range randomUnitVectors(rng, 100, 8) {
+		h.Insert(vectorID(i), v)
+		entries[vectorID(i)] = VectorEntry{Vector: v}
+	}
+	if h.maxLevel == 0 {
+		t.Fatal("test graph should have more than one layer")
+	}
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	if err := h.writeTo(w); err != nil {
+		t.Fatal(err)
+	}
+	_ = w.Flush()
+	data := buf.Bytes()
+	if _, err := readHNSW(bytes.NewReader(data), h.cfg, 8, entries); err != nil {
+		t.Fatalf("intact graph rejected: %v", err)
+	}
+
+	// Header offsets after the magic: version, M, efConstruction (u16
+	// each), count u32, entry i32, max level u16.
+	const countOff, entryOff, levelOff = 10, 14, 18
+	var lowNode int32 = -1
+	for i, node := range h.nodes {
+		if len(node.links) == 1 {
+			lowNode = int32(i)
+			break
+		}
+	}
+	cases := map[string]func([]byte) []byte{
+		"truncated header": func(b []byte) []byte { return b[:levelOff] },
+		"node count beyond the vectors": func(b []byte) []byte {
+			binary.LittleEndian.PutUint32(b[countOff:], math.MaxUint32)
+			return b
+		},
+		"max level above entry": func(b []byte) []byte {
+			binary.LittleEndian.PutUint16(b[levelOff:], uint16(h.maxLevel+1))
+			return b
+		},
+		"entry below max level": func(b []byte) []byte {
+			binary.LittleEndian.PutUint32(b[entryOff:], uint32(lowNode))
+			return b
+		},
+		"no entry with nodes": func(b []byte) []byte {
+			binary.LittleEndian.PutUint32(b[entryOff:], math.MaxUint32)
+			return b
+		},
+	}
+	for name, damage := range cases {
+		b := damage(append([]byte(nil), data...))
+		if _, err := readHNSW(bytes.NewReader(b), h.cfg, 8, entries); err == nil {
+			t.Errorf("%s: graph accepted, want error", name)
+		}
+	}
+}
+
+func TestVectorIndexExactBelowMinSize(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	idx := NewVectorIndex(8)
+	idx.SetHNSWConfig(HNSWConfig{MinSize: 50})
+	for i, v := range randomUnitVectors(rng, 49, 8) {
+		_ = idx.Upsert(vectorID(i), ComputeContentHash(vectorID(i)), v)
+	}
+	if idx.UsesANN() || idx.ann != nil {
+		t.Fatal("small index should not build a graph")
+	}
+	_ = idx.Upsert("extra", ComputeContentHash("extra"), randomUnitVectors(rng, 1, 8)[0])
+	if !idx.UsesANN() {
+		t.Fatal("graph should be built once the index reaches MinSize")
+	}
+	idx.Remove("extra")
+	if idx.UsesANN() || idx.ann == nil {
+		t.Fatal("shrunken index should search exactly but keep the graph in sync")
+	}
+}
+
+func TestVectorIndexHNSWPersistence(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	const dim = 16
+	idx := NewVectorIndex(dim)
+	idx.SetHNSWConfig(HNSWConfig{M: 8, MinSize: 10})
+	vecs := randomUnitVectors(rng, 200, dim)
+	for i, v := range vecs {
+		_ = idx.Upsert(vectorID(i), ComputeContentHash(vectorID(i)), v)
+	}
+	idx.Remove(vectorID(0)) // Keep a tombstone in the saved graph
+	_ = idx.Upsert(vectorID(1), ComputeContentHash("moved"), vecs[0])
+
+	path := filepath.Join(t.TempDir(), "index.bvvi")
+	if err := idx.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	loaded, err := LoadVectorIndex(path)
+	if err != nil {
+		t.Fatalf("LoadVectorIndex: %v", err)
+	}
+	if loaded.SetHNSWConfig(HNSWConfig{M: 8, MinSize: 10}) {
+		t.Fatal("persisted graph should be reused, not rebuilt")
+	}
+	if !reflect.DeepEqual(loaded.ann.nodes, idx.ann.nodes) || loaded.ann.entry != idx.ann.entry {
+		t.Fatal("graph changed across save/load")
+	}
+	for _, q := range vecs[:10] {
+		want, _ := idx.SearchTopK(q, 5)
+		got, _ := loaded.SearchTopK(q, 5)
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("results differ after reload: %v vs %v", got, want)
+		}
+	}
+
+	// Changing the graph shape rebuilds it.
+	if !loaded.SetHNSWConfig(HNSWConfig{M: 12, MinSize: 10}) || loaded.ann.cfg.M != 12 {
+		t.Fatal("expected a rebuild for a different M")
+	}
+
+	// A damaged graph section is rebuilt from the vectors.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data[:len(data)-7], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	damaged, err := LoadVectorIndex(path)
+	if err != nil {
+		t.Fatalf("damaged graph should not fail the load: %v", err)
+	}
+	if damaged.ann != nil {
+		t.Fatal("damaged graph should be dropped")
+	}
+	if !damaged.SetHNSWConfig(HNSWConfig{M: 8, MinSize: 10}) || damaged.ann.Len() != 199 {
+		t.Fatal("expected the graph to be rebuilt")
+	}
+}
+
+func TestLoadVectorIndexLeavesGraphBuildToConfig(t *testing.T) {
+	defaults := DefaultHNSWConfig
+	t.Cleanup(func() { DefaultHNSWConfig = defaults })
+	DefaultHNSWConfig.MinSize = 10
+
+	rng := rand.New(rand.NewSource(7))
+	idx := NewVectorIndex(8)
+	idx.SetHNSWConfig(HNSWConfig{MinSize: 1000}) // Saved without a graph
+	for i, v := range randomUnitVectors(rng, 50, 8) {
+		_ = idx.Upsert(vectorID(i), ComputeContentHash(vectorID(i)), v)
+	}
+	path := filepath.Join(t.TempDir(), "index.bvvi")
+	if err := idx.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadVectorIndex(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.ann != nil {
+		t.Fatal("load built a graph before the ANN config was known")
+	}
+	if !loaded.SetHNSWConfig(HNSWConfig{M: 12, MinSize: 10}) || loaded.ann.cfg.M != 12 {
+		t.Fatal("expected one build with the caller's config")
+	}
+}
+
+func TestVectorIndexLoadsVersion2(t *testing.T) {
+	idx := NewVectorIndex(2)
+	_ = idx.Upsert("A", ComputeContentHash("a"), []float32{1, 0})
+	path := filepath.Join(t.TempDir(), "index.bvvi")
+	if err := idx.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	// A version 2 file is the same minus the trailing graph flag.
+	data, _ := os.ReadFile(path)
+	data[4] = 2
+	if err := os.WriteFile(path, data[:len(data)-1], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadVectorIndex(path)
+	if err != nil || loaded.Size() != 1 {
+		t.Fatalf("load v2: %v", err)
+	}
+	ids := loaded.sortedIDs()
+	if !sort.StringsAreSorted(ids) || ids[0] != "A" {
+		t.Fatalf("unexpected ids %v", ids)
+	}
+}
@@ -0,0 +1,248 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultOpenAIBaseURL = "https://api.openai.com"
+	defaultOllamaBaseURL = "http://localhost:11434"
+	defaultHTTPBatchSize = 64
+	httpEmbedMaxRetries  = 3
+	httpEmbedMaxBackoff  = 30 * time.Second
+)
+
+// HTTPEmbedder talks to an embedding server over HTTP, in either the
+// OpenAI-compatible /v1/embeddings shape (batched "input") or the Ollama
+// /api/embeddings shape (one "prompt" per request). Rate limits, server
+// errors and network failures are retried with exponential backoff.
+type HTTPEmbedder struct {
+	provider  Provider
+	model     string
+	dim       int
+	endpoint  string
+	apiKey    string
+	batchSize int
+	client    *http.Client
+	backoff   time.Duration // First retry delay; doubles per attempt
+}
+
+// NewHTTPEmbedder creates an embedder for the openai or ollama provider.
+func NewHTTPEmbedder(cfg EmbeddingConfig) (*HTTPEmbedder, error) {
+	cfg = cfg.Normalized()
+	base := strings.TrimRight(cfg.BaseURL, "/")
+	var path string
+	switch cfg.Provider {
+	case ProviderOpenAI:
+		if base == "" {
+			base = defaultOpenAIBaseURL
+		}
+		path = "/v1/embeddings"
+		if strings.HasSuffix(base, "/v1") {
+			path = "/embeddings"
+		}
+	case ProviderOllama:
+		if base == "" {
+			base = defaultOllamaBaseURL
+		}
+		path = "/api/embeddings"
+	default:
+		return nil, fmt.Errorf("provider %q is not an HTTP embedder", cfg.Provider)
+	}
+	if u, err := url.Parse(base); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid %s %q: expected http(s)://host[:port]", EnvSemanticURL, cfg.BaseURL)
+	}
+	if cfg.Model == "" {
+		return nil, fmt.Errorf("semantic embedder %q needs a model; set %s", cfg.Provider, EnvSemanticModel)
+	}
+	batch := cfg.BatchSize
+	if batch <= 0 {
+		batch = defaultHTTPBatchSize
+	}
+	return &HTTPEmbedder{
+		provider:  cfg.Provider,
+		model:     cfg.Model,
+		dim:       cfg.Dim,
+		endpoint:  base + path,
+		apiKey:    cfg.APIKey,
+		batchSize: batch,
+		client:    &http.Client{Timeout: 60 * time.Second},
+		backoff:   500 * time.Millisecond,
+	}, nil
+}
+
+func (e *HTTPEmbedder) Provider() Provider { return e.provider }
+func (e *HTTPEmbedder) Dim() int           { return e.dim }
+func (e *HTTPEmbedder) Model() string      { return e.model }
+
+// Endpoint returns the URL requests are sent to.
+func (e *HTTPEmbedder) Endpoint() string { return e.endpoint }
+
+// Embed returns one vector per text, in order. Every vector must have Dim
+// elements; a server returning another size is reported rather than
+// silently mixed into an index built for a different model.
+func (e *HTTPEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += e.batchSize {
+		end := min(start+e.batchSize, len(texts))
+		var vecs [][]float32
+		var err error
+		if e.provider == ProviderOllama {
+			vecs, err = e.embedOllama(ctx, texts[start:end])
+		} else {
+			vecs, err = e.embedOpenAI(ctx, texts[start:end])
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(vecs) != end-start {
+			return nil, fmt.Errorf("%s embedder: server returned %d vectors for %d texts", e.provider, len(vecs), end-start)
+		}
+		for _, v := range vecs {
+			if len(v) != e.dim {
+				return nil, fmt.Errorf("%s embedder: model %q returned %d-dimensional vectors but the index expects %d; set %s=%d",
+					e.provider, e.model, len(v), e.dim, EnvSemanticDim, len(v))
+			}
+		}
+		out = append(out, vecs...)
+	}
+	return out, nil
+}
+
+type openAIEmbeddingRequest struct {
+	Model      string   `json:"model"`
+	Input      []string `json:"input"`
+	Dimensions int      `json:"dimensions,omitempty"`
+}
+
+type openAIEmbeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+func (e *HTTPEmbedder) embedOpenAI(ctx context.Context, texts []string) ([][]float32, error) {
+	req := openAIEmbeddingRequest{Model: e.model, Input: texts}
+	// text-embedding-3 models can shorten their output; other servers may
+	// reject the field, so only send it where it is known to work.
+	if strings.HasPrefix(e.model, "text-embedding-3") && knownModelDims[e.model] != e.dim {
+		req.Dimensions = e.dim
+	}
+	var resp openAIEmbeddingResponse
+	if err := e.post(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(resp.Data, func(i, j int) bool { return resp.Data[i].Index < resp.Data[j].Index })
+	vecs := make([][]float32, len(resp.Data))
+	for i, d := range resp.Data {
+		vecs[i] = d.Embedding
+	}
+	return vecs, nil
+}
+
+type ollamaEmbeddingRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+}
+
+type ollamaEmbeddingResponse struct {
+	Embedding []float32 `json:"embedding"`
+}
+
+func (e *HTTPEmbedder) embedOllama(ctx context.Context, texts []string) ([][]float32, error) {
+	vecs := make([][]float32, 0, len(texts))
+	for _, text := range texts {
+		var resp ollamaEmbeddingResponse
+		if err := e.post(ctx, ollamaEmbeddingRequest{Model: e.model, Prompt: text}, &resp); err != nil {
+			return nil, err
+		}
+		vecs = append(vecs, resp.Embedding)
+	}
+	return vecs, nil
+}
+
+// post sends one JSON request, retrying on network errors, 429 and 5xx.
+func (e *HTTPEmbedder) post(ctx context.Context, payload, result any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	delay := e.backoff
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := e.postOnce(ctx, body, result)
+		if err == nil {
+			return nil
+		}
+		var re *retryableError
+		if !errors.As(err, &re) || attempt >= httpEmbedMaxRetries || ctx.Err() != nil {
+			return fmt.Errorf("%s embedder: %w", e.provider, err)
+		}
+		wait := delay
+		if retryAfter > 0 {
+			wait = retryAfter
+		}
+		wait = min(wait, httpEmbedMaxBackoff)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+// retryableError marks failures worth another attempt.
+type retryableError struct{ err error }
+
+func (e *retryableError) Error() string { return e.err.Error() }
+func (e *retryableError) Unwrap() error { return e.err }
+
+func (e *HTTPEmbedder) postOnce(ctx context.Context, body []byte, result any) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		return 0, &retryableError{err}
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		err := fmt.Errorf("POST %s: %s: %s", e.endpoint, resp.Status, strings.TrimSpace(string(snippet)))
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			var retryAfter time.Duration
+			if secs, convErr := strconv.Atoi(resp.Header.Get("Retry-After")); convErr == nil && secs > 0 {
+				retryAfter = time.Duration(secs) * time.Second
+			}
+			return retryAfter, &retryableError{err}
+		}
+		return 0, err
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return 0, fmt.Errorf("decode response from %s: %w", e.endpoint, err)
+	}
+	return 0, nil
+}
@@ -0,0 +1,203 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// stubEmbedding returns a deterministic vector whose first element encodes
+// the text length, so tests can check that order is preserved.
+func stubEmbedding(text string, dim int) []float32 {
+	vec := make([]float32, dim)
+	vec[0] = float32(len(text))
+	return vec
+}
+
+func newStubServer(t *testing.T, dim int, handler func(w http.ResponseWriter, r *http.Request) bool) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if handler != nil && handler(w, r) {
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/embeddings":
+			var req openAIEmbeddingRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			var resp openAIEmbeddingResponse
+			// Answer in reverse order; clients must sort by index.
+			for i := len(req.Input) - 1; i >= 0; i-- {
+				resp.Data = append(resp.Data, struct {
+					Index     int       `json:"index"`
+					Embedding []float32 `json:"embedding"`
+				}{i, stubEmbedding(req.Input[i], dim)})
+			}
+			_ = json.NewEncoder(w).Encode(resp)
+		case "/api/embeddings":
+			var req ollamaEmbeddingRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(ollamaEmbeddingResponse{Embedding: stubEmbedding(req.Prompt, dim)})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func newTestHTTPEmbedder(t *testing.T, cfg EmbeddingConfig) *HTTPEmbedder {
+	t.Helper()
+	e, err := NewHTTPEmbedder(cfg)
+	if err != nil {
+		t.Fatalf("NewHTTPEmbedder: %v", err)
+	}
+	e.backoff = 0
+	return e
+}
+
+func TestHTTPEmbedderOpenAIBatching(t *testing.T) {
+	var auth atomic.Value
+	srv, calls := newStubServer(t, 8, func(_ http.ResponseWriter, r *http.Request) bool {
+		auth.Store(r.Header.Get("Authorization"))
+		return false
+	})
+	e := newTestHTTPEmbedder(t, EmbeddingConfig{
+		Provider: ProviderOpenAI, Model: "stub", Dim: 8,
+		BaseURL: srv.URL, APIKey: "secret", BatchSize: 2,
+	})
+
+	texts := []string{"a", "bb", "ccc", "dddd", "eeeee"}
+	vecs, err := e.Embed(context.Background(), texts)
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	if len(vecs) != len(texts) {
+		t.Fatalf("got %d vectors, want %d", len(vecs), len(texts))
+	}
+	for i, v := range vecs {
+		if int(v[0]) != len(texts[i]) {
+			t.Errorf("vector %d out of order: %v", i, v[0])
+		}
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("requests = %d, want 3 batches", got)
+	}
+	if got := auth.Load(); got != "Bearer secret" {
+		t.Errorf("Authorization = %q", got)
+	}
+}
+
+func TestHTTPEmbedderOllama(t *testing.T) {
+	srv, calls := newStubServer(t, 4, nil)
+	e := newTestHTTPEmbedder(t, EmbeddingConfig{Provider: ProviderOllama, Model: "stub", Dim: 4, BaseURL: srv.URL + "/"})
+
+	vecs, err := e.Embed(context.Background(), []string{"x", "yy"})
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	if len(vecs) != 2 || vecs[1][0] != 2 {
+		t.Fatalf("unexpected vectors: %v", vecs)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("requests = %d, want one per text", got)
+	}
+}
+
+func TestHTTPEmbedderRetries(t *testing.T) {
+	var failures atomic.Int32
+	srv, calls := newStubServer(t, 4, func(w http.ResponseWriter, _ *http.Request) bool {
+		if failures.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return true
+		}
+		return false
+	})
+	e := newTestHTTPEmbedder(t, EmbeddingConfig{Provider: ProviderOpenAI, Model: "stub", Dim: 4, BaseURL: srv.URL})
+
+	if _, err := e.Embed(context.Background(), []string{"a"}); err != nil {
+		t.Fatalf("Embed after transient failures: %v", err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("requests = %d, want 3", got)
+	}
+
+	// Client errors are not retried.
+	srv2, calls2 := newStubServer(t, 4, func(w http.ResponseWriter, _ *http.Request) bool {
+		http.Error(w, `{"error":"bad model"}`, http.StatusBadRequest)
+		return true
+	})
+	e2 := newTestHTTPEmbedder(t, EmbeddingConfig{Provider: ProviderOpenAI, Model: "stub", Dim: 4, BaseURL: srv2.URL})
+	_, err := e2.Embed(context.Background(), []string{"a"})
+	if err == nil || !strings.Contains(err.Error(), "bad model") {
+		t.Fatalf("expected server error message, got %v", err)
+	}
+	if got := calls2.Load(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+func TestHTTPEmbedderDimensionMismatch(t *testing.T) {
+	srv, _ := newStubServer(t, 768, nil)
+	e := newTestHTTPEmbedder(t, EmbeddingConfig{Provider: ProviderOllama, Model: "stub", Dim: 384, BaseURL: srv.URL})
+
+	_, err := e.Embed(context.Background(), []string{"a"})
+	if err == nil || !strings.Contains(err.Error(), EnvSemanticDim+"=768") {
+		t.Fatalf("expected dimension hint, got %v", err)
+	}
+}
+
+func TestSyncVectorIndexReembedsOnModelChange(t *testing.T) {
+	srv, _ := newStubServer(t, 4, nil)
+	docs := map[string]string{"A": "alpha", "B": "beta"}
+	path := filepath.Join(t.TempDir(), "index.bvvi")
+
+	e1 := newTestHTTPEmbedder(t, EmbeddingConfig{Provider: ProviderOllama, Model: "m1", Dim: 4, BaseURL: srv.URL})
+	idx := NewVectorIndex(4)
+	if _, err := SyncVectorIndex(context.Background(), idx, e1, docs, 0); err != nil {
+		t.Fatalf("SyncVectorIndex: %v", err)
+	}
+	if err := idx.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, ok, err := LoadOrNewVectorIndex(path, 4)
+	if err != nil || !ok {
+		t.Fatalf("LoadOrNewVectorIndex: loaded=%v err=%v", ok, err)
+	}
+	if loaded.Model != "m1" {
+		t.Fatalf("Model = %q, want m1", loaded.Model)
+	}
+
+	stats, err := SyncVectorIndex(context.Background(), loaded, e1, docs, 0)
+	if err != nil || stats.Embedded != 0 {
+		t.Fatalf("same model should not re-embed: %+v, %v", stats, err)
+	}
+
+	e2 := newTestHTTPEmbedder(t, EmbeddingConfig{Provider: ProviderOllama, Model: "m2", Dim: 4, BaseURL: srv.URL})
+	stats, err = SyncVectorIndex(context.Background(), loaded, e2, docs, 0)
+	if err != nil {
+		t.Fatalf("SyncVectorIndex: %v", err)
+	}
+	if stats.Embedded != 2 || stats.Updated != 2 || loaded.Model != "m2" {
+		t.Fatalf("model change should re-embed everything: %+v, model %q", stats, loaded.Model)
+	}
+
+	// An index built for another dimension is discarded.
+	fresh, ok, err := LoadOrNewVectorIndex(path, 8)
+	if err != nil || ok || fresh.Dim != 8 || fresh.Size() != 0 {
+		t.Fatalf("expected a fresh 8-dim index, got loaded=%v dim=%d size=%d err=%v", ok, fresh.Dim, fresh.Size(), err)
+	}
+}
@@ -37,11 +37,16 @@ func (s IndexSyncStats) Changed() bool {
 
 // LoadOrNewVectorIndex loads an existing vector index if present, otherwise creates a new one.
 // If loading fails due to corruption, it backs up the corrupt file and returns a new empty index.
+// An index whose header dimension differs from dim cannot be searched with the current embedder,
+// so it is discarded the same way and rebuilt.
 func LoadOrNewVectorIndex(path string, dim int) (*VectorIndex, bool, error) {
 	idx, err := LoadVectorIndex(path)
-	if err == nil {
+	if err == nil && (dim <= 0 || idx.Dim == dim) {
 		return idx, true, nil
 	}
+	if err == nil {
+		return NewVectorIndex(dim), false, nil
+	}
 
 	if os.IsNotExist(err) {
 		return NewVectorIndex(dim), false, nil
@@ -64,9 +69,9 @@ func LoadOrNewVectorIndex(path string, dim int) (*VectorIndex, bool, error) {
 }
 
 // SyncVectorIndex updates idx to match docs using embedder, incrementally embedding only changed items.
+// When the embedder's model differs from the one recorded in idx, every document is re-embedded.
 //
-// This is intended for offline, deterministic embedding providers. Callers should persist idx
-// with (*VectorIndex).Save when desired.
+// Callers should persist idx with (*VectorIndex).Save when desired.
 func SyncVectorIndex(ctx context.Context, idx *VectorIndex, embedder Embedder, docs map[string]string, batchSize int) (IndexSyncStats, error) {
 	var stats IndexSyncStats
 	if idx == nil {
@@ -83,6 +88,8 @@ func SyncVectorIndex(ctx context.Context, idx *VectorIndex, embedder Embedder, d
 	}
 
 	stats.Total = len(docs)
+	model := EmbedderModel(embedder)
+	reembed := idx.Model != model
 
 	// Remove stale IDs.
 	docIDs := make(map[string]struct{}, len(docs))
@@ -114,7 +121,7 @@ func SyncVectorIndex(ctx context.Context, idx *VectorIndex, embedder Embedder, d
 		text := docs[id]
 		ch := ComputeContentHash(text)
 		existing, ok := idx.Get(id)
-		if ok && existing.ContentHash == ch {
+		if ok && existing.ContentHash == ch && !reembed {
 			stats.Skipped++
 			continue
 		}
@@ -151,6 +158,7 @@ func SyncVectorIndex(ctx context.Context, idx *VectorIndex, embedder Embedder, d
 			stats.Embedded++
 		}
 	}
+	idx.Model = model
 
 	return stats, nil
 }
@@ -0,0 +1,457 @@
+package search
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+const (
+	lexicalIndexMagic = "BVLI"
+	// Bumped whenever Stem changes, so stored terms are rebuilt.
+	lexicalIndexVersion = uint16(2)
+)
+
+// LexicalField is one of the separately weighted fields of a lexical document.
+type LexicalField int
+
+const (
+	FieldTitle LexicalField = iota // Title and ID
+	FieldLabels
+	FieldDescription
+	FieldComments
+	numLexicalFields
+)
+
+// BM25FParams tunes BM25F scoring. Weight scales a field's term frequencies
+// and B controls how strongly its length is normalized.
+type BM25FParams struct {
+	K1     float64
+	Weight [numLexicalFields]float64
+	B      [numLexicalFields]float64
+}
+
+// DefaultBM25FParams ranks title matches above labels, labels above the
+// description and the description above comments.
+var DefaultBM25FParams = BM25FParams{
+	K1:     1.2,
+	Weight: [numLexicalFields]float64{FieldTitle: 3.0, FieldLabels: 2.0, FieldDescription: 1.0, FieldComments: 0.5},
+	B:      [numLexicalFields]float64{FieldTitle: 0.5, FieldLabels: 0.3, FieldDescription: 0.75, FieldComments: 0.75},
+}
+
+// LexicalDocument is the fielded text of one issue for BM25F indexing.
+type LexicalDocument struct {
+	Fields [numLexicalFields]string
+}
+
+// Hash identifies the document content for incremental syncs.
+func (d LexicalDocument) Hash() ContentHash {
+	return ComputeContentHash(strings.Join(d.Fields[:], "\x00"))
+}
+
+// LexicalDocumentFromIssue splits an issue into BM25F fields.
+func LexicalDocumentFromIssue(issue model.Issue) LexicalDocument {
+	var d LexicalDocument
+	d.Fields[FieldTitle] = strings.TrimSpace(issue.ID + " " + issue.Title)
+	d.Fields[FieldLabels] = strings.Join(issue.Labels, " ")
+	d.Fields[FieldDescription] = strings.TrimSpace(issue.Description)
+	var comments []string
+	for _, c := range issue.Comments {
+		if c != nil && c.Text != "" {
+			comments = append(comments, c.Text)
+		}
+	}
+	d.Fields[FieldComments] = strings.Join(comments, "\n")
+	return d
+}
+
+// LexicalDocumentsFromIssues builds an ID->document map suitable for
+// SyncLexicalIndex.
+func LexicalDocumentsFromIssues(issues []model.Issue) map[string]LexicalDocument {
+	docs := make(map[string]LexicalDocument, len(issues))
+	for _, issue := range issues {
+		if issue.ID == "" {
+			continue
+		}
+		docs[issue.ID] = LexicalDocumentFromIssue(issue)
+	}
+	return docs
+}
+
+// DefaultLexicalIndexPath returns the lexical index path next to the vector
+// indexes under the given project directory.
+func DefaultLexicalIndexPath(projectDir string) string {
+	return filepath.Join(projectDir, ".bv", "semantic", "lexical.bvli")
+}
+
+type lexicalEntry struct {
+	hash   ContentHash
+	terms  [numLexicalFields]map[string]uint32 // Term -> frequency
+	length [numLexicalFields]uint32            // Tokens per field
+}
+
+// LexicalIndex is an inverted index over fielded issue documents, scored
+// with BM25F. It keeps per-document term frequencies, so documents can be
+// replaced or removed individually, and rebuilds postings from them on load.
+type LexicalIndex struct {
+	Params BM25FParams
+
+	mu       sync.RWMutex
+	docs     map[string]*lexicalEntry
+	postings map[string]map[string]struct{} // Term -> document IDs
+	totalLen [numLexicalFields]uint64
+}
+
+func NewLexicalIndex() *LexicalIndex {
+	return &LexicalIndex{
+		Params:   DefaultBM25FParams,
+		docs:     make(map[string]*lexicalEntry),
+		postings: make(map[string]map[string]struct{}),
+	}
+}
+
+// Upsert tokenizes doc and replaces any previous version of issueID.
+func (idx *LexicalIndex) Upsert(issueID string, hash ContentHash, doc LexicalDocument) error {
+	if issueID == "" {
+		return fmt.Errorf("issue id cannot be empty")
+	}
+	e := &lexicalEntry{hash: hash}
+	for f := range e.terms {
+		tokens := Tokenize(doc.Fields[f])
+		e.terms[f] = make(map[string]uint32, len(tokens))
+		for _, t := range tokens {
+			e.terms[f][t]++
+		}
+		e.length[f] = uint32(len(tokens))
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.removeLocked(issueID)
+	idx.addLocked(issueID, e)
+	return nil
+}
+
+// Remove deletes issueID from the index.
+func (idx *LexicalIndex) Remove(issueID string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.removeLocked(issueID)
+}
+
+func (idx *LexicalIndex) addLocked(issueID string, e *lexicalEntry) {
+	idx.docs[issueID] = e
+	for f := range e.terms {
+		idx.totalLen[f] += uint64(e.length[f])
+		for t := range e.terms[f] {
+			ids := idx.postings[t]
+			if ids == nil {
+				ids = make(map[string]struct{})
+				idx.postings[t] = ids
+			}
+			ids[issueID] = struct{}{}
+		}
+	}
+}
+
+func (idx *LexicalIndex) removeLocked(issueID string) {
+	e, ok := idx.docs[issueID]
+	if !ok {
+		return
+	}
+	delete(idx.docs, issueID)
+	for f := range e.terms {
+		idx.totalLen[f] -= uint64(e.length[f])
+		for t := range e.terms[f] {
+			if ids := idx.postings[t]; ids != nil {
+				delete(ids, issueID)
+				if len(ids) == 0 {
+					delete(idx.postings, t)
+				}
+			}
+		}
+	}
+}
+
+// Hash returns the content hash stored for issueID.
+func (idx *LexicalIndex) Hash(issueID string) (ContentHash, bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	e, ok := idx.docs[issueID]
+	if !ok {
+		return ContentHash{}, false
+	}
+	return e.hash, true
+}
+
+func (idx *LexicalIndex) Size() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.docs)
+}
+
+// Search returns the k best BM25F matches for query, best first. Documents
+// matching no query term are not returned.
+func (idx *LexicalIndex) Search(query string, k int) []SearchResult {
+	if k <= 0 {
+		return nil
+	}
+	terms := uniqueStrings(Tokenize(query))
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	n := float64(len(idx.docs))
+	if n == 0 || len(terms) == 0 {
+		return nil
+	}
+	p := idx.Params
+	var avgLen [numLexicalFields]float64
+	for f := range avgLen {
+		avgLen[f] = float64(idx.totalLen[f]) / n
+	}
+
+	scores := make(map[string]float64)
+	for _, t := range terms {
+		ids := idx.postings[t]
+		if len(ids) == 0 {
+			continue
+		}
+		df := float64(len(ids))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for id := range ids {
+			e := idx.docs[id]
+			var tf float64
+			for f := range e.terms {
+				freq := e.terms[f][t]
+				if freq == 0 || avgLen[f] == 0 {
+					continue
+				}
+				norm := 1 - p.B[f] + p.B[f]*float64(e.length[f])/avgLen[f]
+				tf += p.Weight[f] * float64(freq) / norm
+			}
+			scores[id] += idf * tf / (p.K1 + tf)
+		}
+	}
+
+	results := make([]SearchResult, 0, len(scores))
+	for id, score := range scores {
+		results = append(results, SearchResult{IssueID: id, Score: score})
+	}
+	sortSearchResults(results)
+	if len(results) > k {
+		results = results[:k]
+	}
+	return results
+}
+
+// SyncLexicalIndex updates idx to match docs, re-tokenizing only documents
+// whose content hash changed, like SyncVectorIndex.
+func SyncLexicalIndex(ctx context.Context, idx *LexicalIndex, docs map[string]LexicalDocument) (IndexSyncStats, error) {
+	var stats IndexSyncStats
+	if idx == nil {
+		return stats, fmt.Errorf("index cannot be nil")
+	}
+	stats.Total = len(docs)
+
+	for _, id := range idx.sortedIDs() {
+		if _, ok := docs[id]; !ok {
+			idx.Remove(id)
+			stats.Removed++
+		}
+	}
+
+	for _, id := range sortedKeys(docs) {
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+		doc := docs[id]
+		hash := doc.Hash()
+		existing, ok := idx.Hash(id)
+		if ok && existing == hash {
+			stats.Skipped++
+			continue
+		}
+		if ok {
+			stats.Updated++
+		} else {
+			stats.Added++
+		}
+		if err := idx.Upsert(id, hash, doc); err != nil {
+			return stats, err
+		}
+	}
+	return stats, nil
+}
+
+// LoadOrNewLexicalIndex loads an existing lexical index if present, otherwise
+// creates a new one. A corrupt index is backed up and replaced, as with
+// LoadOrNewVectorIndex.
+func LoadOrNewLexicalIndex(path string) (*LexicalIndex, bool, error) {
+	idx, err := LoadLexicalIndex(path)
+	if err == nil {
+		return idx, true, nil
+	}
+	if os.IsNotExist(err) {
+		return NewLexicalIndex(), false, nil
+	}
+	backupPath := path + ".corrupt-" + fmt.Sprintf("%d", time.Now().Unix())
+	if renameErr := os.Rename(path, backupPath); renameErr == nil {
+		return NewLexicalIndex(), false, nil
+	}
+	return nil, false, fmt.Errorf("load lexical index (and backup failed): %w", err)
+}
+
+func LoadLexicalIndex(path string) (*LexicalIndex, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	r := bufio.NewReader(f)
+	var magic [4]byte
+	if _, err := io.ReadFull(r, magic[:]); err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	if string(magic[:]) != lexicalIndexMagic {
+		return nil, fmt.Errorf("invalid magic %q", string(magic[:]))
+	}
+	var header struct {
+		Version  uint16
+		Reserved uint16
+		Count    uint32
+	}
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	if header.Version != lexicalIndexVersion {
+		return nil, fmt.Errorf("unsupported version %d", header.Version)
+	}
+
+	idx := NewLexicalIndex()
+	for i := uint32(0); i < header.Count; i++ {
+		id, err := readString16(r)
+		if err != nil || id == "" {
+			return nil, fmt.Errorf("read id: %v", err)
+		}
+		e := &lexicalEntry{}
+		if _, err := io.ReadFull(r, e.hash[:]); err != nil {
+			return nil, fmt.Errorf("read content hash: %w", err)
+		}
+		for f := range e.terms {
+			var counts [2]uint32 // Field length, distinct terms
+			if err := binary.Read(r, binary.LittleEndian, &counts); err != nil {
+				return nil, fmt.Errorf("read field: %w", err)
+			}
+			e.length[f] = counts[0]
+			e.terms[f] = make(map[string]uint32, counts[1])
+			for j := uint32(0); j < counts[1]; j++ {
+				term, err := readString16(r)
+				if err != nil {
+					return nil, fmt.Errorf("read term: %w", err)
+				}
+				var freq uint32
+				if err := binary.Read(r, binary.LittleEndian, &freq); err != nil {
+					return nil, fmt.Errorf("read term frequency: %w", err)
+				}
+				e.terms[f][term] = freq
+			}
+		}
+		idx.addLocked(id, e)
+	}
+	return idx, nil
+}
+
+func (idx *LexicalIndex) Save(path string) error {
+	ids := idx.sortedIDs()
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	return saveAtomically(path, "bvli-*.tmp", func(w *bufio.Writer) error {
+		if _, err := w.WriteString(lexicalIndexMagic); err != nil {
+			return fmt.Errorf("write magic: %w", err)
+		}
+		header := struct {
+			Version  uint16
+			Reserved uint16
+			Count    uint32
+		}{lexicalIndexVersion, 0, uint32(len(ids))}
+		if err := binary.Write(w, binary.LittleEndian, header); err != nil {
+			return fmt.Errorf("write header: %w", err)
+		}
+		for _, id := range ids {
+			e := idx.docs[id]
+			if err := writeString16(w, id); err != nil {
+				return fmt.Errorf("write id: %w", err)
+			}
+			if _, err := w.Write(e.hash[:]); err != nil {
+				return fmt.Errorf("write content hash: %w", err)
+			}
+			for f := range e.terms {
+				counts := [2]uint32{e.length[f], uint32(len(e.terms[f]))}
+				if err := binary.Write(w, binary.LittleEndian, counts); err != nil {
+					return fmt.Errorf("write field: %w", err)
+				}
+				for _, term := range sortedKeys(e.terms[f]) {
+					if err := writeString16(w, term); err != nil {
+						return fmt.Errorf("write term: %w", err)
+					}
+					if err := binary.Write(w, binary.LittleEndian, e.terms[f][term]); err != nil {
+						return fmt.Errorf("write term frequency: %w", err)
+					}
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (idx *LexicalIndex) sortedIDs() []string {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return sortedKeys(idx.docs)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	out := values[:0:0]
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// sortSearchResults orders results by descending score, then by ID.
+func sortSearchResults(results []SearchResult) {
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score == results[j].Score {
+			return results[i].IssueID < results[j].IssueID
+		}
+		return results[i].Score > results[j].Score
+	})
+}
@@ -0,0 +1,160 @@
+package search
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize("The Caching layer cached 3 entries; caches are FLAKY-tests!")
+	want := []string{"cach", "layer", "cach", "3", "entry", "cach", "flaky", "test"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Tokenize = %v, want %v", got, want)
+	}
+}
+
+func TestStem(t *testing.T) {
+	groups := [][]string{
+		{"test", "tests", "testing", "tested"},
+		{"stop", "stops", "stopped", "stopping"},
+		{"hope", "hoping", "hoped"},
+		{"use", "used", "uses", "using"},
+		{"age", "aged", "ages", "aging"},
+		{"retry", "retries", "retrying", "retried"},
+		{"agree", "agreed", "agreeing", "agrees"},
+		{"free", "freed", "freeing", "frees"},
+		{"need", "needs", "needed", "needing"},
+		{"proceed", "proceeds", "proceeded", "proceeding"},
+		{"speed", "speeds", "speeding"},
+		{"cache", "cached", "caching", "caches"},
+		{"deploy", "deployment", "deployments"},
+	}
+	for _, g := range groups {
+		stem := Stem(g[0])
+		for _, w := range g[1:] {
+			if got := Stem(w); got != stem {
+				t.Errorf("Stem(%q) = %q, want %q like %q", w, got, stem, g[0])
+			}
+		}
+	}
+	for _, w := range []string{"bug", "api", "status", "über"} {
+		if got := Stem(w); got != w {
+			t.Errorf("Stem(%q) = %q, want unchanged", w, got)
+		}
+	}
+}
+
+func TestLexicalIndexFieldWeights(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "T-1", Title: "Timeout in exporter"},
+		{ID: "L-1", Title: "Exporter cleanup", Labels: []string{"timeout"}},
+		{ID: "D-1", Title: "Exporter cleanup", Description: "Sometimes hits a timeout"},
+		{ID: "C-1", Title: "Exporter cleanup", Comments: []*model.Comment{{Text: "saw a timeout here"}}},
+		{ID: "N-1", Title: "Unrelated work"},
+	}
+	idx := NewLexicalIndex()
+	if _, err := SyncLexicalIndex(context.Background(), idx, LexicalDocumentsFromIssues(issues)); err != nil {
+		t.Fatalf("SyncLexicalIndex: %v", err)
+	}
+
+	results := idx.Search("timeouts", 10)
+	var ids []string
+	for _, r := range results {
+		ids = append(ids, r.IssueID)
+	}
+	want := []string{"T-1", "L-1", "D-1", "C-1"}
+	if !reflect.DeepEqual(ids, want) {
+		t.Fatalf("ranking = %v, want %v", ids, want)
+	}
+
+	if got := idx.Search("t-1", 1); len(got) != 1 || got[0].IssueID != "T-1" {
+		t.Fatalf("expected ID match, got %v", got)
+	}
+	if got := idx.Search("the", 10); len(got) != 0 {
+		t.Fatalf("stopword-only query should match nothing, got %v", got)
+	}
+}
+
+func TestSyncLexicalIndexPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lexical.bvli")
+	docs := LexicalDocumentsFromIssues([]model.Issue{
+		{ID: "A", Title: "Fix login flow", Description: "OAuth redirect"},
+		{ID: "B", Title: "Update docs", Labels: []string{"docs"}},
+	})
+
+	idx, loaded, err := LoadOrNewLexicalIndex(path)
+	if err != nil || loaded {
+		t.Fatalf("LoadOrNewLexicalIndex: loaded=%v err=%v", loaded, err)
+	}
+	stats, err := SyncLexicalIndex(context.Background(), idx, docs)
+	if err != nil || stats.Added != 2 {
+		t.Fatalf("initial sync: %+v, %v", stats, err)
+	}
+	if err := idx.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	reloaded, loaded, err := LoadOrNewLexicalIndex(path)
+	if err != nil || !loaded || reloaded.Size() != 2 {
+		t.Fatalf("reload: loaded=%v size=%d err=%v", loaded, reloaded.Size(), err)
+	}
+	if !reflect.DeepEqual(reloaded.Search("oauth login", 5), idx.Search("oauth login", 5)) {
+		t.Fatalf("reloaded index ranks differently")
+	}
+
+	stats, err = SyncLexicalIndex(context.Background(), reloaded, docs)
+	if err != nil || stats.Skipped != 2 || stats.Changed() {
+		t.Fatalf("unchanged docs should be skipped: %+v, %v", stats, err)
+	}
+
+	delete(docs, "B")
+	a := docs["A"]
+	a.Fields[FieldDescription] = "PKCE verifier"
+	docs["A"] = a
+	stats, err = SyncLexicalIndex(context.Background(), reloaded, docs)
+	if err != nil || stats.Updated != 1 || stats.Removed != 1 {
+		t.Fatalf("unexpected stats: %+v, %v", stats, err)
+	}
+	if got := reloaded.Search("oauth docs", 5); len(got) != 0 {
+		t.Fatalf("stale terms still indexed: %v", got)
+	}
+	if got := reloaded.Search("pkce", 5); len(got) != 1 || got[0].IssueID != "A" {
+		t.Fatalf("updated terms not indexed: %v", got)
+	}
+
+	// A corrupt file is backed up and replaced with an empty index.
+	if err := os.WriteFile(path, []byte("garbage"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	fresh, loaded, err := LoadOrNewLexicalIndex(path)
+	if err != nil || loaded || fresh.Size() != 0 {
+		t.Fatalf("corrupt index: loaded=%v err=%v", loaded, err)
+	}
+}
+
+func TestFuseRRF(t *testing.T) {
+	lexical := []SearchResult{{IssueID: "A", Score: 12}, {IssueID: "B", Score: 9}, {IssueID: "C", Score: 1}}
+	vector := []SearchResult{{IssueID: "B", Score: 0.9}, {IssueID: "D", Score: 0.8}, {IssueID: "A", Score: 0.1}}
+
+	fused := FuseRRF(DefaultRRFK, lexical, vector)
+	var ids []string
+	for _, r := range fused {
+		ids = append(ids, r.IssueID)
+	}
+	if want := []string{"B", "A", "D", "C"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("fused order = %v, want %v", ids, want)
+	}
+	if fused[0].Score <= 0 || fused[0].Score > 1 {
+		t.Fatalf("score not normalized: %v", fused[0].Score)
+	}
+
+	top := FuseRRF(0, []SearchResult{{IssueID: "X"}}, []SearchResult{{IssueID: "X"}})
+	if len(top) != 1 || top[0].Score != 1 {
+		t.Fatalf("first in every list should score 1, got %v", top)
+	}
+}
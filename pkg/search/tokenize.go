@@ -0,0 +1,130 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// stopwords are dropped from lexical documents and queries.
+var stopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true,
+	"but": true, "by": true, "for": true, "from": true, "has": true, "have": true, "if": true,
+	"in": true, "into": true, "is": true, "it": true, "its": true, "of": true, "on": true,
+	"or": true, "so": true, "that": true, "the": true, "their": true, "then": true, "there": true,
+	"these": true, "this": true, "to": true, "was": true, "were": true, "when": true, "which": true,
+	"while": true, "will": true, "with": true,
+}
+
+// eedWords end in "eed" without being the past tense of an "-ee" verb.
+// Four-letter words ("need", "seed") and "-ceed" words are excluded anyway.
+var eedWords = map[string]bool{
+	"bleed": true, "breed": true, "greed": true, "indeed": true, "speed": true, "steed": true, "tweed": true,
+}
+
+// Tokenize lower-cases text, splits it on anything but letters and digits,
+// drops stopwords and stems what remains.
+func Tokenize(text string) []string {
+	var tokens []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if stopwords[word] {
+			continue
+		}
+		tokens = append(tokens, Stem(word))
+	}
+	return tokens
+}
+
+// Stem reduces an English word to a search stem by stripping common
+// inflections (plurals, -ed, -ing, -ly, a final -e and a few derivational
+// suffixes), loosely following the Porter stemmer. It is deliberately
+// conservative: "tests", "testing" and "tested" share a stem, but short
+// words and words without vowels in the remaining stem are left alone.
+func Stem(word string) string {
+	if len(word) <= 3 || !isASCIIWord(word) {
+		return word
+	}
+
+	// Plurals.
+	switch {
+	case strings.HasSuffix(word, "sses"):
+		word = word[:len(word)-2]
+	case strings.HasSuffix(word, "ies"):
+		word = word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "ss"), strings.HasSuffix(word, "us"), strings.HasSuffix(word, "is"):
+	case strings.HasSuffix(word, "s"):
+		word = word[:len(word)-1]
+	}
+
+	// Past tenses the suffix loop below cannot undo: "retried" -> "retry"
+	// like "retries", and "agreed" -> "agree" like "agreeing".
+	switch {
+	case strings.HasSuffix(word, "ied"):
+		word = word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "eed") && len(word) > 4 && !strings.HasSuffix(word, "ceed") && !eedWords[word]:
+		word = word[:len(word)-1]
+	}
+
+	// Past tense and gerunds, undoing a doubled consonant ("stopped" ->
+	// "stop") or restoring the "e" of short words ("hoping" -> "hope",
+	// "used" -> "use").
+	for _, suffix := range []string{"ing", "ed"} {
+		if !strings.HasSuffix(word, suffix) || strings.HasSuffix(word, "eed") {
+			continue
+		}
+		stem := word[:len(word)-len(suffix)]
+		if len(stem) == 2 && isVowel(stem[0]) && !isVowel(stem[1]) {
+			stem += "e"
+		}
+		if len(stem) < 3 || !hasVowel(stem) {
+			break
+		}
+		n := len(stem)
+		switch {
+		case stem[n-1] == stem[n-2] && !strings.ContainsRune("lsz", rune(stem[n-1])) && !isVowel(stem[n-1]):
+			stem = stem[:n-1]
+		case n == 3 && !isVowel(stem[0]) && isVowel(stem[1]) && !isVowel(stem[2]) && !strings.ContainsRune("wxy", rune(stem[2])):
+			stem += "e"
+		}
+		word = stem
+		break
+	}
+
+	for _, r := range []struct{ suffix, replacement string }{
+		{"ational", "ate"}, {"ization", "ize"}, {"fulness", "ful"}, {"iveness", "ive"},
+		{"ement", ""}, {"ment", ""}, {"ness", ""}, {"ation", "ate"}, {"ly", ""},
+	} {
+		if strings.HasSuffix(word, r.suffix) {
+			stem := word[:len(word)-len(r.suffix)] + r.replacement
+			if len(stem) >= 3 && hasVowel(stem) {
+				word = stem
+			}
+			break
+		}
+	}
+
+	// A final "e" is dropped from longer words so "cache", "cached" and
+	// "caching" meet at "cach".
+	if len(word) > 4 && strings.HasSuffix(word, "e") {
+		word = word[:len(word)-1]
+	}
+	return word
+}
+
+func isASCIIWord(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 'a' || s[i] > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+func isVowel(c byte) bool {
+	return strings.IndexByte("aeiou", c) >= 0
+}
+
+func hasVowel(s string) bool {
+	return strings.ContainsAny(s, "aeiouy")
+}
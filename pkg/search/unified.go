@@ -0,0 +1,408 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/cass"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/correlation"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// DocKind identifies what a search document was built from.
+type DocKind string
+
+const (
+	KindIssue   DocKind = "issue"   // Issue title, labels and description
+	KindComment DocKind = "comment" // One issue comment
+	KindCommit  DocKind = "commit"  // A commit message correlated with beads
+	KindSession DocKind = "session" // A cass agent session snippet mentioning a bead
+)
+
+// AllDocKinds lists every document kind in display order.
+var AllDocKinds = []DocKind{KindIssue, KindComment, KindCommit, KindSession}
+
+// ParseDocKinds parses a comma-separated kind list such as "issue,commit".
+// "all" selects every kind; plurals ("comments") are accepted.
+func ParseDocKinds(s string) ([]DocKind, error) {
+	seen := make(map[DocKind]bool)
+	for _, part := range strings.Split(s, ",") {
+		name := strings.ToLower(strings.TrimSpace(part))
+		if name == "" {
+			continue
+		}
+		if name == "all" {
+			return AllDocKinds, nil
+		}
+		kind := DocKind(strings.TrimSuffix(name, "s"))
+		valid := false
+		for _, k := range AllDocKinds {
+			valid = valid || k == kind
+		}
+		if !valid {
+			return nil, fmt.Errorf("unknown search kind %q (expected issue, comment, commit, session or all)", part)
+		}
+		seen[kind] = true
+	}
+	if len(seen) == 0 {
+		return nil, fmt.Errorf("no search kinds given")
+	}
+	var kinds []DocKind
+	for _, k := range AllDocKinds {
+		if seen[k] {
+			kinds = append(kinds, k)
+		}
+	}
+	return kinds, nil
+}
+
+// FormatDocKinds renders kinds as "issue+comment", for status lines.
+func FormatDocKinds(kinds []DocKind) string {
+	names := make([]string, len(kinds))
+	for i, k := range kinds {
+		names[i] = string(k)
+	}
+	return strings.Join(names, "+")
+}
+
+// IsIssueOnly reports whether kinds is the default issue-only search.
+func IsIssueOnly(kinds []DocKind) bool {
+	return len(kinds) == 0 || (len(kinds) == 1 && kinds[0] == KindIssue)
+}
+
+// SearchDocument is a searchable text owned by one or more beads.
+type SearchDocument struct {
+	Key     string   // Unique index key; the issue ID for KindIssue
+	Kind    DocKind  // What the document was built from
+	BeadIDs []string // Owning beads, most relevant first
+	Title   string   // Short label such as the commit subject
+	Text    string   // Text to embed
+	Excerpt string   // Display text for hits
+}
+
+// IssueSearchDocuments returns one KindIssue document per issue.
+func IssueSearchDocuments(issues []model.Issue) []SearchDocument {
+	docs := make([]SearchDocument, 0, len(issues))
+	for _, issue := range issues {
+		if issue.ID == "" {
+			continue
+		}
+		docs = append(docs, SearchDocument{
+			Key:     issue.ID,
+			Kind:    KindIssue,
+			BeadIDs: []string{issue.ID},
+			Title:   issue.Title,
+			Text:    IssueDocument(issue),
+			Excerpt: issue.Description,
+		})
+	}
+	return docs
+}
+
+// CommentDocuments returns one document per non-empty issue comment.
+func CommentDocuments(issues []model.Issue) []SearchDocument {
+	var docs []SearchDocument
+	for _, issue := range issues {
+		for i, c := range issue.Comments {
+			if issue.ID == "" || c == nil || strings.TrimSpace(c.Text) == "" {
+				continue
+			}
+			id := fmt.Sprint(c.ID)
+			if c.ID == 0 {
+				id = fmt.Sprintf("#%d", i)
+			}
+			title := "comment"
+			if c.Author != "" {
+				title = "comment by " + c.Author
+			}
+			docs = append(docs, SearchDocument{
+				Key:     fmt.Sprintf("comment:%s:%s", issue.ID, id),
+				Kind:    KindComment,
+				BeadIDs: []string{issue.ID},
+				Title:   title,
+				Text:    c.Text,
+				Excerpt: c.Text,
+			})
+		}
+	}
+	return docs
+}
+
+// CommitDocuments returns one document per correlated commit. A commit
+// correlated with several beads is indexed once and owned by all of them,
+// highest correlation confidence first.
+func CommitDocuments(report *correlation.HistoryReport) []SearchDocument {
+	if report == nil {
+		return nil
+	}
+	type owner struct {
+		bead       string
+		confidence float64
+	}
+	owners := make(map[string][]owner)
+	commits := make(map[string]correlation.CorrelatedCommit)
+	for beadID, history := range report.Histories {
+		for _, c := range history.Commits {
+			if c.SHA == "" || strings.TrimSpace(c.Message) == "" {
+				continue
+			}
+			owners[c.SHA] = append(owners[c.SHA], owner{beadID, c.Confidence})
+			commits[c.SHA] = c
+		}
+	}
+
+	docs := make([]SearchDocument, 0, len(commits))
+	for _, sha := range sortedKeys(commits) {
+		c := commits[sha]
+		byConfidence := owners[sha]
+		sort.Slice(byConfidence, func(i, j int) bool {
+			if byConfidence[i].confidence == byConfidence[j].confidence {
+				return byConfidence[i].bead < byConfidence[j].bead
+			}
+			return byConfidence[i].confidence > byConfidence[j].confidence
+		})
+		beads := make([]string, len(byConfidence))
+		for i, o := range byConfidence {
+			beads[i] = o.bead
+		}
+		short := c.ShortSHA
+		if short == "" && len(sha) >= 7 {
+			short = sha[:7]
+		}
+		subject, body, _ := strings.Cut(strings.TrimSpace(c.Message), "\n")
+		docs = append(docs, SearchDocument{
+			Key:     "commit:" + sha,
+			Kind:    KindCommit,
+			BeadIDs: beads,
+			Title:   strings.TrimSpace(short + " " + subject),
+			Text:    c.Message,
+			Excerpt: strings.TrimSpace(body),
+		})
+	}
+	return docs
+}
+
+var beadMentionPattern = regexp.MustCompile(`[A-Za-z][A-Za-z0-9_]*-[A-Za-z0-9][A-Za-z0-9.]*`)
+
+// SessionDocuments turns cass search hits into documents owned by the beads
+// they mention. Hits that mention no known bead cannot be linked back to an
+// issue and are dropped.
+func SessionDocuments(results []cass.SearchResult, issueIDs map[string]bool) []SearchDocument {
+	var docs []SearchDocument
+	seen := make(map[string]bool)
+	for _, r := range results {
+		var beads []string
+		mentioned := make(map[string]bool)
+		for _, m := range beadMentionPattern.FindAllString(r.Title+"\n"+r.Snippet, -1) {
+			m = strings.TrimRight(m, ".")
+			if issueIDs[m] && !mentioned[m] {
+				mentioned[m] = true
+				beads = append(beads, m)
+			}
+		}
+		key := fmt.Sprintf("session:%s:%d", r.SourcePath, r.LineNumber)
+		if len(beads) == 0 || seen[key] || strings.TrimSpace(r.Snippet) == "" {
+			continue
+		}
+		seen[key] = true
+		title := r.Title
+		if r.Agent != "" {
+			title = strings.TrimSpace(r.Agent + ": " + title)
+		}
+		docs = append(docs, SearchDocument{
+			Key:     key,
+			Kind:    KindSession,
+			BeadIDs: beads,
+			Title:   title,
+			Text:    r.Snippet,
+			Excerpt: r.Snippet,
+		})
+	}
+	return docs
+}
+
+// DocumentTexts builds a key->text map suitable for SyncVectorIndex.
+func DocumentTexts(docs []SearchDocument) map[string]string {
+	texts := make(map[string]string, len(docs))
+	for _, d := range docs {
+		texts[d.Key] = d.Text
+	}
+	return texts
+}
+
+// KindIndexPath returns the vector index path for documents of kind. Issues
+// use DefaultIndexPath; other kinds get their own file beside it so that
+// searching one kind never churns another kind's index.
+func KindIndexPath(projectDir string, cfg EmbeddingConfig, kind DocKind) string {
+	path := DefaultIndexPath(projectDir, cfg)
+	if kind == KindIssue {
+		return path
+	}
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "-" + string(kind) + ext
+}
+
+// SyncKindIndex loads the index for kind, brings it up to date with docs
+// and saves it when anything changed.
+func SyncKindIndex(ctx context.Context, projectDir string, cfg EmbeddingConfig, embedder Embedder, kind DocKind, docs []SearchDocument) (*VectorIndex, IndexSyncStats, error) {
+	path := KindIndexPath(projectDir, cfg, kind)
+	idx, loaded, err := LoadOrNewVectorIndex(path, embedder.Dim())
+	if err != nil {
+		return nil, IndexSyncStats{}, err
+	}
+	annBuilt := idx.SetHNSWConfig(HNSWConfigFromEnv())
+	stats, err := SyncVectorIndex(ctx, idx, embedder, DocumentTexts(docs), 64)
+	if err != nil {
+		return nil, stats, fmt.Errorf("building %s index: %w", kind, err)
+	}
+	if !loaded || stats.Changed() || annBuilt {
+		if err := idx.Save(path); err != nil {
+			return nil, stats, fmt.Errorf("saving %s index: %w", kind, err)
+		}
+	}
+	return idx, stats, nil
+}
+
+// ScoreDocuments embeds docs and scores them against query. It is meant for
+// small per-query document sets, such as session snippets, that are not
+// worth persisting.
+func ScoreDocuments(ctx context.Context, embedder Embedder, query []float32, docs []SearchDocument) ([]SearchResult, error) {
+	if len(docs) == 0 {
+		return nil, nil
+	}
+	texts := make([]string, len(docs))
+	for i, d := range docs {
+		texts[i] = d.Text
+	}
+	vecs, err := embedder.Embed(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	if len(vecs) != len(docs) {
+		return nil, fmt.Errorf("embedder returned %d vectors for %d documents", len(vecs), len(docs))
+	}
+	results := make([]SearchResult, len(docs))
+	for i, d := range docs {
+		results[i] = SearchResult{IssueID: d.Key, Score: dotFloat32(query, vecs[i])}
+	}
+	sortSearchResults(results)
+	return results, nil
+}
+
+// MergeRankings combines rankings whose scores share a scale (e.g. cosine
+// similarities from several indexes) into one list sorted best first. A key
+// present in several rankings keeps its best score.
+func MergeRankings(rankings ...[]SearchResult) []SearchResult {
+	best := make(map[string]float64)
+	for _, ranking := range rankings {
+		for _, r := range ranking {
+			if s, ok := best[r.IssueID]; !ok || r.Score > s {
+				best[r.IssueID] = r.Score
+			}
+		}
+	}
+	merged := make([]SearchResult, 0, len(best))
+	for key, score := range best {
+		merged = append(merged, SearchResult{IssueID: key, Score: score})
+	}
+	sortSearchResults(merged)
+	return merged
+}
+
+// AddLexicalDocuments indexes non-issue documents into idx for a single
+// fusion query. Titles and texts map onto the title and description fields.
+func AddLexicalDocuments(idx *LexicalIndex, docs []SearchDocument) error {
+	for _, d := range docs {
+		if d.Kind == KindIssue {
+			continue
+		}
+		var ld LexicalDocument
+		ld.Fields[FieldTitle] = d.Title
+		ld.Fields[FieldDescription] = d.Text
+		if err := idx.Upsert(d.Key, ld.Hash(), ld); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SearchHit is one matching document and the bead it links back to.
+type SearchHit struct {
+	Key     string   `json:"key"`
+	Kind    DocKind  `json:"kind"`
+	BeadID  string   `json:"bead_id"`
+	BeadIDs []string `json:"bead_ids,omitempty"` // Set when several beads own the document
+	Title   string   `json:"title,omitempty"`
+	Snippet string   `json:"snippet,omitempty"`
+	Score   float64  `json:"score"`
+}
+
+// NewSearchHit describes doc as a hit with the given score.
+func NewSearchHit(doc SearchDocument, score float64) SearchHit {
+	hit := SearchHit{
+		Key:     doc.Key,
+		Kind:    doc.Kind,
+		Title:   doc.Title,
+		Snippet: Snippet(doc.Excerpt, 160),
+		Score:   score,
+	}
+	if len(doc.BeadIDs) > 0 {
+		hit.BeadID = doc.BeadIDs[0]
+	}
+	if len(doc.BeadIDs) > 1 {
+		hit.BeadIDs = doc.BeadIDs
+	}
+	return hit
+}
+
+// BeadHits groups the hits that link to one bead, best first.
+type BeadHits struct {
+	BeadID string
+	Score  float64 // Best hit score
+	Hits   []SearchHit
+}
+
+// GroupHitsByBead collects hits under every bead that owns them and orders
+// the groups by their best hit. Hits must already be sorted best first.
+func GroupHitsByBead(hits []SearchHit) []BeadHits {
+	index := make(map[string]int)
+	var groups []BeadHits
+	for _, h := range hits {
+		owners := h.BeadIDs
+		if len(owners) == 0 {
+			owners = []string{h.BeadID}
+		}
+		for _, bead := range owners {
+			i, ok := index[bead]
+			if !ok {
+				i = len(groups)
+				index[bead] = i
+				groups = append(groups, BeadHits{BeadID: bead, Score: h.Score})
+			}
+			groups[i].Hits = append(groups[i].Hits, h)
+			if h.Score > groups[i].Score {
+				groups[i].Score = h.Score
+			}
+		}
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Score == groups[j].Score {
+			return groups[i].BeadID < groups[j].BeadID
+		}
+		return groups[i].Score > groups[j].Score
+	})
+	return groups
+}
+
+// Snippet collapses whitespace in text and truncates it to max runes.
+func Snippet(text string, max int) string {
+	s := strings.Join(strings.Fields(text), " ")
+	if r := []rune(s); len(r) > max {
+		return strings.TrimSpace(string(r[:max-1])) + "…"
+	}
+	return s
+}
@@ -0,0 +1,179 @@
+package search
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/cass"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/correlation"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestParseDocKinds(t *testing.T) {
+	cases := map[string][]DocKind{
+		"issue":                {KindIssue},
+		"commits, Comment":     {KindComment, KindCommit},
+		"session,issue,issues": {KindIssue, KindSession},
+		"all":                  AllDocKinds,
+	}
+	for in, want := range cases {
+		got, err := ParseDocKinds(in)
+		if err != nil {
+			t.Fatalf("ParseDocKinds(%q): %v", in, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ParseDocKinds(%q) = %v, want %v", in, got, want)
+		}
+	}
+	for _, in := range []string{"", " , ", "tickets", "issue,wiki"} {
+		if _, err := ParseDocKinds(in); err == nil {
+			t.Errorf("ParseDocKinds(%q) succeeded, want error", in)
+		}
+	}
+	if !IsIssueOnly(nil) || !IsIssueOnly([]DocKind{KindIssue}) || IsIssueOnly([]DocKind{KindComment}) {
+		t.Error("IsIssueOnly misclassifies kind sets")
+	}
+}
+
+func TestCommentDocuments(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "A-1", Comments: []*model.Comment{
+			{ID: 7, Author: "ann", Text: "Redis drops the session"},
+			{Text: "   "},
+			nil,
+			{Text: "second thought"},
+		}},
+		{ID: "B-2"},
+	}
+	docs := CommentDocuments(issues)
+	if len(docs) != 2 {
+		t.Fatalf("got %d comment docs, want 2: %+v", len(docs), docs)
+	}
+	if docs[0].Key != "comment:A-1:7" || docs[0].Title != "comment by ann" || !reflect.DeepEqual(docs[0].BeadIDs, []string{"A-1"}) {
+		t.Errorf("unexpected first comment doc %+v", docs[0])
+	}
+	if docs[1].Key != "comment:A-1:#3" || docs[1].Kind != KindComment {
+		t.Errorf("comment without ID should be keyed by position, got %+v", docs[1])
+	}
+}
+
+func TestCommitDocumentsOwners(t *testing.T) {
+	report := &correlation.HistoryReport{Histories: map[string]correlation.BeadHistory{
+		"A-1": {BeadID: "A-1", Commits: []correlation.CorrelatedCommit{
+			{SHA: "aaaaaaaaaa", ShortSHA: "aaaaaaa", Message: "Fix token refresh\n\nRetry once on 401.", Confidence: 0.6},
+		}},
+		"B-2": {BeadID: "B-2", Commits: []correlation.CorrelatedCommit{
+			{SHA: "aaaaaaaaaa", ShortSHA: "aaaaaaa", Message: "Fix token refresh\n\nRetry once on 401.", Confidence: 0.9},
+			{SHA: "bbbbbbbbbb", Message: "   "},
+		}},
+	}}
+	docs := CommitDocuments(report)
+	if len(docs) != 1 {
+		t.Fatalf("got %d commit docs, want 1 (shared commit indexed once): %+v", len(docs), docs)
+	}
+	d := docs[0]
+	if d.Key != "commit:aaaaaaaaaa" || d.Title != "aaaaaaa Fix token refresh" || d.Excerpt != "Retry once on 401." {
+		t.Errorf("unexpected commit doc %+v", d)
+	}
+	if !reflect.DeepEqual(d.BeadIDs, []string{"B-2", "A-1"}) {
+		t.Errorf("owners = %v, want most confident first", d.BeadIDs)
+	}
+	if CommitDocuments(nil) != nil {
+		t.Error("nil report should yield no documents")
+	}
+}
+
+func TestSessionDocumentsLinkKnownBeads(t *testing.T) {
+	known := map[string]bool{"bv-12": true, "api-3": true}
+	results := []cass.SearchResult{
+		{SourcePath: "/s/1.jsonl", LineNumber: 4, Agent: "agent", Title: "Fixing bv-12", Snippet: "then api-3. also bv-99"},
+		{SourcePath: "/s/1.jsonl", LineNumber: 4, Title: "dup", Snippet: "bv-12 again"},
+		{SourcePath: "/s/2.jsonl", LineNumber: 1, Snippet: "nothing we track"},
+	}
+	docs := SessionDocuments(results, known)
+	if len(docs) != 1 {
+		t.Fatalf("got %d session docs, want 1: %+v", len(docs), docs)
+	}
+	if !reflect.DeepEqual(docs[0].BeadIDs, []string{"bv-12", "api-3"}) {
+		t.Errorf("owners = %v, want mentioned known beads in order", docs[0].BeadIDs)
+	}
+	if docs[0].Key != "session:/s/1.jsonl:4" || docs[0].Title != "agent: Fixing bv-12" {
+		t.Errorf("unexpected session doc %+v", docs[0])
+	}
+}
+
+func TestGroupHitsByBead(t *testing.T) {
+	hits := []SearchHit{
+		{Key: "commit:x", Kind: KindCommit, BeadID: "B", BeadIDs: []string{"B", "A"}, Score: 0.9},
+		{Key: "A", Kind: KindIssue, BeadID: "A", Score: 0.5},
+		{Key: "comment:C:1", Kind: KindComment, BeadID: "C", Score: 0.4},
+	}
+	groups := GroupHitsByBead(hits)
+	var order []string
+	for _, g := range groups {
+		order = append(order, g.BeadID)
+	}
+	if !reflect.DeepEqual(order, []string{"A", "B", "C"}) {
+		t.Fatalf("group order = %v, want [A B C] (A and B tie on the shared commit)", order)
+	}
+	if len(groups[0].Hits) != 2 || groups[0].Hits[0].Key != "commit:x" {
+		t.Errorf("bead A should hold the commit then its issue, got %+v", groups[0].Hits)
+	}
+}
+
+func TestMergeRankingsKeepsBestScore(t *testing.T) {
+	merged := MergeRankings(
+		[]SearchResult{{IssueID: "A", Score: 0.2}, {IssueID: "B", Score: 0.5}},
+		[]SearchResult{{IssueID: "A", Score: 0.7}, {IssueID: "c:1", Score: 0.1}},
+	)
+	want := []SearchResult{{IssueID: "A", Score: 0.7}, {IssueID: "B", Score: 0.5}, {IssueID: "c:1", Score: 0.1}}
+	if !reflect.DeepEqual(merged, want) {
+		t.Fatalf("MergeRankings = %v, want %v", merged, want)
+	}
+}
+
+func TestSyncKindIndexPersistsBesideIssueIndex(t *testing.T) {
+	dir := t.TempDir()
+	cfg := EmbeddingConfig{Provider: ProviderHash, Dim: 64}
+	embedder := NewHashEmbedder(64)
+	issues := []model.Issue{
+		{ID: "A-1", Comments: []*model.Comment{{ID: 1, Text: "redis evicts sessions under load"}}},
+		{ID: "B-2", Comments: []*model.Comment{{ID: 2, Text: "invoice totals round wrong"}}},
+	}
+
+	idx, stats, err := SyncKindIndex(context.Background(), dir, cfg, embedder, KindComment, CommentDocuments(issues))
+	if err != nil {
+		t.Fatalf("SyncKindIndex: %v", err)
+	}
+	if stats.Added != 2 || idx.Size() != 2 {
+		t.Fatalf("first sync stats = %+v, size %d", stats, idx.Size())
+	}
+	path := KindIndexPath(dir, cfg, KindComment)
+	if path == DefaultIndexPath(dir, cfg) || !strings.HasSuffix(path, "-comment"+filepath.Ext(path)) {
+		t.Fatalf("comment index path %q should sit beside the issue index", path)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("comment index not saved: %v", err)
+	}
+
+	_, stats, err = SyncKindIndex(context.Background(), dir, cfg, embedder, KindComment, CommentDocuments(issues))
+	if err != nil {
+		t.Fatalf("second SyncKindIndex: %v", err)
+	}
+	if stats.Changed() || stats.Skipped != 2 {
+		t.Errorf("second sync should reuse the saved index, got %+v", stats)
+	}
+
+	q, err := embedder.Embed(context.Background(), []string{"redis sessions"})
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	results, err := idx.SearchTopK(q[0], 1)
+	if err != nil || len(results) != 1 || results[0].IssueID != "comment:A-1:1" {
+		t.Errorf("SearchTopK = %v, %v; want the redis comment", results, err)
+	}
+}
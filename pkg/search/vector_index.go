@@ -19,7 +19,7 @@ import (
 
 const (
 	vectorIndexMagic   = "BVVI"
-	vectorIndexVersion = uint16(1)
+	vectorIndexVersion = uint16(3) // Version 2 adds the embedding model name, 3 the HNSW graph
 )
 
 type ContentHash [32]byte
@@ -51,12 +51,15 @@ type VectorEntry struct {
 }
 
 type VectorIndex struct {
-	Dim int
+	Dim   int
+	Model string // Embedding model the vectors came from; "" for model-less embedders
 
 	mu       sync.RWMutex
 	entries  map[string]VectorEntry
 	idsCache []string
 	idsDirty bool
+	annCfg   HNSWConfig
+	ann      *HNSWIndex // Built once the index reaches annCfg.MinSize
 }
 
 func NewVectorIndex(dim int) *VectorIndex {
@@ -67,9 +70,58 @@ func NewVectorIndex(dim int) *VectorIndex {
 		Dim:      dim,
 		entries:  make(map[string]VectorEntry),
 		idsDirty: true,
+		annCfg:   DefaultHNSWConfig,
 	}
 }
 
+// SetHNSWConfig changes the ANN parameters. A graph built with a different
+// M or efConstruction is discarded, and a graph is built if the index has
+// reached cfg.MinSize. It reports whether a graph was built, in which case
+// the index should be saved.
+func (idx *VectorIndex) SetHNSWConfig(cfg HNSWConfig) bool {
+	cfg = cfg.Normalized()
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if idx.ann != nil && (idx.ann.cfg.M != cfg.M || idx.ann.cfg.EfConstruction != cfg.EfConstruction) {
+		idx.ann = nil
+	}
+	if idx.ann != nil {
+		idx.ann.cfg.EfSearch = cfg.EfSearch
+	}
+	idx.annCfg = cfg
+	return idx.ensureANNLocked()
+}
+
+// UsesANN reports whether SearchTopK currently answers from the HNSW graph
+// rather than an exact scan.
+func (idx *VectorIndex) UsesANN() bool {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.ann != nil && len(idx.entries) >= idx.annCfg.MinSize
+}
+
+// ensureANNLocked builds the graph once the index is large enough. Once
+// built it is kept in sync by Upsert and Remove even if the index shrinks.
+func (idx *VectorIndex) ensureANNLocked() bool {
+	if idx.ann != nil || len(idx.entries) < idx.annCfg.MinSize {
+		return false
+	}
+	ids := make([]string, 0, len(idx.entries))
+	for id := range idx.entries {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	idx.ann = NewHNSWIndex(idx.annCfg)
+	for _, id := range ids {
+		idx.ann.Insert(id, idx.entries[id].Vector)
+	}
+	return true
+}
+
+// LoadVectorIndex reads an index saved by Save. A stored graph is kept, but
+// none is built here: SetHNSWConfig builds it once the caller's ANN
+// parameters are known.
 func LoadVectorIndex(path string) (*VectorIndex, error) {
 	f, err := os.Open(path)
 	if err != nil {
@@ -91,7 +143,7 @@ func LoadVectorIndex(path string) (*VectorIndex, error) {
 	if err := binary.Read(r, binary.LittleEndian, &version); err != nil {
 		return nil, fmt.Errorf("read version: %w", err)
 	}
-	if version != vectorIndexVersion {
+	if version < 1 || version > vectorIndexVersion {
 		return nil, fmt.Errorf("unsupported version %d", version)
 	}
 
@@ -114,6 +166,15 @@ func LoadVectorIndex(path string) (*VectorIndex, error) {
 	}
 
 	idx := NewVectorIndex(int(dimU32))
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if version >= 2 {
+		model, err := readString16(r)
+		if err != nil {
+			return nil, fmt.Errorf("read model: %w", err)
+		}
+		idx.Model = model
+	}
 	for i := uint32(0); i < count; i++ {
 		var idLen uint16
 		if err := binary.Read(r, binary.LittleEndian, &idLen); err != nil {
@@ -143,8 +204,20 @@ func LoadVectorIndex(path string) (*VectorIndex, error) {
 			vec[j] = math.Float32frombits(bits)
 		}
 
-		if err := idx.Upsert(issueID, ch, vec); err != nil {
-			return nil, err
+		idx.putLocked(issueID, ch, vec)
+	}
+
+	// A missing, outdated or inconsistent graph is rebuilt from the vectors
+	// by SetHNSWConfig.
+	if version >= 3 {
+		var hasGraph [1]byte
+		if _, err := io.ReadFull(r, hasGraph[:]); err != nil {
+			return nil, fmt.Errorf("read graph flag: %w", err)
+		}
+		if hasGraph[0] == 1 {
+			if ann, err := readHNSW(r, idx.annCfg, idx.Dim, idx.entries); err == nil {
+				idx.ann = ann
+			}
 		}
 	}
 
@@ -158,12 +231,77 @@ func (idx *VectorIndex) Save(path string) error {
 	idx.mu.RLock()
 	defer idx.mu.RUnlock()
 
+	return saveAtomically(path, "bvvi-*.tmp", func(w *bufio.Writer) error {
+		if _, err := w.WriteString(vectorIndexMagic); err != nil {
+			return fmt.Errorf("write magic: %w", err)
+		}
+		if err := binary.Write(w, binary.LittleEndian, vectorIndexVersion); err != nil {
+			return fmt.Errorf("write version: %w", err)
+		}
+		if err := binary.Write(w, binary.LittleEndian, uint16(0)); err != nil {
+			return fmt.Errorf("write reserved: %w", err)
+		}
+		if err := binary.Write(w, binary.LittleEndian, uint32(idx.Dim)); err != nil {
+			return fmt.Errorf("write dim: %w", err)
+		}
+
+		if err := binary.Write(w, binary.LittleEndian, uint32(len(ids))); err != nil {
+			return fmt.Errorf("write count: %w", err)
+		}
+		if err := writeString16(w, idx.Model); err != nil {
+			return fmt.Errorf("write model: %w", err)
+		}
+
+		for _, issueID := range ids {
+			entry, ok := idx.entries[issueID]
+			if !ok {
+				continue
+			}
+			if len(issueID) > math.MaxUint16 {
+				return fmt.Errorf("issue id too long: %d", len(issueID))
+			}
+
+			if err := binary.Write(w, binary.LittleEndian, uint16(len(issueID))); err != nil {
+				return fmt.Errorf("write id len: %w", err)
+			}
+			if _, err := w.WriteString(issueID); err != nil {
+				return fmt.Errorf("write id: %w", err)
+			}
+			if _, err := w.Write(entry.ContentHash[:]); err != nil {
+				return fmt.Errorf("write content hash: %w", err)
+			}
+			if len(entry.Vector) != idx.Dim {
+				return fmt.Errorf("vector dim mismatch for %s: %d != %d", issueID, len(entry.Vector), idx.Dim)
+			}
+			for _, v := range entry.Vector {
+				if err := binary.Write(w, binary.LittleEndian, math.Float32bits(v)); err != nil {
+					return fmt.Errorf("write vector: %w", err)
+				}
+			}
+		}
+
+		if idx.ann == nil {
+			return w.WriteByte(0)
+		}
+		if err := w.WriteByte(1); err != nil {
+			return fmt.Errorf("write graph flag: %w", err)
+		}
+		if err := idx.ann.writeTo(w); err != nil {
+			return fmt.Errorf("write graph: %w", err)
+		}
+		return nil
+	})
+}
+
+// saveAtomically writes a file through a temp file in the same directory and
+// renames it into place, so readers never see a partial index.
+func saveAtomically(path, tempPattern string, write func(w *bufio.Writer) error) error {
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return fmt.Errorf("mkdir %s: %w", dir, err)
 	}
 
-	tmp, err := os.CreateTemp(dir, "bvvi-*.tmp")
+	tmp, err := os.CreateTemp(dir, tempPattern)
 	if err != nil {
 		return fmt.Errorf("create temp: %w", err)
 	}
@@ -174,52 +312,9 @@ func (idx *VectorIndex) Save(path string) error {
 	}()
 
 	w := bufio.NewWriter(tmp)
-
-	if _, err := w.WriteString(vectorIndexMagic); err != nil {
-		return fmt.Errorf("write magic: %w", err)
-	}
-	if err := binary.Write(w, binary.LittleEndian, vectorIndexVersion); err != nil {
-		return fmt.Errorf("write version: %w", err)
-	}
-	if err := binary.Write(w, binary.LittleEndian, uint16(0)); err != nil {
-		return fmt.Errorf("write reserved: %w", err)
-	}
-	if err := binary.Write(w, binary.LittleEndian, uint32(idx.Dim)); err != nil {
-		return fmt.Errorf("write dim: %w", err)
-	}
-
-	if err := binary.Write(w, binary.LittleEndian, uint32(len(ids))); err != nil {
-		return fmt.Errorf("write count: %w", err)
+	if err := write(w); err != nil {
+		return err
 	}
-
-	for _, issueID := range ids {
-		entry, ok := idx.entries[issueID]
-		if !ok {
-			continue
-		}
-		if len(issueID) > math.MaxUint16 {
-			return fmt.Errorf("issue id too long: %d", len(issueID))
-		}
-
-		if err := binary.Write(w, binary.LittleEndian, uint16(len(issueID))); err != nil {
-			return fmt.Errorf("write id len: %w", err)
-		}
-		if _, err := w.WriteString(issueID); err != nil {
-			return fmt.Errorf("write id: %w", err)
-		}
-		if _, err := w.Write(entry.ContentHash[:]); err != nil {
-			return fmt.Errorf("write content hash: %w", err)
-		}
-		if len(entry.Vector) != idx.Dim {
-			return fmt.Errorf("vector dim mismatch for %s: %d != %d", issueID, len(entry.Vector), idx.Dim)
-		}
-		for _, v := range entry.Vector {
-			if err := binary.Write(w, binary.LittleEndian, math.Float32bits(v)); err != nil {
-				return fmt.Errorf("write vector: %w", err)
-			}
-		}
-	}
-
 	if err := w.Flush(); err != nil {
 		return fmt.Errorf("flush: %w", err)
 	}
@@ -228,7 +323,7 @@ func (idx *VectorIndex) Save(path string) error {
 	}
 
 	if err := os.Rename(tmpPath, path); err != nil {
-		// os.Rename doesn't replace existing files on Windows. Since the index is deterministic
+		// os.Rename doesn't replace existing files on Windows. Since indexes are deterministic
 		// and can be rebuilt, fall back to removing the destination and retrying.
 		if runtime.GOOS == "windows" {
 			if _, statErr := os.Stat(path); statErr == nil {
@@ -247,6 +342,31 @@ func (idx *VectorIndex) Save(path string) error {
 	return nil
 }
 
+// writeString16 writes a string prefixed with its uint16 length.
+func writeString16(w io.Writer, s string) error {
+	if len(s) > math.MaxUint16 {
+		return fmt.Errorf("string too long: %d", len(s))
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint16(len(s))); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, s)
+	return err
+}
+
+// readString16 reads a string written by writeString16.
+func readString16(r io.Reader) (string, error) {
+	var n uint16
+	if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+		return "", err
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
 func (idx *VectorIndex) Upsert(issueID string, hash ContentHash, vec []float32) error {
 	if issueID == "" {
 		return fmt.Errorf("issue id cannot be empty")
@@ -258,6 +378,17 @@ func (idx *VectorIndex) Upsert(issueID string, hash ContentHash, vec []float32)
 	idx.mu.Lock()
 	defer idx.mu.Unlock()
 
+	idx.putLocked(issueID, hash, vec)
+	if idx.ann != nil {
+		idx.ann.Insert(issueID, idx.entries[issueID].Vector)
+	} else {
+		idx.ensureANNLocked()
+	}
+	return nil
+}
+
+// putLocked stores a copy of vec without touching the graph.
+func (idx *VectorIndex) putLocked(issueID string, hash ContentHash, vec []float32) {
 	_, exists := idx.entries[issueID]
 	cp := make([]float32, len(vec))
 	copy(cp, vec)
@@ -268,7 +399,6 @@ func (idx *VectorIndex) Upsert(issueID string, hash ContentHash, vec []float32)
 	if !exists {
 		idx.idsDirty = true
 	}
-	return nil
 }
 
 func (idx *VectorIndex) Remove(issueID string) {
@@ -280,6 +410,9 @@ func (idx *VectorIndex) Remove(issueID string) {
 	}
 	delete(idx.entries, issueID)
 	idx.idsDirty = true
+	if idx.ann != nil {
+		idx.ann.Delete(issueID)
+	}
 }
 
 func (idx *VectorIndex) Get(issueID string) (VectorEntry, bool) {
@@ -334,6 +467,15 @@ func (idx *VectorIndex) SearchTopK(query []float32, k int) ([]SearchResult, erro
 		return nil, fmt.Errorf("query dim mismatch: %d != %d", len(query), idx.Dim)
 	}
 
+	// Large indexes answer from the HNSW graph; small ones scan exactly,
+	// which is both fast enough and free of recall loss.
+	idx.mu.RLock()
+	if idx.ann != nil && len(idx.entries) >= idx.annCfg.MinSize {
+		defer idx.mu.RUnlock()
+		return idx.ann.Search(query, k, idx.annCfg.EfSearch), nil
+	}
+	idx.mu.RUnlock()
+
 	// sortedIDs now handles its own locking safely
 	ids := idx.sortedIDs()
 
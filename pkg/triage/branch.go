@@ -58,6 +58,7 @@ type ChangeType string
 
 const (
 	ChangePriority   ChangeType = "priority"
+	ChangeInherit    ChangeType = "priority_inherit" // Blocker takes the priority of the work it holds up
 	ChangeStatus     ChangeType = "status"
 	ChangeLabel      ChangeType = "label_add"
 	ChangeLabelDel   ChangeType = "label_del"
@@ -383,6 +384,33 @@ func generateProposals(issues []model.Issue, triage *analysis.TriageResult, now
 		}
 	}
 
+	// Proposal 1b: Priority inheritance for blockers holding up more
+	// important work. Replaces a weaker score-based priority proposal.
+	priorityProposal := make(map[string]int, len(proposals))
+	for i, p := range proposals {
+		priorityProposal[p.IssueID] = i
+	}
+	for _, inv := range analysis.DetectPriorityInversions(issues) {
+		change := ProposedChange{
+			IssueID:    inv.BlockerID,
+			ChangeType: ChangeInherit,
+			Field:      "priority",
+			OldValue:   fmt.Sprintf("P%d", inv.BlockerPriority),
+			NewValue:   fmt.Sprintf("P%d", inv.InheritedPriority),
+			Reason:     fmt.Sprintf("Blocks P%d %s via %s", inv.BlockedPriority, inv.BlockedID, strings.Join(inv.Chain, " <- ")),
+			Score:      float64(inv.Gap()) / 4.0,
+		}
+		if i, ok := priorityProposal[inv.BlockerID]; ok {
+			var proposed int
+			fmt.Sscanf(proposals[i].NewValue, "P%d", &proposed)
+			if proposed > inv.InheritedPriority {
+				proposals[i] = change
+			}
+			continue
+		}
+		proposals = append(proposals, change)
+	}
+
 	// Proposal 2: Flag stale issues (7+ days for in-progress, 10+ for open)
 	for i := range issues {
 		issue := &issues[i]
@@ -481,7 +509,7 @@ func suggestPriority(score float64, currentPriority int) int {
 func applyProposals(ctx context.Context, db *sql.DB, proposals []ProposedChange) error {
 	for _, p := range proposals {
 		switch p.ChangeType {
-		case ChangePriority:
+		case ChangePriority, ChangeInherit:
 			var newPri int
 			fmt.Sscanf(p.NewValue, "P%d", &newPri)
 			_, err := db.ExecContext(ctx,
@@ -6,7 +6,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
 )
 
 func skipIfNoDolt(t *testing.T) loader.DoltConfig {
@@ -27,6 +29,40 @@ func skipIfNoDolt(t *testing.T) loader.DoltConfig {
 	return config
 }
 
+func TestGenerateProposalsPriorityInheritance(t *testing.T) {
+	now := time.Now()
+	blocks := func(id, on string) []*model.Dependency {
+		return []*model.Dependency{{IssueID: id, DependsOnID: on, Type: model.DepBlocks}}
+	}
+	issues := []model.Issue{
+		{ID: "ship", Status: model.StatusOpen, Priority: 0, UpdatedAt: now, Dependencies: blocks("ship", "chore")},
+		{ID: "chore", Status: model.StatusOpen, Priority: 3, UpdatedAt: now},
+		{ID: "docs", Status: model.StatusOpen, Priority: 1, UpdatedAt: now, Dependencies: blocks("docs", "typo")},
+		{ID: "typo", Status: model.StatusOpen, Priority: 4, UpdatedAt: now},
+	}
+	// Triage alone would raise chore to P1 and typo to P0.
+	result := &analysis.TriageResult{Recommendations: []analysis.Recommendation{
+		{ID: "chore", Score: 0.2},
+		{ID: "typo", Score: 0.3},
+	}}
+
+	got := make(map[string]ProposedChange)
+	for _, p := range generateProposals(issues, result, now) {
+		if p.Field == "priority" {
+			if _, dup := got[p.IssueID]; dup {
+				t.Fatalf("two priority proposals for %s", p.IssueID)
+			}
+			got[p.IssueID] = p
+		}
+	}
+	if c := got["chore"]; c.ChangeType != ChangeInherit || c.OldValue != "P3" || c.NewValue != "P0" {
+		t.Errorf("chore proposal = %+v", c)
+	}
+	if c := got["typo"]; c.ChangeType != ChangePriority || c.NewValue != "P0" {
+		t.Errorf("typo keeps the stronger triage proposal: %+v", c)
+	}
+}
+
 func TestCreateTriageBranch(t *testing.T) {
 	config := skipIfNoDolt(t)
 	mgr := NewBranchManager(config)
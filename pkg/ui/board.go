@@ -57,6 +57,13 @@ type BoardModel struct {
 	// expandedCardID tracks which card is currently expanded inline
 	// Empty string means no card is expanded
 	expandedCardID string
+
+	// Grab mode: moving a card to another column.
+	// grabbed is the card being moved, grabOrigin/grabTarget the actual
+	// column indices (0-3) it came from and would be dropped into.
+	grabbed    *model.Issue
+	grabOrigin int
+	grabTarget int
 }
 
 // searchMatch holds info about a matching card (bv-yg39)
@@ -196,6 +203,10 @@ func (b *BoardModel) updateActiveColumns() {
 
 // shouldShowEmptyColumns returns whether empty columns should be visible (bv-tf6j)
 func (b *BoardModel) shouldShowEmptyColumns() bool {
+	// Every column is a drop target while a card is grabbed
+	if b.grabbed != nil {
+		return true
+	}
 	// Explicit override takes precedence
 	if b.showEmptyColumns != nil {
 		return *b.showEmptyColumns
@@ -919,40 +930,8 @@ func (b BoardModel) View(width, height int) string {
 			Render("No issues to display")
 	}
 
-	// Calculate board width vs detail panel width (bv-r6kh)
-	// Detail panel takes ~35% of width when shown, min 40 chars
-	boardWidth := width
-	detailWidth := 0
-	if b.showDetail && width > 120 {
-		detailWidth = width * 35 / 100
-		if detailWidth < 40 {
-			detailWidth = 40
-		}
-		if detailWidth > 80 {
-			detailWidth = 80
-		}
-		boardWidth = width - detailWidth - 1 // 1 char gap
-	}
-
-	// Calculate column widths - distribute space evenly
-	// Minimum column width for readability, NO maximum cap (bv-ic17)
-	minColWidth := 28
-
-	// Calculate available width (subtract gaps between columns)
-	gaps := numCols - 1
-	availableWidth := boardWidth - (gaps * 2) // 2 chars gap between columns
-
-	// Distribute width evenly across columns, respecting minimum
-	baseWidth := availableWidth / numCols
-	if baseWidth < minColWidth {
-		baseWidth = minColWidth
-	}
-	// NO maxColWidth cap - use all available horizontal space
-
-	colHeight := height - 6 // Account for column header + title bar (bv-tf6j)
-	if colHeight < 8 {
-		colHeight = 8
-	}
+	boardWidth, detailWidth, baseWidth := b.layoutWidths(width)
+	colHeight := boardColumnHeight(height)
 
 	// Get dynamic column headers based on swimlane mode (bv-wjs0)
 	columnTitles, columnEmoji := b.getColumnHeaders()
@@ -996,6 +975,9 @@ func (b BoardModel) View(width, height int) string {
 		// - Wide (>140): Full stats including oldest age
 		var headerText string
 		baseHeader := fmt.Sprintf("%s %s (%d)", columnEmoji[colIdx], columnTitles[colIdx], issueCount)
+		if b.grabbed != nil && colIdx == b.grabTarget {
+			baseHeader = "▼ " + baseHeader
+		}
 
 		if width < 100 {
 			// Narrow: just the base header
@@ -1057,39 +1039,25 @@ func (b BoardModel) View(width, height int) string {
 
 		header := headerStyle.Render(headerText)
 
-		// Calculate visible rows (bv-1daf: 3 content lines)
-		// Card height breakdown:
-		// - 3 content lines (line1: meta, line2: title, line3: deps/labels)
-		// - 2 border lines (RoundedBorder adds top + bottom)
-		// - 1 margin line (MarginBottom(1))
-		// Total: 6 lines per card
-		cardHeight := 6
-		visibleCards := (colHeight - 1) / cardHeight
-		if visibleCards < 1 {
-			visibleCards = 1
-		}
-
-		sel := b.selectedRow[colIdx]
-		if sel >= issueCount && issueCount > 0 {
-			sel = issueCount - 1
-		}
-
-		// Simple scrolling: keep selected card visible
-		start := 0
-		if sel >= visibleCards {
-			start = sel - visibleCards + 1
-		}
-
-		end := start + visibleCards
-		if end > issueCount {
-			end = issueCount
-		}
+		sel, start, end, visibleCards := b.visibleRows(colIdx, colHeight)
 
 		// Render cards
 		var cards []string
+		isDropTarget := b.grabbed != nil && colIdx == b.grabTarget
+		if isDropTarget && colIdx != b.grabOrigin {
+			// Ghost of the grabbed card where it would land
+			cards = append(cards, b.renderCard(*b.grabbed, baseWidth-4, true, colIdx, -1))
+			if end-start >= visibleCards {
+				end-- // Keep the column height stable
+			}
+		}
 		for rowIdx := start; rowIdx < end; rowIdx++ {
 			issue := issues[rowIdx]
 			isSelected := isFocused && rowIdx == sel
+			if b.grabbed != nil {
+				// Only the grabbed card stays highlighted while moving
+				isSelected = colIdx == b.grabOrigin && issue.ID == b.grabbed.ID && !isDropTarget
+			}
 
 			// Check if this card is expanded (bv-i3ii)
 			var card string
@@ -1102,7 +1070,7 @@ func (b BoardModel) View(width, height int) string {
 		}
 
 		// Empty column placeholder
-		if issueCount == 0 {
+		if issueCount == 0 && len(cards) == 0 {
 			emptyStyle := t.Renderer.NewStyle().
 				Width(baseWidth-4).
 				Height(colHeight-2).
@@ -1133,7 +1101,9 @@ func (b BoardModel) View(width, height int) string {
 			Padding(0, 1).
 			Border(lipgloss.RoundedBorder())
 
-		if isFocused {
+		if isDropTarget {
+			colStyle = colStyle.Border(lipgloss.ThickBorder()).BorderForeground(columnColors[colIdx])
+		} else if isFocused {
 			colStyle = colStyle.BorderForeground(columnColors[colIdx])
 		} else {
 			colStyle = colStyle.BorderForeground(t.Secondary)
@@ -1161,6 +1131,196 @@ func (b BoardModel) View(width, height int) string {
 	return boardView
 }
 
+// Board layout: a 2-line title bar, then per column a header line, the
+// top border and 6-line cards (3 content + 2 border + 1 margin, bv-1daf).
+const (
+	boardCardsTop    = 4
+	boardCardHeight  = 6
+	boardMinColWidth = 28 // Minimum column width for readability, NO maximum cap (bv-ic17)
+)
+
+// layoutWidths splits width between the columns and the detail panel (bv-r6kh)
+// and returns the width of each column.
+func (b BoardModel) layoutWidths(width int) (boardWidth, detailWidth, baseWidth int) {
+	// Detail panel takes ~35% of width when shown, min 40 chars
+	boardWidth = width
+	if b.showDetail && width > 120 {
+		detailWidth = width * 35 / 100
+		if detailWidth < 40 {
+			detailWidth = 40
+		}
+		if detailWidth > 80 {
+			detailWidth = 80
+		}
+		boardWidth = width - detailWidth - 1 // 1 char gap
+	}
+
+	numCols := len(b.activeColIdx)
+	if numCols == 0 {
+		return boardWidth, detailWidth, boardMinColWidth
+	}
+
+	// Calculate available width (subtract gaps between columns)
+	gaps := numCols - 1
+	availableWidth := boardWidth - (gaps * 2) // 2 chars gap between columns
+
+	// Distribute width evenly across columns, respecting minimum
+	baseWidth = availableWidth / numCols
+	if baseWidth < boardMinColWidth {
+		baseWidth = boardMinColWidth
+	}
+	return boardWidth, detailWidth, baseWidth
+}
+
+// boardColumnHeight returns the height of a column body for a board of the
+// given height.
+func boardColumnHeight(height int) int {
+	colHeight := height - 6 // Account for column header + title bar (bv-tf6j)
+	if colHeight < 8 {
+		colHeight = 8
+	}
+	return colHeight
+}
+
+// visibleRows returns the selected row and the [start, end) range of cards
+// shown in column colIdx, scrolled to keep the selection visible.
+func (b BoardModel) visibleRows(colIdx, colHeight int) (sel, start, end, visibleCards int) {
+	issueCount := len(b.columns[colIdx])
+	visibleCards = (colHeight - 1) / boardCardHeight
+	if visibleCards < 1 {
+		visibleCards = 1
+	}
+
+	sel = b.selectedRow[colIdx]
+	if sel >= issueCount && issueCount > 0 {
+		sel = issueCount - 1
+	}
+
+	// Simple scrolling: keep selected card visible
+	if sel >= visibleCards {
+		start = sel - visibleCards + 1
+	}
+
+	end = start + visibleCards
+	if end > issueCount {
+		end = issueCount
+	}
+	return sel, start, end, visibleCards
+}
+
+// ColumnAt returns the column (0-3) under screen column x for a board
+// rendered at the given width, or -1.
+func (b BoardModel) ColumnAt(x, width int) int {
+	_, _, baseWidth := b.layoutWidths(width)
+	i := x / (baseWidth + 2) // Border adds 2 to each column
+	if x < 0 || i >= len(b.activeColIdx) {
+		return -1
+	}
+	return b.activeColIdx[i]
+}
+
+// CardAt returns the column and row of the card at (x, y) for a board
+// rendered at width x height.
+func (b BoardModel) CardAt(x, y, width, height int) (col, row int, ok bool) {
+	col = b.ColumnAt(x, width)
+	if col < 0 || y < boardCardsTop {
+		return -1, -1, false
+	}
+	_, start, end, _ := b.visibleRows(col, boardColumnHeight(height))
+	row = start + (y-boardCardsTop)/boardCardHeight
+	if row >= end {
+		return -1, -1, false
+	}
+	return col, row, true
+}
+
+// SelectCard focuses column col and selects its row.
+func (b *BoardModel) SelectCard(col, row int) {
+	if col < 0 || col > 3 || row < 0 || row >= len(b.columns[col]) {
+		return
+	}
+	b.CollapseExpanded()
+	b.focusColumn(col)
+	b.selectedRow[col] = row
+}
+
+// focusColumn focuses the actual column col if it is shown.
+func (b *BoardModel) focusColumn(col int) {
+	for i, activeCol := range b.activeColIdx {
+		if activeCol == col {
+			b.focusedCol = i
+			return
+		}
+	}
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Grab mode: moving cards between columns
+// ═══════════════════════════════════════════════════════════════════════════
+
+// StartGrab picks up the selected card. Returns false if nothing is selected.
+func (b *BoardModel) StartGrab() bool {
+	selected := b.SelectedIssue()
+	if selected == nil {
+		return false
+	}
+	grabbed := *selected
+	b.grabbed = &grabbed
+	b.grabOrigin = b.actualFocusedCol()
+	b.grabTarget = b.grabOrigin
+	b.CollapseExpanded()
+	b.updateActiveColumns()
+	b.focusColumn(b.grabTarget)
+	return true
+}
+
+// IsGrabbing returns whether a card is currently grabbed.
+func (b *BoardModel) IsGrabbing() bool { return b.grabbed != nil }
+
+// GrabbedIssue returns the grabbed card, or nil.
+func (b *BoardModel) GrabbedIssue() *model.Issue { return b.grabbed }
+
+// GrabOrigin returns the column (0-3) the grabbed card came from.
+func (b *BoardModel) GrabOrigin() int { return b.grabOrigin }
+
+// GrabTarget returns the column (0-3) the grabbed card would be dropped into.
+func (b *BoardModel) GrabTarget() int { return b.grabTarget }
+
+// SetGrabTarget moves the drop target to column col (0-3).
+func (b *BoardModel) SetGrabTarget(col int) {
+	if b.grabbed == nil || col < 0 || col > 3 {
+		return
+	}
+	b.grabTarget = col
+	b.focusColumn(col)
+}
+
+// MoveGrabTarget shifts the drop target by delta columns, clamped to the board.
+func (b *BoardModel) MoveGrabTarget(delta int) {
+	col := b.grabTarget + delta
+	if col < 0 {
+		col = 0
+	}
+	if col > 3 {
+		col = 3
+	}
+	b.SetGrabTarget(col)
+}
+
+// EndGrab leaves grab mode, restoring the selection to the grabbed card
+// if it is still on the board.
+func (b *BoardModel) EndGrab() {
+	if b.grabbed == nil {
+		return
+	}
+	id := b.grabbed.ID
+	b.grabbed = nil
+	b.updateActiveColumns()
+	if !b.SelectIssueByID(id) {
+		b.focusColumn(b.grabOrigin)
+	}
+}
+
 // renderTitleBar creates the board title bar with swimlane mode and hidden column count (bv-tf6j)
 func (b BoardModel) renderTitleBar(width int, t Theme) string {
 	// Build title: "BOARD [by: Status]" or "BOARD [by: Priority] [+2 hidden]"
@@ -25,6 +25,8 @@ const (
 	// Views
 	ContextInsights       Context = "insights"
 	ContextFlowMatrix     Context = "flow-matrix"
+	ContextGantt          Context = "gantt"
+	ContextFlowMetrics    Context = "flow-metrics"
 	ContextGraph          Context = "graph"
 	ContextBoard          Context = "board"
 	ContextActionable     Context = "actionable"
@@ -127,6 +129,16 @@ func (m Model) CurrentContext() Context {
 		return ContextFlowMatrix
 	}
 
+	// Schedule (Gantt) view
+	if m.focused == focusGantt {
+		return ContextGantt
+	}
+
+	// Flow metrics view
+	if m.focused == focusFlowMetrics {
+		return ContextFlowMetrics
+	}
+
 	// Label dashboard
 	if m.focused == focusLabelDashboard {
 		return ContextLabelDashboard
@@ -203,6 +215,8 @@ func (c Context) Description() string {
 		ContextCassSession:        "Cass session preview",
 		ContextInsights:           "Insights panel",
 		ContextFlowMatrix:         "Flow matrix",
+		ContextGantt:              "Schedule view",
+		ContextFlowMetrics:        "Flow metrics",
 		ContextGraph:              "Dependency graph",
 		ContextBoard:              "Kanban board",
 		ContextActionable:         "Actionable view",
@@ -237,7 +251,7 @@ func (c Context) IsOverlay() bool {
 // IsView returns true if the context is a full view (not overlay or default list)
 func (c Context) IsView() bool {
 	switch c {
-	case ContextInsights, ContextFlowMatrix, ContextGraph, ContextBoard,
+	case ContextInsights, ContextFlowMatrix, ContextGantt, ContextFlowMetrics, ContextGraph, ContextBoard,
 		ContextActionable, ContextHistory, ContextSprint, ContextLabelDashboard,
 		ContextAttention, ContextSplit, ContextDetail, ContextTimeTravel:
 		return true
@@ -262,6 +276,8 @@ func (c Context) TutorialPages() []int {
 		ContextTimeTravel:         {10},      // Time-Travel
 		ContextLabelDashboard:     {11},      // Labels
 		ContextFlowMatrix:         {11, 12},  // Labels, Advanced
+		ContextGantt:              {12},      // Advanced
+		ContextFlowMetrics:        {12},      // Advanced
 		ContextHelp:               {13},      // Keyboard Reference
 		ContextSprint:             {14},      // Sprints
 		ContextAttention:          {7},       // Insights (attention is part of insights)
@@ -103,6 +103,7 @@ const contextHelpList = `## List View
   Ctrl+S    Semantic search (AI)
   H         Hybrid ranking
   Alt+H     Hybrid preset
+  Alt+S     Search comments/commits
 
 **Switch Views**
   a         Actionable view
@@ -111,6 +112,10 @@ const contextHelpList = `## List View
   i         Insights panel
   h         History view
 
+**Edit (writes to beads)**
+  m/P/A     Status, priority, assignee
+  +/-/M/z   Labels, comment, undo
+
 **Actions**
   U         Self-update bv
   V         Preview cass sessions`
@@ -122,13 +127,20 @@ const contextHelpGraph = `## Graph View
   h/l       Navigate siblings
   Enter     View selected issue
   f         Focus on subgraph
+  C         Toggle status / community colors
   Esc       Exit to list
 
+**Editing Dependencies**
+  Space     Mark the dependent issue
+  + / -     Add / remove dependency on selection
+  z         Undo last edit
+  (edges that would create a cycle are refused)
+
 **Understanding the Graph**
 • Arrows point TO what's blocked
   (A → B means A blocks B)
 • Node size = priority
-• Color = status
+• Color = status (C: community)
   Green=closed, Blue=in_progress`
 
 const contextHelpBoard = `## Board View
@@ -156,8 +168,8 @@ const contextHelpBoard = `## Board View
   🟢 Green   Ready to work
 
 **Actions**
-  Tab       Toggle detail panel
-  Ctrl+j/k  Scroll detail panel
+  Space     Grab card, h/l/1-4 + Enter to move
+  Tab       Toggle detail (Ctrl+j/k scroll)
   V         Preview cass sessions
   y         Copy issue ID
   Enter     View issue details
@@ -223,6 +235,10 @@ const contextHelpDetail = `## Detail View
   O         Open in editor
   C         Copy issue ID
 
+**Edit (writes to beads)**
+  m/P/A     Status, priority, assignee
+  +/-/M/z   Labels, comment, undo
+
 **Info Shown**
 • Full description (markdown)
 • Dependencies
@@ -260,6 +276,7 @@ const contextHelpFilter = `## Filter Mode
   Ctrl+S    Semantic search (AI)
   H         Hybrid ranking
   Alt+H     Hybrid preset
+  Alt+S     Search comments/commits
   n/N       Next/prev match
   Esc       Clear search
 
@@ -453,10 +453,14 @@ func TestWaitForPhase2CmdCompletes(t *testing.T) {
 		0,
 		nil,
 	)
-	cmd := WaitForPhase2Cmd(stats)
-	if msg := cmd(); msg == nil {
+	cmd := WaitForPhase2Cmd(stats, nil)
+	msg, ok := cmd().(Phase2ReadyMsg)
+	if !ok {
 		t.Fatalf("expected Phase2ReadyMsg")
 	}
+	if msg.Insights.Dominators == nil {
+		t.Fatalf("expected the dominator analysis to arrive with the Phase 2 insights")
+	}
 }
 
 func TestDiffStatusAndExitTimeTravel(t *testing.T) {
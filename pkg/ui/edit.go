@@ -0,0 +1,629 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/writeback"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// editPromptKind identifies what the inline edit prompt is collecting.
+type editPromptKind int
+
+const (
+	editPromptNone editPromptKind = iota
+	editPromptPriority
+	editPromptAssignee
+	editPromptAddLabel
+	editPromptRemoveLabel
+	editPromptComment
+)
+
+// editApplyTimeout bounds a single write to the data source.
+const editApplyTimeout = 15 * time.Second
+
+// EditAppliedMsg reports the result of persisting an edit or an undo.
+type EditAppliedMsg struct {
+	Edit writeback.Edit
+	Undo bool
+	Err  error
+}
+
+// applyEditCmd persists e off the UI thread.
+func applyEditCmd(store writeback.Store, e writeback.Edit, undo bool) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), editApplyTimeout)
+		defer cancel()
+		applied, err := store.Apply(ctx, e)
+		return EditAppliedMsg{Edit: applied, Undo: undo, Err: err}
+	}
+}
+
+// SetEditStore enables write-back editing against store. A nil store leaves
+// the TUI read-only.
+func (m *Model) SetEditStore(store writeback.Store) {
+	m.editStore = store
+}
+
+// EditStore returns the configured write-back store (nil when read-only).
+func (m Model) EditStore() writeback.Store {
+	return m.editStore
+}
+
+// SetTransitionRules sets the rules board moves are checked against. Nil
+// uses writeback.DefaultRules.
+func (m *Model) SetTransitionRules(rules *writeback.TransitionRules) {
+	m.transitionRules = rules
+}
+
+// boardRules returns the transition rules for board moves.
+func (m Model) boardRules() *writeback.TransitionRules {
+	if m.transitionRules != nil {
+		return m.transitionRules
+	}
+	return writeback.DefaultRules()
+}
+
+// editableFocus reports whether the current view accepts edit keys.
+func (m Model) editableFocus() bool {
+	switch m.focused {
+	case focusList, focusDetail:
+		return true
+	case focusBoard:
+		return !m.board.IsSearchMode() && !m.board.IsWaitingForG()
+	}
+	return false
+}
+
+// editTarget returns the issue the edit keys act on: the board card in the
+// board view, otherwise the list selection (which the detail pane shows).
+func (m Model) editTarget() *model.Issue {
+	var id string
+	if m.focused == focusBoard {
+		if sel := m.board.SelectedIssue(); sel != nil {
+			id = sel.ID
+		}
+	} else if sel := m.list.SelectedItem(); sel != nil {
+		if item, ok := sel.(IssueItem); ok {
+			id = item.Issue.ID
+		}
+	}
+	if id == "" {
+		return nil
+	}
+	return m.issueMap[id]
+}
+
+// editUnavailable returns why edits cannot start right now, or "".
+func (m Model) editUnavailable() string {
+	switch {
+	case m.editStore == nil:
+		return "Editing unavailable: no writable data source"
+	case m.timeTravelMode:
+		return "Editing disabled in time-travel mode"
+	case m.editPending:
+		return "Previous edit still saving…"
+	}
+	return ""
+}
+
+// handleEditKeys handles the write-back keys shared by the list, board and
+// detail views. It reports whether the key was consumed.
+func (m Model) handleEditKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	key := msg.String()
+	switch key {
+	case "m", "P", "A", "+", "-", "M", "z":
+	case " ":
+		// Space grabs the selected card on the board
+		if m.focused != focusBoard {
+			return m, nil, false
+		}
+		m.startBoardGrab()
+		return m, nil, true
+	default:
+		return m, nil, false
+	}
+
+	if reason := m.editUnavailable(); reason != "" {
+		m.statusMsg = reason
+		m.statusIsError = true
+		return m, nil, true
+	}
+
+	if key == "z" {
+		return m.undoLastEdit()
+	}
+
+	issue := m.editTarget()
+	if issue == nil {
+		m.statusMsg = "❌ No issue selected"
+		m.statusIsError = true
+		return m, nil, true
+	}
+
+	switch key {
+	case "m":
+		cmd := m.startEdit(writeback.SetStatus(issue.ID, writeback.NextStatus(issue.Status)))
+		return m, cmd, true
+	case "P":
+		m.openEditPrompt(editPromptPriority, issue.ID, "")
+	case "A":
+		m.openEditPrompt(editPromptAssignee, issue.ID, issue.Assignee)
+	case "+":
+		m.openEditPrompt(editPromptAddLabel, issue.ID, "")
+	case "-":
+		if len(issue.Labels) == 0 {
+			m.statusMsg = fmt.Sprintf("%s has no labels", issue.ID)
+			m.statusIsError = true
+			return m, nil, true
+		}
+		value := ""
+		if len(issue.Labels) == 1 {
+			value = issue.Labels[0]
+		}
+		m.openEditPrompt(editPromptRemoveLabel, issue.ID, value)
+	case "M":
+		m.openEditPrompt(editPromptComment, issue.ID, "")
+	}
+	return m, nil, true
+}
+
+// openEditPrompt shows the edit prompt for issueID prefilled with value.
+func (m *Model) openEditPrompt(kind editPromptKind, issueID, value string) {
+	ti := textinput.New()
+	ti.CharLimit = 200
+	ti.Width = 50
+	switch kind {
+	case editPromptAssignee:
+		ti.Prompt = "@ "
+		ti.Placeholder = "assignee (empty to unassign)"
+	case editPromptAddLabel:
+		ti.Prompt = "+ "
+		ti.Placeholder = "label"
+	case editPromptRemoveLabel:
+		ti.Prompt = "- "
+		ti.Placeholder = "label"
+	case editPromptComment:
+		ti.Prompt = "💬 "
+		ti.Placeholder = "comment"
+		ti.CharLimit = 2000
+	}
+	ti.PromptStyle = lipgloss.NewStyle().Foreground(m.theme.Primary).Bold(true)
+	ti.TextStyle = lipgloss.NewStyle().Foreground(m.theme.Base.GetForeground())
+	ti.SetValue(value)
+	ti.CursorEnd()
+	ti.Focus()
+
+	m.editInput = ti
+	m.editPrompt = kind
+	m.editIssueID = issueID
+}
+
+// closeEditPrompt dismisses the edit prompt without changing anything.
+func (m *Model) closeEditPrompt() {
+	m.editPrompt = editPromptNone
+	m.editIssueID = ""
+	m.editInput.Blur()
+}
+
+// handleEditPromptKeys routes input to the open edit prompt.
+func (m Model) handleEditPromptKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.closeEditPrompt()
+		return m, nil
+	}
+
+	if m.editPrompt == editPromptPriority {
+		key := msg.String()
+		if len(key) == 1 && key[0] >= '0' && key[0] <= '4' {
+			id := m.editIssueID
+			m.closeEditPrompt()
+			cmd := m.startEdit(writeback.SetPriority(id, int(key[0]-'0')))
+			return m, cmd
+		}
+		return m, nil
+	}
+
+	if msg.String() != "enter" {
+		var cmd tea.Cmd
+		m.editInput, cmd = m.editInput.Update(msg)
+		return m, cmd
+	}
+
+	id := m.editIssueID
+	value := strings.TrimSpace(m.editInput.Value())
+	kind := m.editPrompt
+	m.closeEditPrompt()
+
+	var e writeback.Edit
+	switch kind {
+	case editPromptAssignee:
+		e = writeback.SetAssignee(id, value)
+	case editPromptAddLabel:
+		e = writeback.AddLabel(id, value)
+	case editPromptRemoveLabel:
+		e = writeback.RemoveLabel(id, value)
+	case editPromptComment:
+		e = writeback.AddComment(id, writeback.DefaultAuthor(), value)
+	default:
+		return m, nil
+	}
+	cmd := m.startEdit(e)
+	return m, cmd
+}
+
+// startEdit validates e against the current issue and persists it.
+func (m *Model) startEdit(e writeback.Edit) tea.Cmd {
+	return m.startEditChecked(e, nil)
+}
+
+// startEditChecked is startEdit that also rejects edits the transition
+// rules forbid. Nil rules allow everything.
+func (m *Model) startEditChecked(e writeback.Edit, rules *writeback.TransitionRules) tea.Cmd {
+	issue, ok := m.issueMap[e.IssueID]
+	if !ok {
+		m.statusMsg = fmt.Sprintf("❌ %s no longer exists", e.IssueID)
+		m.statusIsError = true
+		return nil
+	}
+	prepared, err := e.Prepare(*issue, time.Now())
+	if errors.Is(err, writeback.ErrNoChange) {
+		m.statusMsg = fmt.Sprintf("%s unchanged", e.IssueID)
+		m.statusIsError = false
+		return nil
+	}
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("❌ %v", err)
+		m.statusIsError = true
+		return nil
+	}
+	if rules != nil {
+		var blockers []string
+		if m.analyzer != nil {
+			blockers = analysis.NewTriageContext(m.analyzer).OpenBlockers(e.IssueID)
+		}
+		if err := rules.Check(prepared, blockers); err != nil {
+			m.statusMsg = fmt.Sprintf("🚫 %v", err)
+			m.statusIsError = true
+			return nil
+		}
+	}
+	m.editPending = true
+	m.statusMsg = fmt.Sprintf("Saving %s…", prepared)
+	m.statusIsError = false
+	return applyEditCmd(m.editStore, prepared, false)
+}
+
+// undoLastEdit persists the inverse of the most recent session edit.
+func (m Model) undoLastEdit() (Model, tea.Cmd, bool) {
+	last, ok := m.editHistory.Peek()
+	if !ok {
+		m.statusMsg = "Nothing to undo"
+		m.statusIsError = false
+		return m, nil, true
+	}
+	inv := last.Inverse()
+	inv.At = time.Now()
+	m.editPending = true
+	m.statusMsg = fmt.Sprintf("Undoing %s…", last)
+	m.statusIsError = false
+	return m, applyEditCmd(m.editStore, inv, true), true
+}
+
+// handleEditApplied records a persisted edit and refreshes the views.
+func (m Model) handleEditApplied(msg EditAppliedMsg) (Model, tea.Cmd) {
+	m.editPending = false
+	if msg.Err != nil {
+		verb := "Edit"
+		if msg.Undo {
+			verb = "Undo"
+		}
+		m.statusMsg = fmt.Sprintf("❌ %s failed: %v", verb, msg.Err)
+		m.statusIsError = true
+		return m, nil
+	}
+
+	if msg.Undo {
+		last, _ := m.editHistory.Pop()
+		m.statusMsg = fmt.Sprintf("↶ Undid %s", last)
+	} else {
+		m.editHistory.Record(msg.Edit)
+		m.statusMsg = fmt.Sprintf("✓ %s → %s (z to undo)", msg.Edit, m.editStore.Describe())
+	}
+	m.statusIsError = false
+
+	m.applyEditInMemory(msg.Edit)
+	return m, WaitForPhase2Cmd(m.analysis, m.issuesForAsync())
+}
+
+// applyEditInMemory patches the loaded issues with a persisted edit and
+// recomputes what depends on them, so the change shows up immediately even for
+// sources without a file watcher (SQLite, Dolt). Live reload of a rewritten
+// JSONL file converges on the same state.
+func (m *Model) applyEditInMemory(e writeback.Edit) {
+	idx := -1
+	for i := range m.issues {
+		if m.issues[i].ID == e.IssueID {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return
+	}
+
+	// Copy rather than mutate: m.issues may be shared with a snapshot.
+	issues := make([]model.Issue, len(m.issues))
+	copy(issues, m.issues)
+	updated := issues[idx].Clone()
+	writeback.Apply(&updated, e)
+	issues[idx] = updated
+	m.issues = issues
+
+	m.issueMap = make(map[string]*model.Issue, len(m.issues))
+	for i := range m.issues {
+		m.issueMap[m.issues[i].ID] = &m.issues[i]
+	}
+
+	cachedAnalyzer := analysis.NewCachedAnalyzer(m.issues, nil)
+	m.analyzer = cachedAnalyzer.Analyzer
+	m.analysis = cachedAnalyzer.AnalyzeAsync(context.Background())
+	m.recomputeCounts()
+	m.alerts, m.alertsCritical, m.alertsWarning, m.alertsInfo = computeAlerts(m.issues, m.analysis, m.analyzer)
+	m.labelHealthCached = false
+	m.attentionCached = false
+	m.labelDrilldownCache = make(map[string][]model.Issue)
+
+	// Pre-built board/graph layouts in a background snapshot are now stale;
+	// drop them until the worker publishes the next snapshot.
+	if m.snapshot != nil {
+		snap := *m.snapshot
+		snap.Issues = m.issues
+		snap.IssueMap = m.issueMap
+		snap.Analyzer = m.analyzer
+		snap.Analysis = m.analysis
+		snap.ListItems = nil
+		snap.BoardState = nil
+		snap.GraphLayout = nil
+		snap.pooledIssues = nil
+		m.snapshot = &snap
+	}
+
+	var selectedID string
+	if sel := m.list.SelectedItem(); sel != nil {
+		if item, ok := sel.(IssueItem); ok {
+			selectedID = item.Issue.ID
+		}
+	}
+	m.applyFilter()
+	if selectedID != "" {
+		for i, item := range m.list.Items() {
+			if issueItem, ok := item.(IssueItem); ok && issueItem.Issue.ID == selectedID {
+				m.list.Select(i)
+				break
+			}
+		}
+	}
+	if m.isBoardView {
+		m.board.SelectIssueByID(e.IssueID)
+	}
+	m.updateViewportContent()
+}
+
+// renderEditPrompt renders the modal for the open edit prompt.
+func (m Model) renderEditPrompt() string {
+	t := m.theme
+
+	boxStyle := t.Renderer.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Primary).
+		Padding(1, 3)
+
+	titleStyle := t.Renderer.NewStyle().
+		Foreground(t.Primary).
+		Bold(true)
+
+	subtitleStyle := t.Renderer.NewStyle().
+		Foreground(t.Subtext).
+		Italic(true)
+
+	keyStyle := t.Renderer.NewStyle().
+		Foreground(t.Primary).
+		Bold(true)
+
+	textStyle := t.Renderer.NewStyle().
+		Foreground(t.Base.GetForeground())
+
+	var title, body, action string
+	issue := m.issueMap[m.editIssueID]
+	subtitle := m.editIssueID
+	if issue != nil {
+		subtitle = fmt.Sprintf("%s  %s", issue.ID, truncateRunesHelper(issue.Title, 48, "…"))
+	}
+
+	switch m.editPrompt {
+	case editPromptPriority:
+		title = "Set Priority"
+		var opts []string
+		for p := 0; p <= 4; p++ {
+			label := fmt.Sprintf("%d %s", p, GetPriorityIcon(p))
+			if issue != nil && issue.Priority == p {
+				label += " (current)"
+			}
+			opts = append(opts, keyStyle.Render(label))
+		}
+		body = strings.Join(opts, "   ")
+		action = textStyle.Render("Press ") + keyStyle.Render("0-4") + textStyle.Render(" to set, ")
+	case editPromptAssignee:
+		title = "Assign"
+		body = m.editInput.View()
+		action = textStyle.Render("Press ") + keyStyle.Render("Enter") + textStyle.Render(" to save, ")
+	case editPromptAddLabel:
+		title = "Add Label"
+		body = m.editInput.View()
+		action = textStyle.Render("Press ") + keyStyle.Render("Enter") + textStyle.Render(" to add, ")
+	case editPromptRemoveLabel:
+		title = "Remove Label"
+		body = m.editInput.View()
+		if issue != nil && len(issue.Labels) > 0 {
+			body += "\n\n" + subtitleStyle.Render("Labels: "+strings.Join(issue.Labels, ", "))
+		}
+		action = textStyle.Render("Press ") + keyStyle.Render("Enter") + textStyle.Render(" to remove, ")
+	case editPromptComment:
+		title = "Add Comment"
+		body = m.editInput.View() + "\n\n" + subtitleStyle.Render("as "+writeback.DefaultAuthor())
+		action = textStyle.Render("Press ") + keyStyle.Render("Enter") + textStyle.Render(" to post, ")
+	}
+
+	content := titleStyle.Render("✎  "+title) + "\n" +
+		subtitleStyle.Render(subtitle) + "\n\n" +
+		body + "\n\n" +
+		action + keyStyle.Render("Esc") + textStyle.Render(" to cancel")
+
+	return lipgloss.Place(
+		m.width,
+		m.height-1,
+		lipgloss.Center,
+		lipgloss.Center,
+		boxStyle.Render(content),
+	)
+}
+
+// boardColumnEdit returns the edit that moves issueID into board column col
+// under the given swimlane mode.
+func boardColumnEdit(mode SwimLaneMode, col int, issueID string) writeback.Edit {
+	switch mode {
+	case SwimByPriority:
+		return writeback.SetPriority(issueID, col) // P0 | P1 | P2 | P3+
+	case SwimByType:
+		types := [4]model.IssueType{model.TypeBug, model.TypeFeature, model.TypeTask, model.TypeEpic}
+		return writeback.SetType(issueID, types[col])
+	default:
+		statuses := [4]model.Status{model.StatusOpen, model.StatusInProgress, model.StatusBlocked, model.StatusClosed}
+		return writeback.SetStatus(issueID, statuses[col])
+	}
+}
+
+// startBoardGrab picks up the selected board card.
+func (m *Model) startBoardGrab() bool {
+	if reason := m.editUnavailable(); reason != "" {
+		m.statusMsg = reason
+		m.statusIsError = true
+		return false
+	}
+	if !m.board.StartGrab() {
+		m.statusMsg = "❌ No issue selected"
+		m.statusIsError = true
+		return false
+	}
+	m.setGrabStatus()
+	return true
+}
+
+// setGrabStatus shows where the grabbed card would be dropped.
+func (m *Model) setGrabStatus() {
+	grabbed := m.board.GrabbedIssue()
+	if grabbed == nil {
+		return
+	}
+	titles, _ := m.board.getColumnHeaders()
+	m.statusMsg = fmt.Sprintf("✋ Moving %s → %s (h/l or 1-4 choose, ⏎ drop, esc cancel)", grabbed.ID, titles[m.board.GrabTarget()])
+	m.statusIsError = false
+}
+
+// handleBoardGrabKeys moves the drop target of a grabbed card.
+func (m Model) handleBoardGrabKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch key := msg.String(); key {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q":
+		m.board.EndGrab()
+		m.boardMouseDrag = false
+		m.statusMsg = "Move cancelled"
+		m.statusIsError = false
+		return m, nil
+	case "enter", " ":
+		cmd := m.dropGrabbedCard()
+		return m, cmd
+	case "h", "left":
+		m.board.MoveGrabTarget(-1)
+	case "l", "right":
+		m.board.MoveGrabTarget(1)
+	case "1", "2", "3", "4":
+		m.board.SetGrabTarget(int(key[0] - '1'))
+	}
+	m.setGrabStatus()
+	return m, nil
+}
+
+// dropGrabbedCard ends grab mode and persists the move, if the transition
+// rules allow it.
+func (m *Model) dropGrabbedCard() tea.Cmd {
+	grabbed := m.board.GrabbedIssue()
+	if grabbed == nil {
+		return nil
+	}
+	id, origin, target := grabbed.ID, m.board.GrabOrigin(), m.board.GrabTarget()
+	m.board.EndGrab()
+	m.boardMouseDrag = false
+	if target == origin {
+		m.statusMsg = fmt.Sprintf("%s not moved", id)
+		m.statusIsError = false
+		return nil
+	}
+	return m.startEditChecked(boardColumnEdit(m.board.GetSwimLaneMode(), target, id), m.boardRules())
+}
+
+// handleBoardMouse selects the clicked card and drags it between columns.
+// It reports whether the event was consumed.
+func (m Model) handleBoardMouse(msg tea.MouseMsg) (Model, tea.Cmd, bool) {
+	width, height := m.width, m.height-1 // Board body height, as in View
+	switch {
+	case msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft:
+		if m.board.IsGrabbing() {
+			return m, nil, true
+		}
+		col, row, ok := m.board.CardAt(msg.X, msg.Y, width, height)
+		if !ok {
+			return m, nil, false
+		}
+		m.board.SelectCard(col, row)
+		if m.editUnavailable() == "" && m.board.StartGrab() {
+			m.boardMouseDrag = true
+		}
+		return m, nil, true
+
+	case msg.Action == tea.MouseActionMotion && m.boardMouseDrag:
+		if col := m.board.ColumnAt(msg.X, width); col >= 0 && col != m.board.GrabTarget() {
+			m.board.SetGrabTarget(col)
+			m.setGrabStatus()
+		}
+		return m, nil, true
+
+	case msg.Action == tea.MouseActionRelease && m.boardMouseDrag:
+		if col := m.board.ColumnAt(msg.X, width); col >= 0 {
+			m.board.SetGrabTarget(col)
+		}
+		if m.board.GrabTarget() == m.board.GrabOrigin() {
+			// A click without a drag just selects the card
+			m.board.EndGrab()
+			m.boardMouseDrag = false
+			return m, nil, true
+		}
+		cmd := m.dropGrabbedCard()
+		return m, cmd, true
+	}
+	return m, nil, false
+}
@@ -0,0 +1,326 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/writeback"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func keyRunes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+// runEdit sends msgs, runs the write the last one starts and feeds the
+// result back into the model.
+func runEdit(t *testing.T, m Model, msgs ...tea.Msg) Model {
+	t.Helper()
+	var cmd tea.Cmd
+	for _, msg := range msgs {
+		var updated tea.Model
+		updated, cmd = m.Update(msg)
+		m = updated.(Model)
+	}
+	if cmd == nil {
+		t.Fatalf("expected an edit command (status: %q)", m.statusMsg)
+	}
+	applied, ok := cmd().(EditAppliedMsg)
+	if !ok {
+		t.Fatalf("expected EditAppliedMsg from edit command")
+	}
+	if applied.Err != nil {
+		t.Fatalf("edit failed: %v", applied.Err)
+	}
+	updated, _ := m.Update(applied)
+	return updated.(Model)
+}
+
+func TestEditKeysPersistToJSONLAndUndo(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "issues.jsonl")
+	content := `{"id":"E-1","title":"Editable","status":"open","priority":2,"issue_type":"task","created_at":"2026-01-01T00:00:00Z","updated_at":"2026-01-01T00:00:00Z"}` + "\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	issues, err := loader.LoadIssuesFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewModel(issues, nil, "")
+	m.SetEditStore(writeback.NewJSONLStore(path))
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 140, Height: 40})
+	m = updated.(Model)
+
+	m = runEdit(t, m, keyRunes("m"))
+	if got := m.issueMap["E-1"].Status; got != model.StatusInProgress {
+		t.Fatalf("status after m = %s, want in_progress", got)
+	}
+	if m.countOpen != 1 || !strings.Contains(m.statusMsg, "issues.jsonl") {
+		t.Fatalf("counts/status not refreshed: open=%d status=%q", m.countOpen, m.statusMsg)
+	}
+
+	m = runEdit(t, m, keyRunes("P"), keyRunes("0"))
+	if m.editPrompt != editPromptNone || m.issueMap["E-1"].Priority != 0 {
+		t.Fatalf("priority prompt did not apply P0")
+	}
+
+	m = runEdit(t, m, keyRunes("+"), keyRunes("needs-review"), tea.KeyMsg{Type: tea.KeyEnter})
+	if labels := m.issueMap["E-1"].Labels; len(labels) != 1 || labels[0] != "needs-review" {
+		t.Fatalf("label not added: %v", labels)
+	}
+
+	// Board view edits the selected card
+	updated, _ = m.Update(keyRunes("b"))
+	m = updated.(Model)
+	if !m.board.SelectIssueByID("E-1") {
+		t.Fatalf("E-1 missing from board")
+	}
+	m = runEdit(t, m, keyRunes("A"), keyRunes("sam"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.issueMap["E-1"].Assignee != "sam" {
+		t.Fatalf("assignee not set from board view")
+	}
+
+	onDisk, err := loader.LoadIssuesFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := onDisk[0]; got.Status != model.StatusInProgress || got.Priority != 0 || got.Assignee != "sam" || len(got.Labels) != 1 {
+		t.Fatalf("edits not persisted: %+v", got)
+	}
+
+	if m.editHistory.Len() != 4 {
+		t.Fatalf("history len = %d, want 4", m.editHistory.Len())
+	}
+	for i := 0; i < 4; i++ {
+		m = runEdit(t, m, keyRunes("z"))
+	}
+	onDisk, err = loader.LoadIssuesFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := onDisk[0]; got.Status != model.StatusOpen || got.Priority != 2 || got.Assignee != "" || len(got.Labels) != 0 {
+		t.Fatalf("undo did not restore the file: %+v", got)
+	}
+	if got := m.issueMap["E-1"]; got.Status != model.StatusOpen || got.Priority != 2 {
+		t.Fatalf("undo did not restore the model: %+v", got)
+	}
+
+	updated, cmd := m.Update(keyRunes("z"))
+	m = updated.(Model)
+	if cmd != nil || m.statusMsg != "Nothing to undo" {
+		t.Fatalf("expected empty undo stack, status %q", m.statusMsg)
+	}
+}
+
+func TestEditKeysReadOnlyWithoutStore(t *testing.T) {
+	m := NewModel([]model.Issue{{ID: "R-1", Title: "Read only", Status: model.StatusOpen}}, nil, "")
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 140, Height: 40})
+	m = updated.(Model)
+
+	updated, cmd := m.Update(keyRunes("m"))
+	m = updated.(Model)
+	if cmd != nil || !m.statusIsError || !strings.Contains(m.statusMsg, "unavailable") {
+		t.Fatalf("expected read-only status, got %q", m.statusMsg)
+	}
+	if m.issueMap["R-1"].Status != model.StatusOpen {
+		t.Fatalf("read-only model was modified")
+	}
+}
+
+func TestBoardGrabMovesCardsAndEnforcesRules(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "issues.jsonl")
+	content := `{"id":"A","title":"Blocked work","status":"open","priority":1,"issue_type":"task","created_at":"2026-01-02T00:00:00Z","dependencies":[{"issue_id":"A","depends_on_id":"B","type":"blocks"}]}` + "\n" +
+		`{"id":"B","title":"Blocker","status":"open","priority":2,"issue_type":"task","created_at":"2026-01-01T00:00:00Z"}` + "\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	issues, err := loader.LoadIssuesFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewModel(issues, nil, "")
+	m.SetEditStore(writeback.NewJSONLStore(path))
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 140, Height: 40})
+	m = updated.(Model)
+	updated, _ = m.Update(keyRunes("b"))
+	m = updated.(Model)
+
+	// Keyboard: space grabs, l moves the target, enter drops
+	m.board.SelectIssueByID("A")
+	updated, _ = m.Update(keyRunes(" "))
+	m = updated.(Model)
+	if !m.board.IsGrabbing() || !strings.Contains(m.statusMsg, "Moving A") {
+		t.Fatalf("space should grab the card, status %q", m.statusMsg)
+	}
+	m = runEdit(t, m, keyRunes("l"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.board.IsGrabbing() || m.issueMap["A"].Status != model.StatusInProgress {
+		t.Fatalf("drop did not move A to in_progress: %s", m.issueMap["A"].Status)
+	}
+
+	// Closing A while B is open is rejected by the default rules
+	m.board.SelectIssueByID("A")
+	for _, key := range []string{" ", "4"} {
+		updated, _ = m.Update(keyRunes(key))
+		m = updated.(Model)
+	}
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if cmd != nil || !m.statusIsError || !strings.Contains(m.statusMsg, "open blocker") {
+		t.Fatalf("closing a blocked issue should be denied, status %q", m.statusMsg)
+	}
+	if m.issueMap["A"].Status != model.StatusInProgress || m.board.IsGrabbing() {
+		t.Fatalf("denied move changed state")
+	}
+
+	// Mouse: drag B from the first column into the second (columns are 35 cells wide at 140)
+	m = runEdit(t, m,
+		tea.MouseMsg{X: 5, Y: 5, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft},
+		tea.MouseMsg{X: 40, Y: 5, Action: tea.MouseActionMotion, Button: tea.MouseButtonLeft},
+		tea.MouseMsg{X: 40, Y: 5, Action: tea.MouseActionRelease, Button: tea.MouseButtonLeft},
+	)
+	if m.issueMap["B"].Status != model.StatusInProgress {
+		t.Fatalf("mouse drag did not move B: %s", m.issueMap["B"].Status)
+	}
+
+	// Priority swimlanes: dropping into a column sets that priority
+	updated, _ = m.Update(keyRunes("s"))
+	m = updated.(Model)
+	m.board.SelectIssueByID("B")
+	m = runEdit(t, m, keyRunes(" "), keyRunes("1"), keyRunes(" "))
+	if m.issueMap["B"].Priority != 0 {
+		t.Fatalf("priority lane drop did not set P0: %d", m.issueMap["B"].Priority)
+	}
+
+	onDisk, err := loader.LoadIssuesFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, issue := range onDisk {
+		if issue.Status != model.StatusInProgress {
+			t.Fatalf("%s not persisted: %s", issue.ID, issue.Status)
+		}
+		if issue.ID == "B" && issue.Priority != 0 {
+			t.Fatalf("B priority not persisted: %d", issue.Priority)
+		}
+	}
+}
+
+func TestGraphDependencyEditingRefusesCyclesAndPreviews(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "issues.jsonl")
+	content := `{"id":"A","title":"Dependent","status":"open","priority":1,"issue_type":"task","created_at":"2026-01-01T00:00:00Z","dependencies":[{"issue_id":"A","depends_on_id":"B","type":"blocks"}]}` + "\n" +
+		`{"id":"B","title":"Blocker","status":"open","priority":2,"issue_type":"task","created_at":"2026-01-01T00:00:00Z"}` + "\n" +
+		`{"id":"C","title":"Standalone","status":"open","priority":2,"issue_type":"task","created_at":"2026-01-01T00:00:00Z"}` + "\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	issues, err := loader.LoadIssuesFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewModel(issues, nil, "")
+	m.SetEditStore(writeback.NewJSONLStore(path))
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 140, Height: 40})
+	m = updated.(Model)
+	updated, _ = m.Update(keyRunes("g"))
+	m = updated.(Model)
+	if m.focused != focusGraph {
+		t.Fatalf("expected graph focus")
+	}
+
+	// B depending on A would close the cycle A → B → A
+	m.graphView.SelectByID("B")
+	updated, _ = m.Update(keyRunes(" "))
+	m = updated.(Model)
+	m.graphView.SelectByID("A")
+	updated, _ = m.Update(keyRunes("+"))
+	m = updated.(Model)
+	updated, cmd := m.Update(keyRunes("b"))
+	m = updated.(Model)
+	if cmd != nil || m.depEdit == nil || len(m.depEdit.cycle) == 0 {
+		t.Fatalf("cyclic dependency was not refused (status %q)", m.statusMsg)
+	}
+	if view := m.View(); !strings.Contains(view, "B → A → B") {
+		t.Fatalf("refusal does not show the cycle path:\n%s", view)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.depEdit != nil || m.graphView.LinkSource() != "B" {
+		t.Fatalf("esc should close the editor and keep the link")
+	}
+
+	// B depending on C is fine; the preview shows B's critical path growing
+	m.graphView.SelectByID("C")
+	updated, _ = m.Update(keyRunes("+"))
+	m = updated.(Model)
+	updated, cmd = m.Update(keyRunes("b"))
+	m = updated.(Model)
+	if cmd == nil {
+		t.Fatalf("expected a preview command")
+	}
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+	preview := m.depEdit.preview
+	if preview == nil || len(preview.Nodes) != 2 || preview.LongestPathAfter <= preview.LongestPathBefore {
+		t.Fatalf("unexpected preview: %+v", preview)
+	}
+	if view := m.View(); !strings.Contains(view, "PageRank") || !strings.Contains(view, "B is blocked by C") {
+		t.Fatalf("preview not rendered:\n%s", view)
+	}
+	m = runEdit(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if deps := m.issueMap["B"].Dependencies; len(deps) != 1 || deps[0].DependsOnID != "C" {
+		t.Fatalf("dependency not applied: %+v", deps)
+	}
+	if m.graphView.LinkSource() != "" {
+		t.Fatalf("link should be cleared after an edit")
+	}
+
+	// Removing A's dependency on B
+	m.graphView.SelectByID("A")
+	updated, _ = m.Update(keyRunes(" "))
+	m = updated.(Model)
+	m.graphView.SelectByID("B")
+	updated, cmd = m.Update(keyRunes("-"))
+	m = updated.(Model)
+	if cmd == nil || m.depEdit == nil || !m.depEdit.remove {
+		t.Fatalf("expected a removal preview (status %q)", m.statusMsg)
+	}
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+	m = runEdit(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if len(m.issueMap["A"].Dependencies) != 0 {
+		t.Fatalf("dependency not removed: %+v", m.issueMap["A"].Dependencies)
+	}
+
+	onDisk, err := loader.LoadIssuesFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, issue := range onDisk {
+		switch issue.ID {
+		case "A":
+			if len(issue.Dependencies) != 0 {
+				t.Fatalf("removal not persisted: %+v", issue.Dependencies)
+			}
+		case "B":
+			if len(issue.Dependencies) != 1 || issue.Dependencies[0].DependsOnID != "C" {
+				t.Fatalf("addition not persisted: %+v", issue.Dependencies)
+			}
+		}
+	}
+
+	// Undo works from the graph view
+	m = runEdit(t, m, keyRunes("z"))
+	if len(m.issueMap["A"].Dependencies) != 1 {
+		t.Fatalf("undo did not restore A's dependency")
+	}
+}
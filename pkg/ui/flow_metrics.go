@@ -0,0 +1,318 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/flow"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// FlowMetricsModel renders Kanban flow metrics: a stacked-area cumulative
+// flow diagram and a WIP aging scatter plot. The cursor walks the
+// in-progress items in the scatter.
+type FlowMetricsModel struct {
+	report  flow.Report
+	loading bool // Git history still loading; report uses timestamps
+	cursor  int  // Index into report.WIPAging
+	width   int
+	height  int
+	theme   Theme
+}
+
+// NewFlowMetricsModel creates an empty flow metrics view
+func NewFlowMetricsModel(theme Theme) FlowMetricsModel {
+	return FlowMetricsModel{theme: theme}
+}
+
+// SetData replaces the report. loading marks a report computed before git
+// history was available.
+func (m *FlowMetricsModel) SetData(report flow.Report, loading bool) {
+	selected := m.SelectedIssueID()
+	m.report = report
+	m.loading = loading
+	m.cursor = 0
+	for i, item := range report.WIPAging {
+		if item.IssueID == selected {
+			m.cursor = i
+		}
+	}
+}
+
+// SetSize updates the view dimensions
+func (m *FlowMetricsModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// MoveUp selects the previous in-progress item
+func (m *FlowMetricsModel) MoveUp() {
+	if m.cursor > 0 {
+		m.cursor--
+	}
+}
+
+// MoveDown selects the next in-progress item
+func (m *FlowMetricsModel) MoveDown() {
+	if m.cursor < len(m.report.WIPAging)-1 {
+		m.cursor++
+	}
+}
+
+// SelectedIssueID returns the in-progress issue under the cursor, or ""
+func (m *FlowMetricsModel) SelectedIssueID() string {
+	if m.cursor < len(m.report.WIPAging) {
+		return m.report.WIPAging[m.cursor].IssueID
+	}
+	return ""
+}
+
+// chartHeights splits the rows left after 11 fixed lines (title, summary,
+// two headings, two axes, legend, two blanks, selection, key hints)
+// between the CFD and the aging plot.
+func (m *FlowMetricsModel) chartHeights() (cfd, aging int) {
+	available := max(m.height-11, 6)
+	cfd = max(available*3/5, 3)
+	return cfd, max(available-cfd, 3)
+}
+
+// View renders the metrics
+func (m *FlowMetricsModel) View() string {
+	if m.width == 0 {
+		m.width = 80
+	}
+	if m.height == 0 {
+		m.height = 30
+	}
+	t := m.theme
+	r := m.report
+	dim := t.Renderer.NewStyle().Foreground(t.Secondary)
+	italic := dim.Italic(true)
+	heading := t.Renderer.NewStyle().Foreground(t.Primary).Bold(true)
+
+	var sb strings.Builder
+	sb.WriteString(heading.Render("Flow Metrics"))
+	source := "status changes from git history"
+	switch {
+	case m.loading:
+		source = "timestamps only (git history loading…)"
+	case r.Source == flow.SourceTimestamps:
+		source = "timestamps only (no git history)"
+	}
+	sb.WriteString("  " + dim.Render(fmt.Sprintf("last %d days · %s", r.Days, source)))
+	sb.WriteString("\n")
+
+	overP85 := 0
+	for _, item := range r.WIPAging {
+		if item.OverP85 {
+			overP85++
+		}
+	}
+	summary := fmt.Sprintf("Lead p50 %s p85 %s · Cycle p50 %s p85 %s · Throughput %.1f/wk (%d) · WIP %d",
+		flowDays(r.LeadTime.P50Days, r.LeadTime.Count), flowDays(r.LeadTime.P85Days, r.LeadTime.Count),
+		flowDays(r.CycleTime.P50Days, r.CycleTime.Count), flowDays(r.CycleTime.P85Days, r.CycleTime.Count),
+		r.Throughput.MeanPerWeek, r.Throughput.Total, len(r.WIPAging))
+	if overP85 > 0 {
+		summary += fmt.Sprintf(" (%d over p85)", overP85)
+	}
+	sb.WriteString(truncate(summary, m.width-1))
+	sb.WriteString("\n\n")
+
+	cfdHeight, agingHeight := m.chartHeights()
+	sb.WriteString(heading.Render("Cumulative flow"))
+	sb.WriteString("\n")
+	sb.WriteString(m.renderCFD(cfdHeight))
+	sb.WriteString("\n")
+	sb.WriteString(heading.Render("WIP aging"))
+	sb.WriteString("  " + dim.Render("days in progress; ┈ cycle time p50/p85"))
+	sb.WriteString("\n")
+	sb.WriteString(m.renderAging(agingHeight))
+
+	if m.cursor < len(r.WIPAging) {
+		item := r.WIPAging[m.cursor]
+		detail := fmt.Sprintf("%s %s · %.1fd in progress", item.IssueID, item.Title, item.AgeDays)
+		if r.CycleTime.Count > 0 {
+			detail += fmt.Sprintf(" · older than %.0f%% of completed work", item.Percentile)
+		}
+		if item.Assignee != "" {
+			detail += " · " + item.Assignee
+		}
+		sb.WriteString(truncate(detail, m.width-1))
+	}
+	sb.WriteString("\n")
+	sb.WriteString(italic.Render("j/k: select WIP | enter: open issue | esc: back"))
+	return sb.String()
+}
+
+// flowDays formats a duration in days, or "–" without samples
+func flowDays(days float64, count int) string {
+	if count == 0 {
+		return "–"
+	}
+	return fmt.Sprintf("%.1fd", days)
+}
+
+// renderCFD draws the series as stacked areas, done work at the bottom,
+// followed by a date axis and a legend.
+func (m *FlowMetricsModel) renderCFD(height int) string {
+	t := m.theme
+	dim := t.Renderer.NewStyle().Foreground(t.Secondary)
+	cfd := m.report.CFD
+	days := len(cfd.Dates)
+	if days == 0 || len(cfd.Series) == 0 {
+		return t.Renderer.NewStyle().Italic(true).Foreground(t.Secondary).Render("  No issues in this window") + "\n\n\n"
+	}
+
+	peak := 1
+	for d := 0; d < days; d++ {
+		total := 0
+		for _, s := range cfd.Series {
+			total += s.Counts[d]
+		}
+		peak = max(peak, total)
+	}
+
+	axisWidth := len(fmt.Sprint(peak)) + 1
+	width := max(m.width-axisWidth-2, 10)
+	styles := make([]lipgloss.Style, len(cfd.Series))
+	glyphs := make([]string, len(cfd.Series))
+	for i, s := range cfd.Series {
+		styles[i] = t.Renderer.NewStyle().Foreground(t.GetStatusColor(string(s.Status)))
+		glyphs[i] = cfdGlyph(s.Status, i)
+	}
+
+	var sb strings.Builder
+	for row := height - 1; row >= 0; row-- {
+		label := ""
+		switch row {
+		case height - 1:
+			label = fmt.Sprint(peak)
+		case 0:
+			label = "0"
+		}
+		sb.WriteString(dim.Render(fmt.Sprintf("%*s│", axisWidth, label)))
+		threshold := (float64(row) + 0.5) / float64(height) * float64(peak)
+		for col := 0; col < width; col++ {
+			d := col * (days - 1) / max(width-1, 1)
+			cum := 0
+			cell := " "
+			for i, s := range cfd.Series {
+				cum += s.Counts[d]
+				if float64(cum) > threshold {
+					cell = styles[i].Render(glyphs[i])
+					break
+				}
+			}
+			sb.WriteString(cell)
+		}
+		sb.WriteString("\n")
+	}
+
+	first, last := cfd.Dates[0], cfd.Dates[days-1]
+	gap := max(width-len(first)-len(last), 1)
+	sb.WriteString(dim.Render(strings.Repeat(" ", axisWidth+1) + first + strings.Repeat(" ", gap) + last))
+	sb.WriteString("\n")
+
+	var legend []string
+	for i := len(cfd.Series) - 1; i >= 0; i-- {
+		s := cfd.Series[i]
+		legend = append(legend, styles[i].Render(glyphs[i])+fmt.Sprintf(" %s %d", s.Status, s.Counts[days-1]))
+	}
+	sb.WriteString(strings.Repeat(" ", axisWidth+1) + strings.Join(legend, "  "))
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// cfdGlyph keeps statuses that share the theme's fallback color apart.
+func cfdGlyph(status model.Status, i int) string {
+	switch status {
+	case model.StatusOpen, model.StatusInProgress, model.StatusBlocked, model.StatusClosed:
+		return "█"
+	}
+	return []string{"▓", "▒", "░"}[i%3]
+}
+
+// renderAging plots each in-progress item as a dot at its age, oldest on
+// the left, over dotted lines at the cycle time p50 and p85.
+func (m *FlowMetricsModel) renderAging(height int) string {
+	t := m.theme
+	dim := t.Renderer.NewStyle().Foreground(t.Secondary)
+	items := m.report.WIPAging
+	ct := m.report.CycleTime
+	if len(items) == 0 {
+		return t.Renderer.NewStyle().Italic(true).Foreground(t.Secondary).Render("  Nothing in progress") + "\n\n"
+	}
+
+	top := math.Max(items[0].AgeDays, ct.P85Days)
+	top = math.Max(math.Ceil(top), 1)
+	axisWidth := len(fmt.Sprintf("%.0fd", top)) + 1
+	width := max(m.width-axisWidth-2, 10)
+	rowOf := func(days float64) int {
+		return min(int(days/top*float64(height-1)+0.5), height-1)
+	}
+
+	// Spread items evenly across the plot; crowded items share a column.
+	cols := make(map[int][]int)
+	for i := range items {
+		col := 0
+		if len(items) > 1 {
+			col = i * (width - 1) / (len(items) - 1)
+		}
+		cols[col] = append(cols[col], i)
+	}
+
+	p50Row, p85Row := -1, -1
+	if ct.Count > 0 {
+		p50Row, p85Row = rowOf(ct.P50Days), rowOf(ct.P85Days)
+	}
+	dot := t.Renderer.NewStyle().Foreground(t.InProgress)
+	late := t.Renderer.NewStyle().Foreground(t.Blocked).Bold(true)
+	selected := t.Renderer.NewStyle().Foreground(t.Primary).Bold(true)
+
+	var sb strings.Builder
+	for row := height - 1; row >= 0; row-- {
+		label := ""
+		switch row {
+		case height - 1:
+			label = fmt.Sprintf("%.0fd", top)
+		case 0:
+			label = "0d"
+		}
+		sb.WriteString(dim.Render(fmt.Sprintf("%*s│", axisWidth, label)))
+		for col := 0; col < width; col++ {
+			cell := " "
+			if row == p50Row || row == p85Row {
+				cell = dim.Render("┈")
+			}
+			for _, i := range cols[col] {
+				if rowOf(items[i].AgeDays) != row {
+					continue
+				}
+				switch {
+				case i == m.cursor:
+					cell = selected.Render("◉")
+				case items[i].OverP85:
+					cell = late.Render("●")
+				default:
+					cell = dot.Render("●")
+				}
+				if i == m.cursor {
+					break
+				}
+			}
+			sb.WriteString(cell)
+		}
+		switch row {
+		case p85Row:
+			sb.WriteString(dim.Render(" p85"))
+		case p50Row:
+			sb.WriteString(dim.Render(" p50"))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString(dim.Render(strings.Repeat(" ", axisWidth) + "└" + strings.Repeat("─", width)))
+	sb.WriteString("\n")
+	return sb.String()
+}
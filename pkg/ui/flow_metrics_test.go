@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/flow"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func flowMetricsTestIssues(now time.Time) []model.Issue {
+	ago := func(days float64) time.Time { return now.Add(-time.Duration(days * 24 * float64(time.Hour))) }
+	closedAt := func(days float64) *time.Time { t := ago(days); return &t }
+	return []model.Issue{
+		{ID: "A", Title: "Shipped", Status: model.StatusClosed, CreatedAt: ago(20), ClosedAt: closedAt(15)},
+		{ID: "B", Title: "Also shipped", Status: model.StatusClosed, CreatedAt: ago(18), ClosedAt: closedAt(10)},
+		{ID: "C", Title: "Old work", Status: model.StatusInProgress, CreatedAt: ago(16), UpdatedAt: ago(12)},
+		{ID: "D", Title: "Fresh work", Status: model.StatusInProgress, CreatedAt: ago(3), UpdatedAt: ago(1)},
+		{ID: "E", Title: "Waiting", Status: model.StatusOpen, CreatedAt: ago(2)},
+	}
+}
+
+func TestFlowMetricsModelRendersChartsAndSelection(t *testing.T) {
+	now := time.Now()
+	m := NewFlowMetricsModel(Theme{Renderer: lipgloss.DefaultRenderer()})
+	m.SetData(flow.Compute(flowMetricsTestIssues(now), nil, flow.Options{Now: now}), false)
+	m.SetSize(100, 30)
+
+	view := m.View()
+	for _, want := range []string{"Flow Metrics", "timestamps only", "Cumulative flow", "WIP aging", "closed 2", "open 1", "in_progress 2", "█", "◉"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+	if lines := strings.Count(view, "\n") + 1; lines > 30 {
+		t.Errorf("view is %d lines, want at most 30", lines)
+	}
+
+	// WIP aging lists the oldest item first.
+	if m.SelectedIssueID() != "C" || !strings.Contains(view, "C Old work · 12.0d in progress") {
+		t.Fatalf("selected %q:\n%s", m.SelectedIssueID(), view)
+	}
+	m.MoveDown()
+	m.MoveDown()
+	if m.SelectedIssueID() != "D" {
+		t.Errorf("MoveDown selected %q, want D", m.SelectedIssueID())
+	}
+}
+
+func TestFlowMetricsViewOpensAndJumpsToIssue(t *testing.T) {
+	m := NewModel(flowMetricsTestIssues(time.Now()), nil, "")
+	newM, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	m = newM.(Model)
+
+	newM, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("%")})
+	m = newM.(Model)
+	if m.FocusState() != "flow_metrics" || m.CurrentContext() != ContextFlowMetrics {
+		t.Fatalf("focus = %q, want flow_metrics", m.FocusState())
+	}
+	if !strings.Contains(m.View(), "Cumulative flow") {
+		t.Fatalf("flow metrics not rendered:\n%s", m.View())
+	}
+
+	newM, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newM.(Model)
+	if m.FocusState() != "detail" {
+		t.Fatalf("enter: focus = %q, want detail", m.FocusState())
+	}
+	if sel, ok := m.list.SelectedItem().(IssueItem); !ok || sel.Issue.ID != "C" {
+		t.Errorf("selected %+v, want C", m.list.SelectedItem())
+	}
+
+	newM, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("%")})
+	m = newM.(Model)
+	newM, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newM.(Model)
+	if m.FocusState() != "list" {
+		t.Errorf("esc: focus = %q, want list", m.FocusState())
+	}
+}
@@ -0,0 +1,286 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// GanttModel renders a resource-constrained schedule as a Gantt chart:
+// one block per worker, one bar per scheduled issue on a shared day axis.
+type GanttModel struct {
+	schedule     analysis.Schedule
+	rosterSource string
+	lines        []ganttLine
+	cursor       int // Index into lines; always a task line when any exist
+	scrollOffset int
+	width        int
+	height       int
+	theme        Theme
+}
+
+// ganttLine is either a worker header (task == nil) or a task bar.
+type ganttLine struct {
+	worker *analysis.WorkerTimeline
+	task   *analysis.ScheduledTask
+}
+
+// NewGanttModel creates an empty schedule view
+func NewGanttModel(theme Theme) GanttModel {
+	return GanttModel{theme: theme}
+}
+
+// SetData lays out a schedule by worker. source names the roster file, or
+// "derived" when none was found.
+func (m *GanttModel) SetData(schedule analysis.Schedule, source string) {
+	m.schedule = schedule
+	m.rosterSource = source
+	m.lines = m.lines[:0]
+	for i := range m.schedule.Workers {
+		w := &m.schedule.Workers[i]
+		m.lines = append(m.lines, ganttLine{worker: w})
+		for j := range w.Tasks {
+			m.lines = append(m.lines, ganttLine{worker: w, task: &w.Tasks[j]})
+		}
+	}
+	m.cursor, m.scrollOffset = 0, 0
+	m.GoToStart()
+}
+
+// SetSize updates the view dimensions
+func (m *GanttModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.ensureVisible()
+}
+
+// MoveUp moves the cursor to the previous task
+func (m *GanttModel) MoveUp() {
+	for i := m.cursor - 1; i >= 0; i-- {
+		if m.lines[i].task != nil {
+			m.cursor = i
+			break
+		}
+	}
+	m.ensureVisible()
+}
+
+// MoveDown moves the cursor to the next task
+func (m *GanttModel) MoveDown() {
+	for i := m.cursor + 1; i < len(m.lines); i++ {
+		if m.lines[i].task != nil {
+			m.cursor = i
+			break
+		}
+	}
+	m.ensureVisible()
+}
+
+// GoToStart selects the first task
+func (m *GanttModel) GoToStart() {
+	m.cursor = len(m.lines)
+	for i := range m.lines {
+		if m.lines[i].task != nil {
+			m.cursor = i
+			break
+		}
+	}
+	if m.cursor == len(m.lines) {
+		m.cursor = 0
+	}
+	m.scrollOffset = 0
+	m.ensureVisible()
+}
+
+// GoToEnd selects the last task
+func (m *GanttModel) GoToEnd() {
+	for i := len(m.lines) - 1; i >= 0; i-- {
+		if m.lines[i].task != nil {
+			m.cursor = i
+			break
+		}
+	}
+	m.ensureVisible()
+}
+
+// SelectedIssueID returns the issue under the cursor, or "" if none
+func (m *GanttModel) SelectedIssueID() string {
+	if m.cursor < len(m.lines) && m.lines[m.cursor].task != nil {
+		return m.lines[m.cursor].task.IssueID
+	}
+	return ""
+}
+
+// visibleRowCount returns how many chart lines fit. The header takes 5
+// lines (title, summary, blank, axis, rule) and the footer up to 4
+// (scroll position, blank, selected task, key hints).
+func (m *GanttModel) visibleRowCount() int {
+	if available := m.height - 9; available > 1 {
+		return available
+	}
+	return 1
+}
+
+func (m *GanttModel) ensureVisible() {
+	visible := m.visibleRowCount()
+	if m.cursor < m.scrollOffset {
+		m.scrollOffset = m.cursor
+		// Keep the worker header of the first visible task on screen
+		if m.scrollOffset > 0 && m.lines[m.scrollOffset-1].task == nil {
+			m.scrollOffset--
+		}
+	} else if m.cursor >= m.scrollOffset+visible {
+		m.scrollOffset = m.cursor - visible + 1
+	}
+}
+
+// View renders the chart
+func (m *GanttModel) View() string {
+	if m.width == 0 {
+		m.width = 80
+	}
+	if m.height == 0 {
+		m.height = 20
+	}
+	t := m.theme
+	dim := t.Renderer.NewStyle().Foreground(t.Secondary)
+	italic := dim.Italic(true)
+
+	var sb strings.Builder
+	sb.WriteString(t.Renderer.NewStyle().Foreground(t.Primary).Bold(true).Render("Schedule"))
+	source := "roster: " + m.rosterSource
+	if m.rosterSource == "derived" {
+		source = "roster: assignees (add .bv/roster.yaml for capacity and skills)"
+	}
+	sb.WriteString("  " + dim.Render(source))
+	sb.WriteString("\n")
+
+	s := m.schedule
+	summary := fmt.Sprintf("%d tasks · %d workers · finish in %.1f days (%s)",
+		len(s.Tasks), len(s.Workers), s.Days, s.Finish.Local().Format("Mon Jan 2"))
+	if n := len(s.Unscheduled); n > 0 {
+		summary += fmt.Sprintf(" · %d unscheduled", n)
+	}
+	sb.WriteString(summary)
+	sb.WriteString("\n\n")
+
+	if len(s.Tasks) == 0 {
+		sb.WriteString(italic.Render("  No open work to schedule"))
+		sb.WriteString("\n")
+		m.writeUnscheduled(&sb)
+		return sb.String()
+	}
+
+	labelWidth := min(max(m.width/3, 20), 40)
+	barWidth := max(m.width-labelWidth-4, 10)
+	scale := float64(barWidth) / math.Max(s.Days, 0.01)
+
+	sb.WriteString(dim.Render(strings.Repeat(" ", labelWidth+2) + ganttAxis(s.Days, barWidth)))
+	sb.WriteString("\n")
+	sb.WriteString(dim.Render(strings.Repeat("─", min(labelWidth+2+barWidth, m.width-1))))
+	sb.WriteString("\n")
+
+	visible := m.visibleRowCount()
+	end := min(m.scrollOffset+visible, len(m.lines))
+	for i := m.scrollOffset; i < end; i++ {
+		line := m.lines[i]
+		if line.task == nil {
+			w := line.worker
+			head := fmt.Sprintf("%s  %.0fh/day · %.0fh busy · %.0f%%", w.Name, w.HoursPerDay, w.BusyHours, w.Utilization*100)
+			sb.WriteString(t.Renderer.NewStyle().Foreground(t.Secondary).Bold(true).Render(truncate(head, m.width-1)))
+			sb.WriteString("\n")
+			continue
+		}
+
+		task := line.task
+		selected := i == m.cursor
+		prefix := "  "
+		if selected {
+			prefix = "> "
+		}
+		label := padRight(truncate(task.IssueID+" "+task.Title, labelWidth), labelWidth)
+
+		from := int(task.StartDay * scale)
+		to := max(int(math.Ceil(task.EndDay*scale)), from+1)
+		to = min(to, barWidth)
+		from = min(from, to-1)
+		barStyle := t.Renderer.NewStyle().Foreground(t.Open)
+		if task.Status == model.StatusInProgress {
+			barStyle = barStyle.Foreground(t.InProgress)
+		}
+		glyph := "█"
+		if !task.Estimated {
+			glyph = "▒" // Duration is the project median, not an estimate
+		}
+
+		rowStyle := t.Renderer.NewStyle()
+		if selected {
+			rowStyle = rowStyle.Foreground(t.Primary).Bold(true).Background(ThemeBg("#333"))
+		}
+		sb.WriteString(rowStyle.Render(prefix + label))
+		sb.WriteString(strings.Repeat(" ", from+2))
+		sb.WriteString(barStyle.Render(strings.Repeat(glyph, to-from)))
+		sb.WriteString("\n")
+	}
+	if len(m.lines) > visible {
+		sb.WriteString(italic.Render(fmt.Sprintf("  [%d-%d of %d]", m.scrollOffset+1, end, len(m.lines))))
+		sb.WriteString("\n")
+	}
+
+	if task := m.selectedTask(); task != nil {
+		detail := fmt.Sprintf("%s → %s · %s → %s · %dm", task.IssueID, task.Worker,
+			task.Start.Local().Format("Jan 2 15:04"), task.End.Local().Format("Jan 2 15:04"), task.Minutes)
+		if task.Pinned {
+			detail += " · assigned"
+		}
+		if len(task.BlockedBy) > 0 {
+			detail += " · after " + strings.Join(task.BlockedBy, ", ")
+		}
+		sb.WriteString("\n")
+		sb.WriteString(truncate(detail, m.width-1))
+	}
+	sb.WriteString("\n")
+	sb.WriteString(italic.Render("j/k: navigate | enter: open issue | █ estimated ▒ median | esc: back"))
+	return sb.String()
+}
+
+func (m *GanttModel) selectedTask() *analysis.ScheduledTask {
+	if m.cursor < len(m.lines) {
+		return m.lines[m.cursor].task
+	}
+	return nil
+}
+
+func (m *GanttModel) writeUnscheduled(sb *strings.Builder) {
+	for _, u := range m.schedule.Unscheduled {
+		sb.WriteString(truncate(fmt.Sprintf("  %s: %s", u.IssueID, u.Reason), m.width-1))
+		sb.WriteString("\n")
+	}
+}
+
+// ganttAxis labels the bar area with day offsets at even steps.
+func ganttAxis(days float64, width int) string {
+	axis := []rune(strings.Repeat(" ", width))
+	step := 1.0
+	for _, s := range []float64{1, 2, 5, 10, 20, 50, 100, 200, 500} {
+		step = s
+		if days/s*6 <= float64(width)/2 { // ~6 columns per tick label
+			break
+		}
+	}
+	if days < 1 {
+		step = 0.25
+	}
+	for d := 0.0; d <= days+1e-9; d += step {
+		col := int(d / math.Max(days, 0.01) * float64(width))
+		mark := fmt.Sprintf("|%gd", d)
+		if col+len(mark) > width {
+			break
+		}
+		copy(axis[col:], []rune(mark))
+	}
+	return string(axis)
+}
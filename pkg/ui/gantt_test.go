@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func ganttTestIssues() []model.Issue {
+	est := func(m int) *int { return &m }
+	return []model.Issue{
+		{ID: "A", Title: "Design", Status: model.StatusOpen, Priority: 0, Assignee: "ana", EstimatedMinutes: est(360)},
+		{ID: "B", Title: "Build", Status: model.StatusOpen, Priority: 1, Assignee: "ana", EstimatedMinutes: est(720),
+			Dependencies: []*model.Dependency{{IssueID: "B", DependsOnID: "A", Type: model.DepBlocks}}},
+		{ID: "C", Title: "Docs", Status: model.StatusInProgress, Priority: 2, Assignee: "bo"},
+	}
+}
+
+func TestGanttModelNavigationSkipsWorkerHeaders(t *testing.T) {
+	issues := ganttTestIssues()
+	m := NewGanttModel(Theme{Renderer: lipgloss.DefaultRenderer()})
+	m.SetData(analysis.BuildSchedule(issues, analysis.DefaultRoster(issues, 0), analysis.ScheduleOptions{Start: time.Now()}), "derived")
+	m.SetSize(100, 30)
+
+	// Lines: ana, A, B, bo, C
+	var got []string
+	for i := 0; i < 4; i++ {
+		got = append(got, m.SelectedIssueID())
+		m.MoveDown()
+	}
+	if strings.Join(got, ",") != "A,B,C,C" {
+		t.Fatalf("walked %v, want A,B,C,C", got)
+	}
+	m.GoToStart()
+	if m.SelectedIssueID() != "A" {
+		t.Errorf("GoToStart selected %q", m.SelectedIssueID())
+	}
+
+	view := m.View()
+	for _, want := range []string{"Schedule", "ana", "bo", "A Design", "C Docs", "█", "▒"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+	m.MoveDown()
+	if view := m.View(); !strings.Contains(view, "B → ana") || !strings.Contains(view, "after A") {
+		t.Errorf("selected task line missing for B:\n%s", view)
+	}
+}
+
+func TestGanttViewOpensAndJumpsToIssue(t *testing.T) {
+	m := NewModel(ganttTestIssues(), nil, "")
+	newM, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	m = newM.(Model)
+
+	newM, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("@")})
+	m = newM.(Model)
+	if m.FocusState() != "gantt" || m.CurrentContext() != ContextGantt {
+		t.Fatalf("focus = %q, want gantt", m.FocusState())
+	}
+	if !strings.Contains(m.View(), "finish in") {
+		t.Fatalf("schedule not rendered:\n%s", m.View())
+	}
+
+	newM, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	m = newM.(Model)
+	newM, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newM.(Model)
+	if m.FocusState() != "detail" {
+		t.Fatalf("enter: focus = %q, want detail", m.FocusState())
+	}
+	if sel, ok := m.list.SelectedItem().(IssueItem); !ok || sel.Issue.ID != "B" {
+		t.Errorf("selected %+v, want B", m.list.SelectedItem())
+	}
+
+	newM, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("@")})
+	m = newM.(Model)
+	newM, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newM.(Model)
+	if m.FocusState() != "list" {
+		t.Errorf("esc: focus = %q, want list", m.FocusState())
+	}
+}
@@ -29,6 +29,15 @@ type GraphModel struct {
 	// Flat list for navigation
 	sortedIDs []string
 
+	// Issue marked as the dependent side of a dependency being edited
+	linkSource string
+
+	// Dependency-graph communities; communityOf maps an ID to its index.
+	// Nodes are colored by status unless colorByCommunity is on.
+	communities      *analysis.CommunityReport
+	communityOf      map[string]int
+	colorByCommunity bool
+
 	// Precomputed rankings for all metrics (id -> rank, 1-indexed)
 	rankPageRank     map[string]int
 	rankBetweenness  map[string]int
@@ -88,6 +97,7 @@ func (g *GraphModel) SetSnapshot(snapshot *DataSnapshot) {
 		g.rankCriticalPath = snapshot.GraphLayout.RankCriticalPath
 		g.rankInDegree = snapshot.GraphLayout.RankInDegree
 		g.rankOutDegree = snapshot.GraphLayout.RankOutDegree
+		g.setCommunities(snapshot.GraphLayout.Communities)
 	} else {
 		g.rebuildGraph()
 	}
@@ -109,6 +119,9 @@ func (g *GraphModel) SetSnapshot(snapshot *DataSnapshot) {
 	if g.selectedIdx >= len(g.sortedIDs) {
 		g.selectedIdx = 0
 	}
+	if _, ok := g.issueMap[g.linkSource]; !ok {
+		g.linkSource = ""
+	}
 }
 
 // SetIssues updates the graph data preserving the selected issue if possible
@@ -168,9 +181,16 @@ func (g *GraphModel) rebuildGraph() {
 		}
 	}
 
+	if _, ok := g.issueMap[g.linkSource]; !ok {
+		g.linkSource = ""
+	}
+
 	// Compute rankings for all metrics
 	g.computeRankings()
 
+	communities := analysis.DetectCommunities(g.issues, analysis.CommunityOptions{IncludeClosed: true})
+	g.setCommunities(&communities)
+
 	// Sort by critical path score if available, else by ID
 	if g.insights != nil && g.insights.Stats != nil {
 		sort.Slice(g.sortedIDs, func(i, j int) bool {
@@ -190,6 +210,41 @@ func (g *GraphModel) rebuildGraph() {
 	}
 }
 
+// setCommunities installs a community report for node coloring.
+func (g *GraphModel) setCommunities(report *analysis.CommunityReport) {
+	g.communities = report
+	g.communityOf = nil
+	if report != nil {
+		g.communityOf = report.CommunityOf()
+	}
+}
+
+// community returns the community an issue belongs to, if any.
+func (g *GraphModel) community(id string) *analysis.Community {
+	if i, ok := g.communityOf[id]; ok {
+		return &g.communities.Communities[i]
+	}
+	return nil
+}
+
+// ToggleCommunityColors switches node coloring between status and
+// community, and reports whether community coloring is now on.
+func (g *GraphModel) ToggleCommunityColors() bool {
+	g.colorByCommunity = !g.colorByCommunity
+	return g.colorByCommunity
+}
+
+// nodeColor colors nodes by status, or clustered issues by community when
+// community coloring is on.
+func (g *GraphModel) nodeColor(id string, issue *model.Issue, t Theme) lipgloss.AdaptiveColor {
+	if g.colorByCommunity {
+		if c := g.community(id); c != nil {
+			return RepoColors[(c.ID-1)%len(RepoColors)]
+		}
+	}
+	return getStatusColor(issue.Status, t)
+}
+
 // computeRankings precomputes rankings for all metrics
 func (g *GraphModel) computeRankings() {
 	g.rankPageRank = nil
@@ -280,6 +335,28 @@ func (g *GraphModel) SelectByID(id string) bool {
 	return false
 }
 
+// MarkLinkSource marks the selected issue as the dependent side of a new or
+// removed dependency. Marking the same issue again clears the mark.
+func (g *GraphModel) MarkLinkSource() string {
+	sel := g.SelectedIssue()
+	if sel == nil || sel.ID == g.linkSource {
+		g.linkSource = ""
+	} else {
+		g.linkSource = sel.ID
+	}
+	return g.linkSource
+}
+
+// LinkSource returns the marked issue ID, or "" if none.
+func (g *GraphModel) LinkSource() string {
+	return g.linkSource
+}
+
+// ClearLinkSource drops the link mark.
+func (g *GraphModel) ClearLinkSource() {
+	g.linkSource = ""
+}
+
 func (g *GraphModel) TotalCount() int {
 	return len(g.sortedIDs)
 }
@@ -374,6 +451,9 @@ func (g *GraphModel) renderNodeList(width, height int, t Theme) string {
 		isSelected := i == g.selectedIdx
 		statusIcon := getStatusIcon(issue.Status)
 		maxIDLen := width - 4
+		if id == g.linkSource {
+			statusIcon = "🔗"
+		}
 		displayID := smartTruncateID(id, maxIDLen)
 		line := fmt.Sprintf("%s %s", statusIcon, displayID)
 
@@ -386,7 +466,7 @@ func (g *GraphModel) renderNodeList(width, height int, t Theme) string {
 				Width(width)
 		} else {
 			style = t.Renderer.NewStyle().
-				Foreground(getStatusColor(issue.Status, t)).
+				Foreground(g.nodeColor(id, issue, t)).
 				Width(width)
 		}
 		lines = append(lines, style.Render(line))
@@ -446,8 +526,12 @@ func (g *GraphModel) renderVisualGraph(id string, issue *model.Issue, width, hei
 	navStyle := t.Renderer.NewStyle().
 		Foreground(t.Secondary).
 		Italic(true)
+	colorHint := "C: community colors"
+	if g.colorByCommunity {
+		colorHint = "C: status colors"
+	}
 	sections = append(sections, "")
-	sections = append(sections, navStyle.Render("j/k: navigate • enter: view details • g: back to list"))
+	sections = append(sections, navStyle.Render("j/k: navigate • enter: view details • "+colorHint+" • g: back to list"))
 
 	return strings.Join(sections, "\n")
 }
@@ -861,6 +945,15 @@ func (g *GraphModel) renderMetricsPanel(id string, width int, t Theme) string {
 
 	rows = append(rows, "")
 
+	// Section: Community (only for clustered issues)
+	if c := g.community(id); c != nil {
+		rows = append(rows, sectionStyle.Render("Community"))
+		swatch := t.Renderer.NewStyle().Foreground(RepoColors[(c.ID-1)%len(RepoColors)]).Render("●")
+		rows = append(rows, fmt.Sprintf("  %s #%d %s", swatch, c.ID, truncateRunesHelper(c.SuggestedTitle, width-16, "…")))
+		rows = append(rows, fmt.Sprintf("    %d issues · cohesion %.2f", c.Size, c.Cohesion))
+		rows = append(rows, "")
+	}
+
 	// Legend
 	legendStyle := t.Renderer.NewStyle().
 		Foreground(ColorMuted).
@@ -0,0 +1,340 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/writeback"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// dependencyEdit is the open dependency editor in the graph view.
+type dependencyEdit struct {
+	from    string               // dependent issue (the link source)
+	to      string               // issue it depends on (the graph selection)
+	depType model.DependencyType // "" while the type is being chosen
+	remove  bool
+
+	// Set when the edge is refused because it would close a cycle
+	cycle   []string
+	warning string
+
+	preview *analysis.DependencyChangeImpact // nil while being computed
+}
+
+// DependencyPreviewMsg carries the metric preview for a pending dependency edit.
+type DependencyPreviewMsg struct {
+	Dependency model.Dependency
+	Remove     bool
+	Impact     analysis.DependencyChangeImpact
+}
+
+// previewDependencyCmd computes the PageRank/critical-path preview off the UI thread.
+func previewDependencyCmd(issues []model.Issue, dep model.Dependency, remove bool) tea.Cmd {
+	return func() tea.Msg {
+		return DependencyPreviewMsg{
+			Dependency: dep,
+			Remove:     remove,
+			Impact:     analysis.PreviewDependencyChange(issues, dep, remove),
+		}
+	}
+}
+
+// dependencyTypeKeys maps the editor's type keys to dependency types.
+var dependencyTypeKeys = map[string]model.DependencyType{
+	"b": model.DepBlocks,
+	"r": model.DepRelated,
+	"p": model.DepParentChild,
+}
+
+// describeDependency phrases a dependency from the dependent's side.
+func describeDependency(from, to string, depType model.DependencyType) string {
+	switch depType {
+	case model.DepParentChild:
+		return fmt.Sprintf("%s is a child of %s", from, to)
+	case model.DepRelated:
+		return fmt.Sprintf("%s is related to %s", from, to)
+	case model.DepDiscoveredFrom:
+		return fmt.Sprintf("%s was discovered from %s", from, to)
+	default:
+		return fmt.Sprintf("%s is blocked by %s", from, to)
+	}
+}
+
+// handleGraphEditKeys handles dependency editing in the graph view: space
+// marks the dependent issue, + and - add or remove a dependency on the
+// selected issue, z undoes. It reports whether the key was consumed.
+func (m Model) handleGraphEditKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	key := msg.String()
+	switch key {
+	case " ", "+", "-", "z":
+	default:
+		return m, nil, false
+	}
+
+	if reason := m.editUnavailable(); reason != "" {
+		m.statusMsg = reason
+		m.statusIsError = true
+		return m, nil, true
+	}
+
+	if key == "z" {
+		return m.undoLastEdit()
+	}
+
+	if key == " " {
+		if from := m.graphView.MarkLinkSource(); from != "" {
+			m.statusMsg = fmt.Sprintf("🔗 Linking from %s: select another issue, + add or - remove a dependency (esc clears)", from)
+		} else {
+			m.statusMsg = "Link cleared"
+		}
+		m.statusIsError = false
+		return m, nil, true
+	}
+
+	from := m.graphView.LinkSource()
+	selected := m.graphView.SelectedIssue()
+	switch {
+	case from == "":
+		m.statusMsg = "Press space on the dependent issue first"
+		m.statusIsError = true
+		return m, nil, true
+	case selected == nil || selected.ID == from:
+		m.statusMsg = fmt.Sprintf("Select the issue %s should depend on", from)
+		m.statusIsError = true
+		return m, nil, true
+	}
+
+	if key == "+" {
+		m.depEdit = &dependencyEdit{from: from, to: selected.ID}
+		return m, nil, true
+	}
+
+	source := m.issueMap[from]
+	if source == nil {
+		return m, nil, true
+	}
+	for _, dep := range source.Dependencies {
+		if dep == nil || dep.DependsOnID != selected.ID {
+			continue
+		}
+		depType := dep.Type
+		if depType.IsBlocking() {
+			depType = model.DepBlocks
+		}
+		m.depEdit = &dependencyEdit{from: from, to: selected.ID, depType: depType, remove: true}
+		cmd := m.startDependencyPreview()
+		return m, cmd, true
+	}
+	m.statusMsg = fmt.Sprintf("%s has no dependency on %s", from, selected.ID)
+	m.statusIsError = true
+	return m, nil, true
+}
+
+// handleDependencyEditKeys routes input to the open dependency editor.
+func (m Model) handleDependencyEditKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	d := m.depEdit
+	key := msg.String()
+	switch key {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q":
+		m.depEdit = nil
+		m.statusMsg = "Dependency edit cancelled"
+		return m, nil
+	}
+
+	switch {
+	case d.depType == "":
+		depType, ok := dependencyTypeKeys[key]
+		if !ok {
+			return m, nil
+		}
+		d.depType = depType
+		ok, cycle, warning := analysis.CheckTypedDependencyAddition(m.issues, d.from, d.to, depType)
+		if !ok {
+			d.cycle, d.warning = cycle, warning
+			m.statusMsg = "🚫 " + warning
+			m.statusIsError = true
+			return m, nil
+		}
+		cmd := m.startDependencyPreview()
+		return m, cmd
+
+	case d.cycle != nil:
+		return m, nil // Refused; only esc closes
+
+	case key == "enter" || key == "y":
+		var e writeback.Edit
+		if d.remove {
+			e = writeback.RemoveDependency(d.from, d.to, d.depType)
+		} else {
+			e = writeback.AddDependency(d.from, d.to, d.depType, writeback.DefaultAuthor())
+		}
+		m.depEdit = nil
+		m.graphView.ClearLinkSource()
+		cmd := m.startEdit(e)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// startDependencyPreview starts computing the metric preview for the open
+// editor. Non-blocking links are ignored by the analysis graph, so their
+// preview is known without running it.
+func (m *Model) startDependencyPreview() tea.Cmd {
+	d := m.depEdit
+	dep := model.Dependency{IssueID: d.from, DependsOnID: d.to, Type: d.depType}
+	if !d.depType.IsBlocking() {
+		d.preview = &analysis.DependencyChangeImpact{}
+		return nil
+	}
+	return previewDependencyCmd(m.issues, dep, d.remove)
+}
+
+// handleDependencyPreview stores a finished preview if its editor is still open.
+func (m Model) handleDependencyPreview(msg DependencyPreviewMsg) (Model, tea.Cmd) {
+	d := m.depEdit
+	if d == nil || d.from != msg.Dependency.IssueID || d.to != msg.Dependency.DependsOnID ||
+		d.depType != msg.Dependency.Type || d.remove != msg.Remove {
+		return m, nil
+	}
+	impact := msg.Impact
+	d.preview = &impact
+	return m, nil
+}
+
+// clearGraphLink drops the link mark. It reports whether there was one.
+func (m *Model) clearGraphLink() bool {
+	if m.graphView.LinkSource() == "" {
+		return false
+	}
+	m.graphView.ClearLinkSource()
+	m.statusMsg = "Link cleared"
+	m.statusIsError = false
+	return true
+}
+
+// renderDependencyEdit renders the dependency editor modal.
+func (m Model) renderDependencyEdit() string {
+	t := m.theme
+	d := m.depEdit
+
+	boxStyle := t.Renderer.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Primary).
+		Padding(1, 3)
+	titleStyle := t.Renderer.NewStyle().
+		Foreground(t.Primary).
+		Bold(true)
+	subtitleStyle := t.Renderer.NewStyle().
+		Foreground(t.Subtext).
+		Italic(true)
+	keyStyle := t.Renderer.NewStyle().
+		Foreground(t.Primary).
+		Bold(true)
+	textStyle := t.Renderer.NewStyle().
+		Foreground(t.Base.GetForeground())
+	errorStyle := t.Renderer.NewStyle().
+		Foreground(t.Blocked).
+		Bold(true)
+
+	title := "Add Dependency"
+	if d.remove {
+		title = "Remove Dependency"
+	}
+	var subtitle []string
+	for _, id := range []string{d.from, d.to} {
+		if issue := m.issueMap[id]; issue != nil {
+			subtitle = append(subtitle, fmt.Sprintf("%s  %s", id, truncateRunesHelper(issue.Title, 48, "…")))
+		}
+	}
+
+	var body, action string
+	switch {
+	case d.depType == "":
+		var opts []string
+		for _, opt := range []struct{ key, label string }{{"b", "blocks"}, {"r", "related"}, {"p", "parent-child"}} {
+			opts = append(opts, keyStyle.Render(opt.key)+" "+textStyle.Render(opt.label))
+		}
+		body = textStyle.Render(fmt.Sprintf("How does %s depend on %s?", d.from, d.to)) + "\n\n" + strings.Join(opts, "   ")
+		action = textStyle.Render("Press ") + keyStyle.Render("b/r/p") + textStyle.Render(" to choose, ")
+
+	case d.cycle != nil:
+		path := append([]string{d.from}, d.cycle...)
+		body = errorStyle.Render("🚫 Refused: this would create a cycle") + "\n\n" +
+			textStyle.Render(strings.Join(path, " → ")) + "\n\n" +
+			subtitleStyle.Render(d.warning)
+		action = textStyle.Render("Press ")
+
+	default:
+		body = textStyle.Render(describeDependency(d.from, d.to, d.depType)) + "\n\n" + m.renderDependencyPreview(d)
+		verb := " to add, "
+		if d.remove {
+			verb = " to remove, "
+		}
+		action = textStyle.Render("Press ") + keyStyle.Render("Enter") + textStyle.Render(verb)
+	}
+
+	content := titleStyle.Render("⛓  "+title) + "\n" +
+		subtitleStyle.Render(strings.Join(subtitle, "\n")) + "\n\n" +
+		body + "\n\n" +
+		action + keyStyle.Render("Esc") + textStyle.Render(" to cancel")
+
+	return lipgloss.Place(
+		m.width,
+		m.height-1,
+		lipgloss.Center,
+		lipgloss.Center,
+		boxStyle.Render(content),
+	)
+}
+
+// renderDependencyPreview renders the before → after metrics table.
+func (m Model) renderDependencyPreview(d *dependencyEdit) string {
+	t := m.theme
+	mutedStyle := t.Renderer.NewStyle().
+		Foreground(t.Subtext).
+		Italic(true)
+	headerStyle := t.Renderer.NewStyle().
+		Foreground(t.Secondary).
+		Bold(true)
+
+	if d.preview == nil {
+		return mutedStyle.Render("Computing PageRank and critical path…")
+	}
+	if !d.depType.IsBlocking() {
+		return mutedStyle.Render(fmt.Sprintf("%s links do not change PageRank or critical path", d.depType))
+	}
+
+	shift := func(before, after float64, format string) string {
+		s := fmt.Sprintf(format+" → "+format, before, after)
+		switch {
+		case after > before:
+			return s + " ▲"
+		case after < before:
+			return s + " ▼"
+		}
+		return s
+	}
+
+	idWidth := lipgloss.Width("Longest chain")
+	for _, n := range d.preview.Nodes {
+		if w := lipgloss.Width(n.ID); w > idWidth {
+			idWidth = w
+		}
+	}
+	lines := []string{headerStyle.Render(fmt.Sprintf("%-*s  %-22s  %s", idWidth, "", "PageRank", "Critical path"))}
+	for _, n := range d.preview.Nodes {
+		lines = append(lines, fmt.Sprintf("%-*s  %-22s  %s", idWidth, n.ID,
+			shift(n.PageRankBefore, n.PageRankAfter, "%.4f"),
+			shift(n.CriticalPathBefore, n.CriticalPathAfter, "%.0f")))
+	}
+	lines = append(lines, fmt.Sprintf("%-*s  %-22s  %s", idWidth, "Longest chain", "",
+		shift(d.preview.LongestPathBefore, d.preview.LongestPathAfter, "%.0f")))
+	return strings.Join(lines, "\n")
+}
@@ -2,6 +2,7 @@ package ui_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
@@ -1081,3 +1082,32 @@ func TestGraphModelSelectByIDWithDependencies(t *testing.T) {
 		t.Errorf("Expected 'root' selected, got %v", sel)
 	}
 }
+
+// TestGraphModelToggleCommunityColors verifies status coloring is the default
+// and that toggling switches to community colors and back.
+func TestGraphModelToggleCommunityColors(t *testing.T) {
+	theme := createTheme()
+	issues := []model.Issue{
+		{ID: "A", Title: "A", Status: model.StatusOpen},
+		{ID: "B", Title: "B", Status: model.StatusOpen, Dependencies: []*model.Dependency{
+			{DependsOnID: "A", Type: model.DepBlocks},
+		}},
+		{ID: "C", Title: "C", Status: model.StatusOpen, Dependencies: []*model.Dependency{
+			{DependsOnID: "A", Type: model.DepBlocks},
+		}},
+	}
+	g := ui.NewGraphModel(issues, nil, theme)
+
+	if out := g.View(100, 40); !strings.Contains(out, "C: community colors") {
+		t.Errorf("status coloring should be the default, got footer:\n%s", out)
+	}
+	if !g.ToggleCommunityColors() {
+		t.Fatal("first toggle should turn community coloring on")
+	}
+	if out := g.View(100, 40); !strings.Contains(out, "C: status colors") {
+		t.Errorf("footer should offer switching back to status colors:\n%s", out)
+	}
+	if g.ToggleCommunityColors() {
+		t.Error("second toggle should restore status coloring")
+	}
+}
@@ -24,6 +24,7 @@ const (
 	PanelArticulation
 	PanelSlack
 	PanelCycles
+	PanelMustPass
 	PanelPriority // Agent-first priority recommendations
 	PanelCount    // Sentinel for wrapping
 )
@@ -121,6 +122,15 @@ var metricDescriptions = map[MetricPanel]MetricInfo{
 		HowToUse:    "**Break cycles** by removing or reversing a dependency. Refactor to decouple.",
 		FormulaHint: "Detected via Tarjan's SCC algorithm",
 	},
+	PanelMustPass: {
+		Icon:        "🚪",
+		Title:       "Must-Pass",
+		ShortDesc:   "Dominator Tree",
+		WhatIs:      "Beads that **every route** to an epic or final deliverable must pass through.",
+		WhyUseful:   "*Directed single points of failure.* No amount of parallel work gets around them.",
+		HowToUse:    "**Staff these first** and watch them closely; a slip here delays every goal they dominate.",
+		FormulaHint: "`dom(G)` = idom chain from a virtual root of actionable work (Cooper-Harvey-Kennedy)",
+	},
 	PanelPriority: {
 		Icon:        "🎯",
 		Title:       "Priority",
@@ -140,6 +150,7 @@ type InsightsModel struct {
 	extraText      string
 	labelAttention []analysis.LabelAttentionScore
 	labelFlow      *analysis.CrossLabelFlow
+	mustPassGoals  map[string][]string // Must-pass ID -> goals it dominates
 
 	// Priority triage data (bv-91)
 	topPicks []analysis.TopPick
@@ -192,7 +203,7 @@ func NewInsightsModel(ins analysis.Insights, issueMap map[string]*model.Issue, t
 		BorderForeground(theme.Primary).
 		Padding(0, 1)
 
-	return InsightsModel{
+	m := InsightsModel{
 		insights:         ins,
 		issueMap:         issueMap,
 		theme:            theme,
@@ -202,6 +213,8 @@ func NewInsightsModel(ins analysis.Insights, issueMap map[string]*model.Issue, t
 		mdRenderer:       mdRenderer,
 		detailVP:         vp,
 	}
+	m.fillMustPass()
+	return m
 }
 
 func (m *InsightsModel) SetSize(w, h int) {
@@ -222,6 +235,25 @@ func (m *InsightsModel) SetSize(w, h int) {
 
 func (m *InsightsModel) SetInsights(ins analysis.Insights) {
 	m.insights = ins
+	m.fillMustPass()
+}
+
+// fillMustPass indexes the goals behind the must-pass ranking. The dominator
+// analysis arrives with the insights (computed off the UI thread with the
+// Phase 2 metrics); until then the panel stays empty.
+func (m *InsightsModel) fillMustPass() {
+	m.mustPassGoals = nil
+	dom := m.insights.Dominators
+	if dom == nil {
+		return
+	}
+	if m.insights.MustPass == nil {
+		m.insights.MustPass = dom.MustPassItems(0)
+	}
+	m.mustPassGoals = make(map[string][]string, len(dom.MustPass))
+	for _, mp := range dom.MustPass {
+		m.mustPassGoals[mp.ID] = mp.Goals
+	}
 }
 
 // SetTopPicks sets the priority triage recommendations (bv-91)
@@ -563,6 +595,8 @@ func (m *InsightsModel) currentPanelItemCount() int {
 		return len(m.insights.Cores)
 	case PanelArticulation:
 		return len(m.insights.Articulation)
+	case PanelMustPass:
+		return len(m.insights.MustPass)
 	case PanelSlack:
 		return len(m.insights.Slack)
 	case PanelCycles:
@@ -595,6 +629,8 @@ func (m *InsightsModel) getPanelItems(panel MetricPanel) []analysis.InsightItem
 			items = append(items, analysis.InsightItem{ID: id, Value: 0})
 		}
 		return items
+	case PanelMustPass:
+		return m.insights.MustPass
 	case PanelSlack:
 		return m.insights.Slack
 	default:
@@ -678,6 +714,12 @@ func (m *InsightsModel) View() string {
 		colWidth = 25
 	}
 
+	// The third row fits a fourth panel
+	quarterWidth := (mainWidth - 8) / 4
+	if quarterWidth < 20 {
+		quarterWidth = 20
+	}
+
 	// With 4 rows, reduce individual row height
 	rowHeight := (m.height - 8) / 4
 	if rowHeight < 6 {
@@ -691,14 +733,15 @@ func (m *InsightsModel) View() string {
 		m.renderMetricPanel(PanelHubs, colWidth, rowHeight, t),
 		m.renderMetricPanel(PanelAuthorities, colWidth, rowHeight, t),
 		m.renderMetricPanel(PanelCores, colWidth, rowHeight, t),
-		m.renderMetricPanel(PanelArticulation, colWidth, rowHeight, t),
-		m.renderMetricPanel(PanelSlack, colWidth, rowHeight, t),
-		m.renderCyclesPanel(colWidth, rowHeight, t),
+		m.renderMetricPanel(PanelArticulation, quarterWidth, rowHeight, t),
+		m.renderMetricPanel(PanelSlack, quarterWidth, rowHeight, t),
+		m.renderCyclesPanel(quarterWidth, rowHeight, t),
+		m.renderMetricPanel(PanelMustPass, quarterWidth, rowHeight, t),
 	}
 
 	row1 := lipgloss.JoinHorizontal(lipgloss.Top, panels[0], panels[1], panels[2])
 	row2 := lipgloss.JoinHorizontal(lipgloss.Top, panels[3], panels[4], panels[5])
-	row3 := lipgloss.JoinHorizontal(lipgloss.Top, panels[6], panels[7], panels[8])
+	row3 := lipgloss.JoinHorizontal(lipgloss.Top, panels[6], panels[7], panels[8], panels[9])
 	// Priority panel spans full width for prominence (bv-91)
 	// Toggle between priority list and heatmap view (bv-95)
 	var row4 string
@@ -1900,6 +1943,22 @@ func (m *InsightsModel) renderCalculationProofMD(selectedID string) string {
 			sb.WriteString(fmt.Sprintf("\n> Sum of %d hub scores: `%.4f`\n\n", len(dependents), sumHub))
 		}
 
+	case PanelMustPass:
+		goals := m.mustPassGoals[selectedID]
+		sb.WriteString(fmt.Sprintf("**Goals dominated:** `%d`\n\n", len(goals)))
+		if len(goals) > 0 {
+			sb.WriteString("**Every route to these goes through this bead:**\n")
+			for i, id := range goals {
+				if i >= 5 {
+					sb.WriteString(fmt.Sprintf("- _...+%d more_\n", len(goals)-5))
+					break
+				}
+				sb.WriteString(fmt.Sprintf("- ⇢ %s\n", m.getBeadTitle(id, 40)))
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("> Unlike a cut point, no schedule works around this bead. Run `bv --robot-dominators <goal>` for the full chain.\n\n")
+
 	case PanelCycles:
 		idx := m.selectedIndex[PanelCycles]
 		if idx >= 0 && idx < len(m.insights.Cycles) {
@@ -117,6 +117,46 @@ func TestInsightsModelEmpty(t *testing.T) {
 	_ = m.View()
 }
 
+// TestInsightsModelMustPass verifies the must-pass panel is filled from the
+// dominator analysis that comes with the insights
+func TestInsightsModelMustPass(t *testing.T) {
+	blocks := func(id, blocker string) []*model.Dependency {
+		return []*model.Dependency{{IssueID: id, DependsOnID: blocker, Type: model.DepBlocks}}
+	}
+	issueMap := map[string]*model.Issue{
+		"root":  {ID: "root", Title: "Foundation", Status: model.StatusOpen},
+		"mid":   {ID: "mid", Title: "Middle", Status: model.StatusOpen, Dependencies: blocks("mid", "root")},
+		"goal1": {ID: "goal1", Title: "Goal 1", Status: model.StatusOpen, Dependencies: blocks("goal1", "mid")},
+		"goal2": {ID: "goal2", Title: "Goal 2", Status: model.StatusOpen, Dependencies: blocks("goal2", "mid")},
+	}
+	var issues []model.Issue
+	for _, iss := range issueMap {
+		issues = append(issues, *iss)
+	}
+
+	pending := ui.NewInsightsModel(analysis.Insights{}, issueMap, createTheme())
+	for i := 0; i < 9; i++ {
+		pending.NextPanel()
+	}
+	if id := pending.SelectedIssueID(); id != "" {
+		t.Errorf("Expected must-pass to wait for the dominator analysis, got %q", id)
+	}
+
+	m := ui.NewInsightsModel(analysis.Insights{Dominators: analysis.ComputeDominators(issues)}, issueMap, createTheme())
+	m.SetSize(140, 50)
+	for i := 0; i < 9; i++ {
+		m.NextPanel()
+	}
+	if id := m.SelectedIssueID(); id != "mid" {
+		t.Errorf("Expected mid to rank first in must-pass, got %q", id)
+	}
+	m.MoveDown()
+	if id := m.SelectedIssueID(); id != "root" {
+		t.Errorf("Expected root second in must-pass, got %q", id)
+	}
+	_ = m.View()
+}
+
 // TestInsightsModelPanelNavigation verifies panel navigation
 func TestInsightsModelPanelNavigation(t *testing.T) {
 	theme := createTheme()
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/recipe"
 )
@@ -127,6 +128,30 @@ func TestApplyRecipe_Sorting(t *testing.T) {
 	}
 }
 
+func TestApplyRecipe_QueryDoesNotWaitForPhase2(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "A", Status: model.StatusOpen},
+		{ID: "B", Status: model.StatusOpen, Dependencies: []*model.Dependency{{IssueID: "B", DependsOnID: "A", Type: model.DepBlocks}}},
+	}
+	m := NewModel(issues, nil, "")
+	m.analysis = analysis.NewPendingGraphStatsForTest(map[string]int{"B": 1}, map[string]int{"A": 1})
+
+	done := make(chan struct{})
+	go func() {
+		m.applyRecipe(&recipe.Recipe{Name: "hubs", Query: "indegree>=1 OR pagerank>0"})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("applyRecipe blocked on phase 2 metrics")
+	}
+	filtered := m.FilteredIssues()
+	if len(filtered) != 1 || filtered[0].ID != "A" {
+		t.Errorf("expected only the phase 1 match A, got %+v", filtered)
+	}
+}
+
 func TestTimeTravel_DiffBadgePropagation(t *testing.T) {
 	issues := []model.Issue{
 		{ID: "A", Status: model.StatusOpen},
@@ -19,6 +19,7 @@ import (
 	"github.com/Dicklesworthstone/beads_viewer/pkg/debug"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/drift"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/export"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/flow"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/instance"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
@@ -27,6 +28,7 @@ import (
 	"github.com/Dicklesworthstone/beads_viewer/pkg/triage"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/updater"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/watcher"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/writeback"
 
 	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/list"
@@ -70,6 +72,8 @@ const (
 	focusCassModal      // Cass session preview modal (bv-5bqh)
 	focusUpdateModal    // Self-update modal (bv-182)
 	focusTriageDiff     // Triage diff review modal
+	focusGantt          // Resource-constrained schedule (Gantt) view
+	focusFlowMetrics    // Kanban flow metrics (CFD, WIP aging)
 )
 
 // SortMode represents the current list sorting mode (bv-3ita)
@@ -120,14 +124,18 @@ type Phase2ReadyMsg struct {
 	Insights analysis.Insights    // Precomputed insights for Phase 2 metrics
 }
 
-// WaitForPhase2Cmd returns a command that waits for Phase 2 and sends Phase2ReadyMsg
-func WaitForPhase2Cmd(stats *analysis.GraphStats) tea.Cmd {
+// WaitForPhase2Cmd returns a command that waits for Phase 2 and sends Phase2ReadyMsg.
+// The must-pass dominator analysis of issues is computed alongside the insights so
+// it stays off the UI thread.
+func WaitForPhase2Cmd(stats *analysis.GraphStats, issues []model.Issue) tea.Cmd {
 	return func() tea.Msg {
 		if stats == nil {
 			return Phase2ReadyMsg{}
 		}
 		stats.WaitForPhase2()
 		ins := stats.GenerateInsights(stats.NodeCount)
+		ins.Dominators = analysis.ComputeDominators(issues)
+		ins.MustPass = ins.Dominators.MustPassItems(0)
 		return Phase2ReadyMsg{Stats: stats, Insights: ins}
 	}
 }
@@ -359,7 +367,10 @@ type Model struct {
 	graphView          GraphModel
 	tree               TreeModel // Hierarchical tree view (bv-gllx)
 	insightsPanel      InsightsModel
-	flowMatrix         FlowMatrixModel // Cross-label flow matrix
+	flowMatrix         FlowMatrixModel  // Cross-label flow matrix
+	gantt              GanttModel       // Per-worker schedule
+	flowMetrics        FlowMetricsModel // Cumulative flow and WIP aging
+	rosterPath         string           // Roster for the schedule view (default .bv/roster.yaml)
 	theme              Theme
 
 	// Update State
@@ -413,10 +424,14 @@ type Model struct {
 	semanticSearchEnabled  bool
 	semanticIndexBuilding  bool
 	semanticSearch         *SemanticSearch
+	queryFilter            *QueryFilter
 	semanticHybridEnabled  bool
 	semanticHybridPreset   search.PresetName
 	semanticHybridBuilding bool
 	semanticHybridReady    bool
+	semanticKinds          []search.DocKind
+	semanticDocsBuilding   bool
+	semanticDocsStale      bool
 	lastSearchTerm         string
 
 	// Stats (cached)
@@ -462,6 +477,21 @@ type Model struct {
 	timeTravelInput      textinput.Model
 	showTimeTravelPrompt bool
 
+	// Write-back editing (status, priority, assignee, labels, comments)
+	editStore   writeback.Store
+	editHistory writeback.History // session undo stack
+	editPending bool              // an edit is being persisted
+	editPrompt  editPromptKind
+	editIssueID string
+	editInput   textinput.Model
+
+	// Board moves (grab with space or drag with the mouse)
+	transitionRules *writeback.TransitionRules // nil uses writeback.DefaultRules
+	boardMouseDrag  bool                       // the grab was started by a mouse press
+
+	// Graph view dependency editor (nil when closed)
+	depEdit *dependencyEdit
+
 	// Status message (for temporary feedback)
 	statusMsg     string
 	statusIsError bool
@@ -607,6 +637,22 @@ type WorkspaceInfo struct {
 	DoltMode     bool // true when loaded via --dolt (shows "Rig" instead of "Repo")
 }
 
+// updateFilterItems refreshes the search backends after the list items change.
+func (m *Model) updateFilterItems(items []list.Item) {
+	m.updateSemanticIDs(items)
+	if m.queryFilter != nil {
+		m.queryFilter.SetItems(items, m.issues, m.analysis)
+	}
+}
+
+// wrapFilter layers structured query support over a fuzzy or semantic filter.
+func (m *Model) wrapFilter(base list.FilterFunc) list.FilterFunc {
+	if m.queryFilter == nil {
+		return base
+	}
+	return m.queryFilter.Wrap(base)
+}
+
 func (m *Model) updateSemanticIDs(items []list.Item) {
 	if m.semanticSearch == nil {
 		return
@@ -666,11 +712,13 @@ func (m *Model) applySemanticScores(term string) {
 			issueItem.SearchTextScore = score.TextScore
 			issueItem.SearchComponents = score.Components
 			issueItem.SearchScoreSet = true
+			issueItem.SearchMatches = score.Matches
 		} else {
 			issueItem.SearchScore = 0
 			issueItem.SearchTextScore = 0
 			issueItem.SearchComponents = nil
 			issueItem.SearchScoreSet = false
+			issueItem.SearchMatches = nil
 		}
 		items[i] = issueItem
 	}
@@ -684,11 +732,12 @@ func (m *Model) clearSemanticScores() {
 		if !ok {
 			continue
 		}
-		if issueItem.SearchScoreSet || issueItem.SearchComponents != nil {
+		if issueItem.SearchScoreSet || issueItem.SearchComponents != nil || issueItem.SearchMatches != nil {
 			issueItem.SearchScore = 0
 			issueItem.SearchTextScore = 0
 			issueItem.SearchComponents = nil
 			issueItem.SearchScoreSet = false
+			issueItem.SearchMatches = nil
 			items[i] = issueItem
 			changed = true
 		}
@@ -984,6 +1033,11 @@ func NewModel(issues []model.Issue, activeRecipe *recipe.Recipe, beadsPath strin
 	}
 	semanticSearch.SetIDs(semanticIDs)
 
+	// Structured queries in the '/' prompt (status:open priority<=1 ...)
+	queryFilter := NewQueryFilter()
+	queryFilter.SetItems(items, issues, graphStats)
+	l.Filter = queryFilter.Wrap(list.DefaultFilter)
+
 	// Build initial status message if watcher failed
 	var initialStatus string
 	var initialStatusErr bool
@@ -1039,10 +1093,12 @@ func NewModel(issues []model.Issue, activeRecipe *recipe.Recipe, beadsPath strin
 		theme:                  theme,
 		currentFilter:          "all",
 		semanticSearch:         semanticSearch,
+		queryFilter:            queryFilter,
 		semanticHybridEnabled:  false,
 		semanticHybridPreset:   search.PresetDefault,
 		semanticHybridBuilding: false,
 		semanticHybridReady:    false,
+		semanticKinds:          []search.DocKind{search.KindIssue},
 		lastSearchTerm:         "",
 		focused:                focusList,
 		splitPaneRatio:         0.4, // Default: list pane gets 40% of width
@@ -1098,7 +1154,7 @@ func (m Model) Init() tea.Cmd {
 	// This eliminates the "Initializing..." phase entirely.
 	cmds := []tea.Cmd{
 		CheckUpdateCmd(),
-		WaitForPhase2Cmd(m.analysis),
+		WaitForPhase2Cmd(m.analysis, m.issuesForAsync()),
 	}
 	if m.backgroundWorker != nil {
 		cmds = append(cmds, StartBackgroundWorkerCmd(m.backgroundWorker))
@@ -1178,7 +1234,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.Error != nil {
 			// If indexing fails, revert to fuzzy mode for predictable behavior.
 			m.semanticSearchEnabled = false
-			m.list.Filter = list.DefaultFilter
+			m.list.Filter = m.wrapFilter(list.DefaultFilter)
 			m.statusMsg = fmt.Sprintf("Semantic search unavailable: %v", msg.Error)
 			m.statusIsError = true
 			break
@@ -1273,6 +1329,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+	case EditAppliedMsg:
+		return m.handleEditApplied(msg)
+
+	case DependencyPreviewMsg:
+		return m.handleDependencyPreview(msg)
+
 	case Phase2ReadyMsg:
 		// Ignore stale Phase2 completions (from before a file reload)
 		if msg.Stats != m.analysis {
@@ -1289,8 +1351,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.snapshot != nil {
 			m.snapshot.Insights = ins
 		}
-		m.insightsPanel.SetInsights(ins)
 		m.insightsPanel.issueMap = m.issueMap
+		m.insightsPanel.SetInsights(ins)
 		bodyHeight := m.height - 1
 		if bodyHeight < 5 {
 			bodyHeight = 5
@@ -1440,6 +1502,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case SemanticDocsReadyMsg:
+		m.semanticDocsBuilding = false
+		if msg.Error != nil {
+			m.statusMsg = fmt.Sprintf("Unified search index failed: %v", msg.Error)
+			m.statusIsError = true
+			break
+		}
+		if m.semanticSearch != nil {
+			m.semanticSearch.SetKindIndexes(msg.Indexes, msg.Docs)
+			m.semanticSearch.ResetCache()
+		}
+		if m.semanticDocsStale {
+			return m, m.semanticDocsCmd()
+		}
+		if m.semanticSearchEnabled && m.list.FilterState() != list.Unfiltered {
+			currentTerm := m.list.FilterInput.Value()
+			if currentTerm != "" {
+				return m, ComputeSemanticFilterCmd(m.semanticSearch, currentTerm)
+			}
+		}
+
 	case HistoryLoadedMsg:
 		// Background history loading completed
 		m.historyLoading = false
@@ -1450,6 +1533,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		} else if msg.Report != nil {
 			m.historyView = NewHistoryModel(msg.Report, m.theme)
 			m.historyView.SetSize(m.width, m.height-1)
+			if hasDocKind(m.semanticKinds, search.KindCommit) {
+				if cmd := m.semanticDocsCmd(); cmd != nil {
+					return m, cmd
+				}
+			}
+			if m.focused == focusFlowMetrics {
+				m.refreshFlowMetrics()
+			}
 			// Refresh detail pane if visible
 			if m.isSplitView || m.showDetails {
 				m.updateViewportContent()
@@ -1571,8 +1662,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		// Regenerate sub-views (Phase 1 data; Phase 2 will update via Phase2ReadyMsg)
-		m.insightsPanel.SetInsights(m.snapshot.Insights)
 		m.insightsPanel.issueMap = m.issueMap
+		m.insightsPanel.SetInsights(m.snapshot.Insights)
 		bodyHeight := m.height - 1
 		if bodyHeight < 5 {
 			bodyHeight = 5
@@ -1602,7 +1693,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 
 				m.list.SetItems(filteredItems)
-				m.updateSemanticIDs(filteredItems)
+				m.updateFilterItems(filteredItems)
 				m.board.SetIssues(filteredIssues)
 
 				recipeIns := analysis.Insights{}
@@ -1681,7 +1772,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			m.sortFilteredItems(filteredItems, filteredIssues)
 			m.list.SetItems(filteredItems)
-			m.updateSemanticIDs(filteredItems)
+			m.updateFilterItems(filteredItems)
 			if m.snapshot != nil && m.snapshot.BoardState != nil && (!m.workspaceMode || m.activeRepos == nil) && len(filteredIssues) == len(m.snapshot.Issues) {
 				m.board.SetSnapshot(m.snapshot)
 			} else {
@@ -1742,6 +1833,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.semanticIndexBuilding = true
 			cmds = append(cmds, BuildSemanticIndexCmd(m.issuesForAsync()))
 		}
+		if cmd := m.semanticDocsCmd(); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
 
 		// Reload sprints (bv-161)
 		if m.beadsPath != "" {
@@ -1783,7 +1877,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Wait for Phase 2 if not ready
 		if msg.Snapshot.Analysis != nil {
-			cmds = append(cmds, WaitForPhase2Cmd(msg.Snapshot.Analysis))
+			cmds = append(cmds, WaitForPhase2Cmd(msg.Snapshot.Analysis, m.issuesForAsync()))
 		}
 
 		if m.backgroundWorker != nil {
@@ -1954,32 +2048,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if profileRefresh {
 			statsStart = time.Now()
 		}
-		m.countOpen, m.countReady, m.countBlocked, m.countClosed = 0, 0, 0, 0
-		for i := range m.issues {
-			issue := &m.issues[i]
-			if isClosedLikeStatus(issue.Status) {
-				m.countClosed++
-				continue
-			}
-			m.countOpen++
-			if issue.Status == model.StatusBlocked {
-				m.countBlocked++
-				continue
-			}
-			isBlocked := false
-			for _, dep := range issue.Dependencies {
-				if dep == nil || !dep.Type.IsBlocking() {
-					continue
-				}
-				if blocker, exists := m.issueMap[dep.DependsOnID]; exists && !isClosedLikeStatus(blocker.Status) {
-					isBlocked = true
-					break
-				}
-			}
-			if !isBlocked {
-				m.countReady++
-			}
-		}
+		m.recomputeCounts()
 		if profileRefresh {
 			recordTiming("counts", time.Since(statsStart))
 		}
@@ -2022,7 +2091,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if profileRefresh {
 			recordTiming("list_items", time.Since(listStart))
 		}
-		m.updateSemanticIDs(items)
+		m.updateFilterItems(items)
 		m.clearSemanticScores()
 		if m.semanticSearch != nil {
 			m.semanticSearch.ResetCache()
@@ -2144,6 +2213,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.semanticIndexBuilding = true
 			cmds = append(cmds, BuildSemanticIndexCmd(m.issuesForAsync()))
 		}
+		if cmd := m.semanticDocsCmd(); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
 
 		if cacheHit {
 			m.statusMsg = fmt.Sprintf("Reloaded %d issues (cached)", len(newIssues))
@@ -2224,7 +2296,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.watcher != nil && !autoEnabled {
 			cmds = append(cmds, WatchFileCmd(m.watcher))
 		}
-		cmds = append(cmds, WaitForPhase2Cmd(m.analysis))
+		cmds = append(cmds, WaitForPhase2Cmd(m.analysis, m.issuesForAsync()))
 		return m, tea.Batch(cmds...)
 
 	case tea.KeyMsg:
@@ -2232,6 +2304,25 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.statusMsg = ""
 		m.statusIsError = false
 
+		// Edit prompt captures all input while open
+		if m.editPrompt != editPromptNone {
+			return m.handleEditPromptKeys(msg)
+		}
+
+		// A grabbed board card captures input until dropped or cancelled
+		if m.focused == focusBoard && m.board.IsGrabbing() {
+			return m.handleBoardGrabKeys(msg)
+		}
+
+		// The dependency editor captures input until confirmed or cancelled;
+		// esc drops a pending link before it leaves the graph view
+		if m.depEdit != nil {
+			return m.handleDependencyEditKeys(msg)
+		}
+		if m.focused == focusGraph && msg.String() == "esc" && m.clearGraphLink() {
+			return m, nil
+		}
+
 		// Handle AGENTS.md prompt modal (bv-i8dk)
 		if m.showAgentPrompt {
 			m.agentPromptModal, cmd = m.agentPromptModal.Update(msg)
@@ -2664,6 +2755,33 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				m.updateListDelegate()
 				return m, tea.Batch(cmds...)
+			case "alt+s", "alt+S":
+				m.statusIsError = false
+				if m.semanticSearch == nil {
+					m.statusMsg = "Unified search unavailable"
+					m.statusIsError = true
+					return m, nil
+				}
+				m.semanticKinds = nextSearchKinds(m.semanticKinds)
+				if hasDocKind(m.semanticKinds, search.KindSession) {
+					m.ensureSessionSearcher()
+				}
+				m.semanticSearch.SetKinds(m.semanticKinds)
+				m.semanticSearch.ResetCache()
+				m.clearSemanticScores()
+				m.statusMsg = fmt.Sprintf("Search covers: %s", search.FormatDocKinds(m.semanticKinds))
+				if !m.semanticSearchEnabled {
+					m.statusMsg += " (ctrl+s for semantic search)"
+				}
+				if cmd := m.semanticDocsCmd(); cmd != nil {
+					cmds = append(cmds, cmd)
+				} else if m.semanticSearchEnabled && m.list.FilterState() != list.Unfiltered {
+					currentTerm := m.list.FilterInput.Value()
+					if currentTerm != "" {
+						cmds = append(cmds, ComputeSemanticFilterCmd(m.semanticSearch, currentTerm))
+					}
+				}
+				return m, tea.Batch(cmds...)
 			}
 		}
 
@@ -2673,7 +2791,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.semanticSearchEnabled = !m.semanticSearchEnabled
 			if m.semanticSearchEnabled {
 				if m.semanticSearch != nil {
-					m.list.Filter = m.semanticSearch.Filter
+					m.list.Filter = m.wrapFilter(m.semanticSearch.Filter)
 					if !m.semanticSearch.Snapshot().Ready && !m.semanticIndexBuilding {
 						m.semanticIndexBuilding = true
 						m.statusMsg = "Semantic search: building index…"
@@ -2685,7 +2803,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				} else {
 					m.semanticSearchEnabled = false
-					m.list.Filter = list.DefaultFilter
+					m.list.Filter = m.wrapFilter(list.DefaultFilter)
 					m.statusMsg = "Semantic search unavailable"
 					m.statusIsError = true
 				}
@@ -2693,8 +2811,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.semanticHybridBuilding = true
 					cmds = append(cmds, BuildHybridMetricsCmd(m.issuesForAsync()))
 				}
+				if cmd := m.semanticDocsCmd(); cmd != nil {
+					cmds = append(cmds, cmd)
+				}
 			} else {
-				m.list.Filter = list.DefaultFilter
+				m.list.Filter = m.wrapFilter(list.DefaultFilter)
 				m.statusMsg = "Fuzzy search enabled"
 				m.clearSemanticScores()
 			}
@@ -2767,6 +2888,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.focused = focusList
 					return m, nil
 				}
+				if m.focused == focusGantt || m.focused == focusFlowMetrics {
+					m.focused = focusList
+					return m, nil
+				}
 				if m.isGraphView {
 					m.isGraphView = false
 					m.focused = focusList
@@ -2798,6 +2923,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.focused = focusList
 					return m, nil
 				}
+				if m.focused == focusGantt || m.focused == focusFlowMetrics {
+					m.focused = focusList
+					return m, nil
+				}
 				if m.isGraphView {
 					m.isGraphView = false
 					m.focused = focusList
@@ -3075,6 +3204,38 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.flowMatrix.SetSize(m.width, panelHeight)
 				return m, nil
 
+			case "@":
+				// Schedule view: open work assigned to roster workers
+				m.clearAttentionOverlay()
+				schedule, source, err := m.buildSchedule()
+				if err != nil {
+					m.statusMsg = err.Error()
+					m.statusIsError = true
+					return m, nil
+				}
+				m.isGraphView = false
+				m.isBoardView = false
+				m.isActionableView = false
+				m.isHistoryView = false
+				m.focused = focusGantt
+				m.gantt = NewGanttModel(m.theme)
+				m.gantt.SetData(schedule, source)
+				m.gantt.SetSize(m.width, max(m.height-2, 3))
+				return m, nil
+
+			case "%":
+				// Flow metrics view: cumulative flow and WIP aging
+				m.clearAttentionOverlay()
+				m.isGraphView = false
+				m.isBoardView = false
+				m.isActionableView = false
+				m.isHistoryView = false
+				m.focused = focusFlowMetrics
+				m.flowMetrics = NewFlowMetricsModel(m.theme)
+				m.refreshFlowMetrics()
+				m.flowMetrics.SetSize(m.width, max(m.height-2, 3))
+				return m, nil
+
 			case "!":
 				// Toggle alerts panel (bv-168)
 				// Only show if there are active alerts
@@ -3146,6 +3307,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			}
 
+			// Write-back edit keys shared by list, board and detail
+			if m.editableFocus() {
+				var handled bool
+				if m, cmd, handled = m.handleEditKeys(msg); handled {
+					return m, cmd
+				}
+			}
+			if m.focused == focusGraph {
+				var handled bool
+				if m, cmd, handled = m.handleGraphEditKeys(msg); handled {
+					return m, cmd
+				}
+			}
+
 			// Focus-specific key handling
 			switch m.focused {
 			case focusRecipePicker:
@@ -3212,6 +3387,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			case focusFlowMatrix:
 				m = m.handleFlowMatrixKeys(msg)
+			case focusGantt:
+				m = m.handleGanttKeys(msg)
+			case focusFlowMetrics:
+				m = m.handleFlowMetricsKeys(msg)
 
 			case focusList:
 				m = m.handleListKeys(msg)
@@ -3223,6 +3402,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tea.MouseMsg:
+		// Click selects a board card; dragging it moves it to another column
+		if m.focused == focusBoard && m.isBoardView {
+			var handled bool
+			if m, cmd, handled = m.handleBoardMouse(msg); handled {
+				return m, cmd
+			}
+		}
+
 		// Handle mouse wheel scrolling
 		switch msg.Button {
 		case tea.MouseButtonWheelUp:
@@ -3252,6 +3439,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.historyView.MoveUp()
 			case focusFlowMatrix:
 				m.flowMatrix.MoveUp()
+			case focusGantt:
+				m.gantt.MoveUp()
+			case focusFlowMetrics:
+				m.flowMetrics.MoveUp()
 			}
 			return m, nil
 		case tea.MouseButtonWheelDown:
@@ -3281,6 +3472,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.historyView.MoveDown()
 			case focusFlowMatrix:
 				m.flowMatrix.MoveDown()
+			case focusGantt:
+				m.gantt.MoveDown()
+			case focusFlowMetrics:
+				m.flowMetrics.MoveDown()
 			}
 			return m, nil
 		}
@@ -3598,6 +3793,13 @@ func (m Model) handleGraphKeys(msg tea.KeyMsg) Model {
 		m.graphView.ScrollLeft()
 	case "L":
 		m.graphView.ScrollRight()
+	case "C":
+		if m.graphView.ToggleCommunityColors() {
+			m.statusMsg = "Graph nodes colored by community"
+		} else {
+			m.statusMsg = "Graph nodes colored by status"
+		}
+		m.statusIsError = false
 	case "enter":
 		if selected := m.graphView.SelectedIssue(); selected != nil {
 			// Find and select in list
@@ -4091,6 +4293,100 @@ func (m Model) handleFlowMatrixKeys(msg tea.KeyMsg) Model {
 	return m
 }
 
+// handleGanttKeys handles keyboard input when the schedule view is focused
+func (m Model) handleGanttKeys(msg tea.KeyMsg) Model {
+	switch msg.String() {
+	case "@", "q", "esc":
+		m.focused = focusList
+	case "j", "down":
+		m.gantt.MoveDown()
+	case "k", "up":
+		m.gantt.MoveUp()
+	case "G", "end":
+		m.gantt.GoToEnd()
+	case "g", "home":
+		m.gantt.GoToStart()
+	case "enter":
+		id := m.gantt.SelectedIssueID()
+		if id == "" {
+			break
+		}
+		for i, item := range m.list.Items() {
+			if issueItem, ok := item.(IssueItem); ok && issueItem.Issue.ID == id {
+				m.list.Select(i)
+				break
+			}
+		}
+		m.focused = focusDetail
+		if !m.isSplitView {
+			m.showDetails = true
+			m.viewport.GotoTop()
+		}
+		m.updateViewportContent()
+	}
+	return m
+}
+
+// handleFlowMetricsKeys handles keyboard input when the flow metrics view is focused
+func (m Model) handleFlowMetricsKeys(msg tea.KeyMsg) Model {
+	switch msg.String() {
+	case "%", "q", "esc":
+		m.focused = focusList
+	case "j", "down":
+		m.flowMetrics.MoveDown()
+	case "k", "up":
+		m.flowMetrics.MoveUp()
+	case "enter":
+		id := m.flowMetrics.SelectedIssueID()
+		if id == "" {
+			break
+		}
+		for i, item := range m.list.Items() {
+			if issueItem, ok := item.(IssueItem); ok && issueItem.Issue.ID == id {
+				m.list.Select(i)
+				break
+			}
+		}
+		m.focused = focusDetail
+		if !m.isSplitView {
+			m.showDetails = true
+			m.viewport.GotoTop()
+		}
+		m.updateViewportContent()
+	}
+	return m
+}
+
+// refreshFlowMetrics recomputes flow metrics from the loaded git history,
+// or from issue timestamps while history is still loading.
+func (m *Model) refreshFlowMetrics() {
+	events := flow.EventsFromHistory(m.historyView.report)
+	m.flowMetrics.SetData(flow.Compute(m.issues, events, flow.Options{Now: time.Now()}), m.historyLoading)
+}
+
+// SetRosterPath sets the roster file for the schedule view. Empty means
+// .bv/roster.yaml in the project, falling back to the issues' assignees.
+func (m *Model) SetRosterPath(path string) {
+	m.rosterPath = path
+}
+
+// buildSchedule schedules the open issues on the configured roster.
+func (m Model) buildSchedule() (analysis.Schedule, string, error) {
+	path := m.rosterPath
+	if path == "" {
+		path = analysis.RosterPath(m.workDir)
+	}
+	roster, err := analysis.LoadRoster(path)
+	if err != nil {
+		return analysis.Schedule{}, "", err
+	}
+	source := path
+	if len(roster.Workers) == 0 {
+		roster, source = analysis.DefaultRoster(m.issues, 0), "derived"
+	}
+	return analysis.BuildSchedule(m.issues, roster, analysis.ScheduleOptions{Start: time.Now()}), source, nil
+}
+
 // handleRecipePickerKeys handles keyboard input when recipe picker is focused
 func (m Model) handleRecipePickerKeys(msg tea.KeyMsg) Model {
 	switch msg.String() {
@@ -4405,6 +4701,12 @@ func (m Model) restoreFocusFromHelp() focus {
 	if m.focusBeforeHelp == focusFlowMatrix {
 		return focusFlowMatrix
 	}
+	if m.focusBeforeHelp == focusGantt {
+		return focusGantt
+	}
+	if m.focusBeforeHelp == focusFlowMetrics {
+		return focusFlowMetrics
+	}
 	if m.focusBeforeHelp == focusAttention {
 		return focusAttention
 	}
@@ -4513,6 +4815,10 @@ func (m Model) View() string {
 		body = m.renderAlertsPanel()
 	} else if m.showTimeTravelPrompt {
 		body = m.renderTimeTravelPrompt()
+	} else if m.editPrompt != editPromptNone {
+		body = m.renderEditPrompt()
+	} else if m.depEdit != nil {
+		body = m.renderDependencyEdit()
 	} else if m.showRecipePicker {
 		body = m.recipePicker.View()
 	} else if m.showRepoPicker {
@@ -4532,6 +4838,12 @@ func (m Model) View() string {
 	} else if m.focused == focusFlowMatrix {
 		m.flowMatrix.SetSize(m.width, m.height-1)
 		body = m.flowMatrix.View()
+	} else if m.focused == focusGantt {
+		m.gantt.SetSize(m.width, m.height-1)
+		body = m.gantt.View()
+	} else if m.focused == focusFlowMetrics {
+		m.flowMetrics.SetSize(m.width, m.height-1)
+		body = m.flowMetrics.View()
 	} else if m.focused == focusTree {
 		// Hierarchical tree view (bv-gllx)
 		m.tree.SetSize(m.width, m.height-1)
@@ -4871,6 +5183,8 @@ func (m *Model) renderHelpOverlay() string {
 		{"h", "History view"},
 		{"a", "Actionable"},
 		{"f", "Flow matrix"},
+		{"@", "Schedule (Gantt)"},
+		{"%", "Flow metrics"},
 		{"[", "Label dashboard"},
 		{"]", "Attention view"},
 	}
@@ -4890,6 +5204,7 @@ func (m *Model) renderHelpOverlay() string {
 		{"Ctrl+S", "Semantic search"},
 		{"H", "Hybrid ranking"},
 		{"Alt+H", "Hybrid preset"},
+		{"Alt+S", "Search kinds"},
 		{"o", "Open issues"},
 		{"c", "Closed issues"},
 		{"r", "Ready (unblocked)"},
@@ -4903,6 +5218,8 @@ func (m *Model) renderHelpOverlay() string {
 		{"H/L", "Scroll left/right"},
 		{"PgUp/Dn", "Scroll up/down"},
 		{"Enter", "Jump to issue"},
+		{"Space", "Mark dependent"},
+		{"+ / -", "Add / remove dep"},
 	}
 
 	insightsSection := []struct{ key, desc string }{
@@ -4933,6 +5250,16 @@ func (m *Model) renderHelpOverlay() string {
 		{"O", "Open in editor"},
 	}
 
+	editSection := []struct{ key, desc string }{
+		{"m", "Cycle status"},
+		{"P", "Set priority 0-4"},
+		{"A", "Assign"},
+		{"+ / -", "Add / remove label"},
+		{"M", "Add comment"},
+		{"z", "Undo last edit"},
+		{"Space", "Board: grab & move card"},
+	}
+
 	statusSection := []struct{ key, desc string }{
 		{"◌ metrics", "Phase 2 metrics computing"},
 		{"⚠ age", "Snapshot getting stale"},
@@ -4954,6 +5281,7 @@ func (m *Model) renderHelpOverlay() string {
 		renderPanel("Status", "🩺", 2, statusSection),
 		renderPanel("History", "📜", 0, historySection),
 		renderPanel("Actions", "⚡", 1, actionsSection),
+		renderPanel("Edit", "✎", 3, editSection),
 	}
 
 	// Arrange panels into columns
@@ -5564,6 +5892,12 @@ func (m *Model) renderFooter() string {
 					mode = fmt.Sprintf("hybrid/%s (metrics)", m.semanticHybridPreset)
 				}
 			}
+			if !search.IsIssueOnly(m.semanticKinds) {
+				mode += " · " + search.FormatDocKinds(m.semanticKinds)
+				if m.semanticDocsBuilding {
+					mode += " (indexing)"
+				}
+			}
 		}
 		searchBadge = lipgloss.NewStyle().
 			Background(ColorBgHighlight).
@@ -5963,10 +6297,20 @@ func (m *Model) renderFooter() string {
 		keyHints = append(keyHints, keyStyle.Render("A")+" attention", keyStyle.Render("F")+" flow")
 	} else if m.focused == focusFlowMatrix {
 		keyHints = append(keyHints, keyStyle.Render("j/k")+" nav", keyStyle.Render("tab")+" panel", keyStyle.Render("⏎")+" drill", keyStyle.Render("esc")+" back", keyStyle.Render("f")+" close")
+	} else if m.focused == focusGantt {
+		keyHints = append(keyHints, keyStyle.Render("j/k")+" nav", keyStyle.Render("⏎")+" open", keyStyle.Render("esc")+" back", keyStyle.Render("@")+" close")
+	} else if m.focused == focusFlowMetrics {
+		keyHints = append(keyHints, keyStyle.Render("j/k")+" WIP", keyStyle.Render("⏎")+" open", keyStyle.Render("esc")+" back", keyStyle.Render("%")+" close")
+	} else if m.depEdit != nil {
+		keyHints = append(keyHints, keyStyle.Render("b/r/p")+" type", keyStyle.Render("⏎")+" confirm", keyStyle.Render("esc")+" cancel")
+	} else if m.isGraphView && m.graphView.LinkSource() != "" {
+		keyHints = append(keyHints, keyStyle.Render("j/k")+" target", keyStyle.Render("+")+" add dep", keyStyle.Render("-")+" remove dep", keyStyle.Render("esc")+" clear")
 	} else if m.isGraphView {
-		keyHints = append(keyHints, keyStyle.Render("hjkl")+" nav", keyStyle.Render("H/L")+" scroll", keyStyle.Render("⏎")+" view", keyStyle.Render("g")+" list")
+		keyHints = append(keyHints, keyStyle.Render("hjkl")+" nav", keyStyle.Render("space")+" link", keyStyle.Render("⏎")+" view", keyStyle.Render("g")+" list")
+	} else if m.isBoardView && m.board.IsGrabbing() {
+		keyHints = append(keyHints, keyStyle.Render("h/l")+" column", keyStyle.Render("⏎")+" drop", keyStyle.Render("esc")+" cancel")
 	} else if m.isBoardView {
-		keyHints = append(keyHints, keyStyle.Render("hjkl")+" nav", keyStyle.Render("G")+" bottom", keyStyle.Render("⏎")+" view", keyStyle.Render("b")+" list")
+		keyHints = append(keyHints, keyStyle.Render("hjkl")+" nav", keyStyle.Render("G")+" bottom", keyStyle.Render("space")+" move", keyStyle.Render("⏎")+" view", keyStyle.Render("b")+" list")
 	} else if m.isActionableView {
 		keyHints = append(keyHints, keyStyle.Render("j/k")+" nav", keyStyle.Render("⏎")+" view", keyStyle.Render("a")+" list", keyStyle.Render("?")+" help")
 	} else if m.isHistoryView {
@@ -5981,10 +6325,14 @@ func (m *Model) renderFooter() string {
 		}
 		keyHints = append(keyHints, keyStyle.Render("esc")+" cancel", keyStyle.Render("ctrl+s")+" "+mode, keyStyle.Render("⏎")+" select")
 		if m.semanticSearchEnabled {
-			keyHints = append(keyHints, keyStyle.Render("H")+" hybrid", keyStyle.Render("alt+h")+" preset")
+			keyHints = append(keyHints, keyStyle.Render("H")+" hybrid", keyStyle.Render("alt+h")+" preset", keyStyle.Render("alt+s")+" kinds")
 		}
 	} else if m.showTimeTravelPrompt {
 		keyHints = append(keyHints, keyStyle.Render("⏎")+" compare", keyStyle.Render("esc")+" cancel")
+	} else if m.editPrompt == editPromptPriority {
+		keyHints = append(keyHints, keyStyle.Render("0-4")+" priority", keyStyle.Render("esc")+" cancel")
+	} else if m.editPrompt != editPromptNone {
+		keyHints = append(keyHints, keyStyle.Render("⏎")+" save", keyStyle.Render("esc")+" cancel")
 	} else {
 		if m.timeTravelMode {
 			keyHints = append(keyHints, keyStyle.Render("t")+" exit diff", keyStyle.Render("C")+" copy", keyStyle.Render("abgi")+" views", keyStyle.Render("?")+" help")
@@ -6120,6 +6468,53 @@ func nextHybridPreset(current search.PresetName) search.PresetName {
 	return presets[0]
 }
 
+// searchKindCycle is the order Alt+S steps through unified search kinds.
+var searchKindCycle = [][]search.DocKind{
+	{search.KindIssue},
+	{search.KindIssue, search.KindComment},
+	{search.KindIssue, search.KindComment, search.KindCommit},
+	search.AllDocKinds,
+}
+
+func nextSearchKinds(current []search.DocKind) []search.DocKind {
+	key := search.FormatDocKinds(current)
+	for i, kinds := range searchKindCycle {
+		if search.FormatDocKinds(kinds) == key {
+			return searchKindCycle[(i+1)%len(searchKindCycle)]
+		}
+	}
+	return searchKindCycle[0]
+}
+
+// semanticDocsCmd (re)builds the comment and commit indexes when semantic
+// search covers them. A request made while a build is running is replayed
+// once that build finishes.
+func (m *Model) semanticDocsCmd() tea.Cmd {
+	if !m.semanticSearchEnabled || m.semanticSearch == nil || search.IsIssueOnly(m.semanticKinds) {
+		return nil
+	}
+	if m.semanticDocsBuilding {
+		m.semanticDocsStale = true
+		return nil
+	}
+	m.semanticDocsBuilding = true
+	m.semanticDocsStale = false
+	return BuildSemanticDocsCmd(m.issuesForAsync(), m.historyView.report, m.semanticKinds)
+}
+
+// ensureSessionSearcher wires cass into semantic search the first time
+// sessions are included. cass failures yield no session matches.
+func (m *Model) ensureSessionSearcher() {
+	if m.semanticSearch == nil || m.semanticSearch.getSessionSearcher() != nil {
+		return
+	}
+	searcher := cass.NewSearcher(cass.NewDetector())
+	workDir := m.workDir
+	m.semanticSearch.SetSessionSearcher(func(ctx context.Context, query string) []cass.SearchResult {
+		return searcher.SearchInWorkspace(ctx, query, workDir).Results
+	})
+}
+
 // getDiffStatus returns the diff status for an issue if time-travel mode is active
 func (m Model) getDiffStatus(id string) DiffStatus {
 	if !m.timeTravelMode {
@@ -6214,6 +6609,7 @@ func (m *Model) filteredIssuesForActiveView() []model.Issue {
 	filtered := make([]model.Issue, 0, len(m.issues))
 	recipeFilterActive := m.activeRecipe != nil && strings.HasPrefix(m.currentFilter, "recipe:")
 	if recipeFilterActive {
+		rq := newLiveRecipeQuery(m.activeRecipe, m.issues, m.analysis)
 		for _, issue := range m.issues {
 			if m.workspaceMode && m.activeRepos != nil {
 				repoKey := strings.ToLower(ExtractRepoPrefix(issue.ID))
@@ -6221,7 +6617,7 @@ func (m *Model) filteredIssuesForActiveView() []model.Issue {
 					continue
 				}
 			}
-			if issueMatchesRecipe(issue, m.issueMap, m.activeRecipe) {
+			if issueMatchesRecipe(issue, m.issueMap, m.activeRecipe, rq) {
 				filtered = append(filtered, issue)
 			}
 		}
@@ -6236,6 +6632,37 @@ func (m *Model) filteredIssuesForActiveView() []model.Issue {
 	return filtered
 }
 
+// recomputeCounts refreshes the open/ready/blocked/closed footer counts from
+// m.issues and m.issueMap.
+func (m *Model) recomputeCounts() {
+	m.countOpen, m.countReady, m.countBlocked, m.countClosed = 0, 0, 0, 0
+	for i := range m.issues {
+		issue := &m.issues[i]
+		if isClosedLikeStatus(issue.Status) {
+			m.countClosed++
+			continue
+		}
+		m.countOpen++
+		if issue.Status == model.StatusBlocked {
+			m.countBlocked++
+			continue
+		}
+		isBlocked := false
+		for _, dep := range issue.Dependencies {
+			if dep == nil || !dep.Type.IsBlocking() {
+				continue
+			}
+			if blocker, exists := m.issueMap[dep.DependsOnID]; exists && !isClosedLikeStatus(blocker.Status) {
+				isBlocked = true
+				break
+			}
+		}
+		if !isBlocked {
+			m.countReady++
+		}
+	}
+}
+
 func (m *Model) refreshBoardAndGraphForCurrentFilter() {
 	if !m.isBoardView && !m.isGraphView {
 		return
@@ -6309,7 +6736,7 @@ func (m *Model) applyFilter() {
 	m.sortFilteredItems(filteredItems, filteredIssues)
 
 	m.list.SetItems(filteredItems)
-	m.updateSemanticIDs(filteredItems)
+	m.updateFilterItems(filteredItems)
 	if m.snapshot != nil && m.snapshot.BoardState != nil && m.currentFilter == "all" && (!m.workspaceMode || m.activeRepos == nil) && len(filteredIssues) == len(m.snapshot.Issues) {
 		m.board.SetSnapshot(m.snapshot)
 	} else {
@@ -6457,6 +6884,7 @@ func (m *Model) applyRecipe(r *recipe.Recipe) {
 
 	var filteredItems []list.Item
 	var filteredIssues []model.Issue
+	rq := newLiveRecipeQuery(r, m.issues, m.analysis)
 
 	for _, issue := range m.issues {
 		include := true
@@ -6523,6 +6951,9 @@ func (m *Model) applyRecipe(r *recipe.Recipe) {
 			include = !isBlocked
 		}
 
+		// Apply query
+		include = include && rq.match(&issue)
+
 		if include {
 			item := IssueItem{
 				Issue:      issue,
@@ -6663,7 +7094,7 @@ func (m *Model) applyRecipe(r *recipe.Recipe) {
 	}
 
 	m.list.SetItems(filteredItems)
-	m.updateSemanticIDs(filteredItems)
+	m.updateFilterItems(filteredItems)
 	m.board.SetIssues(filteredIssues)
 	// Generate insights for graph view (for metric rankings and sorting)
 	recipeIns := m.analysis.GenerateInsights(len(filteredIssues))
@@ -6808,6 +7239,22 @@ func (m *Model) updateViewportContent() {
 		sb.WriteString("\n")
 	}
 
+	// Search Matches (unified search over comments, commits and sessions)
+	if m.semanticSearchEnabled && len(issueItem.SearchMatches) > 0 && m.list.FilterState() != list.Unfiltered {
+		sb.WriteString("### 🔎 Search Matches\n")
+		for i, hit := range issueItem.SearchMatches {
+			if i == 5 {
+				sb.WriteString(fmt.Sprintf("- … %d more\n", len(issueItem.SearchMatches)-i))
+				break
+			}
+			sb.WriteString(fmt.Sprintf("- **%s** %s (%.3f)\n", hit.Kind, hit.Title, hit.Score))
+			if hit.Snippet != "" {
+				sb.WriteString(fmt.Sprintf("  > %s\n", hit.Snippet))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
 	// Graph Analysis (using thread-safe accessors)
 	pr := m.analysis.GetPageRankScore(item.ID)
 	bt := m.analysis.GetBetweennessScore(item.ID)
@@ -7233,6 +7680,10 @@ func (m Model) FocusState() string {
 		return "agent_prompt"
 	case focusFlowMatrix:
 		return "flow_matrix"
+	case focusGantt:
+		return "gantt"
+	case focusFlowMetrics:
+		return "flow_metrics"
 	case focusTutorial:
 		return "tutorial"
 	case focusCassModal:
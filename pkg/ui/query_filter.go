@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"sync/atomic"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/query"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+type queryFilterSnapshot struct {
+	issues []model.Issue // Parallel to the list items
+	ctx    *query.Context
+}
+
+// QueryFilter lets the list's '/' prompt take structured queries such as
+// "status:open priority<=1 -label:wontfix". Terms that use no field, or
+// fail to parse while being typed, fall through to the wrapped fuzzy or
+// semantic filter. The list runs filters off the UI goroutine, so the
+// snapshot is swapped atomically.
+type QueryFilter struct {
+	snapshot atomic.Value // queryFilterSnapshot
+}
+
+func NewQueryFilter() *QueryFilter {
+	f := &QueryFilter{}
+	f.snapshot.Store(queryFilterSnapshot{})
+	return f
+}
+
+// SetItems records the list items a query is evaluated against. all is the
+// full issue set, so relations such as blockers:(...) see issues the current
+// view hides; stats may be nil.
+func (f *QueryFilter) SetItems(items []list.Item, all []model.Issue, stats *analysis.GraphStats) {
+	issues := make([]model.Issue, len(items))
+	for i, it := range items {
+		if issueItem, ok := it.(IssueItem); ok {
+			issues[i] = issueItem.Issue
+		}
+	}
+	f.snapshot.Store(queryFilterSnapshot{issues: issues, ctx: query.NewContext(all, stats)})
+}
+
+// Wrap returns a list filter that evaluates structured queries and defers
+// everything else to base.
+func (f *QueryFilter) Wrap(base list.FilterFunc) list.FilterFunc {
+	return func(term string, targets []string) []list.Rank {
+		snap := f.snapshot.Load().(queryFilterSnapshot)
+		if snap.ctx == nil || len(snap.issues) != len(targets) {
+			return base(term, targets)
+		}
+		q, err := query.Parse(term)
+		if err != nil || !q.HasFields() {
+			return base(term, targets)
+		}
+		var ranks []list.Rank
+		for i := range snap.issues {
+			if q.Match(&snap.issues[i], snap.ctx) {
+				ranks = append(ranks, list.Rank{Index: i})
+			}
+		}
+		return ranks
+	}
+}
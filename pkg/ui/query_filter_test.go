@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+func TestQueryFilter(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "bv-1", Title: "Login timeout", Status: model.StatusOpen, Priority: 1, IssueType: model.TypeBug, Labels: []string{"api"}},
+		{ID: "bv-2", Title: "Add export", Status: model.StatusOpen, Priority: 2, IssueType: model.TypeFeature,
+			Dependencies: []*model.Dependency{{IssueID: "bv-2", DependsOnID: "bv-1", Type: model.DepBlocks}}},
+		{ID: "bv-3", Title: "Old crash", Status: model.StatusClosed, Priority: 0, IssueType: model.TypeBug},
+	}
+	items := make([]list.Item, len(issues))
+	targets := make([]string, len(issues))
+	for i := range issues {
+		items[i] = IssueItem{Issue: issues[i]}
+		targets[i] = items[i].FilterValue()
+	}
+
+	f := NewQueryFilter()
+	f.SetItems(items, issues, nil)
+	var baseCalls int
+	filter := f.Wrap(func(term string, targets []string) []list.Rank {
+		baseCalls++
+		return list.DefaultFilter(term, targets)
+	})
+
+	indexes := func(ranks []list.Rank) []int {
+		out := []int{}
+		for _, r := range ranks {
+			out = append(out, r.Index)
+		}
+		return out
+	}
+
+	tests := []struct {
+		term string
+		want []int
+		base bool
+	}{
+		{term: "type:bug is:open", want: []int{0}},
+		{term: "blockers:(label:api)", want: []int{1}},
+		{term: "priority<=1", want: []int{0, 2}},
+		{term: "login", want: []int{0}, base: true},     // Plain text stays fuzzy
+		{term: "priority<=", want: []int{}, base: true}, // Still being typed
+	}
+	for _, tt := range tests {
+		baseCalls = 0
+		got := indexes(filter(tt.term, targets))
+		if len(got) != len(tt.want) {
+			t.Errorf("%q: got %v, want %v", tt.term, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%q: got %v, want %v", tt.term, got, tt.want)
+				break
+			}
+		}
+		if (baseCalls > 0) != tt.base {
+			t.Errorf("%q: base filter called = %v, want %v", tt.term, baseCalls > 0, tt.base)
+		}
+	}
+
+	// Targets out of sync with the snapshot defer to the base filter
+	baseCalls = 0
+	filter("type:bug", targets[:2])
+	if baseCalls != 1 {
+		t.Error("Expected base filter when targets do not match the items")
+	}
+}
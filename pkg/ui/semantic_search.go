@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/cass"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/correlation"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/search"
 
@@ -22,6 +24,10 @@ type semanticSearchSnapshot struct {
 	Embedder search.Embedder
 	IDs      []string
 	Docs     map[string]string
+	// KindIndexes and KindDocs hold the comment and commit documents for
+	// unified search, keyed by kind and by document key.
+	KindIndexes map[search.DocKind]*search.VectorIndex
+	KindDocs    map[string]search.SearchDocument
 }
 
 // semanticResultCache holds cached filter results and pending state
@@ -46,19 +52,29 @@ type metricsCacheHolder struct {
 	cache search.MetricsCache
 }
 
+// SessionSearchFunc finds cass session snippets for a query.
+type SessionSearchFunc func(ctx context.Context, query string) []cass.SearchResult
+
+type sessionSearchHolder struct {
+	fn SessionSearchFunc
+}
+
 // SemanticScore captures semantic/hybrid scoring details for a single issue.
 type SemanticScore struct {
 	Score      float64
 	TextScore  float64
 	Components map[string]float64
+	Matches    []search.SearchHit // Comment, commit and session matches, best first
 }
 
 type SemanticSearch struct {
-	snapshot     atomic.Value // semanticSearchSnapshot
-	cache        atomic.Value // *semanticResultCache
-	scores       atomic.Value // *semanticScoreCache
-	hybridConfig atomic.Value // semanticHybridConfig
-	metricsCache atomic.Value // *metricsCacheHolder
+	snapshot      atomic.Value // semanticSearchSnapshot
+	cache         atomic.Value // *semanticResultCache
+	scores        atomic.Value // *semanticScoreCache
+	hybridConfig  atomic.Value // semanticHybridConfig
+	metricsCache  atomic.Value // *metricsCacheHolder
+	kinds         atomic.Value // []search.DocKind
+	sessionSearch atomic.Value // *sessionSearchHolder
 }
 
 func NewSemanticSearch() *SemanticSearch {
@@ -67,6 +83,8 @@ func NewSemanticSearch() *SemanticSearch {
 	s.cache.Store(&semanticResultCache{results: make(map[string][]list.Rank)})
 	s.scores.Store(&semanticScoreCache{scores: make(map[string]SemanticScore)})
 	s.metricsCache.Store(&metricsCacheHolder{})
+	s.kinds.Store([]search.DocKind{search.KindIssue})
+	s.sessionSearch.Store(&sessionSearchHolder{})
 	defaultWeights, err := search.GetPreset(search.PresetDefault)
 	if err != nil {
 		defaultWeights = search.Weights{TextRelevance: 1.0}
@@ -164,6 +182,35 @@ func (s *SemanticSearch) SetMetricsCache(cache search.MetricsCache) {
 	s.metricsCache.Store(&metricsCacheHolder{cache: cache})
 }
 
+// Kinds returns the document kinds searched.
+func (s *SemanticSearch) Kinds() []search.DocKind {
+	v := s.kinds.Load()
+	if v == nil {
+		return []search.DocKind{search.KindIssue}
+	}
+	return v.([]search.DocKind)
+}
+
+// SetKinds selects the document kinds searched.
+func (s *SemanticSearch) SetKinds(kinds []search.DocKind) {
+	cp := make([]search.DocKind, len(kinds))
+	copy(cp, kinds)
+	s.kinds.Store(cp)
+}
+
+// SetSessionSearcher sets the cass lookup used when sessions are searched.
+func (s *SemanticSearch) SetSessionSearcher(fn SessionSearchFunc) {
+	s.sessionSearch.Store(&sessionSearchHolder{fn: fn})
+}
+
+func (s *SemanticSearch) getSessionSearcher() SessionSearchFunc {
+	v := s.sessionSearch.Load()
+	if v == nil {
+		return nil
+	}
+	return v.(*sessionSearchHolder).fn
+}
+
 // ResetCache clears cached semantic results and scores.
 func (s *SemanticSearch) ResetCache() {
 	s.cache.Store(&semanticResultCache{results: make(map[string][]list.Rank)})
@@ -229,6 +276,17 @@ func (s *SemanticSearch) SetIndex(idx *search.VectorIndex, embedder search.Embed
 	s.snapshot.Store(snap)
 }
 
+// SetKindIndexes installs the per-kind indexes and their documents.
+func (s *SemanticSearch) SetKindIndexes(indexes map[search.DocKind]*search.VectorIndex, docs []search.SearchDocument) {
+	snap := s.Snapshot()
+	snap.KindIndexes = indexes
+	snap.KindDocs = make(map[string]search.SearchDocument, len(docs))
+	for _, d := range docs {
+		snap.KindDocs[d.Key] = d
+	}
+	s.snapshot.Store(snap)
+}
+
 func (s *SemanticSearch) SetIDs(ids []string) {
 	snap := s.Snapshot()
 	cp := make([]string, len(ids))
@@ -297,7 +355,13 @@ func (s *SemanticSearch) ComputeSemanticResults(term string) []list.Rank {
 		return nil
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	kinds := s.Kinds()
+	timeout := 500 * time.Millisecond
+	if hasDocKind(kinds, search.KindSession) && s.getSessionSearcher() != nil {
+		// cass runs as a subprocess; give it time before giving up on sessions.
+		timeout = 3 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	vecs, err := snap.Embedder.Embed(ctx, []string{term})
@@ -305,6 +369,8 @@ func (s *SemanticSearch) ComputeSemanticResults(term string) []list.Rank {
 		return nil
 	}
 	q := vecs[0]
+	searchIssues := hasDocKind(kinds, search.KindIssue)
+	matches := s.matchDocuments(ctx, snap, kinds, term, q)
 
 	hybridConfig := s.getHybridConfig()
 	var scorer search.HybridScorer
@@ -327,17 +393,24 @@ func (s *SemanticSearch) ComputeSemanticResults(term string) []list.Rank {
 	scoreMap := make(map[string]SemanticScore, len(snap.IDs))
 	for i, id := range snap.IDs {
 		entry, ok := snap.Index.Get(id)
+		hits := matches[id]
 		textScore := 0.0
 		score := 0.0
-		if !ok {
+		if !ok && len(hits) == 0 {
 			// Item not in index (e.g. new issue before re-indexing).
 			// Assign lowest possible score to keep it in the list but at the bottom.
 			score = -2.0
 			textScore = score
 		} else {
-			textScore = dotFloat32(q, entry.Vector)
-			if doc, ok := snap.Docs[id]; ok {
-				textScore += search.ShortQueryLexicalBoost(term, doc)
+			if ok && searchIssues {
+				textScore = dotFloat32(q, entry.Vector)
+				if doc, ok := snap.Docs[id]; ok {
+					textScore += search.ShortQueryLexicalBoost(term, doc)
+				}
+			}
+			// An issue ranks by its best matching document of any kind.
+			if len(hits) > 0 && (!searchIssues || hits[0].Score > textScore) {
+				textScore = hits[0].Score
 			}
 			score = textScore
 		}
@@ -346,11 +419,12 @@ func (s *SemanticSearch) ComputeSemanticResults(term string) []list.Rank {
 			id:        id,
 			score:     score,
 			textScore: textScore,
-			hasVector: ok,
+			hasVector: ok || len(hits) > 0,
 		}
 		scoreMap[id] = SemanticScore{
 			Score:     score,
 			TextScore: textScore,
+			Matches:   hits,
 		}
 	}
 
@@ -397,6 +471,7 @@ func (s *SemanticSearch) ComputeSemanticResults(term string) []list.Rank {
 				Score:      hybridScore.FinalScore,
 				TextScore:  hybridScore.TextScore,
 				Components: hybridScore.ComponentScores,
+				Matches:    matches[item.id],
 			}
 		}
 	}
@@ -419,6 +494,75 @@ func (s *SemanticSearch) ComputeSemanticResults(term string) []list.Rank {
 	return out
 }
 
+// semanticMatchLimit caps how many documents of each kind are matched per query.
+const semanticMatchLimit = 200
+
+// matchDocuments scores the comment, commit and session documents enabled
+// in kinds against q and returns the positive matches for each bead.
+func (s *SemanticSearch) matchDocuments(ctx context.Context, snap semanticSearchSnapshot, kinds []search.DocKind, term string, q []float32) map[string][]search.SearchHit {
+	var hits []search.SearchHit
+	for _, kind := range kinds {
+		var results []search.SearchResult
+		docs := snap.KindDocs
+		switch kind {
+		case search.KindComment, search.KindCommit:
+			idx := snap.KindIndexes[kind]
+			if idx == nil {
+				continue
+			}
+			results, _ = idx.SearchTopK(q, semanticMatchLimit)
+		case search.KindSession:
+			sessions := s.getSessionSearcher()
+			if sessions == nil {
+				continue
+			}
+			known := make(map[string]bool, len(snap.IDs))
+			for _, id := range snap.IDs {
+				known[id] = true
+			}
+			sessionDocs := search.SessionDocuments(sessions(ctx, term), known)
+			results, _ = search.ScoreDocuments(ctx, snap.Embedder, q, sessionDocs)
+			docs = make(map[string]search.SearchDocument, len(sessionDocs))
+			for _, d := range sessionDocs {
+				docs[d.Key] = d
+			}
+		}
+		for _, r := range results {
+			doc, ok := docs[r.IssueID]
+			if !ok || doc.Kind != kind {
+				continue
+			}
+			score := r.Score + search.ShortQueryLexicalBoost(term, doc.Text)
+			if score > 0 {
+				hits = append(hits, search.NewSearchHit(doc, score))
+			}
+		}
+	}
+	if len(hits) == 0 {
+		return nil
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score == hits[j].Score {
+			return hits[i].Key < hits[j].Key
+		}
+		return hits[i].Score > hits[j].Score
+	})
+	byBead := make(map[string][]search.SearchHit)
+	for _, g := range search.GroupHitsByBead(hits) {
+		byBead[g.BeadID] = g.Hits
+	}
+	return byBead
+}
+
+func hasDocKind(kinds []search.DocKind, kind search.DocKind) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
 // SemanticIndexReadyMsg is emitted when the semantic index build/update completes.
 type SemanticIndexReadyMsg struct {
 	Embedder  search.Embedder
@@ -495,8 +639,9 @@ func BuildSemanticIndexCmd(issues []model.Issue) tea.Cmd {
 		if err != nil {
 			return SemanticIndexReadyMsg{Error: err}
 		}
+		annBuilt := idx.SetHNSWConfig(search.HNSWConfigFromEnv())
 
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.SyncTimeout())
 		defer cancel()
 
 		docs := search.DocumentsFromIssues(issues)
@@ -504,7 +649,7 @@ func BuildSemanticIndexCmd(issues []model.Issue) tea.Cmd {
 		if err != nil {
 			return SemanticIndexReadyMsg{Error: err}
 		}
-		if !loaded || stats.Changed() {
+		if !loaded || stats.Changed() || annBuilt {
 			if err := idx.Save(indexPath); err != nil {
 				return SemanticIndexReadyMsg{Error: fmt.Errorf("save semantic index: %w", err)}
 			}
@@ -520,6 +665,64 @@ func BuildSemanticIndexCmd(issues []model.Issue) tea.Cmd {
 	}
 }
 
+// SemanticDocsReadyMsg is emitted when the comment and commit indexes for
+// unified search have been built or updated.
+type SemanticDocsReadyMsg struct {
+	Kinds   []search.DocKind
+	Indexes map[search.DocKind]*search.VectorIndex
+	Docs    []search.SearchDocument
+	Stats   map[search.DocKind]search.IndexSyncStats
+	Error   error
+}
+
+// BuildSemanticDocsCmd builds or updates the persisted indexes of the
+// comment and commit documents selected by kinds. Commits come from report
+// and are skipped while it is nil; sessions are searched live per query.
+func BuildSemanticDocsCmd(issues []model.Issue, report *correlation.HistoryReport, kinds []search.DocKind) tea.Cmd {
+	return func() tea.Msg {
+		cfg := search.EmbeddingConfigFromEnv()
+		embedder, err := search.NewEmbedderFromConfig(cfg)
+		if err != nil {
+			return SemanticDocsReadyMsg{Kinds: kinds, Error: err}
+		}
+		projectDir, err := os.Getwd()
+		if err != nil {
+			return SemanticDocsReadyMsg{Kinds: kinds, Error: err}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.SyncTimeout())
+		defer cancel()
+
+		msg := SemanticDocsReadyMsg{
+			Kinds:   kinds,
+			Indexes: make(map[search.DocKind]*search.VectorIndex),
+			Stats:   make(map[search.DocKind]search.IndexSyncStats),
+		}
+		for _, kind := range kinds {
+			var docs []search.SearchDocument
+			switch kind {
+			case search.KindComment:
+				docs = search.CommentDocuments(issues)
+			case search.KindCommit:
+				if report == nil {
+					continue
+				}
+				docs = search.CommitDocuments(report)
+			default:
+				continue
+			}
+			idx, stats, err := search.SyncKindIndex(ctx, projectDir, cfg, embedder, kind, docs)
+			if err != nil {
+				return SemanticDocsReadyMsg{Kinds: kinds, Error: err}
+			}
+			msg.Indexes[kind] = idx
+			msg.Stats[kind] = stats
+			msg.Docs = append(msg.Docs, docs...)
+		}
+		return msg
+	}
+}
+
 func dotFloat32(a, b []float32) float64 {
 	if len(a) != len(b) || len(a) == 0 {
 		return 0
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/Dicklesworthstone/beads_viewer/pkg/cass"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/search"
 )
 
@@ -319,6 +320,62 @@ func TestSemanticSearchFilterSortsByScore(t *testing.T) {
 	}
 }
 
+func TestSemanticSearchUnifiedKinds(t *testing.T) {
+	ss := NewSemanticSearch()
+
+	idx := search.NewVectorIndex(3)
+	embedder := &mockEmbedder{
+		dim: 3,
+		embedFunc: func(ctx context.Context, texts []string) ([][]float32, error) {
+			result := make([][]float32, len(texts))
+			for i := range result {
+				result[i] = []float32{1.0, 0.0, 0.0}
+			}
+			return result, nil
+		},
+	}
+	ss.SetIndex(idx, embedder)
+	idx.Upsert("id-a", search.ContentHash{}, []float32{0.0, 1.0, 0.0})
+	idx.Upsert("id-b", search.ContentHash{}, []float32{0.6, 0.8, 0.0})
+	ss.SetIDs([]string{"id-a", "id-b"})
+
+	comments := search.NewVectorIndex(3)
+	comments.Upsert("comment:id-a:1", search.ContentHash{}, []float32{1.0, 0.0, 0.0})
+	ss.SetKindIndexes(map[search.DocKind]*search.VectorIndex{search.KindComment: comments}, []search.SearchDocument{
+		{Key: "comment:id-a:1", Kind: search.KindComment, BeadIDs: []string{"id-a"}, Title: "comment", Text: "exact hit", Excerpt: "exact hit"},
+	})
+
+	if ranks := ss.ComputeSemanticResults("query"); len(ranks) == 0 || ranks[0].Index != 1 {
+		t.Fatalf("issue-only search should rank id-b first, got %v", ranks)
+	}
+
+	ss.SetKinds([]search.DocKind{search.KindIssue, search.KindComment})
+	ranks := ss.ComputeSemanticResults("query")
+	if len(ranks) == 0 || ranks[0].Index != 0 {
+		t.Fatalf("comment match should lift id-a to the top, got %v", ranks)
+	}
+	scores, ok := ss.Scores("query")
+	if !ok || len(scores["id-a"].Matches) != 1 || scores["id-a"].Matches[0].Kind != search.KindComment {
+		t.Fatalf("expected id-a to carry its comment match, got %+v", scores["id-a"])
+	}
+	if len(scores["id-b"].Matches) != 0 {
+		t.Errorf("id-b has no comment matches, got %+v", scores["id-b"].Matches)
+	}
+
+	ss.SetKinds([]search.DocKind{search.KindSession})
+	ss.SetSessionSearcher(func(ctx context.Context, query string) []cass.SearchResult {
+		return []cass.SearchResult{{SourcePath: "s.jsonl", LineNumber: 3, Snippet: "finished id-b today"}}
+	})
+	ranks = ss.ComputeSemanticResults("query")
+	scores, _ = ss.Scores("query")
+	if len(ranks) == 0 || ranks[0].Index != 1 || len(scores["id-b"].Matches) != 1 {
+		t.Fatalf("session mentioning id-b should rank it first, got %v / %+v", ranks, scores["id-b"])
+	}
+	if scores["id-a"].TextScore != 0 {
+		t.Errorf("with issues excluded id-a should score 0, got %v", scores["id-a"].TextScore)
+	}
+}
+
 func TestSemanticSearchFilterLimit(t *testing.T) {
 	ss := NewSemanticSearch()
 
@@ -121,6 +121,9 @@ func (s *ShortcutsSidebar) allSections() []shortcutSection {
 				{"H/L", "Scroll ←/→"},
 				{"PgUp/Dn", "Scroll ↑/↓"},
 				{"Enter", "Jump to issue"},
+				{"Space", "Mark dependent"},
+				{"+/-", "Dep add/rm"},
+				{"z", "Undo edit"},
 			},
 		},
 		{
@@ -157,12 +160,25 @@ func (s *ShortcutsSidebar) allSections() []shortcutSection {
 			items: []shortcutItem{
 				{"h/l", "Columns ←/→"},
 				{"j/k", "Items ↓/↑"},
+				{"Space", "Grab/move card"},
 				{"Tab", "Toggle detail"},
 				{"y", "Copy ID"},
 				{"^j/^k", "Scroll detail"},
 				{"Enter", "Full view"},
 			},
 		},
+		{
+			title:    "Edit",
+			contexts: []string{"list", "detail", "split", "board"},
+			items: []shortcutItem{
+				{"m", "Cycle status"},
+				{"P", "Priority 0-4"},
+				{"A", "Assign"},
+				{"+/-", "Label add/rm"},
+				{"M", "Comment"},
+				{"z", "Undo edit"},
+			},
+		},
 		{
 			title:    "Filters",
 			contexts: []string{"list", "split"},
@@ -10,6 +10,7 @@ import (
 
 	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/query"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/recipe"
 )
 
@@ -158,6 +159,8 @@ type DataSnapshot struct {
 	// entering the tree view for large datasets.
 	TreeRoots   []*IssueTreeNode
 	TreeNodeMap map[string]*IssueTreeNode
+	// EpicRollups holds progress rolled up to every parent, for the tree's progress bars.
+	EpicRollups map[string]analysis.EpicRollup
 	// BoardState contains pre-built Kanban board columns for each swimlane mode (bv-guxz).
 	BoardState *BoardState
 	// GraphLayout contains pre-built graph view data (blockers/dependents, sorted IDs, ranks)
@@ -224,6 +227,9 @@ type GraphLayout struct {
 	RankCriticalPath map[string]int
 	RankInDegree     map[string]int
 	RankOutDegree    map[string]int
+
+	// Dependency-graph communities for node coloring
+	Communities *analysis.CommunityReport
 }
 
 // BoardState contains precomputed Kanban columns for each swimlane mode.
@@ -392,8 +398,9 @@ func (b *SnapshotBuilder) Build() *DataSnapshot {
 	viewIssues := issues
 	if b.recipe != nil {
 		viewIssues = make([]model.Issue, 0, len(issues))
+		rq := newRecipeQuery(b.recipe, issues, graphStats)
 		for i := range issues {
-			if issueMatchesRecipe(issues[i], issueMap, b.recipe) {
+			if issueMatchesRecipe(issues[i], issueMap, b.recipe, rq) {
 				viewIssues = append(viewIssues, issues[i])
 			}
 		}
@@ -467,9 +474,11 @@ func (b *SnapshotBuilder) Build() *DataSnapshot {
 	var (
 		treeRoots   []*IssueTreeNode
 		treeNodeMap map[string]*IssueTreeNode
+		epicRollups map[string]analysis.EpicRollup
 	)
 	if b.cfg.PrecomputeTree {
 		treeRoots, treeNodeMap = buildIssueTreeNodes(issues)
+		epicRollups = analysis.ComputeEpicRollups(issues).ByID()
 	}
 
 	var boardState *BoardState
@@ -506,6 +515,7 @@ func (b *SnapshotBuilder) Build() *DataSnapshot {
 		UnblocksMap:   unblocksMap,
 		TreeRoots:     treeRoots,
 		TreeNodeMap:   treeNodeMap,
+		EpicRollups:   epicRollups,
 		BoardState:    boardState,
 		GraphLayout:   graphLayout,
 		CreatedAt:     time.Now(),
@@ -617,6 +627,7 @@ func resetIssueItemForSnapshot(item *IssueItem, issue model.Issue, stats *analys
 	item.SearchTextScore = 0
 	item.SearchComponents = nil
 	item.SearchScoreSet = false
+	item.SearchMatches = nil
 
 	item.TriageScore = 0
 	item.TriageReason = ""
@@ -631,7 +642,42 @@ func isChangedID(changed map[string]struct{}, id string) bool {
 	return ok
 }
 
-func issueMatchesRecipe(issue model.Issue, issueMap map[string]*model.Issue, r *recipe.Recipe) bool {
+// recipeQuery is a recipe's query field, parsed once per filtering pass.
+type recipeQuery struct {
+	q   *query.Query
+	ctx *query.Context
+}
+
+// newRecipeQuery prepares r's query for evaluation against issues. Recipes
+// with invalid queries are rejected when loaded, so a parse error here only
+// means the query is ignored.
+func newRecipeQuery(r *recipe.Recipe, issues []model.Issue, stats *analysis.GraphStats) recipeQuery {
+	if r == nil {
+		return recipeQuery{}
+	}
+	q, err := r.ParsedQuery()
+	if err != nil || q == nil {
+		return recipeQuery{}
+	}
+	return recipeQuery{q: q, ctx: query.NewContext(issues, stats)}
+}
+
+// newLiveRecipeQuery is newRecipeQuery for the UI goroutine: metric terms
+// see only the metrics computed so far rather than blocking on phase 2, and
+// Phase2ReadyMsg re-applies the recipe once the rest are in.
+func newLiveRecipeQuery(r *recipe.Recipe, issues []model.Issue, stats *analysis.GraphStats) recipeQuery {
+	rq := newRecipeQuery(r, issues, stats)
+	if rq.ctx != nil {
+		rq.ctx.NoWait = true
+	}
+	return rq
+}
+
+func (rq recipeQuery) match(issue *model.Issue) bool {
+	return rq.q == nil || rq.q.Match(issue, rq.ctx)
+}
+
+func issueMatchesRecipe(issue model.Issue, issueMap map[string]*model.Issue, r *recipe.Recipe, rq recipeQuery) bool {
 	if r == nil {
 		return true
 	}
@@ -692,7 +738,7 @@ func issueMatchesRecipe(issue model.Issue, issueMap map[string]*model.Issue, r *
 		}
 	}
 
-	return true
+	return rq.match(&issue)
 }
 
 func sortIssuesByRecipe(issues []model.Issue, stats *analysis.GraphStats, r *recipe.Recipe) {
@@ -822,6 +868,9 @@ func buildGraphLayout(issues []model.Issue, stats *analysis.GraphStats) *GraphLa
 		layout.RankCriticalPath = stats.CriticalPathRank()
 	}
 
+	communities := analysis.DetectCommunities(issues, analysis.CommunityOptions{IncludeClosed: true})
+	layout.Communities = &communities
+
 	layout.SortedIDs = orderIssueIDsByRank(ids, layout.RankCriticalPath)
 	return layout
 }
@@ -10,6 +10,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
 	"github.com/charmbracelet/bubbles/viewport"
 	"github.com/charmbracelet/lipgloss"
@@ -176,16 +177,17 @@ type IssueTreeNode struct {
 
 // TreeModel manages the hierarchical tree view state
 type TreeModel struct {
-	roots          []*IssueTreeNode          // Root nodes (issues with no parent)
-	flatList       []*IssueTreeNode          // Flattened visible nodes for navigation
-	cursor         int                       // Current selection index in flatList
-	viewport       viewport.Model            // For scrolling
-	theme          Theme                     // Visual styling
-	mode           TreeViewMode              // Hierarchy vs blocking
-	issueMap       map[string]*IssueTreeNode // Quick lookup by issue ID
-	width          int                       // Available width
-	height         int                       // Available height
-	viewportOffset int                       // Index of first visible node (bv-r4ng)
+	roots          []*IssueTreeNode               // Root nodes (issues with no parent)
+	flatList       []*IssueTreeNode               // Flattened visible nodes for navigation
+	cursor         int                            // Current selection index in flatList
+	viewport       viewport.Model                 // For scrolling
+	theme          Theme                          // Visual styling
+	mode           TreeViewMode                   // Hierarchy vs blocking
+	issueMap       map[string]*IssueTreeNode      // Quick lookup by issue ID
+	rollups        map[string]analysis.EpicRollup // Progress of each parent's descendants
+	width          int                            // Available width
+	height         int                            // Available height
+	viewportOffset int                            // Index of first visible node (bv-r4ng)
 
 	// Build state
 	built    bool   // Has tree been built?
@@ -302,6 +304,7 @@ func (t *TreeModel) Build(issues []model.Issue) {
 	roots, nodeMap := buildIssueTreeNodes(issues)
 	t.roots = roots
 	t.issueMap = nodeMap
+	t.rollups = analysis.ComputeEpicRollups(issues).ByID()
 
 	// Step 5: Handle empty tree (no parent-child relationships found)
 	// If all issues are roots (no hierarchy), that's fine - show them all
@@ -340,6 +343,7 @@ func (t *TreeModel) BuildFromSnapshot(snapshot *DataSnapshot) {
 	// Reset view state, but keep dimensions/theme/beadsDir.
 	t.roots = snapshot.TreeRoots
 	t.issueMap = snapshot.TreeNodeMap
+	t.rollups = snapshot.EpicRollups
 
 	// If the snapshot didn't include tree data, fall back to building it now.
 	if len(t.roots) == 0 || t.issueMap == nil {
@@ -348,6 +352,10 @@ func (t *TreeModel) BuildFromSnapshot(snapshot *DataSnapshot) {
 		return
 	}
 
+	if t.rollups == nil {
+		t.rollups = analysis.ComputeEpicRollups(snapshot.Issues).ByID()
+	}
+
 	// Apply persisted expand/collapse state and rebuild visible list.
 	t.loadState()
 	t.rebuildFlatList()
@@ -599,6 +607,8 @@ func (t *TreeModel) renderNode(node *IssueTreeNode, isSelected bool) string {
 	title := issue.Title
 	// Use lipgloss.Width for proper display width (handles ANSI codes + Unicode)
 	maxTitleLen := t.width - lipgloss.Width(prefix) - 25 // Account for prefix, indicator, icon, priority, ID
+	rollup := t.renderRollup(node)
+	maxTitleLen -= lipgloss.Width(rollup)
 	if maxTitleLen < 20 {
 		maxTitleLen = 20
 	}
@@ -606,6 +616,7 @@ func (t *TreeModel) renderNode(node *IssueTreeNode, isSelected bool) string {
 
 	// Title uses base style foreground
 	sb.WriteString(title)
+	sb.WriteString(rollup)
 
 	// Status indicator (colored dot at end)
 	statusColor := t.theme.GetStatusColor(string(issue.Status))
@@ -616,6 +627,35 @@ func (t *TreeModel) renderNode(node *IssueTreeNode, isSelected bool) string {
 	return sb.String()
 }
 
+// renderRollup renders a parent's progress as " [████░░░░] 3/8", followed
+// by a zombie marker when the parent is open with every child closed.
+// Leaves render nothing.
+func (t *TreeModel) renderRollup(node *IssueTreeNode) string {
+	rollup, ok := t.rollups[node.Issue.ID]
+	if !ok || rollup.Descendants == 0 {
+		return ""
+	}
+	r := t.theme.Renderer
+	const barWidth = 8
+	filled := rollup.Closed * barWidth / rollup.Descendants
+	if filled == 0 && rollup.Closed > 0 {
+		filled = 1
+	}
+
+	var sb strings.Builder
+	sb.WriteString(" ")
+	sb.WriteString(r.NewStyle().Foreground(t.theme.Muted).Render("["))
+	sb.WriteString(r.NewStyle().Foreground(t.theme.Closed).Render(strings.Repeat("█", filled)))
+	sb.WriteString(r.NewStyle().Foreground(t.theme.Muted).Render(strings.Repeat("░", barWidth-filled) + "]"))
+	sb.WriteString(r.NewStyle().Foreground(t.theme.Secondary).Render(fmt.Sprintf(" %d/%d", rollup.Closed, rollup.Descendants)))
+	if rollup.HasFlag(analysis.RollupFlagZombie) {
+		sb.WriteString(r.NewStyle().Foreground(t.theme.Blocked).Bold(true).Render(" zombie"))
+	} else if rollup.HasFlag(analysis.RollupFlagOrphanProgress) {
+		sb.WriteString(r.NewStyle().Foreground(t.theme.Blocked).Render(" deferred"))
+	}
+	return sb.String()
+}
+
 // buildTreePrefix builds the indentation and branch characters for a node.
 func (t *TreeModel) buildTreePrefix(node *IssueTreeNode) string {
 	if node.Depth == 0 {
@@ -1586,3 +1586,45 @@ func TestPositionIndicatorAtEnd(t *testing.T) {
 		t.Errorf("position indicator at end not found, got:\n%s", output)
 	}
 }
+
+// TestTreeViewRollupProgress verifies parents show a progress bar and zombie marker
+func TestTreeViewRollupProgress(t *testing.T) {
+	now := time.Now()
+	child := func(id, parent string, status model.Status) model.Issue {
+		return model.Issue{ID: id, Title: "Task " + id, Status: status, IssueType: model.TypeTask, CreatedAt: now,
+			Dependencies: []*model.Dependency{{IssueID: id, DependsOnID: parent, Type: model.DepParentChild}}}
+	}
+	issues := []model.Issue{
+		{ID: "epic-1", Title: "Launch", Priority: 1, IssueType: model.TypeEpic, Status: model.StatusOpen, CreatedAt: now},
+		child("task-1", "epic-1", model.StatusClosed),
+		child("task-2", "epic-1", model.StatusOpen),
+		child("task-3", "epic-1", model.StatusOpen),
+		child("task-4", "epic-1", model.StatusOpen),
+		{ID: "epic-2", Title: "Finished", Priority: 2, IssueType: model.TypeEpic, Status: model.StatusOpen, CreatedAt: now},
+		child("task-5", "epic-2", model.StatusClosed),
+	}
+
+	tree := NewTreeModel(newTreeTestTheme())
+	tree.Build(issues)
+	tree.SetSize(120, 30)
+
+	lines := strings.Split(tree.View(), "\n")
+	lineFor := func(id string) string {
+		for _, l := range lines {
+			if strings.Contains(l, id+" ") {
+				return l
+			}
+		}
+		t.Fatalf("%s not rendered:\n%s", id, strings.Join(lines, "\n"))
+		return ""
+	}
+	if l := lineFor("epic-1"); !strings.Contains(l, "[██░░░░░░] 1/4") || strings.Contains(l, "zombie") {
+		t.Errorf("epic-1 progress = %q", l)
+	}
+	if l := lineFor("epic-2"); !strings.Contains(l, "[████████] 1/1 zombie") {
+		t.Errorf("epic-2 should be a zombie: %q", l)
+	}
+	if l := lineFor("task-2"); strings.Contains(l, "[") {
+		t.Errorf("leaves have no progress bar: %q", l)
+	}
+}
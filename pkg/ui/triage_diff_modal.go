@@ -115,6 +115,9 @@ func (m TriageDiffModal) View() string {
 	if n := counts[triage.ChangePriority]; n > 0 {
 		typeParts = append(typeParts, fmt.Sprintf("%d priority", n))
 	}
+	if n := counts[triage.ChangeInherit]; n > 0 {
+		typeParts = append(typeParts, fmt.Sprintf("%d inherited", n))
+	}
 	if n := counts[triage.ChangeStatus]; n > 0 {
 		typeParts = append(typeParts, fmt.Sprintf("%d status", n))
 	}
@@ -193,6 +196,9 @@ func (m TriageDiffModal) changeTypeBadge(r *lipgloss.Renderer, ct triage.ChangeT
 	case triage.ChangePriority:
 		label = "PRI"
 		fg = m.theme.Open
+	case triage.ChangeInherit:
+		label = "INH"
+		fg = m.theme.Blocked
 	case triage.ChangeStatus:
 		label = "STS"
 		fg = m.theme.InProgress
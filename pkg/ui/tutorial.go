@@ -1018,6 +1018,7 @@ func defaultTutorialPages() []TutorialPage {
 | **Ctrl+S** | Semantic search |
 | **H** | Hybrid ranking |
 | **Alt+H** | Hybrid preset |
+| **Alt+S** | Search kinds |
 | **o/c/r/a** | Status filter |
 
 > Press **?** in any view for context help.`,
@@ -1535,6 +1536,7 @@ Quickly narrow down what you see:
 | **Ctrl+S** | Semantic search (meaning-based) |
 | **H** | Hybrid ranking (semantic + graph) |
 | **Alt+H** | Cycle hybrid preset |
+| **Alt+S** | Also search comments, commits, sessions |
 | **n/N** | Next/previous search result |
 
 ### Sorting
@@ -1812,6 +1814,7 @@ impact in the dependency graph.
 | Semantic | **Ctrl+S** | Meaning-based retrieval |
 | Hybrid | **H** | Semantic + graph-aware ranking |
 | Preset | **Alt+H** | Cycle hybrid presets |
+| Kinds | **Alt+S** | Add comments, commits and cass sessions |
 
 ### How It Works
 
@@ -1821,6 +1824,9 @@ impact in the dependency graph.
    impact, priority, and recency—so the most important matches rise.
 3. **Short queries** (e.g. "benchmarks") get a literal-match boost and a
    wider candidate pool for precise, fast lookups.
+4. **Search kinds** (Alt+S) also match comments, correlated commit messages
+   and cass sessions; each issue ranks by its best match, and the detail
+   pane lists what matched.
 
 ### Example
 
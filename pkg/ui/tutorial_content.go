@@ -354,6 +354,7 @@ func structuredTutorialPages() []StructuredTutorialPage {
 					{Key: "Ctrl+S", Desc: "Semantic search (vector index)"},
 					{Key: "H", Desc: "Hybrid ranking (semantic)"},
 					{Key: "Alt+H", Desc: "Cycle hybrid preset"},
+					{Key: "Alt+S", Desc: "Cycle search kinds"},
 					{Key: "n / N", Desc: "Next / prev result"},
 				}},
 				Spacer{Lines: 1},
@@ -463,7 +464,7 @@ func structuredTutorialPages() []StructuredTutorialPage {
 				Bullet{Items: []string{
 					"Arrows point TO what's blocked (A->B = A blocks B)",
 					"Node size reflects priority",
-					"Color indicates status",
+					"Color indicates status (C switches to community)",
 					"Highlighted node is your selection",
 				}},
 				Spacer{Lines: 1},
@@ -472,6 +473,7 @@ func structuredTutorialPages() []StructuredTutorialPage {
 					{Key: "j / k", Desc: "Navigate between nodes"},
 					{Key: "h / l", Desc: "Navigate siblings"},
 					{Key: "f", Desc: "Focus on subgraph"},
+					{Key: "C", Desc: "Toggle community colors"},
 					{Key: "Enter", Desc: "View selected issue"},
 				}},
 				Spacer{Lines: 1},
@@ -561,6 +563,7 @@ func structuredTutorialPages() []StructuredTutorialPage {
 					{Key: "Ctrl+S", Desc: "Semantic search (meaning)"},
 					{Key: "H", Desc: "Hybrid ranking (meaning + graph)"},
 					{Key: "Alt+H", Desc: "Cycle hybrid preset"},
+					{Key: "Alt+S", Desc: "Cycle search kinds"},
 				}},
 				Spacer{Lines: 1},
 				Section{Title: "Example"},
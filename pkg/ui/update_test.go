@@ -71,6 +71,7 @@ func TestInsightsCurrentPanelItemCount(t *testing.T) {
 		Authorities:  []analysis.InsightItem{{ID: "A"}},
 		Cores:        []analysis.InsightItem{{ID: "C"}},
 		Articulation: []string{"ART"},
+		MustPass:     []analysis.InsightItem{{ID: "M"}},
 		Slack:        []analysis.InsightItem{{ID: "S"}},
 		Cycles:       [][]string{{"X", "Y"}},
 		Stats:        analysis.NewGraphStatsForTest(nil, nil, nil, nil, nil, nil, nil, nil, nil, 0, nil),
@@ -0,0 +1,165 @@
+package writeback
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/workspace"
+)
+
+// DoltStore issues UPDATEs against the Dolt SQL server bv loaded from with
+// --dolt. Issue IDs are routed to their database by the namespace prefix the
+// workspace loader applied (see workspace.ConfigFromDatabases). Changes land
+// in the working set; committing them is left to bd, as with any other write.
+type DoltStore struct {
+	config loader.DoltConfig
+	repos  []workspace.RepoConfig // sorted longest prefix first
+}
+
+// NewDoltStore returns a store for the given databases on the Dolt server.
+func NewDoltStore(config loader.DoltConfig, databases []string) *DoltStore {
+	repos := workspace.ConfigFromDatabases(databases).Repos
+	sort.SliceStable(repos, func(i, j int) bool {
+		return len(repos[i].GetPrefix()) > len(repos[j].GetPrefix())
+	})
+	return &DoltStore{config: config, repos: repos}
+}
+
+// Describe implements Store.
+func (s *DoltStore) Describe() string {
+	return fmt.Sprintf("dolt %s:%d", s.config.Host, s.config.Port)
+}
+
+// route returns the database holding issueID and the candidate row IDs to
+// try: the ID as displayed first (bd IDs usually already carry the database
+// prefix), then with the namespace prefix removed.
+func (s *DoltStore) route(issueID string) (string, []string, error) {
+	for _, repo := range s.repos {
+		prefix := repo.GetPrefix()
+		if strings.HasPrefix(issueID, prefix) {
+			return repo.DoltDatabase, []string{issueID, workspace.UnqualifyID(issueID, prefix)}, nil
+		}
+	}
+	if len(s.repos) == 1 {
+		return s.repos[0].DoltDatabase, []string{issueID}, nil
+	}
+	return "", nil, fmt.Errorf("no dolt database for %s", issueID)
+}
+
+// Apply implements Store.
+func (s *DoltStore) Apply(ctx context.Context, e Edit) (Edit, error) {
+	database, candidates, err := s.route(e.IssueID)
+	if err != nil {
+		return e, err
+	}
+
+	db, err := sql.Open("mysql", s.config.DSN(database))
+	if err != nil {
+		return e, fmt.Errorf("connecting to dolt %s: %w", database, err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	db.SetConnMaxLifetime(30 * time.Second)
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return e, fmt.Errorf("begin transaction on %s: %w", database, err)
+	}
+	defer tx.Rollback()
+
+	rowID := ""
+	for _, id := range candidates {
+		var n int
+		if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM issues WHERE id = ?", id).Scan(&n); err != nil {
+			return e, fmt.Errorf("lookup %s in %s: %w", id, database, err)
+		}
+		if n > 0 {
+			rowID = id
+			break
+		}
+	}
+	if rowID == "" {
+		return e, fmt.Errorf("issue %s not found in dolt database %s", e.IssueID, database)
+	}
+
+	switch e.Op {
+	case OpStatus:
+		_, err = tx.ExecContext(ctx, "UPDATE issues SET status = ?, closed_at = ? WHERE id = ?", e.Value, e.ClosedAt, rowID)
+	case OpPriority:
+		_, err = tx.ExecContext(ctx, "UPDATE issues SET priority = ? WHERE id = ?", e.Priority, rowID)
+	case OpType:
+		_, err = tx.ExecContext(ctx, "UPDATE issues SET issue_type = ? WHERE id = ?", e.Value, rowID)
+	case OpAssignee:
+		_, err = tx.ExecContext(ctx, "UPDATE issues SET assignee = ? WHERE id = ?", e.Value, rowID)
+	case OpAddLabel:
+		_, err = tx.ExecContext(ctx, "INSERT IGNORE INTO labels (issue_id, label) VALUES (?, ?)", rowID, e.Value)
+	case OpRemoveLabel:
+		_, err = tx.ExecContext(ctx, "DELETE FROM labels WHERE issue_id = ? AND label = ?", rowID, e.Value)
+	case OpAddComment:
+		if e.Comment == nil {
+			return e, fmt.Errorf("comment edit without comment")
+		}
+		c := *e.Comment
+		var res sql.Result
+		if c.ID != 0 {
+			res, err = tx.ExecContext(ctx, "INSERT INTO comments (id, issue_id, author, text, created_at) VALUES (?, ?, ?, ?, ?)",
+				c.ID, rowID, c.Author, c.Text, c.CreatedAt)
+		} else {
+			res, err = tx.ExecContext(ctx, "INSERT INTO comments (issue_id, author, text, created_at) VALUES (?, ?, ?, ?)",
+				rowID, c.Author, c.Text, c.CreatedAt)
+		}
+		if err == nil && c.ID == 0 {
+			c.ID, err = res.LastInsertId()
+		}
+		e.Comment = &c
+	case OpRemoveComment:
+		if e.Comment == nil {
+			return e, fmt.Errorf("comment edit without comment")
+		}
+		_, err = tx.ExecContext(ctx, "DELETE FROM comments WHERE id = ? AND issue_id = ?", e.Comment.ID, rowID)
+	case OpAddDependency, OpRemoveDependency:
+		if e.Dependency == nil {
+			return e, fmt.Errorf("dependency edit without dependency")
+		}
+		// Strip the namespace prefix from the target the same way the row ID
+		// was resolved.
+		target := e.Dependency.DependsOnID
+		if rowID != e.IssueID {
+			target = workspace.UnqualifyID(target, strings.TrimSuffix(e.IssueID, rowID))
+		}
+		if e.Op == OpAddDependency {
+			_, err = tx.ExecContext(ctx, "INSERT IGNORE INTO dependencies (issue_id, depends_on_id, type, created_at, created_by) VALUES (?, ?, ?, ?, ?)",
+				rowID, target, dependencyTypeName(e.Dependency.Type), e.Dependency.CreatedAt, e.Dependency.CreatedBy)
+		} else {
+			// Legacy rows may store blocking deps with an empty or NULL type;
+			// match them the same way the SQLite store does.
+			query := "DELETE FROM dependencies WHERE issue_id = ? AND depends_on_id = ? AND type = ?"
+			if e.Dependency.Type.IsBlocking() {
+				query = "DELETE FROM dependencies WHERE issue_id = ? AND depends_on_id = ? AND (type = ? OR type = '' OR type IS NULL)"
+			}
+			_, err = tx.ExecContext(ctx, query, rowID, target, dependencyTypeName(e.Dependency.Type))
+		}
+	default:
+		return e, fmt.Errorf("unsupported edit op %q", e.Op)
+	}
+	if err != nil {
+		return e, fmt.Errorf("update %s in %s: %w", rowID, database, err)
+	}
+
+	if !e.At.IsZero() {
+		if _, err := tx.ExecContext(ctx, "UPDATE issues SET updated_at = ? WHERE id = ?", e.At, rowID); err != nil {
+			return e, fmt.Errorf("update %s in %s: %w", rowID, database, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return e, fmt.Errorf("commit on %s: %w", database, err)
+	}
+	return e, nil
+}
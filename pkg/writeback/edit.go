@@ -0,0 +1,418 @@
+// Package writeback persists interactive issue edits made in the TUI back to
+// the active beads data source.
+//
+// An Edit is a single field-level change (status, priority, type, assignee,
+// label, comment or dependency) that carries enough of the previous state to be inverted, which is
+// what the session undo History relies on. Stores apply edits atomically to a
+// JSONL file, a beads SQLite database or a Dolt SQL server.
+package writeback
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// Op identifies the kind of change an Edit makes.
+type Op string
+
+const (
+	OpStatus           Op = "status"
+	OpPriority         Op = "priority"
+	OpType             Op = "type"
+	OpAssignee         Op = "assignee"
+	OpAddLabel         Op = "label_add"
+	OpRemoveLabel      Op = "label_remove"
+	OpAddComment       Op = "comment_add"
+	OpRemoveComment    Op = "comment_remove" // only produced by inverting OpAddComment
+	OpAddDependency    Op = "dependency_add"
+	OpRemoveDependency Op = "dependency_remove"
+)
+
+// ErrNoChange is returned by Prepare when an edit would leave the issue as-is.
+var ErrNoChange = errors.New("no change")
+
+// StatusCycle is the order the TUI steps through when cycling status.
+var StatusCycle = []model.Status{
+	model.StatusOpen,
+	model.StatusInProgress,
+	model.StatusBlocked,
+	model.StatusDeferred,
+	model.StatusClosed,
+}
+
+// NextStatus returns the status after s in StatusCycle. Statuses outside the
+// cycle (review, pinned, hooked, ...) move back to open.
+func NextStatus(s model.Status) model.Status {
+	for i, st := range StatusCycle {
+		if st == s {
+			return StatusCycle[(i+1)%len(StatusCycle)]
+		}
+	}
+	return StatusCycle[0]
+}
+
+// Edit is a single change to one issue.
+//
+// Value/Prev hold the new and previous status, type or assignee, or the label
+// for label ops. Priority ops use Priority/PrevPriority. Status ops also carry the
+// closed_at they write so undoing a close (or a reopen) restores it exactly.
+type Edit struct {
+	IssueID string
+	Op      Op
+
+	Value string
+	Prev  string
+
+	Priority     int
+	PrevPriority int
+
+	ClosedAt     *time.Time
+	PrevClosedAt *time.Time
+
+	// Comment is the comment being added or removed. Stores fill in the ID
+	// they assigned when adding.
+	Comment *model.Comment
+
+	// Dependency is the dependency being added or removed; IssueID is the
+	// dependent side.
+	Dependency *model.Dependency
+
+	// At is when the edit was made; stores write it to updated_at.
+	At time.Time
+}
+
+// SetStatus returns an edit changing the status of an issue.
+func SetStatus(issueID string, status model.Status) Edit {
+	return Edit{IssueID: issueID, Op: OpStatus, Value: string(status)}
+}
+
+// SetPriority returns an edit changing the priority of an issue.
+func SetPriority(issueID string, priority int) Edit {
+	return Edit{IssueID: issueID, Op: OpPriority, Priority: priority}
+}
+
+// SetType returns an edit changing the issue type.
+func SetType(issueID string, issueType model.IssueType) Edit {
+	return Edit{IssueID: issueID, Op: OpType, Value: strings.TrimSpace(string(issueType))}
+}
+
+// SetAssignee returns an edit changing the assignee; empty unassigns.
+func SetAssignee(issueID, assignee string) Edit {
+	return Edit{IssueID: issueID, Op: OpAssignee, Value: strings.TrimSpace(assignee)}
+}
+
+// AddLabel returns an edit adding a label.
+func AddLabel(issueID, label string) Edit {
+	return Edit{IssueID: issueID, Op: OpAddLabel, Value: strings.TrimSpace(label)}
+}
+
+// RemoveLabel returns an edit removing a label.
+func RemoveLabel(issueID, label string) Edit {
+	return Edit{IssueID: issueID, Op: OpRemoveLabel, Value: strings.TrimSpace(label)}
+}
+
+// AddComment returns an edit appending a comment.
+func AddComment(issueID, author, text string) Edit {
+	return Edit{
+		IssueID: issueID,
+		Op:      OpAddComment,
+		Comment: &model.Comment{IssueID: issueID, Author: author, Text: strings.TrimSpace(text)},
+	}
+}
+
+// AddDependency returns an edit making issueID depend on dependsOnID.
+func AddDependency(issueID, dependsOnID string, depType model.DependencyType, author string) Edit {
+	return Edit{
+		IssueID:    issueID,
+		Op:         OpAddDependency,
+		Dependency: &model.Dependency{IssueID: issueID, DependsOnID: dependsOnID, Type: depType, CreatedBy: author},
+	}
+}
+
+// RemoveDependency returns an edit removing the dependency of issueID on
+// dependsOnID with the given type.
+func RemoveDependency(issueID, dependsOnID string, depType model.DependencyType) Edit {
+	return Edit{
+		IssueID:    issueID,
+		Op:         OpRemoveDependency,
+		Dependency: &model.Dependency{IssueID: issueID, DependsOnID: dependsOnID, Type: depType},
+	}
+}
+
+// SameDependency reports whether a and b link the same issues with the same
+// type. A missing type counts as blocks, as bd writes it.
+func SameDependency(a, b *model.Dependency) bool {
+	if a == nil || b == nil || a.DependsOnID != b.DependsOnID {
+		return false
+	}
+	if a.Type.IsBlocking() || b.Type.IsBlocking() {
+		return a.Type.IsBlocking() && b.Type.IsBlocking()
+	}
+	return a.Type == b.Type
+}
+
+// findDependency returns the dependency of issue matching dep, or nil.
+func findDependency(issue model.Issue, dep *model.Dependency) *model.Dependency {
+	for _, d := range issue.Dependencies {
+		if SameDependency(d, dep) {
+			return d
+		}
+	}
+	return nil
+}
+
+// Prepare validates e against the current state of issue, records the
+// previous values needed for undo and stamps the edit time.
+func (e Edit) Prepare(issue model.Issue, now time.Time) (Edit, error) {
+	if issue.ID != e.IssueID {
+		return e, fmt.Errorf("edit for %s applied to %s", e.IssueID, issue.ID)
+	}
+	e.At = now
+
+	switch e.Op {
+	case OpStatus:
+		status := model.Status(e.Value)
+		if !status.IsValid() || status.IsTombstone() {
+			return e, fmt.Errorf("invalid status %q", e.Value)
+		}
+		if status == issue.Status {
+			return e, ErrNoChange
+		}
+		e.Prev = string(issue.Status)
+		e.PrevClosedAt = copyTime(issue.ClosedAt)
+		switch {
+		case !status.IsClosed():
+			e.ClosedAt = nil
+		case issue.ClosedAt != nil:
+			e.ClosedAt = copyTime(issue.ClosedAt)
+		default:
+			e.ClosedAt = copyTime(&now)
+		}
+
+	case OpPriority:
+		if e.Priority < 0 || e.Priority > 4 {
+			return e, fmt.Errorf("priority must be 0-4, got %d", e.Priority)
+		}
+		if e.Priority == issue.Priority {
+			return e, ErrNoChange
+		}
+		e.PrevPriority = issue.Priority
+
+	case OpType:
+		if !model.IssueType(e.Value).IsValid() {
+			return e, fmt.Errorf("invalid issue type %q", e.Value)
+		}
+		if model.IssueType(e.Value) == issue.IssueType {
+			return e, ErrNoChange
+		}
+		e.Prev = string(issue.IssueType)
+
+	case OpAssignee:
+		if e.Value == issue.Assignee {
+			return e, ErrNoChange
+		}
+		e.Prev = issue.Assignee
+
+	case OpAddLabel, OpRemoveLabel:
+		if e.Value == "" || strings.ContainsAny(e.Value, ", \t\n") {
+			return e, fmt.Errorf("invalid label %q", e.Value)
+		}
+		if hasLabel(issue.Labels, e.Value) == (e.Op == OpAddLabel) {
+			return e, ErrNoChange
+		}
+
+	case OpAddComment:
+		if e.Comment == nil || e.Comment.Text == "" {
+			return e, fmt.Errorf("comment text is empty")
+		}
+		c := *e.Comment
+		c.IssueID = issue.ID
+		c.CreatedAt = now
+		e.Comment = &c
+
+	case OpAddDependency, OpRemoveDependency:
+		if e.Dependency == nil || e.Dependency.DependsOnID == "" {
+			return e, fmt.Errorf("dependency target is empty")
+		}
+		if e.Dependency.DependsOnID == issue.ID {
+			return e, fmt.Errorf("%s cannot depend on itself", issue.ID)
+		}
+		if !e.Dependency.Type.IsValid() {
+			return e, fmt.Errorf("invalid dependency type %q", e.Dependency.Type)
+		}
+		existing := findDependency(issue, e.Dependency)
+		if (existing != nil) == (e.Op == OpAddDependency) {
+			return e, ErrNoChange
+		}
+		d := *e.Dependency
+		d.IssueID = issue.ID
+		if existing != nil {
+			// Keep what is stored so undoing the removal restores it as-is
+			d.Type, d.CreatedAt, d.CreatedBy = existing.Type, existing.CreatedAt, existing.CreatedBy
+		} else {
+			d.CreatedAt = now
+		}
+		e.Dependency = &d
+
+	default:
+		return e, fmt.Errorf("unsupported edit op %q", e.Op)
+	}
+	return e, nil
+}
+
+// Inverse returns the edit that undoes e.
+func (e Edit) Inverse() Edit {
+	inv := e
+	switch e.Op {
+	case OpStatus, OpType, OpAssignee:
+		inv.Value, inv.Prev = e.Prev, e.Value
+		inv.ClosedAt, inv.PrevClosedAt = e.PrevClosedAt, e.ClosedAt
+	case OpPriority:
+		inv.Priority, inv.PrevPriority = e.PrevPriority, e.Priority
+	case OpAddLabel:
+		inv.Op = OpRemoveLabel
+	case OpRemoveLabel:
+		inv.Op = OpAddLabel
+	case OpAddComment:
+		inv.Op = OpRemoveComment
+	case OpRemoveComment:
+		inv.Op = OpAddComment
+	case OpAddDependency:
+		inv.Op = OpRemoveDependency
+	case OpRemoveDependency:
+		inv.Op = OpAddDependency
+	}
+	return inv
+}
+
+// String describes the edit for status lines, e.g. "bv-12 status open → closed".
+func (e Edit) String() string {
+	switch e.Op {
+	case OpStatus:
+		return fmt.Sprintf("%s status %s → %s", e.IssueID, e.Prev, e.Value)
+	case OpPriority:
+		return fmt.Sprintf("%s priority P%d → P%d", e.IssueID, e.PrevPriority, e.Priority)
+	case OpType:
+		return fmt.Sprintf("%s type %s → %s", e.IssueID, e.Prev, e.Value)
+	case OpAssignee:
+		if e.Value == "" {
+			return fmt.Sprintf("%s unassigned", e.IssueID)
+		}
+		return fmt.Sprintf("%s assigned to @%s", e.IssueID, e.Value)
+	case OpAddLabel:
+		return fmt.Sprintf("%s +label %s", e.IssueID, e.Value)
+	case OpRemoveLabel:
+		return fmt.Sprintf("%s -label %s", e.IssueID, e.Value)
+	case OpAddComment:
+		return fmt.Sprintf("%s comment added", e.IssueID)
+	case OpRemoveComment:
+		return fmt.Sprintf("%s comment removed", e.IssueID)
+	case OpAddDependency, OpRemoveDependency:
+		if e.Dependency == nil {
+			break
+		}
+		verb := "+dep"
+		if e.Op == OpRemoveDependency {
+			verb = "-dep"
+		}
+		return fmt.Sprintf("%s %s %s (%s)", e.IssueID, verb, e.Dependency.DependsOnID, dependencyTypeName(e.Dependency.Type))
+	}
+	return fmt.Sprintf("%s %s", e.IssueID, e.Op)
+}
+
+// Apply mutates issue in memory the same way a Store persists e. Slices are
+// copied before modification so callers holding a shallow copy of the issue
+// are not affected.
+func Apply(issue *model.Issue, e Edit) {
+	switch e.Op {
+	case OpStatus:
+		issue.Status = model.Status(e.Value)
+		issue.ClosedAt = copyTime(e.ClosedAt)
+	case OpPriority:
+		issue.Priority = e.Priority
+	case OpType:
+		issue.IssueType = model.IssueType(e.Value)
+	case OpAssignee:
+		issue.Assignee = e.Value
+	case OpAddLabel:
+		if !hasLabel(issue.Labels, e.Value) {
+			issue.Labels = append(append([]string(nil), issue.Labels...), e.Value)
+		}
+	case OpRemoveLabel:
+		issue.Labels = withoutLabel(issue.Labels, e.Value)
+	case OpAddComment:
+		if e.Comment != nil {
+			c := *e.Comment
+			issue.Comments = append(append([]*model.Comment(nil), issue.Comments...), &c)
+		}
+	case OpRemoveComment:
+		if e.Comment != nil {
+			kept := make([]*model.Comment, 0, len(issue.Comments))
+			for _, c := range issue.Comments {
+				if c != nil && c.ID == e.Comment.ID {
+					continue
+				}
+				kept = append(kept, c)
+			}
+			issue.Comments = kept
+		}
+	case OpAddDependency:
+		if e.Dependency != nil && findDependency(*issue, e.Dependency) == nil {
+			d := *e.Dependency
+			issue.Dependencies = append(append([]*model.Dependency(nil), issue.Dependencies...), &d)
+		}
+	case OpRemoveDependency:
+		if e.Dependency != nil {
+			kept := make([]*model.Dependency, 0, len(issue.Dependencies))
+			for _, d := range issue.Dependencies {
+				if SameDependency(d, e.Dependency) {
+					continue
+				}
+				kept = append(kept, d)
+			}
+			issue.Dependencies = kept
+		}
+	}
+	if !e.At.IsZero() {
+		issue.UpdatedAt = e.At
+	}
+}
+
+func hasLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+func withoutLabel(labels []string, label string) []string {
+	var kept []string
+	for _, l := range labels {
+		if l != label {
+			kept = append(kept, l)
+		}
+	}
+	return kept
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	v := *t
+	return &v
+}
+
+// dependencyTypeName returns the type as bd writes it, with empty as blocks.
+func dependencyTypeName(t model.DependencyType) string {
+	if t == "" {
+		return string(model.DepBlocks)
+	}
+	return string(t)
+}
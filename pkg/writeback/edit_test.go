@@ -0,0 +1,198 @@
+package writeback
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestPrepareAndInverseRoundTrip(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	base := model.Issue{
+		ID:        "bv-1",
+		Status:    model.StatusOpen,
+		Priority:  2,
+		IssueType: model.TypeTask,
+		Assignee:  "alice",
+		Labels:    []string{"api"},
+		Dependencies: []*model.Dependency{
+			{IssueID: "bv-1", DependsOnID: "bv-0", Type: model.DepBlocks},
+		},
+	}
+
+	edits := []Edit{
+		SetStatus("bv-1", model.StatusClosed),
+		SetPriority("bv-1", 0),
+		SetType("bv-1", model.TypeBug),
+		SetAssignee("bv-1", ""),
+		AddLabel("bv-1", "urgent"),
+		RemoveLabel("bv-1", "api"),
+		AddComment("bv-1", "bob", "  looks good  "),
+		AddDependency("bv-1", "bv-9", model.DepRelated, "bob"),
+		RemoveDependency("bv-1", "bv-0", model.DepBlocks),
+	}
+	for _, edit := range edits {
+		t.Run(string(edit.Op), func(t *testing.T) {
+			issue := base.Clone()
+			prepared, err := edit.Prepare(issue, now)
+			if err != nil {
+				t.Fatalf("Prepare: %v", err)
+			}
+			if prepared.Op == OpAddComment {
+				prepared.Comment.ID = 7 // as a store would assign
+			}
+			Apply(&issue, prepared)
+			if !issue.UpdatedAt.Equal(now) {
+				t.Fatalf("updated_at = %v, want %v", issue.UpdatedAt, now)
+			}
+
+			Apply(&issue, prepared.Inverse())
+			if issue.Status != base.Status || issue.Priority != base.Priority || issue.IssueType != base.IssueType || issue.Assignee != base.Assignee {
+				t.Fatalf("undo did not restore scalar fields: %+v", issue)
+			}
+			if len(issue.Labels) != 1 || issue.Labels[0] != "api" {
+				t.Fatalf("undo did not restore labels: %v", issue.Labels)
+			}
+			if len(issue.Dependencies) != 1 || issue.Dependencies[0].DependsOnID != "bv-0" {
+				t.Fatalf("undo did not restore dependencies: %+v", issue.Dependencies)
+			}
+			if len(issue.Comments) != 0 || issue.ClosedAt != nil {
+				t.Fatalf("undo left comments=%d closed_at=%v", len(issue.Comments), issue.ClosedAt)
+			}
+			if len(base.Labels) != 1 || base.Labels[0] != "api" {
+				t.Fatalf("Apply mutated the original labels slice: %v", base.Labels)
+			}
+		})
+	}
+}
+
+func TestPrepareStatusClosedAt(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	closedAt := now.Add(-48 * time.Hour)
+	issue := model.Issue{ID: "bv-2", Status: model.StatusClosed, ClosedAt: &closedAt}
+
+	reopen, err := SetStatus("bv-2", model.StatusOpen).Prepare(issue, now)
+	if err != nil {
+		t.Fatalf("Prepare: %v", err)
+	}
+	if reopen.ClosedAt != nil {
+		t.Fatalf("reopen should clear closed_at, got %v", reopen.ClosedAt)
+	}
+	undo := reopen.Inverse()
+	if undo.ClosedAt == nil || !undo.ClosedAt.Equal(closedAt) {
+		t.Fatalf("undoing a reopen should restore the original closed_at, got %v", undo.ClosedAt)
+	}
+}
+
+func TestPrepareRejects(t *testing.T) {
+	now := time.Now()
+	issue := model.Issue{ID: "bv-3", Status: model.StatusOpen, Priority: 1, Labels: []string{"ui"}}
+
+	cases := []struct {
+		name   string
+		edit   Edit
+		noop   bool
+		reason string
+	}{
+		{"same status", SetStatus("bv-3", model.StatusOpen), true, ""},
+		{"bad status", SetStatus("bv-3", "nope"), false, "invalid status"},
+		{"tombstone", SetStatus("bv-3", model.StatusTombstone), false, "invalid status"},
+		{"empty type", SetType("bv-3", ""), false, "invalid issue type"},
+		{"priority range", SetPriority("bv-3", 5), false, "priority"},
+		{"same priority", SetPriority("bv-3", 1), true, ""},
+		{"existing label", AddLabel("bv-3", "ui"), true, ""},
+		{"missing label", RemoveLabel("bv-3", "api"), true, ""},
+		{"label with space", AddLabel("bv-3", "two words"), false, "invalid label"},
+		{"empty comment", AddComment("bv-3", "me", "   "), false, "empty"},
+		{"wrong issue", SetPriority("bv-9", 0), false, "applied to"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.edit.Prepare(issue, now)
+			if err == nil {
+				t.Fatalf("expected error")
+			}
+			if tc.noop != errors.Is(err, ErrNoChange) {
+				t.Fatalf("ErrNoChange = %v, want %v (err: %v)", errors.Is(err, ErrNoChange), tc.noop, err)
+			}
+			if tc.reason != "" && !strings.Contains(err.Error(), tc.reason) {
+				t.Fatalf("error %q does not mention %q", err, tc.reason)
+			}
+		})
+	}
+}
+
+func TestNextStatusCycles(t *testing.T) {
+	s := model.StatusOpen
+	seen := map[model.Status]bool{}
+	for i := 0; i < len(StatusCycle); i++ {
+		seen[s] = true
+		s = NextStatus(s)
+	}
+	if s != model.StatusOpen || len(seen) != len(StatusCycle) {
+		t.Fatalf("cycle did not return to open after %d steps (at %s)", len(StatusCycle), s)
+	}
+	if NextStatus(model.StatusReview) != model.StatusOpen {
+		t.Fatalf("statuses outside the cycle should move to open")
+	}
+}
+
+func TestHistoryPeekBeforePop(t *testing.T) {
+	var h History
+	if _, ok := h.Peek(); ok {
+		t.Fatalf("empty history should not peek")
+	}
+	h.Record(SetPriority("bv-1", 1))
+	h.Record(SetPriority("bv-1", 2))
+	if e, _ := h.Peek(); e.Priority != 2 || h.Len() != 2 {
+		t.Fatalf("Peek should return the latest edit without removing it")
+	}
+	h.Pop()
+	if e, _ := h.Pop(); e.Priority != 1 || h.Len() != 0 {
+		t.Fatalf("Pop order wrong")
+	}
+}
+
+func TestTransitionRulesCheck(t *testing.T) {
+	now := time.Now()
+	issue := model.Issue{ID: "bv-4", Status: model.StatusInProgress, Priority: 1, IssueType: model.TypeTask}
+	closing, err := SetStatus("bv-4", model.StatusClosed).Prepare(issue, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules := DefaultRules()
+	if err := rules.Check(closing, []string{"bv-1", "bv-2"}); !errors.Is(err, ErrTransitionDenied) || !strings.Contains(err.Error(), "bv-1, bv-2") {
+		t.Fatalf("closing with open blockers should be denied, got %v", err)
+	}
+	if err := rules.Check(closing, nil); err != nil {
+		t.Fatalf("closing without blockers should pass: %v", err)
+	}
+
+	rules.Deny = []TransitionRule{
+		{From: "in_progress", To: "closed", Reason: "close from review"},
+		{Field: "priority", To: "P0"},
+	}
+	if err := rules.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := rules.Check(closing, nil); err == nil || !strings.Contains(err.Error(), "close from review") {
+		t.Fatalf("deny rule not applied: %v", err)
+	}
+	toP0, _ := SetPriority("bv-4", 0).Prepare(issue, now)
+	if err := rules.Check(toP0, nil); !errors.Is(err, ErrTransitionDenied) {
+		t.Fatalf("priority deny rule not applied: %v", err)
+	}
+	toBug, _ := SetType("bv-4", model.TypeBug).Prepare(issue, now)
+	if err := rules.Check(toBug, nil); err != nil {
+		t.Fatalf("type move should pass: %v", err)
+	}
+
+	bad := TransitionRules{Deny: []TransitionRule{{Field: "assignee", To: "x"}}}
+	if err := bad.Validate(); err == nil {
+		t.Fatalf("unknown field should fail validation")
+	}
+}
@@ -0,0 +1,35 @@
+package writeback
+
+// History is the session undo stack. Edits are recorded after a store has
+// persisted them and removed only once their inverse has been persisted too,
+// so a failed undo leaves the stack unchanged and can be retried.
+type History struct {
+	edits []Edit
+}
+
+// Record pushes a persisted edit onto the stack.
+func (h *History) Record(e Edit) {
+	h.edits = append(h.edits, e)
+}
+
+// Peek returns the most recent edit without removing it.
+func (h *History) Peek() (Edit, bool) {
+	if len(h.edits) == 0 {
+		return Edit{}, false
+	}
+	return h.edits[len(h.edits)-1], true
+}
+
+// Pop removes and returns the most recent edit.
+func (h *History) Pop() (Edit, bool) {
+	e, ok := h.Peek()
+	if ok {
+		h.edits = h.edits[:len(h.edits)-1]
+	}
+	return e, ok
+}
+
+// Len returns the number of undoable edits.
+func (h *History) Len() int {
+	return len(h.edits)
+}
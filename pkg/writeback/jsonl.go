@@ -0,0 +1,377 @@
+package writeback
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// JSONLStore rewrites a beads JSONL file. Only the edited issue's line is
+// re-encoded, and only the fields the edit touches; every other line and every
+// field bv does not model is written back byte-for-byte. The new content goes
+// to a temp file in the same directory which is then renamed over the
+// original, so readers (bd, the file watcher) never see a partial file.
+type JSONLStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewJSONLStore returns a store writing to the JSONL file at path.
+func NewJSONLStore(path string) *JSONLStore {
+	return &JSONLStore{path: path}
+}
+
+// Describe implements Store.
+func (s *JSONLStore) Describe() string {
+	return filepath.Base(s.path)
+}
+
+// Apply implements Store.
+func (s *JSONLStore) Apply(ctx context.Context, e Edit) (Edit, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return e, fmt.Errorf("read %s: %w", s.Describe(), err)
+	}
+
+	var out bytes.Buffer
+	out.Grow(len(data) + 256)
+	target := -1
+	var lines [][]byte
+	var maxCommentID int64
+
+	r := bufio.NewReader(bytes.NewReader(data))
+	for {
+		line, readErr := r.ReadBytes('\n')
+		if len(line) > 0 {
+			lines = append(lines, line)
+			var probe struct {
+				ID       string `json:"id"`
+				Comments []struct {
+					ID int64 `json:"id"`
+				} `json:"comments"`
+			}
+			if json.Unmarshal(bytes.TrimSpace(line), &probe) == nil {
+				if probe.ID == e.IssueID && target < 0 {
+					target = len(lines) - 1
+				}
+				for _, c := range probe.Comments {
+					if c.ID > maxCommentID {
+						maxCommentID = c.ID
+					}
+				}
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return e, fmt.Errorf("read %s: %w", s.Describe(), readErr)
+		}
+	}
+	if target < 0 {
+		return e, fmt.Errorf("issue %s not found in %s", e.IssueID, s.Describe())
+	}
+	if err := ctx.Err(); err != nil {
+		return e, err
+	}
+
+	if e.Op == OpAddComment && e.Comment != nil && e.Comment.ID == 0 {
+		c := *e.Comment
+		c.ID = maxCommentID + 1
+		e.Comment = &c
+	}
+
+	updated, err := applyToJSONLine(lines[target], e)
+	if err != nil {
+		return e, fmt.Errorf("update %s: %w", e.IssueID, err)
+	}
+	for i, line := range lines {
+		if i == target {
+			out.Write(updated)
+			if bytes.HasSuffix(line, []byte("\n")) {
+				out.WriteByte('\n')
+			}
+			continue
+		}
+		out.Write(line)
+	}
+
+	if err := WriteFileAtomic(s.path, out.Bytes()); err != nil {
+		return e, err
+	}
+	return e, nil
+}
+
+// applyToJSONLine re-encodes one issue object with the edit applied, keeping
+// unknown fields as raw JSON and the original key order.
+func applyToJSONLine(line []byte, e Edit) ([]byte, error) {
+	keys, obj, err := decodeOrderedObject(bytes.TrimSpace(line))
+	if err != nil {
+		return nil, err
+	}
+
+	set := func(key string, v interface{}) error {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if _, ok := obj[key]; !ok {
+			keys = append(keys, key)
+		}
+		obj[key] = raw
+		return nil
+	}
+
+	switch e.Op {
+	case OpStatus:
+		if err := set("status", e.Value); err != nil {
+			return nil, err
+		}
+		if e.ClosedAt != nil {
+			if err := set("closed_at", e.ClosedAt); err != nil {
+				return nil, err
+			}
+		} else {
+			delete(obj, "closed_at")
+		}
+
+	case OpPriority:
+		if err := set("priority", e.Priority); err != nil {
+			return nil, err
+		}
+
+	case OpType:
+		if err := set("issue_type", e.Value); err != nil {
+			return nil, err
+		}
+
+	case OpAssignee:
+		if e.Value == "" {
+			delete(obj, "assignee")
+		} else if err := set("assignee", e.Value); err != nil {
+			return nil, err
+		}
+
+	case OpAddLabel, OpRemoveLabel:
+		var labels []string
+		if raw, ok := obj["labels"]; ok {
+			if err := json.Unmarshal(raw, &labels); err != nil {
+				return nil, fmt.Errorf("labels: %w", err)
+			}
+		}
+		if e.Op == OpAddLabel {
+			if !hasLabel(labels, e.Value) {
+				labels = append(labels, e.Value)
+			}
+		} else {
+			labels = withoutLabel(labels, e.Value)
+		}
+		if len(labels) == 0 {
+			delete(obj, "labels")
+		} else if err := set("labels", labels); err != nil {
+			return nil, err
+		}
+
+	case OpAddComment, OpRemoveComment:
+		if e.Comment == nil {
+			return nil, errors.New("comment edit without comment")
+		}
+		var comments []json.RawMessage
+		if raw, ok := obj["comments"]; ok {
+			if err := json.Unmarshal(raw, &comments); err != nil {
+				return nil, fmt.Errorf("comments: %w", err)
+			}
+		}
+		if e.Op == OpAddComment {
+			raw, err := json.Marshal(e.Comment)
+			if err != nil {
+				return nil, err
+			}
+			comments = append(comments, raw)
+		} else {
+			kept := comments[:0]
+			for _, raw := range comments {
+				var c struct {
+					ID int64 `json:"id"`
+				}
+				if json.Unmarshal(raw, &c) == nil && c.ID == e.Comment.ID {
+					continue
+				}
+				kept = append(kept, raw)
+			}
+			comments = kept
+		}
+		if len(comments) == 0 {
+			delete(obj, "comments")
+		} else if err := set("comments", comments); err != nil {
+			return nil, err
+		}
+
+	case OpAddDependency, OpRemoveDependency:
+		if e.Dependency == nil {
+			return nil, errors.New("dependency edit without dependency")
+		}
+		var deps []json.RawMessage
+		if raw, ok := obj["dependencies"]; ok {
+			if err := json.Unmarshal(raw, &deps); err != nil {
+				return nil, fmt.Errorf("dependencies: %w", err)
+			}
+		}
+		kept := deps[:0]
+		for _, raw := range deps {
+			var d model.Dependency
+			if json.Unmarshal(raw, &d) == nil && SameDependency(&d, e.Dependency) {
+				continue // dropped on removal, re-added below otherwise
+			}
+			kept = append(kept, raw)
+		}
+		deps = kept
+		if e.Op == OpAddDependency {
+			raw, err := json.Marshal(e.Dependency)
+			if err != nil {
+				return nil, err
+			}
+			deps = append(deps, raw)
+		}
+		if len(deps) == 0 {
+			delete(obj, "dependencies")
+		} else if err := set("dependencies", deps); err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported edit op %q", e.Op)
+	}
+
+	if !e.At.IsZero() {
+		if err := set("updated_at", e.At); err != nil {
+			return nil, err
+		}
+	}
+	return encodeOrderedObject(keys, obj)
+}
+
+// decodeOrderedObject splits a JSON object into its keys (in document order)
+// and raw values.
+func decodeOrderedObject(data []byte) ([]string, map[string]json.RawMessage, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, nil, errors.New("issue line is not a JSON object")
+	}
+	var keys []string
+	obj := make(map[string]json.RawMessage)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return nil, nil, fmt.Errorf("unexpected token %v", tok)
+		}
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, nil, err
+		}
+		if _, dup := obj[key]; !dup {
+			keys = append(keys, key)
+		}
+		obj[key] = raw
+	}
+	return keys, obj, nil
+}
+
+// encodeOrderedObject is the inverse of decodeOrderedObject; keys that were
+// deleted from obj are skipped.
+func encodeOrderedObject(keys []string, obj map[string]json.RawMessage) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	first := true
+	written := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		raw, ok := obj[key]
+		if !ok || written[key] {
+			continue
+		}
+		written[key] = true
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(raw)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// WriteFileAtomic replaces path with content via a synced temp file and
+// rename, preserving the original file mode.
+func WriteFileAtomic(path string, content []byte) error {
+	var mode os.FileMode = 0644
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".bv-writeback-*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	success := false
+	defer func() {
+		if !success {
+			os.Remove(tmpPath)
+		}
+	}()
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write temp file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("sync temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close temp file: %w", err)
+	}
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		return fmt.Errorf("chmod temp file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		// Windows does not allow renaming over an existing file.
+		if runtime.GOOS == "windows" {
+			if rmErr := os.Remove(path); rmErr == nil {
+				if err2 := os.Rename(tmpPath, path); err2 == nil {
+					success = true
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("rename temp file: %w", err)
+	}
+
+	success = true
+	return nil
+}
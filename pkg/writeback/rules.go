@@ -0,0 +1,130 @@
+package writeback
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// ErrTransitionDenied is wrapped by errors returned from TransitionRules.Check.
+var ErrTransitionDenied = errors.New("transition not allowed")
+
+// TransitionRules decide which board moves are legal.
+type TransitionRules struct {
+	// CloseRequiresUnblocked rejects closing an issue while it still has open
+	// blockers.
+	CloseRequiresUnblocked bool `yaml:"close_requires_unblocked" json:"close_requires_unblocked"`
+
+	// Deny lists forbidden moves, checked in order.
+	Deny []TransitionRule `yaml:"deny,omitempty" json:"deny,omitempty"`
+}
+
+// TransitionRule forbids moving an issue's field from one value to another.
+// Empty or "*" From/To match any value. Priorities may be written as 1 or P1.
+type TransitionRule struct {
+	Field  string `yaml:"field,omitempty" json:"field,omitempty"` // status (default), priority or type
+	From   string `yaml:"from,omitempty" json:"from,omitempty"`
+	To     string `yaml:"to,omitempty" json:"to,omitempty"`
+	Reason string `yaml:"reason,omitempty" json:"reason,omitempty"`
+}
+
+// DefaultRules returns the rules used when no config file exists.
+func DefaultRules() *TransitionRules {
+	return &TransitionRules{CloseRequiresUnblocked: true}
+}
+
+// RulesFilename is the transition rules filename under .bv/.
+const RulesFilename = "transitions.yaml"
+
+// RulesPath returns the transition rules path for a project.
+func RulesPath(projectDir string) string {
+	return filepath.Join(projectDir, ".bv", RulesFilename)
+}
+
+// LoadRules loads transition rules from .bv/transitions.yaml.
+// Returns the defaults if the file doesn't exist.
+func LoadRules(projectDir string) (*TransitionRules, error) {
+	data, err := os.ReadFile(RulesPath(projectDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return DefaultRules(), nil
+		}
+		return nil, fmt.Errorf("reading transition rules: %w", err)
+	}
+
+	rules := DefaultRules()
+	if err := yaml.Unmarshal(data, rules); err != nil {
+		return nil, fmt.Errorf("parsing transition rules: %w", err)
+	}
+	if err := rules.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid transition rules: %w", err)
+	}
+	return rules, nil
+}
+
+// Validate checks that every rule names a known field.
+func (r *TransitionRules) Validate() error {
+	for i, rule := range r.Deny {
+		switch rule.field() {
+		case OpStatus, OpPriority, OpType:
+		default:
+			return fmt.Errorf("deny[%d]: unknown field %q (want status, priority or type)", i, rule.Field)
+		}
+		if rule.From == "" && rule.To == "" {
+			return fmt.Errorf("deny[%d]: from or to is required", i)
+		}
+	}
+	return nil
+}
+
+func (rule TransitionRule) field() Op {
+	if rule.Field == "" {
+		return OpStatus
+	}
+	return Op(strings.ToLower(rule.Field))
+}
+
+// Check reports whether the prepared edit e is allowed. openBlockers are the
+// IDs of open issues blocking e.IssueID; they only matter when closing.
+func (r *TransitionRules) Check(e Edit, openBlockers []string) error {
+	if r == nil {
+		return nil
+	}
+	if e.Op == OpStatus && model.Status(e.Value).IsClosed() && r.CloseRequiresUnblocked && len(openBlockers) > 0 {
+		return fmt.Errorf("%w: %s has %d open blocker(s): %s",
+			ErrTransitionDenied, e.IssueID, len(openBlockers), strings.Join(openBlockers, ", "))
+	}
+
+	from, to := e.Prev, e.Value
+	if e.Op == OpPriority {
+		from, to = strconv.Itoa(e.PrevPriority), strconv.Itoa(e.Priority)
+	}
+	for _, rule := range r.Deny {
+		if rule.field() != e.Op || !matchValue(e.Op, rule.From, from) || !matchValue(e.Op, rule.To, to) {
+			continue
+		}
+		if rule.Reason != "" {
+			return fmt.Errorf("%w: %s", ErrTransitionDenied, rule.Reason)
+		}
+		return fmt.Errorf("%w: %s %s %s → %s", ErrTransitionDenied, e.IssueID, e.Op, from, to)
+	}
+	return nil
+}
+
+func matchValue(op Op, pattern, value string) bool {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" || pattern == "*" {
+		return true
+	}
+	if op == OpPriority {
+		pattern = strings.TrimPrefix(strings.ToUpper(pattern), "P")
+	}
+	return strings.EqualFold(pattern, value)
+}
@@ -0,0 +1,265 @@
+package writeback
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteStore updates a beads SQLite database in a single transaction per
+// edit. Labels are written to the labels table and/or the JSON labels column
+// on issues, whichever the schema has, and edited issues are marked in
+// dirty_issues (when present) so bd exports them to JSONL.
+type SQLiteStore struct {
+	path string
+}
+
+// NewSQLiteStore returns a store writing to the SQLite database at path.
+func NewSQLiteStore(path string) *SQLiteStore {
+	return &SQLiteStore{path: path}
+}
+
+// Describe implements Store.
+func (s *SQLiteStore) Describe() string {
+	return filepath.Base(s.path)
+}
+
+// sqliteTime formats timestamps the way bd stores them.
+func sqliteTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// Apply implements Store.
+func (s *SQLiteStore) Apply(ctx context.Context, e Edit) (Edit, error) {
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?_pragma=busy_timeout(5000)", s.path))
+	if err != nil {
+		return e, fmt.Errorf("cannot open database: %w", err)
+	}
+	defer db.Close()
+
+	columns, err := sqliteColumns(ctx, db, "issues")
+	if err != nil {
+		return e, err
+	}
+	hasLabelsTable := sqliteHasTable(ctx, db, "labels")
+	hasDirtyTable := sqliteHasTable(ctx, db, "dirty_issues")
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return e, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists int
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM issues WHERE id = ?", e.IssueID).Scan(&exists); err != nil {
+		return e, fmt.Errorf("lookup %s: %w", e.IssueID, err)
+	}
+	if exists == 0 {
+		return e, fmt.Errorf("issue %s not found in %s", e.IssueID, s.Describe())
+	}
+
+	switch e.Op {
+	case OpStatus:
+		var closedAt interface{}
+		if e.ClosedAt != nil {
+			closedAt = sqliteTime(*e.ClosedAt)
+		}
+		if columns["closed_at"] {
+			_, err = tx.ExecContext(ctx, "UPDATE issues SET status = ?, closed_at = ? WHERE id = ?", e.Value, closedAt, e.IssueID)
+		} else {
+			_, err = tx.ExecContext(ctx, "UPDATE issues SET status = ? WHERE id = ?", e.Value, e.IssueID)
+		}
+
+	case OpPriority:
+		_, err = tx.ExecContext(ctx, "UPDATE issues SET priority = ? WHERE id = ?", e.Priority, e.IssueID)
+
+	case OpType:
+		if !columns["issue_type"] {
+			return e, fmt.Errorf("%s has no issue_type column", s.Describe())
+		}
+		_, err = tx.ExecContext(ctx, "UPDATE issues SET issue_type = ? WHERE id = ?", e.Value, e.IssueID)
+
+	case OpAssignee:
+		if !columns["assignee"] {
+			return e, fmt.Errorf("%s has no assignee column", s.Describe())
+		}
+		_, err = tx.ExecContext(ctx, "UPDATE issues SET assignee = ? WHERE id = ?", e.Value, e.IssueID)
+
+	case OpAddLabel, OpRemoveLabel:
+		if !hasLabelsTable && !columns["labels"] {
+			return e, fmt.Errorf("%s has no labels storage", s.Describe())
+		}
+		if hasLabelsTable {
+			if e.Op == OpAddLabel {
+				_, err = tx.ExecContext(ctx, "INSERT OR IGNORE INTO labels (issue_id, label) VALUES (?, ?)", e.IssueID, e.Value)
+			} else {
+				_, err = tx.ExecContext(ctx, "DELETE FROM labels WHERE issue_id = ? AND label = ?", e.IssueID, e.Value)
+			}
+			if err != nil {
+				break
+			}
+		}
+		if columns["labels"] {
+			err = updateLabelsColumn(ctx, tx, e)
+		}
+
+	case OpAddComment:
+		if e.Comment == nil {
+			return e, fmt.Errorf("comment edit without comment")
+		}
+		c := *e.Comment
+		var res sql.Result
+		if c.ID != 0 {
+			res, err = tx.ExecContext(ctx, "INSERT INTO comments (id, issue_id, author, text, created_at) VALUES (?, ?, ?, ?, ?)",
+				c.ID, e.IssueID, c.Author, c.Text, sqliteTime(c.CreatedAt))
+		} else {
+			res, err = tx.ExecContext(ctx, "INSERT INTO comments (issue_id, author, text, created_at) VALUES (?, ?, ?, ?)",
+				e.IssueID, c.Author, c.Text, sqliteTime(c.CreatedAt))
+		}
+		if err == nil && c.ID == 0 {
+			c.ID, err = res.LastInsertId()
+		}
+		e.Comment = &c
+
+	case OpRemoveComment:
+		if e.Comment == nil {
+			return e, fmt.Errorf("comment edit without comment")
+		}
+		_, err = tx.ExecContext(ctx, "DELETE FROM comments WHERE id = ? AND issue_id = ?", e.Comment.ID, e.IssueID)
+
+	case OpAddDependency, OpRemoveDependency:
+		if e.Dependency == nil {
+			return e, fmt.Errorf("dependency edit without dependency")
+		}
+		err = updateDependencies(ctx, db, tx, e)
+
+	default:
+		return e, fmt.Errorf("unsupported edit op %q", e.Op)
+	}
+	if err != nil {
+		return e, fmt.Errorf("update %s: %w", e.IssueID, err)
+	}
+
+	if columns["updated_at"] && !e.At.IsZero() {
+		if _, err := tx.ExecContext(ctx, "UPDATE issues SET updated_at = ? WHERE id = ?", sqliteTime(e.At), e.IssueID); err != nil {
+			return e, fmt.Errorf("update %s: %w", e.IssueID, err)
+		}
+	}
+	if hasDirtyTable {
+		if _, err := tx.ExecContext(ctx, "INSERT OR IGNORE INTO dirty_issues (issue_id) VALUES (?)", e.IssueID); err != nil {
+			return e, fmt.Errorf("mark %s dirty: %w", e.IssueID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return e, fmt.Errorf("commit: %w", err)
+	}
+	return e, nil
+}
+
+// updateLabelsColumn rewrites the JSON labels array stored on the issue row.
+func updateLabelsColumn(ctx context.Context, tx *sql.Tx, e Edit) error {
+	var raw sql.NullString
+	if err := tx.QueryRowContext(ctx, "SELECT labels FROM issues WHERE id = ?", e.IssueID).Scan(&raw); err != nil {
+		return err
+	}
+	var labels []string
+	if raw.Valid && strings.TrimSpace(raw.String) != "" && raw.String != "null" {
+		if err := json.Unmarshal([]byte(raw.String), &labels); err != nil {
+			return fmt.Errorf("labels column: %w", err)
+		}
+	}
+	if e.Op == OpAddLabel {
+		if !hasLabel(labels, e.Value) {
+			labels = append(labels, e.Value)
+		}
+	} else {
+		labels = withoutLabel(labels, e.Value)
+	}
+	if labels == nil {
+		labels = []string{}
+	}
+	data, err := json.Marshal(labels)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "UPDATE issues SET labels = ? WHERE id = ?", string(data), e.IssueID)
+	return err
+}
+
+// updateDependencies inserts or deletes a row in the dependencies table.
+// The type column is "dependency_type" in the schema bv reads and "type" in
+// newer bd databases, and created_at/created_by are optional.
+func updateDependencies(ctx context.Context, db *sql.DB, tx *sql.Tx, e Edit) error {
+	columns, err := sqliteColumns(ctx, db, "dependencies")
+	if err != nil {
+		return err
+	}
+	typeColumn := "type"
+	if !columns["type"] {
+		typeColumn = "dependency_type"
+	}
+	d := e.Dependency
+	depType := dependencyTypeName(d.Type)
+
+	if e.Op == OpRemoveDependency {
+		query := "DELETE FROM dependencies WHERE issue_id = ? AND depends_on_id = ? AND " + typeColumn + " = ?"
+		args := []interface{}{e.IssueID, d.DependsOnID, depType}
+		if d.Type.IsBlocking() {
+			query = "DELETE FROM dependencies WHERE issue_id = ? AND depends_on_id = ? AND (" + typeColumn + " = ? OR " + typeColumn + " = '' OR " + typeColumn + " IS NULL)"
+		}
+		_, err = tx.ExecContext(ctx, query, args...)
+		return err
+	}
+
+	insertColumns := []string{"issue_id", "depends_on_id", typeColumn}
+	args := []interface{}{e.IssueID, d.DependsOnID, depType}
+	if columns["created_at"] {
+		insertColumns = append(insertColumns, "created_at")
+		args = append(args, sqliteTime(d.CreatedAt))
+	}
+	if columns["created_by"] {
+		insertColumns = append(insertColumns, "created_by")
+		args = append(args, d.CreatedBy)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ")
+	_, err = tx.ExecContext(ctx, "INSERT OR IGNORE INTO dependencies ("+strings.Join(insertColumns, ", ")+") VALUES ("+placeholders+")", args...)
+	return err
+}
+
+// sqliteColumns returns the lower-cased column names of table.
+func sqliteColumns(ctx context.Context, db *sql.DB, table string) (map[string]bool, error) {
+	rows, err := db.QueryContext(ctx, "PRAGMA table_info("+table+")")
+	if err != nil {
+		return nil, fmt.Errorf("cannot query table info: %w", err)
+	}
+	defer rows.Close()
+
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var cid int
+		var name, colType string
+		var notNull, pk int
+		var dflt interface{}
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dflt, &pk); err != nil {
+			return nil, fmt.Errorf("cannot scan column info: %w", err)
+		}
+		columns[strings.ToLower(name)] = true
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("missing %s table", table)
+	}
+	return columns, rows.Err()
+}
+
+func sqliteHasTable(ctx context.Context, db *sql.DB, table string) bool {
+	var name string
+	err := db.QueryRowContext(ctx, "SELECT name FROM sqlite_master WHERE type='table' AND name=?", table).Scan(&name)
+	return err == nil
+}
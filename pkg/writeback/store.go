@@ -0,0 +1,56 @@
+package writeback
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/Dicklesworthstone/beads_viewer/internal/datasource"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+)
+
+// Store persists edits to a beads data source.
+type Store interface {
+	// Apply writes a prepared edit and returns it as persisted (for example
+	// with the comment ID the source assigned).
+	Apply(ctx context.Context, e Edit) (Edit, error)
+	// Describe names the destination for status messages.
+	Describe() string
+}
+
+// OpenStore returns a store for the source bv would load from beadsDir, using
+// the same discovery and selection as datasource.LoadIssuesFromDir so edits go
+// to the data the user is looking at.
+func OpenStore(beadsDir string) (Store, error) {
+	sources, err := datasource.DiscoverSources(datasource.DiscoveryOptions{
+		BeadsDir:               beadsDir,
+		ValidateAfterDiscovery: true,
+	})
+	if err == nil && len(sources) > 0 {
+		if best, selErr := datasource.SelectBestSource(sources); selErr == nil {
+			switch best.Type {
+			case datasource.SourceTypeSQLite:
+				return NewSQLiteStore(best.Path), nil
+			case datasource.SourceTypeJSONLLocal, datasource.SourceTypeJSONLWorktree:
+				return NewJSONLStore(best.Path), nil
+			}
+		}
+	}
+
+	jsonlPath, err := loader.FindJSONLPath(beadsDir)
+	if err != nil {
+		return nil, fmt.Errorf("no writable beads source in %s: %w", beadsDir, err)
+	}
+	return NewJSONLStore(jsonlPath), nil
+}
+
+// DefaultAuthor returns the author recorded on comments added from bv:
+// $BD_ACTOR (as used by bd), then $USER, then "bv".
+func DefaultAuthor() string {
+	for _, key := range []string{"BD_ACTOR", "USER"} {
+		if v := os.Getenv(key); v != "" {
+			return v
+		}
+	}
+	return "bv"
+}
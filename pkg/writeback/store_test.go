@@ -0,0 +1,283 @@
+package writeback
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/internal/datasource"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func loadIssue(t *testing.T, issues []model.Issue, id string) model.Issue {
+	t.Helper()
+	for _, issue := range issues {
+		if issue.ID == id {
+			return issue
+		}
+	}
+	t.Fatalf("issue %s not loaded", id)
+	return model.Issue{}
+}
+
+func TestJSONLStorePreservesOtherLinesAndFields(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "issues.jsonl")
+	other := `{"id":"bv-2","title":"Untouched",  "status":"open","priority":3,"issue_type":"task","created_at":"2026-01-01T00:00:00Z","updated_at":"2026-01-01T00:00:00Z"}`
+	content := `{"id":"bv-1","title":"Edit me","status":"open","priority":2,"issue_type":"bug","labels":["api"],"created_at":"2026-01-01T00:00:00Z","updated_at":"2026-01-01T00:00:00Z","x_custom":{"keep":true}}` + "\n" +
+		other + "\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewJSONLStore(path)
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	issue := model.Issue{ID: "bv-1", Status: model.StatusOpen, Priority: 2, Labels: []string{"api"}}
+
+	var history History
+	for _, edit := range []Edit{
+		SetStatus("bv-1", model.StatusClosed),
+		AddLabel("bv-1", "urgent"),
+		AddComment("bv-1", "carol", "shipped"),
+		AddDependency("bv-1", "bv-2", model.DepBlocks, "carol"),
+	} {
+		prepared, err := edit.Prepare(issue, now)
+		if err != nil {
+			t.Fatalf("Prepare %s: %v", edit.Op, err)
+		}
+		applied, err := store.Apply(ctx, prepared)
+		if err != nil {
+			t.Fatalf("Apply %s: %v", edit.Op, err)
+		}
+		Apply(&issue, applied)
+		history.Record(applied)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 2 || lines[1] != other {
+		t.Fatalf("untouched line was rewritten:\n%s", data)
+	}
+	if !strings.HasPrefix(lines[0], `{"id":"bv-1","title":"Edit me","status":"closed"`) || !strings.Contains(lines[0], `"x_custom":{"keep":true}`) {
+		t.Fatalf("edited line lost key order or unknown fields: %s", lines[0])
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Fatalf("file mode = %v, want 0600", info.Mode().Perm())
+	}
+	if leftovers, _ := filepath.Glob(filepath.Join(dir, ".bv-writeback-*")); len(leftovers) != 0 {
+		t.Fatalf("temp files left behind: %v", leftovers)
+	}
+
+	loaded, err := loader.LoadIssuesFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := loadIssue(t, loaded, "bv-1")
+	if got.Status != model.StatusClosed || got.ClosedAt == nil || len(got.Labels) != 2 {
+		t.Fatalf("edits not persisted: %+v", got)
+	}
+	if len(got.Comments) != 1 || got.Comments[0].ID != 1 || got.Comments[0].Author != "carol" {
+		t.Fatalf("comment not persisted with an ID: %+v", got.Comments)
+	}
+	if len(got.Dependencies) != 1 || got.Dependencies[0].DependsOnID != "bv-2" || got.Dependencies[0].Type != model.DepBlocks {
+		t.Fatalf("dependency not persisted: %+v", got.Dependencies)
+	}
+
+	for history.Len() > 0 {
+		e, _ := history.Pop()
+		if _, err := store.Apply(ctx, e.Inverse()); err != nil {
+			t.Fatalf("undo %s: %v", e.Op, err)
+		}
+	}
+	loaded, err = loader.LoadIssuesFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = loadIssue(t, loaded, "bv-1")
+	if got.Status != model.StatusOpen || got.ClosedAt != nil || len(got.Labels) != 1 || len(got.Comments) != 0 || len(got.Dependencies) != 0 {
+		t.Fatalf("undo did not restore the issue: %+v", got)
+	}
+
+	if _, err := store.Apply(ctx, SetPriority("missing", 1)); err == nil {
+		t.Fatalf("expected error for unknown issue")
+	}
+}
+
+func TestSQLiteStoreUpdatesSchemaVariants(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "beads.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec(`
+		CREATE TABLE issues (
+			id TEXT PRIMARY KEY,
+			title TEXT NOT NULL,
+			description TEXT,
+			status TEXT NOT NULL,
+			priority INTEGER DEFAULT 3,
+			issue_type TEXT DEFAULT 'task',
+			assignee TEXT,
+			estimated_minutes INTEGER,
+			created_at DATETIME,
+			updated_at DATETIME,
+			due_date DATETIME,
+			closed_at DATETIME,
+			external_ref TEXT,
+			compaction_level INTEGER,
+			compacted_at DATETIME,
+			compacted_at_commit TEXT,
+			original_size INTEGER,
+			labels TEXT,
+			design TEXT,
+			acceptance_criteria TEXT,
+			notes TEXT,
+			source_repo TEXT,
+			tombstone INTEGER DEFAULT 0
+		);
+		CREATE TABLE labels (issue_id TEXT NOT NULL, label TEXT NOT NULL, PRIMARY KEY (issue_id, label));
+		CREATE TABLE comments (id INTEGER PRIMARY KEY AUTOINCREMENT, issue_id TEXT NOT NULL, author TEXT, text TEXT, created_at DATETIME);
+		CREATE TABLE dependencies (issue_id TEXT NOT NULL, depends_on_id TEXT NOT NULL, dependency_type TEXT NOT NULL DEFAULT 'blocks', created_at DATETIME, PRIMARY KEY (issue_id, depends_on_id));
+		CREATE TABLE dirty_issues (issue_id TEXT PRIMARY KEY);
+		INSERT INTO issues (id, title, status, priority, labels) VALUES ('SQ-1', 'Row', 'open', 2, '["api"]');
+		INSERT INTO labels (issue_id, label) VALUES ('SQ-1', 'api');
+	`)
+	db.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewSQLiteStore(path)
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	issue := model.Issue{ID: "SQ-1", Status: model.StatusOpen, Priority: 2, Labels: []string{"api"}}
+	var comment Edit
+	for _, edit := range []Edit{
+		SetStatus("SQ-1", model.StatusInProgress),
+		SetPriority("SQ-1", 0),
+		SetType("SQ-1", model.TypeBug),
+		SetAssignee("SQ-1", "dave"),
+		AddLabel("SQ-1", "urgent"),
+		AddComment("SQ-1", "dave", "on it"),
+		AddDependency("SQ-1", "SQ-0", model.DepParentChild, "dave"),
+	} {
+		prepared, err := edit.Prepare(issue, now)
+		if err != nil {
+			t.Fatalf("Prepare %s: %v", edit.Op, err)
+		}
+		applied, err := store.Apply(ctx, prepared)
+		if err != nil {
+			t.Fatalf("Apply %s: %v", edit.Op, err)
+		}
+		Apply(&issue, applied)
+		if applied.Op == OpAddComment {
+			comment = applied
+		}
+	}
+	if comment.Comment == nil || comment.Comment.ID == 0 {
+		t.Fatalf("SQLite store did not report the inserted comment ID")
+	}
+
+	loaded, err := datasource.LoadFromSource(datasource.DataSource{Type: datasource.SourceTypeSQLite, Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := loadIssue(t, loaded, "SQ-1")
+	if got.Status != model.StatusInProgress || got.Priority != 0 || got.IssueType != model.TypeBug || got.Assignee != "dave" {
+		t.Fatalf("scalar edits not persisted: %+v", got)
+	}
+	if len(got.Dependencies) != 1 || got.Dependencies[0].DependsOnID != "SQ-0" {
+		t.Fatalf("dependency not persisted: %+v", got.Dependencies)
+	}
+	if len(got.Labels) != 2 || len(got.Comments) != 1 || !got.UpdatedAt.Equal(now) {
+		t.Fatalf("labels/comments/updated_at not persisted: labels=%v comments=%d updated=%v", got.Labels, len(got.Comments), got.UpdatedAt)
+	}
+
+	db, err = sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	var tableLabels, dirty int
+	db.QueryRow("SELECT COUNT(*) FROM labels WHERE issue_id = 'SQ-1'").Scan(&tableLabels)
+	db.QueryRow("SELECT COUNT(*) FROM dirty_issues WHERE issue_id = 'SQ-1'").Scan(&dirty)
+	if tableLabels != 2 || dirty != 1 {
+		t.Fatalf("labels table rows = %d (want 2), dirty rows = %d (want 1)", tableLabels, dirty)
+	}
+
+	if _, err := store.Apply(ctx, comment.Inverse()); err != nil {
+		t.Fatalf("undo comment: %v", err)
+	}
+	var comments int
+	db.QueryRow("SELECT COUNT(*) FROM comments").Scan(&comments)
+	if comments != 0 {
+		t.Fatalf("comment not removed by undo")
+	}
+
+	removal, err := RemoveDependency("SQ-1", "SQ-0", model.DepParentChild).Prepare(issue, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Apply(ctx, removal); err != nil {
+		t.Fatalf("remove dependency: %v", err)
+	}
+	var deps int
+	db.QueryRow("SELECT COUNT(*) FROM dependencies").Scan(&deps)
+	if deps != 0 {
+		t.Fatalf("dependency row not removed")
+	}
+}
+
+func TestOpenStorePicksActiveSource(t *testing.T) {
+	dir := t.TempDir()
+	jsonl := filepath.Join(dir, "issues.jsonl")
+	if err := os.WriteFile(jsonl, []byte(`{"id":"bv-1","title":"x","status":"open","priority":1,"issue_type":"task"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	store, err := OpenStore(dir)
+	if err != nil {
+		t.Fatalf("OpenStore: %v", err)
+	}
+	if _, ok := store.(*JSONLStore); !ok || store.Describe() != "issues.jsonl" {
+		t.Fatalf("OpenStore = %T (%s), want JSONL store", store, store.Describe())
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	dir := t.TempDir()
+	rules, err := LoadRules(dir)
+	if err != nil || !rules.CloseRequiresUnblocked || len(rules.Deny) != 0 {
+		t.Fatalf("missing file should give defaults, got %+v (%v)", rules, err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, ".bv"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := "close_requires_unblocked: false\ndeny:\n  - from: closed\n    to: in_progress\n"
+	if err := os.WriteFile(RulesPath(dir), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rules, err = LoadRules(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules.CloseRequiresUnblocked || len(rules.Deny) != 1 || rules.Deny[0].From != "closed" {
+		t.Fatalf("rules not loaded: %+v", rules)
+	}
+
+	if err := os.WriteFile(RulesPath(dir), []byte("deny:\n  - field: owner\n    to: x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadRules(dir); err == nil {
+		t.Fatalf("invalid rules should fail to load")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"testing"
 )
 
@@ -145,3 +146,127 @@ func TestRobotSearchHybridBackwardCompatibility(t *testing.T) {
 		t.Fatalf("expected default mode text, got %q", payload.Mode)
 	}
 }
+
+func TestRobotSearchFusionMode(t *testing.T) {
+	bv := buildBvBinary(t)
+	env := t.TempDir()
+
+	writeBeads(t, env, `{"id":"ERR-1","title":"Exporter crash","description":"panics with ERR_EXPORT_TIMEOUT on large graphs","status":"open","priority":1,"issue_type":"bug"}
+{"id":"ERR-2","title":"Exporter docs","description":"document export options","status":"open","priority":2,"issue_type":"task"}
+{"id":"UI-1","title":"Board colors","description":"tweak lane colors","status":"open","priority":3,"issue_type":"task"}`)
+
+	run := func() (string, map[string]int, []string) {
+		cmd := exec.Command(bv, "--search", "err_export_timeout", "--search-mode", "fusion", "--robot-search")
+		cmd.Dir = env
+		cmd.Env = append(os.Environ(), "BV_SEMANTIC_EMBEDDER=hash", "BV_SEMANTIC_DIM=256")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("robot-search fusion failed: %v\n%s", err, out)
+		}
+		var payload struct {
+			Mode    string         `json:"mode"`
+			Lexical map[string]int `json:"lexical_index"`
+			Results []struct {
+				IssueID string `json:"issue_id"`
+			} `json:"results"`
+		}
+		if err := json.Unmarshal(out, &payload); err != nil {
+			t.Fatalf("fusion json decode: %v\nout=%s", err, out)
+		}
+		var ids []string
+		for _, r := range payload.Results {
+			ids = append(ids, r.IssueID)
+		}
+		return payload.Mode, payload.Lexical, ids
+	}
+
+	mode, lexical, ids := run()
+	if mode != "fusion" {
+		t.Fatalf("expected mode fusion, got %q", mode)
+	}
+	if lexical["added"] != 3 {
+		t.Fatalf("expected 3 issues added to the lexical index, got %v", lexical)
+	}
+	if len(ids) == 0 || ids[0] != "ERR-1" {
+		t.Fatalf("expected exact keyword match first, got %v", ids)
+	}
+	if _, err := os.Stat(filepath.Join(env, ".bv", "semantic", "lexical.bvli")); err != nil {
+		t.Fatalf("lexical index not persisted: %v", err)
+	}
+
+	_, lexical, _ = run()
+	if lexical["skipped"] != 3 || lexical["added"] != 0 {
+		t.Fatalf("second run should reuse the lexical index, got %v", lexical)
+	}
+}
+
+func TestRobotSearchUnifiedKinds(t *testing.T) {
+	bv := buildBvBinary(t)
+	env := t.TempDir()
+
+	writeBeads(t, env, `{"id":"AUTH-1","title":"Login page","description":"build the login form","status":"open","priority":1,"issue_type":"task","comments":[{"id":1,"issue_id":"AUTH-1","author":"ann","text":"redis evicts the session cookie after restart","created_at":"2026-01-01T00:00:00Z"}]}
+{"id":"BILL-1","title":"Billing export","description":"csv export of invoices","status":"open","priority":2,"issue_type":"task"}`)
+
+	type match struct {
+		Key     string `json:"key"`
+		Kind    string `json:"kind"`
+		BeadID  string `json:"bead_id"`
+		Snippet string `json:"snippet"`
+	}
+	type result struct {
+		IssueID string  `json:"issue_id"`
+		Kind    string  `json:"kind"`
+		Match   *match  `json:"match"`
+		Matches []match `json:"matches"`
+	}
+	run := func(args ...string) ([]string, []string, []result) {
+		cmd := exec.Command(bv, append([]string{"--search", "redis session cookie", "--robot-search"}, args...)...)
+		cmd.Dir = env
+		cmd.Env = append(os.Environ(), "BV_SEMANTIC_EMBEDDER=hash", "BV_SEMANTIC_DIM=256")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("robot-search %v failed: %v\n%s", args, err, out)
+		}
+		var payload struct {
+			Kinds    []string `json:"kinds"`
+			Warnings []string `json:"warnings"`
+			Results  []result `json:"results"`
+		}
+		if err := json.Unmarshal(out, &payload); err != nil {
+			t.Fatalf("unified json decode: %v\nout=%s", err, out)
+		}
+		return payload.Kinds, payload.Warnings, payload.Results
+	}
+
+	kinds, warnings, results := run("--search-kinds", "comment,commit")
+	if len(kinds) != 2 || kinds[0] != "comment" || kinds[1] != "commit" {
+		t.Fatalf("expected kinds [comment commit], got %v", kinds)
+	}
+	if len(warnings) == 0 {
+		t.Fatalf("expected a warning for commit search outside git")
+	}
+	if len(results) != 1 || results[0].Kind != "comment" || results[0].IssueID != "AUTH-1" || results[0].Match == nil {
+		t.Fatalf("expected the comment match linked to AUTH-1, got %+v", results)
+	}
+	if results[0].Match.Key != "comment:AUTH-1:1" || results[0].Match.Snippet == "" {
+		t.Fatalf("unexpected match %+v", results[0].Match)
+	}
+	if _, err := os.Stat(filepath.Join(env, ".bv", "semantic", "index-hash-256-comment.bvvi")); err != nil {
+		t.Fatalf("comment index not persisted: %v", err)
+	}
+
+	_, _, results = run("--search-kinds", "issue,comment", "--search-group")
+	if len(results) != 2 || results[0].IssueID != "AUTH-1" {
+		t.Fatalf("expected one grouped result per bead with AUTH-1 first, got %+v", results)
+	}
+	if len(results[0].Matches) != 2 || results[0].Match != nil {
+		t.Fatalf("expected AUTH-1 to group its issue and comment matches, got %+v", results[0].Matches)
+	}
+
+	_, _, results = run("--search-kinds", "issue")
+	for _, r := range results {
+		if r.Kind != "" || r.Match != nil {
+			t.Fatalf("issue-only search should keep the original payload, got %+v", r)
+		}
+	}
+}